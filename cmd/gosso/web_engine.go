@@ -29,6 +29,11 @@ func setupEngine(ctx context.Context, cfg config.GoUnoConfig, logger *zap.Logger
 		return nil, fmt.Errorf("invalid CORS configuration: %w", err)
 	}
 
+	clientCertMiddleware, err := middleware.ClientCertificateMiddleware(cfg.WebServerConfig.ClientCertHeader, cfg.WebServerConfig.TrustedProxies, logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate configuration: %w", err)
+	}
+
 	csrfSkipPaths := []string{
 		"/api/v1/auth/login",
 		"/api/v1/auth/mfa/verify",
@@ -40,7 +45,10 @@ func setupEngine(ctx context.Context, cfg config.GoUnoConfig, logger *zap.Logger
 		"/api/v1/passkey/mfa/complete",
 		"/oauth2/token",
 		"/oauth2/introspect",
+		"/oauth2/par",
+		"/oauth2/register",
 		"/oauth2/device/code",
+		"/oauth2/bc-authorize",
 		"/.well-known",
 		"/swagger",
 	}
@@ -50,6 +58,7 @@ func setupEngine(ctx context.Context, cfg config.GoUnoConfig, logger *zap.Logger
 		middleware.RecoveryMiddleware(logger),
 		cors.New(corsConfig),
 		middleware.RequestIDMiddleware(),
+		clientCertMiddleware,
 		middleware.ZapLoggerMiddleware(logger),
 		middleware.SecurityHeadersMiddleware(cfg.WebServerConfig.Production),
 		middleware.MaxBodySizeMiddleware(cfg.WebServerConfig.MaxBodySize),
@@ -71,6 +80,7 @@ func setupEngine(ctx context.Context, cfg config.GoUnoConfig, logger *zap.Logger
 		OIDCCtrl:         m.oidcCtrl,
 		AdminCtrl:        m.adminCtrl,
 		TokenSvc:         m.tokenSvc,
+		DPoPVerifier:     m.dpopVerifier,
 		PasskeyCtrl:      m.passkeyCtrl,
 		Redis:            redis,
 		RateLimits:       cfg.WebServerConfig.RateLimits,
@@ -110,12 +120,12 @@ func buildCORSConfig(cfg config.GoUnoConfig, logger *zap.Logger) (cors.Config, e
 	if len(cfg.CORSConfig.AllowedHeaders) > 0 {
 		corsConfig.AllowHeaders = cfg.CORSConfig.AllowedHeaders
 	} else {
-		corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token"}
+		corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token", "DPoP"}
 	}
 	if len(cfg.CORSConfig.ExposedHeaders) > 0 {
 		corsConfig.ExposeHeaders = cfg.CORSConfig.ExposedHeaders
 	} else {
-		corsConfig.ExposeHeaders = []string{"X-Request-ID"}
+		corsConfig.ExposeHeaders = []string{"X-Request-ID", "DPoP-Nonce"}
 	}
 	return corsConfig, nil
 }
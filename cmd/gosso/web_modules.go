@@ -2,6 +2,7 @@ package gosso
 
 import (
 	"context"
+	"crypto/rsa"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -20,6 +21,7 @@ import (
 	auditService "github.com/rushairer/gosso/internal/audit/service"
 	"github.com/rushairer/gosso/internal/auth"
 	authController "github.com/rushairer/gosso/internal/auth/controller"
+	authMiddleware "github.com/rushairer/gosso/internal/auth/middleware"
 	authService "github.com/rushairer/gosso/internal/auth/service"
 	"github.com/rushairer/gosso/internal/cache"
 	notificationService "github.com/rushairer/gosso/internal/notification/service"
@@ -29,6 +31,7 @@ import (
 	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
 	"github.com/rushairer/gosso/internal/oidc"
 	oidcController "github.com/rushairer/gosso/internal/oidc/controller"
+	oidcService "github.com/rushairer/gosso/internal/oidc/service"
 	sessionService "github.com/rushairer/gosso/internal/session/service"
 	tokenService "github.com/rushairer/gosso/internal/token/service"
 	"github.com/rushairer/gosso/internal/utility"
@@ -43,6 +46,7 @@ type appModules struct {
 	adminCtrl        *adminController.AdminController
 	passkeyCtrl      *authController.PasskeyController
 	tokenSvc         *tokenService.TokenService
+	dpopVerifier     *tokenService.DPoPVerifier
 	sessionSvc       *sessionService.SessionService
 	passwordResetSvc *authService.PasswordResetService
 	emailSvc         *notificationService.EmailService
@@ -52,16 +56,13 @@ type appModules struct {
 func initModules(ctx context.Context, db *sql.DB, redis *cache.RedisClient, logger *zap.Logger, cfg config.GoUnoConfig, auditor *auditService.Auditor) (*appModules, error) {
 	accountMod := account.InitializeAccountModule(db, auditor, logger, nil)
 
-	keySvc, err := tokenService.NewKeyService(
-		cfg.AuthConfig.PrivateKeyPath,
-		cfg.AuthConfig.KeyID,
-		cfg.WebServerConfig.Production,
-		cfg.AuthConfig.RSAKeyBits,
-		logger,
-	)
+	keySvc, err := newSigningKeyService(cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize key service: %w", err)
 	}
+	// Keep the signing keyring up to date: scheduled rotations, and rotations and
+	// revocations made by other instances.
+	go keySvc.Run(ctx)
 
 	blacklistSvc, err := tokenService.NewBlacklistService(redis, logger)
 	if err != nil {
@@ -81,6 +82,13 @@ func initModules(ctx context.Context, db *sql.DB, redis *cache.RedisClient, logg
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize token service: %w", err)
 	}
+	if err := tokenSvc.SetAccessTokenSigningAlg(cfg.AuthConfig.AccessTokenSigningAlg); err != nil {
+		return nil, fmt.Errorf("failed to configure access token signing: %w", err)
+	}
+	dpopVerifier, err := tokenService.NewDPoPVerifier(redis, cfg.AuthConfig.Issuer, cfg.AuthConfig.DPoPNonceRequired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize DPoP verifier: %w", err)
+	}
 
 	providers := buildOAuthProviders(cfg)
 
@@ -105,11 +113,24 @@ func initModules(ctx context.Context, db *sql.DB, redis *cache.RedisClient, logg
 		return nil, fmt.Errorf("failed to initialize auth module: %w", err)
 	}
 
-	oauth2Mod, err := oauth2.InitializeOAuth2Module(db, redis, logger, cfg.AuthConfig, auditor)
+	// Encrypted request objects (RFC 9101 §6.1) use a dedicated key, never the signing key.
+	var requestObjectKeySvc *tokenService.KeyService
+	var requestObjectKey *rsa.PrivateKey
+	if path := cfg.AuthConfig.RequestObjectEncryptionKeyPath; path != "" {
+		requestObjectKeySvc, err = tokenService.NewKeyService(path, "", cfg.WebServerConfig.Production, cfg.AuthConfig.RSAKeyBits, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize request object encryption key: %w", err)
+		}
+		requestObjectKey = requestObjectKeySvc.PrivateKey()
+	}
+
+	oauth2Mod, err := oauth2.InitializeOAuth2Module(db, redis, logger, cfg.AuthConfig, auditor, requestObjectKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize oauth2 module: %w", err)
 	}
-	oidcMod := oidc.InitializeOIDCModule(tokenSvc, accountMod.Service, cfg.AuthConfig, authMod.SessionService, accountMod.CredentialRepo, oauth2Mod.ClientRepo, nil, logger)
+	tokenSvc.SetProtectedResources(oauth2Mod.Resources.Identifiers())
+	tokenSvc.SetSubjectResolver(oauth2Mod.SubjectIdentifiers)
+	oidcMod := oidc.InitializeOIDCModule(tokenSvc, requestObjectKeySvc, accountMod.Service, cfg.AuthConfig, authMod.SessionService, accountMod.CredentialRepo, oauth2Mod.ClientRepo, oauth2Mod.SubjectIdentifiers, nil, logger)
 
 	// Wire cross-module dependencies into account service via a single atomic call.
 	// This replaces the previous three Set* calls that had temporal coupling risks.
@@ -124,6 +145,17 @@ func initModules(ctx context.Context, db *sql.DB, redis *cache.RedisClient, logg
 		RoleCacheInvalidator:    roleCacheInvalidator,
 	})
 
+	// Backchannel authentication (CIBA) is enabled by configuring the user notification webhook.
+	var backchannelAuthSvc oauth2Controller.BackchannelAuthManager
+	var backchannelUserNotifier oauth2Controller.BackchannelUserNotifier
+	var backchannelClientNotifier oauth2Controller.BackchannelClientNotifier
+	if cfg.AuthConfig.CIBANotificationURL != "" {
+		notifier := oauth2Service.NewBackchannelNotifier(cfg.AuthConfig.CIBANotificationURL, nil, nil, logger)
+		backchannelAuthSvc = oauth2Mod.DeviceCodeService
+		backchannelUserNotifier = notifier
+		backchannelClientNotifier = notifier
+	}
+
 	authCtrl := authController.NewAuthController(authMod.AuthService, tokenSvc, authMod.SocialLoginService, authMod.VerificationService, authMod.PasswordResetService, !cfg.WebServerConfig.Debug, logger)
 	accountValidatorCacheTTL := cfg.AuthConfig.AccountValidatorCacheTTL
 	if accountValidatorCacheTTL <= 0 {
@@ -136,7 +168,7 @@ func initModules(ctx context.Context, db *sql.DB, redis *cache.RedisClient, logg
 		TokenSvc:                   tokenSvc,
 		IDTokenSvc:                 oidcMod.IDTokenService,
 		DeviceCodeSvc:              oauth2Mod.DeviceCodeService,
-		ClientAuth:                 &oauth2Service.ClientAuthenticator{},
+		ClientAuth:                 oauth2Mod.ClientAuthenticator,
 		AccountValidator:           newAccountValidatorAdapter(accountMod.Service, logger, accountValidatorCacheTTL),
 		SessionValidator:           authMod.SessionService,
 		Redis:                      redis,
@@ -147,6 +179,24 @@ func initModules(ctx context.Context, db *sql.DB, redis *cache.RedisClient, logg
 		PermissionFetcher:          &accountRoleFetcherAdapter{accountSvc: accountMod.Service},
 		IncludeUserRoles:           cfg.AuthConfig.IncludeUserRoles,
 		IncludeUserPermissions:     cfg.AuthConfig.IncludeUserPermissions,
+		IDTokenHintVerifier:        &idTokenHintVerifierAdapter{logoutSvc: oidcMod.LogoutService},
+		JWTBearerVerifier:          oauth2Mod.JWTBearerVerifier,
+		ClientRegistrar:            oauth2Mod.ClientRegistration,
+		DPoPVerifier:               dpopVerifier,
+		RequestObjectVerifier:      oauth2Mod.RequestObjectVerifier,
+		JARMSigner:                 tokenService.NewAuthorizationResponseSigner(keySvc, cfg.AuthConfig.Issuer),
+		IntrospectionSigner:        tokenService.NewIntrospectionResponseSigner(keySvc, cfg.AuthConfig.Issuer),
+		BackchannelAuthSvc:         backchannelAuthSvc,
+		BackchannelUserNotifier:    backchannelUserNotifier,
+		BackchannelClientNotifier:  backchannelClientNotifier,
+		LoginHintResolver:          &loginHintResolverAdapter{accountSvc: accountMod.Service},
+		SubjectIdentifiers:         oauth2Mod.SubjectIdentifiers,
+		Resources:                  oauth2Mod.Resources,
+		AuthOptions: authMiddleware.AuthConfigOptions{
+			LoginURL:         cfg.AuthConfig.LoginURL,
+			EnableCookieAuth: cfg.AuthConfig.EnableCookieAuth,
+			AuthCookieName:   cfg.AuthConfig.AuthCookieName,
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize OAuth2 controller: %w", err)
@@ -158,7 +208,7 @@ func initModules(ctx context.Context, db *sql.DB, redis *cache.RedisClient, logg
 	if !ok {
 		logger.Warn("AuthService is not *AuthService; lockout management will be unavailable")
 	}
-	adminCtrl := adminController.NewAdminController(accountMod.Service, oauth2Mod.ConsentService, auditQueryRepo, authSvcConcrete, logger)
+	adminCtrl := adminController.NewAdminController(accountMod.Service, oauth2Mod.ConsentService, auditQueryRepo, authSvcConcrete, oauth2Mod.ClientRegistration, tokenSvc.KeyService(), logger)
 
 	var passkeyCtrl *authController.PasskeyController
 	if authMod.PasskeyService != nil {
@@ -173,6 +223,7 @@ func initModules(ctx context.Context, db *sql.DB, redis *cache.RedisClient, logg
 		adminCtrl:        adminCtrl,
 		passkeyCtrl:      passkeyCtrl,
 		tokenSvc:         tokenSvc,
+		dpopVerifier:     dpopVerifier,
 		sessionSvc:       authMod.SessionService,
 		passwordResetSvc: authMod.PasswordResetService,
 		emailSvc:         authMod.EmailService,
@@ -338,6 +389,34 @@ func (a *oauth2ClientDeleterAdapter) SoftDeleteOAuth2ClientsByAccount(ctx contex
 	return a.clientRepo.SoftDeleteByAccountID(ctx, tx, accountID, deletedAt)
 }
 
+// idTokenHintVerifierAdapter lets the OAuth2 controller validate id_token_hint
+// with the same rules the OIDC logout endpoint applies.
+type idTokenHintVerifierAdapter struct {
+	logoutSvc *oidcService.LogoutService
+}
+
+func (a *idTokenHintVerifierAdapter) VerifyIDTokenHint(ctx context.Context, idTokenHint, clientID string) (string, error) {
+	claims, err := a.logoutSvc.ValidateIDTokenHint(ctx, idTokenHint, clientID)
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
+
+// loginHintResolverAdapter resolves the login_hint of backchannel authentication requests
+// as a username.
+type loginHintResolverAdapter struct {
+	accountSvc accountService.AccountService
+}
+
+func (a *loginHintResolverAdapter) ResolveLoginHint(ctx context.Context, loginHint string) (string, error) {
+	account, err := a.accountSvc.FindAccountByUsername(ctx, loginHint)
+	if err != nil {
+		return "", err
+	}
+	return account.ID, nil
+}
+
 type accountRoleFetcherAdapter struct {
 	accountSvc accountService.AccountService
 }
@@ -371,3 +450,26 @@ func (a *accountRoleFetcherAdapter) GetAccountPermissions(ctx context.Context, a
 	}
 	return permissions, nil
 }
+
+// newSigningKeyService creates the key service that signs tokens: the signing keyring when
+// one is configured, otherwise the single key at private_key_path.
+func newSigningKeyService(cfg config.GoUnoConfig, logger *zap.Logger) (*tokenService.KeyService, error) {
+	if cfg.AuthConfig.SigningKeyringPath == "" {
+		return tokenService.NewKeyService(
+			cfg.AuthConfig.PrivateKeyPath,
+			cfg.AuthConfig.KeyID,
+			cfg.WebServerConfig.Production,
+			cfg.AuthConfig.RSAKeyBits,
+			logger,
+		)
+	}
+	return tokenService.NewKeyringKeyService(tokenService.KeyringOptions{
+		Dir:            cfg.AuthConfig.SigningKeyringPath,
+		RotationPeriod: cfg.AuthConfig.SigningKeyRotationPeriod,
+		Overlap:        cfg.AuthConfig.SigningKeyOverlap,
+		ImportKeyPath:  cfg.AuthConfig.PrivateKeyPath,
+		ImportKeyID:    cfg.AuthConfig.KeyID,
+		KeyBits:        cfg.AuthConfig.RSAKeyBits,
+		Algorithms:     cfg.AuthConfig.SigningAlgs,
+	}, logger)
+}
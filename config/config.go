@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"time"
 )
@@ -28,21 +29,25 @@ type GoUnoConfig struct {
 }
 
 type WebServerConfig struct {
-	Production        bool             `mapstructure:"production"`
-	Debug             bool             `mapstructure:"debug"`
-	Address           string           `mapstructure:"address"`
-	Port              int              `mapstructure:"port"`
-	IdleTimeout       time.Duration    `mapstructure:"idle_timeout"`
-	ReadTimeout       time.Duration    `mapstructure:"read_timeout"`
-	ReadHeaderTimeout time.Duration    `mapstructure:"read_header_timeout"`
-	WriteTimeout      time.Duration    `mapstructure:"write_timeout"`
-	RequestTimeout    time.Duration    `mapstructure:"request_timeout"`
-	ShutdownTimeout   time.Duration    `mapstructure:"shutdown_timeout"`
-	MaxBodySize       int64            `mapstructure:"max_body_size"`
-	TrustedProxies    []string         `mapstructure:"trusted_proxies"`
-	RateLimits        RateLimitsConfig `mapstructure:"rate_limits"`
-	CSRFSkipPaths     []string         `mapstructure:"csrf_skip_paths"`
-	CSRFCookieSecure  bool             `mapstructure:"csrf_cookie_secure"`
+	Production        bool          `mapstructure:"production"`
+	Debug             bool          `mapstructure:"debug"`
+	Address           string        `mapstructure:"address"`
+	Port              int           `mapstructure:"port"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	RequestTimeout    time.Duration `mapstructure:"request_timeout"`
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	MaxBodySize       int64         `mapstructure:"max_body_size"`
+	TrustedProxies    []string      `mapstructure:"trusted_proxies"`
+	// ClientCertHeader names the request header a TLS-terminating proxy forwards the
+	// verified client certificate in, for mutual-TLS client authentication (RFC 8705).
+	// It is only read on requests from trusted_proxies. Empty disables the header.
+	ClientCertHeader string           `mapstructure:"client_cert_header"`
+	RateLimits       RateLimitsConfig `mapstructure:"rate_limits"`
+	CSRFSkipPaths    []string         `mapstructure:"csrf_skip_paths"`
+	CSRFCookieSecure bool             `mapstructure:"csrf_cookie_secure"`
 }
 
 type RateLimitsConfig struct {
@@ -143,9 +148,12 @@ type AuthConfig struct {
 	WebAuthnRPOrigin        string        `mapstructure:"webauthn_rp_origin"`
 	TOTPEncryptionKey       string        `mapstructure:"totp_encryption_key" json:"-"`
 	VerifyHashPepper        string        `mapstructure:"verify_hash_pepper" json:"-"`
-	LoginRateLimitWindow    time.Duration `mapstructure:"login_rate_limit_window"`
-	LoginMaxAttempts        int           `mapstructure:"login_max_attempts"`
-	LoginMaxAttemptsPerIP   int           `mapstructure:"login_max_attempts_per_ip"`
+	// ClientSecretEncryptionKey is an optional 32-byte hex key used to keep a recoverable
+	// copy of client_secret_jwt client secrets. client_secret_jwt is disabled when empty.
+	ClientSecretEncryptionKey string        `mapstructure:"client_secret_encryption_key" json:"-"`
+	LoginRateLimitWindow      time.Duration `mapstructure:"login_rate_limit_window"`
+	LoginMaxAttempts          int           `mapstructure:"login_max_attempts"`
+	LoginMaxAttemptsPerIP     int           `mapstructure:"login_max_attempts_per_ip"`
 	// LoginIPAllowlist contains IP addresses or CIDR ranges that are exempt from
 	// per-IP login rate limiting. Use this for known proxy/NAT exit IPs where
 	// multiple legitimate users share the same public IP.
@@ -178,6 +186,105 @@ type AuthConfig struct {
 	AuthCookieName                 string        `mapstructure:"auth_cookie_name"`
 	IncludeUserRoles               bool          `mapstructure:"include_user_roles"`
 	IncludeUserPermissions         bool          `mapstructure:"include_user_permissions"`
+	// DPoPNonceRequired makes every DPoP proof carry a server-provided nonce (RFC 9449 §8),
+	// which bounds how long a proof captured in transit can be replayed.
+	DPoPNonceRequired bool `mapstructure:"dpop_nonce_required"`
+	// MTLSEndpointAliasBaseURL is the base URL of the endpoints that accept TLS client
+	// certificates, advertised as mtls_endpoint_aliases in discovery (RFC 8705 §5).
+	// Empty uses the issuer.
+	MTLSEndpointAliasBaseURL string `mapstructure:"mtls_endpoint_alias_base_url"`
+	// TLSClientAuthCAPath is the PEM bundle of the CAs that tls_client_auth client
+	// certificates must chain to (RFC 8705 §2.1). tls_client_auth is refused when empty;
+	// self_signed_tls_client_auth does not use it.
+	TLSClientAuthCAPath string `mapstructure:"tls_client_auth_ca_path"`
+	// JWTBearerIssuers lists the external issuers whose JWTs are accepted by the
+	// urn:ietf:params:oauth:grant-type:jwt-bearer grant (RFC 7523). Empty disables the grant.
+	JWTBearerIssuers []JWTBearerIssuerConfig `mapstructure:"jwt_bearer_issuers"`
+	// SoftwareStatementIssuers lists the software publishers whose signed software
+	// statements are accepted by dynamic client registration (RFC 7591 §2.3).
+	SoftwareStatementIssuers []SoftwareStatementIssuerConfig `mapstructure:"software_statement_issuers"`
+	// RequireSoftwareStatement rejects dynamic registrations without a software statement
+	// from one of SoftwareStatementIssuers.
+	RequireSoftwareStatement bool `mapstructure:"require_software_statement"`
+	// RequestObjectEncryptionKeyPath is the PEM file of the RSA key that clients encrypt
+	// request objects to (RFC 9101 §6.1). It is published in the JWKS with use "enc" and
+	// must not be the signing key. Encrypted request objects are rejected when empty.
+	RequestObjectEncryptionKeyPath string `mapstructure:"request_object_encryption_key_path"`
+	// CIBANotificationURL is the webhook that new client-initiated backchannel authentication
+	// requests are posted to, e.g. a push notification gateway that prompts the user on their
+	// device. Empty disables backchannel authentication (OpenID Connect CIBA Core).
+	CIBANotificationURL string `mapstructure:"ciba_notification_url"`
+	// PairwiseSubjectSalt keys the hash that derives pairwise subject identifiers (OpenID
+	// Connect Core §8.1). Changing it changes every pairwise subject already issued. Clients
+	// cannot register for pairwise identifiers when empty.
+	PairwiseSubjectSalt string `mapstructure:"pairwise_subject_salt" json:"-"`
+	// ProtectedResources registers the resource servers that clients can request access
+	// tokens for with the resource parameter (RFC 8707). Such tokens carry only the requested
+	// resources as their audience.
+	ProtectedResources []ProtectedResourceConfig `mapstructure:"protected_resources"`
+	// SigningKeyringPath is a directory holding the signing keyring: the signing keys with
+	// their states and schedule. It may be shared by several instances. When set, the key
+	// at private_key_path only seeds an empty keyring as its first active key.
+	SigningKeyringPath string `mapstructure:"signing_keyring_path"`
+	// SigningKeyRotationPeriod is how long each keyring key signs before the next one takes
+	// over. 0 disables automatic rotation.
+	SigningKeyRotationPeriod time.Duration `mapstructure:"signing_key_rotation_period"`
+	// SigningKeyOverlap is how long a new keyring key is published in the JWKS before it
+	// starts signing, and a retired key after it stops. It must outlive access and ID tokens.
+	SigningKeyOverlap time.Duration `mapstructure:"signing_key_overlap"`
+	// SigningAlgs lists the JWS algorithms with an active signing key (RS256, PS256, ES256,
+	// EdDSA). RS256 is always required; the others need a signing keyring.
+	SigningAlgs []string `mapstructure:"signing_algs"`
+	// AccessTokenSigningAlg signs access tokens and must be one of signing_algs. ID tokens
+	// use each client's id_token_signed_response_alg instead.
+	AccessTokenSigningAlg string `mapstructure:"access_token_signing_alg"`
+	// MaxClient*Expiry are the longest lifetimes clients may register for their access
+	// tokens, ID tokens, refresh tokens (idle lifetime), authorization codes and device
+	// codes. Zero uses the matching *_expiry, so clients can only shorten it.
+	MaxClientAccessTokenExpiry       time.Duration `mapstructure:"max_client_access_token_expiry"`
+	MaxClientIDTokenExpiry           time.Duration `mapstructure:"max_client_id_token_expiry"`
+	MaxClientRefreshTokenExpiry      time.Duration `mapstructure:"max_client_refresh_token_expiry"`
+	MaxClientAuthorizationCodeExpiry time.Duration `mapstructure:"max_client_authorization_code_expiry"`
+	MaxClientDeviceCodeExpiry        time.Duration `mapstructure:"max_client_device_code_expiry"`
+}
+
+// ProtectedResourceConfig registers a resource server for resource indicators (RFC 8707).
+// Scopes caps the scopes granted in tokens for the resource. A positive AccessTokenExpiry
+// replaces access_token_expiry for them. AccessTokenProfile "rfc9068" issues its tokens in
+// the JWT profile for OAuth 2.0 access tokens (RFC 9068). ClientIDs are the clients the
+// resource server authenticates as; they may introspect tokens issued for the resource.
+type ProtectedResourceConfig struct {
+	Identifier         string        `mapstructure:"identifier"`
+	Scopes             []string      `mapstructure:"scopes"`
+	AccessTokenExpiry  time.Duration `mapstructure:"access_token_expiry"`
+	AccessTokenProfile string        `mapstructure:"access_token_profile"`
+	ClientIDs          []string      `mapstructure:"client_ids"`
+}
+
+// SoftwareStatementIssuerConfig trusts a software publisher for dynamic client registration.
+// Exactly one of JWKSURI and JWKS (an inline JWK Set document) must be set.
+type SoftwareStatementIssuerConfig struct {
+	Issuer  string `mapstructure:"issuer"`
+	JWKSURI string `mapstructure:"jwks_uri"`
+	JWKS    string `mapstructure:"jwks"`
+}
+
+// JWTBearerIssuerConfig trusts an external issuer for the jwt-bearer grant.
+// Exactly one of JWKSURI and JWKS (an inline JWK Set document) must be set.
+type JWTBearerIssuerConfig struct {
+	Issuer   string                   `mapstructure:"issuer"`
+	JWKSURI  string                   `mapstructure:"jwks_uri"`
+	JWKS     string                   `mapstructure:"jwks"`
+	Subjects []JWTBearerSubjectConfig `mapstructure:"subjects"`
+}
+
+// JWTBearerSubjectConfig maps an assertion sub claim to exactly one gosso account or client.
+// Scopes, when set, caps the scopes that can be granted for the subject.
+type JWTBearerSubjectConfig struct {
+	Subject   string   `mapstructure:"subject"`
+	AccountID string   `mapstructure:"account_id"`
+	ClientID  string   `mapstructure:"client_id"`
+	Scopes    []string `mapstructure:"scopes"`
 }
 
 type CORSConfig struct {
@@ -277,6 +384,9 @@ func (c *GoUnoConfig) validateWebServer() error {
 			return fmt.Errorf("web_server: trusted_proxies entry %q is not a valid IP address or CIDR notation", proxy)
 		}
 	}
+	if c.WebServerConfig.ClientCertHeader != "" && len(c.WebServerConfig.TrustedProxies) == 0 {
+		return fmt.Errorf("web_server: client_cert_header requires trusted_proxies, otherwise any client could forge a certificate")
+	}
 	if c.WebServerConfig.Address != "" && net.ParseIP(c.WebServerConfig.Address) == nil {
 		return fmt.Errorf("web_server: address must be a valid IP address (got %q)", c.WebServerConfig.Address)
 	}
@@ -410,12 +520,65 @@ func (c *GoUnoConfig) validateAuth() error {
 	if err := c.validateTOTPKey(); err != nil {
 		return err
 	}
+	if err := c.validateClientSecretEncryptionKey(); err != nil {
+		return err
+	}
+	if err := c.validateJWTBearerIssuers(); err != nil {
+		return err
+	}
+	if err := c.validateSoftwareStatementIssuers(); err != nil {
+		return err
+	}
+	if err := c.validateProtectedResources(); err != nil {
+		return err
+	}
+	if base := c.AuthConfig.MTLSEndpointAliasBaseURL; base != "" {
+		u, err := url.Parse(base)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("auth: mtls_endpoint_alias_base_url must be a valid URL with http or https scheme")
+		}
+		if strings.HasSuffix(base, "/") {
+			return fmt.Errorf("auth: mtls_endpoint_alias_base_url must not have a trailing slash (got %q)", base)
+		}
+	}
+	if notifyURL := c.AuthConfig.CIBANotificationURL; notifyURL != "" {
+		u, err := url.Parse(notifyURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("auth: ciba_notification_url must be a valid URL with http or https scheme")
+		}
+	}
+	if salt := c.AuthConfig.PairwiseSubjectSalt; salt != "" && len(salt) < 32 {
+		return fmt.Errorf("auth: pairwise_subject_salt must be at least 32 characters (got %d)", len(salt))
+	}
 	if err := c.validateAuthDurations(); err != nil {
 		return err
 	}
 	if err := c.validatePrivateKeyPath(); err != nil {
 		return err
 	}
+	if err := c.validateSigningKeyring(); err != nil {
+		return err
+	}
+	if err := c.validateSigningAlgs(); err != nil {
+		return err
+	}
+	if path := c.AuthConfig.RequestObjectEncryptionKeyPath; path != "" {
+		if path == c.AuthConfig.PrivateKeyPath {
+			return fmt.Errorf("auth: request_object_encryption_key_path must not be the signing key (private_key_path)")
+		}
+		if stat, err := os.Stat(path); err == nil && stat.IsDir() {
+			return fmt.Errorf("auth: request_object_encryption_key_path is a directory, not a file: %s", path)
+		} else if err != nil && (c.WebServerConfig.Production || !os.IsNotExist(err)) {
+			return fmt.Errorf("auth: cannot access request_object_encryption_key_path: %w", err)
+		}
+	}
+	if path := c.AuthConfig.TLSClientAuthCAPath; path != "" {
+		if stat, err := os.Stat(path); err == nil && stat.IsDir() {
+			return fmt.Errorf("auth: tls_client_auth_ca_path is a directory, not a file: %s", path)
+		} else if err != nil {
+			return fmt.Errorf("auth: cannot access tls_client_auth_ca_path: %w", err)
+		}
+	}
 	if c.AuthConfig.MaxSessions <= 0 {
 		return fmt.Errorf("auth: max_sessions must be positive")
 	}
@@ -434,6 +597,9 @@ func (c *GoUnoConfig) validateAuth() error {
 	if isWeakSecret(c.AuthConfig.VerifyHashPepper) {
 		fmt.Fprintln(os.Stderr, "WARNING: auth.verify_hash_pepper is using a weak repeating dummy value (e.g. all zeros). This neutralizes cryptographic security.")
 	}
+	if isWeakSecret(c.AuthConfig.PairwiseSubjectSalt) {
+		fmt.Fprintln(os.Stderr, "WARNING: auth.pairwise_subject_salt is using a weak repeating dummy value (e.g. all zeros). Pairwise subjects can be correlated across sectors.")
+	}
 	return c.validateWebAuthn()
 }
 
@@ -451,6 +617,116 @@ func (c *GoUnoConfig) validateTOTPKey() error {
 	return nil
 }
 
+func (c *GoUnoConfig) validateClientSecretEncryptionKey() error {
+	if c.AuthConfig.ClientSecretEncryptionKey == "" {
+		return nil
+	}
+	key, err := hex.DecodeString(c.AuthConfig.ClientSecretEncryptionKey)
+	if err != nil {
+		return fmt.Errorf("auth: client_secret_encryption_key must be a valid hex string")
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("auth: client_secret_encryption_key must decode to exactly 32 bytes (got %d)", len(key))
+	}
+	return nil
+}
+
+func (c *GoUnoConfig) validateJWTBearerIssuers() error {
+	seen := make(map[string]bool, len(c.AuthConfig.JWTBearerIssuers))
+	for i, iss := range c.AuthConfig.JWTBearerIssuers {
+		if iss.Issuer == "" {
+			return fmt.Errorf("auth: jwt_bearer_issuers[%d].issuer is empty", i)
+		}
+		if seen[iss.Issuer] {
+			return fmt.Errorf("auth: jwt_bearer_issuers issuer %q is configured more than once", iss.Issuer)
+		}
+		seen[iss.Issuer] = true
+		if (iss.JWKSURI == "") == (iss.JWKS == "") {
+			return fmt.Errorf("auth: jwt_bearer_issuers[%d] must set exactly one of jwks_uri or jwks", i)
+		}
+		if iss.JWKSURI != "" {
+			u, err := url.Parse(iss.JWKSURI)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("auth: jwt_bearer_issuers[%d].jwks_uri must be a valid http or https URL", i)
+			}
+			if c.WebServerConfig.Production && u.Scheme != "https" {
+				return fmt.Errorf("auth: jwt_bearer_issuers[%d].jwks_uri must use https in production", i)
+			}
+		}
+		if len(iss.Subjects) == 0 {
+			return fmt.Errorf("auth: jwt_bearer_issuers[%d].subjects must not be empty", i)
+		}
+		subjects := make(map[string]bool, len(iss.Subjects))
+		for j, sub := range iss.Subjects {
+			if sub.Subject == "" {
+				return fmt.Errorf("auth: jwt_bearer_issuers[%d].subjects[%d].subject is empty", i, j)
+			}
+			if subjects[sub.Subject] {
+				return fmt.Errorf("auth: jwt_bearer_issuers[%d] subject %q is configured more than once", i, sub.Subject)
+			}
+			subjects[sub.Subject] = true
+			if (sub.AccountID == "") == (sub.ClientID == "") {
+				return fmt.Errorf("auth: jwt_bearer_issuers[%d].subjects[%d] must set exactly one of account_id or client_id", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *GoUnoConfig) validateSoftwareStatementIssuers() error {
+	seen := make(map[string]bool, len(c.AuthConfig.SoftwareStatementIssuers))
+	for i, iss := range c.AuthConfig.SoftwareStatementIssuers {
+		if iss.Issuer == "" {
+			return fmt.Errorf("auth: software_statement_issuers[%d].issuer is empty", i)
+		}
+		if seen[iss.Issuer] {
+			return fmt.Errorf("auth: software_statement_issuers issuer %q is configured more than once", iss.Issuer)
+		}
+		seen[iss.Issuer] = true
+		if (iss.JWKSURI == "") == (iss.JWKS == "") {
+			return fmt.Errorf("auth: software_statement_issuers[%d] must set exactly one of jwks_uri or jwks", i)
+		}
+		if iss.JWKSURI != "" {
+			u, err := url.Parse(iss.JWKSURI)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("auth: software_statement_issuers[%d].jwks_uri must be a valid http or https URL", i)
+			}
+			if c.WebServerConfig.Production && u.Scheme != "https" {
+				return fmt.Errorf("auth: software_statement_issuers[%d].jwks_uri must use https in production", i)
+			}
+		}
+	}
+	if c.AuthConfig.RequireSoftwareStatement && len(c.AuthConfig.SoftwareStatementIssuers) == 0 {
+		return fmt.Errorf("auth: require_software_statement needs at least one software_statement_issuers entry")
+	}
+	return nil
+}
+
+func (c *GoUnoConfig) validateProtectedResources() error {
+	seen := make(map[string]bool, len(c.AuthConfig.ProtectedResources))
+	for i, res := range c.AuthConfig.ProtectedResources {
+		// RFC 8707 §2: a resource indicator is an absolute URI without a fragment.
+		u, err := url.Parse(res.Identifier)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return fmt.Errorf("auth: protected_resources[%d].identifier must be an absolute URI without fragment", i)
+		}
+		if seen[res.Identifier] {
+			return fmt.Errorf("auth: protected_resources identifier %q is configured more than once", res.Identifier)
+		}
+		seen[res.Identifier] = true
+		if len(res.Scopes) == 0 {
+			return fmt.Errorf("auth: protected_resources[%d].scopes must not be empty", i)
+		}
+		if res.AccessTokenExpiry < 0 {
+			return fmt.Errorf("auth: protected_resources[%d].access_token_expiry must not be negative", i)
+		}
+		if res.AccessTokenProfile != "" && res.AccessTokenProfile != "rfc9068" {
+			return fmt.Errorf("auth: protected_resources[%d].access_token_profile must be empty or rfc9068", i)
+		}
+	}
+	return nil
+}
+
 func (c *GoUnoConfig) validateAuthDurations() error {
 	positive := []struct {
 		name  string
@@ -494,6 +770,25 @@ func (c *GoUnoConfig) validateAuthDurations() error {
 			return fmt.Errorf("auth: %s must not be negative (got %s)", check.name, check.value)
 		}
 	}
+	// Client lifetime ceilings: 0 means the default lifetime is the ceiling.
+	ceilings := []struct {
+		name, defaultName string
+		value, expiry     time.Duration
+	}{
+		{"max_client_access_token_expiry", "access_token_expiry", c.AuthConfig.MaxClientAccessTokenExpiry, c.AuthConfig.AccessTokenExpiry},
+		{"max_client_id_token_expiry", "id_token_expiry", c.AuthConfig.MaxClientIDTokenExpiry, c.AuthConfig.IDTokenExpiry},
+		{"max_client_refresh_token_expiry", "refresh_token_expiry", c.AuthConfig.MaxClientRefreshTokenExpiry, c.AuthConfig.RefreshTokenExpiry},
+		{"max_client_authorization_code_expiry", "authorization_code_expiry", c.AuthConfig.MaxClientAuthorizationCodeExpiry, c.AuthConfig.AuthorizationCodeExpiry},
+		{"max_client_device_code_expiry", "device_code_expiry", c.AuthConfig.MaxClientDeviceCodeExpiry, c.AuthConfig.DeviceCodeExpiry},
+	}
+	for _, check := range ceilings {
+		if check.value < 0 {
+			return fmt.Errorf("auth: %s must not be negative (got %s)", check.name, check.value)
+		}
+		if check.value > 0 && check.value < check.expiry {
+			return fmt.Errorf("auth: %s (%s) must not be shorter than %s (%s)", check.name, check.value, check.defaultName, check.expiry)
+		}
+	}
 	// Non-negative checks: 0 means "use service-level default", negative is invalid.
 	intChecks := []struct {
 		name  string
@@ -534,7 +829,7 @@ func (c *GoUnoConfig) validateAuthDurations() error {
 
 func (c *GoUnoConfig) validatePrivateKeyPath() error {
 	if c.AuthConfig.PrivateKeyPath == "" {
-		if c.WebServerConfig.Production && c.AuthConfig.KeyID == "" {
+		if c.WebServerConfig.Production && c.AuthConfig.KeyID == "" && c.AuthConfig.SigningKeyringPath == "" {
 			return fmt.Errorf("auth: key_id is required in production mode")
 		}
 		return nil
@@ -558,6 +853,70 @@ func (c *GoUnoConfig) validatePrivateKeyPath() error {
 	return nil
 }
 
+func (c *GoUnoConfig) validateSigningKeyring() error {
+	path := c.AuthConfig.SigningKeyringPath
+	if path == "" {
+		return nil
+	}
+	if stat, err := os.Stat(path); err == nil && !stat.IsDir() {
+		return fmt.Errorf("auth: signing_keyring_path is a file, not a directory: %s", path)
+	} else if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("auth: cannot access signing_keyring_path: %w", err)
+	}
+	period, overlap := c.AuthConfig.SigningKeyRotationPeriod, c.AuthConfig.SigningKeyOverlap
+	if period < 0 {
+		return fmt.Errorf("auth: signing_key_rotation_period must not be negative (got %s)", period)
+	}
+	// Retired keys are only trusted for the overlap, so it must outlive the tokens they signed.
+	for _, expiry := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"access_token_expiry", c.AuthConfig.AccessTokenExpiry},
+		{"id_token_expiry", c.AuthConfig.IDTokenExpiry},
+		{"max_client_access_token_expiry", c.AuthConfig.MaxClientAccessTokenExpiry},
+		{"max_client_id_token_expiry", c.AuthConfig.MaxClientIDTokenExpiry},
+	} {
+		if overlap < expiry.value {
+			return fmt.Errorf("auth: signing_key_overlap (%s) must not be shorter than %s (%s)", overlap, expiry.name, expiry.value)
+		}
+	}
+	if period > 0 && period <= overlap {
+		return fmt.Errorf("auth: signing_key_rotation_period (%s) must be longer than signing_key_overlap (%s)", period, overlap)
+	}
+	return nil
+}
+
+// supportedSigningAlgs are the JWS algorithms signing keys can be generated for.
+var supportedSigningAlgs = []string{"RS256", "PS256", "ES256", "EdDSA"}
+
+func (c *GoUnoConfig) validateSigningAlgs() error {
+	algs := c.AuthConfig.SigningAlgs
+	if len(algs) == 0 {
+		algs = []string{"RS256"}
+	}
+	seen := make(map[string]bool, len(algs))
+	for _, alg := range algs {
+		if !slices.Contains(supportedSigningAlgs, alg) {
+			return fmt.Errorf("auth: unsupported signing_algs entry %q (supported: %s)", alg, strings.Join(supportedSigningAlgs, ", "))
+		}
+		if seen[alg] {
+			return fmt.Errorf("auth: duplicate signing_algs entry %q", alg)
+		}
+		seen[alg] = true
+		if alg != "RS256" && c.AuthConfig.SigningKeyringPath == "" {
+			return fmt.Errorf("auth: signing_algs entry %q requires signing_keyring_path", alg)
+		}
+	}
+	if !seen["RS256"] {
+		return fmt.Errorf("auth: signing_algs must include RS256")
+	}
+	if alg := c.AuthConfig.AccessTokenSigningAlg; alg != "" && !seen[alg] {
+		return fmt.Errorf("auth: access_token_signing_alg %q is not in signing_algs", alg)
+	}
+	return nil
+}
+
 func (c *GoUnoConfig) validateWebAuthn() error {
 	if c.AuthConfig.WebAuthnRPID == "" {
 		return nil
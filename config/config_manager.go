@@ -99,6 +99,7 @@ func (cm *ConfigManager) setConfigDefaults(v *viper.Viper) {
 	v.SetDefault("web_server.max_body_size", 10*1024*1024) // 10MB
 	v.SetDefault("web_server.csrf_skip_paths", []string{})
 	v.SetDefault("web_server.csrf_cookie_secure", false)
+	v.SetDefault("web_server.client_cert_header", "")
 	v.SetDefault("web_server.rate_limits.login", 5)
 	v.SetDefault("web_server.rate_limits.token", 10)
 	v.SetDefault("web_server.rate_limits.passkey", 10)
@@ -138,6 +139,17 @@ func (cm *ConfigManager) setConfigDefaults(v *viper.Viper) {
 	v.SetDefault("auth.auth_cookie_name", "__Host-access_token")
 	v.SetDefault("auth.include_user_roles", false)
 	v.SetDefault("auth.include_user_permissions", false)
+	v.SetDefault("auth.dpop_nonce_required", false)
+	v.SetDefault("auth.mtls_endpoint_alias_base_url", "")
+	v.SetDefault("auth.require_software_statement", false)
+	v.SetDefault("auth.request_object_encryption_key_path", "")
+	v.SetDefault("auth.ciba_notification_url", "")
+	v.SetDefault("auth.pairwise_subject_salt", "")
+	v.SetDefault("auth.signing_keyring_path", "")
+	v.SetDefault("auth.signing_key_rotation_period", "0s")
+	v.SetDefault("auth.signing_key_overlap", "24h")
+	v.SetDefault("auth.signing_algs", []string{"RS256"})
+	v.SetDefault("auth.access_token_signing_alg", "RS256")
 	v.SetDefault("auth.verify_hash_pepper", "")
 	// Redis configuration
 	v.SetDefault("redis.max_active_conns", 10)
@@ -300,6 +300,145 @@ func TestValidate_Errors(t *testing.T) {
 			},
 			wantErr: "auth: totp_encryption_key must decode to exactly 32 bytes",
 		},
+		{
+			name: "client secret encryption key not hex",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.ClientSecretEncryptionKey = "not-hex"
+			},
+			wantErr: "auth: client_secret_encryption_key must be a valid hex string",
+		},
+		{
+			name: "client secret encryption key wrong length",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.ClientSecretEncryptionKey = "abcd"
+			},
+			wantErr: "auth: client_secret_encryption_key must decode to exactly 32 bytes",
+		},
+
+		// ── Auth — jwt-bearer issuers ───────────
+		{
+			name: "jwt bearer issuer empty",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.JWTBearerIssuers = []JWTBearerIssuerConfig{{JWKSURI: "https://idp.example.com/jwks", Subjects: []JWTBearerSubjectConfig{{Subject: "job", ClientID: "cid"}}}}
+			},
+			wantErr: "auth: jwt_bearer_issuers[0].issuer is empty",
+		},
+		{
+			name: "jwt bearer issuer duplicated",
+			mutate: func(c *GoUnoConfig) {
+				iss := JWTBearerIssuerConfig{Issuer: "https://idp.example.com", JWKSURI: "https://idp.example.com/jwks", Subjects: []JWTBearerSubjectConfig{{Subject: "job", ClientID: "cid"}}}
+				c.AuthConfig.JWTBearerIssuers = []JWTBearerIssuerConfig{iss, iss}
+			},
+			wantErr: `auth: jwt_bearer_issuers issuer "https://idp.example.com" is configured more than once`,
+		},
+		{
+			name: "jwt bearer issuer without keys",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.JWTBearerIssuers = []JWTBearerIssuerConfig{{Issuer: "https://idp.example.com", Subjects: []JWTBearerSubjectConfig{{Subject: "job", ClientID: "cid"}}}}
+			},
+			wantErr: "auth: jwt_bearer_issuers[0] must set exactly one of jwks_uri or jwks",
+		},
+		{
+			name: "jwt bearer jwks_uri must use https in production",
+			mutate: func(c *GoUnoConfig) {
+				c.WebServerConfig.Production = true
+				c.AuthConfig.JWTBearerIssuers = []JWTBearerIssuerConfig{{Issuer: "https://idp.example.com", JWKSURI: "http://idp.example.com/jwks", Subjects: []JWTBearerSubjectConfig{{Subject: "job", ClientID: "cid"}}}}
+			},
+			wantErr: "auth: jwt_bearer_issuers[0].jwks_uri must use https in production",
+		},
+		{
+			name: "jwt bearer issuer without subjects",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.JWTBearerIssuers = []JWTBearerIssuerConfig{{Issuer: "https://idp.example.com", JWKSURI: "https://idp.example.com/jwks"}}
+			},
+			wantErr: "auth: jwt_bearer_issuers[0].subjects must not be empty",
+		},
+		{
+			name: "jwt bearer subject maps to account and client",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.JWTBearerIssuers = []JWTBearerIssuerConfig{{Issuer: "https://idp.example.com", JWKSURI: "https://idp.example.com/jwks", Subjects: []JWTBearerSubjectConfig{{Subject: "job", AccountID: "acc", ClientID: "cid"}}}}
+			},
+			wantErr: "auth: jwt_bearer_issuers[0].subjects[0] must set exactly one of account_id or client_id",
+		},
+		{
+			name: "jwt bearer subject duplicated",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.JWTBearerIssuers = []JWTBearerIssuerConfig{{Issuer: "https://idp.example.com", JWKSURI: "https://idp.example.com/jwks", Subjects: []JWTBearerSubjectConfig{{Subject: "job", ClientID: "cid"}, {Subject: "job", AccountID: "acc"}}}}
+			},
+			wantErr: `auth: jwt_bearer_issuers[0] subject "job" is configured more than once`,
+		},
+		{
+			name: "software statement issuer empty",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SoftwareStatementIssuers = []SoftwareStatementIssuerConfig{{JWKSURI: "https://publisher.example.com/jwks"}}
+			},
+			wantErr: "auth: software_statement_issuers[0].issuer is empty",
+		},
+		{
+			name: "software statement issuer without keys",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SoftwareStatementIssuers = []SoftwareStatementIssuerConfig{{Issuer: "https://publisher.example.com"}}
+			},
+			wantErr: "auth: software_statement_issuers[0] must set exactly one of jwks_uri or jwks",
+		},
+		{
+			name: "software statement jwks_uri must use https in production",
+			mutate: func(c *GoUnoConfig) {
+				c.WebServerConfig.Production = true
+				c.AuthConfig.SoftwareStatementIssuers = []SoftwareStatementIssuerConfig{{Issuer: "https://publisher.example.com", JWKSURI: "http://publisher.example.com/jwks"}}
+			},
+			wantErr: "auth: software_statement_issuers[0].jwks_uri must use https in production",
+		},
+		{
+			name: "software statement required without issuers",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.RequireSoftwareStatement = true
+			},
+			wantErr: "auth: require_software_statement needs at least one software_statement_issuers entry",
+		},
+		{
+			name: "protected resource identifier not absolute",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.ProtectedResources = []ProtectedResourceConfig{{Identifier: "/orders", Scopes: []string{"orders:read"}}}
+			},
+			wantErr: "auth: protected_resources[0].identifier must be an absolute URI without fragment",
+		},
+		{
+			name: "protected resource identifier with fragment",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.ProtectedResources = []ProtectedResourceConfig{{Identifier: "https://api.example.com/orders#v1", Scopes: []string{"orders:read"}}}
+			},
+			wantErr: "auth: protected_resources[0].identifier must be an absolute URI without fragment",
+		},
+		{
+			name: "protected resource duplicated",
+			mutate: func(c *GoUnoConfig) {
+				res := ProtectedResourceConfig{Identifier: "https://api.example.com/orders", Scopes: []string{"orders:read"}}
+				c.AuthConfig.ProtectedResources = []ProtectedResourceConfig{res, res}
+			},
+			wantErr: `auth: protected_resources identifier "https://api.example.com/orders" is configured more than once`,
+		},
+		{
+			name: "protected resource without scopes",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.ProtectedResources = []ProtectedResourceConfig{{Identifier: "https://api.example.com/orders"}}
+			},
+			wantErr: "auth: protected_resources[0].scopes must not be empty",
+		},
+		{
+			name: "protected resource negative expiry",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.ProtectedResources = []ProtectedResourceConfig{{Identifier: "https://api.example.com/orders", Scopes: []string{"orders:read"}, AccessTokenExpiry: -time.Minute}}
+			},
+			wantErr: "auth: protected_resources[0].access_token_expiry must not be negative",
+		},
+		{
+			name: "protected resource unknown access token profile",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.ProtectedResources = []ProtectedResourceConfig{{Identifier: "https://api.example.com/orders", Scopes: []string{"orders:read"}, AccessTokenProfile: "jwt"}}
+			},
+			wantErr: "auth: protected_resources[0].access_token_profile must be empty or rfc9068",
+		},
 
 		// ── Auth — token expiries ───────────────
 		{
@@ -316,6 +455,20 @@ func TestValidate_Errors(t *testing.T) {
 			},
 			wantErr: "auth: refresh_token_expiry must be positive",
 		},
+		{
+			name: "negative max_client_device_code_expiry",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.MaxClientDeviceCodeExpiry = -1 * time.Minute
+			},
+			wantErr: "auth: max_client_device_code_expiry must not be negative",
+		},
+		{
+			name: "max_client_access_token_expiry shorter than access_token_expiry",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.MaxClientAccessTokenExpiry = time.Minute
+			},
+			wantErr: "auth: max_client_access_token_expiry (1m0s) must not be shorter than access_token_expiry",
+		},
 		{
 			name: "zero id_token_expiry",
 			mutate: func(c *GoUnoConfig) {
@@ -539,6 +692,140 @@ func TestValidate_Errors(t *testing.T) {
 			wantErr: "auth: max_session_age (1h0m0s) must not be shorter than session_ttl (24h0m0s)",
 		},
 
+		// ── Mutual TLS ────────────────────────
+		{
+			name: "client_cert_header without trusted_proxies",
+			mutate: func(c *GoUnoConfig) {
+				c.WebServerConfig.ClientCertHeader = "X-Client-Cert"
+				c.WebServerConfig.TrustedProxies = nil
+			},
+			wantErr: "web_server: client_cert_header requires trusted_proxies",
+		},
+		{
+			name: "mtls endpoint alias base url invalid",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.MTLSEndpointAliasBaseURL = "mtls.example.com"
+			},
+			wantErr: "auth: mtls_endpoint_alias_base_url must be a valid URL",
+		},
+		{
+			name: "mtls endpoint alias base url trailing slash",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.MTLSEndpointAliasBaseURL = "https://mtls.example.com/"
+			},
+			wantErr: "auth: mtls_endpoint_alias_base_url must not have a trailing slash",
+		},
+		{
+			name: "ciba notification url invalid",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.CIBANotificationURL = "notify.example.com/ciba"
+			},
+			wantErr: "auth: ciba_notification_url must be a valid URL",
+		},
+		{
+			name: "pairwise subject salt too short",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.PairwiseSubjectSalt = "short-salt"
+			},
+			wantErr: "auth: pairwise_subject_salt must be at least 32 characters",
+		},
+		{
+			name: "request object encryption key is the signing key",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.PrivateKeyPath = "/path/to/key.pem"
+				c.AuthConfig.KeyID = "k1"
+				c.AuthConfig.RequestObjectEncryptionKeyPath = "/path/to/key.pem"
+			},
+			wantErr: "auth: request_object_encryption_key_path must not be the signing key",
+		},
+		{
+			name: "request object encryption key is a directory",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.RequestObjectEncryptionKeyPath = os.TempDir()
+			},
+			wantErr: "auth: request_object_encryption_key_path is a directory",
+		},
+		{
+			name: "signing keyring path is a file",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningKeyringPath = "config.go"
+			},
+			wantErr: "auth: signing_keyring_path is a file, not a directory",
+		},
+		{
+			name: "signing key overlap shorter than access tokens",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningKeyringPath = "/path/to/keyring"
+				c.AuthConfig.SigningKeyOverlap = time.Second
+			},
+			wantErr: "auth: signing_key_overlap (1s) must not be shorter than access_token_expiry",
+		},
+		{
+			name: "signing key rotation period within overlap",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningKeyringPath = "/path/to/keyring"
+				c.AuthConfig.SigningKeyOverlap = 24 * time.Hour
+				c.AuthConfig.SigningKeyRotationPeriod = 12 * time.Hour
+			},
+			wantErr: "auth: signing_key_rotation_period (12h0m0s) must be longer than signing_key_overlap (24h0m0s)",
+		},
+		{
+			name: "negative signing key rotation period",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningKeyringPath = "/path/to/keyring"
+				c.AuthConfig.SigningKeyOverlap = 24 * time.Hour
+				c.AuthConfig.SigningKeyRotationPeriod = -time.Hour
+			},
+			wantErr: "auth: signing_key_rotation_period must not be negative",
+		},
+		{
+			name: "unsupported signing alg",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningAlgs = []string{"RS256", "HS256"}
+			},
+			wantErr: `auth: unsupported signing_algs entry "HS256"`,
+		},
+		{
+			name: "duplicate signing alg",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningAlgs = []string{"RS256", "RS256"}
+			},
+			wantErr: `auth: duplicate signing_algs entry "RS256"`,
+		},
+		{
+			name: "signing algs without RS256",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningKeyringPath = "/path/to/keyring"
+				c.AuthConfig.SigningKeyOverlap = 24 * time.Hour
+				c.AuthConfig.SigningAlgs = []string{"ES256"}
+			},
+			wantErr: "auth: signing_algs must include RS256",
+		},
+		{
+			name: "non-RS256 signing alg without keyring",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningAlgs = []string{"RS256", "ES256"}
+			},
+			wantErr: `auth: signing_algs entry "ES256" requires signing_keyring_path`,
+		},
+		{
+			name: "access token signing alg not configured",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.AccessTokenSigningAlg = "EdDSA"
+			},
+			wantErr: `auth: access_token_signing_alg "EdDSA" is not in signing_algs`,
+		},
+		{
+			name: "keyring with ES256 and EdDSA",
+			mutate: func(c *GoUnoConfig) {
+				c.AuthConfig.SigningKeyringPath = "/path/to/keyring"
+				c.AuthConfig.SigningKeyOverlap = 24 * time.Hour
+				c.AuthConfig.SigningAlgs = []string{"RS256", "ES256", "EdDSA"}
+				c.AuthConfig.AccessTokenSigningAlg = "ES256"
+			},
+			wantErr: "",
+		},
+
 		// ── WebAuthn IPv6 loopback (should pass) ──
 		{
 			name: "webauthn http origin allows IPv6 loopback",
@@ -625,6 +912,36 @@ func TestValidate_ValidWithWebAuthnLocalhost(t *testing.T) {
 	assert.NoError(t, cfg.Validate())
 }
 
+func TestValidate_ValidWithJWTBearerIssuers(t *testing.T) {
+	cfg := validConfig()
+	cfg.AuthConfig.JWTBearerIssuers = []JWTBearerIssuerConfig{
+		{
+			Issuer:  "https://workload.example.com",
+			JWKSURI: "https://workload.example.com/.well-known/jwks.json",
+			Subjects: []JWTBearerSubjectConfig{
+				{Subject: "spiffe://example.com/batch", ClientID: "batch-client", Scopes: []string{"reports:read"}},
+				{Subject: "spiffe://example.com/sync", AccountID: "acc-001"},
+			},
+		},
+		{
+			Issuer:   "https://ci.example.com",
+			JWKS:     `{"keys":[]}`,
+			Subjects: []JWTBearerSubjectConfig{{Subject: "deploy", ClientID: "deploy-client"}},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_ValidWithSoftwareStatementIssuers(t *testing.T) {
+	cfg := validConfig()
+	cfg.AuthConfig.SoftwareStatementIssuers = []SoftwareStatementIssuerConfig{
+		{Issuer: "https://publisher.example.com", JWKSURI: "https://publisher.example.com/.well-known/jwks.json"},
+		{Issuer: "https://partner.example.com", JWKS: `{"keys":[]}`},
+	}
+	cfg.AuthConfig.RequireSoftwareStatement = true
+	assert.NoError(t, cfg.Validate())
+}
+
 // ──────────────────────────────────────────────
 // DatabaseConfig.GetDriver
 // ──────────────────────────────────────────────
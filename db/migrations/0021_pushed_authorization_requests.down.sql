@@ -0,0 +1,4 @@
+-- Revert 0021: remove Pushed Authorization Requests enforcement flag
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS require_pushed_authorization_requests;
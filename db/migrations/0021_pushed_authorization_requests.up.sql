@@ -0,0 +1,6 @@
+-- 0021_pushed_authorization_requests
+-- OAuth 2.0 Pushed Authorization Requests (PAR) per-client enforcement flag
+-- See: https://www.rfc-editor.org/rfc/rfc9126
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN require_pushed_authorization_requests BOOLEAN NOT NULL DEFAULT false;
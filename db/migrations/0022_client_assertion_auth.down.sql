@@ -0,0 +1,7 @@
+-- Revert 0022: remove JWT client assertion authentication columns
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS client_secret_encrypted,
+    DROP COLUMN IF EXISTS jwks_uri,
+    DROP COLUMN IF EXISTS jwks,
+    DROP COLUMN IF EXISTS token_endpoint_auth_method;
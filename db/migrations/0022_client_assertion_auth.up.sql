@@ -0,0 +1,16 @@
+-- 0022_client_assertion_auth
+-- JWT client assertion authentication (private_key_jwt / client_secret_jwt)
+-- See: https://www.rfc-editor.org/rfc/rfc7523
+-- See: https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication
+--
+-- token_endpoint_auth_method: empty keeps the legacy behaviour (client_secret_basic /
+-- client_secret_post for confidential clients, none for public clients).
+-- jwks: inline JWK Set (JSON text) used to verify private_key_jwt assertions.
+-- client_secret_encrypted: AES-GCM encrypted client secret, only stored for
+-- client_secret_jwt clients because HMAC verification needs the plaintext secret.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN token_endpoint_auth_method TEXT NOT NULL DEFAULT '',
+    ADD COLUMN jwks TEXT NOT NULL DEFAULT '',
+    ADD COLUMN jwks_uri TEXT NOT NULL DEFAULT '',
+    ADD COLUMN client_secret_encrypted TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Revert 0023: remove per-client token exchange policy
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS token_exchange_audiences;
@@ -0,0 +1,10 @@
+-- 0023_token_exchange
+-- Per-client token exchange policy (RFC 8693)
+-- See: https://www.rfc-editor.org/rfc/rfc8693
+--
+-- token_exchange_audiences: audience / resource values this client may request
+-- when exchanging a subject token. An empty list means the client cannot
+-- obtain exchanged tokens even if the grant type is enabled.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN token_exchange_audiences JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,4 @@
+-- Revert 0024: remove per-client DPoP requirement
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS dpop_bound_access_tokens;
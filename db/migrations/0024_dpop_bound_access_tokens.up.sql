@@ -0,0 +1,10 @@
+-- 0024_dpop_bound_access_tokens
+-- Per-client DPoP requirement (RFC 9449)
+-- See: https://www.rfc-editor.org/rfc/rfc9449#section-5.2
+--
+-- dpop_bound_access_tokens: when true, the token endpoint rejects requests from
+-- this client that do not carry a DPoP proof, so every access token it receives
+-- is bound to the client's DPoP key.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN dpop_bound_access_tokens BOOLEAN NOT NULL DEFAULT false;
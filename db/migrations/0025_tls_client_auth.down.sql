@@ -0,0 +1,9 @@
+-- Revert 0025: remove mutual-TLS client authentication settings
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS tls_client_certificate_bound_access_tokens,
+    DROP COLUMN IF EXISTS tls_client_auth_san_email,
+    DROP COLUMN IF EXISTS tls_client_auth_san_ip,
+    DROP COLUMN IF EXISTS tls_client_auth_san_uri,
+    DROP COLUMN IF EXISTS tls_client_auth_san_dns,
+    DROP COLUMN IF EXISTS tls_client_auth_subject_dn;
@@ -0,0 +1,17 @@
+-- 0025_tls_client_auth
+-- Mutual-TLS client authentication and certificate-bound access tokens (RFC 8705)
+-- See: https://www.rfc-editor.org/rfc/rfc8705#section-2.1.2
+--
+-- tls_client_auth_subject_dn / tls_client_auth_san_*: the expected subject of the
+-- client certificate for tls_client_auth clients; exactly one is set. Clients using
+-- self_signed_tls_client_auth register their certificates via jwks or jwks_uri.
+-- tls_client_certificate_bound_access_tokens: when true, the token endpoint binds
+-- every access token issued to this client to its TLS client certificate.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN tls_client_auth_subject_dn TEXT NOT NULL DEFAULT '',
+    ADD COLUMN tls_client_auth_san_dns TEXT NOT NULL DEFAULT '',
+    ADD COLUMN tls_client_auth_san_uri TEXT NOT NULL DEFAULT '',
+    ADD COLUMN tls_client_auth_san_ip TEXT NOT NULL DEFAULT '',
+    ADD COLUMN tls_client_auth_san_email TEXT NOT NULL DEFAULT '',
+    ADD COLUMN tls_client_certificate_bound_access_tokens BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+-- Revert 0026: remove dynamic client registration access tokens
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS registration_access_token_hash;
@@ -0,0 +1,11 @@
+-- 0026_dynamic_client_registration
+-- Dynamic client registration and management (RFC 7591 / RFC 7592)
+-- See: https://www.rfc-editor.org/rfc/rfc7592#section-3
+--
+-- registration_access_token_hash: SHA-256 (hex) of the registration access token
+-- issued to a dynamically registered client. The token authorizes reading, updating
+-- and deleting the client at its registration_client_uri. Empty for clients created
+-- through the client management API.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN registration_access_token_hash TEXT NOT NULL DEFAULT '';
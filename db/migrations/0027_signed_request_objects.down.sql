@@ -0,0 +1,5 @@
+-- Revert 0027: remove the signed request object requirement and registered request URIs
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS request_uris,
+    DROP COLUMN IF EXISTS require_signed_request_object;
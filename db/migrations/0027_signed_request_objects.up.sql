@@ -0,0 +1,15 @@
+-- 0027_signed_request_objects
+-- JWT-secured authorization requests (RFC 9101)
+-- See: https://www.rfc-editor.org/rfc/rfc9101#section-10.5
+--
+-- require_signed_request_object: when true, /oauth2/authorize (and the PAR endpoint)
+-- only accept authorization requests carried in a signed request object (the
+-- "request" parameter); plain query or form parameters are rejected.
+--
+-- request_uris: the URLs from which /oauth2/authorize may fetch the client's request
+-- objects by reference (OpenID Connect Dynamic Client Registration §2). request_uri
+-- values outside this list are rejected, so the server never fetches arbitrary URLs.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN require_signed_request_object BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN request_uris JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,7 @@
+-- Revert 0028: remove rich authorization request columns
+
+ALTER TABLE oauth2_consents
+    DROP COLUMN IF EXISTS authorization_details;
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS authorization_details_types;
@@ -0,0 +1,16 @@
+-- 0028_authorization_details
+-- Rich authorization requests (RFC 9396)
+-- See: https://www.rfc-editor.org/rfc/rfc9396
+--
+-- authorization_details_types: the authorization details types (e.g. "payment_initiation")
+-- the client may request in the authorization_details parameter (RFC 9396 §10.1).
+-- Requests containing other types are rejected with invalid_authorization_details.
+--
+-- oauth2_consents.authorization_details: the authorization details the user approved
+-- most recently, so a repeated request for the same details can skip the consent page.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN authorization_details_types JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE oauth2_consents
+    ADD COLUMN authorization_details JSONB;
@@ -0,0 +1,5 @@
+-- Revert 0029: remove backchannel authentication columns
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS backchannel_client_notification_endpoint,
+    DROP COLUMN IF EXISTS backchannel_token_delivery_mode;
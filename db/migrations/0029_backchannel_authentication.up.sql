@@ -0,0 +1,15 @@
+-- 0029_backchannel_authentication
+-- Client-initiated backchannel authentication (OpenID Connect CIBA Core 1.0)
+-- See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html
+--
+-- backchannel_token_delivery_mode: how the client learns the outcome of a backchannel
+-- authentication request: "poll" (the client polls the token endpoint), "ping" (the server
+-- notifies the client, which then calls the token endpoint) or "push" (the server delivers
+-- the tokens). Empty means poll.
+--
+-- backchannel_client_notification_endpoint: the HTTPS endpoint ping and push notifications
+-- are sent to. Required for those modes.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN backchannel_token_delivery_mode TEXT NOT NULL DEFAULT '',
+    ADD COLUMN backchannel_client_notification_endpoint TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Revert 0030: remove pairwise subject identifiers
+
+DROP TABLE IF EXISTS pairwise_subjects;
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS sector_identifier_uri,
+    DROP COLUMN IF EXISTS subject_type;
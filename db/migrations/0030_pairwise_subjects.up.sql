@@ -0,0 +1,28 @@
+-- 0030_pairwise_subjects
+-- Pairwise subject identifiers (OpenID Connect Core §8)
+-- See: https://openid.net/specs/openid-connect-core-1_0.html#SubjectIDTypes
+-- See: https://openid.net/specs/openid-connect-registration-1_0.html#SectorIdentifierValidation
+--
+-- subject_type: "public" (the account ID is the sub of every client) or "pairwise" (each
+-- sector sees a different, uncorrelatable sub derived from the account ID). Empty means public.
+--
+-- sector_identifier_uri: HTTPS URL of a JSON array listing the client's redirect_uris. Its
+-- host is the sector of a pairwise client, so several clients of one organisation can share
+-- subjects. Without it the sector is the host of the redirect_uris, which must all share it.
+--
+-- pairwise_subjects: the pairwise subjects handed out in ID tokens, mapped back to their
+-- account. Pairwise subjects are one-way hashes, so resolving an id_token_hint needs this table.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN subject_type TEXT NOT NULL DEFAULT '',
+    ADD COLUMN sector_identifier_uri TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE pairwise_subjects (
+    sector_identifier TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (sector_identifier, subject)
+);
+
+CREATE INDEX idx_pairwise_subjects_account_id ON pairwise_subjects (account_id);
@@ -0,0 +1,4 @@
+-- Revert 0031: remove the per-client ID token signing algorithm
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS id_token_signed_response_alg;
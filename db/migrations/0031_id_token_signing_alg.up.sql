@@ -0,0 +1,9 @@
+-- 0031_id_token_signing_alg
+-- Per-client ID token signing algorithm (OpenID Connect Registration §2)
+-- See: https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+--
+-- id_token_signed_response_alg: the JWS algorithm (RS256, PS256, ES256 or EdDSA) the
+-- client's ID tokens and logout tokens are signed with. Empty means RS256.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN id_token_signed_response_alg TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Revert 0032: remove the per-client access token profile
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS access_token_profile;
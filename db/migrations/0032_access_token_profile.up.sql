@@ -0,0 +1,9 @@
+-- 0032_access_token_profile
+-- Per-client access token profile (RFC 9068)
+-- See: https://www.rfc-editor.org/rfc/rfc9068
+--
+-- access_token_profile: empty issues gosso's own access token format; rfc9068 issues
+-- the JWT profile for OAuth 2.0 access tokens (typ at+jwt).
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN access_token_profile TEXT NOT NULL DEFAULT '';
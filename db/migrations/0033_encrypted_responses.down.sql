@@ -0,0 +1,7 @@
+-- Revert 0033: remove per-client ID token and UserInfo encryption
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS userinfo_encrypted_response_enc,
+    DROP COLUMN IF EXISTS userinfo_encrypted_response_alg,
+    DROP COLUMN IF EXISTS id_token_encrypted_response_enc,
+    DROP COLUMN IF EXISTS id_token_encrypted_response_alg;
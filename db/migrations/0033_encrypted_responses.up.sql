@@ -0,0 +1,13 @@
+-- 0033_encrypted_responses
+-- Per-client encryption of ID tokens and UserInfo responses (OpenID Connect Registration §2)
+-- See: https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+--
+-- *_encrypted_response_alg: the JWE key management algorithm (RSA-OAEP or RSA-OAEP-256)
+-- used with an encryption key from the client's jwks or jwks_uri. Empty means unencrypted.
+-- *_encrypted_response_enc: the JWE content encryption algorithm, A128CBC-HS256 unless set.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN id_token_encrypted_response_alg TEXT NOT NULL DEFAULT '',
+    ADD COLUMN id_token_encrypted_response_enc TEXT NOT NULL DEFAULT '',
+    ADD COLUMN userinfo_encrypted_response_alg TEXT NOT NULL DEFAULT '',
+    ADD COLUMN userinfo_encrypted_response_enc TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Revert 0034: remove the per-client UserInfo signing algorithm
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS userinfo_signed_response_alg;
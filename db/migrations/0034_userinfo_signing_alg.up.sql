@@ -0,0 +1,9 @@
+-- 0034_userinfo_signing_alg
+-- Per-client signed UserInfo responses (OpenID Connect Registration §2)
+-- See: https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+--
+-- userinfo_signed_response_alg: the JWS algorithm (RS256, PS256, ES256 or EdDSA) the
+-- client's UserInfo responses are signed with. Empty means plain JSON responses.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN userinfo_signed_response_alg TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- Revert 0035: remove the per-client refresh token lifetime policy
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS refresh_token_expiration,
+    DROP COLUMN IF EXISTS refresh_token_absolute_lifetime,
+    DROP COLUMN IF EXISTS refresh_token_idle_lifetime;
@@ -0,0 +1,17 @@
+-- 0035_refresh_token_policy
+-- Per-client refresh token lifetime policy and offline access (OpenID Connect Core §11)
+-- See: https://openid.net/specs/openid-connect-core-1_0.html#OfflineAccess
+--
+-- refresh_token_idle_lifetime: seconds a refresh token stays valid after it is issued.
+-- 0 applies auth.refresh_token_expiry.
+--
+-- refresh_token_absolute_lifetime: seconds after the original grant beyond which no
+-- rotated refresh token is valid. 0 sets no limit.
+--
+-- refresh_token_expiration: "sliding" starts a new idle lifetime on every rotation; "fixed"
+-- keeps the expiry of the first refresh token of the grant. Empty means sliding.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN refresh_token_idle_lifetime INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN refresh_token_absolute_lifetime INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN refresh_token_expiration TEXT NOT NULL DEFAULT '';
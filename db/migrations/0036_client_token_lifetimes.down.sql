@@ -0,0 +1,7 @@
+-- Revert 0036: remove the per-client token and code lifetimes
+
+ALTER TABLE oauth2_clients
+    DROP COLUMN IF EXISTS device_code_lifetime,
+    DROP COLUMN IF EXISTS authorization_code_lifetime,
+    DROP COLUMN IF EXISTS id_token_lifetime,
+    DROP COLUMN IF EXISTS access_token_lifetime;
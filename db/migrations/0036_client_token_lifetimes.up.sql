@@ -0,0 +1,13 @@
+-- 0036_client_token_lifetimes
+-- Per-client token and code lifetimes
+--
+-- access_token_lifetime, id_token_lifetime, authorization_code_lifetime and
+-- device_code_lifetime: seconds the client's access tokens, ID tokens, authorization codes
+-- and device codes stay valid. 0 applies the server-wide auth.*_expiry setting. Values are
+-- bounded by the auth.max_client_*_expiry ceilings when the client is registered or updated.
+
+ALTER TABLE oauth2_clients
+    ADD COLUMN access_token_lifetime INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN id_token_lifetime INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN authorization_code_lifetime INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN device_code_lifetime INTEGER NOT NULL DEFAULT 0;
@@ -22,6 +22,8 @@ import (
 	"github.com/rushairer/gosso/internal/controllerutil"
 	"github.com/rushairer/gosso/internal/utility"
 	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
 	"github.com/rushairer/gosso/middleware"
 )
 
@@ -72,6 +74,18 @@ type AdminLockoutManager interface {
 	ClearLockout(ctx context.Context, accountID string) error
 }
 
+// AdminInitialAccessTokenIssuer issues initial access tokens for dynamic client registration.
+type AdminInitialAccessTokenIssuer interface {
+	IssueInitialAccessToken(ctx context.Context, issuedBy, accountID string, ttl time.Duration, uses int) (*oauth2Service.InitialAccessToken, error)
+}
+
+// AdminSigningKeyManager manages the keys that sign the server's tokens.
+type AdminSigningKeyManager interface {
+	Keys() []tokenDomain.SigningKey
+	Rotate(alg string) ([]tokenDomain.SigningKey, error)
+	Revoke(kid string) error
+}
+
 type accountSummaryLister interface {
 	ListAccountSummaries(ctx context.Context, page, pageSize int, status string) ([]*accountService.AccountSummary, int, error)
 }
@@ -82,15 +96,21 @@ type AdminController struct {
 	consentMgr    AdminConsentManager
 	auditQueryMgr AdminAuditQueryManager
 	lockoutMgr    AdminLockoutManager
+	iatIssuer     AdminInitialAccessTokenIssuer
+	signingKeys   AdminSigningKeyManager
 	logger        *zap.Logger
 }
 
-// NewAdminController creates a new admin controller instance
+// NewAdminController creates a new admin controller instance.
+// iatIssuer may be nil when dynamic client registration is disabled, and signingKeys
+// when the signing keys are not managed through the admin API.
 func NewAdminController(
 	accountSvc accountService.AccountService,
 	consentMgr AdminConsentManager,
 	auditQueryMgr AdminAuditQueryManager,
 	lockoutMgr AdminLockoutManager,
+	iatIssuer AdminInitialAccessTokenIssuer,
+	signingKeys AdminSigningKeyManager,
 	logger *zap.Logger,
 ) *AdminController {
 	return &AdminController{
@@ -98,6 +118,8 @@ func NewAdminController(
 		consentMgr:    consentMgr,
 		auditQueryMgr: auditQueryMgr,
 		lockoutMgr:    lockoutMgr,
+		iatIssuer:     iatIssuer,
+		signingKeys:   signingKeys,
 		logger:        logger,
 	}
 }
@@ -105,6 +127,14 @@ func NewAdminController(
 // RegisterRoutes registers admin routes
 func (c *AdminController) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/audit-logs", authMiddleware.RequirePermission("admin:audit:read"), c.ListAuditLogs)
+	if c.iatIssuer != nil {
+		rg.POST("/oauth2/initial-access-tokens", authMiddleware.RequirePermission("admin:clients:manage"), c.IssueInitialAccessToken)
+	}
+	if c.signingKeys != nil {
+		rg.GET("/signing-keys", authMiddleware.RequirePermission("admin:keys:read"), c.ListSigningKeys)
+		rg.POST("/signing-keys/rotate", authMiddleware.RequirePermission("admin:keys:manage"), c.RotateSigningKey)
+		rg.POST("/signing-keys/:kid/revoke", authMiddleware.RequirePermission("admin:keys:manage"), c.RevokeSigningKey)
+	}
 
 	accounts := rg.Group("/accounts")
 	{
@@ -624,3 +654,60 @@ func (c *AdminController) ResetMFA(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse("MFA reset successfully"))
 }
+
+// IssueInitialAccessTokenRequestBody is the request body for issuing an initial access token.
+type IssueInitialAccessTokenRequestBody struct {
+	// AccountID owns the clients registered with the token. Defaults to the administrator.
+	AccountID        string `json:"account_id"`
+	ExpiresIn        int    `json:"expires_in" binding:"omitempty,min=0"`
+	MaxRegistrations int    `json:"max_registrations" binding:"omitempty,min=0"`
+}
+
+// IssueInitialAccessToken POST /api/admin/oauth2/initial-access-tokens
+//
+// Issues a token that authorizes dynamic client registration (RFC 7591 §3). The
+// plaintext token is only returned in this response.
+func (c *AdminController) IssueInitialAccessToken(ctx *gin.Context) {
+	var req IssueInitialAccessTokenRequestBody
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gouno.NewErrorResponse(http.StatusBadRequest, "invalid request body"))
+		return
+	}
+	adminID := ctx.GetString(middleware.ContextKeyAccountID)
+	accountID := adminID
+	if req.AccountID != "" {
+		var ok bool
+		if accountID, ok = controllerutil.ValidateUUID(ctx, req.AccountID, "account_id"); !ok {
+			return
+		}
+	}
+	account, err := c.accountSvc.FindAccountByID(ctx, accountID)
+	if err != nil {
+		controllerutil.AbortWithServiceError(ctx, c.logger, err, adminAccountErrorMap,
+			http.StatusInternalServerError, "Failed to get account")
+		return
+	}
+	if !account.IsActive() {
+		ctx.JSON(http.StatusConflict, gouno.NewErrorResponse(http.StatusConflict, "account is not active"))
+		return
+	}
+
+	iat, err := c.iatIssuer.IssueInitialAccessToken(ctx.Request.Context(), adminID, accountID,
+		time.Duration(req.ExpiresIn)*time.Second, req.MaxRegistrations)
+	if err != nil {
+		if oauth2Service.IsValidationError(err) {
+			ctx.JSON(http.StatusBadRequest, gouno.NewErrorResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+		controllerutil.AbortWithServiceError(ctx, c.logger, err, nil, http.StatusInternalServerError, "Failed to issue initial access token")
+		return
+	}
+
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusCreated, gouno.NewSuccessResponse(gin.H{
+		"initial_access_token": iat.Token,
+		"account_id":           iat.AccountID,
+		"expires_at":           iat.ExpiresAt,
+		"max_registrations":    iat.Uses,
+	}))
+}
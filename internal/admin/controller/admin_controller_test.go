@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -22,6 +23,7 @@ import (
 	auditRepository "github.com/rushairer/gosso/internal/audit/repository"
 	authService "github.com/rushairer/gosso/internal/auth/service"
 	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
 	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
 	gm "github.com/rushairer/gosso/middleware"
 )
@@ -207,7 +209,7 @@ func setupAdminController(accountSvc *mockAccountService) *gin.Engine {
 		ctx.Next()
 	})
 
-	ctrl := NewAdminController(accountSvc, &mockConsentManager{}, &mockAuditQueryManager{}, &mockLockoutManager{}, zap.NewNop())
+	ctrl := NewAdminController(accountSvc, &mockConsentManager{}, &mockAuditQueryManager{}, &mockLockoutManager{}, nil, nil, zap.NewNop())
 
 	api := engine.Group("/api")
 	ctrl.RegisterRoutes(api.Group("/admin"))
@@ -226,7 +228,7 @@ func setupAdminControllerWithAdminID(accountSvc *mockAccountService, adminID str
 		ctx.Next()
 	})
 
-	ctrl := NewAdminController(accountSvc, &mockConsentManager{}, &mockAuditQueryManager{}, &mockLockoutManager{}, zap.NewNop())
+	ctrl := NewAdminController(accountSvc, &mockConsentManager{}, &mockAuditQueryManager{}, &mockLockoutManager{}, nil, nil, zap.NewNop())
 
 	api := engine.Group("/api")
 	ctrl.RegisterRoutes(api.Group("/admin"))
@@ -989,3 +991,185 @@ func TestResetMFA_SelfAccount(t *testing.T) {
 
 	assert.Equal(t, http.StatusForbidden, w.Code)
 }
+
+// mockInitialAccessTokenIssuer implements AdminInitialAccessTokenIssuer for testing.
+type mockInitialAccessTokenIssuer struct {
+	issuedBy  string
+	accountID string
+	ttl       time.Duration
+	uses      int
+	err       error
+}
+
+func (m *mockInitialAccessTokenIssuer) IssueInitialAccessToken(_ context.Context, issuedBy, accountID string, ttl time.Duration, uses int) (*oauth2Service.InitialAccessToken, error) {
+	m.issuedBy, m.accountID, m.ttl, m.uses = issuedBy, accountID, ttl, uses
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &oauth2Service.InitialAccessToken{Token: "iat-token", AccountID: accountID, ExpiresAt: time.Now().Add(ttl), Uses: uses}, nil
+}
+
+func setupInitialAccessTokenController(accountSvc *mockAccountService, issuer AdminInitialAccessTokenIssuer, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		ctx.Set(gm.ContextKeyAccountID, adminUUID)
+		ctx.Set(gm.ContextKeyClaims, &tokenDomain.AccessTokenClaims{Permissions: permissions})
+		ctx.Next()
+	})
+	ctrl := NewAdminController(accountSvc, &mockConsentManager{}, &mockAuditQueryManager{}, &mockLockoutManager{}, issuer, nil, zap.NewNop())
+	ctrl.RegisterRoutes(engine.Group("/api/admin"))
+	return engine
+}
+
+func postInitialAccessToken(engine *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/oauth2/initial-access-tokens", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestIssueInitialAccessToken_Success(t *testing.T) {
+	accountSvc := &mockAccountService{findByIDFn: func() (*accountDomain.Account, error) { return newAdminTestAccount(), nil }}
+	issuer := &mockInitialAccessTokenIssuer{}
+	engine := setupInitialAccessTokenController(accountSvc, issuer, []string{"admin:clients:manage"})
+
+	w := postInitialAccessToken(engine, `{"account_id":"`+validUUID+`","expires_in":3600,"max_registrations":5}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), "iat-token")
+	assert.Equal(t, adminUUID, issuer.issuedBy)
+	assert.Equal(t, validUUID, issuer.accountID)
+	assert.Equal(t, time.Hour, issuer.ttl)
+	assert.Equal(t, 5, issuer.uses)
+
+	// Without account_id the token registers clients for the administrator.
+	w = postInitialAccessToken(engine, `{}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, adminUUID, issuer.accountID)
+}
+
+func TestIssueInitialAccessToken_Errors(t *testing.T) {
+	active := func() (*accountDomain.Account, error) { return newAdminTestAccount(), nil }
+	suspended := func() (*accountDomain.Account, error) {
+		account := newAdminTestAccount()
+		account.Status = accountDomain.AccountStatusSuspended
+		return account, nil
+	}
+	tests := []struct {
+		name        string
+		findByID    func() (*accountDomain.Account, error)
+		issuerErr   error
+		permissions []string
+		body        string
+		status      int
+	}{
+		{"missing permission", active, nil, []string{"admin:users:manage"}, `{}`, http.StatusForbidden},
+		{"invalid account id", active, nil, []string{"admin:*"}, `{"account_id":"not-a-uuid"}`, http.StatusBadRequest},
+		{"account not found", func() (*accountDomain.Account, error) { return nil, accountRepository.ErrAccountNotFound }, nil, []string{"admin:*"}, `{}`, http.StatusNotFound},
+		{"account not active", suspended, nil, []string{"admin:*"}, `{}`, http.StatusConflict},
+		{"invalid lifetime", active, &oauth2Service.ValidationError{Message: "expires_in out of range"}, []string{"admin:*"}, `{"expires_in":5}`, http.StatusBadRequest},
+		{"issuer failure", active, fmt.Errorf("redis down"), []string{"admin:*"}, `{}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := &mockInitialAccessTokenIssuer{err: tt.issuerErr}
+			engine := setupInitialAccessTokenController(&mockAccountService{findByIDFn: tt.findByID}, issuer, tt.permissions)
+			w := postInitialAccessToken(engine, tt.body)
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestIssueInitialAccessToken_DisabledWithoutIssuer(t *testing.T) {
+	engine := setupAdminController(&mockAccountService{})
+	w := postInitialAccessToken(engine, `{}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// mockSigningKeyManager implements AdminSigningKeyManager for testing.
+type mockSigningKeyManager struct {
+	keys    []tokenDomain.SigningKey
+	rotated string
+	revoked string
+	err     error
+}
+
+func (m *mockSigningKeyManager) Keys() []tokenDomain.SigningKey { return m.keys }
+
+func (m *mockSigningKeyManager) Rotate(alg string) ([]tokenDomain.SigningKey, error) {
+	m.rotated = alg
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []tokenDomain.SigningKey{{KeyID: "kid-2", Algorithm: "RS256", State: tokenDomain.SigningKeyActive}}, nil
+}
+
+func (m *mockSigningKeyManager) Revoke(kid string) error {
+	m.revoked = kid
+	return m.err
+}
+
+func setupSigningKeyController(keys *mockSigningKeyManager, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		ctx.Set(gm.ContextKeyAccountID, adminUUID)
+		ctx.Set(gm.ContextKeyClaims, &tokenDomain.AccessTokenClaims{Permissions: permissions})
+		ctx.Next()
+	})
+	ctrl := NewAdminController(&mockAccountService{}, &mockConsentManager{}, &mockAuditQueryManager{}, &mockLockoutManager{}, nil, keys, zap.NewNop())
+	ctrl.RegisterRoutes(engine.Group("/api/admin"))
+	return engine
+}
+
+func TestSigningKeys(t *testing.T) {
+	activatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	keys := &mockSigningKeyManager{keys: []tokenDomain.SigningKey{
+		{KeyID: "kid-1", State: tokenDomain.SigningKeyActive, ActivatesAt: activatedAt},
+	}}
+	engine := setupSigningKeyController(keys, []string{"admin:keys:read", "admin:keys:manage"})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/signing-keys", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"kid":"kid-1"`)
+	assert.Contains(t, w.Body.String(), `"activates_at":"2026-01-02T03:04:05Z"`)
+	assert.NotContains(t, w.Body.String(), "retired_at")
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/signing-keys/rotate?alg=RS256", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"kid":"kid-2"`)
+	assert.Contains(t, w.Body.String(), `"alg":"RS256"`)
+	assert.Equal(t, "RS256", keys.rotated)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/signing-keys/kid-1/revoke", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "kid-1", keys.revoked)
+}
+
+func TestSigningKeys_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		permissions []string
+		path        string
+		status      int
+	}{
+		{"missing permission", nil, []string{"admin:keys:read"}, "/api/admin/signing-keys/kid-1/revoke", http.StatusForbidden},
+		{"unknown key", tokenDomain.ErrSigningKeyNotFound, []string{"admin:*"}, "/api/admin/signing-keys/kid-9/revoke", http.StatusNotFound},
+		{"no keyring", tokenDomain.ErrKeyringDisabled, []string{"admin:*"}, "/api/admin/signing-keys/rotate", http.StatusConflict},
+		{"unconfigured algorithm", tokenDomain.ErrSigningAlgUnavailable, []string{"admin:*"}, "/api/admin/signing-keys/rotate?alg=ES256", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := setupSigningKeyController(&mockSigningKeyManager{err: tt.err}, tt.permissions)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, nil))
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rushairer/gouno"
+
+	"github.com/rushairer/gosso/internal/controllerutil"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+)
+
+// adminSigningKeyErrorMap maps signing keyring errors to HTTP responses.
+var adminSigningKeyErrorMap = []controllerutil.ErrorRule{
+	{Sentinel: tokenDomain.ErrSigningKeyNotFound, Mapping: controllerutil.ErrorMapping{Status: http.StatusNotFound, Message: "signing key not found"}},
+	{Sentinel: tokenDomain.ErrKeyringDisabled, Mapping: controllerutil.ErrorMapping{Status: http.StatusConflict, Message: "signing keyring is not configured"}},
+	{Sentinel: tokenDomain.ErrSigningAlgUnavailable, Mapping: controllerutil.ErrorMapping{Status: http.StatusBadRequest, Message: "signing algorithm is not configured"}},
+}
+
+// signingKeyResponse is the admin view of a signing key. Key material is never returned.
+type signingKeyResponse struct {
+	KeyID       string                      `json:"kid"`
+	Algorithm   string                      `json:"alg"`
+	State       tokenDomain.SigningKeyState `json:"state"`
+	CreatedAt   *time.Time                  `json:"created_at,omitempty"`
+	ActivatesAt *time.Time                  `json:"activates_at,omitempty"`
+	RetiredAt   *time.Time                  `json:"retired_at,omitempty"`
+	RevokedAt   *time.Time                  `json:"revoked_at,omitempty"`
+	ExpiresAt   *time.Time                  `json:"expires_at,omitempty"`
+}
+
+func newSigningKeyResponse(key tokenDomain.SigningKey) signingKeyResponse {
+	optional := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+	return signingKeyResponse{
+		KeyID:       key.KeyID,
+		Algorithm:   key.Algorithm,
+		State:       key.State,
+		CreatedAt:   optional(key.CreatedAt),
+		ActivatesAt: optional(key.ActivatesAt),
+		RetiredAt:   optional(key.RetiredAt),
+		RevokedAt:   optional(key.RevokedAt),
+		ExpiresAt:   optional(key.ExpiresAt),
+	}
+}
+
+// ListSigningKeys GET /api/admin/signing-keys
+func (c *AdminController) ListSigningKeys(ctx *gin.Context) {
+	keys := c.signingKeys.Keys()
+	items := make([]signingKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, newSigningKeyResponse(key))
+	}
+	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse(gin.H{"keys": items}))
+}
+
+// RotateSigningKey POST /api/admin/signing-keys/rotate
+// The optional alg query parameter rotates only the key of that algorithm; otherwise the
+// key of every configured algorithm is rotated. It returns the new active keys.
+func (c *AdminController) RotateSigningKey(ctx *gin.Context) {
+	keys, err := c.signingKeys.Rotate(ctx.Query("alg"))
+	if err != nil {
+		controllerutil.AbortWithServiceError(ctx, c.logger, err, adminSigningKeyErrorMap,
+			http.StatusInternalServerError, "Failed to rotate signing key")
+		return
+	}
+	items := make([]signingKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, newSigningKeyResponse(key))
+	}
+	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse(gin.H{"keys": items}))
+}
+
+// RevokeSigningKey POST /api/admin/signing-keys/:kid/revoke
+func (c *AdminController) RevokeSigningKey(ctx *gin.Context) {
+	if err := c.signingKeys.Revoke(ctx.Param("kid")); err != nil {
+		controllerutil.AbortWithServiceError(ctx, c.logger, err, adminSigningKeyErrorMap,
+			http.StatusInternalServerError, "Failed to revoke signing key")
+		return
+	}
+	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse("signing key revoked"))
+}
@@ -44,4 +44,10 @@ const (
 	ActionOAuth2ClientRegister = "oauth2.client.register"
 	ActionOAuth2ClientUpdate   = "oauth2.client.update"
 	ActionOAuth2ClientDelete   = "oauth2.client.delete"
+
+	// OAuth2 dynamic client registration audit actions
+	ActionOAuth2InitialAccessTokenIssue = "oauth2.initial_access_token.issue"
+
+	// OAuth2 token exchange audit actions
+	ActionOAuth2TokenExchange = "oauth2.token.exchange"
 )
@@ -188,6 +188,7 @@ func (c *AuthController) Login(ctx *gin.Context) {
 	controllerutil.SetNoCacheHeaders(ctx)
 	setSSOAuthCookie(ctx, result.AccessToken, int(c.tokenMgr.AccessExpiry().Seconds()), c.secureCookie)
 	setRefreshTokenCookie(ctx, result.RefreshToken, int(c.tokenMgr.RefreshExpiry().Seconds()), c.secureCookie)
+	controllerutil.SetBrowserStateCookie(ctx, result.Session.ID, int(c.tokenMgr.RefreshExpiry().Seconds()), c.secureCookie)
 	respondTokenSet(ctx, c.tokenMgr, result.AccessToken, result.RefreshToken, result.Session.ID)
 }
 
@@ -222,6 +223,7 @@ func (c *AuthController) Refresh(ctx *gin.Context) {
 	controllerutil.SetNoCacheHeaders(ctx)
 	setSSOAuthCookie(ctx, result.AccessToken, int(c.tokenMgr.AccessExpiry().Seconds()), c.secureCookie)
 	setRefreshTokenCookie(ctx, result.RefreshToken, int(c.tokenMgr.RefreshExpiry().Seconds()), c.secureCookie)
+	controllerutil.SetBrowserStateCookie(ctx, result.SessionID, int(c.tokenMgr.RefreshExpiry().Seconds()), c.secureCookie)
 	respondTokenSet(ctx, c.tokenMgr, result.AccessToken, result.RefreshToken, result.SessionID)
 }
 
@@ -265,6 +267,7 @@ func (c *AuthController) MFAVerify(ctx *gin.Context) {
 	controllerutil.SetNoCacheHeaders(ctx)
 	setSSOAuthCookie(ctx, result.AccessToken, int(c.tokenMgr.AccessExpiry().Seconds()), c.secureCookie)
 	setRefreshTokenCookie(ctx, result.RefreshToken, int(c.tokenMgr.RefreshExpiry().Seconds()), c.secureCookie)
+	controllerutil.SetBrowserStateCookie(ctx, result.Session.ID, int(c.tokenMgr.RefreshExpiry().Seconds()), c.secureCookie)
 	respondTokenSet(ctx, c.tokenMgr, result.AccessToken, result.RefreshToken, result.Session.ID)
 }
 
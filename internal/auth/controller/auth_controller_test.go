@@ -15,6 +15,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/rushairer/gosso/internal/auth/service"
+	sessionService "github.com/rushairer/gosso/internal/session/service"
 )
 
 // ──────────────────────────────────────────────
@@ -52,7 +53,7 @@ func TestLogin_Success(t *testing.T) {
 	assert.Equal(t, "Bearer", data["token_type"])
 	assert.Equal(t, float64(900), data["expires_in"])
 	assert.Equal(t, session.ID, data["session_id"])
-	require.Len(t, w.Result().Cookies(), 2)
+	require.Len(t, w.Result().Cookies(), 3)
 	assert.Equal(t, "__Host-access_token", w.Result().Cookies()[0].Name)
 	assert.Equal(t, "access-123", w.Result().Cookies()[0].Value)
 	assert.True(t, w.Result().Cookies()[0].HttpOnly)
@@ -60,6 +61,10 @@ func TestLogin_Success(t *testing.T) {
 	assert.Equal(t, "__Host-refresh_token", w.Result().Cookies()[1].Name)
 	assert.True(t, w.Result().Cookies()[1].HttpOnly)
 	assert.Equal(t, http.SameSiteStrictMode, w.Result().Cookies()[1].SameSite)
+	// The browser state is read by the check_session_iframe, so it is not HttpOnly.
+	assert.Equal(t, sessionService.BrowserStateCookieName, w.Result().Cookies()[2].Name)
+	assert.Equal(t, sessionService.BrowserState(session.ID), w.Result().Cookies()[2].Value)
+	assert.False(t, w.Result().Cookies()[2].HttpOnly)
 }
 
 func TestLogin_MFARequired(t *testing.T) {
@@ -217,11 +222,12 @@ func TestMFAVerify_Success(t *testing.T) {
 	assert.Equal(t, "Bearer", data["token_type"])
 	assert.Equal(t, float64(900), data["expires_in"])
 	assert.Equal(t, session.ID, data["session_id"])
-	require.Len(t, w.Result().Cookies(), 2)
+	require.Len(t, w.Result().Cookies(), 3)
 	assert.Equal(t, "__Host-access_token", w.Result().Cookies()[0].Name)
 	assert.Equal(t, "mfa-access-123", w.Result().Cookies()[0].Value)
 	assert.True(t, w.Result().Cookies()[0].HttpOnly)
 	assert.Equal(t, "__Host-refresh_token", w.Result().Cookies()[1].Name)
+	assert.Equal(t, sessionService.BrowserStateCookieName, w.Result().Cookies()[2].Name)
 }
 
 func TestMFAVerify_MissingCode(t *testing.T) {
@@ -41,6 +41,7 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 
 	clearSSOAuthCookie(ctx, c.secureCookie)
 	clearRefreshTokenCookie(ctx, c.secureCookie)
+	controllerutil.ClearBrowserStateCookie(ctx, c.secureCookie)
 	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse("logged out"))
 }
 
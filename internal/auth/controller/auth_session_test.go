@@ -37,6 +37,12 @@ func TestLogout_Success(t *testing.T) {
 	engine.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	require.Contains(t, cookies, sessionService.BrowserStateCookieName)
+	assert.Less(t, cookies[sessionService.BrowserStateCookieName].MaxAge, 0)
 }
 
 func TestLogout_ServiceError(t *testing.T) {
@@ -109,10 +109,6 @@ func clearRefreshTokenCookie(ctx *gin.Context, secure bool) {
 	})
 }
 
-func isSecureRequest(ctx *gin.Context) bool {
-	return ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
-}
-
 // mfaRequiredResponse constructs the MFA-required response body.
 func mfaRequiredResponse(token string, mfaTypes []string) gin.H {
 	return gin.H{
@@ -274,8 +274,9 @@ func (c *PasskeyController) LoginComplete(ctx *gin.Context) {
 	}
 
 	controllerutil.SetNoCacheHeaders(ctx)
-	setSSOAuthCookie(ctx, loginResult.AccessToken, int(c.tokenMgr.AccessExpiry().Seconds()), isSecureRequest(ctx))
-	setRefreshTokenCookie(ctx, loginResult.RefreshToken, int(c.tokenMgr.RefreshExpiry().Seconds()), isSecureRequest(ctx))
+	setSSOAuthCookie(ctx, loginResult.AccessToken, int(c.tokenMgr.AccessExpiry().Seconds()), controllerutil.IsSecureRequest(ctx))
+	setRefreshTokenCookie(ctx, loginResult.RefreshToken, int(c.tokenMgr.RefreshExpiry().Seconds()), controllerutil.IsSecureRequest(ctx))
+	controllerutil.SetBrowserStateCookie(ctx, loginResult.Session.ID, int(c.tokenMgr.RefreshExpiry().Seconds()), controllerutil.IsSecureRequest(ctx))
 	respondTokenSet(ctx, c.tokenMgr, loginResult.AccessToken, loginResult.RefreshToken, loginResult.Session.ID)
 }
 
@@ -367,8 +368,9 @@ func (c *PasskeyController) MFAComplete(ctx *gin.Context) {
 	}
 
 	controllerutil.SetNoCacheHeaders(ctx)
-	setSSOAuthCookie(ctx, result.AccessToken, int(c.tokenMgr.AccessExpiry().Seconds()), isSecureRequest(ctx))
-	setRefreshTokenCookie(ctx, result.RefreshToken, int(c.tokenMgr.RefreshExpiry().Seconds()), isSecureRequest(ctx))
+	setSSOAuthCookie(ctx, result.AccessToken, int(c.tokenMgr.AccessExpiry().Seconds()), controllerutil.IsSecureRequest(ctx))
+	setRefreshTokenCookie(ctx, result.RefreshToken, int(c.tokenMgr.RefreshExpiry().Seconds()), controllerutil.IsSecureRequest(ctx))
+	controllerutil.SetBrowserStateCookie(ctx, result.Session.ID, int(c.tokenMgr.RefreshExpiry().Seconds()), controllerutil.IsSecureRequest(ctx))
 	respondTokenSet(ctx, c.tokenMgr, result.AccessToken, result.RefreshToken, result.Session.ID)
 }
 
@@ -24,6 +24,7 @@ import (
 	"github.com/rushairer/gosso/internal/auth/service"
 	sessionDomain "github.com/rushairer/gosso/internal/session/domain"
 	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	tokenService "github.com/rushairer/gosso/internal/token/service"
 	"github.com/rushairer/gosso/middleware"
 )
 
@@ -71,12 +72,26 @@ type mockTokenMgrForPasskey struct{}
 func (m *mockTokenMgrForPasskey) GenerateAccessToken(_ *tokenDomain.AccessTokenClaims) (string, error) {
 	return "mock-access", nil
 }
-func (m *mockTokenMgrForPasskey) GenerateRefreshToken(_ context.Context, _, _, _, _ string) (*tokenDomain.RefreshToken, error) {
+func (m *mockTokenMgrForPasskey) GenerateAccessTokenWithLifetime(_ *tokenDomain.AccessTokenClaims, _ time.Duration) (string, error) {
+	return "mock-access", nil
+}
+
+func (m *mockTokenMgrForPasskey) GenerateResourceAccessToken(_ *tokenDomain.AccessTokenClaims, _ time.Duration) (string, error) {
+	return "mock-access", nil
+}
+
+func (m *mockTokenMgrForPasskey) GenerateReferenceAccessToken(_ context.Context, _ *tokenDomain.AccessTokenClaims, _ time.Duration) (string, error) {
+	return "mock-access", nil
+}
+func (m *mockTokenMgrForPasskey) GenerateRefreshToken(_ context.Context, _, _, _, _ string, _ json.RawMessage, _, _ []string, _ *tokenDomain.ConfirmationClaim, _ *tokenDomain.RefreshTokenPolicy) (*tokenDomain.RefreshToken, error) {
 	return &tokenDomain.RefreshToken{Token: "mock-refresh"}, nil
 }
 func (m *mockTokenMgrForPasskey) ValidateAccessTokenWithContext(_ context.Context, _ string) (*tokenDomain.AccessTokenClaims, error) {
 	return &tokenDomain.AccessTokenClaims{AccountID: "account-001"}, nil
 }
+func (m *mockTokenMgrForPasskey) ParseAccessToken(ctx context.Context, token string) (*tokenDomain.AccessTokenClaims, error) {
+	return m.ValidateAccessTokenWithContext(ctx, token)
+}
 func (m *mockTokenMgrForPasskey) ValidateRefreshToken(_ context.Context, _ string) (*tokenDomain.RefreshToken, error) {
 	return &tokenDomain.RefreshToken{Token: "mock-refresh"}, nil
 }
@@ -87,9 +102,12 @@ func (m *mockTokenMgrForPasskey) RevokeRefreshToken(_ context.Context, _ string)
 func (m *mockTokenMgrForPasskey) RevokeAccessToken(_ context.Context, _ string, _ time.Time) error {
 	return nil
 }
-func (m *mockTokenMgrForPasskey) IntrospectToken(_ context.Context, _ string) (map[string]any, error) {
+func (m *mockTokenMgrForPasskey) IntrospectToken(_ context.Context, _, _ string) (map[string]any, error) {
 	return map[string]any{"active": true}, nil
 }
+func (m *mockTokenMgrForPasskey) ExchangeToken(_ context.Context, _ *tokenService.TokenExchangeRequest) (string, time.Time, error) {
+	return "exchanged-token", time.Now().Add(15 * time.Minute), nil
+}
 func (m *mockTokenMgrForPasskey) AccessExpiry() time.Duration  { return 15 * time.Minute }
 func (m *mockTokenMgrForPasskey) RefreshExpiry() time.Duration { return 7 * 24 * time.Hour }
 
@@ -7,6 +7,7 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -26,6 +27,7 @@ import (
 	sessionDomain "github.com/rushairer/gosso/internal/session/domain"
 	"github.com/rushairer/gosso/internal/testutil"
 	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	tokenService "github.com/rushairer/gosso/internal/token/service"
 	"github.com/rushairer/gosso/middleware"
 )
 
@@ -148,7 +150,19 @@ func (m *mockTokenManager) GenerateAccessToken(_ *tokenDomain.AccessTokenClaims)
 	return "mock-access-token", nil
 }
 
-func (m *mockTokenManager) GenerateRefreshToken(_ context.Context, _, _, _, _ string) (*tokenDomain.RefreshToken, error) {
+func (m *mockTokenManager) GenerateAccessTokenWithLifetime(_ *tokenDomain.AccessTokenClaims, _ time.Duration) (string, error) {
+	return "mock-access-token", nil
+}
+
+func (m *mockTokenManager) GenerateResourceAccessToken(_ *tokenDomain.AccessTokenClaims, _ time.Duration) (string, error) {
+	return "mock-access-token", nil
+}
+
+func (m *mockTokenManager) GenerateReferenceAccessToken(_ context.Context, _ *tokenDomain.AccessTokenClaims, _ time.Duration) (string, error) {
+	return "mock-access-token", nil
+}
+
+func (m *mockTokenManager) GenerateRefreshToken(_ context.Context, _, _, _, _ string, _ json.RawMessage, _, _ []string, _ *tokenDomain.ConfirmationClaim, _ *tokenDomain.RefreshTokenPolicy) (*tokenDomain.RefreshToken, error) {
 	return &tokenDomain.RefreshToken{Token: "mock-refresh-token"}, nil
 }
 
@@ -159,6 +173,10 @@ func (m *mockTokenManager) ValidateAccessTokenWithContext(_ context.Context, _ s
 	}, nil
 }
 
+func (m *mockTokenManager) ParseAccessToken(ctx context.Context, token string) (*tokenDomain.AccessTokenClaims, error) {
+	return m.ValidateAccessTokenWithContext(ctx, token)
+}
+
 func (m *mockTokenManager) ValidateRefreshToken(_ context.Context, _ string) (*tokenDomain.RefreshToken, error) {
 	return &tokenDomain.RefreshToken{Token: "mock-refresh-token"}, nil
 }
@@ -173,10 +191,14 @@ func (m *mockTokenManager) RevokeAccessToken(_ context.Context, _ string, _ time
 	return nil
 }
 
-func (m *mockTokenManager) IntrospectToken(_ context.Context, _ string) (map[string]any, error) {
+func (m *mockTokenManager) IntrospectToken(_ context.Context, _, _ string) (map[string]any, error) {
 	return map[string]any{"active": true}, nil
 }
 
+func (m *mockTokenManager) ExchangeToken(_ context.Context, _ *tokenService.TokenExchangeRequest) (string, time.Time, error) {
+	return "exchanged-token", time.Now().Add(15 * time.Minute), nil
+}
+
 func (m *mockTokenManager) AccessExpiry() time.Duration {
 	if m.accessExpiry > 0 {
 		return m.accessExpiry
@@ -15,6 +15,7 @@ import (
 	authService "github.com/rushairer/gosso/internal/auth/service"
 	sessionDomain "github.com/rushairer/gosso/internal/session/domain"
 	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	tokenService "github.com/rushairer/gosso/internal/token/service"
 	"github.com/rushairer/gosso/middleware"
 )
 
@@ -27,15 +28,32 @@ type TokenValidator interface {
 	ValidateAccessTokenWithContext(ctx context.Context, tokenString string) (*tokenDomain.AccessTokenClaims, error)
 }
 
+// DPoPProofVerifier validates DPoP proofs sent with DPoP-bound access tokens (RFC 9449 §7).
+type DPoPProofVerifier interface {
+	VerifyProof(ctx context.Context, proof, method, path, accessToken string) (string, error)
+	NonceRequired() bool
+	Nonce(ctx context.Context) (string, error)
+}
+
 // errUnauthorized is the generic error returned for all authentication failures.
 // Detailed reasons are logged server-side only to prevent information leakage.
 var errUnauthorized = errors.New("unauthorized")
 
+// DPoP authentication failures, reported in the WWW-Authenticate challenge (RFC 9449 §7.1).
+var (
+	errDPoPRequired     = errors.New("access token is bound to a DPoP key")
+	errInvalidDPoPProof = errors.New("invalid DPoP proof")
+	errUseDPoPNonce     = errors.New("DPoP nonce required")
+)
+
 // AuthConfigOptions holds configuration options for the JWT auth middleware.
 type AuthConfigOptions struct {
 	LoginURL         string
 	EnableCookieAuth bool
 	AuthCookieName   string
+	// DPoP verifies proofs for tokens sent with the DPoP authorization scheme.
+	// When nil, DPoP-bound tokens are rejected.
+	DPoP DPoPProofVerifier
 }
 
 // ValidateBearerToken extracts and validates the Bearer token from the request.
@@ -50,7 +68,7 @@ func ValidateBearerToken(ctx *gin.Context, tokenSvc TokenValidator, sessionValid
 
 // ValidateBearerTokenWithConfig validates token with specific config options.
 func ValidateBearerTokenWithConfig(ctx *gin.Context, tokenSvc TokenValidator, sessionValidator sessionDomain.SessionValidator, cfg AuthConfigOptions) (*tokenDomain.AccessTokenClaims, error) {
-	tokenString := extractBearerTokenWithConfig(ctx, cfg.EnableCookieAuth, cfg.AuthCookieName)
+	tokenString, dpop := extractAccessTokenWithConfig(ctx, cfg.EnableCookieAuth, cfg.AuthCookieName)
 	if tokenString == "" {
 		return nil, errUnauthorized
 	}
@@ -59,6 +77,9 @@ func ValidateBearerTokenWithConfig(ctx *gin.Context, tokenSvc TokenValidator, se
 	if err != nil {
 		return nil, errUnauthorized
 	}
+	if err := checkDPoPBinding(ctx, cfg.DPoP, tokenString, dpop, claims); err != nil {
+		return nil, err
+	}
 
 	// Reject internal MFA tokens from accessing general endpoints. OAuth/OIDC
 	// access tokens use normal scope strings such as "openid profile" and must
@@ -85,6 +106,52 @@ func ValidateBearerTokenWithConfig(ctx *gin.Context, tokenSvc TokenValidator, se
 	return claims, nil
 }
 
+// checkDPoPBinding enforces sender-constrained access tokens (RFC 9449 §7). A token bound
+// to a DPoP key must be sent with the DPoP scheme and a proof signed by that key and
+// carrying the token hash; a token sent with the DPoP scheme must be DPoP-bound.
+func checkDPoPBinding(ctx *gin.Context, verifier DPoPProofVerifier, tokenString string, dpop bool, claims *tokenDomain.AccessTokenClaims) error {
+	jkt := claims.DPoPThumbprint()
+	if !dpop {
+		if jkt != "" {
+			return errDPoPRequired
+		}
+		return nil
+	}
+	if verifier == nil || jkt == "" {
+		return errUnauthorized
+	}
+	proofs := ctx.Request.Header.Values("DPoP")
+	if len(proofs) != 1 {
+		return errInvalidDPoPProof
+	}
+	proofJKT, err := verifier.VerifyProof(ctx.Request.Context(), proofs[0], ctx.Request.Method, ctx.Request.URL.Path, tokenString)
+	if errors.Is(err, tokenService.ErrDPoPNonceRequired) {
+		return errUseDPoPNonce
+	}
+	if err != nil || proofJKT != jkt {
+		return errInvalidDPoPProof
+	}
+	return nil
+}
+
+// setDPoPChallenge adds the WWW-Authenticate challenge for a DPoP failure and, when the
+// verifier requires nonces, a fresh DPoP-Nonce (RFC 9449 §7.1, §9).
+func setDPoPChallenge(ctx *gin.Context, verifier DPoPProofVerifier, err error) {
+	code := "invalid_token"
+	switch {
+	case errors.Is(err, errInvalidDPoPProof):
+		code = "invalid_dpop_proof"
+	case errors.Is(err, errUseDPoPNonce):
+		code = "use_dpop_nonce"
+	}
+	ctx.Header("WWW-Authenticate", fmt.Sprintf(`DPoP error=%q, algs=%q`, code, strings.Join(tokenService.DPoPSigningAlgs, " ")))
+	if verifier != nil && verifier.NonceRequired() {
+		if nonce, nonceErr := verifier.Nonce(ctx.Request.Context()); nonceErr == nil {
+			ctx.Header("DPoP-Nonce", nonce)
+		}
+	}
+}
+
 // JWTAuthMiddleware is the JWT authentication middleware.
 // sessionValidator is required — it verifies the session still exists in Redis,
 // ensuring revoked sessions (e.g. after account deletion or suspension) are rejected.
@@ -124,6 +191,9 @@ func JWTAuthMiddlewareWithConfig(tokenSvc TokenValidator, sessionValidator sessi
 
 			status := http.StatusUnauthorized
 			msg := "unauthorized"
+			if errors.Is(err, errDPoPRequired) || errors.Is(err, errInvalidDPoPProof) || errors.Is(err, errUseDPoPNonce) {
+				setDPoPChallenge(ctx, cfg.DPoP, err)
+			}
 			if errors.Is(err, ErrTokenScopeNotAllowed) {
 				status = http.StatusForbidden
 				msg = "forbidden"
@@ -143,25 +213,38 @@ func extractBearerToken(ctx *gin.Context) string {
 }
 
 func extractBearerTokenWithConfig(ctx *gin.Context, enableCookieAuth bool, authCookieName string) string {
+	tokenString, dpop := extractAccessTokenWithConfig(ctx, enableCookieAuth, authCookieName)
+	if dpop {
+		return ""
+	}
+	return tokenString
+}
+
+// extractAccessTokenWithConfig returns the access token from the Authorization header
+// (Bearer or DPoP scheme) or the auth cookie, and whether the DPoP scheme was used.
+func extractAccessTokenWithConfig(ctx *gin.Context, enableCookieAuth bool, authCookieName string) (string, bool) {
 	authHeader := ctx.GetHeader("Authorization")
 	if authHeader != "" {
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
-			return parts[1]
+			return parts[1], false
+		}
+		if len(parts) == 2 && strings.EqualFold(parts[0], "DPoP") {
+			return parts[1], true
 		}
 	}
 	// Fallback to access_token cookie if enabled
 	if enableCookieAuth && authCookieName != "" {
 		if cookie, err := ctx.Cookie(authCookieName); err == nil {
-			return cookie
+			return cookie, false
 		}
 		if !strings.HasPrefix(authCookieName, "__Secure-") && !strings.HasPrefix(authCookieName, "__Host-") {
 			if cookie, err := ctx.Cookie("__Secure-" + authCookieName); err == nil {
-				return cookie
+				return cookie, false
 			}
 		}
 	}
-	return ""
+	return "", false
 }
 
 // AdminRequiredMiddleware checks for admin role (must be used after JWTAuthMiddleware)
@@ -423,6 +423,111 @@ func TestValidateBearerToken_NilSessionValidator(t *testing.T) {
 	engine.ServeHTTP(w, req)
 }
 
+// ──────────────────────────────────────────────
+// DPoP-bound access tokens
+// ──────────────────────────────────────────────
+
+// mockDPoPVerifier implements DPoPProofVerifier for testing.
+type mockDPoPVerifier struct {
+	jkt           string
+	err           error
+	nonceRequired bool
+	lastPath      string
+	lastToken     string
+}
+
+func (m *mockDPoPVerifier) VerifyProof(_ context.Context, _, _, path, accessToken string) (string, error) {
+	m.lastPath = path
+	m.lastToken = accessToken
+	return m.jkt, m.err
+}
+
+func (m *mockDPoPVerifier) NonceRequired() bool { return m.nonceRequired }
+
+func (m *mockDPoPVerifier) Nonce(context.Context) (string, error) { return "server-nonce", nil }
+
+func serveDPoPRequest(t *testing.T, claims *tokenDomain.AccessTokenClaims, verifier DPoPProofVerifier, scheme string, proofs ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler, err := JWTAuthMiddlewareWithConfig(&mockTokenValidator{claims: claims}, &mockSessionValidator{}, AuthConfigOptions{DPoP: verifier})
+	require.NoError(t, err)
+	engine := setupGin()
+	engine.GET("/protected", handler, func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", scheme+" access-token")
+	for _, proof := range proofs {
+		req.Header.Add("DPoP", proof)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestJWTAuthMiddleware_DPoP_Success(t *testing.T) {
+	verifier := &mockDPoPVerifier{jkt: "jkt-001"}
+	claims := &tokenDomain.AccessTokenClaims{AccountID: "acct-001", Cnf: &tokenDomain.ConfirmationClaim{JKT: "jkt-001"}}
+
+	w := serveDPoPRequest(t, claims, verifier, "DPoP", "proof")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/protected", verifier.lastPath)
+	assert.Equal(t, "access-token", verifier.lastToken, "proof must be checked against the presented token")
+}
+
+func TestJWTAuthMiddleware_DPoP_BoundTokenAsBearer(t *testing.T) {
+	claims := &tokenDomain.AccessTokenClaims{AccountID: "acct-001", Cnf: &tokenDomain.ConfirmationClaim{JKT: "jkt-001"}}
+
+	w := serveDPoPRequest(t, claims, &mockDPoPVerifier{jkt: "jkt-001"}, "Bearer")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), `DPoP error="invalid_token"`)
+}
+
+func TestJWTAuthMiddleware_DPoP_Invalid(t *testing.T) {
+	bound := &tokenDomain.AccessTokenClaims{AccountID: "acct-001", Cnf: &tokenDomain.ConfirmationClaim{JKT: "jkt-001"}}
+
+	tests := []struct {
+		name     string
+		claims   *tokenDomain.AccessTokenClaims
+		verifier DPoPProofVerifier
+		proofs   []string
+	}{
+		{"missing proof", bound, &mockDPoPVerifier{jkt: "jkt-001"}, nil},
+		{"multiple proofs", bound, &mockDPoPVerifier{jkt: "jkt-001"}, []string{"p1", "p2"}},
+		{"proof from other key", bound, &mockDPoPVerifier{jkt: "jkt-other"}, []string{"proof"}},
+		{"invalid proof", bound, &mockDPoPVerifier{err: tokenService.ErrInvalidDPoPProof}, []string{"proof"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveDPoPRequest(t, tt.claims, tt.verifier, "DPoP", tt.proofs...)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Contains(t, w.Header().Get("WWW-Authenticate"), `DPoP error="invalid_dpop_proof"`)
+		})
+	}
+}
+
+func TestJWTAuthMiddleware_DPoP_UnboundTokenRejected(t *testing.T) {
+	claims := &tokenDomain.AccessTokenClaims{AccountID: "acct-001"}
+
+	w := serveDPoPRequest(t, claims, &mockDPoPVerifier{jkt: "jkt-001"}, "DPoP", "proof")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Without a verifier, DPoP-bound tokens cannot be used at all.
+	bound := &tokenDomain.AccessTokenClaims{AccountID: "acct-001", Cnf: &tokenDomain.ConfirmationClaim{JKT: "jkt-001"}}
+	w = serveDPoPRequest(t, bound, nil, "DPoP", "proof")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestJWTAuthMiddleware_DPoP_NonceRequired(t *testing.T) {
+	verifier := &mockDPoPVerifier{err: tokenService.ErrDPoPNonceRequired, nonceRequired: true}
+	claims := &tokenDomain.AccessTokenClaims{AccountID: "acct-001", Cnf: &tokenDomain.ConfirmationClaim{JKT: "jkt-001"}}
+
+	w := serveDPoPRequest(t, claims, verifier, "DPoP", "proof")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), `DPoP error="use_dpop_nonce"`)
+	assert.Equal(t, "server-nonce", w.Header().Get("DPoP-Nonce"))
+}
+
 // ──────────────────────────────────────────────
 // JWTAuthMiddleware (end-to-end with real TokenService)
 // ──────────────────────────────────────────────
@@ -268,7 +268,7 @@ func TestRefreshTokens_PreservesClientIDAndScope(t *testing.T) {
 	// Generate refresh token with ClientID and Scope
 	clientID := "gosso-admin-spa"
 	scopes := "openid profile email admin"
-	rt, err := fixture.tokenSvc.GenerateRefreshToken(context.Background(), "account-001", clientID, session.ID, scopes)
+	rt, err := fixture.tokenSvc.GenerateRefreshToken(context.Background(), "account-001", clientID, session.ID, scopes, nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	// Call RefreshTokens
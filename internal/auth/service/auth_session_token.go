@@ -54,7 +54,7 @@ func (s *AuthService) createSessionAndTokens(ctx context.Context, account *accou
 		return nil, "", nil, fmt.Errorf("generate access token: %w", err)
 	}
 
-	refreshToken, err := s.tokenSvc.GenerateRefreshToken(ctx, account.ID, "", session.ID, "")
+	refreshToken, err := s.tokenSvc.GenerateRefreshToken(ctx, account.ID, "", session.ID, "", nil, nil, nil, nil, nil)
 	if err != nil {
 		return nil, "", nil, fmt.Errorf("generate refresh token: %w", err)
 	}
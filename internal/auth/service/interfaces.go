@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	accountDomain "github.com/rushairer/gosso/internal/account/domain"
 	sessionDomain "github.com/rushairer/gosso/internal/session/domain"
 	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	tokenService "github.com/rushairer/gosso/internal/token/service"
 )
 
 // AuthOrchestrator defines the interface used by controllers for authentication operations.
@@ -35,13 +37,18 @@ type AuthOrchestrator interface {
 // TokenManager defines the interface used by controllers and middleware for token operations.
 type TokenManager interface {
 	GenerateAccessToken(claims *tokenDomain.AccessTokenClaims) (string, error)
-	GenerateRefreshToken(ctx context.Context, accountID, clientID, sessionID, scope string) (*tokenDomain.RefreshToken, error)
+	GenerateAccessTokenWithLifetime(claims *tokenDomain.AccessTokenClaims, lifetime time.Duration) (string, error)
+	GenerateResourceAccessToken(claims *tokenDomain.AccessTokenClaims, lifetime time.Duration) (string, error)
+	GenerateReferenceAccessToken(ctx context.Context, claims *tokenDomain.AccessTokenClaims, lifetime time.Duration) (string, error)
+	GenerateRefreshToken(ctx context.Context, accountID, clientID, sessionID, scope string, authorizationDetails json.RawMessage, resources, userInfoClaims []string, cnf *tokenDomain.ConfirmationClaim, policy *tokenDomain.RefreshTokenPolicy) (*tokenDomain.RefreshToken, error)
 	ValidateAccessTokenWithContext(ctx context.Context, tokenString string) (*tokenDomain.AccessTokenClaims, error)
+	ParseAccessToken(ctx context.Context, tokenString string) (*tokenDomain.AccessTokenClaims, error)
 	ValidateRefreshToken(ctx context.Context, token string) (*tokenDomain.RefreshToken, error)
 	RotateRefreshToken(ctx context.Context, oldToken string) (*tokenDomain.RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, token string) error
 	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
-	IntrospectToken(ctx context.Context, tokenString string) (map[string]any, error)
+	IntrospectToken(ctx context.Context, tokenString, tokenTypeHint string) (map[string]any, error)
+	ExchangeToken(ctx context.Context, req *tokenService.TokenExchangeRequest) (string, time.Time, error)
 	AccessExpiry() time.Duration
 	RefreshExpiry() time.Duration
 }
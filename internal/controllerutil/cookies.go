@@ -0,0 +1,50 @@
+package controllerutil
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	sessionService "github.com/rushairer/gosso/internal/session/service"
+)
+
+// IsSecureRequest reports whether the request arrived over TLS, directly or through a
+// proxy that set X-Forwarded-Proto.
+func IsSecureRequest(ctx *gin.Context) bool {
+	return ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+}
+
+// SetBrowserStateCookie records the browser state of a session for the check_session_iframe
+// (OpenID Connect Session Management 1.0). It is readable by script and sent in cross-site
+// frames, so it carries only a hash of the session ID. An empty sessionID sets nothing.
+func SetBrowserStateCookie(ctx *gin.Context, sessionID string, maxAgeSeconds int, secure bool) {
+	if sessionID == "" {
+		return
+	}
+	sameSite := http.SameSiteLaxMode
+	if secure {
+		sameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     sessionService.BrowserStateCookieName,
+		Value:    sessionService.BrowserState(sessionID),
+		Path:     "/",
+		MaxAge:   maxAgeSeconds,
+		Secure:   secure,
+		SameSite: sameSite,
+	})
+}
+
+// ClearBrowserStateCookie removes the browser state, so that the check_session_iframe
+// reports the session as changed to every relying party.
+func ClearBrowserStateCookie(ctx *gin.Context, secure bool) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:    sessionService.BrowserStateCookieName,
+		Value:   "",
+		Path:    "/",
+		MaxAge:  -1,
+		Expires: time.Unix(0, 0),
+		Secure:  secure,
+	})
+}
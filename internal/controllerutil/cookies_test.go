@@ -0,0 +1,72 @@
+package controllerutil
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sessionService "github.com/rushairer/gosso/internal/session/service"
+)
+
+func newCookieTestContext(header http.Header, tlsState *tls.ConnectionState) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	for k, v := range header {
+		ctx.Request.Header[k] = v
+	}
+	ctx.Request.TLS = tlsState
+	return ctx, w
+}
+
+func TestIsSecureRequest(t *testing.T) {
+	ctx, _ := newCookieTestContext(nil, nil)
+	assert.False(t, IsSecureRequest(ctx))
+
+	ctx, _ = newCookieTestContext(nil, &tls.ConnectionState{})
+	assert.True(t, IsSecureRequest(ctx))
+
+	ctx, _ = newCookieTestContext(http.Header{"X-Forwarded-Proto": {"https"}}, nil)
+	assert.True(t, IsSecureRequest(ctx))
+}
+
+func TestSetBrowserStateCookie(t *testing.T) {
+	ctx, w := newCookieTestContext(nil, nil)
+	SetBrowserStateCookie(ctx, "session-001", 3600, true)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, sessionService.BrowserStateCookieName, cookies[0].Name)
+	assert.Equal(t, sessionService.BrowserState("session-001"), cookies[0].Value)
+	assert.Equal(t, 3600, cookies[0].MaxAge)
+	assert.True(t, cookies[0].Secure)
+	assert.False(t, cookies[0].HttpOnly, "the check_session_iframe reads the cookie")
+	assert.Equal(t, http.SameSiteNoneMode, cookies[0].SameSite)
+
+	ctx, w = newCookieTestContext(nil, nil)
+	SetBrowserStateCookie(ctx, "session-001", 3600, false)
+	require.Len(t, w.Result().Cookies(), 1)
+	assert.Equal(t, http.SameSiteLaxMode, w.Result().Cookies()[0].SameSite)
+
+	ctx, w = newCookieTestContext(nil, nil)
+	SetBrowserStateCookie(ctx, "", 3600, true)
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestClearBrowserStateCookie(t *testing.T) {
+	ctx, w := newCookieTestContext(nil, nil)
+	ClearBrowserStateCookie(ctx, true)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, sessionService.BrowserStateCookieName, cookies[0].Name)
+	assert.Empty(t, cookies[0].Value)
+	assert.Negative(t, cookies[0].MaxAge)
+	assert.True(t, cookies[0].Secure)
+}
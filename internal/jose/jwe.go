@@ -0,0 +1,264 @@
+package jose
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1" // registers crypto.SHA1, which RSA-OAEP (RFC 7518 §4.3) is defined with
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Key management algorithms (RFC 7518 §4.3) supported for JWE.
+const (
+	KeyAlgRSAOAEP    = "RSA-OAEP"
+	KeyAlgRSAOAEP256 = "RSA-OAEP-256"
+)
+
+var (
+	// KeyEncryptionAlgs are the supported JWE "alg" values.
+	KeyEncryptionAlgs = []string{KeyAlgRSAOAEP, KeyAlgRSAOAEP256}
+	// ContentEncryptionAlgs are the supported JWE "enc" values (RFC 7518 §5).
+	ContentEncryptionAlgs = []string{"A128CBC-HS256", "A192CBC-HS384", "A256CBC-HS512", "A128GCM", "A192GCM", "A256GCM"}
+)
+
+// ErrJWEDecryption is returned for any JWE that cannot be decrypted. Failures are not
+// distinguished so the result cannot be used as a padding or key oracle.
+var ErrJWEDecryption = errors.New("jwe: decryption failed")
+
+// JWEHeader is the JOSE header of a compact serialized JWE (RFC 7516 §4).
+type JWEHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+	Cty string `json:"cty,omitempty"`
+	Zip string `json:"zip,omitempty"`
+}
+
+// IsJWE reports whether token has the five segments of a compact serialized JWE.
+func IsJWE(token string) bool {
+	return strings.Count(token, ".") == 4
+}
+
+// Encrypt encrypts plaintext to pub as a compact serialized JWE. header.Alg and
+// header.Enc select the algorithms; the remaining members are copied as is.
+func Encrypt(plaintext []byte, pub *rsa.PublicKey, header JWEHeader) (string, error) {
+	oaepHash, err := oaepHashFor(header.Alg)
+	if err != nil {
+		return "", err
+	}
+	keySize, err := contentKeySize(header.Enc)
+	if err != nil {
+		return "", err
+	}
+	cek := make([]byte, keySize)
+	if _, err := rand.Read(cek); err != nil {
+		return "", err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(oaepHash.New(), rand.Reader, pub, cek, nil)
+	if err != nil {
+		return "", fmt.Errorf("jwe: encrypt content key: %w", err)
+	}
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(rawHeader)
+	iv, ciphertext, tag, err := sealContent(header.Enc, cek, plaintext, []byte(protected))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		protected,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+// Decrypt decrypts a compact serialized JWE with key and returns the plaintext and header.
+// Compressed ("zip") payloads are rejected.
+func Decrypt(token string, key *rsa.PrivateKey) ([]byte, *JWEHeader, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, nil, errors.New("jwe: malformed compact serialization")
+	}
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, errors.New("jwe: malformed header")
+	}
+	var header JWEHeader
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, nil, errors.New("jwe: malformed header")
+	}
+	if header.Zip != "" {
+		return nil, nil, errors.New("jwe: compressed payloads are not supported")
+	}
+	oaepHash, err := oaepHashFor(header.Alg)
+	if err != nil {
+		return nil, nil, err
+	}
+	keySize, err := contentKeySize(header.Enc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var segments [4][]byte
+	for i := range segments {
+		if segments[i], err = base64.RawURLEncoding.DecodeString(parts[i+1]); err != nil {
+			return nil, nil, ErrJWEDecryption
+		}
+	}
+	cek, err := rsa.DecryptOAEP(oaepHash.New(), nil, key, segments[0], nil)
+	if err != nil || len(cek) != keySize {
+		return nil, nil, ErrJWEDecryption
+	}
+	plaintext, err := openContent(header.Enc, cek, segments[1], segments[2], segments[3], []byte(parts[0]))
+	if err != nil {
+		return nil, nil, ErrJWEDecryption
+	}
+	return plaintext, &header, nil
+}
+
+func oaepHashFor(alg string) (crypto.Hash, error) {
+	switch alg {
+	case KeyAlgRSAOAEP:
+		return crypto.SHA1, nil
+	case KeyAlgRSAOAEP256:
+		return crypto.SHA256, nil
+	default:
+		return 0, fmt.Errorf("jwe: unsupported alg %q", alg)
+	}
+}
+
+// contentKeySize returns the content encryption key length in bytes for enc.
+func contentKeySize(enc string) (int, error) {
+	switch enc {
+	case "A128GCM":
+		return 16, nil
+	case "A192GCM":
+		return 24, nil
+	case "A256GCM", "A128CBC-HS256":
+		return 32, nil
+	case "A192CBC-HS384":
+		return 48, nil
+	case "A256CBC-HS512":
+		return 64, nil
+	default:
+		return 0, fmt.Errorf("jwe: unsupported enc %q", enc)
+	}
+}
+
+func sealContent(enc string, cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	if strings.HasSuffix(enc, "GCM") {
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		iv = make([]byte, aead.NonceSize())
+		if _, err := rand.Read(iv); err != nil {
+			return nil, nil, nil, err
+		}
+		sealed := aead.Seal(nil, iv, plaintext, aad)
+		split := len(sealed) - aead.Overhead()
+		return iv, sealed[:split], sealed[split:], nil
+	}
+
+	// AES_CBC_HMAC_SHA2 (RFC 7518 §5.2): the first half of the key authenticates, the
+	// second half encrypts.
+	macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	iv = make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, nil, err
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext = append(append([]byte{}, plaintext...), make([]byte, padding)...)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+	return iv, ciphertext, cbcHMACTag(macKey, aad, iv, ciphertext), nil
+}
+
+func openContent(enc string, cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if strings.HasSuffix(enc, "GCM") {
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, err
+		}
+		if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+			return nil, ErrJWEDecryption
+		}
+		return aead.Open(nil, iv, append(append([]byte{}, ciphertext...), tag...), aad)
+	}
+
+	macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+	if subtle.ConstantTimeCompare(tag, cbcHMACTag(macKey, aad, iv, ciphertext)) != 1 {
+		return nil, ErrJWEDecryption
+	}
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrJWEDecryption
+	}
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrJWEDecryption
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, ErrJWEDecryption
+		}
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// cbcHMACTag computes the authentication tag of AES_CBC_HMAC_SHA2 (RFC 7518 §5.2.2.1).
+func cbcHMACTag(macKey, aad, iv, ciphertext []byte) []byte {
+	var newHash func() hash.Hash
+	switch len(macKey) {
+	case 16:
+		newHash = sha256.New
+	case 24:
+		newHash = sha512.New384
+	default:
+		newHash = sha512.New
+	}
+	mac := hmac.New(newHash, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	var al [8]byte
+	binary.BigEndian.PutUint64(al[:], uint64(len(aad))*8)
+	mac.Write(al[:])
+	return mac.Sum(nil)[:len(macKey)]
+}
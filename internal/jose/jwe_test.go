@@ -0,0 +1,89 @@
+package jose
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWE_RoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	plaintext := []byte(`{"iss":"client","scope":"openid"}`)
+
+	for _, alg := range KeyEncryptionAlgs {
+		for _, enc := range ContentEncryptionAlgs {
+			t.Run(alg+"/"+enc, func(t *testing.T) {
+				token, err := Encrypt(plaintext, &key.PublicKey, JWEHeader{Alg: alg, Enc: enc, Kid: "enc-1", Cty: "JWT"})
+				require.NoError(t, err)
+				assert.True(t, IsJWE(token))
+
+				got, header, err := Decrypt(token, key)
+				require.NoError(t, err)
+				assert.Equal(t, plaintext, got)
+				assert.Equal(t, JWEHeader{Alg: alg, Enc: enc, Kid: "enc-1", Cty: "JWT"}, *header)
+			})
+		}
+	}
+}
+
+func TestJWE_Tampered(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, enc := range []string{"A256GCM", "A128CBC-HS256"} {
+		token, err := Encrypt([]byte("payload"), &key.PublicKey, JWEHeader{Alg: KeyAlgRSAOAEP256, Enc: enc})
+		require.NoError(t, err)
+		parts := strings.Split(token, ".")
+
+		// Flipping a ciphertext bit must fail authentication.
+		ciphertext, err := base64.RawURLEncoding.DecodeString(parts[3])
+		require.NoError(t, err)
+		ciphertext[0] ^= 1
+		tampered := append([]string{}, parts...)
+		tampered[3] = base64.RawURLEncoding.EncodeToString(ciphertext)
+		_, _, err = Decrypt(strings.Join(tampered, "."), key)
+		assert.ErrorIs(t, err, ErrJWEDecryption, enc)
+
+		// The protected header is authenticated as additional data.
+		tampered = append([]string{}, parts...)
+		tampered[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RSA-OAEP-256","enc":"` + enc + `","kid":"x"}`))
+		_, _, err = Decrypt(strings.Join(tampered, "."), key)
+		assert.ErrorIs(t, err, ErrJWEDecryption, enc)
+	}
+}
+
+func TestJWE_WrongKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	token, err := Encrypt([]byte("payload"), &key.PublicKey, JWEHeader{Alg: KeyAlgRSAOAEP, Enc: "A128GCM"})
+	require.NoError(t, err)
+	_, _, err = Decrypt(token, other)
+	assert.ErrorIs(t, err, ErrJWEDecryption)
+}
+
+func TestJWE_Unsupported(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, err = Encrypt([]byte("payload"), &key.PublicKey, JWEHeader{Alg: "RSA1_5", Enc: "A128GCM"})
+	assert.Error(t, err)
+	_, err = Encrypt([]byte("payload"), &key.PublicKey, JWEHeader{Alg: KeyAlgRSAOAEP, Enc: "A512GCM"})
+	assert.Error(t, err)
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RSA-OAEP","enc":"A128GCM","zip":"DEF"}`))
+	_, _, err = Decrypt(header+".a.b.c.d", key)
+	assert.ErrorContains(t, err, "compressed")
+
+	_, _, err = Decrypt("a.b.c", key)
+	assert.Error(t, err)
+	assert.False(t, IsJWE("a.b.c"))
+}
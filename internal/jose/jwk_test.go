@@ -0,0 +1,102 @@
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWK_Thumbprint_RFC7638Example(t *testing.T) {
+	k := JWK{
+		Kty: "RSA",
+		Kid: "2011-04-29",
+		Alg: "RS256",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:   "AQAB",
+	}
+	jkt, err := k.Thumbprint()
+	require.NoError(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", jkt)
+
+	// Optional members do not change the thumbprint.
+	k.Kid, k.Alg, k.Use = "", "", "sig"
+	same, err := k.Thumbprint()
+	require.NoError(t, err)
+	assert.Equal(t, jkt, same)
+}
+
+func TestJWK_PublicKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	k := JWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+	}
+	pub, err := k.PublicKey()
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(pub))
+	_, err = k.Thumbprint()
+	assert.NoError(t, err)
+
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	k = JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(edPub)}
+	pub, err = k.PublicKey()
+	require.NoError(t, err)
+	assert.True(t, edPub.Equal(pub))
+}
+
+func TestNewJWK_RoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for _, pub := range []crypto.PublicKey{&rsaKey.PublicKey, &ecKey.PublicKey, edPub} {
+		k, err := NewJWK(pub)
+		require.NoError(t, err)
+		got, err := k.PublicKey()
+		require.NoError(t, err)
+		assert.True(t, pub.(interface{ Equal(crypto.PublicKey) bool }).Equal(got), "%s key", k.Kty)
+	}
+
+	_, err = NewJWK("not a key")
+	assert.Error(t, err)
+}
+
+func TestJWK_Invalid(t *testing.T) {
+	zero := base64.RawURLEncoding.EncodeToString(make([]byte, 32))
+	tests := []struct {
+		name string
+		key  JWK
+	}{
+		{"unknown kty", JWK{Kty: "oct"}},
+		{"EC point not on curve", JWK{Kty: "EC", Crv: "P-256", X: zero, Y: zero}},
+		{"unsupported curve", JWK{Kty: "EC", Crv: "secp256k1", X: zero, Y: zero}},
+		{"short RSA key", JWK{Kty: "RSA", N: "AQAB", E: "AQAB"}},
+		{"OKP wrong curve", JWK{Kty: "OKP", Crv: "X25519", X: zero}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.key.PublicKey()
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := (&JWK{Kty: "EC", Crv: "P-256", X: zero}).Thumbprint()
+	assert.Error(t, err)
+	_, err = (&JWK{Kty: "oct"}).Thumbprint()
+	assert.Error(t, err)
+}
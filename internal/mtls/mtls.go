@@ -0,0 +1,77 @@
+// Package mtls carries the TLS client certificate of a request (RFC 8705) from the edge
+// of the server, where it is read from the connection or a trusted proxy header, to the
+// OAuth 2.0 and token packages.
+package mtls
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type contextKey struct{}
+
+// WithCertificate returns a copy of ctx carrying the client certificate of the request.
+func WithCertificate(ctx context.Context, cert *x509.Certificate) context.Context {
+	return context.WithValue(ctx, contextKey{}, cert)
+}
+
+// CertificateFromContext returns the client certificate stored by WithCertificate, or nil
+// if the request did not present one.
+func CertificateFromContext(ctx context.Context) *x509.Certificate {
+	cert, _ := ctx.Value(contextKey{}).(*x509.Certificate)
+	return cert
+}
+
+// Thumbprint returns the x5t#S256 value of cert: the base64url-encoded SHA-256 hash of its
+// DER encoding (RFC 8705 §3.1). It returns "" for a nil certificate.
+func Thumbprint(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ParseCertificateHeader decodes a client certificate forwarded by a TLS-terminating
+// proxy. It accepts a PEM certificate, optionally URL-encoded (nginx
+// $ssl_client_escaped_cert, AWS ALB), or the base64 DER body of one (Traefik, HAProxy).
+func ParseCertificateHeader(value string) (*x509.Certificate, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, errors.New("empty client certificate header")
+	}
+	if strings.Contains(value, "%") {
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("unescape client certificate: %w", err)
+		}
+		value = unescaped
+	}
+
+	var der []byte
+	if strings.HasPrefix(value, "-----BEGIN") {
+		block, _ := pem.Decode([]byte(value))
+		if block == nil || block.Type != "CERTIFICATE" {
+			return nil, errors.New("client certificate is not a PEM certificate")
+		}
+		der = block.Bytes
+	} else {
+		var err error
+		der, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+		if err != nil {
+			return nil, fmt.Errorf("decode client certificate: %w", err)
+		}
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse client certificate: %w", err)
+	}
+	return cert, nil
+}
@@ -0,0 +1,65 @@
+package mtls
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/testutil"
+)
+
+func TestCertificateContext(t *testing.T) {
+	assert.Nil(t, CertificateFromContext(context.Background()))
+
+	cert, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client.example.com"})
+	assert.Same(t, cert, CertificateFromContext(WithCertificate(context.Background(), cert)))
+}
+
+func TestThumbprint(t *testing.T) {
+	assert.Empty(t, Thumbprint(nil))
+
+	cert, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client.example.com"})
+	sum := sha256.Sum256(cert.Raw)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), Thumbprint(cert))
+}
+
+func TestParseCertificateHeader(t *testing.T) {
+	cert, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client.example.com"})
+	pemCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"pem", pemCert},
+		{"url-encoded pem", url.QueryEscape(pemCert)},
+		{"base64 der", base64.StdEncoding.EncodeToString(cert.Raw)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseCertificateHeader(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, cert.Raw, parsed.Raw)
+		})
+	}
+}
+
+func TestParseCertificateHeader_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"not-base64!",
+		base64.StdEncoding.EncodeToString([]byte("not a certificate")),
+		"-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----\n",
+		"%zz",
+	} {
+		_, err := ParseCertificateHeader(value)
+		assert.Error(t, err, value)
+	}
+}
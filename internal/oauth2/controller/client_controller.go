@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -49,13 +50,46 @@ func (c *ClientController) RegisterRoutes(rg *gin.RouterGroup, authMiddleware gi
 
 // RegisterClientRequest is the request body for registering a client
 type RegisterClientRequest struct {
-	Name                   string   `json:"name" binding:"required,max=255"`
-	Description            string   `json:"description" binding:"max=2000"`
-	RedirectURIs           []string `json:"redirect_uris" binding:"required,min=1"`
-	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris"`
-	GrantTypes             []string `json:"grant_types"`
-	Scopes                 []string `json:"scopes"`
-	IsConfidential         bool     `json:"is_confidential"`
+	Name                                  string          `json:"name" binding:"required,max=255"`
+	Description                           string          `json:"description" binding:"max=2000"`
+	RedirectURIs                          []string        `json:"redirect_uris" binding:"required,min=1"`
+	PostLogoutRedirectURIs                []string        `json:"post_logout_redirect_uris"`
+	GrantTypes                            []string        `json:"grant_types"`
+	Scopes                                []string        `json:"scopes"`
+	IsConfidential                        bool            `json:"is_confidential"`
+	RequirePushedAuthorizationRequests    bool            `json:"require_pushed_authorization_requests"`
+	TokenEndpointAuthMethod               string          `json:"token_endpoint_auth_method"`
+	JWKS                                  json.RawMessage `json:"jwks"`
+	JWKSURI                               string          `json:"jwks_uri"`
+	TokenExchangeAudiences                []string        `json:"token_exchange_audiences"`
+	DPoPBoundAccessTokens                 bool            `json:"dpop_bound_access_tokens"`
+	TLSClientAuthSubjectDN                string          `json:"tls_client_auth_subject_dn"`
+	TLSClientAuthSANDNS                   string          `json:"tls_client_auth_san_dns"`
+	TLSClientAuthSANURI                   string          `json:"tls_client_auth_san_uri"`
+	TLSClientAuthSANIP                    string          `json:"tls_client_auth_san_ip"`
+	TLSClientAuthSANEmail                 string          `json:"tls_client_auth_san_email"`
+	TLSClientCertificateBoundAccessTokens bool            `json:"tls_client_certificate_bound_access_tokens"`
+	RequireSignedRequestObject            bool            `json:"require_signed_request_object"`
+	RequestURIs                           []string        `json:"request_uris"`
+	AuthorizationDetailsTypes             []string        `json:"authorization_details_types"`
+	BackchannelTokenDeliveryMode          string          `json:"backchannel_token_delivery_mode"`
+	BackchannelClientNotificationEndpoint string          `json:"backchannel_client_notification_endpoint"`
+	SubjectType                           string          `json:"subject_type"`
+	SectorIdentifierURI                   string          `json:"sector_identifier_uri"`
+	IDTokenSignedResponseAlg              string          `json:"id_token_signed_response_alg"`
+	AccessTokenProfile                    string          `json:"access_token_profile"`
+	IDTokenEncryptedResponseAlg           string          `json:"id_token_encrypted_response_alg"`
+	IDTokenEncryptedResponseEnc           string          `json:"id_token_encrypted_response_enc"`
+	UserinfoEncryptedResponseAlg          string          `json:"userinfo_encrypted_response_alg"`
+	UserinfoEncryptedResponseEnc          string          `json:"userinfo_encrypted_response_enc"`
+	UserinfoSignedResponseAlg             string          `json:"userinfo_signed_response_alg"`
+	RefreshTokenIdleLifetime              int             `json:"refresh_token_idle_lifetime"`
+	RefreshTokenAbsoluteLifetime          int             `json:"refresh_token_absolute_lifetime"`
+	RefreshTokenExpiration                string          `json:"refresh_token_expiration"`
+	AccessTokenLifetime                   int             `json:"access_token_lifetime"`
+	IDTokenLifetime                       int             `json:"id_token_lifetime"`
+	AuthorizationCodeLifetime             int             `json:"authorization_code_lifetime"`
+	DeviceCodeLifetime                    int             `json:"device_code_lifetime"`
 }
 
 // RegisterClientResponse is the response body for registering a client
@@ -79,15 +113,49 @@ func (c *ClientController) RegisterClient(ctx *gin.Context) {
 	}
 
 	client, secret, err := c.clientSvc.RegisterClient(ctx, &oauth2Service.RegisterClientRequest{
-		AccountID:              accountID,
-		Name:                   req.Name,
-		Description:            req.Description,
-		RedirectURIs:           req.RedirectURIs,
-		PostLogoutRedirectURIs: req.PostLogoutRedirectURIs,
-		GrantTypes:             req.GrantTypes,
-		Scopes:                 req.Scopes,
-		IsConfidential:         req.IsConfidential,
-		AllowReservedScopes:    canManageReservedClientScopes(ctx),
+		AccountID:                             accountID,
+		Name:                                  req.Name,
+		Description:                           req.Description,
+		RedirectURIs:                          req.RedirectURIs,
+		PostLogoutRedirectURIs:                req.PostLogoutRedirectURIs,
+		GrantTypes:                            req.GrantTypes,
+		Scopes:                                req.Scopes,
+		IsConfidential:                        req.IsConfidential,
+		RequirePushedAuthorizationRequests:    req.RequirePushedAuthorizationRequests,
+		TokenEndpointAuthMethod:               req.TokenEndpointAuthMethod,
+		JWKS:                                  req.JWKS,
+		JWKSURI:                               req.JWKSURI,
+		TokenExchangeAudiences:                req.TokenExchangeAudiences,
+		DPoPBoundAccessTokens:                 req.DPoPBoundAccessTokens,
+		TLSClientAuthSubjectDN:                req.TLSClientAuthSubjectDN,
+		TLSClientAuthSANDNS:                   req.TLSClientAuthSANDNS,
+		TLSClientAuthSANURI:                   req.TLSClientAuthSANURI,
+		TLSClientAuthSANIP:                    req.TLSClientAuthSANIP,
+		TLSClientAuthSANEmail:                 req.TLSClientAuthSANEmail,
+		TLSClientCertificateBoundAccessTokens: req.TLSClientCertificateBoundAccessTokens,
+		RequireSignedRequestObject:            req.RequireSignedRequestObject,
+		RequestURIs:                           req.RequestURIs,
+		AuthorizationDetailsTypes:             req.AuthorizationDetailsTypes,
+		BackchannelTokenDeliveryMode:          req.BackchannelTokenDeliveryMode,
+		BackchannelClientNotificationEndpoint: req.BackchannelClientNotificationEndpoint,
+		SubjectType:                           req.SubjectType,
+		SectorIdentifierURI:                   req.SectorIdentifierURI,
+		IDTokenSignedResponseAlg:              req.IDTokenSignedResponseAlg,
+		AccessTokenProfile:                    req.AccessTokenProfile,
+		IDTokenEncryptedResponseAlg:           req.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:           req.IDTokenEncryptedResponseEnc,
+		UserinfoEncryptedResponseAlg:          req.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc:          req.UserinfoEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:             req.UserinfoSignedResponseAlg,
+		RefreshTokenIdleLifetime:              req.RefreshTokenIdleLifetime,
+		RefreshTokenAbsoluteLifetime:          req.RefreshTokenAbsoluteLifetime,
+		RefreshTokenExpiration:                req.RefreshTokenExpiration,
+		AccessTokenLifetime:                   req.AccessTokenLifetime,
+		IDTokenLifetime:                       req.IDTokenLifetime,
+		AuthorizationCodeLifetime:             req.AuthorizationCodeLifetime,
+		DeviceCodeLifetime:                    req.DeviceCodeLifetime,
+		AllowReservedScopes:                   canManageReservedClientScopes(ctx),
+		AllowTokenExchangePolicy:              canManageReservedClientScopes(ctx),
 	})
 	if err != nil {
 		if isValidationError(err) {
@@ -173,13 +241,47 @@ func (c *ClientController) UpdateClient(ctx *gin.Context) {
 	}
 
 	svcReq := &oauth2Service.UpdateClientRequest{
-		Name:                   req.Name,
-		Description:            req.Description,
-		RedirectURIs:           req.RedirectURIs,
-		PostLogoutRedirectURIs: req.PostLogoutRedirectURIs,
-		GrantTypes:             req.GrantTypes,
-		Scopes:                 req.Scopes,
-		AllowReservedScopes:    canManageReservedClientScopes(ctx),
+		Name:                                  req.Name,
+		Description:                           req.Description,
+		RedirectURIs:                          req.RedirectURIs,
+		PostLogoutRedirectURIs:                req.PostLogoutRedirectURIs,
+		GrantTypes:                            req.GrantTypes,
+		Scopes:                                req.Scopes,
+		RequirePushedAuthorizationRequests:    req.RequirePushedAuthorizationRequests,
+		TokenEndpointAuthMethod:               req.TokenEndpointAuthMethod,
+		JWKS:                                  req.JWKS,
+		JWKSURI:                               req.JWKSURI,
+		TokenExchangeAudiences:                req.TokenExchangeAudiences,
+		DPoPBoundAccessTokens:                 req.DPoPBoundAccessTokens,
+		TLSClientAuthSubjectDN:                req.TLSClientAuthSubjectDN,
+		TLSClientAuthSANDNS:                   req.TLSClientAuthSANDNS,
+		TLSClientAuthSANURI:                   req.TLSClientAuthSANURI,
+		TLSClientAuthSANIP:                    req.TLSClientAuthSANIP,
+		TLSClientAuthSANEmail:                 req.TLSClientAuthSANEmail,
+		TLSClientCertificateBoundAccessTokens: req.TLSClientCertificateBoundAccessTokens,
+		RequireSignedRequestObject:            req.RequireSignedRequestObject,
+		RequestURIs:                           req.RequestURIs,
+		AuthorizationDetailsTypes:             req.AuthorizationDetailsTypes,
+		BackchannelTokenDeliveryMode:          req.BackchannelTokenDeliveryMode,
+		BackchannelClientNotificationEndpoint: req.BackchannelClientNotificationEndpoint,
+		SubjectType:                           req.SubjectType,
+		SectorIdentifierURI:                   req.SectorIdentifierURI,
+		IDTokenSignedResponseAlg:              req.IDTokenSignedResponseAlg,
+		AccessTokenProfile:                    req.AccessTokenProfile,
+		IDTokenEncryptedResponseAlg:           req.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:           req.IDTokenEncryptedResponseEnc,
+		UserinfoEncryptedResponseAlg:          req.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc:          req.UserinfoEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:             req.UserinfoSignedResponseAlg,
+		RefreshTokenIdleLifetime:              req.RefreshTokenIdleLifetime,
+		RefreshTokenAbsoluteLifetime:          req.RefreshTokenAbsoluteLifetime,
+		RefreshTokenExpiration:                req.RefreshTokenExpiration,
+		AccessTokenLifetime:                   req.AccessTokenLifetime,
+		IDTokenLifetime:                       req.IDTokenLifetime,
+		AuthorizationCodeLifetime:             req.AuthorizationCodeLifetime,
+		DeviceCodeLifetime:                    req.DeviceCodeLifetime,
+		AllowReservedScopes:                   canManageReservedClientScopes(ctx),
+		AllowTokenExchangePolicy:              canManageReservedClientScopes(ctx),
 	}
 
 	client, err := c.clientSvc.UpdateClientByAccountID(ctx, accountID, clientID, svcReq)
@@ -198,12 +300,45 @@ func (c *ClientController) UpdateClient(ctx *gin.Context) {
 
 // UpdateClientRequest is the request body for updating a client
 type UpdateClientRequest struct {
-	Name                   *string  `json:"name"`
-	Description            *string  `json:"description"`
-	RedirectURIs           []string `json:"redirect_uris"`
-	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris"`
-	GrantTypes             []string `json:"grant_types"`
-	Scopes                 []string `json:"scopes"`
+	Name                                  *string         `json:"name"`
+	Description                           *string         `json:"description"`
+	RedirectURIs                          []string        `json:"redirect_uris"`
+	PostLogoutRedirectURIs                []string        `json:"post_logout_redirect_uris"`
+	GrantTypes                            []string        `json:"grant_types"`
+	Scopes                                []string        `json:"scopes"`
+	RequirePushedAuthorizationRequests    *bool           `json:"require_pushed_authorization_requests"`
+	TokenEndpointAuthMethod               *string         `json:"token_endpoint_auth_method"`
+	JWKS                                  json.RawMessage `json:"jwks"`
+	JWKSURI                               *string         `json:"jwks_uri"`
+	TokenExchangeAudiences                []string        `json:"token_exchange_audiences"`
+	DPoPBoundAccessTokens                 *bool           `json:"dpop_bound_access_tokens"`
+	TLSClientAuthSubjectDN                *string         `json:"tls_client_auth_subject_dn"`
+	TLSClientAuthSANDNS                   *string         `json:"tls_client_auth_san_dns"`
+	TLSClientAuthSANURI                   *string         `json:"tls_client_auth_san_uri"`
+	TLSClientAuthSANIP                    *string         `json:"tls_client_auth_san_ip"`
+	TLSClientAuthSANEmail                 *string         `json:"tls_client_auth_san_email"`
+	TLSClientCertificateBoundAccessTokens *bool           `json:"tls_client_certificate_bound_access_tokens"`
+	RequireSignedRequestObject            *bool           `json:"require_signed_request_object"`
+	RequestURIs                           []string        `json:"request_uris"`
+	AuthorizationDetailsTypes             []string        `json:"authorization_details_types"`
+	BackchannelTokenDeliveryMode          *string         `json:"backchannel_token_delivery_mode"`
+	BackchannelClientNotificationEndpoint *string         `json:"backchannel_client_notification_endpoint"`
+	SubjectType                           *string         `json:"subject_type"`
+	SectorIdentifierURI                   *string         `json:"sector_identifier_uri"`
+	IDTokenSignedResponseAlg              *string         `json:"id_token_signed_response_alg"`
+	AccessTokenProfile                    *string         `json:"access_token_profile"`
+	IDTokenEncryptedResponseAlg           *string         `json:"id_token_encrypted_response_alg"`
+	IDTokenEncryptedResponseEnc           *string         `json:"id_token_encrypted_response_enc"`
+	UserinfoEncryptedResponseAlg          *string         `json:"userinfo_encrypted_response_alg"`
+	UserinfoEncryptedResponseEnc          *string         `json:"userinfo_encrypted_response_enc"`
+	UserinfoSignedResponseAlg             *string         `json:"userinfo_signed_response_alg"`
+	RefreshTokenIdleLifetime              *int            `json:"refresh_token_idle_lifetime"`
+	RefreshTokenAbsoluteLifetime          *int            `json:"refresh_token_absolute_lifetime"`
+	RefreshTokenExpiration                *string         `json:"refresh_token_expiration"`
+	AccessTokenLifetime                   *int            `json:"access_token_lifetime"`
+	IDTokenLifetime                       *int            `json:"id_token_lifetime"`
+	AuthorizationCodeLifetime             *int            `json:"authorization_code_lifetime"`
+	DeviceCodeLifetime                    *int            `json:"device_code_lifetime"`
 }
 
 // DeleteClient DELETE /api/oauth2/clients/:client_id
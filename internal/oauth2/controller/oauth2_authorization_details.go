@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"encoding/json"
+	"slices"
+
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// consentDetail is an authorization detail (RFC 9396) as shown on the consent page.
+type consentDetail struct {
+	Type   string
+	Fields []consentDetailField
+}
+
+// consentDetailField is a member of an authorization detail other than type. Strings are
+// shown as is, other values as JSON.
+type consentDetailField struct {
+	Name  string
+	Value string
+}
+
+// consentDetails converts details to their consent page form, with members sorted by name
+// so the page does not depend on map order.
+func consentDetails(details oauth2Domain.AuthorizationDetails) []consentDetail {
+	result := make([]consentDetail, 0, len(details))
+	for _, detail := range details {
+		names := make([]string, 0, len(detail))
+		for name := range detail {
+			if name != "type" {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+
+		view := consentDetail{Type: detail.Type()}
+		for _, name := range names {
+			value, ok := detail[name].(string)
+			if !ok {
+				data, err := json.Marshal(detail[name])
+				if err != nil {
+					continue
+				}
+				value = string(data)
+			}
+			view.Fields = append(view.Fields, consentDetailField{Name: name, Value: value})
+		}
+		result = append(result, view)
+	}
+	return result
+}
+
+// grantedAuthorizationDetails returns the authorization details for a token issued from a
+// grant of granted (RFC 9396 §6.1). A requested value narrows the grant and must be covered
+// by it; without one the full grant is used.
+func grantedAuthorizationDetails(granted oauth2Domain.AuthorizationDetails, requested string) (oauth2Domain.AuthorizationDetails, bool) {
+	if requested == "" {
+		return granted, true
+	}
+	details, err := oauth2Domain.ParseAuthorizationDetails([]byte(requested))
+	if err != nil || !granted.Covers(details) {
+		return nil, false
+	}
+	return details, true
+}
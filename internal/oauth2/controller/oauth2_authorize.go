@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"math"
 	"mime"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 
 	"github.com/rushairer/gosso/internal/cache"
 	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	sessionDomain "github.com/rushairer/gosso/internal/session/domain"
 	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
 	"github.com/rushairer/gosso/internal/utility"
 	"github.com/rushairer/gosso/middleware"
@@ -36,6 +40,20 @@ func sessionIDFromContext(ctx *gin.Context) string {
 	return claims.SessionID
 }
 
+// sessionAuthTime returns when the current session was established, or zero if the
+// request was not authenticated with a validated session.
+func sessionAuthTime(ctx *gin.Context) time.Time {
+	raw, ok := ctx.Get(middleware.ContextKeySession)
+	if !ok {
+		return time.Time{}
+	}
+	session, ok := raw.(*sessionDomain.Session)
+	if !ok {
+		return time.Time{}
+	}
+	return session.CreatedAt
+}
+
 // consentState stores the PKCE and authorization parameters from the GET /authorize request.
 // It is persisted in Redis to prevent tampering between the consent page render and the POST.
 type consentState struct {
@@ -43,44 +61,261 @@ type consentState struct {
 	SessionID           string `json:"session_id"`
 	ClientID            string `json:"client_id"`
 	RedirectURI         string `json:"redirect_uri"`
+	ResponseMode        string `json:"response_mode,omitempty"`
 	CodeChallenge       string `json:"code_challenge"`
 	CodeChallengeMethod string `json:"code_challenge_method"`
 	Nonce               string `json:"nonce"`
 	State               string `json:"state"`
 	Scope               string `json:"scope"`
+	// AuthorizationDetails is the raw authorization_details parameter (RFC 9396).
+	AuthorizationDetails string `json:"authorization_details,omitempty"`
+	// Resources are the resource parameters (RFC 8707). They are not echoed by the consent
+	// form; the stored values are used.
+	Resources []string `json:"resources,omitempty"`
+	// Claims is the raw claims parameter (OIDC Core §5.5). Like Resources it is not echoed
+	// by the consent form.
+	Claims string `json:"claims,omitempty"`
 }
 
-// Authorize GET /oauth2/authorize
-func (c *OAuth2Controller) Authorize(ctx *gin.Context) {
-	clientID := ctx.Query("client_id")
-	redirectURI := ctx.Query("redirect_uri")
-	responseType := ctx.Query("response_type")
-	scope := strings.ReplaceAll(ctx.Query("scope"), "+", " ")
-	state := ctx.Query("state")
-	codeChallenge := ctx.Query("code_challenge")
-	codeChallengeMethod := ctx.Query("code_challenge_method")
-	nonce := ctx.Query("nonce")
+// authorizeParams holds the authorization request parameters, either read from the
+// /authorize query string or restored from a pushed authorization request (RFC 9126),
+// possibly overridden by a request object (RFC 9101).
+type authorizeParams struct {
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	ResponseType        string `json:"response_type"`
+	ResponseMode        string `json:"response_mode,omitempty"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Nonce               string `json:"nonce"`
+	Prompt              string `json:"prompt,omitempty"`
+	MaxAge              string `json:"max_age,omitempty"`
+	LoginHint           string `json:"login_hint,omitempty"`
+	IDTokenHint         string `json:"id_token_hint,omitempty"`
+	// AuthorizationDetails is the raw authorization_details parameter (RFC 9396 §2).
+	AuthorizationDetails string `json:"authorization_details,omitempty"`
+	// Resource lists the resource parameters (RFC 8707 §2), which may be repeated.
+	Resource []string `json:"resource,omitempty"`
+	// Claims is the raw claims parameter (OIDC Core §5.5.1).
+	Claims string `json:"claims,omitempty"`
+	// RequestObject reports that the parameters came from a verified request object
+	// (RFC 9101). It is never read from or written to a query string.
+	RequestObject bool `json:"request_object,omitempty"`
+}
 
-	if codeChallenge != "" && codeChallengeMethod != "S256" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "code_challenge_method must be S256"})
-		return
+// authorizeParamsFromValues extracts authorization request parameters from query or form values.
+func authorizeParamsFromValues(v url.Values) authorizeParams {
+	return authorizeParams{
+		ClientID:             v.Get("client_id"),
+		RedirectURI:          v.Get("redirect_uri"),
+		ResponseType:         v.Get("response_type"),
+		ResponseMode:         v.Get("response_mode"),
+		Scope:                strings.ReplaceAll(v.Get("scope"), "+", " "),
+		State:                v.Get("state"),
+		CodeChallenge:        v.Get("code_challenge"),
+		CodeChallengeMethod:  v.Get("code_challenge_method"),
+		Nonce:                v.Get("nonce"),
+		Prompt:               strings.ReplaceAll(v.Get("prompt"), "+", " "),
+		MaxAge:               v.Get("max_age"),
+		LoginHint:            v.Get("login_hint"),
+		IDTokenHint:          v.Get("id_token_hint"),
+		AuthorizationDetails: v.Get("authorization_details"),
+		Resource:             v["resource"],
+		Claims:               v.Get("claims"),
 	}
-	if codeChallengeMethod != "" && codeChallenge == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "code_challenge is required when code_challenge_method is specified"})
-		return
+}
+
+// values encodes the non-empty parameters as a query string for /oauth2/authorize.
+func (p *authorizeParams) values() url.Values {
+	v := url.Values{}
+	for name, value := range map[string]string{
+		"client_id":             p.ClientID,
+		"redirect_uri":          p.RedirectURI,
+		"response_type":         p.ResponseType,
+		"response_mode":         p.ResponseMode,
+		"scope":                 p.Scope,
+		"state":                 p.State,
+		"code_challenge":        p.CodeChallenge,
+		"code_challenge_method": p.CodeChallengeMethod,
+		"nonce":                 p.Nonce,
+		"prompt":                p.Prompt,
+		"max_age":               p.MaxAge,
+		"login_hint":            p.LoginHint,
+		"id_token_hint":         p.IDTokenHint,
+		"authorization_details": p.AuthorizationDetails,
+		"claims":                p.Claims,
+	} {
+		if value != "" {
+			v.Set(name, value)
+		}
 	}
+	for _, resource := range p.Resource {
+		v.Add("resource", resource)
+	}
+	return v
+}
 
-	if responseType != "code" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_response_type"})
-		return
+// authorizePrompt is the parsed OIDC prompt parameter (OIDC Core §3.1.2.1).
+type authorizePrompt struct {
+	none          bool
+	login         bool
+	consent       bool
+	selectAccount bool
+}
+
+// parsePrompt parses a space-delimited prompt value. prompt=none must not be
+// combined with any other value.
+func parsePrompt(raw string) (authorizePrompt, bool) {
+	var p authorizePrompt
+	values := strings.Fields(raw)
+	for _, v := range values {
+		switch v {
+		case "none":
+			p.none = true
+		case "login":
+			p.login = true
+		case "consent":
+			p.consent = true
+		case "select_account":
+			p.selectAccount = true
+		default:
+			return authorizePrompt{}, false
+		}
+	}
+	if p.none && len(values) > 1 {
+		return authorizePrompt{}, false
+	}
+	return p, true
+}
+
+// parseMaxAge parses the max_age parameter in seconds. The second return value
+// reports whether max_age was present.
+func parseMaxAge(raw string) (time.Duration, bool, error) {
+	if raw == "" {
+		return 0, false, nil
+	}
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || seconds < 0 || seconds > math.MaxInt64/int64(time.Second) {
+		return 0, false, errors.New("invalid max_age")
+	}
+	return time.Duration(seconds) * time.Second, true, nil
+}
+
+// authorizeParamError is an authorization request validation failure reported as a 400 response.
+type authorizeParamError struct {
+	Code        string
+	Description string
+}
+
+// writeAuthorizeParamError writes e as an OAuth2 error response.
+func writeAuthorizeParamError(ctx *gin.Context, e *authorizeParamError) {
+	body := gin.H{"error": e.Code}
+	if e.Description != "" {
+		body["error_description"] = e.Description
+	}
+	ctx.JSON(http.StatusBadRequest, body)
+}
+
+// validate checks the client-independent authorization request parameters.
+func (p *authorizeParams) validate() *authorizeParamError {
+	if p.CodeChallenge != "" && p.CodeChallengeMethod != "S256" {
+		return &authorizeParamError{"invalid_request", "code_challenge_method must be S256"}
+	}
+	if p.CodeChallengeMethod != "" && p.CodeChallenge == "" {
+		return &authorizeParamError{"invalid_request", "code_challenge is required when code_challenge_method is specified"}
+	}
+
+	if p.ResponseType != "code" {
+		return &authorizeParamError{Code: "unsupported_response_type"}
 	}
 
 	// OIDC Core Section 3.1.2.1: reject unsupported response_mode values.
-	if responseMode := ctx.Query("response_mode"); responseMode != "" && responseMode != "query" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "unsupported response_mode, only 'query' is supported"})
+	if p.ResponseMode != "" && !slices.Contains(supportedResponseModes, p.ResponseMode) {
+		return &authorizeParamError{"invalid_request", "unsupported response_mode"}
+	}
+
+	if _, ok := parsePrompt(p.Prompt); !ok {
+		return &authorizeParamError{"invalid_request", "invalid prompt value"}
+	}
+	if _, _, err := parseMaxAge(p.MaxAge); err != nil {
+		return &authorizeParamError{"invalid_request", "max_age must be a non-negative integer"}
+	}
+	if _, err := oauth2Domain.ParseAuthorizationDetails([]byte(p.AuthorizationDetails)); err != nil {
+		return &authorizeParamError{"invalid_authorization_details", err.Error()}
+	}
+	if _, err := oauth2Domain.ParseClaimsRequest([]byte(p.Claims)); err != nil {
+		return &authorizeParamError{"invalid_request", err.Error()}
+	}
+	if len(p.Resource) > maxResourceIndicators {
+		return &authorizeParamError{"invalid_target", "too many resource parameters"}
+	}
+	for _, resource := range p.Resource {
+		if !oauth2Domain.IsValidResourceIndicator(resource) {
+			return &authorizeParamError{"invalid_target", "resource must be an absolute URI without fragment"}
+		}
+	}
+	return nil
+}
+
+// validateAuthorizeParamsForClient checks the authorization request parameters against the client registration.
+func (c *OAuth2Controller) validateAuthorizeParamsForClient(p *authorizeParams, client *oauth2Domain.OAuth2Client) *authorizeParamError {
+	if !client.ValidateRedirectURI(p.RedirectURI) {
+		return &authorizeParamError{Code: "invalid_redirect_uri"}
+	}
+
+	// State parameter is required for public clients (RFC 6749 Section 10.12)
+	if !client.IsConfidential && p.State == "" {
+		return &authorizeParamError{"invalid_request", "state parameter is required for public clients"}
+	}
+
+	// PKCE is mandatory for public clients (RFC 7636 Section 4.1)
+	if !client.IsConfidential && p.CodeChallenge == "" {
+		return &authorizeParamError{"invalid_request", "code_challenge is required for public clients"}
+	}
+
+	// PKCE enforcement for confidential clients (configurable via auth.enforce_pkce_for_confidential)
+	if client.IsConfidential && c.enforcePKCEForConfidential && p.CodeChallenge == "" {
+		return &authorizeParamError{"invalid_request", "code_challenge is required for all clients"}
+	}
+
+	// RFC 9396 §5: only authorization details types registered for the client are accepted.
+	details, _ := oauth2Domain.ParseAuthorizationDetails([]byte(p.AuthorizationDetails))
+	if !client.AllowsAuthorizationDetails(details) {
+		return &authorizeParamError{"invalid_authorization_details", "authorization_details type not allowed for this client"}
+	}
+
+	// RFC 8707 §2: the resources must be registered and allow one of the client's scopes.
+	if len(p.Resource) > 0 {
+		if paramErr := c.checkResources(p.Resource, client.ValidateScope(splitScope(p.Scope))); paramErr != nil {
+			return paramErr
+		}
+	}
+	return nil
+}
+
+// Authorize GET /oauth2/authorize
+func (c *OAuth2Controller) Authorize(ctx *gin.Context) {
+	params, pushed, ok := c.resolveAuthorizeParams(ctx)
+	if !ok {
 		return
 	}
+	// A request object needs the client's keys, so its parameters are validated after
+	// the client is loaded.
+	requestObject, requestURI := "", ""
+	if !pushed {
+		requestObject = ctx.Query("request")
+		requestURI = ctx.Query("request_uri")
+	}
+	if requestObject == "" && requestURI == "" {
+		if paramErr := params.validate(); paramErr != nil {
+			writeAuthorizeParamError(ctx, paramErr)
+			return
+		}
+	}
 
+	clientID := params.ClientID
 	client, err := c.clientSvc.FindByClientID(ctx, clientID)
 	if err != nil {
 		c.logger.Error("Authorize: FindByClientID failed", zap.String("client_id", clientID), zap.Error(err))
@@ -91,31 +326,83 @@ func (c *OAuth2Controller) Authorize(ctx *gin.Context) {
 		return
 	}
 
-	if !client.ValidateRedirectURI(redirectURI) {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_redirect_uri"})
-		return
+	if requestURI != "" {
+		var paramErr *authorizeParamError
+		if requestObject, paramErr = c.fetchRequestObject(ctx, client, requestURI); paramErr != nil {
+			writeAuthorizeParamError(ctx, paramErr)
+			return
+		}
+	}
+	if requestObject != "" {
+		var paramErr *authorizeParamError
+		if params, paramErr = c.applyRequestObject(ctx, params, client, requestObject); paramErr != nil {
+			writeAuthorizeParamError(ctx, paramErr)
+			return
+		}
+		if paramErr = params.validate(); paramErr != nil {
+			writeAuthorizeParamError(ctx, paramErr)
+			return
+		}
 	}
 
-	// State parameter is required for public clients (RFC 6749 Section 10.12)
-	if !client.IsConfidential && state == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "state parameter is required for public clients"})
+	// RFC 9126 Section 6: clients registered with require_pushed_authorization_requests
+	// may only start an authorization request through the PAR endpoint.
+	if client.RequirePushedAuthorizationRequests && !pushed {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "pushed authorization request is required for this client"})
 		return
 	}
-
-	// PKCE is mandatory for public clients (RFC 7636 Section 4.1)
-	if !client.IsConfidential && codeChallenge == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "code_challenge is required for public clients"})
+	if paramErr := checkSignedRequestObjectRequirement(&params, client); paramErr != nil {
+		writeAuthorizeParamError(ctx, paramErr)
+		return
+	}
+	if paramErr := c.checkResponseMode(params.ResponseMode); paramErr != nil {
+		writeAuthorizeParamError(ctx, paramErr)
 		return
 	}
 
-	// PKCE enforcement for confidential clients (configurable via auth.enforce_pkce_for_confidential)
-	if client.IsConfidential && c.enforcePKCEForConfidential && codeChallenge == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "code_challenge is required for all clients"})
+	if paramErr := c.validateAuthorizeParamsForClient(&params, client); paramErr != nil {
+		writeAuthorizeParamError(ctx, paramErr)
 		return
 	}
 
-	accountIDStr, ok := middleware.RequireAccountID(ctx)
-	if !ok {
+	redirectURI := params.RedirectURI
+	responseMode := params.ResponseMode
+	scope := params.Scope
+	state := params.State
+	codeChallenge := params.CodeChallenge
+	codeChallengeMethod := params.CodeChallengeMethod
+	nonce := params.Nonce
+	// These were validated by params.validate above.
+	prompt, _ := parsePrompt(params.Prompt)
+	maxAge, hasMaxAge, _ := parseMaxAge(params.MaxAge)
+	details, _ := oauth2Domain.ParseAuthorizationDetails([]byte(params.AuthorizationDetails))
+	claims, _ := oauth2Domain.ParseClaimsRequest([]byte(params.Claims))
+
+	// From here on the redirect URI is trusted, so errors are returned to the client
+	// (OIDC Core §3.1.2.6) instead of being shown to the end user.
+	hintSubject := ""
+	if params.IDTokenHint != "" && c.idTokenHintVerifier != nil {
+		sub, hintErr := c.idTokenHintVerifier.VerifyIDTokenHint(ctx, params.IDTokenHint, clientID)
+		if hintErr != nil {
+			c.logger.Warn("Authorize: invalid id_token_hint", zap.String("client_id", clientID), zap.Error(hintErr))
+			c.respondWithError(ctx, clientID, redirectURI, responseMode, "invalid_request", "invalid id_token_hint", state)
+			return
+		}
+		hintSubject = sub
+	}
+
+	accountIDStr, authTime, authenticated := authenticatedAccount(ctx)
+	needsLogin := !authenticated ||
+		prompt.login || prompt.selectAccount ||
+		(hintSubject != "" && hintSubject != accountIDStr) ||
+		(authenticated && !c.requestedSubjectMatches(client, accountIDStr, claims)) ||
+		(hasMaxAge && (authTime.IsZero() || time.Since(authTime) > maxAge))
+	if needsLogin {
+		if prompt.none {
+			c.respondWithError(ctx, clientID, redirectURI, responseMode, "login_required", "", state)
+			return
+		}
+		c.redirectToLogin(ctx, params, pushed)
 		return
 	}
 	sessionID := sessionIDFromContext(ctx)
@@ -126,6 +413,39 @@ func (c *OAuth2Controller) Authorize(ctx *gin.Context) {
 		return
 	}
 
+	// prompt=consent always shows the consent page, even if the user consented before.
+	if !prompt.consent {
+		existingConsent, consentErr := c.consentSvc.GetConsent(ctx, accountIDStr, clientID)
+		if consentErr != nil && !errors.Is(consentErr, oauth2Domain.ErrConsentNotFound) {
+			c.logger.Warn("Failed to get consent, showing consent page", zap.Error(consentErr), zap.String("account_id", utility.MaskOpaqueID(accountIDStr)), zap.String("client_id", clientID))
+		}
+		// Authorization details are never granted without asking: they typically describe a
+		// single transaction, so even a payment identical to an approved one needs consent
+		// (RFC 9396 §6.1). Individually requested claims must be covered by the consented scopes.
+		if existingConsent != nil && len(details) == 0 && existingConsent.CoversClaims(claims) {
+			// Only grant scopes the user previously consented to AND the client is currently allowed.
+			// No overlap falls through to require re-consent.
+			clientAllowedScopes := client.ValidateScope(splitScope(scope))
+			allowedScopes := intersectScopes(clientAllowedScopes, existingConsent.Scopes)
+			if len(allowedScopes) > 0 {
+				code, err := c.authCodeSvc.GenerateCode(ctx, clientID, accountIDStr, redirectURI, allowedScopes, details, params.Resource, claims, codeChallenge, codeChallengeMethod, nonce, sessionID, authTime, client.AuthorizationCodeExpiry())
+				if err != nil {
+					c.logger.Error("Failed to generate authorization code for existing consent", zap.Error(err))
+					ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+					return
+				}
+				c.respondWithCode(ctx, clientID, redirectURI, responseMode, code, state)
+				return
+			}
+		}
+	}
+
+	// prompt=none must not display the consent page (OIDC Core §3.1.2.1).
+	if prompt.none {
+		c.respondWithError(ctx, clientID, redirectURI, responseMode, "consent_required", "", state)
+		return
+	}
+
 	// Store PKCE + nonce parameters server-side to prevent tampering in the consent form.
 	// Redis is required for consent state storage; reject if unavailable to prevent PKCE bypass.
 	consentID := uuid.New().String()
@@ -137,15 +457,19 @@ func (c *OAuth2Controller) Authorize(ctx *gin.Context) {
 		return
 	}
 	stateData, err := json.Marshal(consentState{
-		AccountID:           accountIDStr,
-		SessionID:           sessionID,
-		ClientID:            clientID,
-		RedirectURI:         redirectURI,
-		CodeChallenge:       codeChallenge,
-		CodeChallengeMethod: codeChallengeMethod,
-		Nonce:               nonce,
-		State:               state,
-		Scope:               scope,
+		AccountID:            accountIDStr,
+		SessionID:            sessionID,
+		ClientID:             clientID,
+		RedirectURI:          redirectURI,
+		ResponseMode:         responseMode,
+		CodeChallenge:        codeChallenge,
+		CodeChallengeMethod:  codeChallengeMethod,
+		Nonce:                nonce,
+		State:                state,
+		Scope:                scope,
+		AuthorizationDetails: params.AuthorizationDetails,
+		Resources:            params.Resource,
+		Claims:               params.Claims,
 	})
 	if err != nil {
 		c.logger.Error("Failed to marshal consent state", zap.Error(err))
@@ -163,48 +487,83 @@ func (c *OAuth2Controller) Authorize(ctx *gin.Context) {
 		return
 	}
 
-	existingConsent, consentErr := c.consentSvc.GetConsent(ctx, accountIDStr, clientID)
-	if consentErr != nil && !errors.Is(consentErr, oauth2Domain.ErrConsentNotFound) {
-		c.logger.Warn("Failed to get consent, showing consent page", zap.Error(consentErr), zap.String("account_id", utility.MaskOpaqueID(accountIDStr)), zap.String("client_id", clientID))
-	}
-	if existingConsent != nil {
-		// Only grant scopes the user previously consented to AND the client is currently allowed
-		requestedScopes := splitScope(scope)
-		clientAllowedScopes := client.ValidateScope(requestedScopes)
-		allowedScopes := intersectScopes(clientAllowedScopes, existingConsent.Scopes)
-		if len(allowedScopes) == 0 {
-			// No overlap — require re-consent
-			c.renderConsentTemplate(ctx, consentTemplateData{
-				ClientName: client.Name, ClientID: clientID, Scopes: requestedScopes, Scope: scope,
-				State: state, RedirectURI: redirectURI, CodeChallenge: codeChallenge,
-				CodeChallengeMethod: codeChallengeMethod, Nonce: nonce, ConsentID: consentID,
-				CSRFToken: csrfTokenFromCookie(ctx), CSPNonce: middleware.GetCSPNonce(ctx),
-			})
-			return
+	c.renderConsentTemplate(ctx, consentTemplateData{
+		ClientName: client.Name, ClientID: clientID, Scopes: splitScope(scope), Scope: scope,
+		State: state, RedirectURI: redirectURI, ResponseMode: responseMode, CodeChallenge: codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod, Nonce: nonce, ConsentID: consentID,
+		CSRFToken: csrfTokenFromCookie(ctx), CSPNonce: middleware.GetCSPNonce(ctx),
+		AuthorizationDetails: params.AuthorizationDetails, Details: details, Resources: params.Resource,
+		Claims: consentClaims(claims),
+	})
+}
+
+// authenticatedAccount returns the account authenticated by optionalAuthentication and
+// when that authentication happened. The time comes from the validated session; it is
+// zero when unknown, which makes any max_age check fail and forces a fresh login.
+func authenticatedAccount(ctx *gin.Context) (string, time.Time, bool) {
+	accountID := ctx.GetString(middleware.ContextKeyAccountID)
+	if accountID == "" {
+		return "", time.Time{}, false
+	}
+	return accountID, sessionAuthTime(ctx), true
+}
+
+// redirectToLogin sends the user agent to the configured login page. After login the
+// user returns to the same authorization request, minus the parameters that demanded the
+// re-authentication (prompt=login/select_account and max_age), which would otherwise loop.
+// login_hint and the stripped prompt values are forwarded to the login page.
+func (c *OAuth2Controller) redirectToLogin(ctx *gin.Context, params authorizeParams, pushed bool) {
+	resume := params
+	var remaining, forwarded []string
+	for _, v := range strings.Fields(params.Prompt) {
+		if v == "login" || v == "select_account" {
+			forwarded = append(forwarded, v)
+		} else {
+			remaining = append(remaining, v)
 		}
-		code, err := c.authCodeSvc.GenerateCode(ctx, clientID, accountIDStr, redirectURI, allowedScopes, codeChallenge, codeChallengeMethod, nonce, sessionID)
+	}
+	resume.Prompt = strings.Join(remaining, " ")
+	resume.MaxAge = ""
+
+	returnQuery := resume.values()
+	if pushed || params.RequestObject {
+		// The pushed request was consumed on load; push the resumed request again so the
+		// authorization parameters still never travel through the browser. Verified
+		// request objects are pushed too, so they are not downgraded to plain parameters.
+		requestURI, err := c.storePushedAuthorizationRequest(ctx, resume)
 		if err != nil {
-			c.logger.Error("Failed to generate authorization code for existing consent", zap.Error(err))
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+			c.logger.Error("Failed to store pushed authorization request in Redis", zap.Error(err))
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "server_error", "error_description": "unable to store authorization request, please try again"})
 			return
 		}
-		redirectWithCode(ctx, redirectURI, code.Code, state, c.issuer)
-		return
+		returnQuery = url.Values{"client_id": {params.ClientID}, "request_uri": {requestURI}}
 	}
+	returnURL := ctx.Request.URL.Path + "?" + returnQuery.Encode()
 
-	// No existing consent — show consent page
-	c.renderConsentTemplate(ctx, consentTemplateData{
-		ClientName: client.Name, ClientID: clientID, Scopes: splitScope(scope), Scope: scope,
-		State: state, RedirectURI: redirectURI, CodeChallenge: codeChallenge,
-		CodeChallengeMethod: codeChallengeMethod, Nonce: nonce, ConsentID: consentID,
-		CSRFToken: csrfTokenFromCookie(ctx), CSPNonce: middleware.GetCSPNonce(ctx),
-	})
+	loginQuery := url.Values{"redirect_uri": {returnURL}}
+	if params.LoginHint != "" {
+		loginQuery.Set("login_hint", params.LoginHint)
+	}
+	if len(forwarded) > 0 {
+		loginQuery.Set("prompt", strings.Join(forwarded, " "))
+	}
+	loginURL := c.authOptions.LoginURL
+	if loginURL == "" {
+		loginURL = "/login"
+	}
+	if strings.Contains(loginURL, "?") {
+		loginURL += "&" + loginQuery.Encode()
+	} else {
+		loginURL += "?" + loginQuery.Encode()
+	}
+	ctx.Redirect(http.StatusFound, loginURL)
 }
 
 // ConsentRequest is the consent approval request body.
 type ConsentRequest struct {
 	ClientID            string `form:"client_id" binding:"required"`
 	RedirectURI         string `form:"redirect_uri" binding:"required"`
+	ResponseMode        string `form:"response_mode"`
 	Scope               string `form:"scope" binding:"required"`
 	State               string `form:"state"`
 	Approved            bool   `form:"approved"`
@@ -212,6 +571,8 @@ type ConsentRequest struct {
 	CodeChallengeMethod string `form:"code_challenge_method"`
 	Nonce               string `form:"nonce"`
 	ConsentID           string `form:"consent_id"`
+	// AuthorizationDetails echoes the authorization_details parameter (RFC 9396).
+	AuthorizationDetails string `form:"authorization_details"`
 }
 
 // SubmitConsent POST /oauth2/authorize
@@ -266,18 +627,15 @@ func (c *OAuth2Controller) SubmitConsent(ctx *gin.Context) {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_client", "error_description": "invalid client or redirect_uri"})
 			return
 		}
-		u, err := url.Parse(req.RedirectURI)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_redirect_uri"})
+		if req.ResponseMode != "" && !slices.Contains(supportedResponseModes, req.ResponseMode) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "unsupported response_mode"})
 			return
 		}
-		q := u.Query()
-		q.Set("error", "access_denied")
-		if req.State != "" {
-			q.Set("state", req.State)
+		if paramErr := c.checkResponseMode(req.ResponseMode); paramErr != nil {
+			writeAuthorizeParamError(ctx, paramErr)
+			return
 		}
-		u.RawQuery = q.Encode()
-		ctx.Redirect(http.StatusFound, u.String())
+		c.respondWithError(ctx, req.ClientID, req.RedirectURI, req.ResponseMode, "access_denied", "", req.State)
 		return
 	}
 
@@ -313,13 +671,15 @@ func (c *OAuth2Controller) SubmitConsent(ctx *gin.Context) {
 	}
 	if req.ClientID != stored.ClientID ||
 		req.RedirectURI != stored.RedirectURI ||
+		req.ResponseMode != stored.ResponseMode ||
 		accountIDStr != stored.AccountID ||
 		sessionIDFromContext(ctx) != stored.SessionID ||
 		req.CodeChallenge != stored.CodeChallenge ||
 		req.CodeChallengeMethod != stored.CodeChallengeMethod ||
 		req.Nonce != stored.Nonce ||
 		req.State != stored.State ||
-		req.Scope != stored.Scope {
+		req.Scope != stored.Scope ||
+		req.AuthorizationDetails != stored.AuthorizationDetails {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "authorization parameters mismatch"})
 		return
 	}
@@ -346,6 +706,19 @@ func (c *OAuth2Controller) SubmitConsent(ctx *gin.Context) {
 		return
 	}
 
+	// The details matched the stored request; the client may have been changed since.
+	details, err := oauth2Domain.ParseAuthorizationDetails([]byte(req.AuthorizationDetails))
+	if err != nil || !client.AllowsAuthorizationDetails(details) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_authorization_details"})
+		return
+	}
+
+	claims, err := oauth2Domain.ParseClaimsRequest([]byte(stored.Claims))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "invalid consent session data"})
+		return
+	}
+
 	// Verify account is still active before generating authorization code.
 	// The account may have been suspended between the GET (consent page) and POST (approval).
 	if !c.accountValidator.IsAccountActive(ctx, accountIDStr) {
@@ -358,6 +731,7 @@ func (c *OAuth2Controller) SubmitConsent(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
 		return
 	}
+	consent.AuthorizationDetails = details
 	if saveErr := c.consentSvc.SaveConsent(ctx, consent); saveErr != nil {
 		c.logger.Error("Failed to save consent", zap.Error(saveErr))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
@@ -365,14 +739,14 @@ func (c *OAuth2Controller) SubmitConsent(ctx *gin.Context) {
 	}
 
 	sessionID := sessionIDFromContext(ctx)
-	code, err := c.authCodeSvc.GenerateCode(ctx, req.ClientID, accountIDStr, req.RedirectURI, scopes, req.CodeChallenge, req.CodeChallengeMethod, req.Nonce, sessionID)
+	code, err := c.authCodeSvc.GenerateCode(ctx, req.ClientID, accountIDStr, req.RedirectURI, scopes, details, stored.Resources, claims, req.CodeChallenge, req.CodeChallengeMethod, req.Nonce, sessionID, sessionAuthTime(ctx), client.AuthorizationCodeExpiry())
 	if err != nil {
 		c.logger.Error("Failed to generate authorization code after consent approval", zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
 		return
 	}
 
-	redirectWithCode(ctx, req.RedirectURI, code.Code, req.State, c.issuer)
+	c.respondWithCode(ctx, req.ClientID, req.RedirectURI, req.ResponseMode, code, req.State)
 }
 
 // consentTemplateData holds the data passed to the consent HTML template.
@@ -383,30 +757,43 @@ type consentTemplateData struct {
 	Scope               string
 	State               string
 	RedirectURI         string
+	ResponseMode        string
 	CodeChallenge       string
 	CodeChallengeMethod string
 	Nonce               string
 	CSRFToken           string
 	ConsentID           string
 	CSPNonce            string
+	// AuthorizationDetails is the raw parameter for the form; Details is rendered.
+	AuthorizationDetails string
+	Details              oauth2Domain.AuthorizationDetails
+	// Resources are the requested protected resources (RFC 8707).
+	Resources []string
+	// Claims are the individually requested claims (OIDC Core §5.5).
+	Claims []consentClaim
 }
 
 // renderConsentTemplate renders the consent page and writes it to the response.
 func (c *OAuth2Controller) renderConsentTemplate(ctx *gin.Context, data consentTemplateData) {
 	var buf bytes.Buffer
 	if err := c.consentTmpl.Execute(&buf, gin.H{
-		"ClientName":          data.ClientName,
-		"ClientID":            data.ClientID,
-		"Scopes":              data.Scopes,
-		"Scope":               data.Scope,
-		"State":               data.State,
-		"RedirectURI":         data.RedirectURI,
-		"CodeChallenge":       data.CodeChallenge,
-		"CodeChallengeMethod": data.CodeChallengeMethod,
-		"Nonce":               data.Nonce,
-		"CSRFToken":           data.CSRFToken,
-		"ConsentID":           data.ConsentID,
-		"CSPNonce":            data.CSPNonce,
+		"ClientName":           data.ClientName,
+		"ClientID":             data.ClientID,
+		"Scopes":               data.Scopes,
+		"Scope":                data.Scope,
+		"State":                data.State,
+		"RedirectURI":          data.RedirectURI,
+		"ResponseMode":         data.ResponseMode,
+		"CodeChallenge":        data.CodeChallenge,
+		"CodeChallengeMethod":  data.CodeChallengeMethod,
+		"Nonce":                data.Nonce,
+		"CSRFToken":            data.CSRFToken,
+		"ConsentID":            data.ConsentID,
+		"CSPNonce":             data.CSPNonce,
+		"AuthorizationDetails": data.AuthorizationDetails,
+		"Details":              consentDetails(data.Details),
+		"Resources":            data.Resources,
+		"Claims":               data.Claims,
 	}); err != nil {
 		c.logger.Error("Failed to render consent template", zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
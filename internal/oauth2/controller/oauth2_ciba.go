@@ -0,0 +1,593 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/controllerutil"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	"github.com/rushairer/gosso/internal/utility"
+)
+
+// BackchannelAuthManager defines the backchannel authentication request operations (CIBA).
+type BackchannelAuthManager interface {
+	CreateBackchannelAuthRequest(ctx context.Context, req *oauth2Domain.BackchannelAuthRequest, expiry time.Duration) (*oauth2Domain.BackchannelAuthRequest, error)
+	GetBackchannelAuthRequest(ctx context.Context, authReqID string) (*oauth2Domain.BackchannelAuthRequest, error)
+	GetBackchannelAuthRequestByID(ctx context.Context, id string) (*oauth2Domain.BackchannelAuthRequest, error)
+	AuthorizeBackchannelAuthRequest(ctx context.Context, id, accountID string) (*oauth2Domain.BackchannelAuthRequest, error)
+	DenyBackchannelAuthRequest(ctx context.Context, id, accountID string) (*oauth2Domain.BackchannelAuthRequest, error)
+	CheckBackchannelPollRate(ctx context.Context, authReqID string) error
+	ClaimAuthorizedBackchannelAuthRequest(ctx context.Context, authReqID, clientID string) (*oauth2Domain.BackchannelAuthRequest, error)
+}
+
+// BackchannelUserNotifier asks the user a backchannel authentication request is addressed to
+// for approval, e.g. through a push notification to their authentication device.
+type BackchannelUserNotifier interface {
+	NotifyUser(ctx context.Context, req *oauth2Domain.BackchannelAuthRequest, client *oauth2Domain.OAuth2Client) error
+}
+
+// BackchannelClientNotifier delivers ping and push mode callbacks to a client notification
+// endpoint, authenticated with the client_notification_token of the request.
+type BackchannelClientNotifier interface {
+	NotifyClient(clientID, endpoint, clientNotificationToken string, payload any)
+}
+
+// BackchannelIDTokenManager generates the ID token of CIBA push mode token deliveries, which
+// carries the rt_hash and auth_req_id claims (CIBA Core §10.3.1).
+type BackchannelIDTokenManager interface {
+	GenerateBackchannelIDToken(ctx context.Context, accountID, clientID string, scopes []string, authTime time.Time, accessToken, refreshToken, authReqID string) (string, error)
+}
+
+// LoginHintResolver maps the login_hint of a backchannel authentication request to an account.
+type LoginHintResolver interface {
+	ResolveLoginHint(ctx context.Context, loginHint string) (string, error)
+}
+
+const (
+	maxBindingMessageLength          = 64
+	maxClientNotificationTokenLength = 1024
+)
+
+// BackchannelAuthentication POST /oauth2/bc-authorize (OpenID Connect CIBA Core §7)
+//
+// A confidential client asks for the user identified by login_hint or id_token_hint to be
+// authenticated on their own device. The user is notified through the configured
+// BackchannelUserNotifier and responds through the approval API; the client learns the
+// outcome by polling the token endpoint, by a ping, or by having the tokens pushed to it.
+func (c *OAuth2Controller) BackchannelAuthentication(ctx *gin.Context) {
+	if ctx.Request.ContentLength > oauth2MaxFormBodySize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "invalid_request", "error_description": "request body too large"})
+		return
+	}
+
+	// CIBA Core §7.1: the request uses application/x-www-form-urlencoded.
+	contentType := ctx.GetHeader("Content-Type")
+	if contentType == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "Content-Type header is required"})
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/x-www-form-urlencoded" {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "invalid_request", "error_description": "Content-Type must be application/x-www-form-urlencoded"})
+		return
+	}
+	if err := ctx.Request.ParseForm(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	form := ctx.Request.PostForm
+
+	// Basic auth takes precedence over body parameters per RFC 6749 §2.3.1.
+	clientID, clientSecret, hasBasicAuth := ctx.Request.BasicAuth()
+	if !hasBasicAuth {
+		clientID = form.Get("client_id")
+		clientSecret = form.Get("client_secret")
+	}
+	assertion := form.Get("client_assertion")
+	if !resolveClientAssertion(ctx, form.Get("client_assertion_type"), assertion, &clientID, hasBasicAuth || clientSecret != "") {
+		return
+	}
+	if clientID == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "client authentication required"})
+		return
+	}
+
+	client, err := c.clientSvc.FindByClientID(ctx, clientID)
+	if err != nil {
+		c.clientAuth.DummyAuthenticate()
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+	if authErr := c.authenticateClient(ctx, client, clientSecret, assertion); authErr != nil {
+		controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
+		return
+	} else if !client.IsConfidential {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "public clients are not allowed to use backchannel authentication"})
+		return
+	}
+	if !client.HasGrantType(oauth2Domain.GrantTypeCIBA) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "ciba grant not allowed for this client"})
+		return
+	}
+
+	mode := client.BackchannelDeliveryMode()
+	if mode != oauth2Domain.BackchannelTokenDeliveryPoll && c.backchannelClientNotifier == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "backchannel token delivery mode " + mode + " is not supported"})
+		return
+	}
+	if form.Has("request") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "signed authentication requests are not supported"})
+		return
+	}
+
+	// CIBA Core §7.1: scope is required and must contain openid.
+	scopes := client.ValidateScope(splitScope(form.Get("scope")))
+	if !slices.Contains(scopes, "openid") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope", "error_description": "scope must include openid and only scopes allowed for this client"})
+		return
+	}
+
+	accountID, ok := c.resolveBackchannelUser(ctx, client, form.Get("login_hint"), form.Get("id_token_hint"), form.Get("login_hint_token"))
+	if !ok {
+		return
+	}
+
+	bindingMessage := form.Get("binding_message")
+	if !validBindingMessage(bindingMessage) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_binding_message", "error_description": "binding_message must be at most 64 printable characters"})
+		return
+	}
+
+	notificationToken := form.Get("client_notification_token")
+	if mode != oauth2Domain.BackchannelTokenDeliveryPoll && notificationToken == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "client_notification_token is required for " + mode + " mode"})
+		return
+	}
+	if len(notificationToken) > maxClientNotificationTokenLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "client_notification_token is too long"})
+		return
+	}
+
+	var expiry time.Duration
+	if raw := form.Get("requested_expiry"); raw != "" {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil || seconds <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "requested_expiry must be a positive integer"})
+			return
+		}
+		expiry = time.Duration(seconds) * time.Second
+	}
+
+	authReq, err := c.backchannelAuth.CreateBackchannelAuthRequest(ctx, &oauth2Domain.BackchannelAuthRequest{
+		ClientID:                client.ClientID,
+		AccountID:               accountID,
+		Scopes:                  scopes,
+		BindingMessage:          bindingMessage,
+		ClientNotificationToken: notificationToken,
+		DeliveryMode:            mode,
+	}, expiry)
+	if err != nil {
+		c.logger.Error("Failed to create backchannel authentication request", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	if err := c.backchannelUserNotifier.NotifyUser(ctx, authReq, client); err != nil {
+		c.logger.Error("Failed to notify user of backchannel authentication request", zap.Error(err),
+			zap.String("client_id", client.ClientID), zap.String("account_id", utility.MaskOpaqueID(accountID)))
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "server_error", "error_description": "unable to reach the user, please try again"})
+		return
+	}
+
+	response := gin.H{
+		"auth_req_id": authReq.AuthReqID,
+		"expires_in":  int(time.Until(authReq.ExpiresAt).Round(time.Second).Seconds()),
+	}
+	if mode != oauth2Domain.BackchannelTokenDeliveryPush {
+		response["interval"] = authReq.Interval
+	}
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// resolveBackchannelUser identifies the user of a backchannel authentication request from
+// exactly one of its hints (CIBA Core §7.1). login_hint_token is not supported.
+func (c *OAuth2Controller) resolveBackchannelUser(ctx *gin.Context, client *oauth2Domain.OAuth2Client, loginHint, idTokenHint, loginHintToken string) (string, bool) {
+	hints := 0
+	for _, h := range []string{loginHint, idTokenHint, loginHintToken} {
+		if h != "" {
+			hints++
+		}
+	}
+	if hints != 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "exactly one of login_hint, id_token_hint or login_hint_token is required"})
+		return "", false
+	}
+
+	var accountID string
+	switch {
+	case loginHintToken != "":
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "login_hint_token is not supported"})
+		return "", false
+	case idTokenHint != "":
+		if c.idTokenHintVerifier == nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "id_token_hint is not supported"})
+			return "", false
+		}
+		subject, err := c.idTokenHintVerifier.VerifyIDTokenHint(ctx, idTokenHint, client.ClientID)
+		if err != nil {
+			c.logger.Debug("Invalid id_token_hint in backchannel authentication request", zap.Error(err))
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "invalid id_token_hint"})
+			return "", false
+		}
+		accountID = subject
+	default:
+		if c.loginHintResolver == nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "login_hint is not supported"})
+			return "", false
+		}
+		resolved, err := c.loginHintResolver.ResolveLoginHint(ctx, loginHint)
+		if err != nil {
+			c.logger.Debug("Unknown login_hint in backchannel authentication request", zap.Error(err))
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "unknown_user_id", "error_description": "the user could not be identified"})
+			return "", false
+		}
+		accountID = resolved
+	}
+
+	if !c.accountValidator.IsAccountActive(ctx, accountID) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unknown_user_id", "error_description": "the user could not be identified"})
+		return "", false
+	}
+	return accountID, true
+}
+
+// validBindingMessage reports whether msg is acceptable as a binding_message: short plain
+// text that fits on the user's device (CIBA Core §7.1).
+func validBindingMessage(msg string) bool {
+	if !utf8.ValidString(msg) || utf8.RuneCountInString(msg) > maxBindingMessageLength {
+		return false
+	}
+	return !strings.ContainsFunc(msg, unicode.IsControl)
+}
+
+// BackchannelAuthRequestResponse describes a backchannel authentication request to the user
+// it is addressed to.
+type BackchannelAuthRequestResponse struct {
+	RequestID      string    `json:"request_id"`
+	ClientID       string    `json:"client_id"`
+	ClientName     string    `json:"client_name"`
+	Scope          string    `json:"scope"`
+	BindingMessage string    `json:"binding_message,omitempty"`
+	Status         string    `json:"status"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// GetBackchannelAuthRequest GET /oauth2/backchannel/requests/:id
+//
+// Returns a backchannel authentication request to the authenticated user it is addressed
+// to, so their device can show what is being approved.
+func (c *OAuth2Controller) GetBackchannelAuthRequest(ctx *gin.Context) {
+	accountID, ok := c.authenticateFirstPartyRequest(ctx)
+	if !ok {
+		return
+	}
+	authReq, ok := c.findBackchannelAuthRequestForUser(ctx, ctx.Param("id"), accountID)
+	if !ok {
+		return
+	}
+	client, err := c.clientSvc.FindByClientID(ctx, authReq.ClientID)
+	if err != nil {
+		c.logger.Warn("Client lookup failed for backchannel authentication request", zap.Error(err), zap.String("client_id", authReq.ClientID))
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "not_found", "error_description": "backchannel authentication request not found"})
+		return
+	}
+
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusOK, BackchannelAuthRequestResponse{
+		RequestID:      authReq.ID,
+		ClientID:       client.ClientID,
+		ClientName:     client.Name,
+		Scope:          strings.Join(authReq.Scopes, " "),
+		BindingMessage: authReq.BindingMessage,
+		Status:         string(authReq.Status),
+		ExpiresAt:      authReq.ExpiresAt,
+	})
+}
+
+// BackchannelAuthDecisionRequest is the body of the approval API.
+type BackchannelAuthDecisionRequest struct {
+	Approved *bool `json:"approved" binding:"required"`
+}
+
+// SubmitBackchannelAuthRequest POST /oauth2/backchannel/requests/:id
+//
+// Records the decision of the authenticated user on a pending backchannel authentication
+// request and notifies ping and push clients of it.
+func (c *OAuth2Controller) SubmitBackchannelAuthRequest(ctx *gin.Context) {
+	var req BackchannelAuthDecisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "approved is required"})
+		return
+	}
+	accountID, ok := c.authenticateFirstPartyRequest(ctx)
+	if !ok {
+		return
+	}
+	authReq, ok := c.findBackchannelAuthRequestForUser(ctx, ctx.Param("id"), accountID)
+	if !ok {
+		return
+	}
+
+	var err error
+	if *req.Approved {
+		authReq, err = c.backchannelAuth.AuthorizeBackchannelAuthRequest(ctx, authReq.ID, accountID)
+	} else {
+		authReq, err = c.backchannelAuth.DenyBackchannelAuthRequest(ctx, authReq.ID, accountID)
+	}
+	if err != nil {
+		if errors.Is(err, oauth2Domain.ErrBackchannelAuthRequestNotFound) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "invalid_request", "error_description": "backchannel authentication request has already been decided"})
+			return
+		}
+		c.logger.Error("Failed to record backchannel authentication decision", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+
+	c.notifyBackchannelClient(ctx, authReq)
+
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"status": string(authReq.Status)})
+}
+
+// findBackchannelAuthRequestForUser loads the unexpired request id addressed to accountID.
+// Requests of other users are reported as not found.
+func (c *OAuth2Controller) findBackchannelAuthRequestForUser(ctx *gin.Context, id, accountID string) (*oauth2Domain.BackchannelAuthRequest, bool) {
+	authReq, err := c.backchannelAuth.GetBackchannelAuthRequestByID(ctx, id)
+	if err != nil && !errors.Is(err, oauth2Domain.ErrBackchannelAuthRequestNotFound) {
+		c.logger.Error("Failed to load backchannel authentication request", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return nil, false
+	}
+	if err != nil || authReq.AccountID != accountID || authReq.IsExpired() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "not_found", "error_description": "backchannel authentication request not found"})
+		return nil, false
+	}
+	return authReq, true
+}
+
+// notifyBackchannelClient tells a ping or push client about the user's decision on authReq
+// (CIBA Core §10). Push clients receive the tokens, or the error, directly.
+func (c *OAuth2Controller) notifyBackchannelClient(ctx *gin.Context, authReq *oauth2Domain.BackchannelAuthRequest) {
+	if authReq.DeliveryMode == oauth2Domain.BackchannelTokenDeliveryPoll || authReq.DeliveryMode == "" || c.backchannelClientNotifier == nil {
+		return
+	}
+	client, err := c.clientSvc.FindByClientID(ctx, authReq.ClientID)
+	if err != nil {
+		c.logger.Warn("Client lookup failed for backchannel notification", zap.Error(err), zap.String("client_id", authReq.ClientID))
+		return
+	}
+	endpoint := client.BackchannelClientNotificationEndpoint
+	if endpoint == "" {
+		return
+	}
+
+	var payload gin.H
+	switch {
+	case authReq.DeliveryMode == oauth2Domain.BackchannelTokenDeliveryPing:
+		payload = gin.H{"auth_req_id": authReq.AuthReqID}
+	case authReq.Status == oauth2Domain.DeviceCodeStatusAuthorized:
+		payload = c.pushBackchannelTokens(ctx, client, authReq)
+	default:
+		payload = gin.H{"auth_req_id": authReq.AuthReqID, "error": "access_denied", "error_description": "the user denied the request"}
+	}
+	c.backchannelClientNotifier.NotifyClient(client.ClientID, endpoint, authReq.ClientNotificationToken, payload)
+}
+
+// pushBackchannelTokens claims an approved push mode request and returns the token
+// delivery payload (CIBA Core §10.3.1), or a transaction_failed error payload.
+func (c *OAuth2Controller) pushBackchannelTokens(ctx *gin.Context, client *oauth2Domain.OAuth2Client, authReq *oauth2Domain.BackchannelAuthRequest) gin.H {
+	failed := gin.H{"auth_req_id": authReq.AuthReqID, "error": "transaction_failed", "error_description": "tokens could not be issued"}
+	if !c.accountValidator.IsAccountActive(ctx, authReq.AccountID) {
+		return failed
+	}
+	claimed, err := c.backchannelAuth.ClaimAuthorizedBackchannelAuthRequest(ctx, authReq.AuthReqID, client.ClientID)
+	if err != nil {
+		c.logger.Warn("Backchannel authentication request claim failed", zap.Error(err), zap.String("client_id", client.ClientID))
+		return failed
+	}
+	tokens, err := c.issueBackchannelTokens(ctx, client, claimed, nil, nil, nil, claimed.AuthReqID)
+	if err != nil {
+		c.logger.Error("Failed to issue pushed backchannel tokens", zap.Error(err), zap.String("client_id", client.ClientID))
+		return failed
+	}
+	tokens["auth_req_id"] = claimed.AuthReqID
+	return tokens
+}
+
+// handleCIBAGrant exchanges an auth_req_id for tokens (CIBA Core §10.1) for poll and ping
+// mode clients. Token endpoint errors use HTTP 400 throughout, including slow_down (§11).
+func (c *OAuth2Controller) handleCIBAGrant(ctx *gin.Context, req *TokenRequest) {
+	if c.backchannelAuth == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
+		return
+	}
+	if req.AuthReqID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "auth_req_id is required"})
+		return
+	}
+
+	client, err := c.clientSvc.FindByClientID(ctx, req.ClientID)
+	if err != nil {
+		c.clientAuth.DummyAuthenticate()
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+	if !client.HasGrantType(oauth2Domain.GrantTypeCIBA) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "ciba grant not allowed for this client"})
+		return
+	}
+	if authErr := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); authErr != nil {
+		controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
+		return
+	} else if !client.IsConfidential {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "public clients are not allowed to use backchannel authentication"})
+		return
+	}
+	if client.BackchannelDeliveryMode() == oauth2Domain.BackchannelTokenDeliveryPush {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "push mode clients receive tokens at their notification endpoint"})
+		return
+	}
+	if !c.bindSenderConstraints(ctx, client, req) {
+		return
+	}
+
+	authReq, err := c.backchannelAuth.GetBackchannelAuthRequest(ctx, req.AuthReqID)
+	if err != nil || authReq.ClientID != client.ClientID {
+		if err != nil && !errors.Is(err, oauth2Domain.ErrBackchannelAuthRequestNotFound) {
+			c.logger.Error("Failed to load backchannel authentication request", zap.Error(err))
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "invalid auth_req_id"})
+		return
+	}
+	if authReq.IsExpired() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "expired_token", "error_description": "auth_req_id has expired"})
+		return
+	}
+	if pollErr := c.backchannelAuth.CheckBackchannelPollRate(ctx, req.AuthReqID); pollErr != nil {
+		if errors.Is(pollErr, oauth2Domain.ErrSlowDown) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "slow_down", "error_description": "polling too frequently"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "invalid auth_req_id"})
+		return
+	}
+
+	switch authReq.Status {
+	case oauth2Domain.DeviceCodeStatusPending:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "authorization_pending", "error_description": "the user has not yet responded"})
+		return
+	case oauth2Domain.DeviceCodeStatusDenied:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "access_denied", "error_description": "the user denied the request"})
+		return
+	case oauth2Domain.DeviceCodeStatusAuthorized:
+		// Continue to account validation and atomic claim.
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "auth_req_id already used"})
+		return
+	}
+
+	if !c.accountValidator.IsAccountActive(ctx, authReq.AccountID) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "account is not active"})
+		return
+	}
+	target, ok := c.resourceTarget(ctx, req.Resource, nil, authReq.Scopes)
+	if !ok {
+		return
+	}
+	claimed, err := c.backchannelAuth.ClaimAuthorizedBackchannelAuthRequest(ctx, req.AuthReqID, client.ClientID)
+	if err != nil {
+		c.logger.Warn("Backchannel authentication request claim failed", zap.Error(err), zap.String("client_id", client.ClientID))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "auth_req_id already used"})
+		return
+	}
+
+	response, err := c.issueBackchannelTokens(ctx, client, claimed, target, tokenConfirmation(req), refreshTokenConfirmation(client, req), "")
+	if err != nil {
+		c.logger.Error("Failed to issue backchannel tokens", zap.Error(err), zap.String("client_id", client.ClientID))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	response["token_type"] = accessTokenType(req.dpopJKT)
+
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// issueBackchannelTokens issues the access, refresh and ID tokens for an approved backchannel
+// authentication request. A non-nil target restricts the access token to protected
+// resources. A non-empty pushedAuthReqID marks a push mode delivery, whose ID token carries
+// the auth_req_id and rt_hash claims.
+func (c *OAuth2Controller) issueBackchannelTokens(ctx *gin.Context, client *oauth2Domain.OAuth2Client, authReq *oauth2Domain.BackchannelAuthRequest, target *oauth2Domain.ResourceTarget, cnf, refreshCnf *tokenDomain.ConfirmationClaim, pushedAuthReqID string) (gin.H, error) {
+	var roles, permissions []string
+	if c.includeUserRoles && c.roleFetcher != nil {
+		var err error
+		if roles, err = c.roleFetcher.GetAccountRoles(ctx, authReq.AccountID); err != nil {
+			return nil, err
+		}
+	}
+	if c.includeUserPermissions && c.permissionFetcher != nil {
+		var err error
+		if permissions, err = c.permissionFetcher.GetAccountPermissions(ctx, authReq.AccountID); err != nil {
+			return nil, err
+		}
+	}
+
+	scope := strings.Join(authReq.Scopes, " ")
+	accessToken, accessTokenScope, accessTokenLifetime, err := c.issueAccessToken(ctx, client, &tokenDomain.AccessTokenClaims{
+		AccountID:   authReq.AccountID,
+		Scope:       scope,
+		ClientID:    client.ClientID,
+		Roles:       roles,
+		Permissions: permissions,
+		Cnf:         cnf,
+	}, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var refreshTokenStr string
+	if issuesRefreshToken(client, splitScope(scope), "") {
+		var resources []string
+		if target != nil {
+			resources = target.Audience
+		}
+		refreshToken, rtErr := c.tokenSvc.GenerateRefreshToken(ctx, authReq.AccountID, client.ClientID, "", scope, nil, resources, nil, refreshCnf, refreshTokenPolicy(client))
+		if rtErr != nil {
+			return nil, rtErr
+		}
+		refreshTokenStr = refreshToken.Token
+	}
+
+	var idToken string
+	if c.idTokenSvc != nil {
+		if pushedAuthReqID != "" {
+			backchannelIDTokens, ok := c.idTokenSvc.(BackchannelIDTokenManager)
+			if !ok {
+				return nil, errors.New("id token service does not support push mode")
+			}
+			idToken, err = backchannelIDTokens.GenerateBackchannelIDToken(ctx, authReq.AccountID, client.ClientID, authReq.Scopes, authReq.AuthorizedAt, accessToken, refreshTokenStr, pushedAuthReqID)
+		} else {
+			idToken, err = c.idTokenSvc.GenerateIDToken(ctx, authReq.AccountID, client.ClientID, authReq.Scopes, nil, "", authReq.AuthorizedAt, accessToken, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := gin.H{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(accessTokenLifetime.Seconds()),
+		"scope":        accessTokenScope,
+	}
+	if idToken != "" {
+		response["id_token"] = idToken
+	}
+	if refreshTokenStr != "" {
+		response["refresh_token"] = refreshTokenStr
+	}
+	return response, nil
+}
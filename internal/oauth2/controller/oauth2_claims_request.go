@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"slices"
+
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// consentClaim is an individually requested claim (OIDC Core §5.5) as shown on the
+// consent page.
+type consentClaim struct {
+	Name      string
+	Essential bool
+}
+
+// consentClaims returns the end-user claims of claims, from either member, sorted by name.
+func consentClaims(claims *oauth2Domain.ClaimsRequest) []consentClaim {
+	names := append(claims.IDTokenClaims(), claims.UserInfoClaims()...)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	result := make([]consentClaim, 0, len(names))
+	for _, name := range names {
+		result = append(result, consentClaim{Name: name, Essential: claims.Essential(name)})
+	}
+	return result
+}
+
+// requestedSubjectMatches reports whether accountID is the end user the claims request asks
+// for, when it names one with a sub value (OIDC Core §5.5.1). The value is compared with
+// the subject identifier the client sees.
+func (c *OAuth2Controller) requestedSubjectMatches(client *oauth2Domain.OAuth2Client, accountID string, claims *oauth2Domain.ClaimsRequest) bool {
+	sub, ok := claims.Subject()
+	if !ok {
+		return true
+	}
+	if c.subjectIdentifiers == nil {
+		return sub == accountID
+	}
+	subject, err := c.subjectIdentifiers.Subject(client, accountID)
+	return err == nil && subject == sub
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/rushairer/gosso/internal/mtls"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
+)
+
+// clientAssertionTypeJWTBearer is the only client_assertion_type supported (RFC 7523 §2.2).
+const clientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// resolveClientAssertion validates the client_assertion parameters of a request and, when
+// client_id was not sent, takes it from the assertion's sub claim (RFC 7521 §4.2). It
+// writes an error response and returns false if the request is malformed. hasSecret
+// reports whether the client also presented a secret (body or Basic auth), which is
+// rejected: a client MUST NOT use more than one authentication method (RFC 6749 §2.3).
+func resolveClientAssertion(ctx *gin.Context, assertionType, assertion string, clientID *string, hasSecret bool) bool {
+	if assertionType == "" && assertion == "" {
+		return true
+	}
+	if assertionType != clientAssertionTypeJWTBearer {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "unsupported client_assertion_type"})
+		return false
+	}
+	if assertion == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "client_assertion is required"})
+		return false
+	}
+	if hasSecret {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "multiple client authentication methods are not allowed"})
+		return false
+	}
+
+	// The signature is verified later against the keys of the client named here.
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(assertion, claims); err != nil || claims.Subject == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "client authentication failed"})
+		return false
+	}
+	if *clientID == "" {
+		*clientID = claims.Subject
+	} else if *clientID != claims.Subject {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "client authentication failed"})
+		return false
+	}
+	return true
+}
+
+// authenticateClient verifies the client with its TLS client certificate when it is
+// registered for mutual-TLS authentication, with its assertion when one was presented,
+// and with its client_secret otherwise.
+func (c *OAuth2Controller) authenticateClient(ctx *gin.Context, client *oauth2Domain.OAuth2Client, clientSecret, assertion string) error {
+	if client.UsesTLSClientAuth() {
+		// A client MUST NOT use more than one authentication method (RFC 6749 §2.3).
+		if clientSecret != "" || assertion != "" {
+			return oauth2Service.ErrClientAuthMethodNotAllowed
+		}
+		return c.clientAuth.AuthenticateClientCertificate(ctx.Request.Context(), client, mtls.CertificateFromContext(ctx.Request.Context()))
+	}
+	if assertion == "" {
+		return c.clientAuth.AuthenticateClient(client, clientSecret)
+	}
+	return c.clientAuth.AuthenticateClientAssertion(ctx.Request.Context(), client, assertion, c.clientAssertionAudiences(ctx))
+}
+
+// clientAssertionAudiences lists the aud values accepted in a client assertion: the issuer,
+// the token endpoint, and the endpoint the assertion is presented to (RFC 7523 §3).
+func (c *OAuth2Controller) clientAssertionAudiences(ctx *gin.Context) []string {
+	issuer := strings.TrimSuffix(c.issuer, "/")
+	audiences := []string{c.issuer, issuer + "/oauth2/token"}
+	if path := ctx.Request.URL.Path; path != "/oauth2/token" {
+		audiences = append(audiences, issuer+path)
+	}
+	return audiences
+}
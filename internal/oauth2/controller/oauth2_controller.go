@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"crypto/x509"
 	"embed"
 	"errors"
 	"fmt"
@@ -21,11 +22,13 @@ import (
 	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
 	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
 	sessionDomain "github.com/rushairer/gosso/internal/session/domain"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	"github.com/rushairer/gosso/middleware"
 )
 
 // DeviceCodeManager defines the device code operations needed by the OAuth2 controller.
 type DeviceCodeManager interface {
-	CreateDeviceCode(ctx context.Context, clientID string, scopes []string) (*oauth2Domain.DeviceCode, error)
+	CreateDeviceCode(ctx context.Context, clientID string, scopes []string, lifetime time.Duration) (*oauth2Domain.DeviceCode, error)
 	GetDeviceCode(ctx context.Context, deviceCode string) (*oauth2Domain.DeviceCode, error)
 	GetDeviceCodeByUserCode(ctx context.Context, userCode string) (*oauth2Domain.DeviceCode, error)
 	AuthorizeDeviceCode(ctx context.Context, deviceCode, accountID string) error
@@ -39,7 +42,7 @@ type DeviceCodeManager interface {
 // AuthCodeManager defines authorization code generation and validation operations.
 type AuthCodeManager interface {
 	ValidateCode(ctx context.Context, code, clientID, redirectURI string, codeVerifier *string) (*oauth2Domain.AuthorizationCode, error)
-	GenerateCode(ctx context.Context, clientID, accountID, redirectURI string, scopes []string, codeChallenge, codeChallengeMethod, nonce, sessionID string) (*oauth2Domain.AuthorizationCode, error)
+	GenerateCode(ctx context.Context, clientID, accountID, redirectURI string, scopes []string, authorizationDetails oauth2Domain.AuthorizationDetails, resources []string, claims *oauth2Domain.ClaimsRequest, codeChallenge, codeChallengeMethod, nonce, sessionID string, authTime time.Time, lifetime time.Duration) (*oauth2Domain.AuthorizationCode, error)
 }
 
 // ConsentManager defines user consent persistence and retrieval operations.
@@ -50,17 +53,79 @@ type ConsentManager interface {
 
 // IDTokenManager defines OIDC ID token generation operations.
 type IDTokenManager interface {
-	GenerateIDToken(ctx context.Context, accountID, clientID string, scopes []string, nonce string, authTime time.Time, accessToken string, authMethods []string) (string, error)
+	GenerateIDToken(ctx context.Context, accountID, clientID string, scopes, claims []string, nonce string, authTime time.Time, accessToken string, authMethods []string) (string, error)
+}
+
+// IDTokenHintVerifier validates an id_token_hint previously issued by this server
+// and returns the account ID of its subject. Expired ID tokens are accepted (OIDC Core §3.1.2.1).
+type IDTokenHintVerifier interface {
+	VerifyIDTokenHint(ctx context.Context, idTokenHint, clientID string) (string, error)
+}
+
+// SubjectIdentifier derives the subject identifier a client sees for an account, which
+// differs from the account ID for clients using pairwise identifiers (OIDC Core §8).
+type SubjectIdentifier interface {
+	Subject(client *oauth2Domain.OAuth2Client, accountID string) (string, error)
+	// IssueSubject returns the subject identifier the client with clientID sees for
+	// accountID, recording it so the subject can later be resolved back to the account.
+	IssueSubject(ctx context.Context, clientID, accountID string) (string, error)
+}
+
+// ResourceResolver resolves resource indicators (RFC 8707) against the registered
+// protected resources.
+type ResourceResolver interface {
+	// Resolve returns the audience, scopes and lifetime of an access token for resources
+	// requested with scopes.
+	Resolve(resources, scopes []string) (*oauth2Domain.ResourceTarget, error)
+	// Target returns the audience, lifetime and profile of an access token for the
+	// registered resources among identifiers, or nil when none is registered.
+	Target(identifiers []string) *oauth2Domain.ResourceTarget
+	// IsResourceServer reports whether clientID is registered for one of the resources in
+	// audience.
+	IsResourceServer(clientID string, audience []string) bool
 }
 
 // ClientAuthManager defines OAuth2 client credential verification operations.
 type ClientAuthManager interface {
 	AuthenticateClient(client *oauth2Domain.OAuth2Client, clientSecret string) error
+	// AuthenticateClientAssertion verifies a JWT client assertion (private_key_jwt or
+	// client_secret_jwt) whose aud must contain one of audiences.
+	AuthenticateClientAssertion(ctx context.Context, client *oauth2Domain.OAuth2Client, assertion string, audiences []string) error
+	// AuthenticateClientCertificate verifies mutual-TLS client authentication with the
+	// client certificate presented on the connection (RFC 8705 §2).
+	AuthenticateClientCertificate(ctx context.Context, client *oauth2Domain.OAuth2Client, cert *x509.Certificate) error
 	// DummyAuthenticate performs a dummy bcrypt comparison to mitigate timing side-channels
 	// when client lookup fails, making the response time indistinguishable from a failed auth.
 	DummyAuthenticate()
 }
 
+// JWTBearerVerifier verifies JWT authorization grants from trusted issuers (RFC 7523 §2.1).
+type JWTBearerVerifier interface {
+	// VerifyJWTBearer validates assertion, whose aud must contain one of audiences, and
+	// returns the account or client its subject is mapped to.
+	VerifyJWTBearer(ctx context.Context, assertion string, audiences []string) (*oauth2Service.JWTBearerGrant, error)
+}
+
+// RequestObjectVerifier verifies JWT-secured authorization requests (RFC 9101).
+type RequestObjectVerifier interface {
+	// VerifyRequestObject decrypts and verifies a request object signed by client and
+	// returns the authorization request parameters it carries.
+	VerifyRequestObject(ctx context.Context, client *oauth2Domain.OAuth2Client, requestObject string) (url.Values, error)
+	// FetchRequestObject retrieves a request object passed by reference from one of the
+	// request_uris registered by client.
+	FetchRequestObject(ctx context.Context, client *oauth2Domain.OAuth2Client, requestURI string) (string, error)
+}
+
+// AuthorizationResponseSigner signs authorization responses for the JWT response modes (JARM).
+type AuthorizationResponseSigner interface {
+	SignAuthorizationResponse(clientID string, params url.Values) (string, error)
+}
+
+// IntrospectionResponseSigner signs token introspection responses as JWTs (RFC 9701).
+type IntrospectionResponseSigner interface {
+	SignIntrospectionResponse(clientID string, result map[string]any) (string, error)
+}
+
 // AccountValidator checks whether an account exists and is active.
 type AccountValidator interface {
 	IsAccountActive(ctx context.Context, accountID string) bool
@@ -85,6 +150,9 @@ var deviceTemplateFS embed.FS
 //go:embed template/result.html
 var resultTemplateFS embed.FS
 
+//go:embed template/form_post.html
+var formPostTemplateFS embed.FS
+
 // OAuth2Controller handles OAuth2 protocol endpoints.
 type OAuth2Controller struct {
 	clientSvc                  oauth2Service.OAuth2ClientService
@@ -102,11 +170,26 @@ type OAuth2Controller struct {
 	consentTmpl                *template.Template // html/template — never use text/template (would enable XSS)
 	deviceTmpl                 *template.Template
 	resultTmpl                 *template.Template
+	formPostTmpl               *template.Template
 	logger                     *zap.Logger
 	roleFetcher                AccountRoleFetcher
 	permissionFetcher          AccountPermissionFetcher
 	includeUserRoles           bool
 	includeUserPermissions     bool
+	idTokenHintVerifier        IDTokenHintVerifier
+	jwtBearer                  JWTBearerVerifier
+	dpop                       authMiddleware.DPoPProofVerifier
+	authOptions                authMiddleware.AuthConfigOptions
+	registrar                  ClientRegistrar
+	requestObjects             RequestObjectVerifier
+	jarm                       AuthorizationResponseSigner
+	introspectionSigner        IntrospectionResponseSigner
+	backchannelAuth            BackchannelAuthManager
+	backchannelUserNotifier    BackchannelUserNotifier
+	backchannelClientNotifier  BackchannelClientNotifier
+	loginHintResolver          LoginHintResolver
+	subjectIdentifiers         SubjectIdentifier
+	resources                  ResourceResolver
 }
 
 // NewOAuth2Controller creates a new OAuth2 controller instance.
@@ -139,6 +222,10 @@ func NewOAuth2Controller(
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse result template: %w", err)
 	}
+	formPostTmpl, err := template.ParseFS(formPostTemplateFS, "template/form_post.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse form_post template: %w", err)
+	}
 	return &OAuth2Controller{
 		clientSvc:        clientSvc,
 		authCodeSvc:      authCodeSvc,
@@ -154,7 +241,13 @@ func NewOAuth2Controller(
 		consentTmpl:      consentTmpl,
 		deviceTmpl:       deviceTmpl,
 		resultTmpl:       resultTmpl,
+		formPostTmpl:     formPostTmpl,
 		logger:           logger,
+		authOptions: authMiddleware.AuthConfigOptions{
+			LoginURL:         "/login",
+			EnableCookieAuth: true,
+			AuthCookieName:   authCookieName,
+		},
 	}, nil
 }
 
@@ -178,6 +271,40 @@ type OAuth2ControllerConfig struct {
 	PermissionFetcher          AccountPermissionFetcher
 	IncludeUserRoles           bool
 	IncludeUserPermissions     bool
+	IDTokenHintVerifier        IDTokenHintVerifier
+	// JWTBearerVerifier enables the jwt-bearer grant. Nil disables it.
+	JWTBearerVerifier JWTBearerVerifier
+	// DPoPVerifier validates DPoP proofs at the token endpoint. Nil ignores DPoP headers.
+	DPoPVerifier authMiddleware.DPoPProofVerifier
+	// ClientRegistrar enables the dynamic client registration endpoints. Nil disables them.
+	ClientRegistrar ClientRegistrar
+	// RequestObjectVerifier enables the request parameter of /authorize and /par and
+	// request_uri values pointing at client-hosted request objects (RFC 9101). Nil rejects
+	// them with request_not_supported / request_uri_not_supported.
+	RequestObjectVerifier RequestObjectVerifier
+	// JARMSigner enables the JWT response modes jwt, query.jwt and
+	// form_post.jwt (JARM). Nil rejects them.
+	JARMSigner AuthorizationResponseSigner
+	// IntrospectionSigner returns introspection responses as signed JWTs to resource servers
+	// that accept application/token-introspection+jwt (RFC 9701). Nil always returns JSON.
+	IntrospectionSigner IntrospectionResponseSigner
+	// BackchannelAuthSvc and BackchannelUserNotifier enable client-initiated backchannel
+	// authentication (CIBA): the /bc-authorize endpoint, the approval API and the ciba
+	// grant. Nil disables it. BackchannelClientNotifier is required for ping and push
+	// clients, and LoginHintResolver for the login_hint parameter.
+	BackchannelAuthSvc        BackchannelAuthManager
+	BackchannelUserNotifier   BackchannelUserNotifier
+	BackchannelClientNotifier BackchannelClientNotifier
+	LoginHintResolver         LoginHintResolver
+	// SubjectIdentifiers maps the sub of introspected tokens to the subject identifier the
+	// token's client knows the account by. Nil returns account IDs.
+	SubjectIdentifiers SubjectIdentifier
+	// Resources enables the resource parameter (RFC 8707) of authorization and token
+	// requests. Nil rejects it with invalid_target.
+	Resources ResourceResolver
+	// AuthOptions configures how GET /authorize authenticates the browser session and
+	// where unauthenticated users are sent to log in. Zero value keeps the defaults.
+	AuthOptions authMiddleware.AuthConfigOptions
 }
 
 // NewOAuth2ControllerFromConfig creates a new OAuth2 controller from a config struct.
@@ -197,6 +324,24 @@ func NewOAuth2ControllerFromConfig(cfg OAuth2ControllerConfig) (*OAuth2Controlle
 	c.permissionFetcher = cfg.PermissionFetcher
 	c.includeUserRoles = cfg.IncludeUserRoles
 	c.includeUserPermissions = cfg.IncludeUserPermissions
+	c.idTokenHintVerifier = cfg.IDTokenHintVerifier
+	c.jwtBearer = cfg.JWTBearerVerifier
+	c.dpop = cfg.DPoPVerifier
+	c.registrar = cfg.ClientRegistrar
+	c.requestObjects = cfg.RequestObjectVerifier
+	c.jarm = cfg.JARMSigner
+	c.introspectionSigner = cfg.IntrospectionSigner
+	if cfg.BackchannelAuthSvc != nil && cfg.BackchannelUserNotifier != nil {
+		c.backchannelAuth = cfg.BackchannelAuthSvc
+		c.backchannelUserNotifier = cfg.BackchannelUserNotifier
+	}
+	c.backchannelClientNotifier = cfg.BackchannelClientNotifier
+	c.loginHintResolver = cfg.LoginHintResolver
+	c.subjectIdentifiers = cfg.SubjectIdentifiers
+	c.resources = cfg.Resources
+	if cfg.AuthOptions != (authMiddleware.AuthConfigOptions{}) {
+		c.authOptions = cfg.AuthOptions
+	}
 	return c, nil
 }
 
@@ -207,6 +352,32 @@ func NewOAuth2ControllerFromConfig(cfg OAuth2ControllerConfig) (*OAuth2Controlle
 // but serves inline within handler methods because OAuth2 endpoints may serve both
 // authenticated and unauthenticated flows on the same route.
 func (c *OAuth2Controller) authenticateRequest(ctx *gin.Context) (string, bool) {
+	claims, ok := c.authenticateBearer(ctx)
+	if !ok {
+		return "", false
+	}
+	return claims.AccountID, true
+}
+
+// authenticateFirstPartyRequest is authenticateRequest for APIs that only the user's own
+// session may call, such as approving backchannel authentication requests. Access tokens
+// issued to OAuth clients are refused with 403, so a client holding a token for the user
+// cannot approve its own requests.
+func (c *OAuth2Controller) authenticateFirstPartyRequest(ctx *gin.Context) (string, bool) {
+	claims, ok := c.authenticateBearer(ctx)
+	if !ok {
+		return "", false
+	}
+	if claims.ClientID != "" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "forbidden", "error_description": "tokens issued to clients cannot be used here"})
+		return "", false
+	}
+	return claims.AccountID, true
+}
+
+// authenticateBearer validates the access token of the request and returns its claims, or
+// writes an error response.
+func (c *OAuth2Controller) authenticateBearer(ctx *gin.Context) (*tokenDomain.AccessTokenClaims, bool) {
 	claims, err := authMiddleware.ValidateBearerToken(ctx, c.tokenSvc, c.sessionValidator)
 	if err != nil {
 		if errors.Is(err, authMiddleware.ErrTokenScopeNotAllowed) {
@@ -214,15 +385,28 @@ func (c *OAuth2Controller) authenticateRequest(ctx *gin.Context) (string, bool)
 		} else {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "error_description": "invalid or expired token"})
 		}
-		return "", false
+		return nil, false
 	}
-	return claims.AccountID, true
+	return claims, true
+}
+
+// optionalAuthentication authenticates GET /oauth2/authorize when credentials are present.
+// Unlike JWTAuthMiddleware it never rejects the request: Authorize decides whether to
+// redirect to the login page or, for prompt=none, return login_required to the client.
+func (c *OAuth2Controller) optionalAuthentication(ctx *gin.Context) {
+	claims, err := authMiddleware.ValidateBearerTokenWithConfig(ctx, c.tokenSvc, c.sessionValidator, c.authOptions)
+	if err == nil {
+		ctx.Set(middleware.ContextKeyAccountID, claims.AccountID)
+		ctx.Set(middleware.ContextKeyClaims, claims)
+	}
+	ctx.Next()
 }
 
 // RegisterRoutes registers OAuth2 routes.
 // Rate limit middleware arguments are optional and applied per endpoint.
+// GET /authorize authenticates itself (see optionalAuthentication) so it can honour OIDC prompt.
 func (c *OAuth2Controller) RegisterRoutes(rg *gin.RouterGroup, authMiddleware, tokenLimit, introspectLimit, deviceCodeLimit, deviceUserLimit gin.HandlerFunc) {
-	rg.GET("/authorize", authMiddleware, c.Authorize)
+	rg.GET("/authorize", c.optionalAuthentication, c.Authorize)
 	rg.POST("/authorize", authMiddleware, c.SubmitConsent)
 
 	tokenHandlers := []gin.HandlerFunc{c.Token}
@@ -242,6 +426,12 @@ func (c *OAuth2Controller) RegisterRoutes(rg *gin.RouterGroup, authMiddleware, t
 	}
 	rg.POST("/introspect", introspectHandlers...)
 
+	parHandlers := []gin.HandlerFunc{c.PushedAuthorizationRequest}
+	if tokenLimit != nil {
+		parHandlers = []gin.HandlerFunc{tokenLimit, c.PushedAuthorizationRequest}
+	}
+	rg.POST("/par", parHandlers...)
+
 	deviceCodeHandlers := []gin.HandlerFunc{c.DeviceCodeRequest}
 	if deviceCodeLimit != nil {
 		deviceCodeHandlers = []gin.HandlerFunc{deviceCodeLimit, c.DeviceCodeRequest}
@@ -254,11 +444,35 @@ func (c *OAuth2Controller) RegisterRoutes(rg *gin.RouterGroup, authMiddleware, t
 		deviceUserHandlers = []gin.HandlerFunc{deviceUserLimit, c.DeviceUserSubmit}
 	}
 	rg.POST("/device", deviceUserHandlers...)
+
+	if c.backchannelAuth != nil {
+		bcAuthorizeHandlers := []gin.HandlerFunc{c.BackchannelAuthentication}
+		if tokenLimit != nil {
+			bcAuthorizeHandlers = []gin.HandlerFunc{tokenLimit, c.BackchannelAuthentication}
+		}
+		rg.POST("/bc-authorize", bcAuthorizeHandlers...)
+		// The approval API is outside /bc-authorize so it keeps CSRF protection for
+		// cookie-authenticated callers.
+		rg.GET("/backchannel/requests/:id", authMiddleware, c.GetBackchannelAuthRequest)
+		rg.POST("/backchannel/requests/:id", authMiddleware, c.SubmitBackchannelAuthRequest)
+	}
+
+	if c.registrar != nil {
+		registerHandlers := []gin.HandlerFunc{c.RegisterClient}
+		if tokenLimit != nil {
+			registerHandlers = []gin.HandlerFunc{tokenLimit, c.RegisterClient}
+		}
+		rg.POST("/register", registerHandlers...)
+		rg.GET("/register/:client_id", c.GetRegisteredClient)
+		rg.PUT("/register/:client_id", c.UpdateRegisteredClient)
+		rg.DELETE("/register/:client_id", c.DeleteRegisteredClient)
+	}
 }
 
 // redirectWithCode builds the OAuth2 authorization redirect URL with the
-// authorization code, optional state, and optional OIDC iss parameter.
-func redirectWithCode(ctx *gin.Context, redirectURI, code, state, issuer string) {
+// authorization code, optional state, optional OIDC session_state, and optional
+// OIDC iss parameter.
+func redirectWithCode(ctx *gin.Context, redirectURI, code, state, sessionState, issuer string) {
 	parsedURL, err := url.Parse(redirectURI)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_redirect_uri"})
@@ -269,6 +483,9 @@ func redirectWithCode(ctx *gin.Context, redirectURI, code, state, issuer string)
 	if state != "" {
 		params.Set("state", state)
 	}
+	if sessionState != "" {
+		params.Set("session_state", sessionState)
+	}
 	// OIDC Core Section 3.1.2.6: iss parameter MUST be included if advertised in discovery.
 	if issuer != "" {
 		params.Set("iss", issuer)
@@ -277,6 +494,30 @@ func redirectWithCode(ctx *gin.Context, redirectURI, code, state, issuer string)
 	ctx.Redirect(http.StatusFound, parsedURL.String())
 }
 
+// redirectWithError redirects to the client's redirect URI with an OAuth2 error
+// (RFC 6749 §4.1.2.1), preserving state and the RFC 9207 iss parameter.
+// Callers must have validated redirectURI against the client registration.
+func redirectWithError(ctx *gin.Context, redirectURI, errCode, description, state, issuer string) {
+	parsedURL, err := url.Parse(redirectURI)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_redirect_uri"})
+		return
+	}
+	params := parsedURL.Query()
+	params.Set("error", errCode)
+	if description != "" {
+		params.Set("error_description", description)
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	if issuer != "" {
+		params.Set("iss", issuer)
+	}
+	parsedURL.RawQuery = params.Encode()
+	ctx.Redirect(http.StatusFound, parsedURL.String())
+}
+
 // splitScope splits a space-delimited scope string into individual scope tokens.
 // Empty input returns nil; consecutive spaces are collapsed.
 func splitScope(scope string) []string {
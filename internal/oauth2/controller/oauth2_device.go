@@ -38,6 +38,9 @@ type DeviceCodeRequestParams struct {
 	ClientID     string `form:"client_id" binding:"required,max=128"`
 	ClientSecret string `form:"client_secret" binding:"max=256"`
 	Scope        string `form:"scope" binding:"max=2048"`
+
+	ClientAssertionType string `form:"client_assertion_type" binding:"max=128"`
+	ClientAssertion     string `form:"client_assertion" binding:"max=8192"`
 }
 
 // DeviceCodeRequest POST /oauth2/device/code
@@ -47,6 +50,9 @@ func (c *OAuth2Controller) DeviceCodeRequest(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "client_id is required"})
 		return
 	}
+	if !resolveClientAssertion(ctx, req.ClientAssertionType, req.ClientAssertion, &req.ClientID, req.ClientSecret != "") {
+		return
+	}
 
 	client, err := c.clientSvc.FindByClientID(ctx, req.ClientID)
 	if err != nil {
@@ -62,7 +68,7 @@ func (c *OAuth2Controller) DeviceCodeRequest(ctx *gin.Context) {
 	}
 
 	// Client authentication for confidential clients (RFC 8628 §3.1)
-	if authErr := c.clientAuth.AuthenticateClient(client, req.ClientSecret); authErr != nil {
+	if authErr := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); authErr != nil {
 		controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
 		return
 	}
@@ -79,7 +85,7 @@ func (c *OAuth2Controller) DeviceCodeRequest(ctx *gin.Context) {
 		}
 	}
 
-	dc, err := c.deviceCodeSvc.CreateDeviceCode(ctx, req.ClientID, scopes)
+	dc, err := c.deviceCodeSvc.CreateDeviceCode(ctx, req.ClientID, scopes, client.DeviceCodeExpiry())
 	if err != nil {
 		c.logger.Error("Failed to create device code", zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
@@ -250,10 +256,13 @@ func (c *OAuth2Controller) handleDeviceCodeGrant(ctx *gin.Context, req *TokenReq
 	}
 
 	// Client authentication for confidential clients
-	if authErr := c.clientAuth.AuthenticateClient(client, req.ClientSecret); authErr != nil {
+	if authErr := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); authErr != nil {
 		controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
 		return
 	}
+	if !c.bindSenderConstraints(ctx, client, req) {
+		return
+	}
 
 	dc, err := c.deviceCodeSvc.GetDeviceCode(ctx, req.DeviceCode)
 	if err != nil {
@@ -298,6 +307,10 @@ func (c *OAuth2Controller) handleDeviceCodeGrant(ctx *gin.Context, req *TokenReq
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "account is not active"})
 		return
 	}
+	target, ok := c.resourceTarget(ctx, req.Resource, nil, dc.Scopes)
+	if !ok {
+		return
+	}
 
 	// Atomically claim the device code (status authorized→used) to prevent double-use.
 	// The Lua script handles all status validation atomically, so no non-atomic
@@ -339,13 +352,14 @@ func (c *OAuth2Controller) handleDeviceCodeGrant(ctx *gin.Context, req *TokenReq
 		}
 	}
 
-	accessToken, err := c.tokenSvc.GenerateAccessToken(&tokenDomain.AccessTokenClaims{
+	accessToken, accessTokenScope, accessTokenLifetime, err := c.issueAccessToken(ctx, client, &tokenDomain.AccessTokenClaims{
 		AccountID:   dc.AccountID,
 		Scope:       strings.Join(dc.Scopes, " "),
 		ClientID:    dc.ClientID,
 		Roles:       roles,
 		Permissions: permissions,
-	})
+		Cnf:         tokenConfirmation(req),
+	}, target)
 	if err != nil {
 		c.logger.Error("Failed to generate access token for device code", zap.Error(err), zap.String("client_id", dc.ClientID))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
@@ -354,8 +368,12 @@ func (c *OAuth2Controller) handleDeviceCodeGrant(ctx *gin.Context, req *TokenReq
 
 	var refreshToken *tokenDomain.RefreshToken
 	var refreshTokenStr string
-	if client.HasGrantType(oauth2Domain.GrantTypeRefreshToken) {
-		refreshToken, err = c.tokenSvc.GenerateRefreshToken(ctx, dc.AccountID, dc.ClientID, "", strings.Join(dc.Scopes, " "))
+	if issuesRefreshToken(client, dc.Scopes, "") {
+		var resources []string
+		if target != nil {
+			resources = target.Audience
+		}
+		refreshToken, err = c.tokenSvc.GenerateRefreshToken(ctx, dc.AccountID, dc.ClientID, "", strings.Join(dc.Scopes, " "), nil, resources, nil, refreshTokenConfirmation(client, req), refreshTokenPolicy(client))
 		if err != nil {
 			c.logger.Error("Failed to generate refresh token for device code", zap.Error(err), zap.String("client_id", dc.ClientID))
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
@@ -364,16 +382,16 @@ func (c *OAuth2Controller) handleDeviceCodeGrant(ctx *gin.Context, req *TokenReq
 		refreshTokenStr = refreshToken.Token
 	}
 
-	idToken, ok := c.maybeGenerateIDToken(ctx, dc.AccountID, dc.ClientID, dc.Scopes, "", dc.AuthorizedAt, accessToken, nil)
+	idToken, ok := c.maybeGenerateIDToken(ctx, dc.AccountID, dc.ClientID, dc.Scopes, nil, "", dc.AuthorizedAt, accessToken, nil)
 	if !ok {
 		return
 	}
 
 	response := gin.H{
 		"access_token": accessToken,
-		"token_type":   "Bearer",
-		"expires_in":   int(c.tokenSvc.AccessExpiry().Seconds()),
-		"scope":        strings.Join(dc.Scopes, " "),
+		"token_type":   accessTokenType(req.dpopJKT),
+		"expires_in":   int(accessTokenLifetime.Seconds()),
+		"scope":        accessTokenScope,
 	}
 	if idToken != "" {
 		response["id_token"] = idToken
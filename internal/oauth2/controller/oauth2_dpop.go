@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	tokenService "github.com/rushairer/gosso/internal/token/service"
+)
+
+// verifyTokenDPoPProof validates the DPoP header of a token request (RFC 9449 §5) and
+// records the thumbprint of the proof key in req. Requests without a DPoP header are
+// left unbound. It writes an error response and returns false if the proof is invalid.
+func (c *OAuth2Controller) verifyTokenDPoPProof(ctx *gin.Context, req *TokenRequest) bool {
+	proofs := ctx.Request.Header.Values("DPoP")
+	if len(proofs) == 0 || c.dpop == nil {
+		return true
+	}
+	if len(proofs) > 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_dpop_proof", "error_description": "exactly one DPoP header is allowed"})
+		return false
+	}
+	if isCookieSessionRequest(ctx) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "DPoP is not supported for cookie sessions"})
+		return false
+	}
+
+	jkt, err := c.dpop.VerifyProof(ctx.Request.Context(), proofs[0], http.MethodPost, ctx.Request.URL.Path, "")
+	c.setDPoPNonce(ctx)
+	if errors.Is(err, tokenService.ErrDPoPNonceRequired) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "use_dpop_nonce", "error_description": "authorization server requires nonce in DPoP proof"})
+		return false
+	}
+	if err != nil {
+		c.logger.Debug("DPoP proof rejected at token endpoint", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_dpop_proof", "error_description": "invalid DPoP proof"})
+		return false
+	}
+	req.dpopJKT = jkt
+	return true
+}
+
+// setDPoPNonce provides a fresh server nonce in the DPoP-Nonce header when nonces are
+// required (RFC 9449 §8.2).
+func (c *OAuth2Controller) setDPoPNonce(ctx *gin.Context) {
+	if !c.dpop.NonceRequired() {
+		return
+	}
+	nonce, err := c.dpop.Nonce(ctx.Request.Context())
+	if err != nil {
+		c.logger.Warn("Failed to issue DPoP nonce", zap.Error(err))
+		return
+	}
+	ctx.Header("DPoP-Nonce", nonce)
+}
+
+// requireDPoPProof rejects token requests without a DPoP proof from clients registered
+// with dpop_bound_access_tokens (RFC 9449 §5.2). It writes an error response and
+// returns false if the client requires DPoP and the request carries no proof.
+func requireDPoPProof(ctx *gin.Context, client *oauth2Domain.OAuth2Client, req *TokenRequest) bool {
+	if client.DPoPBoundAccessTokens && req.dpopJKT == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_dpop_proof", "error_description": "DPoP proof is required for this client"})
+		return false
+	}
+	return true
+}
+
+// tokenConfirmation returns the cnf claim binding an access token to the DPoP key
+// (RFC 9449 §6) and client certificate (RFC 8705 §3) of req, or nil for a bearer token.
+func tokenConfirmation(req *TokenRequest) *tokenDomain.ConfirmationClaim {
+	if req.dpopJKT == "" && req.certThumbprint == "" {
+		return nil
+	}
+	return &tokenDomain.ConfirmationClaim{JKT: req.dpopJKT, X5TS256: req.certThumbprint}
+}
+
+// refreshTokenConfirmation returns the cnf a new refresh token is bound to. Only refresh
+// tokens of public clients are bound; confidential clients are already bound to their
+// credentials (RFC 9449 §5, RFC 8705 §4).
+func refreshTokenConfirmation(client *oauth2Domain.OAuth2Client, req *TokenRequest) *tokenDomain.ConfirmationClaim {
+	if client.IsConfidential {
+		return nil
+	}
+	return tokenConfirmation(req)
+}
+
+// accessTokenType returns the token_type of an access token bound to jkt (RFC 9449 §5).
+func accessTokenType(jkt string) string {
+	if jkt == "" {
+		return "Bearer"
+	}
+	return "DPoP"
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/controllerutil"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+)
+
+// handleJWTBearerGrant implements the JWT authorization grant (RFC 7523 §2.1). A JWT signed
+// by a trusted issuer is exchanged for an access token for the account or client its
+// subject is mapped to. Client authentication is optional (RFC 7523 §3.1), except for
+// subjects mapped to an account, whose token must be bound to an authenticated client.
+// No refresh token is issued: the holder presents a fresh assertion instead.
+func (c *OAuth2Controller) handleJWTBearerGrant(ctx *gin.Context, req *TokenRequest) {
+	if c.jwtBearer == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
+		return
+	}
+	if req.Assertion == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "assertion is required"})
+		return
+	}
+
+	var client *oauth2Domain.OAuth2Client
+	if req.ClientID != "" {
+		var err error
+		client, err = c.clientSvc.FindByClientID(ctx, req.ClientID)
+		if err != nil {
+			c.clientAuth.DummyAuthenticate()
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+			return
+		}
+		if !client.IsConfidential {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "jwt-bearer grant requires confidential client"})
+			return
+		}
+		if !client.HasGrantType(oauth2Domain.GrantTypeJWTBearer) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "jwt-bearer grant not allowed for this client"})
+			return
+		}
+		if authErr := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); authErr != nil {
+			controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
+			return
+		}
+	}
+
+	grant, err := c.jwtBearer.VerifyJWTBearer(ctx.Request.Context(), req.Assertion, c.clientAssertionAudiences(ctx))
+	if err != nil {
+		if errors.Is(err, oauth2Service.ErrJWTBearerGrantUnavailable) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
+			return
+		}
+		if errors.Is(err, oauth2Service.ErrJWTBearerReplayCheckUnavailable) {
+			c.logger.Error("JWT bearer replay check unavailable", zap.Error(err))
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "server_error", "error_description": "assertion replay check unavailable, please try again"})
+			return
+		}
+		c.logger.Debug("JWT bearer assertion rejected", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "invalid assertion"})
+		return
+	}
+
+	accountID := grant.AccountID
+	if grant.ClientID != "" {
+		// The subject acts as a client, as with client_credentials.
+		if client == nil {
+			client, err = c.clientSvc.FindByClientID(ctx, grant.ClientID)
+			if err != nil {
+				c.logger.Warn("JWT bearer subject is mapped to an unknown client", zap.String("issuer", grant.Issuer), zap.String("client_id", grant.ClientID))
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "assertion subject is not mapped to a valid client"})
+				return
+			}
+			if !client.HasGrantType(oauth2Domain.GrantTypeJWTBearer) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "jwt-bearer grant not allowed for this client"})
+				return
+			}
+		} else if client.ClientID != grant.ClientID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "assertion subject is not mapped to this client"})
+			return
+		}
+		accountID = client.AccountID
+	} else if client == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "client authentication is required for this assertion subject"})
+		return
+	}
+
+	if !c.bindSenderConstraints(ctx, client, req) {
+		return
+	}
+
+	scopes := client.ValidateScope(splitScope(req.Scope))
+	if len(grant.Scopes) > 0 {
+		scopes = slices.DeleteFunc(scopes, func(s string) bool { return !slices.Contains(grant.Scopes, s) })
+	}
+	if len(scopes) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope", "error_description": "scope parameter is required and must contain scopes allowed for this client and assertion subject"})
+		return
+	}
+
+	if !c.accountValidator.IsAccountActive(ctx, accountID) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "account is not active"})
+		return
+	}
+	target, ok := c.resourceTarget(ctx, req.Resource, nil, scopes)
+	if !ok {
+		return
+	}
+
+	var roles, permissions []string
+	if grant.AccountID != "" {
+		if c.includeUserRoles && c.roleFetcher != nil {
+			var rolesErr error
+			roles, rolesErr = c.roleFetcher.GetAccountRoles(ctx, accountID)
+			if rolesErr != nil {
+				c.logger.Error("Failed to fetch roles for account", zap.Error(rolesErr), zap.String("account_id", accountID))
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+				return
+			}
+		}
+		if c.includeUserPermissions && c.permissionFetcher != nil {
+			var permErr error
+			permissions, permErr = c.permissionFetcher.GetAccountPermissions(ctx, accountID)
+			if permErr != nil {
+				c.logger.Error("Failed to fetch permissions for account", zap.Error(permErr), zap.String("account_id", accountID))
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+				return
+			}
+		}
+	}
+
+	claims := &tokenDomain.AccessTokenClaims{
+		AccountID:   accountID,
+		Scope:       strings.Join(scopes, " "),
+		ClientID:    client.ClientID,
+		Roles:       roles,
+		Permissions: permissions,
+		Cnf:         tokenConfirmation(req),
+	}
+	if grant.ClientID != "" {
+		// The client is the subject of its own tokens (RFC 9068 §2.2).
+		claims.Subject = client.ClientID
+	}
+	accessToken, accessTokenScope, accessTokenLifetime, err := c.issueAccessToken(ctx, client, claims, target)
+	if err != nil {
+		c.logger.Error("Failed to generate access token for jwt-bearer grant", zap.Error(err), zap.String("client_id", client.ClientID))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+
+	c.logger.Info("Issued access token for JWT bearer assertion",
+		zap.String("issuer", grant.Issuer),
+		zap.String("subject", grant.Subject),
+		zap.String("client_id", client.ClientID),
+		zap.String("account_id", accountID),
+	)
+
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusOK, gin.H{
+		"access_token": accessToken,
+		"token_type":   accessTokenType(req.dpopJKT),
+		"expires_in":   int(accessTokenLifetime.Seconds()),
+		"scope":        accessTokenScope,
+	})
+}
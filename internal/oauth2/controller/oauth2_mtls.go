@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rushairer/gosso/internal/mtls"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// bindSenderConstraints checks the sender constraints an authenticated client must meet
+// before tokens are issued and records them in req: a DPoP proof for clients registered
+// with dpop_bound_access_tokens (RFC 9449 §5.2), and the client certificate for clients
+// using mutual-TLS authentication or tls_client_certificate_bound_access_tokens (RFC 8705
+// §3). It writes an error response and returns false if a constraint is not met.
+func (c *OAuth2Controller) bindSenderConstraints(ctx *gin.Context, client *oauth2Domain.OAuth2Client, req *TokenRequest) bool {
+	if !requireDPoPProof(ctx, client, req) {
+		return false
+	}
+	if !client.UsesTLSClientAuth() && !client.TLSClientCertificateBoundAccessTokens {
+		return true
+	}
+	cert := mtls.CertificateFromContext(ctx.Request.Context())
+	if cert == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "client certificate is required for this client"})
+		return false
+	}
+	req.certThumbprint = mtls.Thumbprint(cert)
+	return true
+}
+
+// hasClientCertificate reports whether the request presented a TLS client certificate,
+// which may authenticate the client in place of a secret or assertion (RFC 8705 §2).
+func hasClientCertificate(ctx *gin.Context) bool {
+	return mtls.CertificateFromContext(ctx.Request.Context()) != nil
+}
@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/cache"
+	"github.com/rushairer/gosso/internal/controllerutil"
+)
+
+// parRequestTTL is the lifetime of a pushed authorization request. RFC 9126 §2.2 recommends
+// a short lifetime; 90 seconds covers a login redirect while keeping the window for replay small.
+const parRequestTTL = 90 * time.Second
+const parRequestKeyPrefix = "par_request:"
+
+// parRequestURIPrefix is the URN namespace for request_uri values issued by the PAR endpoint (RFC 9126 §2.2).
+const parRequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+// PushedAuthorizationRequest POST /oauth2/par (RFC 9126)
+//
+// Confidential clients push the full authorization request over the back channel and
+// receive a one-time request_uri that replaces the query parameters on /oauth2/authorize,
+// so PKCE challenges, nonces and scopes never travel through the browser.
+func (c *OAuth2Controller) PushedAuthorizationRequest(ctx *gin.Context) {
+	// Defense-in-depth: reject oversized form bodies early, before parsing.
+	if ctx.Request.ContentLength > oauth2MaxFormBodySize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":             "invalid_request",
+			"error_description": "request body too large",
+		})
+		return
+	}
+
+	// RFC 9126 §2.1: the request MUST use application/x-www-form-urlencoded.
+	contentType := ctx.GetHeader("Content-Type")
+	if contentType == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "Content-Type header is required"})
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/x-www-form-urlencoded" {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "invalid_request", "error_description": "Content-Type must be application/x-www-form-urlencoded"})
+		return
+	}
+
+	if err := ctx.Request.ParseForm(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	form := ctx.Request.PostForm
+
+	// RFC 9126 §2.1: request_uri MUST NOT be provided to the PAR endpoint.
+	if form.Has("request_uri") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "request_uri is not allowed in a pushed authorization request"})
+		return
+	}
+
+	// Basic auth takes precedence over body parameters per RFC 6749 §2.3.1.
+	clientID, clientSecret, hasBasicAuth := ctx.Request.BasicAuth()
+	if !hasBasicAuth {
+		clientID = form.Get("client_id")
+		clientSecret = form.Get("client_secret")
+	}
+	assertion := form.Get("client_assertion")
+	if !resolveClientAssertion(ctx, form.Get("client_assertion_type"), assertion, &clientID, hasBasicAuth || clientSecret != "") {
+		return
+	}
+	if clientID == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "client authentication required"})
+		return
+	}
+
+	client, err := c.clientSvc.FindByClientID(ctx, clientID)
+	if err != nil {
+		// DummyAuthenticate to normalize timing and prevent client ID enumeration.
+		c.clientAuth.DummyAuthenticate()
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+	if authErr := c.authenticateClient(ctx, client, clientSecret, assertion); authErr != nil {
+		controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
+		return
+	} else if !client.IsConfidential {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "public clients are not allowed to push authorization requests"})
+		return
+	}
+
+	params := authorizeParamsFromValues(form)
+	// RFC 9126 §2.1: a client_id body parameter must identify the authenticated client.
+	if params.ClientID != "" && params.ClientID != client.ClientID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "client_id does not match the authenticated client"})
+		return
+	}
+	params.ClientID = client.ClientID
+	if requestObject := form.Get("request"); requestObject != "" {
+		var paramErr *authorizeParamError
+		if params, paramErr = c.applyRequestObject(ctx, params, client, requestObject); paramErr != nil {
+			writeAuthorizeParamError(ctx, paramErr)
+			return
+		}
+	}
+	if paramErr := checkSignedRequestObjectRequirement(&params, client); paramErr != nil {
+		writeAuthorizeParamError(ctx, paramErr)
+		return
+	}
+
+	if !client.HasGrantType("authorization_code") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "authorization_code grant not allowed for this client"})
+		return
+	}
+	if paramErr := params.validate(); paramErr != nil {
+		writeAuthorizeParamError(ctx, paramErr)
+		return
+	}
+	if paramErr := c.validateAuthorizeParamsForClient(&params, client); paramErr != nil {
+		writeAuthorizeParamError(ctx, paramErr)
+		return
+	}
+
+	if c.redis == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "server_error", "error_description": "authorization request storage unavailable"})
+		return
+	}
+	requestURI, err := c.storePushedAuthorizationRequest(ctx, params)
+	if err != nil {
+		c.logger.Error("Failed to store pushed authorization request in Redis", zap.Error(err))
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "server_error", "error_description": "unable to store authorization request, please try again"})
+		return
+	}
+
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusCreated, gin.H{
+		"request_uri": requestURI,
+		"expires_in":  int(parRequestTTL.Seconds()),
+	})
+}
+
+// storePushedAuthorizationRequest persists params for parRequestTTL and returns the request_uri.
+func (c *OAuth2Controller) storePushedAuthorizationRequest(ctx *gin.Context, params authorizeParams) (string, error) {
+	if c.redis == nil {
+		return "", errors.New("authorization request storage unavailable")
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("marshal pushed authorization request: %w", err)
+	}
+	requestID := uuid.New().String()
+	if err := c.redis.Set(ctx, parRequestKeyPrefix+requestID, string(data), parRequestTTL); err != nil {
+		return "", fmt.Errorf("store pushed authorization request: %w", err)
+	}
+	return parRequestURIPrefix + requestID, nil
+}
+
+// resolveAuthorizeParams returns the authorization request parameters for GET /oauth2/authorize.
+// When a request_uri issued by the PAR endpoint is present the parameters are loaded (and
+// consumed) from the pushed authorization request; per RFC 9126 §4 any other query parameters
+// except client_id are ignored. Other request_uri values reference a client-hosted request
+// object (RFC 9101 §5.2), which Authorize fetches once the client is known.
+// The second return value reports whether the parameters came from PAR.
+// On failure an error response has already been written.
+func (c *OAuth2Controller) resolveAuthorizeParams(ctx *gin.Context) (authorizeParams, bool, bool) {
+	requestURI := ctx.Query("request_uri")
+	if requestURI == "" {
+		return authorizeParamsFromValues(ctx.Request.URL.Query()), false, true
+	}
+	if ctx.Query("request") != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "request and request_uri must not both be present"})
+		return authorizeParams{}, false, false
+	}
+
+	requestID, found := strings.CutPrefix(requestURI, parRequestURIPrefix)
+	if !found {
+		return authorizeParamsFromValues(ctx.Request.URL.Query()), false, true
+	}
+	if requestID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request_uri", "error_description": "invalid or expired request_uri"})
+		return authorizeParams{}, false, false
+	}
+	if c.redis == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "server_error", "error_description": "authorization request storage unavailable"})
+		return authorizeParams{}, false, false
+	}
+
+	// The request is read first and only consumed once the client_id matches, so a request
+	// naming another client cannot burn a request_uri it does not own.
+	key := parRequestKeyPrefix + requestID
+	data, ok := c.loadPushedAuthorizationRequest(ctx, c.redis.Get, key)
+	if !ok {
+		return authorizeParams{}, false, false
+	}
+
+	var params authorizeParams
+	if err := json.Unmarshal([]byte(data), &params); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request_uri", "error_description": "invalid authorization request data"})
+		return authorizeParams{}, false, false
+	}
+	// RFC 9126 §4: the client_id query parameter must match the client that pushed the request.
+	if ctx.Query("client_id") != params.ClientID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request_uri", "error_description": "request_uri was not issued to this client"})
+		return authorizeParams{}, false, false
+	}
+
+	// One-time use: only the request whose GetDel still sees the stored value may use it, so
+	// a leaked request_uri cannot be replayed even by concurrent requests.
+	consumed, ok := c.loadPushedAuthorizationRequest(ctx, c.redis.GetDel, key)
+	if !ok {
+		return authorizeParams{}, false, false
+	}
+	if consumed != data {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request_uri", "error_description": "invalid or expired request_uri"})
+		return authorizeParams{}, false, false
+	}
+	return params, true, true
+}
+
+// loadPushedAuthorizationRequest reads a pushed authorization request with load. It writes an
+// error response and returns false if the request is missing or the store is unavailable.
+func (c *OAuth2Controller) loadPushedAuthorizationRequest(ctx *gin.Context, load func(context.Context, string) (string, error), key string) (string, bool) {
+	data, err := load(ctx, key)
+	if err == nil && data != "" {
+		return data, true
+	}
+	if err == nil || errors.Is(err, cache.ErrKeyNotFound) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request_uri", "error_description": "invalid or expired request_uri"})
+		return "", false
+	}
+	c.logger.Error("Failed to load pushed authorization request from Redis", zap.Error(err))
+	ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "server_error", "error_description": "authorization request storage error"})
+	return "", false
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/controllerutil"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
+)
+
+// ClientRegistrar implements dynamic client registration (RFC 7591) and client
+// configuration (RFC 7592).
+type ClientRegistrar interface {
+	RegisterClient(ctx context.Context, initialAccessToken string, md *oauth2Service.ClientMetadata) (*oauth2Service.ClientRegistration, error)
+	GetClient(ctx context.Context, clientID, registrationAccessToken string) (*oauth2Domain.OAuth2Client, error)
+	UpdateClient(ctx context.Context, clientID, registrationAccessToken string, md *oauth2Service.ClientMetadata) (*oauth2Domain.OAuth2Client, error)
+	DeleteClient(ctx context.Context, clientID, registrationAccessToken string) error
+}
+
+// clientInformationResponse is the client information response (RFC 7591 §3.2.1,
+// RFC 7592 §3). Secrets are only included right after registration.
+type clientInformationResponse struct {
+	oauth2Service.ClientMetadata
+	ClientSecret            string `json:"client_secret,omitempty"`
+	ClientIDIssuedAt        int64  `json:"client_id_issued_at"`
+	ClientSecretExpiresAt   *int64 `json:"client_secret_expires_at,omitempty"`
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri"`
+}
+
+// RegisterClient POST /oauth2/register (RFC 7591 §3)
+//
+// Registers a client with the JSON metadata in the body. The request must carry an
+// administrator-issued initial access token as a Bearer token.
+func (c *OAuth2Controller) RegisterClient(ctx *gin.Context) {
+	controllerutil.SetNoCacheHeaders(ctx)
+	token, ok := registrationBearerToken(ctx)
+	if !ok {
+		return
+	}
+	var md oauth2Service.ClientMetadata
+	if err := ctx.ShouldBindJSON(&md); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": oauth2Service.RegistrationErrorInvalidClientMetadata, "error_description": "request body must be a JSON object of client metadata"})
+		return
+	}
+	// client_id is assigned by the server.
+	md.ClientID = ""
+
+	reg, err := c.registrar.RegisterClient(ctx.Request.Context(), token, &md)
+	if err != nil {
+		c.writeRegistrationError(ctx, err)
+		return
+	}
+	resp := c.clientInformation(reg.Client)
+	resp.ClientSecret = reg.ClientSecret
+	if reg.ClientSecret != "" {
+		// The secret does not expire (RFC 7591 §3.2.1).
+		never := int64(0)
+		resp.ClientSecretExpiresAt = &never
+	}
+	resp.RegistrationAccessToken = reg.RegistrationAccessToken
+	ctx.JSON(http.StatusCreated, resp)
+}
+
+// GetRegisteredClient GET /oauth2/register/:client_id (RFC 7592 §2.1)
+func (c *OAuth2Controller) GetRegisteredClient(ctx *gin.Context) {
+	controllerutil.SetNoCacheHeaders(ctx)
+	token, ok := registrationBearerToken(ctx)
+	if !ok {
+		return
+	}
+	client, err := c.registrar.GetClient(ctx.Request.Context(), ctx.Param("client_id"), token)
+	if err != nil {
+		c.writeRegistrationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, c.clientInformation(client))
+}
+
+// UpdateRegisteredClient PUT /oauth2/register/:client_id (RFC 7592 §2.2)
+//
+// Replaces the client metadata with the JSON body, which must include client_id.
+func (c *OAuth2Controller) UpdateRegisteredClient(ctx *gin.Context) {
+	controllerutil.SetNoCacheHeaders(ctx)
+	token, ok := registrationBearerToken(ctx)
+	if !ok {
+		return
+	}
+	var md oauth2Service.ClientMetadata
+	if err := ctx.ShouldBindJSON(&md); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": oauth2Service.RegistrationErrorInvalidClientMetadata, "error_description": "request body must be a JSON object of client metadata"})
+		return
+	}
+	client, err := c.registrar.UpdateClient(ctx.Request.Context(), ctx.Param("client_id"), token, &md)
+	if err != nil {
+		c.writeRegistrationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, c.clientInformation(client))
+}
+
+// DeleteRegisteredClient DELETE /oauth2/register/:client_id (RFC 7592 §2.3)
+func (c *OAuth2Controller) DeleteRegisteredClient(ctx *gin.Context) {
+	controllerutil.SetNoCacheHeaders(ctx)
+	token, ok := registrationBearerToken(ctx)
+	if !ok {
+		return
+	}
+	if err := c.registrar.DeleteClient(ctx.Request.Context(), ctx.Param("client_id"), token); err != nil {
+		c.writeRegistrationError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *OAuth2Controller) clientInformation(client *oauth2Domain.OAuth2Client) clientInformationResponse {
+	return clientInformationResponse{
+		ClientMetadata:        oauth2Service.ClientMetadataFromClient(client),
+		ClientIDIssuedAt:      client.CreatedAt.Unix(),
+		RegistrationClientURI: c.issuer + "/oauth2/register/" + client.ClientID,
+	}
+}
+
+// writeRegistrationError writes a registration error response (RFC 7591 §3.2.2) or,
+// for bad credentials, a Bearer token error (RFC 6750 §3.1).
+func (c *OAuth2Controller) writeRegistrationError(ctx *gin.Context, err error) {
+	var regErr *oauth2Service.RegistrationError
+	switch {
+	case errors.As(err, &regErr):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": regErr.Code, "error_description": regErr.Description})
+	case errors.Is(err, oauth2Service.ErrInvalidInitialAccessToken), errors.Is(err, oauth2Service.ErrInvalidRegistrationAccessToken):
+		// RFC 7592 §2.1: an unknown client is reported like a bad token, so client IDs
+		// cannot be probed.
+		ctx.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token", "error_description": err.Error()})
+	default:
+		c.logger.Error("Client registration request failed", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+	}
+}
+
+// registrationBearerToken returns the Bearer token of a registration request. Cookies
+// are not accepted: these endpoints are for software, not browsers.
+func registrationBearerToken(ctx *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(ctx.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		ctx.Header("WWW-Authenticate", "Bearer")
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token", "error_description": "a Bearer token is required"})
+		return "", false
+	}
+	return token, true
+}
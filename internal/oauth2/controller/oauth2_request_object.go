@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// applyRequestObject verifies the request object (RFC 9101) sent by client and returns the
+// authorization request it carries. Parameters in the request object take precedence over
+// those in params (OIDC Core §6.1); clients registered with require_signed_request_object
+// get only the request object parameters, so nothing unsigned can be added to their requests.
+func (c *OAuth2Controller) applyRequestObject(ctx *gin.Context, params authorizeParams, client *oauth2Domain.OAuth2Client, requestObject string) (authorizeParams, *authorizeParamError) {
+	if c.requestObjects == nil {
+		return authorizeParams{}, &authorizeParamError{"request_not_supported", "request objects are not supported"}
+	}
+	signed, err := c.requestObjects.VerifyRequestObject(ctx.Request.Context(), client, requestObject)
+	if err != nil {
+		c.logger.Warn("Invalid request object", zap.String("client_id", client.ClientID), zap.Error(err))
+		return authorizeParams{}, &authorizeParamError{"invalid_request_object", "request object could not be verified"}
+	}
+
+	merged := url.Values{}
+	if !client.RequireSignedRequestObject {
+		merged = params.values()
+	}
+	for name, values := range signed {
+		merged[name] = values
+	}
+	result := authorizeParamsFromValues(merged)
+	result.ClientID = client.ClientID
+	result.RequestObject = true
+	return result, nil
+}
+
+// fetchRequestObject retrieves the request object that client passed by reference in
+// requestURI (RFC 9101 §5.2).
+func (c *OAuth2Controller) fetchRequestObject(ctx *gin.Context, client *oauth2Domain.OAuth2Client, requestURI string) (string, *authorizeParamError) {
+	if c.requestObjects == nil {
+		return "", &authorizeParamError{"request_uri_not_supported", "only request_uri values issued by the pushed authorization request endpoint are supported"}
+	}
+	requestObject, err := c.requestObjects.FetchRequestObject(ctx.Request.Context(), client, requestURI)
+	if err != nil {
+		c.logger.Warn("Failed to fetch request object", zap.String("client_id", client.ClientID), zap.Error(err))
+		return "", &authorizeParamError{"invalid_request_uri", "request object could not be retrieved from request_uri"}
+	}
+	return requestObject, nil
+}
+
+// checkSignedRequestObjectRequirement rejects plain authorization requests from clients
+// registered with require_signed_request_object (RFC 9101 §10.5).
+func checkSignedRequestObjectRequirement(params *authorizeParams, client *oauth2Domain.OAuth2Client) *authorizeParamError {
+	if client.RequireSignedRequestObject && !params.RequestObject {
+		return &authorizeParamError{"invalid_request", "a signed request object is required for this client"}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+)
+
+// maxResourceIndicators bounds the resource parameters of a single request (RFC 8707).
+const maxResourceIndicators = 10
+
+// checkResources validates the resource parameters of an authorization request: each must
+// be registered, and together they must allow one of scopes.
+func (c *OAuth2Controller) checkResources(resources, scopes []string) *authorizeParamError {
+	if c.resources == nil {
+		return &authorizeParamError{"invalid_target", "resource indicators are not supported"}
+	}
+	if _, err := c.resources.Resolve(resources, scopes); err != nil {
+		if errors.Is(err, oauth2Domain.ErrResourceScopeNotAllowed) {
+			return &authorizeParamError{"invalid_scope", "no requested scope is allowed for the requested resources"}
+		}
+		return &authorizeParamError{"invalid_target", "unknown resource"}
+	}
+	return nil
+}
+
+// resourceTarget resolves the resource parameters of a token request (RFC 8707 §2.2).
+// granted lists the resources of the grant, which requested may narrow; without resource
+// parameters all of them are used. A grant without resources allows any registered
+// resource. It returns nil for an access token not restricted to resources, and writes an
+// error response when ok is false.
+func (c *OAuth2Controller) resourceTarget(ctx *gin.Context, requested, granted, scopes []string) (*oauth2Domain.ResourceTarget, bool) {
+	resources := requested
+	if len(resources) == 0 {
+		resources = granted
+	} else if len(granted) > 0 {
+		for _, resource := range resources {
+			if !slices.Contains(granted, resource) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_target", "error_description": "resource exceeds the authorization grant"})
+				return nil, false
+			}
+		}
+	}
+	if len(resources) == 0 {
+		return nil, true
+	}
+	if paramErr := c.checkResources(resources, scopes); paramErr != nil {
+		writeAuthorizeParamError(ctx, paramErr)
+		return nil, false
+	}
+	target, _ := c.resources.Resolve(resources, scopes)
+	return target, true
+}
+
+// issueAccessToken generates an access token for claims, restricted to target when it is
+// not nil, and returns it with its scope and lifetime (see accessTokenLifetime). Clients with
+// the reference profile receive opaque tokens unless a target requires RFC 9068.
+func (c *OAuth2Controller) issueAccessToken(ctx context.Context, client *oauth2Domain.OAuth2Client, claims *tokenDomain.AccessTokenClaims, target *oauth2Domain.ResourceTarget) (string, string, time.Duration, error) {
+	if err := c.applyAccessTokenProfile(ctx, client, claims, target); err != nil {
+		return "", "", 0, err
+	}
+	lifetime := c.accessTokenLifetime(client, target)
+	reference := usesReferenceToken(client, claims)
+	if target == nil {
+		if reference {
+			token, err := c.tokenSvc.GenerateReferenceAccessToken(ctx, claims, lifetime)
+			return token, claims.Scope, lifetime, err
+		}
+		token, err := c.tokenSvc.GenerateAccessTokenWithLifetime(claims, lifetime)
+		return token, claims.Scope, lifetime, err
+	}
+	restricted := *claims
+	restricted.Audience = target.Audience
+	restricted.Scope = strings.Join(target.Scopes, " ")
+	if reference {
+		token, err := c.tokenSvc.GenerateReferenceAccessToken(ctx, &restricted, lifetime)
+		return token, restricted.Scope, lifetime, err
+	}
+	token, err := c.tokenSvc.GenerateResourceAccessToken(&restricted, lifetime)
+	return token, restricted.Scope, lifetime, err
+}
+
+// accessTokenLifetime returns the lifetime of client's access tokens for target: the
+// shorter of the client's and the resources' registered lifetimes, or the configured
+// default.
+func (c *OAuth2Controller) accessTokenLifetime(client *oauth2Domain.OAuth2Client, target *oauth2Domain.ResourceTarget) time.Duration {
+	lifetime := client.AccessTokenExpiry()
+	if target != nil && target.Lifetime > 0 && (lifetime <= 0 || target.Lifetime < lifetime) {
+		lifetime = target.Lifetime
+	}
+	if lifetime <= 0 {
+		lifetime = c.tokenSvc.AccessExpiry()
+	}
+	return lifetime
+}
+
+// usesReferenceToken reports whether client receives claims as an opaque reference token:
+// it has the reference profile and the token is not required in the RFC 9068 profile.
+func usesReferenceToken(client *oauth2Domain.OAuth2Client, claims *tokenDomain.AccessTokenClaims) bool {
+	return client.AccessTokenProfile == oauth2Domain.AccessTokenProfileReference &&
+		claims.Profile != tokenDomain.AccessTokenProfileRFC9068
+}
+
+// applyAccessTokenProfile selects the RFC 9068 profile for claims when the client or any
+// target resource requires it. The token's sub is then the subject identifier the client
+// sees for the account, unless the caller already set one.
+func (c *OAuth2Controller) applyAccessTokenProfile(ctx context.Context, client *oauth2Domain.OAuth2Client, claims *tokenDomain.AccessTokenClaims, target *oauth2Domain.ResourceTarget) error {
+	if client.AccessTokenProfile != oauth2Domain.AccessTokenProfileRFC9068 &&
+		(target == nil || target.AccessTokenProfile != oauth2Domain.AccessTokenProfileRFC9068) {
+		return nil
+	}
+	claims.Profile = tokenDomain.AccessTokenProfileRFC9068
+	if claims.Subject != "" || c.subjectIdentifiers == nil {
+		return nil
+	}
+	subject, err := c.subjectIdentifiers.IssueSubject(ctx, client.ClientID, claims.AccountID)
+	if err != nil {
+		return fmt.Errorf("issue access token subject: %w", err)
+	}
+	claims.Subject = subject
+	return nil
+}
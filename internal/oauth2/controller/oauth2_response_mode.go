@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/controllerutil"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	sessionService "github.com/rushairer/gosso/internal/session/service"
+	"github.com/rushairer/gosso/middleware"
+)
+
+// Response modes (OAuth 2.0 Multiple Response Type Encoding Practices, OAuth 2.0 Form Post
+// Response Mode and JARM). "jwt" is query.jwt for the code response type.
+const (
+	responseModeQuery       = "query"
+	responseModeFormPost    = "form_post"
+	responseModeJWT         = "jwt"
+	responseModeQueryJWT    = "query.jwt"
+	responseModeFormPostJWT = "form_post.jwt"
+)
+
+// supportedResponseModes lists the accepted response_mode values; an empty value means query.
+var supportedResponseModes = []string{
+	responseModeQuery, responseModeFormPost, responseModeJWT, responseModeQueryJWT, responseModeFormPostJWT,
+}
+
+// isJWTResponseMode reports whether mode delivers the response as a signed JWT (JARM).
+func isJWTResponseMode(mode string) bool {
+	return mode == responseModeJWT || mode == responseModeQueryJWT || mode == responseModeFormPostJWT
+}
+
+// checkResponseMode rejects the JWT response modes when no signer is configured. Unknown
+// modes are already rejected by authorizeParams.validate.
+func (c *OAuth2Controller) checkResponseMode(mode string) *authorizeParamError {
+	if isJWTResponseMode(mode) && c.jarm == nil {
+		return &authorizeParamError{"invalid_request", "JWT response modes are not supported"}
+	}
+	return nil
+}
+
+// respondWithCode returns an authorization code to the client in responseMode.
+func (c *OAuth2Controller) respondWithCode(ctx *gin.Context, clientID, redirectURI, responseMode string, code *oauth2Domain.AuthorizationCode, state string) {
+	sessionState := c.sessionState(ctx, clientID, redirectURI, code)
+	if responseMode == "" || responseMode == responseModeQuery {
+		redirectWithCode(ctx, redirectURI, code.Code, state, sessionState, c.issuer)
+		return
+	}
+	params := url.Values{"code": {code.Code}}
+	if state != "" {
+		params.Set("state", state)
+	}
+	if sessionState != "" {
+		params.Set("session_state", sessionState)
+	}
+	c.deliverAuthorizationResponse(ctx, clientID, redirectURI, responseMode, params)
+}
+
+// sessionState returns the session_state of an OpenID Connect authentication response
+// (OpenID Connect Session Management 1.0 §3) and refreshes the browser state cookie it is
+// checked against. It is empty for OAuth-only requests, codes without a session and
+// redirect URIs without a web origin, such as the custom schemes of native apps.
+func (c *OAuth2Controller) sessionState(ctx *gin.Context, clientID, redirectURI string, code *oauth2Domain.AuthorizationCode) string {
+	if code.SessionID == "" || !slices.Contains(code.Scopes, "openid") {
+		return ""
+	}
+	state, err := sessionService.SessionState(clientID, redirectURI, code.SessionID)
+	if err != nil {
+		c.logger.Debug("No session_state for authorization response", zap.String("client_id", clientID), zap.Error(err))
+		return ""
+	}
+	controllerutil.SetBrowserStateCookie(ctx, code.SessionID, int(c.tokenSvc.RefreshExpiry().Seconds()), controllerutil.IsSecureRequest(ctx))
+	return state
+}
+
+// respondWithError returns an OAuth2 error (RFC 6749 §4.1.2.1) to the client in responseMode.
+// Callers must have validated redirectURI against the client registration.
+func (c *OAuth2Controller) respondWithError(ctx *gin.Context, clientID, redirectURI, responseMode, errCode, description, state string) {
+	if responseMode == "" || responseMode == responseModeQuery {
+		redirectWithError(ctx, redirectURI, errCode, description, state, c.issuer)
+		return
+	}
+	params := url.Values{"error": {errCode}}
+	if description != "" {
+		params.Set("error_description", description)
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	c.deliverAuthorizationResponse(ctx, clientID, redirectURI, responseMode, params)
+}
+
+// deliverAuthorizationResponse sends params to redirectURI as an auto-submitted form
+// (form_post) or wrapped in a signed JWT in the response parameter (JARM §2.3).
+func (c *OAuth2Controller) deliverAuthorizationResponse(ctx *gin.Context, clientID, redirectURI, responseMode string, params url.Values) {
+	if c.issuer != "" {
+		params.Set("iss", c.issuer)
+	}
+	if isJWTResponseMode(responseMode) {
+		response, err := c.jarm.SignAuthorizationResponse(clientID, params)
+		if err != nil {
+			c.logger.Error("Failed to sign authorization response", zap.String("client_id", clientID), zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+			return
+		}
+		params = url.Values{"response": {response}}
+	}
+
+	if responseMode == responseModeFormPost || responseMode == responseModeFormPostJWT {
+		c.renderFormPost(ctx, redirectURI, params)
+		return
+	}
+	parsedURL, err := url.Parse(redirectURI)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_redirect_uri"})
+		return
+	}
+	query := parsedURL.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	parsedURL.RawQuery = query.Encode()
+	ctx.Redirect(http.StatusFound, parsedURL.String())
+}
+
+// formPostField is a hidden input of the form_post response page.
+type formPostField struct {
+	Name  string
+	Value string
+}
+
+// renderFormPost writes an HTML page that POSTs params to redirectURI (OAuth 2.0 Form Post
+// Response Mode §2). The page replaces the default CSP so the form may target the client.
+func (c *OAuth2Controller) renderFormPost(ctx *gin.Context, redirectURI string, params url.Values) {
+	parsedURL, err := url.Parse(redirectURI)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_redirect_uri"})
+		return
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	fields := make([]formPostField, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, formPostField{Name: name, Value: params.Get(name)})
+	}
+
+	nonce := middleware.GetCSPNonce(ctx)
+	var buf bytes.Buffer
+	if err := c.formPostTmpl.Execute(&buf, gin.H{
+		"RedirectURI": redirectURI,
+		"Fields":      fields,
+		"CSPNonce":    nonce,
+	}); err != nil {
+		c.logger.Error("Failed to render form_post response", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	if nonce != "" {
+		ctx.Header("Content-Security-Policy", "default-src 'none'; script-src 'nonce-"+nonce+"'; form-action "+parsedURL.Scheme+"://"+parsedURL.Host+"; base-uri 'none'; frame-ancestors 'none'")
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -1,13 +1,20 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"mime"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 
 	"github.com/rushairer/gosso/internal/controllerutil"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	tokenService "github.com/rushairer/gosso/internal/token/service"
 )
 
 // Revoke POST /oauth2/revoke (RFC 7009)
@@ -17,6 +24,9 @@ func (c *OAuth2Controller) Revoke(ctx *gin.Context) {
 		TokenHint    string `json:"token_type_hint" form:"token_type_hint" binding:"max=64"`
 		ClientID     string `json:"client_id" form:"client_id" binding:"max=128"`
 		ClientSecret string `json:"client_secret" form:"client_secret" binding:"max=256"`
+
+		ClientAssertionType string `json:"client_assertion_type" form:"client_assertion_type" binding:"max=128"`
+		ClientAssertion     string `json:"client_assertion" form:"client_assertion" binding:"max=8192"`
 	}
 
 	// RFC 7009 §2: the revocation endpoint accepts application/x-www-form-urlencoded
@@ -46,10 +56,14 @@ func (c *OAuth2Controller) Revoke(ctx *gin.Context) {
 
 	// RFC 7009 requires client authentication where applicable. Basic auth
 	// takes precedence over request body credentials per RFC 6749 §2.3.1.
-	if clientID, clientSecret, ok := ctx.Request.BasicAuth(); ok {
+	clientID, clientSecret, hasBasicAuth := ctx.Request.BasicAuth()
+	if hasBasicAuth {
 		req.ClientID = clientID
 		req.ClientSecret = clientSecret
 	}
+	if !resolveClientAssertion(ctx, req.ClientAssertionType, req.ClientAssertion, &req.ClientID, hasBasicAuth || req.ClientSecret != "") {
+		return
+	}
 
 	// Resolve client and authenticate if credentials are provided.
 	// RFC 7009 §2.1: public clients may revoke tokens without authentication.
@@ -61,42 +75,27 @@ func (c *OAuth2Controller) Revoke(ctx *gin.Context) {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
 			return
 		}
-		if err := c.clientAuth.AuthenticateClient(client, req.ClientSecret); err != nil {
+		if err := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); err != nil {
 			controllerutil.HandleClientAuthError(ctx, c.logger, err)
 			return
 		}
 		clientIDMatch = req.ClientID
 	}
 
-	// Try refresh token first (unless hint says otherwise)
-	if req.TokenHint != "access_token" {
-		rt, err := c.tokenSvc.ValidateRefreshToken(ctx, req.Token)
-		if err == nil {
-			if clientIDMatch != "" && rt.ClientID != clientIDMatch {
-				ctx.Status(http.StatusOK)
-				return
-			}
-			if err := c.tokenSvc.RevokeRefreshToken(ctx, req.Token); err != nil {
-				c.logger.Error("Failed to revoke refresh token", zap.Error(err))
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
-				return
-			}
-			ctx.Status(http.StatusOK)
+	// Try refresh tokens first unless the hint names access tokens. RFC 7009 §2.1: the hint
+	// only orders the lookups; whatever type matches is revoked.
+	revokers := []func(*gin.Context, string, string) (bool, error){c.revokeRefreshToken, c.revokeAccessToken}
+	if req.TokenHint == tokenService.TokenTypeHintAccessToken {
+		slices.Reverse(revokers)
+	}
+	for _, revoke := range revokers {
+		found, err := revoke(ctx, req.Token, clientIDMatch)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
 			return
 		}
-	}
-
-	// Try access token (RFC 7009 §2.1: revoke whatever type matches)
-	if req.TokenHint != "refresh_token" {
-		claims, err := c.tokenSvc.ValidateAccessTokenWithContext(ctx, req.Token)
-		if err == nil && (clientIDMatch == "" || claims.ClientID == clientIDMatch) {
-			if claims.ExpiresAt != nil {
-				if revokeErr := c.tokenSvc.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time); revokeErr != nil {
-					c.logger.Error("Failed to revoke access token", zap.Error(revokeErr))
-					ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
-					return
-				}
-			}
+		if found {
+			break
 		}
 	}
 
@@ -104,12 +103,49 @@ func (c *OAuth2Controller) Revoke(ctx *gin.Context) {
 	ctx.Status(http.StatusOK)
 }
 
+// revokeRefreshToken revokes token if it is a refresh token issued to clientID, or to any
+// client when clientID is empty. It reports whether token is a refresh token at all.
+func (c *OAuth2Controller) revokeRefreshToken(ctx *gin.Context, token, clientID string) (bool, error) {
+	rt, err := c.tokenSvc.ValidateRefreshToken(ctx, token)
+	if err != nil {
+		return false, nil
+	}
+	if clientID != "" && rt.ClientID != clientID {
+		return true, nil
+	}
+	if err := c.tokenSvc.RevokeRefreshToken(ctx, token); err != nil {
+		c.logger.Error("Failed to revoke refresh token", zap.Error(err))
+		return true, err
+	}
+	return true, nil
+}
+
+// revokeAccessToken revokes token if it is a JWT or reference access token issued to
+// clientID, or to any client when clientID is empty. It reports whether token is an
+// access token at all.
+func (c *OAuth2Controller) revokeAccessToken(ctx *gin.Context, token, clientID string) (bool, error) {
+	claims, err := c.tokenSvc.ParseAccessToken(ctx, token)
+	if err != nil {
+		return false, nil
+	}
+	if (clientID != "" && claims.ClientID != clientID) || claims.ExpiresAt == nil {
+		return true, nil
+	}
+	if err := c.tokenSvc.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		c.logger.Error("Failed to revoke access token", zap.Error(err))
+		return true, err
+	}
+	return true, nil
+}
+
 // IntrospectRequest is the token introspection request body.
 type IntrospectRequest struct {
-	Token string `json:"token" form:"token" binding:"required,max=2048"`
+	Token         string `json:"token" form:"token" binding:"required,max=2048"`
+	TokenTypeHint string `json:"token_type_hint" form:"token_type_hint" binding:"max=64"`
 }
 
-// Introspect POST /oauth2/introspect (RFC 7662)
+// Introspect POST /oauth2/introspect (RFC 7662). Access tokens, including reference tokens,
+// and refresh tokens can be introspected.
 func (c *OAuth2Controller) Introspect(ctx *gin.Context) {
 	var req IntrospectRequest
 
@@ -134,6 +170,10 @@ func (c *OAuth2Controller) Introspect(ctx *gin.Context) {
 		clientID = ctx.PostForm("client_id")
 		clientSecret = ctx.PostForm("client_secret")
 	}
+	assertion := ctx.PostForm("client_assertion")
+	if !resolveClientAssertion(ctx, ctx.PostForm("client_assertion_type"), assertion, &clientID, hasBasicAuth || clientSecret != "") {
+		return
+	}
 
 	if clientID == "" {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "client authentication required"})
@@ -147,7 +187,7 @@ func (c *OAuth2Controller) Introspect(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
 		return
 	}
-	if authErr := c.clientAuth.AuthenticateClient(client, clientSecret); authErr != nil {
+	if authErr := c.authenticateClient(ctx, client, clientSecret, assertion); authErr != nil {
 		controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
 		return
 	} else if !client.IsConfidential {
@@ -155,18 +195,90 @@ func (c *OAuth2Controller) Introspect(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.tokenSvc.IntrospectToken(ctx, req.Token)
+	result, err := c.tokenSvc.IntrospectToken(ctx, req.Token, req.TokenTypeHint)
 	if err != nil {
 		c.logger.Error("Token introspection failed", zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
 		return
 	}
 
-	// RFC 7662: only return full token info if the token belongs to the authenticated client
-	if tokenClientID, ok := result["client_id"].(string); !ok || tokenClientID != clientID {
-		ctx.JSON(http.StatusOK, gin.H{"active": false})
+	// RFC 7662 §4: only the client the token was issued to and the resource servers it is
+	// intended for learn anything about it.
+	owner, err := c.introspectionTokenClient(ctx, client, result)
+	if err != nil {
+		c.logger.Error("Token introspection failed to look up token client", zap.String("client_id", clientID), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
 		return
 	}
+	if owner == nil {
+		c.respondIntrospection(ctx, clientID, map[string]any{"active": false})
+		return
+	}
+	if sub, ok := result["sub"].(string); ok && c.subjectIdentifiers != nil {
+		// Resource servers see the subject the token's client sees.
+		subject, err := c.subjectIdentifiers.Subject(owner, sub)
+		if err != nil {
+			c.logger.Error("Token introspection failed to derive subject", zap.String("client_id", clientID), zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+			return
+		}
+		result["sub"] = subject
+	}
+
+	c.respondIntrospection(ctx, clientID, result)
+}
+
+// introspectionTokenClient returns the client an introspected token was issued to when
+// caller may see the token's details: caller is that client, is in the token's aud, or
+// is the resource server of one of the resources in it. It returns nil otherwise, and
+// for tokens of clients that no longer exist.
+func (c *OAuth2Controller) introspectionTokenClient(ctx context.Context, caller *oauth2Domain.OAuth2Client, result map[string]any) (*oauth2Domain.OAuth2Client, error) {
+	tokenClientID, _ := result["client_id"].(string)
+	if tokenClientID == "" {
+		return nil, nil
+	}
+	if tokenClientID == caller.ClientID {
+		return caller, nil
+	}
+	audience, _ := result["aud"].(jwt.ClaimStrings)
+	if !slices.Contains(audience, caller.ClientID) && (c.resources == nil || !c.resources.IsResourceServer(caller.ClientID, audience)) {
+		return nil, nil
+	}
+	owner, err := c.clientSvc.FindByClientID(ctx, tokenClientID)
+	if errors.Is(err, oauth2Domain.ErrClientNotFound) {
+		return nil, nil
+	}
+	return owner, err
+}
+
+// introspectionJWTMediaType is the media type of signed introspection responses (RFC 9701 §4).
+const introspectionJWTMediaType = "application/token-introspection+jwt"
+
+// respondIntrospection returns result as JSON, or as a JWT signed for clientID when the
+// resource server accepts application/token-introspection+jwt (RFC 9701 §4).
+func (c *OAuth2Controller) respondIntrospection(ctx *gin.Context, clientID string, result map[string]any) {
+	if c.introspectionSigner == nil || !acceptsMediaType(ctx.GetHeader("Accept"), introspectionJWTMediaType) {
+		ctx.JSON(http.StatusOK, result)
+		return
+	}
+	response, err := c.introspectionSigner.SignIntrospectionResponse(clientID, result)
+	if err != nil {
+		c.logger.Error("Failed to sign introspection response", zap.String("client_id", clientID), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, introspectionJWTMediaType, []byte(response))
+}
 
-	ctx.JSON(http.StatusOK, result)
+// acceptsMediaType reports whether the Accept header lists mediaType explicitly. Wildcards
+// do not count, so clients that accept anything keep receiving JSON.
+func acceptsMediaType(accept, mediaType string) bool {
+	for _, accepted := range strings.Split(accept, ",") {
+		parsed, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && parsed == mediaType && params["q"] != "0" {
+			return true
+		}
+	}
+	return false
 }
@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 
 	"github.com/rushairer/gosso/internal/controllerutil"
+	"github.com/rushairer/gosso/internal/mtls"
 	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
 	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
 	"github.com/rushairer/gosso/internal/utility"
@@ -21,6 +23,12 @@ import (
 // for clients that include optional fields (e.g., long redirect_uri values) while still
 // preventing abuse via excessively large form bodies.
 const oauth2MaxFormBodySize = 8 * 1024
+
+// oauth2MaxTokenBodySize is the maximum allowed Content-Length for the token endpoint. It
+// covers the combined field limits of TokenRequest (8KB assertion and client_assertion,
+// two 4KB token exchange tokens, ten audience and resource values each), with room for
+// percent-encoding of the URIs among them.
+const oauth2MaxTokenBodySize = 128 * 1024
 const authCookieName = "__Host-access_token"
 const refreshCookieName = "__Host-refresh_token"
 const cookieSessionHeader = "X-Gosso-Cookie-Session"
@@ -36,7 +44,7 @@ func setSSOAuthCookie(ctx *gin.Context, accessToken string, maxAgeSeconds int) {
 		Path:     "/",
 		MaxAge:   maxAgeSeconds,
 		HttpOnly: true,
-		Secure:   ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https",
+		Secure:   controllerutil.IsSecureRequest(ctx),
 		SameSite: http.SameSiteLaxMode,
 	})
 }
@@ -51,7 +59,7 @@ func setSSORefreshCookie(ctx *gin.Context, refreshToken string, maxAgeSeconds in
 		Path:     "/",
 		MaxAge:   maxAgeSeconds,
 		HttpOnly: true,
-		Secure:   ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https",
+		Secure:   controllerutil.IsSecureRequest(ctx),
 		SameSite: http.SameSiteStrictMode,
 	})
 }
@@ -67,12 +75,40 @@ type TokenRequest struct {
 	RefreshToken string `json:"refresh_token" form:"refresh_token" binding:"max=2048"`
 	Scope        string `json:"scope" form:"scope" binding:"max=2048"`
 	DeviceCode   string `json:"device_code" form:"device_code" binding:"max=128"`
+
+	// Rich authorization request parameter (RFC 9396 §6): a JSON array narrowing the
+	// authorization details of the grant.
+	AuthorizationDetails string `json:"authorization_details" form:"authorization_details" binding:"max=4096"`
+
+	// Token exchange parameters (RFC 8693 §2.1).
+	SubjectToken       string   `json:"subject_token" form:"subject_token" binding:"max=4096"`
+	SubjectTokenType   string   `json:"subject_token_type" form:"subject_token_type" binding:"max=128"`
+	ActorToken         string   `json:"actor_token" form:"actor_token" binding:"max=4096"`
+	ActorTokenType     string   `json:"actor_token_type" form:"actor_token_type" binding:"max=128"`
+	RequestedTokenType string   `json:"requested_token_type" form:"requested_token_type" binding:"max=128"`
+	Audience           []string `json:"audience" form:"audience" binding:"max=10,dive,max=512"`
+	Resource           []string `json:"resource" form:"resource" binding:"max=10,dive,max=2048"`
+
+	// Backchannel authentication grant parameter (CIBA Core §10.1).
+	AuthReqID string `json:"auth_req_id" form:"auth_req_id" binding:"max=128"`
+
+	// JWT authorization grant parameter (RFC 7523 §2.1).
+	Assertion string `json:"assertion" form:"assertion" binding:"max=8192"`
+
+	ClientAssertionType string `json:"client_assertion_type" form:"client_assertion_type" binding:"max=128"`
+	ClientAssertion     string `json:"client_assertion" form:"client_assertion" binding:"max=8192"`
+
+	// dpopJKT is the thumbprint of the DPoP proof key sent with the request, if any.
+	dpopJKT string
+	// certThumbprint is the x5t#S256 of the client certificate the issued tokens are
+	// bound to (RFC 8705 §3), if any.
+	certThumbprint string
 }
 
 // Token POST /oauth2/token
 func (c *OAuth2Controller) Token(ctx *gin.Context) {
 	// Defense-in-depth: reject oversized form bodies early, before parsing.
-	if ctx.Request.ContentLength > oauth2MaxFormBodySize {
+	if ctx.Request.ContentLength > oauth2MaxTokenBodySize {
 		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{
 			"error":             "invalid_request",
 			"error_description": "request body too large",
@@ -108,10 +144,17 @@ func (c *OAuth2Controller) Token(ctx *gin.Context) {
 	}
 
 	// RFC 6749 §2.3.1: When Basic Auth is present, it takes precedence over body parameters.
-	if clientID, clientSecret, ok := ctx.Request.BasicAuth(); ok {
+	clientID, clientSecret, hasBasicAuth := ctx.Request.BasicAuth()
+	if hasBasicAuth {
 		req.ClientID = clientID
 		req.ClientSecret = clientSecret
 	}
+	if !resolveClientAssertion(ctx, req.ClientAssertionType, req.ClientAssertion, &req.ClientID, hasBasicAuth || req.ClientSecret != "") {
+		return
+	}
+	if !c.verifyTokenDPoPProof(ctx, &req) {
+		return
+	}
 
 	switch req.GrantType {
 	case "authorization_code":
@@ -122,6 +165,12 @@ func (c *OAuth2Controller) Token(ctx *gin.Context) {
 		c.handleClientCredentialsGrant(ctx, &req)
 	case "urn:ietf:params:oauth:grant-type:device_code":
 		c.handleDeviceCodeGrant(ctx, &req)
+	case oauth2Domain.GrantTypeTokenExchange:
+		c.handleTokenExchangeGrant(ctx, &req)
+	case oauth2Domain.GrantTypeJWTBearer:
+		c.handleJWTBearerGrant(ctx, &req)
+	case oauth2Domain.GrantTypeCIBA:
+		c.handleCIBAGrant(ctx, &req)
 	default:
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
 	}
@@ -141,7 +190,7 @@ func (c *OAuth2Controller) handleAuthorizationCodeGrant(ctx *gin.Context, req *T
 		return
 	}
 
-	if authErr := c.clientAuth.AuthenticateClient(client, req.ClientSecret); authErr != nil {
+	if authErr := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); authErr != nil {
 		controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
 		return
 	} else if !client.IsConfidential && req.CodeVerifier == "" {
@@ -149,6 +198,9 @@ func (c *OAuth2Controller) handleAuthorizationCodeGrant(ctx *gin.Context, req *T
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "code_verifier required for public clients"})
 		return
 	}
+	if !c.bindSenderConstraints(ctx, client, req) {
+		return
+	}
 
 	var codeVerifier *string
 	if req.CodeVerifier != "" {
@@ -167,6 +219,21 @@ func (c *OAuth2Controller) handleAuthorizationCodeGrant(ctx *gin.Context, req *T
 		return
 	}
 
+	details, ok := grantedAuthorizationDetails(authCode.AuthorizationDetails, req.AuthorizationDetails)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_authorization_details", "error_description": "authorization_details exceed the authorization grant"})
+		return
+	}
+	target, ok := c.resourceTarget(ctx, req.Resource, authCode.Resources, authCode.Scopes)
+	if !ok {
+		return
+	}
+	// The refresh token keeps the resources of the grant, or those first requested here.
+	resources := authCode.Resources
+	if len(resources) == 0 && target != nil {
+		resources = target.Audience
+	}
+
 	var roles []string
 	if c.includeUserRoles && c.roleFetcher != nil {
 		var rolesErr error
@@ -189,14 +256,23 @@ func (c *OAuth2Controller) handleAuthorizationCodeGrant(ctx *gin.Context, req *T
 		}
 	}
 
-	accessToken, err := c.tokenSvc.GenerateAccessToken(&tokenDomain.AccessTokenClaims{
-		AccountID:   authCode.AccountID,
-		Scope:       strings.Join(authCode.Scopes, " "),
-		ClientID:    authCode.ClientID,
-		SessionID:   authCode.SessionID,
-		Roles:       roles,
-		Permissions: permissions,
-	})
+	var authTime *jwt.NumericDate
+	if !authCode.AuthTime.IsZero() {
+		authTime = jwt.NewNumericDate(authCode.AuthTime)
+	}
+	accessToken, accessTokenScope, accessTokenLifetime, err := c.issueAccessToken(ctx, client, &tokenDomain.AccessTokenClaims{
+		AccountID:            authCode.AccountID,
+		Scope:                strings.Join(authCode.Scopes, " "),
+		ClientID:             authCode.ClientID,
+		SessionID:            authCode.SessionID,
+		Roles:                roles,
+		Permissions:          permissions,
+		AuthTime:             authTime,
+		AMR:                  authCode.AuthMethods,
+		Cnf:                  tokenConfirmation(req),
+		AuthorizationDetails: details.JSON(),
+		UserInfoClaims:       authCode.Claims.UserInfoClaims(),
+	}, target)
 	if err != nil {
 		c.logger.Error("Failed to generate access token for authorization code", zap.Error(err), zap.String("client_id", req.ClientID))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
@@ -204,8 +280,8 @@ func (c *OAuth2Controller) handleAuthorizationCodeGrant(ctx *gin.Context, req *T
 	}
 
 	var refreshToken *tokenDomain.RefreshToken
-	if client.HasGrantType("refresh_token") {
-		refreshToken, err = c.tokenSvc.GenerateRefreshToken(ctx, authCode.AccountID, authCode.ClientID, authCode.SessionID, strings.Join(authCode.Scopes, " "))
+	if issuesRefreshToken(client, authCode.Scopes, authCode.SessionID) {
+		refreshToken, err = c.tokenSvc.GenerateRefreshToken(ctx, authCode.AccountID, authCode.ClientID, authCode.SessionID, strings.Join(authCode.Scopes, " "), authCode.AuthorizationDetails.JSON(), resources, authCode.Claims.UserInfoClaims(), refreshTokenConfirmation(client, req), refreshTokenPolicy(client))
 		if err != nil {
 			c.logger.Error("Failed to generate refresh token for authorization code", zap.Error(err), zap.String("client_id", req.ClientID))
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
@@ -213,16 +289,16 @@ func (c *OAuth2Controller) handleAuthorizationCodeGrant(ctx *gin.Context, req *T
 		}
 	}
 
-	idToken, ok := c.maybeGenerateIDToken(ctx, authCode.AccountID, authCode.ClientID, authCode.Scopes, authCode.Nonce, authCode.AuthTime, accessToken, authCode.AuthMethods)
+	idToken, ok := c.maybeGenerateIDToken(ctx, authCode.AccountID, authCode.ClientID, authCode.Scopes, authCode.Claims.IDTokenClaims(), authCode.Nonce, authCode.AuthTime, accessToken, authCode.AuthMethods)
 	if !ok {
 		return
 	}
 
 	response := gin.H{
 		"access_token": accessToken,
-		"token_type":   "Bearer",
-		"expires_in":   int(c.tokenSvc.AccessExpiry().Seconds()),
-		"scope":        strings.Join(authCode.Scopes, " "),
+		"token_type":   accessTokenType(req.dpopJKT),
+		"expires_in":   int(accessTokenLifetime.Seconds()),
+		"scope":        accessTokenScope,
 	}
 	if refreshToken != nil {
 		response["refresh_token"] = refreshToken.Token
@@ -230,14 +306,17 @@ func (c *OAuth2Controller) handleAuthorizationCodeGrant(ctx *gin.Context, req *T
 	if idToken != "" {
 		response["id_token"] = idToken
 	}
+	if len(details) > 0 {
+		response["authorization_details"] = details
+	}
 
 	controllerutil.SetNoCacheHeaders(ctx)
 	if isCookieSessionRequest(ctx) {
-		setSSOAuthCookie(ctx, accessToken, int(c.tokenSvc.AccessExpiry().Seconds()))
+		setSSOAuthCookie(ctx, accessToken, int(accessTokenLifetime.Seconds()))
 		if refreshToken != nil {
 			setSSORefreshCookie(ctx, refreshToken.Token, maxAgeUntil(refreshToken.ExpiresAt))
 		}
-		ctx.JSON(http.StatusOK, gin.H{"expires_in": int(c.tokenSvc.AccessExpiry().Seconds()), "scope": strings.Join(authCode.Scopes, " ")})
+		ctx.JSON(http.StatusOK, gin.H{"expires_in": int(accessTokenLifetime.Seconds()), "scope": accessTokenScope})
 		return
 	}
 	ctx.JSON(http.StatusOK, response)
@@ -263,12 +342,15 @@ func (c *OAuth2Controller) handleRefreshTokenGrant(ctx *gin.Context, req *TokenR
 	}
 	// RFC 6749 §6: confidential clients MUST authenticate when using refresh token grant.
 	// Public clients are exempt — they are bound by the refresh token itself and optionally PKCE.
-	if client.IsConfidential {
-		if authErr := c.clientAuth.AuthenticateClient(client, req.ClientSecret); authErr != nil {
+	if client.IsConfidential || req.ClientAssertion != "" {
+		if authErr := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); authErr != nil {
 			controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
 			return
 		}
 	}
+	if !c.bindSenderConstraints(ctx, client, req) {
+		return
+	}
 
 	// Now that the client is authenticated, validate the refresh token
 	oldRefreshToken, err := c.tokenSvc.ValidateRefreshToken(ctx, req.RefreshToken)
@@ -293,6 +375,19 @@ func (c *OAuth2Controller) handleRefreshTokenGrant(ctx *gin.Context, req *TokenR
 		return
 	}
 
+	// RFC 9449 §5 and RFC 8705 §4: a bound refresh token may only be used with a proof
+	// from the same key and over a connection with the same client certificate.
+	if cnf := oldRefreshToken.Cnf; cnf != nil {
+		if cnf.JKT != "" && cnf.JKT != req.dpopJKT {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_dpop_proof", "error_description": "DPoP proof key does not match the refresh token"})
+			return
+		}
+		if cnf.X5TS256 != "" && cnf.X5TS256 != mtls.Thumbprint(mtls.CertificateFromContext(ctx.Request.Context())) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "client certificate does not match the refresh token"})
+			return
+		}
+	}
+
 	// Verify account is still active BEFORE consuming the old refresh token.
 	// If the account is inactive, reject early so the client retains the old token.
 	if !c.accountValidator.IsAccountActive(ctx, oldRefreshToken.AccountID) {
@@ -311,6 +406,23 @@ func (c *OAuth2Controller) handleRefreshTokenGrant(ctx *gin.Context, req *TokenR
 			}
 		}
 	}
+	// RFC 9396 §6.2: requested authorization details must be covered by the original grant.
+	// The stored details were validated when the refresh token was issued.
+	grantedDetails, _ := oauth2Domain.ParseAuthorizationDetails(oldRefreshToken.AuthorizationDetails)
+	details, ok := grantedAuthorizationDetails(grantedDetails, req.AuthorizationDetails)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_authorization_details", "error_description": "authorization_details exceed the authorization grant"})
+		return
+	}
+	// RFC 8707 §2.2: resources are remembered with the refresh token and may be narrowed.
+	requestedScope := oldRefreshToken.Scope
+	if req.Scope != "" {
+		requestedScope = req.Scope
+	}
+	target, ok := c.resourceTarget(ctx, req.Resource, oldRefreshToken.Resources, splitScope(requestedScope))
+	if !ok {
+		return
+	}
 
 	// All validations passed — now atomically rotate the token
 	newRefreshToken, err := c.tokenSvc.RotateRefreshToken(ctx, req.RefreshToken)
@@ -320,11 +432,6 @@ func (c *OAuth2Controller) handleRefreshTokenGrant(ctx *gin.Context, req *TokenR
 		return
 	}
 
-	accessTokenScope := newRefreshToken.Scope
-	if req.Scope != "" {
-		accessTokenScope = req.Scope
-	}
-
 	var roles []string
 	if c.includeUserRoles && c.roleFetcher != nil {
 		var rolesErr error
@@ -347,14 +454,17 @@ func (c *OAuth2Controller) handleRefreshTokenGrant(ctx *gin.Context, req *TokenR
 		}
 	}
 
-	accessToken, err := c.tokenSvc.GenerateAccessToken(&tokenDomain.AccessTokenClaims{
-		AccountID:   newRefreshToken.AccountID,
-		Scope:       accessTokenScope,
-		ClientID:    newRefreshToken.ClientID,
-		SessionID:   newRefreshToken.SessionID,
-		Roles:       roles,
-		Permissions: permissions,
-	})
+	accessToken, accessTokenScope, accessTokenLifetime, err := c.issueAccessToken(ctx, client, &tokenDomain.AccessTokenClaims{
+		AccountID:            newRefreshToken.AccountID,
+		Scope:                requestedScope,
+		ClientID:             newRefreshToken.ClientID,
+		SessionID:            newRefreshToken.SessionID,
+		Roles:                roles,
+		Permissions:          permissions,
+		Cnf:                  tokenConfirmation(req),
+		AuthorizationDetails: details.JSON(),
+		UserInfoClaims:       newRefreshToken.UserInfoClaims,
+	}, target)
 	if err != nil {
 		c.logger.Error("Failed to generate access token for refresh", zap.Error(err), zap.String("client_id", newRefreshToken.ClientID))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
@@ -363,18 +473,45 @@ func (c *OAuth2Controller) handleRefreshTokenGrant(ctx *gin.Context, req *TokenR
 
 	controllerutil.SetNoCacheHeaders(ctx)
 	if isCookieSessionRequest(ctx) {
-		setSSOAuthCookie(ctx, accessToken, int(c.tokenSvc.AccessExpiry().Seconds()))
+		setSSOAuthCookie(ctx, accessToken, int(accessTokenLifetime.Seconds()))
 		setSSORefreshCookie(ctx, newRefreshToken.Token, maxAgeUntil(newRefreshToken.ExpiresAt))
-		ctx.JSON(http.StatusOK, gin.H{"expires_in": int(c.tokenSvc.AccessExpiry().Seconds()), "scope": accessTokenScope})
+		ctx.JSON(http.StatusOK, gin.H{"expires_in": int(accessTokenLifetime.Seconds()), "scope": accessTokenScope})
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"access_token":  accessToken,
 		"refresh_token": newRefreshToken.Token,
-		"token_type":    "Bearer",
-		"expires_in":    int(c.tokenSvc.AccessExpiry().Seconds()),
+		"token_type":    accessTokenType(req.dpopJKT),
+		"expires_in":    int(accessTokenLifetime.Seconds()),
 		"scope":         accessTokenScope,
-	})
+	}
+	if len(details) > 0 {
+		response["authorization_details"] = details
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// issuesRefreshToken reports whether a grant of scopes to client gets a refresh token.
+// Without offline_access (OIDC Core §11) only a grant made in a browser session gets one,
+// and it is revoked with the session; an offline_access token outlives it.
+func issuesRefreshToken(client *oauth2Domain.OAuth2Client, scopes []string, sessionID string) bool {
+	if !client.HasGrantType(oauth2Domain.GrantTypeRefreshToken) {
+		return false
+	}
+	return sessionID != "" || slices.Contains(scopes, tokenDomain.ScopeOfflineAccess)
+}
+
+// refreshTokenPolicy returns the refresh token lifetime policy of client, or nil for the
+// server default.
+func refreshTokenPolicy(client *oauth2Domain.OAuth2Client) *tokenDomain.RefreshTokenPolicy {
+	if !client.HasRefreshTokenPolicy() {
+		return nil
+	}
+	return &tokenDomain.RefreshTokenPolicy{
+		IdleLifetime:     time.Duration(client.RefreshTokenIdleLifetime) * time.Second,
+		AbsoluteLifetime: time.Duration(client.RefreshTokenAbsoluteLifetime) * time.Second,
+		Sliding:          client.RefreshTokenExpiration != oauth2Domain.RefreshTokenExpirationFixed,
+	}
 }
 
 func maxAgeUntil(expiry time.Time) int {
@@ -386,7 +523,7 @@ func maxAgeUntil(expiry time.Time) int {
 }
 
 func (c *OAuth2Controller) handleClientCredentialsGrant(ctx *gin.Context, req *TokenRequest) {
-	if req.ClientID == "" || req.ClientSecret == "" {
+	if req.ClientID == "" || (req.ClientSecret == "" && req.ClientAssertion == "" && !hasClientCertificate(ctx)) {
 		c.clientAuth.DummyAuthenticate()
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
 		return
@@ -411,16 +548,30 @@ func (c *OAuth2Controller) handleClientCredentialsGrant(ctx *gin.Context, req *T
 		return
 	}
 
-	if authErr := c.clientAuth.AuthenticateClient(client, req.ClientSecret); authErr != nil {
+	if authErr := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); authErr != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
 		return
 	}
+	if !c.bindSenderConstraints(ctx, client, req) {
+		return
+	}
 
 	scopes := client.ValidateScope(splitScope(req.Scope))
 	if len(scopes) == 0 {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope", "error_description": "scope parameter is required and must contain valid scopes for this client"})
 		return
 	}
+	// RFC 9396 §6: without a user grant, details are checked against the client's types only.
+	details, err := oauth2Domain.ParseAuthorizationDetails([]byte(req.AuthorizationDetails))
+	if err != nil || !client.AllowsAuthorizationDetails(details) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_authorization_details", "error_description": "invalid authorization_details or type not allowed for this client"})
+		return
+	}
+
+	target, ok := c.resourceTarget(ctx, req.Resource, nil, scopes)
+	if !ok {
+		return
+	}
 
 	// Verify account is still active (deleted/suspended clients cannot get new tokens)
 	if !c.accountValidator.IsAccountActive(ctx, client.AccountID) {
@@ -428,36 +579,45 @@ func (c *OAuth2Controller) handleClientCredentialsGrant(ctx *gin.Context, req *T
 		return
 	}
 
-	accessToken, err := c.tokenSvc.GenerateAccessToken(&tokenDomain.AccessTokenClaims{
-		Scope:     strings.Join(scopes, " "),
-		ClientID:  req.ClientID,
-		AccountID: client.AccountID,
-	})
+	accessToken, accessTokenScope, accessTokenLifetime, err := c.issueAccessToken(ctx, client, &tokenDomain.AccessTokenClaims{
+		// The client is the subject of its own tokens (RFC 9068 §2.2).
+		RegisteredClaims:     jwt.RegisteredClaims{Subject: req.ClientID},
+		Scope:                strings.Join(scopes, " "),
+		ClientID:             req.ClientID,
+		AccountID:            client.AccountID,
+		Cnf:                  tokenConfirmation(req),
+		AuthorizationDetails: details.JSON(),
+	}, target)
 	if err != nil {
 		c.logger.Error("Failed to generate access token for client_credentials", zap.Error(err), zap.String("client_id", req.ClientID))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
 		return
 	}
 
-	controllerutil.SetNoCacheHeaders(ctx)
-	ctx.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"access_token": accessToken,
-		"token_type":   "Bearer",
-		"expires_in":   int(c.tokenSvc.AccessExpiry().Seconds()),
-		"scope":        strings.Join(scopes, " "),
-	})
+		"token_type":   accessTokenType(req.dpopJKT),
+		"expires_in":   int(accessTokenLifetime.Seconds()),
+		"scope":        accessTokenScope,
+	}
+	if len(details) > 0 {
+		response["authorization_details"] = details
+	}
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusOK, response)
 }
 
-// maybeGenerateIDToken generates an ID token if the "openid" scope is present.
+// maybeGenerateIDToken generates an ID token if the "openid" scope is present. claims are
+// the claims requested individually for the ID token.
 // Returns the ID token string (or empty string if openid scope is not requested).
 // On error, writes an HTTP error response and returns ("", false).
-func (c *OAuth2Controller) maybeGenerateIDToken(ctx *gin.Context, accountID, clientID string, scopes []string, nonce string, authTime time.Time, accessToken string, authMethods []string) (string, bool) {
+func (c *OAuth2Controller) maybeGenerateIDToken(ctx *gin.Context, accountID, clientID string, scopes, claims []string, nonce string, authTime time.Time, accessToken string, authMethods []string) (string, bool) {
 	if c.idTokenSvc == nil {
 		return "", true
 	}
 	for _, s := range scopes {
 		if s == "openid" {
-			idToken, err := c.idTokenSvc.GenerateIDToken(ctx, accountID, clientID, scopes, nonce, authTime, accessToken, authMethods)
+			idToken, err := c.idTokenSvc.GenerateIDToken(ctx, accountID, clientID, scopes, claims, nonce, authTime, accessToken, authMethods)
 			if err != nil {
 				c.logger.Error("Failed to generate ID token", zap.Error(err))
 				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error", "error_description": "failed to generate id_token"})
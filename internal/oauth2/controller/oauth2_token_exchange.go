@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/controllerutil"
+	"github.com/rushairer/gosso/internal/mtls"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	tokenService "github.com/rushairer/gosso/internal/token/service"
+)
+
+// maxTokenExchangeTargets bounds the combined audience and resource values of one exchange.
+const maxTokenExchangeTargets = 10
+
+// handleTokenExchangeGrant implements the token exchange grant (RFC 8693). An authenticated
+// confidential client trades a subject_token (and optionally an actor_token) for a new access
+// token that is narrowed to the requested audiences and scope and carries an act claim.
+func (c *OAuth2Controller) handleTokenExchangeGrant(ctx *gin.Context, req *TokenRequest) {
+	if req.ClientID == "" || (req.ClientSecret == "" && req.ClientAssertion == "" && !hasClientCertificate(ctx)) {
+		c.clientAuth.DummyAuthenticate()
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+
+	client, err := c.clientSvc.FindByClientID(ctx, req.ClientID)
+	if err != nil {
+		c.clientAuth.DummyAuthenticate()
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+
+	if !client.IsConfidential {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "token exchange requires confidential client"})
+		return
+	}
+
+	if !client.HasGrantType(oauth2Domain.GrantTypeTokenExchange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unauthorized_client", "error_description": "token exchange grant not allowed for this client"})
+		return
+	}
+
+	if authErr := c.authenticateClient(ctx, client, req.ClientSecret, req.ClientAssertion); authErr != nil {
+		controllerutil.HandleClientAuthError(ctx, c.logger, authErr)
+		return
+	}
+	if !c.bindSenderConstraints(ctx, client, req) {
+		return
+	}
+
+	if req.SubjectToken == "" || req.SubjectTokenType == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "subject_token and subject_token_type are required"})
+		return
+	}
+	if req.SubjectTokenType != oauth2Domain.TokenTypeAccessToken {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "unsupported subject_token_type"})
+		return
+	}
+	// RFC 8693 §2.1: actor_token_type is required with actor_token and must not appear without it.
+	if (req.ActorToken == "") != (req.ActorTokenType == "") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "actor_token and actor_token_type must be sent together"})
+		return
+	}
+	if req.ActorTokenType != "" && req.ActorTokenType != oauth2Domain.TokenTypeAccessToken {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "unsupported actor_token_type"})
+		return
+	}
+	if req.RequestedTokenType != "" && req.RequestedTokenType != oauth2Domain.TokenTypeAccessToken {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "unsupported requested_token_type"})
+		return
+	}
+
+	audiences, ok := tokenExchangeTargets(ctx, req.Audience, req.Resource)
+	if !ok {
+		return
+	}
+	for _, aud := range audiences {
+		if !client.CanExchangeForAudience(aud) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_target", "error_description": "requested audience is not allowed for this client"})
+			return
+		}
+	}
+
+	subject, ok := c.validateExchangeToken(ctx, req, req.SubjectToken, "subject_token")
+	if !ok {
+		return
+	}
+	// The subject token must have been issued to or for the client, so a client cannot
+	// exchange tokens meant for other parties.
+	if subject.ClientID != client.ClientID && !slices.Contains(subject.Audience, client.ClientID) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "subject_token was not issued to or for this client"})
+		return
+	}
+	var actor *tokenDomain.AccessTokenClaims
+	if req.ActorToken != "" {
+		if actor, ok = c.validateExchangeToken(ctx, req, req.ActorToken, "actor_token"); !ok {
+			return
+		}
+		// The actor token must identify the authenticated client, not some other party.
+		if actor.ClientID != client.ClientID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "actor_token was not issued to this client"})
+			return
+		}
+	}
+
+	if !c.accountValidator.IsAccountActive(ctx, subject.AccountID) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "account is not active"})
+		return
+	}
+
+	// The exchanged token can only be narrower than the subject token.
+	scope := subject.Scope
+	if req.Scope != "" {
+		granted := splitScope(subject.Scope)
+		for _, s := range splitScope(req.Scope) {
+			if !slices.Contains(granted, s) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope", "error_description": "requested scope exceeds subject_token scope"})
+				return
+			}
+		}
+		scope = strings.Join(splitScope(req.Scope), " ")
+	}
+
+	// Registered resources among the audiences restrict the scope and select the lifetime
+	// and profile like resource indicators do.
+	var target *oauth2Domain.ResourceTarget
+	if c.resources != nil {
+		if registered := c.resources.Target(audiences); registered != nil {
+			if target, err = c.resources.Resolve(registered.Audience, splitScope(scope)); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope", "error_description": "no requested scope is allowed for the requested resources"})
+				return
+			}
+			scope = strings.Join(target.Scopes, " ")
+		}
+	}
+	profile := &tokenDomain.AccessTokenClaims{AccountID: subject.AccountID, ClientID: client.ClientID}
+	if err := c.applyAccessTokenProfile(ctx, client, profile, target); err != nil {
+		c.logger.Error("Failed to exchange token", zap.Error(err), zap.String("client_id", client.ClientID))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+
+	accessToken, expiresAt, err := c.tokenSvc.ExchangeToken(ctx, &tokenService.TokenExchangeRequest{
+		ClientID:       client.ClientID,
+		Subject:        subject,
+		Actor:          actor,
+		Audiences:      audiences,
+		Scope:          scope,
+		Cnf:            tokenConfirmation(req),
+		Lifetime:       c.accessTokenLifetime(client, target),
+		Profile:        profile.Profile,
+		ProfileSubject: profile.Subject,
+		Reference:      usesReferenceToken(client, profile),
+	})
+	if errors.Is(err, tokenService.ErrActorChainTooDeep) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "delegation chain too deep"})
+		return
+	}
+	if err != nil {
+		c.logger.Error("Failed to exchange token", zap.Error(err), zap.String("client_id", client.ClientID))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+
+	controllerutil.SetNoCacheHeaders(ctx)
+	ctx.JSON(http.StatusOK, gin.H{
+		"access_token":      accessToken,
+		"issued_token_type": oauth2Domain.TokenTypeAccessToken,
+		"token_type":        accessTokenType(req.dpopJKT),
+		"expires_in":        maxAgeUntil(expiresAt),
+		"scope":             scope,
+	})
+}
+
+// tokenExchangeTargets merges the audience and resource parameters into one de-duplicated
+// list. resource values must be absolute URIs without a fragment (RFC 8693 §2.1).
+// It writes an error response and returns false if the targets are missing or malformed.
+func tokenExchangeTargets(ctx *gin.Context, audiences, resources []string) ([]string, bool) {
+	var targets []string
+	for _, r := range resources {
+		u, err := url.Parse(r)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_target", "error_description": "resource must be an absolute URI without fragment"})
+			return nil, false
+		}
+	}
+	for _, t := range slices.Concat(audiences, resources) {
+		if t != "" && !slices.Contains(targets, t) {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "audience or resource is required"})
+		return nil, false
+	}
+	if len(targets) > maxTokenExchangeTargets {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_target", "error_description": "too many audience or resource values"})
+		return nil, false
+	}
+	return targets, true
+}
+
+// validateExchangeToken validates a subject_token or actor_token issued by this server.
+// A sender-constrained token is only accepted with a DPoP proof from the key, or over a
+// connection with the client certificate, it is bound to (RFC 9449 §5, RFC 8705 §3), so a
+// stolen token cannot be exchanged for an unconstrained one. It writes an error response
+// and returns false if the token is not active or its binding is not proven.
+func (c *OAuth2Controller) validateExchangeToken(ctx *gin.Context, req *TokenRequest, token, param string) (*tokenDomain.AccessTokenClaims, bool) {
+	claims, err := c.tokenSvc.ParseAccessToken(ctx, token)
+	if errors.Is(err, tokenService.ErrBlacklistUnavailable) || errors.Is(err, tokenService.ErrTokenStoreUnavailable) {
+		c.logger.Error("Token revocation check unavailable during token exchange", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return nil, false
+	}
+	if err != nil || claims.AccountID == "" {
+		c.logger.Debug("Token exchange input token rejected", zap.String("param", param), zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "invalid " + param})
+		return nil, false
+	}
+	if jkt := claims.DPoPThumbprint(); jkt != "" && jkt != req.dpopJKT {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": param + " is bound to another DPoP key"})
+		return nil, false
+	}
+	if x5t := claims.CertificateThumbprint(); x5t != "" && x5t != mtls.Thumbprint(mtls.CertificateFromContext(ctx.Request.Context())) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": param + " is bound to another client certificate"})
+		return nil, false
+	}
+	return claims, true
+}
@@ -22,6 +22,12 @@ type AuthorizationCode struct {
 	ExpiresAt           time.Time `json:"expires_at"`
 	AuthTime            time.Time `json:"auth_time"`              // When the user authenticated (consent time)
 	AuthMethods         []string  `json:"auth_methods,omitempty"` // AMR values (e.g. ["pwd"], ["pwd","otp"], ["swk"])
+	// AuthorizationDetails are the approved authorization details (RFC 9396), if any.
+	AuthorizationDetails AuthorizationDetails `json:"authorization_details,omitempty"`
+	// Resources are the protected resources (RFC 8707) the authorization was requested for.
+	Resources []string `json:"resources,omitempty"`
+	// Claims is the claims parameter (OIDC Core §5.5) of the authorization request, if any.
+	Claims *ClaimsRequest `json:"claims,omitempty"`
 }
 
 // NewAuthorizationCode creates a new AuthorizationCode with the required fields.
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Limits for the authorization_details parameter.
+const (
+	MaxAuthorizationDetailsSize = 4096 // bytes of the JSON parameter; form bodies are capped at 8 KiB
+	maxAuthorizationDetails     = 16   // entries per request
+)
+
+// ErrInvalidAuthorizationDetails is returned for a malformed authorization_details parameter.
+var ErrInvalidAuthorizationDetails = errors.New("invalid authorization_details")
+
+// AuthorizationDetail is one object of the authorization_details parameter (RFC 9396 §2).
+// Only type is interpreted; all other members are type specific and kept as is.
+type AuthorizationDetail map[string]any
+
+// Type returns the type member of d.
+func (d AuthorizationDetail) Type() string {
+	t, _ := d["type"].(string)
+	return t
+}
+
+// AuthorizationDetails is the list of authorization details of a request or grant.
+type AuthorizationDetails []AuthorizationDetail
+
+// ParseAuthorizationDetails parses an authorization_details parameter: a non-empty JSON
+// array of objects, each with a string type member (RFC 9396 §2). Empty input returns nil.
+func ParseAuthorizationDetails(raw []byte) (AuthorizationDetails, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+	if len(raw) > MaxAuthorizationDetailsSize {
+		return nil, fmt.Errorf("%w: too large", ErrInvalidAuthorizationDetails)
+	}
+	var details AuthorizationDetails
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return nil, fmt.Errorf("%w: must be a JSON array of objects", ErrInvalidAuthorizationDetails)
+	}
+	if len(details) == 0 || len(details) > maxAuthorizationDetails {
+		return nil, fmt.Errorf("%w: must contain 1 to %d entries", ErrInvalidAuthorizationDetails, maxAuthorizationDetails)
+	}
+	for _, d := range details {
+		if d.Type() == "" {
+			return nil, fmt.Errorf("%w: every entry needs a type", ErrInvalidAuthorizationDetails)
+		}
+	}
+	return details, nil
+}
+
+// Types returns the distinct types of d in order of appearance.
+func (d AuthorizationDetails) Types() []string {
+	var types []string
+	for _, detail := range d {
+		if !slices.Contains(types, detail.Type()) {
+			types = append(types, detail.Type())
+		}
+	}
+	return types
+}
+
+// Covers reports whether every entry of requested equals an entry of d, i.e. requested
+// asks for no more than d grants (RFC 9396 §6.1).
+func (d AuthorizationDetails) Covers(requested AuthorizationDetails) bool {
+	for _, r := range requested {
+		if !slices.ContainsFunc(d, func(granted AuthorizationDetail) bool {
+			return reflect.DeepEqual(granted, r)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// JSON returns the canonical JSON encoding of d (object members sorted), or nil if d is empty.
+func (d AuthorizationDetails) JSON() json.RawMessage {
+	if len(d) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		// Parsed details only hold JSON values, which always marshal.
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const paymentDetails = `[{"type":"payment_initiation","actions":["initiate"],"instructedAmount":{"currency":"EUR","amount":"123.50"},"creditorName":"Merchant A"}]`
+
+func TestParseAuthorizationDetails(t *testing.T) {
+	details, err := ParseAuthorizationDetails([]byte(paymentDetails))
+	require.NoError(t, err)
+	require.Len(t, details, 1)
+	assert.Equal(t, "payment_initiation", details[0].Type())
+	assert.Equal(t, "Merchant A", details[0]["creditorName"])
+
+	details, err = ParseAuthorizationDetails(nil)
+	require.NoError(t, err)
+	assert.Nil(t, details)
+}
+
+func TestParseAuthorizationDetails_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":        `payment_initiation`,
+		"object":          `{"type":"payment_initiation"}`,
+		"empty array":     `[]`,
+		"missing type":    `[{"actions":["initiate"]}]`,
+		"non-string type": `[{"type":42}]`,
+		"non-object":      `["payment_initiation"]`,
+		"too many":        "[" + strings.TrimSuffix(strings.Repeat(`{"type":"a"},`, 17), ",") + "]",
+		"too large":       `[{"type":"a","data":"` + strings.Repeat("x", MaxAuthorizationDetailsSize) + `"}]`,
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseAuthorizationDetails([]byte(raw))
+			assert.ErrorIs(t, err, ErrInvalidAuthorizationDetails)
+		})
+	}
+}
+
+func TestAuthorizationDetails_Types(t *testing.T) {
+	details := AuthorizationDetails{{"type": "b"}, {"type": "a"}, {"type": "b"}}
+	assert.Equal(t, []string{"b", "a"}, details.Types())
+	assert.Nil(t, AuthorizationDetails(nil).Types())
+}
+
+func TestAuthorizationDetails_Covers(t *testing.T) {
+	granted, err := ParseAuthorizationDetails([]byte(`[{"type":"a","amount":"10"},{"type":"b"}]`))
+	require.NoError(t, err)
+
+	subset, _ := ParseAuthorizationDetails([]byte(`[{"amount":"10","type":"a"}]`))
+	assert.True(t, granted.Covers(subset))
+	assert.True(t, granted.Covers(nil))
+
+	changed, _ := ParseAuthorizationDetails([]byte(`[{"type":"a","amount":"1000"}]`))
+	assert.False(t, granted.Covers(changed))
+	assert.False(t, AuthorizationDetails(nil).Covers(subset))
+}
+
+func TestAuthorizationDetails_JSON(t *testing.T) {
+	details, err := ParseAuthorizationDetails([]byte(`[{"type":"a","amount":"10"}]`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"type":"a","amount":"10"}]`, string(details.JSON()))
+	assert.Nil(t, AuthorizationDetails(nil).JSON())
+}
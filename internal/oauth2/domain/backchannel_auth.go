@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Backchannel token delivery modes (OpenID Connect CIBA Core §5).
+const (
+	BackchannelTokenDeliveryPoll = "poll"
+	BackchannelTokenDeliveryPing = "ping"
+	BackchannelTokenDeliveryPush = "push"
+)
+
+// IsValidBackchannelTokenDeliveryMode reports whether mode is a supported CIBA token delivery mode.
+func IsValidBackchannelTokenDeliveryMode(mode string) bool {
+	switch mode {
+	case BackchannelTokenDeliveryPoll, BackchannelTokenDeliveryPing, BackchannelTokenDeliveryPush:
+		return true
+	}
+	return false
+}
+
+// BackchannelDeliveryMode returns the client's CIBA token delivery mode, defaulting to poll.
+func (c *OAuth2Client) BackchannelDeliveryMode() string {
+	if c == nil || c.BackchannelTokenDeliveryMode == "" {
+		return BackchannelTokenDeliveryPoll
+	}
+	return c.BackchannelTokenDeliveryMode
+}
+
+// BackchannelAuthRequest sentinel errors.
+var (
+	ErrBackchannelAuthRequestNotFound = errors.New("backchannel authentication request not found")
+	ErrBackchannelAuthRequestExpired  = errors.New("backchannel authentication request expired")
+)
+
+// BackchannelAuthRequest is a pending client-initiated backchannel authentication request
+// (OpenID Connect CIBA Core). It shares the DeviceCode status lifecycle: pending until the
+// user approves or denies it, then used once tokens have been issued.
+type BackchannelAuthRequest struct {
+	// AuthReqID is the raw auth_req_id. It is only returned to the client and kept for ping
+	// and push clients, which are notified with it after the user has responded.
+	AuthReqID               string           `json:"auth_req_id,omitempty"`
+	ClientID                string           `json:"client_id"`
+	AccountID               string           `json:"account_id"`
+	Scopes                  []string         `json:"scopes"`
+	BindingMessage          string           `json:"binding_message,omitempty"`
+	ClientNotificationToken string           `json:"client_notification_token,omitempty"`
+	DeliveryMode            string           `json:"delivery_mode"`
+	Status                  DeviceCodeStatus `json:"status"`
+	ExpiresAt               time.Time        `json:"expires_at"`
+	AuthorizedAt            time.Time        `json:"authorized_at,omitempty"`
+	LastPollAt              time.Time        `json:"last_poll_at"`
+	Interval                int              `json:"interval"`
+	CreatedAt               time.Time        `json:"created_at"`
+	// ID is the SHA-256 hash of the auth_req_id, used as the Redis key and as the request
+	// identifier in the approval API. It is populated when reading from Redis.
+	ID string `json:"-"`
+}
+
+// IsExpired returns true if the request has passed its expiration time.
+func (r *BackchannelAuthRequest) IsExpired() bool {
+	if r == nil {
+		return true
+	}
+	return time.Now().After(r.ExpiresAt)
+}
+
+// IsPending returns true if the request is still awaiting the user's decision.
+func (r *BackchannelAuthRequest) IsPending() bool {
+	if r == nil {
+		return false
+	}
+	return r.Status == DeviceCodeStatusPending
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Limits for the claims parameter.
+const (
+	MaxClaimsRequestSize = 4096 // bytes of the JSON parameter
+	maxRequestedClaims   = 64   // claims per member
+)
+
+// ErrInvalidClaimsRequest is returned for a malformed claims parameter.
+var ErrInvalidClaimsRequest = errors.New("invalid claims parameter")
+
+// scopeClaims lists the claims each OIDC scope requests (OIDC Core §5.4), limited to the
+// claims gosso releases.
+var scopeClaims = map[string][]string{
+	"profile": {"name", "preferred_username", "picture", "locale"},
+	"email":   {"email", "email_verified"},
+	"phone":   {"phone_number", "phone_number_verified"},
+}
+
+// ScopeClaims returns the claims requested by scopes, in scope order.
+func ScopeClaims(scopes []string) []string {
+	var claims []string
+	for _, scope := range scopes {
+		for _, claim := range scopeClaims[scope] {
+			if !slices.Contains(claims, claim) {
+				claims = append(claims, claim)
+			}
+		}
+	}
+	return claims
+}
+
+// IsUserClaim reports whether name is an end-user claim gosso releases in ID tokens and
+// UserInfo responses.
+func IsUserClaim(name string) bool {
+	for _, claims := range scopeClaims {
+		if slices.Contains(claims, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClaimRequest is the request for one claim (OIDC Core §5.5.1). A claim requested with
+// null has a nil ClaimRequest.
+type ClaimRequest struct {
+	Essential bool  `json:"essential,omitempty"`
+	Value     any   `json:"value,omitempty"`
+	Values    []any `json:"values,omitempty"`
+}
+
+// ClaimsRequest is the claims authorization request parameter (OIDC Core §5.5): the
+// individual claims to return from the UserInfo endpoint and in the ID token.
+type ClaimsRequest struct {
+	UserInfo map[string]*ClaimRequest `json:"userinfo,omitempty"`
+	IDToken  map[string]*ClaimRequest `json:"id_token,omitempty"`
+}
+
+// ParseClaimsRequest parses a claims parameter: a JSON object whose userinfo and id_token
+// members, when present, are objects mapping claim names to null or a claim request.
+// Other members are ignored. Empty input returns nil.
+func ParseClaimsRequest(raw []byte) (*ClaimsRequest, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+	if len(raw) > MaxClaimsRequestSize {
+		return nil, fmt.Errorf("%w: too large", ErrInvalidClaimsRequest)
+	}
+	var r ClaimsRequest
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, fmt.Errorf("%w: must be a JSON object of claim requests", ErrInvalidClaimsRequest)
+	}
+	if len(r.UserInfo) > maxRequestedClaims || len(r.IDToken) > maxRequestedClaims {
+		return nil, fmt.Errorf("%w: at most %d claims per member", ErrInvalidClaimsRequest, maxRequestedClaims)
+	}
+	return &r, nil
+}
+
+// UserInfoClaims returns the end-user claims requested for the UserInfo response, sorted.
+func (r *ClaimsRequest) UserInfoClaims() []string {
+	if r == nil {
+		return nil
+	}
+	return userClaimNames(r.UserInfo)
+}
+
+// IDTokenClaims returns the end-user claims requested for the ID token, sorted.
+func (r *ClaimsRequest) IDTokenClaims() []string {
+	if r == nil {
+		return nil
+	}
+	return userClaimNames(r.IDToken)
+}
+
+// Essential reports whether name is requested as an essential claim in either member.
+func (r *ClaimsRequest) Essential(name string) bool {
+	if r == nil {
+		return false
+	}
+	return r.UserInfo[name] != nil && r.UserInfo[name].Essential ||
+		r.IDToken[name] != nil && r.IDToken[name].Essential
+}
+
+// Subject returns the sub value the request demands, if any. The client may only be
+// answered for that subject (OIDC Core §5.5.1).
+func (r *ClaimsRequest) Subject() (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	for _, member := range []map[string]*ClaimRequest{r.IDToken, r.UserInfo} {
+		if req := member["sub"]; req != nil {
+			if sub, ok := req.Value.(string); ok && sub != "" {
+				return sub, true
+			}
+		}
+	}
+	return "", false
+}
+
+// userClaimNames returns the names in member that are end-user claims, sorted.
+func userClaimNames(member map[string]*ClaimRequest) []string {
+	var names []string
+	for name := range member {
+		if IsUserClaim(name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const emailClaimsRequest = `{"id_token":{"email":{"essential":true},"email_verified":null,"acr":{"values":["urn:mace:incommon:iap:silver"]}},"userinfo":{"name":null,"picture":{"essential":false}}}`
+
+func TestParseClaimsRequest(t *testing.T) {
+	claims, err := ParseClaimsRequest([]byte(emailClaimsRequest))
+	require.NoError(t, err)
+	require.NotNil(t, claims)
+	assert.Equal(t, []string{"email", "email_verified"}, claims.IDTokenClaims())
+	assert.Equal(t, []string{"name", "picture"}, claims.UserInfoClaims())
+	assert.True(t, claims.Essential("email"))
+	assert.False(t, claims.Essential("picture"))
+	assert.False(t, claims.Essential("email_verified"))
+	assert.Equal(t, []any{"urn:mace:incommon:iap:silver"}, claims.IDToken["acr"].Values)
+
+	claims, err = ParseClaimsRequest(nil)
+	require.NoError(t, err)
+	assert.Nil(t, claims)
+	assert.Nil(t, claims.IDTokenClaims())
+	assert.Nil(t, claims.UserInfoClaims())
+	assert.False(t, claims.Essential("email"))
+}
+
+func TestParseClaimsRequest_Invalid(t *testing.T) {
+	many := make([]string, 0, maxRequestedClaims+1)
+	for i := range maxRequestedClaims + 1 {
+		many = append(many, fmt.Sprintf(`"claim_%d":null`, i))
+	}
+	tests := map[string]string{
+		"not json":          `email`,
+		"array":             `[{"userinfo":{}}]`,
+		"non-object member": `{"userinfo":["email"]}`,
+		"non-object claim":  `{"id_token":{"email":true}}`,
+		"too many":          `{"userinfo":{` + strings.Join(many, ",") + `}}`,
+		"too large":         `{"userinfo":{"` + strings.Repeat("x", MaxClaimsRequestSize) + `":null}}`,
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseClaimsRequest([]byte(raw))
+			assert.ErrorIs(t, err, ErrInvalidClaimsRequest)
+		})
+	}
+}
+
+func TestClaimsRequest_Subject(t *testing.T) {
+	claims, err := ParseClaimsRequest([]byte(`{"id_token":{"sub":{"value":"subject-001"}}}`))
+	require.NoError(t, err)
+	sub, ok := claims.Subject()
+	assert.True(t, ok)
+	assert.Equal(t, "subject-001", sub)
+
+	claims, err = ParseClaimsRequest([]byte(`{"userinfo":{"sub":null}}`))
+	require.NoError(t, err)
+	_, ok = claims.Subject()
+	assert.False(t, ok)
+}
+
+func TestScopeClaims(t *testing.T) {
+	assert.Equal(t, []string{"email", "email_verified", "name", "preferred_username", "picture", "locale"},
+		ScopeClaims([]string{"openid", "email", "profile", "email"}))
+	assert.Nil(t, ScopeClaims([]string{"openid"}))
+	assert.True(t, IsUserClaim("phone_number"))
+	assert.False(t, IsUserClaim("sub"))
+}
@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -14,25 +17,60 @@ import (
 
 // OAuth2Client OAuth2 client entity
 type OAuth2Client struct {
-	ID                                string         `json:"id"`
-	AccountID                         string         `json:"account_id"`
-	ClientID                          string         `json:"client_id"`
-	ClientSecretHash                  string         `json:"-"` // Only has value for confidential clients
-	Name                              string         `json:"name"`
-	Description                       string         `json:"description,omitempty"`
-	RedirectURIs                      []string       `json:"redirect_uris"`
-	PostLogoutRedirectURIs            []string       `json:"post_logout_redirect_uris,omitempty"`
-	GrantTypes                        []string       `json:"grant_types"`
-	Scopes                            []string       `json:"scopes"`
-	IsConfidential                    bool           `json:"is_confidential"`
-	Metadata                          map[string]any `json:"metadata"`
-	FrontchannelLogoutURI             string         `json:"frontchannel_logout_uri,omitempty"`
-	FrontchannelLogoutSessionRequired bool           `json:"frontchannel_logout_session_required,omitempty"`
-	BackchannelLogoutURI              string         `json:"backchannel_logout_uri,omitempty"`
-	BackchannelLogoutSessionRequired  bool           `json:"backchannel_logout_session_required,omitempty"`
-	CreatedAt                         time.Time      `json:"created_at"`
-	UpdatedAt                         time.Time      `json:"updated_at"`
-	DeletedAt                         *time.Time     `json:"deleted_at,omitempty"`
+	ID                                    string          `json:"id"`
+	AccountID                             string          `json:"account_id"`
+	ClientID                              string          `json:"client_id"`
+	ClientSecretHash                      string          `json:"-"` // Only has value for confidential clients
+	Name                                  string          `json:"name"`
+	Description                           string          `json:"description,omitempty"`
+	RedirectURIs                          []string        `json:"redirect_uris"`
+	PostLogoutRedirectURIs                []string        `json:"post_logout_redirect_uris,omitempty"`
+	GrantTypes                            []string        `json:"grant_types"`
+	Scopes                                []string        `json:"scopes"`
+	IsConfidential                        bool            `json:"is_confidential"`
+	Metadata                              map[string]any  `json:"metadata"`
+	FrontchannelLogoutURI                 string          `json:"frontchannel_logout_uri,omitempty"`
+	FrontchannelLogoutSessionRequired     bool            `json:"frontchannel_logout_session_required,omitempty"`
+	BackchannelLogoutURI                  string          `json:"backchannel_logout_uri,omitempty"`
+	BackchannelLogoutSessionRequired      bool            `json:"backchannel_logout_session_required,omitempty"`
+	RequirePushedAuthorizationRequests    bool            `json:"require_pushed_authorization_requests,omitempty"`
+	TokenEndpointAuthMethod               string          `json:"token_endpoint_auth_method,omitempty"`
+	JWKS                                  json.RawMessage `json:"jwks,omitempty"`
+	JWKSURI                               string          `json:"jwks_uri,omitempty"`
+	ClientSecretEncrypted                 string          `json:"-"` // Only set for client_secret_jwt clients
+	TokenExchangeAudiences                []string        `json:"token_exchange_audiences,omitempty"`
+	DPoPBoundAccessTokens                 bool            `json:"dpop_bound_access_tokens,omitempty"`
+	TLSClientAuthSubjectDN                string          `json:"tls_client_auth_subject_dn,omitempty"`
+	TLSClientAuthSANDNS                   string          `json:"tls_client_auth_san_dns,omitempty"`
+	TLSClientAuthSANURI                   string          `json:"tls_client_auth_san_uri,omitempty"`
+	TLSClientAuthSANIP                    string          `json:"tls_client_auth_san_ip,omitempty"`
+	TLSClientAuthSANEmail                 string          `json:"tls_client_auth_san_email,omitempty"`
+	TLSClientCertificateBoundAccessTokens bool            `json:"tls_client_certificate_bound_access_tokens,omitempty"`
+	RegistrationAccessTokenHash           string          `json:"-"` // Only set for dynamically registered clients
+	RequireSignedRequestObject            bool            `json:"require_signed_request_object,omitempty"`
+	RequestURIs                           []string        `json:"request_uris,omitempty"`
+	AuthorizationDetailsTypes             []string        `json:"authorization_details_types,omitempty"`
+	BackchannelTokenDeliveryMode          string          `json:"backchannel_token_delivery_mode,omitempty"`
+	BackchannelClientNotificationEndpoint string          `json:"backchannel_client_notification_endpoint,omitempty"`
+	SubjectType                           string          `json:"subject_type,omitempty"`
+	SectorIdentifierURI                   string          `json:"sector_identifier_uri,omitempty"`
+	IDTokenSignedResponseAlg              string          `json:"id_token_signed_response_alg,omitempty"`
+	AccessTokenProfile                    string          `json:"access_token_profile,omitempty"`
+	IDTokenEncryptedResponseAlg           string          `json:"id_token_encrypted_response_alg,omitempty"`
+	IDTokenEncryptedResponseEnc           string          `json:"id_token_encrypted_response_enc,omitempty"`
+	UserinfoEncryptedResponseAlg          string          `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserinfoEncryptedResponseEnc          string          `json:"userinfo_encrypted_response_enc,omitempty"`
+	UserinfoSignedResponseAlg             string          `json:"userinfo_signed_response_alg,omitempty"`
+	RefreshTokenIdleLifetime              int             `json:"refresh_token_idle_lifetime,omitempty"`
+	RefreshTokenAbsoluteLifetime          int             `json:"refresh_token_absolute_lifetime,omitempty"`
+	RefreshTokenExpiration                string          `json:"refresh_token_expiration,omitempty"`
+	AccessTokenLifetime                   int             `json:"access_token_lifetime,omitempty"`
+	IDTokenLifetime                       int             `json:"id_token_lifetime,omitempty"`
+	AuthorizationCodeLifetime             int             `json:"authorization_code_lifetime,omitempty"`
+	DeviceCodeLifetime                    int             `json:"device_code_lifetime,omitempty"`
+	CreatedAt                             time.Time       `json:"created_at"`
+	UpdatedAt                             time.Time       `json:"updated_at"`
+	DeletedAt                             *time.Time      `json:"deleted_at,omitempty"`
 }
 
 const (
@@ -42,6 +80,106 @@ const (
 	adminScopePrefix            = "admin:"
 )
 
+// DefaultIDTokenSigningAlg is the ID token signing algorithm of clients that did not
+// register id_token_signed_response_alg (OpenID Connect Registration §2).
+const DefaultIDTokenSigningAlg = "RS256"
+
+// IDTokenSigningAlg returns the JWS algorithm the client's ID tokens are signed with.
+func (c *OAuth2Client) IDTokenSigningAlg() string {
+	if c == nil || c.IDTokenSignedResponseAlg == "" {
+		return DefaultIDTokenSigningAlg
+	}
+	return c.IDTokenSignedResponseAlg
+}
+
+// DefaultEncryptedResponseEnc is the content encryption algorithm of clients that registered
+// an *_encrypted_response_alg without the matching *_encrypted_response_enc (OpenID Connect
+// Registration §2).
+const DefaultEncryptedResponseEnc = "A128CBC-HS256"
+
+// EncryptsResponses reports whether the client's ID tokens or UserInfo responses are
+// encrypted to a key from its jwks or jwks_uri.
+func (c *OAuth2Client) EncryptsResponses() bool {
+	return c.IDTokenEncryptedResponseAlg != "" || c.UserinfoEncryptedResponseAlg != ""
+}
+
+// Access token profiles of clients and protected resources.
+const (
+	// AccessTokenProfileDefault issues gosso's own access token format.
+	AccessTokenProfileDefault = ""
+	// AccessTokenProfileRFC9068 issues the JWT profile for OAuth 2.0 access tokens (RFC 9068).
+	AccessTokenProfileRFC9068 = "rfc9068"
+	// AccessTokenProfileReference issues opaque reference tokens that resource servers
+	// introspect. It is a client profile only; protected resources requiring RFC 9068
+	// still receive JWTs.
+	AccessTokenProfileReference = "reference"
+)
+
+// IsValidAccessTokenProfile reports whether profile is a supported access token profile.
+func IsValidAccessTokenProfile(profile string) bool {
+	return profile == AccessTokenProfileDefault || profile == AccessTokenProfileRFC9068 ||
+		profile == AccessTokenProfileReference
+}
+
+// Refresh token expiration modes. Lifetimes are in seconds; a zero idle lifetime applies the
+// server default and a zero absolute lifetime sets no limit.
+const (
+	// RefreshTokenExpirationSliding starts a new idle lifetime on every rotation, up to the
+	// absolute lifetime of the grant. It is the default.
+	RefreshTokenExpirationSliding = "sliding"
+	// RefreshTokenExpirationFixed keeps the expiry of the first refresh token of the grant
+	// across rotations.
+	RefreshTokenExpirationFixed = "fixed"
+)
+
+// IsValidRefreshTokenExpiration reports whether mode is a supported refresh token
+// expiration mode; empty means sliding.
+func IsValidRefreshTokenExpiration(mode string) bool {
+	return mode == "" || mode == RefreshTokenExpirationSliding || mode == RefreshTokenExpirationFixed
+}
+
+// HasRefreshTokenPolicy reports whether the client overrides the server's refresh token
+// lifetime policy.
+func (c *OAuth2Client) HasRefreshTokenPolicy() bool {
+	return c != nil && (c.RefreshTokenIdleLifetime > 0 || c.RefreshTokenAbsoluteLifetime > 0 ||
+		c.RefreshTokenExpiration == RefreshTokenExpirationFixed)
+}
+
+// Per-client lifetimes are in seconds; zero applies the server default. Each accessor returns
+// zero for a nil client.
+
+// AccessTokenExpiry returns the lifetime of the client's access tokens.
+func (c *OAuth2Client) AccessTokenExpiry() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.AccessTokenLifetime) * time.Second
+}
+
+// IDTokenExpiry returns the lifetime of the client's ID tokens.
+func (c *OAuth2Client) IDTokenExpiry() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.IDTokenLifetime) * time.Second
+}
+
+// AuthorizationCodeExpiry returns the lifetime of the client's authorization codes.
+func (c *OAuth2Client) AuthorizationCodeExpiry() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.AuthorizationCodeLifetime) * time.Second
+}
+
+// DeviceCodeExpiry returns the lifetime of the client's device codes.
+func (c *OAuth2Client) DeviceCodeExpiry() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.DeviceCodeLifetime) * time.Second
+}
+
 // ValidateRedirectURI validates that the redirect URI is in the registered list.
 // Uses constant-time comparison throughout: all registered URIs are checked even
 // after a match is found, to avoid leaking the matched position via timing.
@@ -137,24 +275,107 @@ func IsAdminScope(scope string) bool {
 	return scope == ScopeAdmin || strings.HasPrefix(scope, adminScopePrefix)
 }
 
+// Token endpoint authentication methods (RFC 7591 §2, OIDC Core §9).
+// An empty TokenEndpointAuthMethod keeps the legacy behaviour: client_secret_basic or
+// client_secret_post for confidential clients and none for public clients.
+const (
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodClientSecretJWT   = "client_secret_jwt"
+	AuthMethodPrivateKeyJWT     = "private_key_jwt"
+	AuthMethodNone              = "none"
+
+	// Mutual-TLS client authentication methods (RFC 8705 §2).
+	AuthMethodTLSClientAuth           = "tls_client_auth"
+	AuthMethodSelfSignedTLSClientAuth = "self_signed_tls_client_auth"
+)
+
+// UsesClientAssertion reports whether the client must authenticate with a JWT
+// client assertion (RFC 7523) instead of presenting its client_secret.
+func (c *OAuth2Client) UsesClientAssertion() bool {
+	if c == nil {
+		return false
+	}
+	return c.TokenEndpointAuthMethod == AuthMethodClientSecretJWT || c.TokenEndpointAuthMethod == AuthMethodPrivateKeyJWT
+}
+
+// UsesTLSClientAuth reports whether the client authenticates with a TLS client
+// certificate (RFC 8705 §2) instead of presenting its client_secret.
+func (c *OAuth2Client) UsesTLSClientAuth() bool {
+	if c == nil {
+		return false
+	}
+	return c.TokenEndpointAuthMethod == AuthMethodTLSClientAuth || c.TokenEndpointAuthMethod == AuthMethodSelfSignedTLSClientAuth
+}
+
+// HashRegistrationAccessToken returns the stored form of a registration access token
+// (RFC 7592 §1): its hex-encoded SHA-256 hash. The tokens are random 256-bit values, so a
+// fast hash is sufficient.
+func HashRegistrationAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyRegistrationAccessToken reports whether token is the registration access token
+// issued to the client at dynamic registration. Clients created through the client
+// management API have none and never match.
+func (c *OAuth2Client) VerifyRegistrationAccessToken(token string) bool {
+	if c == nil || c.RegistrationAccessTokenHash == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashRegistrationAccessToken(token)), []byte(c.RegistrationAccessTokenHash)) == 1
+}
+
+// CanExchangeForAudience reports whether the client's token exchange policy allows it
+// to request a token for the given audience or resource (RFC 8693 §2.1).
+func (c *OAuth2Client) CanExchangeForAudience(audience string) bool {
+	if c == nil || audience == "" {
+		return false
+	}
+	return slices.Contains(c.TokenExchangeAudiences, audience)
+}
+
+// AllowsAuthorizationDetails reports whether every entry of details has a type the
+// client is registered for (RFC 9396 §5).
+func (c *OAuth2Client) AllowsAuthorizationDetails(details AuthorizationDetails) bool {
+	if c == nil {
+		return false
+	}
+	for _, t := range details.Types() {
+		if !slices.Contains(c.AuthorizationDetailsTypes, t) {
+			return false
+		}
+	}
+	return true
+}
+
 // Grant Type constants
 const (
 	GrantTypeAuthorizationCode = "authorization_code"
 	GrantTypeRefreshToken      = "refresh_token"
 	GrantTypeClientCredentials = "client_credentials"
 	GrantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+	GrantTypeJWTBearer         = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	GrantTypeCIBA              = "urn:openid:params:grant-type:ciba"
 )
 
 // IsValidGrantType reports whether gt is a known OAuth2 grant type.
 func IsValidGrantType(gt string) bool {
 	switch gt {
 	case GrantTypeAuthorizationCode, GrantTypeRefreshToken,
-		GrantTypeClientCredentials, GrantTypeDeviceCode:
+		GrantTypeClientCredentials, GrantTypeDeviceCode, GrantTypeTokenExchange,
+		GrantTypeJWTBearer, GrantTypeCIBA:
 		return true
 	}
 	return false
 }
 
+// Token type identifiers used by token exchange (RFC 8693 §3).
+const (
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+)
+
 // Error definitions
 var (
 	ErrClientNotFound               = errors.New("oauth2 client not found")
@@ -186,6 +186,8 @@ func TestIsValidGrantType_AllValid(t *testing.T) {
 	assert.True(t, IsValidGrantType("refresh_token"))
 	assert.True(t, IsValidGrantType("client_credentials"))
 	assert.True(t, IsValidGrantType("urn:ietf:params:oauth:grant-type:device_code"))
+	assert.True(t, IsValidGrantType("urn:ietf:params:oauth:grant-type:token-exchange"))
+	assert.True(t, IsValidGrantType("urn:ietf:params:oauth:grant-type:jwt-bearer"))
 }
 
 func TestIsValidGrantType_Invalid(t *testing.T) {
@@ -198,3 +200,77 @@ func TestNewOAuth2Client_InvalidGrantType(t *testing.T) {
 	_, err := NewOAuth2Client("account-1", "Test App", "client-123", []string{"authorization_code", "fake_grant"})
 	assert.ErrorIs(t, err, ErrClientInvalidGrantType)
 }
+
+// ──────────────────────────────────────────────
+// CanExchangeForAudience
+// ──────────────────────────────────────────────
+
+func TestCanExchangeForAudience(t *testing.T) {
+	c := &OAuth2Client{TokenExchangeAudiences: []string{"orders-api", "https://billing.example.com"}}
+	assert.True(t, c.CanExchangeForAudience("orders-api"))
+	assert.True(t, c.CanExchangeForAudience("https://billing.example.com"))
+	assert.False(t, c.CanExchangeForAudience("payments-api"))
+	assert.False(t, c.CanExchangeForAudience(""))
+	assert.False(t, (&OAuth2Client{}).CanExchangeForAudience("orders-api"))
+
+	var nilClient *OAuth2Client
+	assert.False(t, nilClient.CanExchangeForAudience("orders-api"))
+}
+
+func TestAllowsAuthorizationDetails(t *testing.T) {
+	c := &OAuth2Client{AuthorizationDetailsTypes: []string{"payment_initiation", "account_information"}}
+	assert.True(t, c.AllowsAuthorizationDetails(AuthorizationDetails{{"type": "payment_initiation"}}))
+	assert.True(t, c.AllowsAuthorizationDetails(AuthorizationDetails{{"type": "payment_initiation"}, {"type": "account_information"}}))
+	assert.False(t, c.AllowsAuthorizationDetails(AuthorizationDetails{{"type": "payment_initiation"}, {"type": "tax_filing"}}))
+	assert.False(t, (&OAuth2Client{}).AllowsAuthorizationDetails(AuthorizationDetails{{"type": "payment_initiation"}}))
+
+	var nilClient *OAuth2Client
+	assert.False(t, nilClient.AllowsAuthorizationDetails(AuthorizationDetails{{"type": "payment_initiation"}}))
+}
+
+func TestVerifyRegistrationAccessToken(t *testing.T) {
+	client := &OAuth2Client{RegistrationAccessTokenHash: HashRegistrationAccessToken("rat-123")}
+	assert.True(t, client.VerifyRegistrationAccessToken("rat-123"))
+	assert.False(t, client.VerifyRegistrationAccessToken("rat-124"))
+	assert.False(t, client.VerifyRegistrationAccessToken(""))
+
+	// Clients created through the management API have no registration access token.
+	assert.False(t, (&OAuth2Client{}).VerifyRegistrationAccessToken(""))
+	assert.False(t, (*OAuth2Client)(nil).VerifyRegistrationAccessToken("rat-123"))
+}
+
+func TestSectorIdentifier(t *testing.T) {
+	c := &OAuth2Client{RedirectURIs: []string{"https://app.example.com:8443/callback", "https://app.example.com/other"}}
+	assert.Equal(t, "app.example.com", c.SectorIdentifier())
+
+	c.SectorIdentifierURI = "https://sector.example.org/redirect_uris.json"
+	assert.Equal(t, "sector.example.org", c.SectorIdentifier())
+
+	assert.Empty(t, (&OAuth2Client{}).SectorIdentifier())
+}
+
+func TestUsesPairwiseSubject(t *testing.T) {
+	assert.True(t, (&OAuth2Client{SubjectType: SubjectTypePairwise}).UsesPairwiseSubject())
+	assert.False(t, (&OAuth2Client{SubjectType: SubjectTypePublic}).UsesPairwiseSubject())
+	assert.False(t, (&OAuth2Client{}).UsesPairwiseSubject())
+	assert.False(t, (*OAuth2Client)(nil).UsesPairwiseSubject())
+}
+
+func TestEncryptsResponses(t *testing.T) {
+	assert.False(t, (&OAuth2Client{}).EncryptsResponses())
+	assert.True(t, (&OAuth2Client{IDTokenEncryptedResponseAlg: "RSA-OAEP"}).EncryptsResponses())
+	assert.True(t, (&OAuth2Client{UserinfoEncryptedResponseAlg: "RSA-OAEP-256"}).EncryptsResponses())
+}
+
+func TestIsValidAccessTokenProfile(t *testing.T) {
+	assert.True(t, IsValidAccessTokenProfile(AccessTokenProfileDefault))
+	assert.True(t, IsValidAccessTokenProfile(AccessTokenProfileRFC9068))
+	assert.True(t, IsValidAccessTokenProfile(AccessTokenProfileReference))
+	assert.False(t, IsValidAccessTokenProfile("jwt"))
+}
+
+func TestIDTokenSigningAlg(t *testing.T) {
+	assert.Equal(t, "ES256", (&OAuth2Client{IDTokenSignedResponseAlg: "ES256"}).IDTokenSigningAlg())
+	assert.Equal(t, DefaultIDTokenSigningAlg, (&OAuth2Client{}).IDTokenSigningAlg())
+	assert.Equal(t, DefaultIDTokenSigningAlg, (*OAuth2Client)(nil).IDTokenSigningAlg())
+}
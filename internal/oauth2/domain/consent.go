@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,14 +24,16 @@ const maxConsentScopes = 64 // Maximum number of scopes per consent
 
 // Consent user authorization consent record for an OAuth2 client
 type Consent struct {
-	ID        string     `json:"id"`
-	AccountID string     `json:"account_id"`
-	ClientID  string     `json:"client_id"`
-	Scopes    []string   `json:"scopes"`
-	GrantedAt time.Time  `json:"granted_at"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID        string   `json:"id"`
+	AccountID string   `json:"account_id"`
+	ClientID  string   `json:"client_id"`
+	Scopes    []string `json:"scopes"`
+	// AuthorizationDetails are the authorization details (RFC 9396) the user last approved.
+	AuthorizationDetails AuthorizationDetails `json:"authorization_details,omitempty"`
+	GrantedAt            time.Time            `json:"granted_at"`
+	CreatedAt            time.Time            `json:"created_at"`
+	UpdatedAt            time.Time            `json:"updated_at"`
+	DeletedAt            *time.Time           `json:"deleted_at,omitempty"`
 }
 
 // NewConsent creates a new Consent with the required fields.
@@ -68,3 +71,15 @@ func NewConsent(accountID, clientID string, scopes []string) (*Consent, error) {
 		UpdatedAt: time.Now(),
 	}, nil
 }
+
+// CoversClaims reports whether the consented scopes request every end-user claim of claims
+// (OIDC Core §5.4), so releasing them needs no further consent.
+func (c *Consent) CoversClaims(claims *ClaimsRequest) bool {
+	consented := ScopeClaims(c.Scopes)
+	for _, name := range append(claims.IDTokenClaims(), claims.UserInfoClaims()...) {
+		if !slices.Contains(consented, name) {
+			return false
+		}
+	}
+	return true
+}
@@ -42,3 +42,17 @@ func TestNewConsent_EmptyScopes(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, consent.Scopes)
 }
+
+func TestConsent_CoversClaims(t *testing.T) {
+	consent, err := NewConsent("account-001", "client-001", []string{"openid", "email"})
+	require.NoError(t, err)
+
+	covered, err := ParseClaimsRequest([]byte(`{"id_token":{"email":{"essential":true}},"userinfo":{"acr":null}}`))
+	require.NoError(t, err)
+	assert.True(t, consent.CoversClaims(covered))
+	assert.True(t, consent.CoversClaims(nil))
+
+	uncovered, err := ParseClaimsRequest([]byte(`{"userinfo":{"name":null}}`))
+	require.NoError(t, err)
+	assert.False(t, consent.CoversClaims(uncovered))
+}
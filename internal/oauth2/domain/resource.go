@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+var (
+	// ErrInvalidTarget is returned when a resource indicator is malformed, not registered,
+	// or not covered by the grant (RFC 8707 §2, error code invalid_target).
+	ErrInvalidTarget = errors.New("invalid resource indicator")
+	// ErrResourceScopeNotAllowed is returned when none of the requested scopes is allowed
+	// for the requested resources.
+	ErrResourceScopeNotAllowed = errors.New("no requested scope is allowed for the resource")
+)
+
+// ProtectedResource is a resource server that access tokens can be restricted to (RFC 8707).
+type ProtectedResource struct {
+	// Identifier is the resource indicator clients send in the resource parameter.
+	Identifier string
+	// Scopes are the scopes tokens for the resource may carry.
+	Scopes []string
+	// AccessTokenExpiry, when positive, replaces the default access token lifetime.
+	AccessTokenExpiry time.Duration
+	// AccessTokenProfile is the format of tokens for the resource; see OAuth2Client.
+	AccessTokenProfile string
+	// ClientIDs are the clients the resource server authenticates as. They may introspect
+	// tokens whose audience includes the resource.
+	ClientIDs []string
+}
+
+// ResourceTarget describes an access token restricted to protected resources.
+type ResourceTarget struct {
+	// Audience lists the resource identifiers, which form the token's aud claim.
+	Audience []string
+	// Scopes are the requested scopes allowed for at least one of the resources.
+	Scopes []string
+	// Lifetime is the shortest AccessTokenExpiry of the resources; zero uses the default.
+	Lifetime time.Duration
+	// AccessTokenProfile is AccessTokenProfileRFC9068 when any of the resources requires
+	// RFC 9068 access tokens.
+	AccessTokenProfile string
+}
+
+// IsValidResourceIndicator reports whether resource is an absolute URI without a fragment
+// (RFC 8707 §2).
+func IsValidResourceIndicator(resource string) bool {
+	u, err := url.Parse(resource)
+	return err == nil && u.IsAbs() && u.Fragment == ""
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+)
+
+// Subject identifier types (OpenID Connect Core §8).
+const (
+	SubjectTypePublic   = "public"
+	SubjectTypePairwise = "pairwise"
+)
+
+// ErrPairwiseSubjectNotFound is returned when a pairwise subject cannot be mapped back to an account.
+var ErrPairwiseSubjectNotFound = errors.New("pairwise subject not found")
+
+// IsValidSubjectType reports whether subjectType is a supported subject identifier type.
+func IsValidSubjectType(subjectType string) bool {
+	return subjectType == SubjectTypePublic || subjectType == SubjectTypePairwise
+}
+
+// UsesPairwiseSubject reports whether the client receives pairwise subject identifiers.
+func (c *OAuth2Client) UsesPairwiseSubject() bool {
+	return c != nil && c.SubjectType == SubjectTypePairwise
+}
+
+// SectorIdentifier returns the sector a pairwise client belongs to (OpenID Connect Core §8.1):
+// the host of its sector_identifier_uri, or else the host of its redirect URIs. It returns
+// an empty string when neither yields a host.
+func (c *OAuth2Client) SectorIdentifier() string {
+	if c.SectorIdentifierURI != "" {
+		return uriHost(c.SectorIdentifierURI)
+	}
+	if len(c.RedirectURIs) == 0 {
+		return ""
+	}
+	return uriHost(c.RedirectURIs[0])
+}
+
+// uriHost returns the host of uri without the port, or an empty string if uri cannot be parsed.
+func uriHost(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
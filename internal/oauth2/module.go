@@ -1,38 +1,61 @@
 package oauth2
 
 import (
+	"crypto/rsa"
+	"crypto/x509"
 	"database/sql"
 	"fmt"
+	"os"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/rushairer/gosso/config"
 	auditService "github.com/rushairer/gosso/internal/audit/service"
 	"github.com/rushairer/gosso/internal/cache"
+	"github.com/rushairer/gosso/internal/oauth2/domain"
 	"github.com/rushairer/gosso/internal/oauth2/repository"
 	"github.com/rushairer/gosso/internal/oauth2/service"
 )
 
 // OAuth2Module holds all initialized OAuth2 services and repositories.
 type OAuth2Module struct {
-	ClientService     service.OAuth2ClientService
-	AuthCodeService   *service.AuthCodeService
-	ConsentService    *service.ConsentService
-	DeviceCodeService *service.DeviceCodeService
-	ClientRepo        repository.OAuth2ClientRepository
+	ClientService       service.OAuth2ClientService
+	AuthCodeService     *service.AuthCodeService
+	ConsentService      *service.ConsentService
+	DeviceCodeService   *service.DeviceCodeService
+	ClientAuthenticator *service.ClientAuthenticator
+	// JWTBearerVerifier is nil when auth.jwt_bearer_issuers is empty.
+	JWTBearerVerifier *service.JWTBearerVerifier
+	// ClientRegistration serves dynamic client registration (RFC 7591 / RFC 7592).
+	ClientRegistration *service.ClientRegistrationService
+	// RequestObjectVerifier verifies JWT-secured authorization requests (RFC 9101).
+	RequestObjectVerifier *service.RequestObjectVerifier
+	// SubjectIdentifiers derives the public or pairwise subject identifier each client sees.
+	SubjectIdentifiers *service.SubjectIdentifierService
+	// Resources holds the protected resources of auth.protected_resources (RFC 8707).
+	Resources  *service.ResourceRegistry
+	ClientRepo repository.OAuth2ClientRepository
 }
 
-// InitializeOAuth2Module initializes the OAuth2 module
+// InitializeOAuth2Module initializes the OAuth2 module. requestObjectKey decrypts encrypted
+// request objects and is nil when auth.request_object_encryption_key_path is empty.
 func InitializeOAuth2Module(
 	db *sql.DB,
 	redis *cache.RedisClient,
 	logger *zap.Logger,
 	authConfig config.AuthConfig,
 	auditor *auditService.Auditor,
+	requestObjectKey *rsa.PrivateKey,
 ) (*OAuth2Module, error) {
 	clientRepo := repository.NewOAuth2ClientRepository(db)
 	consentRepo := repository.NewConsentRepository(db)
-	clientSvc := service.NewOAuth2ClientService(db, clientRepo, auditor, logger)
+	secretCipher, err := service.NewClientSecretCipher(authConfig.ClientSecretEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("initialize client secret cipher: %w", err)
+	}
+	subjectIdentifiers := service.NewSubjectIdentifierService(authConfig.PairwiseSubjectSalt, clientRepo, repository.NewPairwiseSubjectRepository(db), nil)
+	clientSvc := service.NewOAuth2ClientService(db, clientRepo, auditor, logger, secretCipher, subjectIdentifiers, authConfig.SigningAlgs, clientLifetimeLimits(authConfig))
 	authCodeSvc, err := service.NewAuthCodeService(redis, logger, authConfig.AuthorizationCodeExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("initialize auth code service: %w", err)
@@ -45,12 +68,118 @@ func InitializeOAuth2Module(
 	if err != nil {
 		return nil, fmt.Errorf("initialize device code service: %w", err)
 	}
+	jwtBearerVerifier, err := service.NewJWTBearerVerifier(redis, trustedJWTIssuers(authConfig.JWTBearerIssuers), nil)
+	if err != nil {
+		return nil, fmt.Errorf("initialize jwt bearer verifier: %w", err)
+	}
+	clientRegistration, err := service.NewClientRegistrationService(clientSvc, redis, auditor, logger, service.ClientRegistrationConfig{
+		SoftwareStatementIssuers: softwareStatementIssuers(authConfig.SoftwareStatementIssuers),
+		RequireSoftwareStatement: authConfig.RequireSoftwareStatement,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initialize client registration service: %w", err)
+	}
+
+	clientAuth := service.NewClientAuthenticator(redis, secretCipher, nil)
+	if path := authConfig.TLSClientAuthCAPath; path != "" {
+		roots, err := loadCertPool(path)
+		if err != nil {
+			return nil, fmt.Errorf("load tls_client_auth CAs: %w", err)
+		}
+		clientAuth.SetTLSClientAuthRoots(roots)
+	}
 
 	return &OAuth2Module{
-		ClientService:     clientSvc,
-		AuthCodeService:   authCodeSvc,
-		ConsentService:    consentSvc,
-		DeviceCodeService: deviceCodeSvc,
-		ClientRepo:        clientRepo,
+		ClientService:         clientSvc,
+		AuthCodeService:       authCodeSvc,
+		ConsentService:        consentSvc,
+		DeviceCodeService:     deviceCodeSvc,
+		ClientAuthenticator:   clientAuth,
+		JWTBearerVerifier:     jwtBearerVerifier,
+		ClientRegistration:    clientRegistration,
+		RequestObjectVerifier: service.NewRequestObjectVerifier(clientAuth, authConfig.Issuer, requestObjectKey, nil),
+		SubjectIdentifiers:    subjectIdentifiers,
+		Resources:             service.NewResourceRegistry(protectedResources(authConfig.ProtectedResources)),
+		ClientRepo:            clientRepo,
 	}, nil
 }
+
+// loadCertPool reads a PEM bundle of CA certificates.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates in %s", path)
+	}
+	return pool, nil
+}
+
+// clientLifetimeLimits returns the lifetime ceilings of per-client overrides. An unset
+// ceiling is the server-wide lifetime.
+func clientLifetimeLimits(cfg config.AuthConfig) service.ClientLifetimeLimits {
+	ceiling := func(limit, expiry time.Duration) time.Duration {
+		if limit > 0 {
+			return limit
+		}
+		return expiry
+	}
+	return service.ClientLifetimeLimits{
+		AccessToken:       ceiling(cfg.MaxClientAccessTokenExpiry, cfg.AccessTokenExpiry),
+		IDToken:           ceiling(cfg.MaxClientIDTokenExpiry, cfg.IDTokenExpiry),
+		RefreshToken:      ceiling(cfg.MaxClientRefreshTokenExpiry, cfg.RefreshTokenExpiry),
+		AuthorizationCode: ceiling(cfg.MaxClientAuthorizationCodeExpiry, cfg.AuthorizationCodeExpiry),
+		DeviceCode:        ceiling(cfg.MaxClientDeviceCodeExpiry, cfg.DeviceCodeExpiry),
+	}
+}
+
+// trustedJWTIssuers converts the jwt-bearer issuer configuration to service types.
+func trustedJWTIssuers(cfgs []config.JWTBearerIssuerConfig) []service.TrustedJWTIssuer {
+	issuers := make([]service.TrustedJWTIssuer, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		iss := service.TrustedJWTIssuer{Issuer: cfg.Issuer, JWKSURI: cfg.JWKSURI}
+		if cfg.JWKS != "" {
+			iss.JWKS = []byte(cfg.JWKS)
+		}
+		for _, sub := range cfg.Subjects {
+			iss.Subjects = append(iss.Subjects, service.JWTBearerSubject{
+				Subject:   sub.Subject,
+				AccountID: sub.AccountID,
+				ClientID:  sub.ClientID,
+				Scopes:    sub.Scopes,
+			})
+		}
+		issuers = append(issuers, iss)
+	}
+	return issuers
+}
+
+// softwareStatementIssuers converts the software statement issuer configuration to service types.
+func softwareStatementIssuers(cfgs []config.SoftwareStatementIssuerConfig) []service.SoftwareStatementIssuer {
+	issuers := make([]service.SoftwareStatementIssuer, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		iss := service.SoftwareStatementIssuer{Issuer: cfg.Issuer, JWKSURI: cfg.JWKSURI}
+		if cfg.JWKS != "" {
+			iss.JWKS = []byte(cfg.JWKS)
+		}
+		issuers = append(issuers, iss)
+	}
+	return issuers
+}
+
+// protectedResources converts the protected resource configuration to domain types.
+func protectedResources(cfgs []config.ProtectedResourceConfig) []domain.ProtectedResource {
+	resources := make([]domain.ProtectedResource, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		resources = append(resources, domain.ProtectedResource{
+			Identifier:         cfg.Identifier,
+			Scopes:             cfg.Scopes,
+			AccessTokenExpiry:  cfg.AccessTokenExpiry,
+			AccessTokenProfile: cfg.AccessTokenProfile,
+			ClientIDs:          cfg.ClientIDs,
+		})
+	}
+	return resources
+}
@@ -20,13 +20,16 @@ func NewOAuth2ClientRepository(db *sql.DB) OAuth2ClientRepository {
 	return &oauth2ClientRepositoryImpl{db: db}
 }
 
-// clientJSONFields holds raw JSON bytes for the five JSON columns of an oauth2_clients row.
+// clientJSONFields holds raw JSON bytes for the eight JSON columns of an oauth2_clients row.
 type clientJSONFields struct {
-	redirectURIs   []byte
-	postLogoutURIs []byte
-	grantTypes     []byte
-	scopes         []byte
-	metadata       []byte
+	redirectURIs           []byte
+	postLogoutURIs         []byte
+	grantTypes             []byte
+	scopes                 []byte
+	metadata               []byte
+	tokenExchangeAudiences []byte
+	requestURIs            []byte
+	authzDetailsTypes      []byte
 }
 
 // unmarshalClientJSONFields populates an OAuth2Client's JSON columns from raw bytes.
@@ -48,6 +51,21 @@ func unmarshalClientJSONFields(client *domain.OAuth2Client, f *clientJSONFields)
 			return fmt.Errorf("unmarshal metadata: %w", err)
 		}
 	}
+	if f.tokenExchangeAudiences != nil {
+		if err := json.Unmarshal(f.tokenExchangeAudiences, &client.TokenExchangeAudiences); err != nil {
+			return fmt.Errorf("unmarshal token_exchange_audiences: %w", err)
+		}
+	}
+	if f.requestURIs != nil {
+		if err := json.Unmarshal(f.requestURIs, &client.RequestURIs); err != nil {
+			return fmt.Errorf("unmarshal request_uris: %w", err)
+		}
+	}
+	if f.authzDetailsTypes != nil {
+		if err := json.Unmarshal(f.authzDetailsTypes, &client.AuthorizationDetailsTypes); err != nil {
+			return fmt.Errorf("unmarshal authorization_details_types: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -76,12 +94,39 @@ func marshalClientJSONFields(client *domain.OAuth2Client) (*clientJSONFields, er
 			return nil, fmt.Errorf("marshal metadata: %w", err)
 		}
 	}
+	exchangeAudiences := client.TokenExchangeAudiences
+	if exchangeAudiences == nil {
+		exchangeAudiences = []string{}
+	}
+	tokenExchangeAudiences, err := json.Marshal(exchangeAudiences)
+	if err != nil {
+		return nil, fmt.Errorf("marshal token_exchange_audiences: %w", err)
+	}
+	registeredRequestURIs := client.RequestURIs
+	if registeredRequestURIs == nil {
+		registeredRequestURIs = []string{}
+	}
+	requestURIs, err := json.Marshal(registeredRequestURIs)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request_uris: %w", err)
+	}
+	detailsTypes := client.AuthorizationDetailsTypes
+	if detailsTypes == nil {
+		detailsTypes = []string{}
+	}
+	authzDetailsTypes, err := json.Marshal(detailsTypes)
+	if err != nil {
+		return nil, fmt.Errorf("marshal authorization_details_types: %w", err)
+	}
 	return &clientJSONFields{
-		redirectURIs:   redirectURIs,
-		postLogoutURIs: postLogoutURIs,
-		grantTypes:     grantTypes,
-		scopes:         scopes,
-		metadata:       metadata,
+		redirectURIs:           redirectURIs,
+		postLogoutURIs:         postLogoutURIs,
+		grantTypes:             grantTypes,
+		scopes:                 scopes,
+		metadata:               metadata,
+		tokenExchangeAudiences: tokenExchangeAudiences,
+		requestURIs:            requestURIs,
+		authzDetailsTypes:      authzDetailsTypes,
 	}, nil
 }
 
@@ -92,8 +137,8 @@ func (r *oauth2ClientRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, cli
 	}
 
 	query := `
-		INSERT INTO oauth2_clients (account_id, client_id, client_secret_hash, name, description, redirect_uris, post_logout_redirect_uris, grant_types, scopes, is_confidential, metadata, frontchannel_logout_uri, frontchannel_logout_session_required, backchannel_logout_uri, backchannel_logout_session_required)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		INSERT INTO oauth2_clients (account_id, client_id, client_secret_hash, name, description, redirect_uris, post_logout_redirect_uris, grant_types, scopes, is_confidential, metadata, frontchannel_logout_uri, frontchannel_logout_session_required, backchannel_logout_uri, backchannel_logout_session_required, require_pushed_authorization_requests, token_endpoint_auth_method, jwks, jwks_uri, client_secret_encrypted, token_exchange_audiences, dpop_bound_access_tokens, tls_client_auth_subject_dn, tls_client_auth_san_dns, tls_client_auth_san_uri, tls_client_auth_san_ip, tls_client_auth_san_email, tls_client_certificate_bound_access_tokens, registration_access_token_hash, require_signed_request_object, request_uris, authorization_details_types, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, subject_type, sector_identifier_uri, id_token_signed_response_alg, access_token_profile, id_token_encrypted_response_alg, id_token_encrypted_response_enc, userinfo_encrypted_response_alg, userinfo_encrypted_response_enc, userinfo_signed_response_alg, refresh_token_idle_lifetime, refresh_token_absolute_lifetime, refresh_token_expiration, access_token_lifetime, id_token_lifetime, authorization_code_lifetime, device_code_lifetime)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46, $47, $48, $49, $50)
 		RETURNING id, created_at, updated_at`
 
 	err = tx.QueryRowContext(ctx, query,
@@ -112,6 +157,41 @@ func (r *oauth2ClientRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, cli
 		client.FrontchannelLogoutSessionRequired,
 		client.BackchannelLogoutURI,
 		client.BackchannelLogoutSessionRequired,
+		client.RequirePushedAuthorizationRequests,
+		client.TokenEndpointAuthMethod,
+		string(client.JWKS),
+		client.JWKSURI,
+		client.ClientSecretEncrypted,
+		f.tokenExchangeAudiences,
+		client.DPoPBoundAccessTokens,
+		client.TLSClientAuthSubjectDN,
+		client.TLSClientAuthSANDNS,
+		client.TLSClientAuthSANURI,
+		client.TLSClientAuthSANIP,
+		client.TLSClientAuthSANEmail,
+		client.TLSClientCertificateBoundAccessTokens,
+		client.RegistrationAccessTokenHash,
+		client.RequireSignedRequestObject,
+		f.requestURIs,
+		f.authzDetailsTypes,
+		client.BackchannelTokenDeliveryMode,
+		client.BackchannelClientNotificationEndpoint,
+		client.SubjectType,
+		client.SectorIdentifierURI,
+		client.IDTokenSignedResponseAlg,
+		client.AccessTokenProfile,
+		client.IDTokenEncryptedResponseAlg,
+		client.IDTokenEncryptedResponseEnc,
+		client.UserinfoEncryptedResponseAlg,
+		client.UserinfoEncryptedResponseEnc,
+		client.UserinfoSignedResponseAlg,
+		client.RefreshTokenIdleLifetime,
+		client.RefreshTokenAbsoluteLifetime,
+		client.RefreshTokenExpiration,
+		client.AccessTokenLifetime,
+		client.IDTokenLifetime,
+		client.AuthorizationCodeLifetime,
+		client.DeviceCodeLifetime,
 	).Scan(&client.ID, &client.CreatedAt, &client.UpdatedAt)
 
 	if err != nil {
@@ -132,7 +212,7 @@ func (r *oauth2ClientRepositoryImpl) FindByClientIDTx(ctx context.Context, tx *s
 // findByClientID is the shared implementation for both transactional and non-transactional variants.
 func findByClientID(ctx context.Context, queryRow func(context.Context, string, ...any) *sql.Row, clientID string) (*domain.OAuth2Client, error) {
 	const query = `
-		SELECT id, account_id, client_id, client_secret_hash, name, description, redirect_uris, post_logout_redirect_uris, grant_types, scopes, is_confidential, metadata, frontchannel_logout_uri, frontchannel_logout_session_required, backchannel_logout_uri, backchannel_logout_session_required, created_at, updated_at, deleted_at
+		SELECT id, account_id, client_id, client_secret_hash, name, description, redirect_uris, post_logout_redirect_uris, grant_types, scopes, is_confidential, metadata, frontchannel_logout_uri, frontchannel_logout_session_required, backchannel_logout_uri, backchannel_logout_session_required, require_pushed_authorization_requests, token_endpoint_auth_method, jwks, jwks_uri, client_secret_encrypted, token_exchange_audiences, dpop_bound_access_tokens, tls_client_auth_subject_dn, tls_client_auth_san_dns, tls_client_auth_san_uri, tls_client_auth_san_ip, tls_client_auth_san_email, tls_client_certificate_bound_access_tokens, registration_access_token_hash, require_signed_request_object, request_uris, authorization_details_types, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, subject_type, sector_identifier_uri, id_token_signed_response_alg, access_token_profile, id_token_encrypted_response_alg, id_token_encrypted_response_enc, userinfo_encrypted_response_alg, userinfo_encrypted_response_enc, userinfo_signed_response_alg, refresh_token_idle_lifetime, refresh_token_absolute_lifetime, refresh_token_expiration, access_token_lifetime, id_token_lifetime, authorization_code_lifetime, device_code_lifetime, created_at, updated_at, deleted_at
 		FROM oauth2_clients
 		WHERE client_id = $1 AND deleted_at IS NULL`
 
@@ -149,7 +229,7 @@ func findByClientID(ctx context.Context, queryRow func(context.Context, string,
 
 func (r *oauth2ClientRepositoryImpl) FindByAccountID(ctx context.Context, accountID string) ([]*domain.OAuth2Client, error) {
 	query := `
-		SELECT id, account_id, client_id, client_secret_hash, name, description, redirect_uris, post_logout_redirect_uris, grant_types, scopes, is_confidential, metadata, frontchannel_logout_uri, frontchannel_logout_session_required, backchannel_logout_uri, backchannel_logout_session_required, created_at, updated_at, deleted_at
+		SELECT id, account_id, client_id, client_secret_hash, name, description, redirect_uris, post_logout_redirect_uris, grant_types, scopes, is_confidential, metadata, frontchannel_logout_uri, frontchannel_logout_session_required, backchannel_logout_uri, backchannel_logout_session_required, require_pushed_authorization_requests, token_endpoint_auth_method, jwks, jwks_uri, client_secret_encrypted, token_exchange_audiences, dpop_bound_access_tokens, tls_client_auth_subject_dn, tls_client_auth_san_dns, tls_client_auth_san_uri, tls_client_auth_san_ip, tls_client_auth_san_email, tls_client_certificate_bound_access_tokens, registration_access_token_hash, require_signed_request_object, request_uris, authorization_details_types, backchannel_token_delivery_mode, backchannel_client_notification_endpoint, subject_type, sector_identifier_uri, id_token_signed_response_alg, access_token_profile, id_token_encrypted_response_alg, id_token_encrypted_response_enc, userinfo_encrypted_response_alg, userinfo_encrypted_response_enc, userinfo_signed_response_alg, refresh_token_idle_lifetime, refresh_token_absolute_lifetime, refresh_token_expiration, access_token_lifetime, id_token_lifetime, authorization_code_lifetime, device_code_lifetime, created_at, updated_at, deleted_at
 		FROM oauth2_clients
 		WHERE account_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC`
@@ -171,14 +251,39 @@ func (r *oauth2ClientRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, cli
 
 	query := `
 		UPDATE oauth2_clients
-		SET name = $1, description = $2, redirect_uris = $3, post_logout_redirect_uris = $4, grant_types = $5, scopes = $6, metadata = $7, frontchannel_logout_uri = $8, frontchannel_logout_session_required = $9, backchannel_logout_uri = $10, backchannel_logout_session_required = $11, updated_at = $12
-		WHERE id = $13 AND deleted_at IS NULL AND updated_at = $14
+		SET name = $1, description = $2, redirect_uris = $3, post_logout_redirect_uris = $4, grant_types = $5, scopes = $6, metadata = $7, frontchannel_logout_uri = $8, frontchannel_logout_session_required = $9, backchannel_logout_uri = $10, backchannel_logout_session_required = $11, require_pushed_authorization_requests = $12, token_endpoint_auth_method = $13, jwks = $14, jwks_uri = $15, client_secret_encrypted = $16, token_exchange_audiences = $17, dpop_bound_access_tokens = $18, tls_client_auth_subject_dn = $19, tls_client_auth_san_dns = $20, tls_client_auth_san_uri = $21, tls_client_auth_san_ip = $22, tls_client_auth_san_email = $23, tls_client_certificate_bound_access_tokens = $24, require_signed_request_object = $25, request_uris = $26, authorization_details_types = $27, backchannel_token_delivery_mode = $28, backchannel_client_notification_endpoint = $29, subject_type = $30, sector_identifier_uri = $31, id_token_signed_response_alg = $32, access_token_profile = $33, id_token_encrypted_response_alg = $34, id_token_encrypted_response_enc = $35, userinfo_encrypted_response_alg = $36, userinfo_encrypted_response_enc = $37, userinfo_signed_response_alg = $38, refresh_token_idle_lifetime = $39, refresh_token_absolute_lifetime = $40, refresh_token_expiration = $41, access_token_lifetime = $42, id_token_lifetime = $43, authorization_code_lifetime = $44, device_code_lifetime = $45, updated_at = $46
+		WHERE id = $47 AND deleted_at IS NULL AND updated_at = $48
 		RETURNING updated_at`
 
 	err = tx.QueryRowContext(ctx, query,
 		client.Name, client.Description, f.redirectURIs, f.postLogoutURIs, f.grantTypes, f.scopes, f.metadata,
 		client.FrontchannelLogoutURI, client.FrontchannelLogoutSessionRequired,
 		client.BackchannelLogoutURI, client.BackchannelLogoutSessionRequired,
+		client.RequirePushedAuthorizationRequests,
+		client.TokenEndpointAuthMethod, string(client.JWKS), client.JWKSURI, client.ClientSecretEncrypted,
+		f.tokenExchangeAudiences,
+		client.DPoPBoundAccessTokens,
+		client.TLSClientAuthSubjectDN, client.TLSClientAuthSANDNS, client.TLSClientAuthSANURI,
+		client.TLSClientAuthSANIP, client.TLSClientAuthSANEmail,
+		client.TLSClientCertificateBoundAccessTokens,
+		client.RequireSignedRequestObject,
+		f.requestURIs,
+		f.authzDetailsTypes,
+		client.BackchannelTokenDeliveryMode, client.BackchannelClientNotificationEndpoint,
+		client.SubjectType, client.SectorIdentifierURI, client.IDTokenSignedResponseAlg,
+		client.AccessTokenProfile,
+		client.IDTokenEncryptedResponseAlg,
+		client.IDTokenEncryptedResponseEnc,
+		client.UserinfoEncryptedResponseAlg,
+		client.UserinfoEncryptedResponseEnc,
+		client.UserinfoSignedResponseAlg,
+		client.RefreshTokenIdleLifetime,
+		client.RefreshTokenAbsoluteLifetime,
+		client.RefreshTokenExpiration,
+		client.AccessTokenLifetime,
+		client.IDTokenLifetime,
+		client.AuthorizationCodeLifetime,
+		client.DeviceCodeLifetime,
 		time.Now(), client.ID, expectedUpdatedAt,
 	).Scan(&client.UpdatedAt)
 
@@ -198,7 +303,7 @@ func (r *oauth2ClientRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, cli
 }
 
 func (r *oauth2ClientRepositoryImpl) SoftDelete(ctx context.Context, tx *sql.Tx, id string, deletedAt time.Time) error {
-	query := `UPDATE oauth2_clients SET deleted_at = $1, updated_at = $1, client_secret_hash = '' WHERE id = $2 AND deleted_at IS NULL`
+	query := `UPDATE oauth2_clients SET deleted_at = $1, updated_at = $1, client_secret_hash = '', client_secret_encrypted = '' WHERE id = $2 AND deleted_at IS NULL`
 	result, err := tx.ExecContext(ctx, query, deletedAt, id)
 	if err != nil {
 		return fmt.Errorf("soft delete oauth2_client: %w", err)
@@ -216,7 +321,7 @@ func (r *oauth2ClientRepositoryImpl) SoftDelete(ctx context.Context, tx *sql.Tx,
 // SoftDeleteByAccountID soft deletes all OAuth2 clients of an account.
 // Returns nil even if zero rows are affected (idempotent for bulk delete).
 func (r *oauth2ClientRepositoryImpl) SoftDeleteByAccountID(ctx context.Context, tx *sql.Tx, accountID string, deletedAt time.Time) error {
-	query := `UPDATE oauth2_clients SET deleted_at = $1, updated_at = $1, client_secret_hash = '' WHERE account_id = $2 AND deleted_at IS NULL`
+	query := `UPDATE oauth2_clients SET deleted_at = $1, updated_at = $1, client_secret_hash = '', client_secret_encrypted = '' WHERE account_id = $2 AND deleted_at IS NULL`
 	_, err := tx.ExecContext(ctx, query, deletedAt, accountID)
 	if err != nil {
 		return fmt.Errorf("soft delete oauth2_clients by account_id: %w", err)
@@ -233,6 +338,18 @@ func (r *oauth2ClientRepositoryImpl) FindFrontchannelLogoutClientsByAccountID(ct
 		       c.is_confidential, c.metadata,
 		       c.frontchannel_logout_uri, c.frontchannel_logout_session_required,
 		       c.backchannel_logout_uri, c.backchannel_logout_session_required,
+		       c.require_pushed_authorization_requests,
+		       c.token_endpoint_auth_method, c.jwks, c.jwks_uri, c.client_secret_encrypted,
+		       c.token_exchange_audiences, c.dpop_bound_access_tokens,
+		       c.tls_client_auth_subject_dn, c.tls_client_auth_san_dns, c.tls_client_auth_san_uri,
+		       c.tls_client_auth_san_ip, c.tls_client_auth_san_email,
+		       c.tls_client_certificate_bound_access_tokens, c.registration_access_token_hash,
+		       c.require_signed_request_object, c.request_uris, c.authorization_details_types,
+		       c.backchannel_token_delivery_mode, c.backchannel_client_notification_endpoint,
+		       c.subject_type, c.sector_identifier_uri, c.id_token_signed_response_alg, c.access_token_profile,
+		       c.id_token_encrypted_response_alg, c.id_token_encrypted_response_enc, c.userinfo_encrypted_response_alg, c.userinfo_encrypted_response_enc, c.userinfo_signed_response_alg,
+		       c.refresh_token_idle_lifetime, c.refresh_token_absolute_lifetime, c.refresh_token_expiration,
+		       c.access_token_lifetime, c.id_token_lifetime, c.authorization_code_lifetime, c.device_code_lifetime,
 		       c.created_at, c.updated_at, c.deleted_at
 		FROM oauth2_clients c
 		INNER JOIN oauth2_consents oc ON oc.client_id = c.id AND oc.account_id = $1 AND oc.deleted_at IS NULL
@@ -256,6 +373,18 @@ func (r *oauth2ClientRepositoryImpl) FindBackchannelLogoutClientsByAccountID(ctx
 		       c.is_confidential, c.metadata,
 		       c.frontchannel_logout_uri, c.frontchannel_logout_session_required,
 		       c.backchannel_logout_uri, c.backchannel_logout_session_required,
+		       c.require_pushed_authorization_requests,
+		       c.token_endpoint_auth_method, c.jwks, c.jwks_uri, c.client_secret_encrypted,
+		       c.token_exchange_audiences, c.dpop_bound_access_tokens,
+		       c.tls_client_auth_subject_dn, c.tls_client_auth_san_dns, c.tls_client_auth_san_uri,
+		       c.tls_client_auth_san_ip, c.tls_client_auth_san_email,
+		       c.tls_client_certificate_bound_access_tokens, c.registration_access_token_hash,
+		       c.require_signed_request_object, c.request_uris, c.authorization_details_types,
+		       c.backchannel_token_delivery_mode, c.backchannel_client_notification_endpoint,
+		       c.subject_type, c.sector_identifier_uri, c.id_token_signed_response_alg, c.access_token_profile,
+		       c.id_token_encrypted_response_alg, c.id_token_encrypted_response_enc, c.userinfo_encrypted_response_alg, c.userinfo_encrypted_response_enc, c.userinfo_signed_response_alg,
+		       c.refresh_token_idle_lifetime, c.refresh_token_absolute_lifetime, c.refresh_token_expiration,
+		       c.access_token_lifetime, c.id_token_lifetime, c.authorization_code_lifetime, c.device_code_lifetime,
 		       c.created_at, c.updated_at, c.deleted_at
 		FROM oauth2_clients c
 		INNER JOIN oauth2_consents oc ON oc.client_id = c.id AND oc.account_id = $1 AND oc.deleted_at IS NULL
@@ -36,6 +36,19 @@ func clientColumns() []string {
 		"redirect_uris", "post_logout_redirect_uris", "grant_types", "scopes", "is_confidential", "metadata",
 		"frontchannel_logout_uri", "frontchannel_logout_session_required",
 		"backchannel_logout_uri", "backchannel_logout_session_required",
+		"require_pushed_authorization_requests",
+		"token_endpoint_auth_method", "jwks", "jwks_uri", "client_secret_encrypted",
+		"token_exchange_audiences", "dpop_bound_access_tokens",
+		"tls_client_auth_subject_dn", "tls_client_auth_san_dns", "tls_client_auth_san_uri",
+		"tls_client_auth_san_ip", "tls_client_auth_san_email",
+		"tls_client_certificate_bound_access_tokens", "registration_access_token_hash",
+		"require_signed_request_object", "request_uris", "authorization_details_types",
+		"backchannel_token_delivery_mode", "backchannel_client_notification_endpoint",
+		"subject_type", "sector_identifier_uri", "id_token_signed_response_alg", "access_token_profile",
+		"id_token_encrypted_response_alg", "id_token_encrypted_response_enc",
+		"userinfo_encrypted_response_alg", "userinfo_encrypted_response_enc", "userinfo_signed_response_alg",
+		"refresh_token_idle_lifetime", "refresh_token_absolute_lifetime", "refresh_token_expiration",
+		"access_token_lifetime", "id_token_lifetime", "authorization_code_lifetime", "device_code_lifetime",
 		"created_at", "updated_at", "deleted_at"}
 }
 
@@ -48,10 +61,26 @@ func clientRowValues(c *domain.OAuth2Client) []driver.Value {
 	if c.Metadata != nil {
 		md, _ = json.Marshal(c.Metadata)
 	}
+	tea, _ := json.Marshal(c.TokenExchangeAudiences)
+	rqu, _ := json.Marshal(c.RequestURIs)
+	adt, _ := json.Marshal(c.AuthorizationDetailsTypes)
 	return []driver.Value{c.ID, c.AccountID, c.ClientID, c.ClientSecretHash, c.Name, c.Description,
 		ru, plu, gt, sc, c.IsConfidential, md,
 		c.FrontchannelLogoutURI, c.FrontchannelLogoutSessionRequired,
 		c.BackchannelLogoutURI, c.BackchannelLogoutSessionRequired,
+		c.RequirePushedAuthorizationRequests,
+		c.TokenEndpointAuthMethod, string(c.JWKS), c.JWKSURI, c.ClientSecretEncrypted,
+		tea, c.DPoPBoundAccessTokens,
+		c.TLSClientAuthSubjectDN, c.TLSClientAuthSANDNS, c.TLSClientAuthSANURI,
+		c.TLSClientAuthSANIP, c.TLSClientAuthSANEmail,
+		c.TLSClientCertificateBoundAccessTokens, c.RegistrationAccessTokenHash,
+		c.RequireSignedRequestObject, rqu, adt,
+		c.BackchannelTokenDeliveryMode, c.BackchannelClientNotificationEndpoint,
+		c.SubjectType, c.SectorIdentifierURI, c.IDTokenSignedResponseAlg, c.AccessTokenProfile,
+		c.IDTokenEncryptedResponseAlg, c.IDTokenEncryptedResponseEnc,
+		c.UserinfoEncryptedResponseAlg, c.UserinfoEncryptedResponseEnc, c.UserinfoSignedResponseAlg,
+		c.RefreshTokenIdleLifetime, c.RefreshTokenAbsoluteLifetime, c.RefreshTokenExpiration,
+		c.AccessTokenLifetime, c.IDTokenLifetime, c.AuthorizationCodeLifetime, c.DeviceCodeLifetime,
 		time.Now(), time.Now(), nil}
 }
 
@@ -149,7 +178,19 @@ func TestCreate_Success(t *testing.T) {
 		WithArgs(c.AccountID, c.ClientID, c.ClientSecretHash, c.Name, c.Description,
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), c.IsConfidential, sqlmock.AnyArg(),
 			c.FrontchannelLogoutURI, c.FrontchannelLogoutSessionRequired,
-			c.BackchannelLogoutURI, c.BackchannelLogoutSessionRequired).
+			c.BackchannelLogoutURI, c.BackchannelLogoutSessionRequired,
+			c.RequirePushedAuthorizationRequests,
+			c.TokenEndpointAuthMethod, string(c.JWKS), c.JWKSURI, c.ClientSecretEncrypted,
+			sqlmock.AnyArg(), c.DPoPBoundAccessTokens,
+			c.TLSClientAuthSubjectDN, c.TLSClientAuthSANDNS, c.TLSClientAuthSANURI,
+			c.TLSClientAuthSANIP, c.TLSClientAuthSANEmail, c.TLSClientCertificateBoundAccessTokens,
+			c.RegistrationAccessTokenHash, c.RequireSignedRequestObject, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			c.BackchannelTokenDeliveryMode, c.BackchannelClientNotificationEndpoint,
+			c.SubjectType, c.SectorIdentifierURI, c.IDTokenSignedResponseAlg, c.AccessTokenProfile,
+			c.IDTokenEncryptedResponseAlg, c.IDTokenEncryptedResponseEnc,
+			c.UserinfoEncryptedResponseAlg, c.UserinfoEncryptedResponseEnc, c.UserinfoSignedResponseAlg,
+			c.RefreshTokenIdleLifetime, c.RefreshTokenAbsoluteLifetime, c.RefreshTokenExpiration,
+			c.AccessTokenLifetime, c.IDTokenLifetime, c.AuthorizationCodeLifetime, c.DeviceCodeLifetime).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(c.ID, time.Now(), time.Now()))
 
 	repo := NewOAuth2ClientRepository(db)
@@ -178,6 +219,18 @@ func TestUpdate_Success(t *testing.T) {
 		WithArgs(c.Name, c.Description, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			c.FrontchannelLogoutURI, c.FrontchannelLogoutSessionRequired,
 			c.BackchannelLogoutURI, c.BackchannelLogoutSessionRequired,
+			c.RequirePushedAuthorizationRequests,
+			c.TokenEndpointAuthMethod, string(c.JWKS), c.JWKSURI, c.ClientSecretEncrypted,
+			sqlmock.AnyArg(), c.DPoPBoundAccessTokens,
+			c.TLSClientAuthSubjectDN, c.TLSClientAuthSANDNS, c.TLSClientAuthSANURI,
+			c.TLSClientAuthSANIP, c.TLSClientAuthSANEmail, c.TLSClientCertificateBoundAccessTokens,
+			c.RequireSignedRequestObject, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			c.BackchannelTokenDeliveryMode, c.BackchannelClientNotificationEndpoint,
+			c.SubjectType, c.SectorIdentifierURI, c.IDTokenSignedResponseAlg, c.AccessTokenProfile,
+			c.IDTokenEncryptedResponseAlg, c.IDTokenEncryptedResponseEnc,
+			c.UserinfoEncryptedResponseAlg, c.UserinfoEncryptedResponseEnc, c.UserinfoSignedResponseAlg,
+			c.RefreshTokenIdleLifetime, c.RefreshTokenAbsoluteLifetime, c.RefreshTokenExpiration,
+			c.AccessTokenLifetime, c.IDTokenLifetime, c.AuthorizationCodeLifetime, c.DeviceCodeLifetime,
 			sqlmock.AnyArg(), c.ID, expectedUpdatedAt).
 		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
 
@@ -29,16 +29,17 @@ func (r *consentRepositoryImpl) Upsert(ctx context.Context, tx *sql.Tx, consent
 	}
 
 	query := `
-		INSERT INTO oauth2_consents (account_id, client_id, scopes, granted_at)
-		VALUES ($1, (SELECT id FROM oauth2_clients WHERE client_id = $2 AND deleted_at IS NULL), $3, $4)
+		INSERT INTO oauth2_consents (account_id, client_id, scopes, authorization_details, granted_at)
+		VALUES ($1, (SELECT id FROM oauth2_clients WHERE client_id = $2 AND deleted_at IS NULL), $3, $4, $5)
 		ON CONFLICT (account_id, client_id) WHERE deleted_at IS NULL
-		DO UPDATE SET scopes = EXCLUDED.scopes, granted_at = EXCLUDED.granted_at, deleted_at = NULL
+		DO UPDATE SET scopes = EXCLUDED.scopes, authorization_details = EXCLUDED.authorization_details, granted_at = EXCLUDED.granted_at, deleted_at = NULL
 		RETURNING id, created_at, updated_at`
 
 	if err := tx.QueryRowContext(ctx, query,
 		consent.AccountID,
 		consent.ClientID,
 		scopesJSON,
+		[]byte(consent.AuthorizationDetails.JSON()),
 		consent.GrantedAt,
 	).Scan(&consent.ID, &consent.CreatedAt, &consent.UpdatedAt); err != nil {
 		return fmt.Errorf("upsert consent: %w", err)
@@ -60,7 +61,7 @@ func (r *consentRepositoryImpl) FindByAccountAndClientTx(ctx context.Context, tx
 // findConsentByAccountAndClient is the shared implementation for FindByAccountAndClient and FindByAccountAndClientTx.
 func findConsentByAccountAndClient(ctx context.Context, q db.Queryable, accountID, clientID string) (*domain.Consent, error) {
 	query := `
-		SELECT c.id, c.account_id, cl.client_id, c.scopes, c.granted_at, c.created_at, c.updated_at, c.deleted_at
+		SELECT c.id, c.account_id, cl.client_id, c.scopes, c.authorization_details, c.granted_at, c.created_at, c.updated_at, c.deleted_at
 		FROM oauth2_consents c
 		INNER JOIN oauth2_clients cl ON cl.id = c.client_id
 		WHERE c.account_id = $1 AND cl.client_id = $2 AND c.deleted_at IS NULL`
@@ -101,7 +102,7 @@ func (r *consentRepositoryImpl) SoftDelete(ctx context.Context, tx *sql.Tx, acco
 // FindByAccountID returns all non-deleted consent records for the given account.
 func (r *consentRepositoryImpl) FindByAccountID(ctx context.Context, accountID string) ([]*domain.Consent, error) {
 	query := `
-		SELECT c.id, c.account_id, cl.client_id, c.scopes, c.granted_at, c.created_at, c.updated_at, c.deleted_at
+		SELECT c.id, c.account_id, cl.client_id, c.scopes, c.authorization_details, c.granted_at, c.created_at, c.updated_at, c.deleted_at
 		FROM oauth2_consents c
 		INNER JOIN oauth2_clients cl ON cl.id = c.client_id
 		WHERE c.account_id = $1 AND c.deleted_at IS NULL
@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+// PairwiseSubjectRepository maps pairwise subject identifiers back to the accounts they were issued for.
+type PairwiseSubjectRepository interface {
+	Save(ctx context.Context, sectorIdentifier, subject, accountID string) error
+	FindAccountID(ctx context.Context, sectorIdentifier, subject string) (string, error)
+}
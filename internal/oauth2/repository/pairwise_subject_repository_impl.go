@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+type pairwiseSubjectRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewPairwiseSubjectRepository creates a new pairwise subject repository instance.
+func NewPairwiseSubjectRepository(db *sql.DB) PairwiseSubjectRepository {
+	return &pairwiseSubjectRepositoryImpl{db: db}
+}
+
+// Save records the account a pairwise subject was derived from. Subjects are
+// deterministic, so saving an existing mapping is a no-op.
+func (r *pairwiseSubjectRepositoryImpl) Save(ctx context.Context, sectorIdentifier, subject, accountID string) error {
+	query := `
+		INSERT INTO pairwise_subjects (sector_identifier, subject, account_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (sector_identifier, subject) DO NOTHING`
+
+	if _, err := r.db.ExecContext(ctx, query, sectorIdentifier, subject, accountID); err != nil {
+		return fmt.Errorf("save pairwise subject: %w", err)
+	}
+	return nil
+}
+
+// FindAccountID returns the account ID a pairwise subject was issued for within a sector.
+func (r *pairwiseSubjectRepositoryImpl) FindAccountID(ctx context.Context, sectorIdentifier, subject string) (string, error) {
+	query := `SELECT account_id FROM pairwise_subjects WHERE sector_identifier = $1 AND subject = $2`
+
+	var accountID string
+	err := r.db.QueryRowContext(ctx, query, sectorIdentifier, subject).Scan(&accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%w: sector=%s", domain.ErrPairwiseSubjectNotFound, sectorIdentifier)
+	}
+	if err != nil {
+		return "", fmt.Errorf("find pairwise subject: %w", err)
+	}
+	return accountID, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+func TestPairwiseSubjectRepo_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO pairwise_subjects").
+		WithArgs("app.example.com", "pairwise-sub", "account-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewPairwiseSubjectRepository(db)
+	err = repo.Save(context.Background(), "app.example.com", "pairwise-sub", "account-001")
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPairwiseSubjectRepo_Save_DBError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO pairwise_subjects").WillReturnError(fmt.Errorf("connection lost"))
+
+	repo := NewPairwiseSubjectRepository(db)
+	err = repo.Save(context.Background(), "app.example.com", "pairwise-sub", "account-001")
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPairwiseSubjectRepo_FindAccountID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT account_id FROM pairwise_subjects").
+		WithArgs("app.example.com", "pairwise-sub").
+		WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow("account-001"))
+
+	repo := NewPairwiseSubjectRepository(db)
+	accountID, err := repo.FindAccountID(context.Background(), "app.example.com", "pairwise-sub")
+
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", accountID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPairwiseSubjectRepo_FindAccountID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT account_id FROM pairwise_subjects").WillReturnError(sql.ErrNoRows)
+
+	repo := NewPairwiseSubjectRepository(db)
+	_, err = repo.FindAccountID(context.Background(), "app.example.com", "unknown")
+
+	assert.ErrorIs(t, err, domain.ErrPairwiseSubjectNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	dbPkg "github.com/rushairer/gosso/internal/db"
@@ -9,11 +10,12 @@ import (
 	"github.com/rushairer/gosso/internal/oauth2/domain"
 )
 
-// scanOAuth2Client scans a single oauth2_clients row (19 columns) into an OAuth2Client.
+// scanOAuth2Client scans a single oauth2_clients row (54 columns) into an OAuth2Client.
 func scanOAuth2Client(s dbPkg.Scannable) (*domain.OAuth2Client, error) {
 	client := &domain.OAuth2Client{}
-	var redirectURIs, postLogoutURIs, grantTypes, scopes, metadata []byte
+	var redirectURIs, postLogoutURIs, grantTypes, scopes, metadata, tokenExchangeAudiences, requestURIs, authzDetailsTypes []byte
 	var clientSecretHash, description sql.NullString
+	var jwks string
 
 	if err := s.Scan(
 		&client.ID, &client.AccountID, &client.ClientID, &clientSecretHash,
@@ -21,6 +23,19 @@ func scanOAuth2Client(s dbPkg.Scannable) (*domain.OAuth2Client, error) {
 		&client.IsConfidential, &metadata,
 		&client.FrontchannelLogoutURI, &client.FrontchannelLogoutSessionRequired,
 		&client.BackchannelLogoutURI, &client.BackchannelLogoutSessionRequired,
+		&client.RequirePushedAuthorizationRequests,
+		&client.TokenEndpointAuthMethod, &jwks, &client.JWKSURI, &client.ClientSecretEncrypted,
+		&tokenExchangeAudiences, &client.DPoPBoundAccessTokens,
+		&client.TLSClientAuthSubjectDN, &client.TLSClientAuthSANDNS, &client.TLSClientAuthSANURI,
+		&client.TLSClientAuthSANIP, &client.TLSClientAuthSANEmail,
+		&client.TLSClientCertificateBoundAccessTokens, &client.RegistrationAccessTokenHash,
+		&client.RequireSignedRequestObject, &requestURIs, &authzDetailsTypes,
+		&client.BackchannelTokenDeliveryMode, &client.BackchannelClientNotificationEndpoint,
+		&client.SubjectType, &client.SectorIdentifierURI, &client.IDTokenSignedResponseAlg, &client.AccessTokenProfile,
+		&client.IDTokenEncryptedResponseAlg, &client.IDTokenEncryptedResponseEnc,
+		&client.UserinfoEncryptedResponseAlg, &client.UserinfoEncryptedResponseEnc, &client.UserinfoSignedResponseAlg,
+		&client.RefreshTokenIdleLifetime, &client.RefreshTokenAbsoluteLifetime, &client.RefreshTokenExpiration,
+		&client.AccessTokenLifetime, &client.IDTokenLifetime, &client.AuthorizationCodeLifetime, &client.DeviceCodeLifetime,
 		&client.CreatedAt, &client.UpdatedAt, &client.DeletedAt,
 	); err != nil {
 		return nil, err
@@ -28,10 +43,15 @@ func scanOAuth2Client(s dbPkg.Scannable) (*domain.OAuth2Client, error) {
 
 	client.ClientSecretHash = clientSecretHash.String
 	client.Description = description.String
+	if jwks != "" {
+		client.JWKS = json.RawMessage(jwks)
+	}
 
 	if err := unmarshalClientJSONFields(client, &clientJSONFields{
 		redirectURIs: redirectURIs, postLogoutURIs: postLogoutURIs,
 		grantTypes: grantTypes, scopes: scopes, metadata: metadata,
+		tokenExchangeAudiences: tokenExchangeAudiences, requestURIs: requestURIs,
+		authzDetailsTypes: authzDetailsTypes,
 	}); err != nil {
 		return nil, err
 	}
@@ -55,16 +75,17 @@ func scanOAuth2Clients(rows *sql.Rows) ([]*domain.OAuth2Client, error) {
 	return clients, nil
 }
 
-// scanConsent scans a single oauth2_consents row (8 columns) into a Consent.
+// scanConsent scans a single oauth2_consents row (9 columns) into a Consent.
 func scanConsent(s dbPkg.Scannable) (*domain.Consent, error) {
 	var consent domain.Consent
-	var scopesJSON []byte
+	var scopesJSON, detailsJSON []byte
 
 	if err := s.Scan(
 		&consent.ID,
 		&consent.AccountID,
 		&consent.ClientID,
 		&scopesJSON,
+		&detailsJSON,
 		&consent.GrantedAt,
 		&consent.CreatedAt,
 		&consent.UpdatedAt,
@@ -77,6 +98,9 @@ func scanConsent(s dbPkg.Scannable) (*domain.Consent, error) {
 	if err := dbPkg.UnmarshalJSONField(scopesJSON, &consent.Scopes, "scopes"); err != nil {
 		return nil, err
 	}
+	if err := dbPkg.UnmarshalJSONField(detailsJSON, &consent.AuthorizationDetails, "authorization_details"); err != nil {
+		return nil, err
+	}
 
 	return &consent, nil
 }
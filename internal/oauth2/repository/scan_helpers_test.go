@@ -40,7 +40,14 @@ func TestScanOAuth2Client_Success(t *testing.T) {
 		"client-uuid-001", "account-001", "cid-abc123", "$2a$10$hash",
 		"Test App", "A test app",
 		ruJSON, pluJSON, gtJSON, scJSON,
-		true, mdJSON, "", false, "", false,
+		true, mdJSON, "", false, "", false, false, "", "", "", "",
+		[]byte(`["orders-api"]`), true,
+		"CN=client.example.com", "", "", "", "", true, "registration-hash", true,
+		[]byte(`["https://app.example.com/request.jwt"]`),
+		[]byte(`["payment_initiation"]`),
+		"ping", "https://app.example.com/ciba",
+		"pairwise", "https://app.example.com/sector.json", "ES256", "rfc9068", "RSA-OAEP-256", "A256GCM", "RSA-OAEP", "A128CBC-HS256", "RS256",
+		86400, 2592000, "fixed", 120, 300, 60, 900,
 		now, now, nil,
 	)
 	mock.ExpectQuery("SELECT .+ FROM oauth2_clients").WillReturnRows(rows)
@@ -67,6 +74,32 @@ func TestScanOAuth2Client_Success(t *testing.T) {
 	assert.Equal(t, grantTypes, client.GrantTypes)
 	assert.Equal(t, scopes, client.Scopes)
 	assert.Equal(t, "value", client.Metadata["key"])
+	assert.Equal(t, []string{"orders-api"}, client.TokenExchangeAudiences)
+	assert.True(t, client.DPoPBoundAccessTokens)
+	assert.Equal(t, "CN=client.example.com", client.TLSClientAuthSubjectDN)
+	assert.True(t, client.TLSClientCertificateBoundAccessTokens)
+	assert.Equal(t, "registration-hash", client.RegistrationAccessTokenHash)
+	assert.True(t, client.RequireSignedRequestObject)
+	assert.Equal(t, []string{"https://app.example.com/request.jwt"}, client.RequestURIs)
+	assert.Equal(t, []string{"payment_initiation"}, client.AuthorizationDetailsTypes)
+	assert.Equal(t, "ping", client.BackchannelTokenDeliveryMode)
+	assert.Equal(t, "https://app.example.com/ciba", client.BackchannelClientNotificationEndpoint)
+	assert.Equal(t, "pairwise", client.SubjectType)
+	assert.Equal(t, "https://app.example.com/sector.json", client.SectorIdentifierURI)
+	assert.Equal(t, "ES256", client.IDTokenSignedResponseAlg)
+	assert.Equal(t, "rfc9068", client.AccessTokenProfile)
+	assert.Equal(t, "RSA-OAEP-256", client.IDTokenEncryptedResponseAlg)
+	assert.Equal(t, "A256GCM", client.IDTokenEncryptedResponseEnc)
+	assert.Equal(t, "RSA-OAEP", client.UserinfoEncryptedResponseAlg)
+	assert.Equal(t, "A128CBC-HS256", client.UserinfoEncryptedResponseEnc)
+	assert.Equal(t, "RS256", client.UserinfoSignedResponseAlg)
+	assert.Equal(t, 86400, client.RefreshTokenIdleLifetime)
+	assert.Equal(t, 2592000, client.RefreshTokenAbsoluteLifetime)
+	assert.Equal(t, "fixed", client.RefreshTokenExpiration)
+	assert.Equal(t, 120, client.AccessTokenLifetime)
+	assert.Equal(t, 300, client.IDTokenLifetime)
+	assert.Equal(t, 60, client.AuthorizationCodeLifetime)
+	assert.Equal(t, 900, client.DeviceCodeLifetime)
 
 	assert.False(t, client.CreatedAt.IsZero())
 	assert.False(t, client.UpdatedAt.IsZero())
@@ -105,10 +138,11 @@ func TestScanConsent_Success(t *testing.T) {
 	scopes := []string{"openid", "email"}
 	scJSON, _ := json.Marshal(scopes)
 
-	columns := []string{"id", "account_id", "client_id", "scopes", "granted_at", "created_at", "updated_at", "deleted_at"}
+	columns := []string{"id", "account_id", "client_id", "scopes", "authorization_details", "granted_at", "created_at", "updated_at", "deleted_at"}
 	rows := sqlmock.NewRows(columns).AddRow(
 		"consent-001", "account-001", "client-001",
-		scJSON, now, now, now, nil,
+		scJSON, []byte(`[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"123.50"}}]`),
+		now, now, now, nil,
 	)
 	mock.ExpectQuery("SELECT .+ FROM oauth2_consents").WillReturnRows(rows)
 
@@ -124,6 +158,7 @@ func TestScanConsent_Success(t *testing.T) {
 	assert.Equal(t, "account-001", consent.AccountID)
 	assert.Equal(t, "client-001", consent.ClientID)
 	assert.Equal(t, scopes, consent.Scopes)
+	assert.Equal(t, []string{"payment_initiation"}, consent.AuthorizationDetails.Types())
 	assert.False(t, consent.GrantedAt.IsZero())
 	assert.False(t, consent.CreatedAt.IsZero())
 	assert.False(t, consent.UpdatedAt.IsZero())
@@ -147,12 +182,14 @@ func TestScanOAuth2Clients_MultipleRows(t *testing.T) {
 		AddRow(c1.ID, c1.AccountID, c1.ClientID, "", c1.Name, "",
 			mustMarshal(t, c1.RedirectURIs), mustMarshal(t, c1.PostLogoutRedirectURIs),
 			mustMarshal(t, c1.GrantTypes), mustMarshal(t, c1.Scopes),
-			false, nil, "", false, "", false,
+			false, nil, "", false, "", false, false, "", "", "", "",
+			[]byte(`[]`), false, "", "", "", "", "", false, "", false, []byte(`[]`), []byte(`[]`), "", "", "", "", "", "", "", "", "", "", "", 0, 0, "", 0, 0, 0, 0,
 			now, now, nil).
 		AddRow(c2.ID, c2.AccountID, c2.ClientID, "", c2.Name, "",
 			mustMarshal(t, c2.RedirectURIs), mustMarshal(t, c2.PostLogoutRedirectURIs),
 			mustMarshal(t, c2.GrantTypes), mustMarshal(t, c2.Scopes),
-			false, nil, "", false, "", false,
+			false, nil, "", false, "", false, false, "", "", "", "",
+			[]byte(`[]`), false, "", "", "", "", "", false, "", false, []byte(`[]`), []byte(`[]`), "", "", "", "", "", "", "", "", "", "", "", 0, 0, "", 0, 0, 0, 0,
 			now, now, nil)
 	mock.ExpectQuery("SELECT .+ FROM oauth2_clients").WillReturnRows(rows)
 
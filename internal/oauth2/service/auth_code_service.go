@@ -47,12 +47,21 @@ func NewAuthCodeService(redis *cache.RedisClient, logger *zap.Logger, expiry tim
 	}, nil
 }
 
-// GenerateCode generates an authorization code and stores it in Redis
+// GenerateCode generates an authorization code and stores it in Redis.
+// authTime is when the end user authenticated (OIDC auth_time); zero means now. resources
+// are the protected resources (RFC 8707) and claims the claims parameter (OIDC Core §5.5)
+// requested at authorization. A positive lifetime, registered by the client, replaces the
+// configured expiry.
 func (s *AuthCodeService) GenerateCode(
 	ctx context.Context,
 	clientID, accountID, redirectURI string,
 	scopes []string,
+	authorizationDetails domain.AuthorizationDetails,
+	resources []string,
+	claims *domain.ClaimsRequest,
 	codeChallenge, codeChallengeMethod, nonce, sessionID string,
+	authTime time.Time,
+	lifetime time.Duration,
 ) (*domain.AuthorizationCode, error) {
 	bytes := make([]byte, authCodeLength)
 	if _, err := rand.Read(bytes); err != nil {
@@ -61,7 +70,13 @@ func (s *AuthCodeService) GenerateCode(
 	codeString := hex.EncodeToString(bytes)
 
 	now := time.Now()
-	ac, err := domain.NewAuthorizationCode(codeString, clientID, accountID, redirectURI, scopes, now.Add(s.expiry), now)
+	if authTime.IsZero() {
+		authTime = now
+	}
+	if lifetime <= 0 {
+		lifetime = s.expiry
+	}
+	ac, err := domain.NewAuthorizationCode(codeString, clientID, accountID, redirectURI, scopes, now.Add(lifetime), authTime)
 	if err != nil {
 		return nil, fmt.Errorf("create authorization code: %w", err)
 	}
@@ -69,6 +84,9 @@ func (s *AuthCodeService) GenerateCode(
 	ac.CodeChallenge = codeChallenge
 	ac.CodeChallengeMethod = codeChallengeMethod
 	ac.Nonce = nonce
+	ac.AuthorizationDetails = authorizationDetails
+	ac.Resources = resources
+	ac.Claims = claims
 
 	// Clear the plaintext code before storing in Redis — only the hash is used as the key.
 	// The raw code is returned to the caller but never persisted.
@@ -81,7 +99,7 @@ func (s *AuthCodeService) GenerateCode(
 	}
 
 	key := authCodeKeyPrefix + tokenDomain.HashToken(codeString)
-	if err := s.redis.Set(ctx, key, data, s.expiry); err != nil {
+	if err := s.redis.Set(ctx, key, data, lifetime); err != nil {
 		return nil, fmt.Errorf("store authorization code: %w", err)
 	}
 
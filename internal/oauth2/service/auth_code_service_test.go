@@ -34,7 +34,7 @@ func TestGenerateCode(t *testing.T) {
 	ctx := context.Background()
 
 	code, err := svc.GenerateCode(ctx, "client-001", "account-001", "http://localhost/callback",
-		[]string{"openid", "profile"}, "", "", "test-nonce", "session-001")
+		[]string{"openid", "profile"}, nil, nil, nil, "", "", "test-nonce", "session-001", time.Time{}, 0)
 	require.NoError(t, err)
 
 	assert.NotEmpty(t, code.Code)
@@ -47,6 +47,77 @@ func TestGenerateCode(t *testing.T) {
 	assert.True(t, code.ExpiresAt.After(time.Now()))
 }
 
+func TestGenerateCode_ClientLifetime(t *testing.T) {
+	svc, cleanup := setupTestAuthCodeService(t)
+	defer cleanup()
+
+	code, err := svc.GenerateCode(context.Background(), "client-001", "account-001", "http://localhost/callback",
+		[]string{"openid"}, nil, nil, nil, "", "", "", "session-001", time.Time{}, 30*time.Second)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), code.ExpiresAt, time.Second)
+}
+
+func TestGenerateCode_PreservesAuthTime(t *testing.T) {
+	svc, cleanup := setupTestAuthCodeService(t)
+	defer cleanup()
+
+	authTime := time.Now().Add(-30 * time.Minute).Truncate(time.Second)
+	code, err := svc.GenerateCode(context.Background(), "client-001", "account-001", "http://localhost/callback",
+		[]string{"openid"}, nil, nil, nil, "", "", "", "session-001", authTime, 0)
+	require.NoError(t, err)
+	assert.True(t, code.AuthTime.Equal(authTime))
+
+	validated, err := svc.ValidateCode(context.Background(), code.Code, "client-001", "http://localhost/callback", nil)
+	require.NoError(t, err)
+	assert.True(t, validated.AuthTime.Equal(authTime))
+}
+
+func TestGenerateCode_PreservesAuthorizationDetails(t *testing.T) {
+	svc, cleanup := setupTestAuthCodeService(t)
+	defer cleanup()
+
+	details, err := domain.ParseAuthorizationDetails([]byte(`[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"123.50"}}]`))
+	require.NoError(t, err)
+	code, err := svc.GenerateCode(context.Background(), "client-001", "account-001", "http://localhost/callback",
+		[]string{"openid"}, details, nil, nil, "", "", "", "session-001", time.Time{}, 0)
+	require.NoError(t, err)
+
+	validated, err := svc.ValidateCode(context.Background(), code.Code, "client-001", "http://localhost/callback", nil)
+	require.NoError(t, err)
+	assert.Equal(t, details, validated.AuthorizationDetails)
+}
+
+func TestGenerateCode_PreservesResources(t *testing.T) {
+	svc, cleanup := setupTestAuthCodeService(t)
+	defer cleanup()
+
+	resources := []string{"https://api.example.com/orders", "https://api.example.com/billing"}
+	code, err := svc.GenerateCode(context.Background(), "client-001", "account-001", "http://localhost/callback",
+		[]string{"openid", "orders:read"}, nil, resources, nil, "", "", "", "session-001", time.Time{}, 0)
+	require.NoError(t, err)
+
+	validated, err := svc.ValidateCode(context.Background(), code.Code, "client-001", "http://localhost/callback", nil)
+	require.NoError(t, err)
+	assert.Equal(t, resources, validated.Resources)
+}
+
+func TestGenerateCode_PreservesClaims(t *testing.T) {
+	svc, cleanup := setupTestAuthCodeService(t)
+	defer cleanup()
+
+	claims, err := domain.ParseClaimsRequest([]byte(`{"id_token":{"email":{"essential":true}},"userinfo":{"name":null}}`))
+	require.NoError(t, err)
+	code, err := svc.GenerateCode(context.Background(), "client-001", "account-001", "http://localhost/callback",
+		[]string{"openid"}, nil, nil, claims, "", "", "", "session-001", time.Time{}, 0)
+	require.NoError(t, err)
+
+	validated, err := svc.ValidateCode(context.Background(), code.Code, "client-001", "http://localhost/callback", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email"}, validated.Claims.IDTokenClaims())
+	assert.True(t, validated.Claims.Essential("email"))
+	assert.Equal(t, []string{"name"}, validated.Claims.UserInfoClaims())
+}
+
 func TestValidateCode_Success(t *testing.T) {
 	svc, cleanup := setupTestAuthCodeService(t)
 	defer cleanup()
@@ -54,7 +125,7 @@ func TestValidateCode_Success(t *testing.T) {
 	ctx := context.Background()
 
 	code, err := svc.GenerateCode(ctx, "client-002", "account-002", "http://localhost/callback",
-		[]string{"openid"}, "", "", "", "session-002")
+		[]string{"openid"}, nil, nil, nil, "", "", "", "session-002", time.Time{}, 0)
 	require.NoError(t, err)
 
 	validated, err := svc.ValidateCode(ctx, code.Code, "client-002", "http://localhost/callback", nil)
@@ -71,7 +142,7 @@ func TestValidateCode_SingleUse(t *testing.T) {
 	ctx := context.Background()
 
 	code, err := svc.GenerateCode(ctx, "client-003", "account-003", "http://localhost/callback",
-		[]string{"openid"}, "", "", "", "")
+		[]string{"openid"}, nil, nil, nil, "", "", "", "", time.Time{}, 0)
 	require.NoError(t, err)
 
 	// First use succeeds
@@ -90,7 +161,7 @@ func TestValidateCode_ClientMismatch(t *testing.T) {
 	ctx := context.Background()
 
 	code, err := svc.GenerateCode(ctx, "client-004", "account-004", "http://localhost/callback",
-		[]string{"openid"}, "", "", "", "")
+		[]string{"openid"}, nil, nil, nil, "", "", "", "", time.Time{}, 0)
 	require.NoError(t, err)
 
 	_, err = svc.ValidateCode(ctx, code.Code, "wrong-client", "http://localhost/callback", nil)
@@ -104,7 +175,7 @@ func TestValidateCode_URIMismatch(t *testing.T) {
 	ctx := context.Background()
 
 	code, err := svc.GenerateCode(ctx, "client-005", "account-005", "http://localhost/callback",
-		[]string{"openid"}, "", "", "", "")
+		[]string{"openid"}, nil, nil, nil, "", "", "", "", time.Time{}, 0)
 	require.NoError(t, err)
 
 	_, err = svc.ValidateCode(ctx, code.Code, "client-005", "http://localhost/wrong", nil)
@@ -122,7 +193,7 @@ func TestValidateCode_PKCE_Success(t *testing.T) {
 	codeChallenge := domain.HashPKCEVerifier("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
 
 	code, err := svc.GenerateCode(ctx, "client-006", "account-006", "http://localhost/callback",
-		[]string{"openid"}, codeChallenge, "S256", "", "")
+		[]string{"openid"}, nil, nil, nil, codeChallenge, "S256", "", "", time.Time{}, 0)
 	require.NoError(t, err)
 
 	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
@@ -140,7 +211,7 @@ func TestValidateCode_PKCE_Fail(t *testing.T) {
 	codeChallenge := domain.HashPKCEVerifier("correct-verifier-must-be-at-least-43-characters-long")
 
 	code, err := svc.GenerateCode(ctx, "client-007", "account-007", "http://localhost/callback",
-		[]string{"openid"}, codeChallenge, "S256", "", "")
+		[]string{"openid"}, nil, nil, nil, codeChallenge, "S256", "", "", time.Time{}, 0)
 	require.NoError(t, err)
 
 	wrongVerifier := "wrong-verifier-must-be-at-least-43-characters-long"
@@ -156,7 +227,7 @@ func TestValidateCode_Expired(t *testing.T) {
 
 	ctx := context.Background()
 	code, err := svc.GenerateCode(ctx, "client", "account", "http://localhost/callback",
-		[]string{"openid"}, "", "", "nonce", "")
+		[]string{"openid"}, nil, nil, nil, "", "", "nonce", "", time.Time{}, 0)
 	require.NoError(t, err)
 
 	// Overwrite stored data with expired ExpiresAt while keeping Redis key alive
@@ -177,7 +248,7 @@ func TestValidateCode_PKCE_NilVerifier(t *testing.T) {
 	ctx := context.Background()
 	codeChallenge := domain.HashPKCEVerifier("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
 	code, err := svc.GenerateCode(ctx, "client", "account", "http://localhost/callback",
-		[]string{"openid"}, codeChallenge, "S256", "", "")
+		[]string{"openid"}, nil, nil, nil, codeChallenge, "S256", "", "", time.Time{}, 0)
 	require.NoError(t, err)
 
 	// PKCE challenge was set but verifier is nil
@@ -193,7 +264,7 @@ func TestValidateCode_CorruptData(t *testing.T) {
 
 	ctx := context.Background()
 	code, err := svc.GenerateCode(ctx, "client", "account", "http://localhost/callback",
-		[]string{"openid"}, "", "", "", "")
+		[]string{"openid"}, nil, nil, nil, "", "", "", "", time.Time{}, 0)
 	require.NoError(t, err)
 
 	// Overwrite stored data with invalid JSON
@@ -214,7 +285,7 @@ func TestGenerateCode_RedisError(t *testing.T) {
 	mr.Close()
 
 	_, err = svc.GenerateCode(ctx, "client", "account", "http://localhost/callback",
-		[]string{"openid"}, "", "", "", "")
+		[]string{"openid"}, nil, nil, nil, "", "", "", "", time.Time{}, 0)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "store authorization code")
 }
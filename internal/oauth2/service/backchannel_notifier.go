@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+	"github.com/rushairer/gosso/internal/utility"
+)
+
+// backchannelNotificationTimeout bounds each notification POST.
+const backchannelNotificationTimeout = 5 * time.Second
+
+// BackchannelUserNotification is the JSON body posted to the user notification webhook for
+// a new backchannel authentication request. The receiving service (e.g. a push notification
+// gateway) prompts the user, whose app then approves or denies the request through
+// GET/POST /oauth2/backchannel/requests/{request_id}.
+type BackchannelUserNotification struct {
+	RequestID      string    `json:"request_id"`
+	AccountID      string    `json:"account_id"`
+	ClientID       string    `json:"client_id"`
+	ClientName     string    `json:"client_name"`
+	Scopes         []string  `json:"scopes"`
+	BindingMessage string    `json:"binding_message,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// BackchannelNotifier delivers CIBA notifications over HTTP: requests to the user through a
+// configured webhook, and ping and push callbacks to client notification endpoints.
+type BackchannelNotifier struct {
+	userNotificationURL string
+	httpClient          *http.Client
+	clientHTTPClient    *http.Client
+	logger              *zap.Logger
+}
+
+// NewBackchannelNotifier creates a notifier that posts new requests to userNotificationURL.
+// httpClient calls the configured user notification webhook; nil selects a client with a 5
+// second timeout. clientHTTPClient calls the notification endpoints that clients register;
+// nil uses NewClientURLHTTPClient.
+func NewBackchannelNotifier(userNotificationURL string, httpClient, clientHTTPClient *http.Client, logger *zap.Logger) *BackchannelNotifier {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: backchannelNotificationTimeout}
+	}
+	if clientHTTPClient == nil {
+		clientHTTPClient = NewClientURLHTTPClient()
+	}
+	return &BackchannelNotifier{
+		userNotificationURL: userNotificationURL,
+		httpClient:          httpClient,
+		clientHTTPClient:    clientHTTPClient,
+		logger:              utility.EnsureLogger(logger),
+	}
+}
+
+// NotifyUser posts req to the user notification webhook. It is synchronous so the
+// backchannel authentication endpoint can report a user that cannot be reached.
+func (n *BackchannelNotifier) NotifyUser(ctx context.Context, req *domain.BackchannelAuthRequest, client *domain.OAuth2Client) error {
+	ctx, cancel := context.WithTimeout(ctx, backchannelNotificationTimeout)
+	defer cancel()
+
+	status, err := n.post(ctx, n.httpClient, n.userNotificationURL, "", BackchannelUserNotification{
+		RequestID:      req.ID,
+		AccountID:      req.AccountID,
+		ClientID:       client.ClientID,
+		ClientName:     client.Name,
+		Scopes:         req.Scopes,
+		BindingMessage: req.BindingMessage,
+		ExpiresAt:      req.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("notify user: %w", err)
+	}
+	if status >= 400 {
+		return fmt.Errorf("notify user: webhook returned status %d", status)
+	}
+	return nil
+}
+
+// NotifyClient posts payload to a client notification endpoint in the background,
+// authenticated with the client_notification_token of the request (CIBA Core §10.2, §10.3).
+func (n *BackchannelNotifier) NotifyClient(clientID, endpoint, clientNotificationToken string, payload any) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), backchannelNotificationTimeout)
+		defer cancel()
+
+		status, err := n.post(ctx, n.clientHTTPClient, endpoint, clientNotificationToken, payload)
+		if err != nil {
+			n.logger.Warn("Backchannel client notification failed",
+				zap.String("client_id", clientID), zap.String("uri", endpoint), zap.Error(err))
+			return
+		}
+		if status >= 400 {
+			n.logger.Warn("Backchannel client notification returned error status",
+				zap.String("client_id", clientID), zap.String("uri", endpoint), zap.Int("status", status))
+			return
+		}
+		n.logger.Debug("Backchannel client notification succeeded",
+			zap.String("client_id", clientID), zap.String("uri", endpoint))
+	}()
+}
+
+// post sends payload as JSON to uri with httpClient, with bearerToken as Bearer credentials
+// when set, and returns the response status.
+func (n *BackchannelNotifier) post(ctx context.Context, httpClient *http.Client, uri, bearerToken string, payload any) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("marshal notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode, nil
+}
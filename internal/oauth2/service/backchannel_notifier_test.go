@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+func TestBackchannelNotifier_NotifyUser(t *testing.T) {
+	var received BackchannelUserNotification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	n := NewBackchannelNotifier(srv.URL, nil, nil, zap.NewNop())
+	req := &domain.BackchannelAuthRequest{
+		ID:             "request-001",
+		AccountID:      "account-001",
+		Scopes:         []string{"openid"},
+		BindingMessage: "W4SCT",
+		ExpiresAt:      time.Now().Add(time.Minute),
+	}
+	client := &domain.OAuth2Client{ClientID: "ciba-client", Name: "Call Center"}
+	require.NoError(t, n.NotifyUser(context.Background(), req, client))
+
+	assert.Equal(t, "request-001", received.RequestID)
+	assert.Equal(t, "account-001", received.AccountID)
+	assert.Equal(t, "Call Center", received.ClientName)
+	assert.Equal(t, "W4SCT", received.BindingMessage)
+}
+
+func TestBackchannelNotifier_NotifyUser_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	n := NewBackchannelNotifier(srv.URL, nil, nil, zap.NewNop())
+	err := n.NotifyUser(context.Background(), &domain.BackchannelAuthRequest{}, &domain.OAuth2Client{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 503")
+}
+
+func TestBackchannelNotifier_NotifyClient(t *testing.T) {
+	type notification struct {
+		auth string
+		body map[string]string
+	}
+	received := make(chan notification, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received <- notification{auth: r.Header.Get("Authorization"), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := NewBackchannelNotifier("", nil, srv.Client(), zap.NewNop())
+	n.NotifyClient("ciba-client", srv.URL, "notification-token", map[string]string{"auth_req_id": "req-001"})
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "Bearer notification-token", got.auth)
+		assert.Equal(t, "req-001", got.body["auth_req_id"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("client notification was not delivered")
+	}
+}
+
+func TestBackchannelNotifier_NotifyClient_NonPublicEndpoint(t *testing.T) {
+	delivered := make(chan struct{}, 1)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer srv.Close()
+
+	// Client notification endpoints are registered by clients, so by default they are never
+	// called on non-public addresses.
+	core, logs := observer.New(zap.WarnLevel)
+	n := NewBackchannelNotifier("", nil, nil, zap.New(core))
+	n.NotifyClient("ciba-client", srv.URL, "notification-token", map[string]string{"auth_req_id": "req-001"})
+
+	require.Eventually(t, func() bool { return logs.Len() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, logs.All()[0].ContextMap()["error"], "non-public address")
+	assert.Empty(t, delivered)
+}
@@ -1,8 +1,23 @@
 package service
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/rushairer/gosso/internal/cache"
 	"github.com/rushairer/gosso/internal/oauth2/domain"
 )
 
@@ -10,14 +25,69 @@ import (
 // It prevents attackers from distinguishing between different failure modes via response timing.
 const dummyBcryptHash = "$2a$10$0000000000000000000000000000000000000000000000000000000"
 
+const (
+	// clientAssertionLeeway tolerates clock skew between the client and this server.
+	clientAssertionLeeway = 30 * time.Second
+	// maxClientAssertionLifetime bounds how far in the future exp may be, which also
+	// bounds how long a jti has to be remembered for replay protection.
+	maxClientAssertionLifetime  = 10 * time.Minute
+	clientAssertionJTIKeyPrefix = "client_assertion_jti:"
+)
+
+// Signing algorithms accepted for JWT client assertions, per token_endpoint_auth_method.
+var (
+	ClientSecretJWTSigningAlgs = []string{"HS256", "HS384", "HS512"}
+	PrivateKeyJWTSigningAlgs   = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+)
+
 // ClientAuthenticator handles OAuth2 client authentication.
-type ClientAuthenticator struct{}
+// The zero value supports client_secret_basic / client_secret_post only; use
+// NewClientAuthenticator to also accept JWT client assertions (RFC 7523).
+type ClientAuthenticator struct {
+	redis        *cache.RedisClient
+	secretCipher *ClientSecretCipher
+	jwks         jwksURICache
+	// tlsClientAuthRoots are the CAs that tls_client_auth certificates must chain to.
+	tlsClientAuthRoots *x509.CertPool
+}
+
+// NewClientAuthenticator creates a ClientAuthenticator that also verifies client assertions.
+// Redis is required for jti replay protection; secretCipher may be nil, in which case
+// client_secret_jwt assertions are rejected. httpClient fetches client jwks_uri documents;
+// nil uses NewClientURLHTTPClient.
+func NewClientAuthenticator(redis *cache.RedisClient, secretCipher *ClientSecretCipher, httpClient *http.Client) *ClientAuthenticator {
+	if httpClient == nil {
+		httpClient = NewClientURLHTTPClient()
+	}
+	return &ClientAuthenticator{
+		redis:        redis,
+		secretCipher: secretCipher,
+		jwks:         newJWKSURICache(httpClient),
+	}
+}
+
+// SetTLSClientAuthRoots sets the trust anchors of PKI mutual-TLS client authentication
+// (RFC 8705 §2.1). tls_client_auth is refused while none are set.
+func (a *ClientAuthenticator) SetTLSClientAuthRoots(roots *x509.CertPool) {
+	a.tlsClientAuthRoots = roots
+}
 
 // AuthenticateClient verifies client credentials.
 // For confidential clients, it verifies the client_secret via bcrypt.
 // For public clients, it returns nil (no secret required).
-// Returns ErrClientSecretRequired or ErrInvalidClientSecret on failure.
+// Clients registered for private_key_jwt or client_secret_jwt must use a client assertion,
+// and clients registered for mutual-TLS authentication must use AuthenticateClientCertificate.
+// Returns ErrClientSecretRequired, ErrInvalidClientSecret, ErrClientAssertionRequired or
+// ErrClientCertificateRequired on failure.
 func (a *ClientAuthenticator) AuthenticateClient(client *domain.OAuth2Client, clientSecret string) error {
+	if client.UsesClientAssertion() {
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyBcryptHash), []byte("dummy"))
+		return ErrClientAssertionRequired
+	}
+	if client.UsesTLSClientAuth() {
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyBcryptHash), []byte("dummy"))
+		return ErrClientCertificateRequired
+	}
 	if !client.IsConfidential {
 		// Timing normalization: if a secret is provided for a public client,
 		// perform a dummy bcrypt comparison to prevent timing-based client type detection.
@@ -41,6 +111,134 @@ func (a *ClientAuthenticator) AuthenticateClient(client *domain.OAuth2Client, cl
 	return nil
 }
 
+// AuthenticateClientAssertion verifies a JWT client assertion (RFC 7523 §3, OIDC Core §9).
+// iss and sub must be the client_id, aud must contain one of audiences, exp and jti are
+// required, and each jti is accepted only once while the assertion is valid.
+func (a *ClientAuthenticator) AuthenticateClientAssertion(ctx context.Context, client *domain.OAuth2Client, assertion string, audiences []string) error {
+	var algs []string
+	switch client.TokenEndpointAuthMethod {
+	case domain.AuthMethodClientSecretJWT:
+		algs = ClientSecretJWTSigningAlgs
+	case domain.AuthMethodPrivateKeyJWT:
+		algs = PrivateKeyJWTSigningAlgs
+	default:
+		return ErrClientAuthMethodNotAllowed
+	}
+	if a.redis == nil {
+		return fmt.Errorf("%w: replay protection unavailable", ErrInvalidClientAssertion)
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(algs),
+		jwt.WithIssuer(client.ClientID),
+		jwt.WithSubject(client.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clientAssertionLeeway),
+	)
+	claims := &jwt.RegisteredClaims{}
+	if _, err := parser.ParseWithClaims(assertion, claims, func(token *jwt.Token) (any, error) {
+		return a.assertionVerificationKey(ctx, client, token)
+	}); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidClientAssertion, err)
+	}
+
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool { return slices.Contains(audiences, aud) }) {
+		return fmt.Errorf("%w: audience mismatch", ErrInvalidClientAssertion)
+	}
+	if claims.ID == "" {
+		return fmt.Errorf("%w: jti is required", ErrInvalidClientAssertion)
+	}
+	ttl := time.Until(claims.ExpiresAt.Time) + clientAssertionLeeway
+	if ttl > maxClientAssertionLifetime+clientAssertionLeeway {
+		return fmt.Errorf("%w: exp is too far in the future", ErrInvalidClientAssertion)
+	}
+
+	// Hash the jti so attacker-controlled values never become raw Redis keys.
+	sum := sha256.Sum256([]byte(client.ClientID + "\x00" + claims.ID))
+	fresh, err := a.redis.SetNX(ctx, clientAssertionJTIKeyPrefix+hex.EncodeToString(sum[:]), "1", ttl)
+	if err != nil {
+		return fmt.Errorf("%w: replay check failed: %v", ErrInvalidClientAssertion, err)
+	}
+	if !fresh {
+		return ErrClientAssertionReplayed
+	}
+	return nil
+}
+
+// assertionVerificationKey returns the key(s) that may have signed token.
+func (a *ClientAuthenticator) assertionVerificationKey(ctx context.Context, client *domain.OAuth2Client, token *jwt.Token) (any, error) {
+	if client.TokenEndpointAuthMethod == domain.AuthMethodClientSecretJWT {
+		if client.ClientSecretEncrypted == "" {
+			return nil, errors.New("client has no secret for client_secret_jwt")
+		}
+		secret, err := a.secretCipher.Decrypt(client.ClientID, client.ClientSecretEncrypted)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	keys, err := a.clientVerificationKeys(ctx, client, false)
+	if err != nil {
+		return nil, err
+	}
+	matched := matchClientJWKs(keys, kid, token.Method)
+	if len(matched) == 0 && client.JWKSURI != "" {
+		// The client may have rotated keys since the set was cached.
+		if keys, err = a.clientVerificationKeys(ctx, client, true); err != nil {
+			return nil, err
+		}
+		matched = matchClientJWKs(keys, kid, token.Method)
+	}
+	if len(matched) == 0 {
+		return nil, errors.New("no matching verification key")
+	}
+	return jwt.VerificationKeySet{Keys: matched}, nil
+}
+
+// matchClientJWKs returns the keys usable for method, restricted to kid when present.
+func matchClientJWKs(keys []clientJWK, kid string, method jwt.SigningMethod) []jwt.VerificationKey {
+	alg := method.Alg()
+	var matched []jwt.VerificationKey
+	for _, k := range keys {
+		if kid != "" && k.KeyID != kid {
+			continue
+		}
+		if k.Algorithm != "" && k.Algorithm != alg {
+			continue
+		}
+		var ok bool
+		switch method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			_, ok = k.Key.(*rsa.PublicKey)
+		case *jwt.SigningMethodECDSA:
+			var pub *ecdsa.PublicKey
+			pub, ok = k.Key.(*ecdsa.PublicKey)
+			ok = ok && pub.Curve.Params().BitSize == method.(*jwt.SigningMethodECDSA).CurveBits
+		case *jwt.SigningMethodEd25519:
+			_, ok = k.Key.(ed25519.PublicKey)
+		}
+		if ok {
+			matched = append(matched, k.Key)
+		}
+	}
+	return matched
+}
+
+// clientVerificationKeys returns the client's registered public keys from the inline
+// jwks or, failing that, from jwks_uri. forceRefresh refetches jwks_uri unless it was
+// fetched within clientJWKSRefreshInterval.
+func (a *ClientAuthenticator) clientVerificationKeys(ctx context.Context, client *domain.OAuth2Client, forceRefresh bool) ([]clientJWK, error) {
+	if len(client.JWKS) > 0 {
+		return parseClientJWKS(client.JWKS)
+	}
+	if client.JWKSURI == "" {
+		return nil, errors.New("client has no registered jwks or jwks_uri")
+	}
+	return a.jwks.keys(ctx, client.JWKSURI, forceRefresh)
+}
+
 // DummyAuthenticate performs a dummy bcrypt comparison to mitigate timing side-channels.
 // Call this when client lookup fails to make the response time indistinguishable
 // from "client found, wrong secret."
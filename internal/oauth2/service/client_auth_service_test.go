@@ -1,13 +1,27 @@
 package service
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/rushairer/gosso/internal/oauth2/domain"
+	"github.com/rushairer/gosso/internal/testutil"
 )
 
 func hashSecret(t *testing.T, secret string) string {
@@ -54,3 +68,187 @@ func TestClientAuthenticator_ConfidentialClient_CorrectSecret(t *testing.T) {
 	}
 	assert.NoError(t, auth.AuthenticateClient(client, "real-secret"))
 }
+
+func rsaJWKS(t *testing.T, kid string, pub *rsa.PublicKey) json.RawMessage {
+	t.Helper()
+	raw, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+	require.NoError(t, err)
+	return raw
+}
+
+func ecJWKS(t *testing.T, kid string, pub *ecdsa.PublicKey) json.RawMessage {
+	t.Helper()
+	raw, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, 32))),
+		"y": base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, 32))),
+	}}})
+	require.NoError(t, err)
+	return raw
+}
+
+func signAssertion(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.RegisteredClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func assertionClaims(clientID, aud string) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Issuer:    clientID,
+		Subject:   clientID,
+		Audience:  jwt.ClaimStrings{aud},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		ID:        uuid.NewString(),
+	}
+}
+
+const testTokenEndpoint = "https://sso.example.com/oauth2/token"
+
+func setupPrivateKeyJWTClient(t *testing.T) (*ClientAuthenticator, *domain.OAuth2Client, *rsa.PrivateKey) {
+	t.Helper()
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	client := &domain.OAuth2Client{
+		ClientID:                "pkjwt-client",
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT,
+		JWKS:                    rsaJWKS(t, "k1", &key.PublicKey),
+	}
+	return NewClientAuthenticator(redisClient, nil, nil), client, key
+}
+
+func TestClientAuthenticator_AssertionClientRejectsSecret(t *testing.T) {
+	auth := &ClientAuthenticator{}
+	client := &domain.OAuth2Client{
+		IsConfidential:          true,
+		ClientSecretHash:        hashSecret(t, "real-secret"),
+		TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT,
+	}
+	assert.ErrorIs(t, auth.AuthenticateClient(client, "real-secret"), ErrClientAssertionRequired)
+}
+
+func TestClientAuthenticator_PrivateKeyJWT(t *testing.T) {
+	auth, client, key := setupPrivateKeyJWTClient(t)
+	assertion := signAssertion(t, jwt.SigningMethodRS256, key, "k1", assertionClaims(client.ClientID, testTokenEndpoint))
+
+	err := auth.AuthenticateClientAssertion(context.Background(), client, assertion, []string{testTokenEndpoint})
+	require.NoError(t, err)
+
+	// RFC 7523 §3: the same jti must not be accepted twice.
+	err = auth.AuthenticateClientAssertion(context.Background(), client, assertion, []string{testTokenEndpoint})
+	assert.ErrorIs(t, err, ErrClientAssertionReplayed)
+}
+
+func TestClientAuthenticator_PrivateKeyJWT_Invalid(t *testing.T) {
+	auth, client, key := setupPrivateKeyJWTClient(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		key    *rsa.PrivateKey
+		mutate func(*jwt.RegisteredClaims)
+	}{
+		{"wrong audience", key, func(c *jwt.RegisteredClaims) { c.Audience = jwt.ClaimStrings{"https://other.example.com"} }},
+		{"issuer mismatch", key, func(c *jwt.RegisteredClaims) { c.Issuer = "someone-else" }},
+		{"subject mismatch", key, func(c *jwt.RegisteredClaims) { c.Subject = "someone-else" }},
+		{"missing exp", key, func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil }},
+		{"expired", key, func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }},
+		{"exp too far in the future", key, func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour)) }},
+		{"missing jti", key, func(c *jwt.RegisteredClaims) { c.ID = "" }},
+		{"unregistered key", otherKey, func(*jwt.RegisteredClaims) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := assertionClaims(client.ClientID, testTokenEndpoint)
+			tt.mutate(&claims)
+			assertion := signAssertion(t, jwt.SigningMethodRS256, tt.key, "k1", claims)
+			err := auth.AuthenticateClientAssertion(context.Background(), client, assertion, []string{testTokenEndpoint})
+			assert.ErrorIs(t, err, ErrInvalidClientAssertion)
+		})
+	}
+}
+
+func TestClientAuthenticator_PrivateKeyJWT_RejectsHMAC(t *testing.T) {
+	auth, client, _ := setupPrivateKeyJWTClient(t)
+	// An HS256 assertion keyed with public material must never verify for private_key_jwt.
+	assertion := signAssertion(t, jwt.SigningMethodHS256, []byte("public-key-bytes"), "k1", assertionClaims(client.ClientID, testTokenEndpoint))
+	err := auth.AuthenticateClientAssertion(context.Background(), client, assertion, []string{testTokenEndpoint})
+	assert.ErrorIs(t, err, ErrInvalidClientAssertion)
+}
+
+func TestClientAuthenticator_PrivateKeyJWT_JWKSURI(t *testing.T) {
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var fetches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(ecJWKS(t, "ec1", &key.PublicKey))
+	}))
+	t.Cleanup(srv.Close)
+
+	auth := NewClientAuthenticator(redisClient, nil, srv.Client())
+	client := &domain.OAuth2Client{
+		ClientID:                "pkjwt-uri-client",
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT,
+		JWKSURI:                 srv.URL + "/jwks.json",
+	}
+	for range 2 {
+		assertion := signAssertion(t, jwt.SigningMethodES256, key, "ec1", assertionClaims(client.ClientID, testTokenEndpoint))
+		require.NoError(t, auth.AuthenticateClientAssertion(context.Background(), client, assertion, []string{testTokenEndpoint}))
+	}
+	assert.Equal(t, 1, fetches, "jwks_uri response should be cached")
+}
+
+func TestClientAuthenticator_ClientSecretJWT(t *testing.T) {
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	cipher, err := NewClientSecretCipher("a9fd7106e9647479494fddf8a850b6d9a09114c0afa81e77385686f5455e7270")
+	require.NoError(t, err)
+	encrypted, err := cipher.Encrypt("csjwt-client", "shared-secret-value-with-enough-entropy")
+	require.NoError(t, err)
+
+	client := &domain.OAuth2Client{
+		ClientID:                "csjwt-client",
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodClientSecretJWT,
+		ClientSecretEncrypted:   encrypted,
+	}
+	auth := NewClientAuthenticator(redisClient, cipher, nil)
+
+	assertion := signAssertion(t, jwt.SigningMethodHS256, []byte("shared-secret-value-with-enough-entropy"), "", assertionClaims(client.ClientID, testTokenEndpoint))
+	require.NoError(t, auth.AuthenticateClientAssertion(context.Background(), client, assertion, []string{testTokenEndpoint}))
+
+	wrong := signAssertion(t, jwt.SigningMethodHS256, []byte("wrong-secret"), "", assertionClaims(client.ClientID, testTokenEndpoint))
+	assert.ErrorIs(t, auth.AuthenticateClientAssertion(context.Background(), client, wrong, []string{testTokenEndpoint}), ErrInvalidClientAssertion)
+}
+
+func TestClientAuthenticator_AssertionNotAllowedForSecretClient(t *testing.T) {
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	auth := NewClientAuthenticator(redisClient, nil, nil)
+	client := &domain.OAuth2Client{ClientID: "secret-client", IsConfidential: true}
+
+	assertion := signAssertion(t, jwt.SigningMethodHS256, []byte("anything"), "", assertionClaims(client.ClientID, testTokenEndpoint))
+	err := auth.AuthenticateClientAssertion(context.Background(), client, assertion, []string{testTokenEndpoint})
+	assert.ErrorIs(t, err, ErrClientAuthMethodNotAllowed)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rushairer/gosso/internal/jose"
+)
+
+// maxClientJWKSKeys bounds the number of keys accepted from a client JWK Set.
+const maxClientJWKSKeys = 20
+
+// Public key uses (RFC 7517 §4.2) selected from client JWK Sets.
+const (
+	jwkUseSignature  = "sig"
+	jwkUseEncryption = "enc"
+)
+
+// clientJWK is a public verification or encryption key taken from a client's JWK Set (RFC 7517).
+type clientJWK struct {
+	KeyID     string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
+// parseClientJWKS parses a JWK Set and returns its signature verification keys.
+// Keys with use other than "sig" are skipped. Private key members are rejected so a
+// client cannot accidentally publish its signing key through registration.
+func parseClientJWKS(data []byte) ([]clientJWK, error) {
+	return parseClientJWKSForUse(data, jwkUseSignature)
+}
+
+// parseClientEncryptionJWKS parses a JWK Set and returns its encryption keys, the keys
+// with use "enc" or no use.
+func parseClientEncryptionJWKS(data []byte) ([]clientJWK, error) {
+	return parseClientJWKSForUse(data, jwkUseEncryption)
+}
+
+// parseClientJWKSForUse parses a JWK Set and returns the keys with the given use or none.
+func parseClientJWKSForUse(data []byte, use string) ([]clientJWK, error) {
+	var set struct {
+		Keys []jose.JWK `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+	if len(set.Keys) == 0 {
+		return nil, errors.New("jwks must contain at least one key")
+	}
+	if len(set.Keys) > maxClientJWKSKeys {
+		return nil, fmt.Errorf("jwks must not contain more than %d keys", maxClientJWKSKeys)
+	}
+
+	keys := make([]clientJWK, 0, len(set.Keys))
+	for i, jwk := range set.Keys {
+		if jwk.IsPrivate() {
+			return nil, fmt.Errorf("jwks key %d contains private key material", i)
+		}
+		if jwk.Use != "" && jwk.Use != use {
+			continue
+		}
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %d: %w", i, err)
+		}
+		keys = append(keys, clientJWK{KeyID: jwk.Kid, Algorithm: jwk.Alg, Key: pub})
+	}
+	if len(keys) == 0 {
+		if use == jwkUseEncryption {
+			return nil, errors.New("jwks contains no encryption keys")
+		}
+		return nil, errors.New("jwks contains no signature keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClientJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keys, err := parseClientJWKS(rsaJWKS(t, "rsa1", &rsaKey.PublicKey))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "rsa1", keys[0].KeyID)
+	assert.Equal(t, "RS256", keys[0].Algorithm)
+	assert.True(t, rsaKey.PublicKey.Equal(keys[0].Key))
+
+	keys, err = parseClientJWKS(ecJWKS(t, "ec1", &ecKey.PublicKey))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, ecKey.PublicKey.Equal(keys[0].Key))
+
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys, err = parseClientJWKS([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","x":"` + base64.RawURLEncoding.EncodeToString(edPub) + `"}]}`))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, edPub.Equal(keys[0].Key))
+}
+
+func TestParseClientJWKS_SkipsEncryptionKeys(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	x := base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32)))
+	y := base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32)))
+
+	_, err = parseClientJWKS([]byte(`{"keys":[{"kty":"EC","use":"enc","crv":"P-256","x":"` + x + `","y":"` + y + `"}]}`))
+	assert.ErrorContains(t, err, "no signature keys")
+}
+
+func TestParseClientJWKS_Invalid(t *testing.T) {
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	zero := base64.RawURLEncoding.EncodeToString(make([]byte, 32))
+
+	tests := []struct {
+		name string
+		jwks string
+	}{
+		{"not json", `not json`},
+		{"no keys", `{"keys":[]}`},
+		{"private key", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"` + zero + `","d":"` + zero + `"}]}`},
+		{"unsupported kty", `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`},
+		{"unsupported curve", `{"keys":[{"kty":"EC","crv":"secp256k1","x":"` + zero + `","y":"` + zero + `"}]}`},
+		{"point not on curve", `{"keys":[{"kty":"EC","crv":"P-256","x":"` + zero + `","y":"` + zero + `"}]}`},
+		{"short rsa key", string(rsaJWKS(t, "small", &smallKey.PublicKey))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseClientJWKS([]byte(tt.jwks))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,624 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/audit"
+	auditDomain "github.com/rushairer/gosso/internal/audit/domain"
+	auditService "github.com/rushairer/gosso/internal/audit/service"
+	"github.com/rushairer/gosso/internal/cache"
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+	"github.com/rushairer/gosso/internal/utility"
+)
+
+const (
+	// initialAccessTokenKeyPrefix namespaces initial access tokens, keyed by the SHA-256
+	// hash of the token so a Redis dump does not leak usable tokens.
+	initialAccessTokenKeyPrefix = "initial_access_token:"
+
+	// DefaultInitialAccessTokenTTL is the lifetime of an initial access token when the
+	// administrator does not choose one.
+	DefaultInitialAccessTokenTTL = 24 * time.Hour
+	// MaxInitialAccessTokenTTL bounds the lifetime of an initial access token.
+	MaxInitialAccessTokenTTL = 30 * 24 * time.Hour
+	// MaxInitialAccessTokenUses bounds how many clients one initial access token can register.
+	MaxInitialAccessTokenUses = 100
+)
+
+// Client registration error codes (RFC 7591 §3.2.2).
+const (
+	RegistrationErrorInvalidRedirectURI          = "invalid_redirect_uri"
+	RegistrationErrorInvalidClientMetadata       = "invalid_client_metadata"
+	RegistrationErrorInvalidSoftwareStatement    = "invalid_software_statement"
+	RegistrationErrorUnapprovedSoftwareStatement = "unapproved_software_statement"
+)
+
+// Client metadata keys under which software statement details are kept.
+const (
+	ClientMetadataSoftwareID      = "software_id"
+	ClientMetadataSoftwareVersion = "software_version"
+)
+
+// issueInitialAccessTokenScript stores an initial access token hash with its owner
+// account and remaining uses, and sets its expiry in the same step.
+// ARGV[1]=account_id, ARGV[2]=uses, ARGV[3]=ttl_ms
+var issueInitialAccessTokenScript = redis.NewScript(`
+redis.call('HSET', KEYS[1], 'account_id', ARGV[1], 'remaining', ARGV[2])
+return redis.call('PEXPIRE', KEYS[1], ARGV[3])
+`)
+
+// consumeInitialAccessTokenScript takes one use of an initial access token and returns
+// its owner account, or nil when the token is unknown, expired or used up.
+var consumeInitialAccessTokenScript = redis.NewScript(`
+local remaining = tonumber(redis.call('HGET', KEYS[1], 'remaining'))
+if not remaining or remaining <= 0 then
+    return false
+end
+redis.call('HINCRBY', KEYS[1], 'remaining', -1)
+return redis.call('HGET', KEYS[1], 'account_id')
+`)
+
+// refundInitialAccessTokenScript gives back a use taken by a registration that was
+// rejected, unless the token has expired in the meantime.
+var refundInitialAccessTokenScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+    return redis.call('HINCRBY', KEYS[1], 'remaining', 1)
+end
+return 0
+`)
+
+// ClientMetadata is the client metadata of a dynamic registration request and response
+// (RFC 7591 §2), plus the gosso client settings that have registered metadata names.
+type ClientMetadata struct {
+	ClientID                              string          `json:"client_id,omitempty"`
+	ClientName                            string          `json:"client_name,omitempty"`
+	RedirectURIs                          []string        `json:"redirect_uris,omitempty"`
+	PostLogoutRedirectURIs                []string        `json:"post_logout_redirect_uris,omitempty"`
+	TokenEndpointAuthMethod               string          `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes                            []string        `json:"grant_types,omitempty"`
+	ResponseTypes                         []string        `json:"response_types,omitempty"`
+	Scope                                 string          `json:"scope,omitempty"`
+	JWKSURI                               string          `json:"jwks_uri,omitempty"`
+	JWKS                                  json.RawMessage `json:"jwks,omitempty"`
+	SoftwareID                            string          `json:"software_id,omitempty"`
+	SoftwareVersion                       string          `json:"software_version,omitempty"`
+	SoftwareStatement                     string          `json:"software_statement,omitempty"`
+	RequirePushedAuthorizationRequests    bool            `json:"require_pushed_authorization_requests,omitempty"`
+	DPoPBoundAccessTokens                 bool            `json:"dpop_bound_access_tokens,omitempty"`
+	TLSClientAuthSubjectDN                string          `json:"tls_client_auth_subject_dn,omitempty"`
+	TLSClientAuthSANDNS                   string          `json:"tls_client_auth_san_dns,omitempty"`
+	TLSClientAuthSANURI                   string          `json:"tls_client_auth_san_uri,omitempty"`
+	TLSClientAuthSANIP                    string          `json:"tls_client_auth_san_ip,omitempty"`
+	TLSClientAuthSANEmail                 string          `json:"tls_client_auth_san_email,omitempty"`
+	TLSClientCertificateBoundAccessTokens bool            `json:"tls_client_certificate_bound_access_tokens,omitempty"`
+	RequireSignedRequestObject            bool            `json:"require_signed_request_object,omitempty"`
+	RequestURIs                           []string        `json:"request_uris,omitempty"`
+	AuthorizationDetailsTypes             []string        `json:"authorization_details_types,omitempty"`
+	BackchannelTokenDeliveryMode          string          `json:"backchannel_token_delivery_mode,omitempty"`
+	BackchannelClientNotificationEndpoint string          `json:"backchannel_client_notification_endpoint,omitempty"`
+	SubjectType                           string          `json:"subject_type,omitempty"`
+	SectorIdentifierURI                   string          `json:"sector_identifier_uri,omitempty"`
+	IDTokenSignedResponseAlg              string          `json:"id_token_signed_response_alg,omitempty"`
+	AccessTokenProfile                    string          `json:"access_token_profile,omitempty"`
+	IDTokenEncryptedResponseAlg           string          `json:"id_token_encrypted_response_alg,omitempty"`
+	IDTokenEncryptedResponseEnc           string          `json:"id_token_encrypted_response_enc,omitempty"`
+	UserinfoEncryptedResponseAlg          string          `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserinfoEncryptedResponseEnc          string          `json:"userinfo_encrypted_response_enc,omitempty"`
+	UserinfoSignedResponseAlg             string          `json:"userinfo_signed_response_alg,omitempty"`
+	RefreshTokenIdleLifetime              int             `json:"refresh_token_idle_lifetime,omitempty"`
+	RefreshTokenAbsoluteLifetime          int             `json:"refresh_token_absolute_lifetime,omitempty"`
+	RefreshTokenExpiration                string          `json:"refresh_token_expiration,omitempty"`
+	AccessTokenLifetime                   int             `json:"access_token_lifetime,omitempty"`
+	IDTokenLifetime                       int             `json:"id_token_lifetime,omitempty"`
+	AuthorizationCodeLifetime             int             `json:"authorization_code_lifetime,omitempty"`
+	DeviceCodeLifetime                    int             `json:"device_code_lifetime,omitempty"`
+}
+
+// ClientMetadataFromClient returns the registered metadata of client.
+func ClientMetadataFromClient(client *domain.OAuth2Client) ClientMetadata {
+	md := ClientMetadata{
+		ClientID:                              client.ClientID,
+		ClientName:                            client.Name,
+		RedirectURIs:                          client.RedirectURIs,
+		PostLogoutRedirectURIs:                client.PostLogoutRedirectURIs,
+		TokenEndpointAuthMethod:               client.TokenEndpointAuthMethod,
+		GrantTypes:                            client.GrantTypes,
+		Scope:                                 strings.Join(client.Scopes, " "),
+		JWKSURI:                               client.JWKSURI,
+		JWKS:                                  client.JWKS,
+		RequirePushedAuthorizationRequests:    client.RequirePushedAuthorizationRequests,
+		DPoPBoundAccessTokens:                 client.DPoPBoundAccessTokens,
+		TLSClientAuthSubjectDN:                client.TLSClientAuthSubjectDN,
+		TLSClientAuthSANDNS:                   client.TLSClientAuthSANDNS,
+		TLSClientAuthSANURI:                   client.TLSClientAuthSANURI,
+		TLSClientAuthSANIP:                    client.TLSClientAuthSANIP,
+		TLSClientAuthSANEmail:                 client.TLSClientAuthSANEmail,
+		TLSClientCertificateBoundAccessTokens: client.TLSClientCertificateBoundAccessTokens,
+		RequireSignedRequestObject:            client.RequireSignedRequestObject,
+		RequestURIs:                           client.RequestURIs,
+		AuthorizationDetailsTypes:             client.AuthorizationDetailsTypes,
+		BackchannelTokenDeliveryMode:          client.BackchannelTokenDeliveryMode,
+		BackchannelClientNotificationEndpoint: client.BackchannelClientNotificationEndpoint,
+		SubjectType:                           client.SubjectType,
+		SectorIdentifierURI:                   client.SectorIdentifierURI,
+		IDTokenSignedResponseAlg:              client.IDTokenSigningAlg(),
+		AccessTokenProfile:                    client.AccessTokenProfile,
+		IDTokenEncryptedResponseAlg:           client.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:           client.IDTokenEncryptedResponseEnc,
+		UserinfoEncryptedResponseAlg:          client.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc:          client.UserinfoEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:             client.UserinfoSignedResponseAlg,
+		RefreshTokenIdleLifetime:              client.RefreshTokenIdleLifetime,
+		RefreshTokenAbsoluteLifetime:          client.RefreshTokenAbsoluteLifetime,
+		RefreshTokenExpiration:                client.RefreshTokenExpiration,
+		AccessTokenLifetime:                   client.AccessTokenLifetime,
+		IDTokenLifetime:                       client.IDTokenLifetime,
+		AuthorizationCodeLifetime:             client.AuthorizationCodeLifetime,
+		DeviceCodeLifetime:                    client.DeviceCodeLifetime,
+	}
+	if md.SubjectType == "" {
+		md.SubjectType = domain.SubjectTypePublic
+	}
+	if md.TokenEndpointAuthMethod == "" {
+		md.TokenEndpointAuthMethod = domain.AuthMethodClientSecretBasic
+		if !client.IsConfidential {
+			md.TokenEndpointAuthMethod = domain.AuthMethodNone
+		}
+	}
+	if client.HasGrantType(domain.GrantTypeAuthorizationCode) {
+		md.ResponseTypes = []string{"code"}
+	}
+	md.SoftwareID, _ = client.Metadata[ClientMetadataSoftwareID].(string)
+	md.SoftwareVersion, _ = client.Metadata[ClientMetadataSoftwareVersion].(string)
+	return md
+}
+
+// ClientRegistration is the result of a dynamic client registration (RFC 7591 §3.2.1).
+// ClientSecret is empty for public clients. Both secrets are only available here.
+type ClientRegistration struct {
+	Client                  *domain.OAuth2Client
+	ClientSecret            string
+	RegistrationAccessToken string
+}
+
+// InitialAccessToken is an administrator-issued credential for the registration endpoint.
+// Clients registered with it are owned by AccountID.
+type InitialAccessToken struct {
+	Token     string
+	AccountID string
+	ExpiresAt time.Time
+	Uses      int
+}
+
+// RegistrationError is a client registration error response (RFC 7591 §3.2.2).
+type RegistrationError struct {
+	Code        string
+	Description string
+}
+
+func (e *RegistrationError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// SoftwareStatementIssuer is a software publisher whose signed software statements
+// (RFC 7591 §2.3) are accepted. Exactly one of JWKSURI and JWKS provides its keys.
+type SoftwareStatementIssuer struct {
+	Issuer  string
+	JWKSURI string
+	JWKS    []byte
+}
+
+// ClientRegistrationConfig configures dynamic client registration.
+type ClientRegistrationConfig struct {
+	// SoftwareStatementIssuers lists the trusted software statement publishers. Statements
+	// from other issuers are rejected as unapproved.
+	SoftwareStatementIssuers []SoftwareStatementIssuer
+	// RequireSoftwareStatement rejects registrations without a software statement.
+	RequireSoftwareStatement bool
+	// HTTPClient fetches publisher jwks_uri documents. Nil uses a 5 second timeout.
+	HTTPClient *http.Client
+}
+
+// ClientRegistrationService implements dynamic client registration (RFC 7591) and the
+// client configuration endpoint (RFC 7592) on top of OAuth2ClientService, so dynamically
+// registered clients go through the same validation and audit trail as managed ones.
+//
+// Registration requires an initial access token issued by an administrator; the token's
+// account owns the clients registered with it. Each client receives a registration
+// access token that authorizes reading, updating and deleting it.
+type ClientRegistrationService struct {
+	clients                  OAuth2ClientService
+	redis                    *cache.RedisClient
+	auditor                  *auditService.Auditor
+	logger                   *zap.Logger
+	statementIssuers         map[string]*trustedJWTIssuer
+	requireSoftwareStatement bool
+	jwks                     jwksURICache
+}
+
+// NewClientRegistrationService creates a client registration service. Redis stores the
+// initial access tokens and is required.
+func NewClientRegistrationService(clients OAuth2ClientService, redis *cache.RedisClient, auditor *auditService.Auditor, logger *zap.Logger, cfg ClientRegistrationConfig) (*ClientRegistrationService, error) {
+	if redis == nil {
+		return nil, errors.New("client registration service: redis client is required")
+	}
+	s := &ClientRegistrationService{
+		clients:                  clients,
+		redis:                    redis,
+		auditor:                  auditor,
+		logger:                   utility.EnsureLogger(logger),
+		statementIssuers:         make(map[string]*trustedJWTIssuer, len(cfg.SoftwareStatementIssuers)),
+		requireSoftwareStatement: cfg.RequireSoftwareStatement,
+		jwks:                     newJWKSURICache(cfg.HTTPClient),
+	}
+	for _, iss := range cfg.SoftwareStatementIssuers {
+		if iss.Issuer == "" {
+			return nil, errors.New("software statement issuer must not be empty")
+		}
+		if _, dup := s.statementIssuers[iss.Issuer]; dup {
+			return nil, fmt.Errorf("software statement issuer %q is configured more than once", iss.Issuer)
+		}
+		trusted, err := newTrustedJWTIssuer(iss.Issuer, iss.JWKSURI, iss.JWKS)
+		if err != nil {
+			return nil, err
+		}
+		s.statementIssuers[iss.Issuer] = trusted
+	}
+	if s.requireSoftwareStatement && len(s.statementIssuers) == 0 {
+		return nil, errors.New("client registration service: require_software_statement needs at least one software statement issuer")
+	}
+	return s, nil
+}
+
+// IssueInitialAccessToken issues a token that registers up to uses clients owned by
+// accountID within ttl. issuedBy is the administrator requesting it, for the audit trail.
+func (s *ClientRegistrationService) IssueInitialAccessToken(ctx context.Context, issuedBy, accountID string, ttl time.Duration, uses int) (*InitialAccessToken, error) {
+	if accountID == "" {
+		return nil, &ValidationError{Message: "account_id is required"}
+	}
+	if ttl == 0 {
+		ttl = DefaultInitialAccessTokenTTL
+	}
+	if ttl < time.Minute || ttl > MaxInitialAccessTokenTTL {
+		return nil, &ValidationError{Message: fmt.Sprintf("expires_in must be between 60 and %d seconds", int(MaxInitialAccessTokenTTL.Seconds()))}
+	}
+	if uses == 0 {
+		uses = 1
+	}
+	if uses < 1 || uses > MaxInitialAccessTokenUses {
+		return nil, &ValidationError{Message: fmt.Sprintf("max_registrations must be between 1 and %d", MaxInitialAccessTokenUses)}
+	}
+
+	token, err := generateRegistrationToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate initial access token: %w", err)
+	}
+	key := initialAccessTokenKeyPrefix + domain.HashRegistrationAccessToken(token)
+	if err := s.redis.RunScript(ctx, issueInitialAccessTokenScript, []string{key}, accountID, uses, ttl.Milliseconds()).Err(); err != nil {
+		return nil, fmt.Errorf("store initial access token: %w", err)
+	}
+
+	auditService.AuditLog(ctx, s.auditor, s.logger, auditDomain.NewRecord(
+		auditDomain.ActionOAuth2InitialAccessTokenIssue,
+		audit.IPFromContext(ctx),
+		&accountID,
+		utility.MarshalJSONOrEmpty(map[string]any{"issued_by": issuedBy, "max_registrations": uses, "expires_in": int(ttl.Seconds())}),
+		nil,
+	))
+
+	return &InitialAccessToken{Token: token, AccountID: accountID, ExpiresAt: time.Now().Add(ttl), Uses: uses}, nil
+}
+
+// RegisterClient registers a client with the metadata of a registration request
+// (RFC 7591 §3.1), authorized by initialAccessToken. Metadata errors are returned as
+// *RegistrationError and give the initial access token use back.
+func (s *ClientRegistrationService) RegisterClient(ctx context.Context, initialAccessToken string, md *ClientMetadata) (*ClientRegistration, error) {
+	if initialAccessToken == "" {
+		return nil, ErrInvalidInitialAccessToken
+	}
+	key := initialAccessTokenKeyPrefix + domain.HashRegistrationAccessToken(initialAccessToken)
+	accountID, err := s.redis.RunScript(ctx, consumeInitialAccessTokenScript, []string{key}).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInvalidInitialAccessToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("consume initial access token: %w", err)
+	}
+
+	registration, err := s.registerClient(ctx, accountID, md)
+	if err != nil {
+		var regErr *RegistrationError
+		if errors.As(err, &regErr) {
+			if refundErr := s.redis.RunScript(ctx, refundInitialAccessTokenScript, []string{key}).Err(); refundErr != nil {
+				s.logger.Warn("Failed to refund initial access token use", zap.Error(refundErr))
+			}
+		}
+		return nil, err
+	}
+	return registration, nil
+}
+
+func (s *ClientRegistrationService) registerClient(ctx context.Context, accountID string, md *ClientMetadata) (*ClientRegistration, error) {
+	if err := s.applySoftwareStatement(ctx, md); err != nil {
+		return nil, err
+	}
+	if md.TokenEndpointAuthMethod == "" {
+		// RFC 7591 §2: the default is client_secret_basic.
+		md.TokenEndpointAuthMethod = domain.AuthMethodClientSecretBasic
+	}
+	if len(md.GrantTypes) == 0 {
+		md.GrantTypes = []string{domain.GrantTypeAuthorizationCode}
+	}
+	if err := validateRegistrationMetadata(md); err != nil {
+		return nil, err
+	}
+
+	registrationToken, err := generateRegistrationToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate registration access token: %w", err)
+	}
+	metadata := map[string]any{}
+	if md.SoftwareID != "" {
+		metadata[ClientMetadataSoftwareID] = md.SoftwareID
+	}
+	if md.SoftwareVersion != "" {
+		metadata[ClientMetadataSoftwareVersion] = md.SoftwareVersion
+	}
+
+	client, secret, err := s.clients.RegisterClient(ctx, &RegisterClientRequest{
+		AccountID:                             accountID,
+		Name:                                  md.ClientName,
+		RedirectURIs:                          md.RedirectURIs,
+		PostLogoutRedirectURIs:                md.PostLogoutRedirectURIs,
+		GrantTypes:                            md.GrantTypes,
+		Scopes:                                strings.Fields(md.Scope),
+		IsConfidential:                        md.TokenEndpointAuthMethod != domain.AuthMethodNone,
+		Metadata:                              metadata,
+		RequirePushedAuthorizationRequests:    md.RequirePushedAuthorizationRequests,
+		TokenEndpointAuthMethod:               md.TokenEndpointAuthMethod,
+		JWKS:                                  md.JWKS,
+		JWKSURI:                               md.JWKSURI,
+		DPoPBoundAccessTokens:                 md.DPoPBoundAccessTokens,
+		TLSClientAuthSubjectDN:                md.TLSClientAuthSubjectDN,
+		TLSClientAuthSANDNS:                   md.TLSClientAuthSANDNS,
+		TLSClientAuthSANURI:                   md.TLSClientAuthSANURI,
+		TLSClientAuthSANIP:                    md.TLSClientAuthSANIP,
+		TLSClientAuthSANEmail:                 md.TLSClientAuthSANEmail,
+		TLSClientCertificateBoundAccessTokens: md.TLSClientCertificateBoundAccessTokens,
+		RequireSignedRequestObject:            md.RequireSignedRequestObject,
+		RequestURIs:                           md.RequestURIs,
+		AuthorizationDetailsTypes:             md.AuthorizationDetailsTypes,
+		BackchannelTokenDeliveryMode:          md.BackchannelTokenDeliveryMode,
+		BackchannelClientNotificationEndpoint: md.BackchannelClientNotificationEndpoint,
+		SubjectType:                           md.SubjectType,
+		SectorIdentifierURI:                   md.SectorIdentifierURI,
+		IDTokenSignedResponseAlg:              md.IDTokenSignedResponseAlg,
+		AccessTokenProfile:                    md.AccessTokenProfile,
+		IDTokenEncryptedResponseAlg:           md.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:           md.IDTokenEncryptedResponseEnc,
+		UserinfoEncryptedResponseAlg:          md.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc:          md.UserinfoEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:             md.UserinfoSignedResponseAlg,
+		RefreshTokenIdleLifetime:              md.RefreshTokenIdleLifetime,
+		RefreshTokenAbsoluteLifetime:          md.RefreshTokenAbsoluteLifetime,
+		RefreshTokenExpiration:                md.RefreshTokenExpiration,
+		AccessTokenLifetime:                   md.AccessTokenLifetime,
+		IDTokenLifetime:                       md.IDTokenLifetime,
+		AuthorizationCodeLifetime:             md.AuthorizationCodeLifetime,
+		DeviceCodeLifetime:                    md.DeviceCodeLifetime,
+		RegistrationAccessTokenHash:           domain.HashRegistrationAccessToken(registrationToken),
+	})
+	if err != nil {
+		return nil, registrationError(err)
+	}
+	return &ClientRegistration{Client: client, ClientSecret: secret, RegistrationAccessToken: registrationToken}, nil
+}
+
+// GetClient returns the client named by clientID if registrationAccessToken is its
+// registration access token (RFC 7592 §2.1).
+func (s *ClientRegistrationService) GetClient(ctx context.Context, clientID, registrationAccessToken string) (*domain.OAuth2Client, error) {
+	client, err := s.clients.FindByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, domain.ErrClientNotFound) {
+			return nil, ErrInvalidRegistrationAccessToken
+		}
+		return nil, err
+	}
+	if !client.VerifyRegistrationAccessToken(registrationAccessToken) {
+		return nil, ErrInvalidRegistrationAccessToken
+	}
+	return client, nil
+}
+
+// UpdateClient replaces the metadata of a dynamically registered client (RFC 7592 §2.2).
+// Omitted fields are reset to their defaults, as the request carries the full metadata.
+func (s *ClientRegistrationService) UpdateClient(ctx context.Context, clientID, registrationAccessToken string, md *ClientMetadata) (*domain.OAuth2Client, error) {
+	client, err := s.GetClient(ctx, clientID, registrationAccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if md.ClientID != client.ClientID {
+		return nil, &RegistrationError{Code: RegistrationErrorInvalidClientMetadata, Description: "client_id must match the client being updated"}
+	}
+	if err := s.applySoftwareStatement(ctx, md); err != nil {
+		return nil, err
+	}
+	if md.TokenEndpointAuthMethod == "" {
+		md.TokenEndpointAuthMethod = domain.AuthMethodClientSecretBasic
+	}
+	if len(md.GrantTypes) == 0 {
+		md.GrantTypes = []string{domain.GrantTypeAuthorizationCode}
+	}
+	if err := validateRegistrationMetadata(md); err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(md.Scope)
+	if len(scopes) == 0 {
+		scopes = []string{"openid"}
+	}
+	req := &UpdateClientRequest{
+		Name:                                  &md.ClientName,
+		PostLogoutRedirectURIs:                append([]string{}, md.PostLogoutRedirectURIs...),
+		GrantTypes:                            md.GrantTypes,
+		Scopes:                                scopes,
+		RequirePushedAuthorizationRequests:    &md.RequirePushedAuthorizationRequests,
+		TokenEndpointAuthMethod:               &md.TokenEndpointAuthMethod,
+		JWKS:                                  md.JWKS,
+		JWKSURI:                               &md.JWKSURI,
+		DPoPBoundAccessTokens:                 &md.DPoPBoundAccessTokens,
+		TLSClientAuthSubjectDN:                &md.TLSClientAuthSubjectDN,
+		TLSClientAuthSANDNS:                   &md.TLSClientAuthSANDNS,
+		TLSClientAuthSANURI:                   &md.TLSClientAuthSANURI,
+		TLSClientAuthSANIP:                    &md.TLSClientAuthSANIP,
+		TLSClientAuthSANEmail:                 &md.TLSClientAuthSANEmail,
+		TLSClientCertificateBoundAccessTokens: &md.TLSClientCertificateBoundAccessTokens,
+		RequireSignedRequestObject:            &md.RequireSignedRequestObject,
+		RequestURIs:                           append([]string{}, md.RequestURIs...),
+		AuthorizationDetailsTypes:             append([]string{}, md.AuthorizationDetailsTypes...),
+		BackchannelTokenDeliveryMode:          &md.BackchannelTokenDeliveryMode,
+		BackchannelClientNotificationEndpoint: &md.BackchannelClientNotificationEndpoint,
+		SubjectType:                           &md.SubjectType,
+		SectorIdentifierURI:                   &md.SectorIdentifierURI,
+		IDTokenSignedResponseAlg:              &md.IDTokenSignedResponseAlg,
+		AccessTokenProfile:                    &md.AccessTokenProfile,
+		IDTokenEncryptedResponseAlg:           &md.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:           &md.IDTokenEncryptedResponseEnc,
+		UserinfoEncryptedResponseAlg:          &md.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc:          &md.UserinfoEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:             &md.UserinfoSignedResponseAlg,
+		RefreshTokenIdleLifetime:              &md.RefreshTokenIdleLifetime,
+		RefreshTokenAbsoluteLifetime:          &md.RefreshTokenAbsoluteLifetime,
+		RefreshTokenExpiration:                &md.RefreshTokenExpiration,
+		AccessTokenLifetime:                   &md.AccessTokenLifetime,
+		IDTokenLifetime:                       &md.IDTokenLifetime,
+		AuthorizationCodeLifetime:             &md.AuthorizationCodeLifetime,
+		DeviceCodeLifetime:                    &md.DeviceCodeLifetime,
+	}
+	if len(md.RedirectURIs) > 0 {
+		req.RedirectURIs = md.RedirectURIs
+	}
+	updated, err := s.clients.UpdateClientByAccountID(ctx, client.AccountID, client.ClientID, req)
+	if err != nil {
+		return nil, registrationError(err)
+	}
+	return updated, nil
+}
+
+// DeleteClient deletes a dynamically registered client (RFC 7592 §2.3).
+func (s *ClientRegistrationService) DeleteClient(ctx context.Context, clientID, registrationAccessToken string) error {
+	client, err := s.GetClient(ctx, clientID, registrationAccessToken)
+	if err != nil {
+		return err
+	}
+	return s.clients.DeleteClient(ctx, client.AccountID, client.ClientID)
+}
+
+// applySoftwareStatement verifies the software statement of md, if any, and applies its
+// claims, which take precedence over the plain JSON metadata (RFC 7591 §2.3).
+func (s *ClientRegistrationService) applySoftwareStatement(ctx context.Context, md *ClientMetadata) error {
+	statement := md.SoftwareStatement
+	if statement == "" {
+		if s.requireSoftwareStatement {
+			return &RegistrationError{Code: RegistrationErrorUnapprovedSoftwareStatement, Description: "a software statement is required"}
+		}
+		return nil
+	}
+
+	unverified := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(statement, unverified); err != nil {
+		return &RegistrationError{Code: RegistrationErrorInvalidSoftwareStatement, Description: "software statement is not a valid JWT"}
+	}
+	issuer, ok := s.statementIssuers[unverified.Issuer]
+	if !ok {
+		return &RegistrationError{Code: RegistrationErrorUnapprovedSoftwareStatement, Description: "software statement issuer is not trusted"}
+	}
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(PrivateKeyJWTSigningAlgs),
+		jwt.WithIssuer(unverified.Issuer),
+		jwt.WithLeeway(clientAssertionLeeway),
+	)
+	if _, err := parser.ParseWithClaims(statement, claims, func(token *jwt.Token) (any, error) {
+		return issuer.verificationKey(ctx, &s.jwks, token)
+	}); err != nil {
+		s.logger.Debug("Software statement verification failed", zap.Error(err))
+		return &RegistrationError{Code: RegistrationErrorInvalidSoftwareStatement, Description: "software statement verification failed"}
+	}
+
+	for _, registered := range []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti", "client_id", "software_statement"} {
+		delete(claims, registered)
+	}
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return &RegistrationError{Code: RegistrationErrorInvalidSoftwareStatement, Description: "software statement claims are invalid"}
+	}
+	if err := json.Unmarshal(raw, md); err != nil {
+		return &RegistrationError{Code: RegistrationErrorInvalidSoftwareStatement, Description: "software statement claims are invalid"}
+	}
+	return nil
+}
+
+// validateRegistrationMetadata checks the metadata rules specific to dynamic registration.
+// Everything else is validated by OAuth2ClientService.
+func validateRegistrationMetadata(md *ClientMetadata) error {
+	if strings.TrimSpace(md.ClientName) == "" {
+		return &RegistrationError{Code: RegistrationErrorInvalidClientMetadata, Description: "client_name is required"}
+	}
+	usesCode := slices.Contains(md.GrantTypes, domain.GrantTypeAuthorizationCode)
+	if len(md.ResponseTypes) == 0 && usesCode {
+		md.ResponseTypes = []string{"code"}
+	}
+	// RFC 7591 §2.1: response_types and grant_types must be consistent. Only the code
+	// response type is supported.
+	for _, rt := range md.ResponseTypes {
+		if rt != "code" {
+			return &RegistrationError{Code: RegistrationErrorInvalidClientMetadata, Description: fmt.Sprintf("unsupported response_type: %q", rt)}
+		}
+	}
+	if usesCode != (len(md.ResponseTypes) > 0) {
+		return &RegistrationError{Code: RegistrationErrorInvalidClientMetadata, Description: "response_type code requires the authorization_code grant type and vice versa"}
+	}
+	if usesCode && len(md.RedirectURIs) == 0 {
+		return &RegistrationError{Code: RegistrationErrorInvalidRedirectURI, Description: "redirect_uris is required for the authorization_code grant type"}
+	}
+	if err := validateRedirectURIs(md.RedirectURIs); err != nil {
+		return &RegistrationError{Code: RegistrationErrorInvalidRedirectURI, Description: err.Error()}
+	}
+	if err := validateRedirectURIs(md.PostLogoutRedirectURIs); err != nil {
+		return &RegistrationError{Code: RegistrationErrorInvalidRedirectURI, Description: "post_logout_redirect_uris: " + err.Error()}
+	}
+	return nil
+}
+
+// registrationError maps OAuth2ClientService validation errors to invalid_client_metadata.
+func registrationError(err error) error {
+	if IsValidationError(err) {
+		return &RegistrationError{Code: RegistrationErrorInvalidClientMetadata, Description: err.Error()}
+	}
+	return err
+}
+
+// generateRegistrationToken generates a 32-byte bearer token (64 hex characters) used for
+// initial and registration access tokens.
+func generateRegistrationToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+	"github.com/rushairer/gosso/internal/testutil"
+)
+
+const testSoftwarePublisher = "https://publisher.example.com"
+
+// fakeClientService is an in-memory OAuth2ClientService for registration tests.
+type fakeClientService struct {
+	clients map[string]*domain.OAuth2Client
+	lastReq *RegisterClientRequest
+	update  *UpdateClientRequest
+	err     error
+}
+
+func (f *fakeClientService) RegisterClient(_ context.Context, req *RegisterClientRequest) (*domain.OAuth2Client, string, error) {
+	if f.err != nil {
+		return nil, "", f.err
+	}
+	f.lastReq = req
+	client := &domain.OAuth2Client{
+		ClientID:                    "dyn-client",
+		AccountID:                   req.AccountID,
+		Name:                        req.Name,
+		RedirectURIs:                req.RedirectURIs,
+		GrantTypes:                  req.GrantTypes,
+		Scopes:                      req.Scopes,
+		IsConfidential:              req.IsConfidential,
+		Metadata:                    req.Metadata,
+		TokenEndpointAuthMethod:     req.TokenEndpointAuthMethod,
+		RegistrationAccessTokenHash: req.RegistrationAccessTokenHash,
+	}
+	f.clients[client.ClientID] = client
+	return client, "client-secret", nil
+}
+
+func (f *fakeClientService) FindByClientID(_ context.Context, clientID string) (*domain.OAuth2Client, error) {
+	client, ok := f.clients[clientID]
+	if !ok {
+		return nil, domain.ErrClientNotFound
+	}
+	return client, nil
+}
+
+func (f *fakeClientService) FindByAccountID(context.Context, string) ([]*domain.OAuth2Client, error) {
+	return nil, nil
+}
+
+func (f *fakeClientService) UpdateClient(context.Context, *domain.OAuth2Client) error { return nil }
+
+func (f *fakeClientService) UpdateClientByAccountID(_ context.Context, accountID, clientID string, req *UpdateClientRequest) (*domain.OAuth2Client, error) {
+	client := f.clients[clientID]
+	if client.AccountID != accountID {
+		return nil, ErrClientAccessDenied
+	}
+	f.update = req
+	client.Name = *req.Name
+	return client, nil
+}
+
+func (f *fakeClientService) DeleteClient(_ context.Context, accountID, clientID string) error {
+	if f.clients[clientID].AccountID != accountID {
+		return ErrClientAccessDenied
+	}
+	delete(f.clients, clientID)
+	return nil
+}
+
+func setupClientRegistration(t *testing.T, cfg ClientRegistrationConfig) (*ClientRegistrationService, *fakeClientService, *miniredis.Miniredis) {
+	t.Helper()
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	clients := &fakeClientService{clients: map[string]*domain.OAuth2Client{}}
+	svc, err := NewClientRegistrationService(clients, redisClient, nil, nil, cfg)
+	require.NoError(t, err)
+	return svc, clients, mr
+}
+
+func webClientMetadata() *ClientMetadata {
+	return &ClientMetadata{
+		ClientName:   "Dynamic App",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		Scope:        "openid profile",
+	}
+}
+
+func TestNewClientRegistrationService_InvalidConfig(t *testing.T) {
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	clients := &fakeClientService{}
+
+	_, err := NewClientRegistrationService(clients, nil, nil, nil, ClientRegistrationConfig{})
+	assert.Error(t, err)
+	_, err = NewClientRegistrationService(clients, redisClient, nil, nil, ClientRegistrationConfig{RequireSoftwareStatement: true})
+	assert.Error(t, err)
+	_, err = NewClientRegistrationService(clients, redisClient, nil, nil, ClientRegistrationConfig{
+		SoftwareStatementIssuers: []SoftwareStatementIssuer{{Issuer: testSoftwarePublisher}},
+	})
+	assert.Error(t, err, "issuer without keys")
+	_, err = NewClientRegistrationService(clients, redisClient, nil, nil, ClientRegistrationConfig{
+		SoftwareStatementIssuers: []SoftwareStatementIssuer{
+			{Issuer: testSoftwarePublisher, JWKSURI: "https://publisher.example.com/jwks"},
+			{Issuer: testSoftwarePublisher, JWKSURI: "https://publisher.example.com/jwks"},
+		},
+	})
+	assert.Error(t, err, "duplicate issuer")
+}
+
+func TestIssueInitialAccessToken_Validation(t *testing.T) {
+	svc, _, _ := setupClientRegistration(t, ClientRegistrationConfig{})
+	ctx := context.Background()
+
+	_, err := svc.IssueInitialAccessToken(ctx, "admin", "", 0, 0)
+	assert.True(t, IsValidationError(err))
+	_, err = svc.IssueInitialAccessToken(ctx, "admin", "acc-001", time.Second, 0)
+	assert.True(t, IsValidationError(err))
+	_, err = svc.IssueInitialAccessToken(ctx, "admin", "acc-001", 0, MaxInitialAccessTokenUses+1)
+	assert.True(t, IsValidationError(err))
+
+	iat, err := svc.IssueInitialAccessToken(ctx, "admin", "acc-001", 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, iat.Token, 64)
+	assert.Equal(t, 1, iat.Uses)
+	assert.WithinDuration(t, time.Now().Add(DefaultInitialAccessTokenTTL), iat.ExpiresAt, time.Minute)
+}
+
+func TestRegisterClient_Success(t *testing.T) {
+	svc, clients, _ := setupClientRegistration(t, ClientRegistrationConfig{})
+	ctx := context.Background()
+	iat, err := svc.IssueInitialAccessToken(ctx, "admin", "acc-001", time.Hour, 1)
+	require.NoError(t, err)
+
+	md := webClientMetadata()
+	md.SoftwareID = "app-1"
+	reg, err := svc.RegisterClient(ctx, iat.Token, md)
+	require.NoError(t, err)
+
+	assert.Equal(t, "client-secret", reg.ClientSecret)
+	assert.Len(t, reg.RegistrationAccessToken, 64)
+	assert.True(t, reg.Client.VerifyRegistrationAccessToken(reg.RegistrationAccessToken))
+	assert.Equal(t, "acc-001", clients.lastReq.AccountID)
+	assert.True(t, clients.lastReq.IsConfidential)
+	assert.Equal(t, domain.AuthMethodClientSecretBasic, clients.lastReq.TokenEndpointAuthMethod)
+	assert.Equal(t, []string{domain.GrantTypeAuthorizationCode}, clients.lastReq.GrantTypes)
+	assert.Equal(t, []string{"openid", "profile"}, clients.lastReq.Scopes)
+	assert.Equal(t, "app-1", clients.lastReq.Metadata[ClientMetadataSoftwareID])
+
+	// The single-use token is spent.
+	_, err = svc.RegisterClient(ctx, iat.Token, webClientMetadata())
+	assert.ErrorIs(t, err, ErrInvalidInitialAccessToken)
+}
+
+func TestRegisterClient_InvalidInitialAccessToken(t *testing.T) {
+	svc, _, mr := setupClientRegistration(t, ClientRegistrationConfig{})
+	ctx := context.Background()
+
+	_, err := svc.RegisterClient(ctx, "", webClientMetadata())
+	assert.ErrorIs(t, err, ErrInvalidInitialAccessToken)
+	_, err = svc.RegisterClient(ctx, "unknown", webClientMetadata())
+	assert.ErrorIs(t, err, ErrInvalidInitialAccessToken)
+
+	iat, err := svc.IssueInitialAccessToken(ctx, "admin", "acc-001", time.Hour, 1)
+	require.NoError(t, err)
+	mr.FastForward(2 * time.Hour)
+	_, err = svc.RegisterClient(ctx, iat.Token, webClientMetadata())
+	assert.ErrorIs(t, err, ErrInvalidInitialAccessToken)
+}
+
+func TestRegisterClient_InvalidMetadata(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(md *ClientMetadata)
+		code   string
+	}{
+		{"missing name", func(md *ClientMetadata) { md.ClientName = "" }, RegistrationErrorInvalidClientMetadata},
+		{"missing redirect uris", func(md *ClientMetadata) { md.RedirectURIs = nil }, RegistrationErrorInvalidRedirectURI},
+		{"invalid redirect uri", func(md *ClientMetadata) { md.RedirectURIs = []string{"http://app.example.com/cb#frag"} }, RegistrationErrorInvalidRedirectURI},
+		{"unsupported response type", func(md *ClientMetadata) { md.ResponseTypes = []string{"token"} }, RegistrationErrorInvalidClientMetadata},
+		{"response type without grant", func(md *ClientMetadata) {
+			md.GrantTypes = []string{domain.GrantTypeClientCredentials}
+			md.ResponseTypes = []string{"code"}
+		}, RegistrationErrorInvalidClientMetadata},
+		{"unexpected software statement", func(md *ClientMetadata) { md.SoftwareStatement = "not-a-jwt" }, RegistrationErrorInvalidSoftwareStatement},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := setupClientRegistration(t, ClientRegistrationConfig{})
+			ctx := context.Background()
+			iat, err := svc.IssueInitialAccessToken(ctx, "admin", "acc-001", time.Hour, 1)
+			require.NoError(t, err)
+
+			md := webClientMetadata()
+			tt.modify(md)
+			_, err = svc.RegisterClient(ctx, iat.Token, md)
+			var regErr *RegistrationError
+			require.ErrorAs(t, err, &regErr)
+			assert.Equal(t, tt.code, regErr.Code)
+
+			// A rejected registration gives the token use back.
+			_, err = svc.RegisterClient(ctx, iat.Token, webClientMetadata())
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRegisterClient_ClientServiceValidationError(t *testing.T) {
+	svc, clients, _ := setupClientRegistration(t, ClientRegistrationConfig{})
+	ctx := context.Background()
+	iat, err := svc.IssueInitialAccessToken(ctx, "admin", "acc-001", time.Hour, 1)
+	require.NoError(t, err)
+
+	clients.err = &ValidationError{Message: "invalid scope"}
+	_, err = svc.RegisterClient(ctx, iat.Token, webClientMetadata())
+	var regErr *RegistrationError
+	require.ErrorAs(t, err, &regErr)
+	assert.Equal(t, RegistrationErrorInvalidClientMetadata, regErr.Code)
+}
+
+func TestRegisterClient_SoftwareStatement(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	svc, clients, _ := setupClientRegistration(t, ClientRegistrationConfig{
+		SoftwareStatementIssuers: []SoftwareStatementIssuer{{Issuer: testSoftwarePublisher, JWKS: rsaJWKS(t, "p1", &key.PublicKey)}},
+		RequireSoftwareStatement: true,
+	})
+	ctx := context.Background()
+	sign := func(issuer string, signer *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":         issuer,
+			"iat":         time.Now().Unix(),
+			"software_id": "published-app",
+			"client_name": "Published App",
+		})
+		token.Header["kid"] = "p1"
+		signed, err := token.SignedString(signer)
+		require.NoError(t, err)
+		return signed
+	}
+	register := func(statement string) error {
+		iat, err := svc.IssueInitialAccessToken(ctx, "admin", "acc-001", time.Hour, 1)
+		require.NoError(t, err)
+		md := webClientMetadata()
+		md.SoftwareID = "self-asserted"
+		md.SoftwareStatement = statement
+		_, err = svc.RegisterClient(ctx, iat.Token, md)
+		return err
+	}
+	registrationErrorCode := func(err error) string {
+		var regErr *RegistrationError
+		require.ErrorAs(t, err, &regErr)
+		return regErr.Code
+	}
+
+	require.NoError(t, register(sign(testSoftwarePublisher, key)))
+	assert.Equal(t, "Published App", clients.lastReq.Name, "statement claims take precedence")
+	assert.Equal(t, "published-app", clients.lastReq.Metadata[ClientMetadataSoftwareID])
+
+	assert.Equal(t, RegistrationErrorUnapprovedSoftwareStatement, registrationErrorCode(register("")))
+	assert.Equal(t, RegistrationErrorUnapprovedSoftwareStatement, registrationErrorCode(register(sign("https://other.example.com", key))))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	assert.Equal(t, RegistrationErrorInvalidSoftwareStatement, registrationErrorCode(register(sign(testSoftwarePublisher, otherKey))))
+}
+
+func TestClientConfiguration(t *testing.T) {
+	svc, clients, _ := setupClientRegistration(t, ClientRegistrationConfig{})
+	ctx := context.Background()
+	iat, err := svc.IssueInitialAccessToken(ctx, "admin", "acc-001", time.Hour, 1)
+	require.NoError(t, err)
+	reg, err := svc.RegisterClient(ctx, iat.Token, webClientMetadata())
+	require.NoError(t, err)
+	clientID := reg.Client.ClientID
+
+	_, err = svc.GetClient(ctx, clientID, "wrong-token")
+	assert.ErrorIs(t, err, ErrInvalidRegistrationAccessToken)
+	_, err = svc.GetClient(ctx, "missing", reg.RegistrationAccessToken)
+	assert.ErrorIs(t, err, ErrInvalidRegistrationAccessToken)
+	client, err := svc.GetClient(ctx, clientID, reg.RegistrationAccessToken)
+	require.NoError(t, err)
+	md := ClientMetadataFromClient(client)
+	assert.Equal(t, []string{"code"}, md.ResponseTypes)
+	assert.Equal(t, "openid profile", md.Scope)
+
+	md.ClientName = "Renamed App"
+	md.ClientID = "other-client"
+	_, err = svc.UpdateClient(ctx, clientID, reg.RegistrationAccessToken, &md)
+	var regErr *RegistrationError
+	require.ErrorAs(t, err, &regErr)
+	assert.Equal(t, RegistrationErrorInvalidClientMetadata, regErr.Code)
+
+	md.ClientID = clientID
+	updated, err := svc.UpdateClient(ctx, clientID, reg.RegistrationAccessToken, &md)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed App", updated.Name)
+	assert.Equal(t, []string{"openid", "profile"}, clients.update.Scopes)
+	assert.Equal(t, "", *clients.update.JWKSURI, "omitted fields are reset")
+
+	assert.ErrorIs(t, svc.DeleteClient(ctx, clientID, "wrong-token"), ErrInvalidRegistrationAccessToken)
+	require.NoError(t, svc.DeleteClient(ctx, clientID, reg.RegistrationAccessToken))
+	_, err = svc.GetClient(ctx, clientID, reg.RegistrationAccessToken)
+	assert.ErrorIs(t, err, ErrInvalidRegistrationAccessToken)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrClientSecretCipherUnavailable is returned when client_secret_jwt is used without
+// auth.client_secret_encryption_key configured.
+var ErrClientSecretCipherUnavailable = errors.New("client secret encryption key is not configured")
+
+// ClientSecretCipher encrypts client secrets that must stay recoverable. Secrets are
+// normally stored only as bcrypt hashes, but client_secret_jwt assertions are HMACs
+// keyed with the secret itself (RFC 7523 §2.2), so those clients also keep an
+// AES-256-GCM encrypted copy. The client_id is bound as additional data so an
+// encrypted secret cannot be copied onto another client row.
+type ClientSecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewClientSecretCipher creates a cipher from a hex-encoded 32-byte key.
+// An empty key returns (nil, nil): client_secret_jwt is then unavailable.
+func NewClientSecretCipher(hexKey string) (*ClientSecretCipher, error) {
+	if hexKey == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode client secret encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("client secret encryption key must be 32 bytes (got %d)", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create client secret cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create client secret cipher: %w", err)
+	}
+	return &ClientSecretCipher{aead: aead}, nil
+}
+
+// Encrypt returns the hex-encoded nonce + ciphertext of secret for clientID.
+func (c *ClientSecretCipher) Encrypt(clientID, secret string) (string, error) {
+	if c == nil {
+		return "", ErrClientSecretCipherUnavailable
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	return hex.EncodeToString(c.aead.Seal(nonce, nonce, []byte(secret), []byte(clientID))), nil
+}
+
+// Decrypt reverses Encrypt. It fails if the value was encrypted for a different client.
+func (c *ClientSecretCipher) Decrypt(clientID, encoded string) (string, error) {
+	if c == nil {
+		return "", ErrClientSecretCipherUnavailable
+	}
+	data, err := hex.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decode encrypted client secret: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("encrypted client secret is too short")
+	}
+	plaintext, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(clientID))
+	if err != nil {
+		return "", fmt.Errorf("decrypt client secret: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClientSecretKey = "a9fd7106e9647479494fddf8a850b6d9a09114c0afa81e77385686f5455e7270"
+
+func TestClientSecretCipher_RoundTrip(t *testing.T) {
+	cipher, err := NewClientSecretCipher(testClientSecretKey)
+	require.NoError(t, err)
+
+	encrypted, err := cipher.Encrypt("client-a", "s3cret")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "s3cret")
+
+	plaintext, err := cipher.Decrypt("client-a", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", plaintext)
+}
+
+func TestClientSecretCipher_BoundToClientID(t *testing.T) {
+	cipher, err := NewClientSecretCipher(testClientSecretKey)
+	require.NoError(t, err)
+
+	encrypted, err := cipher.Encrypt("client-a", "s3cret")
+	require.NoError(t, err)
+
+	_, err = cipher.Decrypt("client-b", encrypted)
+	assert.Error(t, err)
+}
+
+func TestNewClientSecretCipher_Key(t *testing.T) {
+	cipher, err := NewClientSecretCipher("")
+	require.NoError(t, err)
+	assert.Nil(t, cipher)
+
+	_, err = cipher.Encrypt("client-a", "s3cret")
+	assert.ErrorIs(t, err, ErrClientSecretCipherUnavailable)
+
+	_, err = NewClientSecretCipher("not-hex")
+	assert.Error(t, err)
+
+	_, err = NewClientSecretCipher("abcd")
+	assert.Error(t, err)
+}
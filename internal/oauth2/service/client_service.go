@@ -5,8 +5,11 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,6 +20,7 @@ import (
 	auditDomain "github.com/rushairer/gosso/internal/audit/domain"
 	auditService "github.com/rushairer/gosso/internal/audit/service"
 	dbutil "github.com/rushairer/gosso/internal/db"
+	"github.com/rushairer/gosso/internal/jose"
 	"github.com/rushairer/gosso/internal/oauth2/domain"
 	"github.com/rushairer/gosso/internal/oauth2/repository"
 	"github.com/rushairer/gosso/internal/utility"
@@ -24,16 +28,73 @@ import (
 
 // RegisterClientRequest represents a request to register an OAuth2 client
 type RegisterClientRequest struct {
-	AccountID              string
-	Name                   string
-	Description            string
-	RedirectURIs           []string
-	PostLogoutRedirectURIs []string
-	GrantTypes             []string
-	Scopes                 []string
-	IsConfidential         bool
-	Metadata               map[string]any
-	AllowReservedScopes    bool
+	AccountID                          string
+	Name                               string
+	Description                        string
+	RedirectURIs                       []string
+	PostLogoutRedirectURIs             []string
+	GrantTypes                         []string
+	Scopes                             []string
+	IsConfidential                     bool
+	Metadata                           map[string]any
+	AllowReservedScopes                bool
+	RequirePushedAuthorizationRequests bool
+	TokenEndpointAuthMethod            string
+	JWKS                               json.RawMessage
+	JWKSURI                            string
+	TokenExchangeAudiences             []string
+	DPoPBoundAccessTokens              bool
+	// TLS client certificate subject for tls_client_auth (RFC 8705 §2.1.2); at most one is set.
+	TLSClientAuthSubjectDN                string
+	TLSClientAuthSANDNS                   string
+	TLSClientAuthSANURI                   string
+	TLSClientAuthSANIP                    string
+	TLSClientAuthSANEmail                 string
+	TLSClientCertificateBoundAccessTokens bool
+	RequireSignedRequestObject            bool
+	RequestURIs                           []string
+	AuthorizationDetailsTypes             []string
+	// CIBA token delivery (poll, ping or push) and the notification endpoint used by ping
+	// and push clients.
+	BackchannelTokenDeliveryMode          string
+	BackchannelClientNotificationEndpoint string
+	// Subject identifier type (public or pairwise) and the sector_identifier_uri listing the
+	// redirect URIs of a pairwise client (OpenID Connect Core §8).
+	SubjectType         string
+	SectorIdentifierURI string
+	// IDTokenSignedResponseAlg is the algorithm the client's ID tokens are signed with;
+	// empty means RS256 (OpenID Connect Registration §2).
+	IDTokenSignedResponseAlg string
+	// AccessTokenProfile is the format of the client's access tokens: empty for gosso's own,
+	// or rfc9068 (RFC 9068).
+	AccessTokenProfile string
+	// JWE algorithms the client's ID tokens and UserInfo responses are encrypted with, using
+	// a key from its jwks or jwks_uri; an empty alg means unencrypted and an empty enc means
+	// A128CBC-HS256 (OpenID Connect Registration §2).
+	IDTokenEncryptedResponseAlg  string
+	IDTokenEncryptedResponseEnc  string
+	UserinfoEncryptedResponseAlg string
+	UserinfoEncryptedResponseEnc string
+	// UserinfoSignedResponseAlg is the algorithm the client's UserInfo responses are signed
+	// with; empty means plain JSON responses (OpenID Connect Registration §2).
+	UserinfoSignedResponseAlg string
+	// Refresh token lifetime policy: the idle and absolute lifetimes in seconds (0 for the
+	// server default and no limit) and the expiration mode, sliding (or empty) or fixed.
+	RefreshTokenIdleLifetime     int
+	RefreshTokenAbsoluteLifetime int
+	RefreshTokenExpiration       string
+	// Lifetimes in seconds of the client's access tokens, ID tokens, authorization codes and
+	// device codes; 0 applies the server default.
+	AccessTokenLifetime       int
+	IDTokenLifetime           int
+	AuthorizationCodeLifetime int
+	DeviceCodeLifetime        int
+	// AllowTokenExchangePolicy permits setting TokenExchangeAudiences. The policy decides
+	// which APIs a client can mint tokens for, so only administrators may change it.
+	AllowTokenExchangePolicy bool
+	// RegistrationAccessTokenHash is set for clients created through dynamic client
+	// registration (RFC 7592); see domain.HashRegistrationAccessToken.
+	RegistrationAccessTokenHash string
 }
 
 // OAuth2ClientService is the OAuth2 client service interface
@@ -47,19 +108,48 @@ type OAuth2ClientService interface {
 }
 
 type oauth2ClientServiceImpl struct {
-	db         *sql.DB
-	clientRepo repository.OAuth2ClientRepository
-	auditor    *auditService.Auditor
-	logger     *zap.Logger
+	db           *sql.DB
+	clientRepo   repository.OAuth2ClientRepository
+	auditor      *auditService.Auditor
+	logger       *zap.Logger
+	secretCipher *ClientSecretCipher
+	subjects     *SubjectIdentifierService
+	// signingAlgs are the algorithms clients may have their ID tokens and UserInfo
+	// responses signed with.
+	signingAlgs []string
+	// lifetimeLimits caps the token and code lifetimes clients may register.
+	lifetimeLimits ClientLifetimeLimits
 }
 
-// NewOAuth2ClientService creates a new OAuth2 client service instance
-func NewOAuth2ClientService(db *sql.DB, clientRepo repository.OAuth2ClientRepository, auditor *auditService.Auditor, logger *zap.Logger) OAuth2ClientService {
+// ClientLifetimeLimits are the longest lifetimes clients may register for their tokens and
+// codes. The refresh token limit applies to the idle lifetime; the absolute lifetime only
+// ever shortens a grant. A zero limit leaves that lifetime unbounded.
+type ClientLifetimeLimits struct {
+	AccessToken       time.Duration
+	IDToken           time.Duration
+	RefreshToken      time.Duration
+	AuthorizationCode time.Duration
+	DeviceCode        time.Duration
+}
+
+// NewOAuth2ClientService creates a new OAuth2 client service instance.
+// secretCipher may be nil, in which case clients cannot register for client_secret_jwt, and
+// subjects may be nil, in which case clients cannot register for pairwise subject identifiers.
+// signingAlgs lists the algorithms the server signs with (auth.signing_algs);
+// empty means RS256 only.
+func NewOAuth2ClientService(db *sql.DB, clientRepo repository.OAuth2ClientRepository, auditor *auditService.Auditor, logger *zap.Logger, secretCipher *ClientSecretCipher, subjects *SubjectIdentifierService, signingAlgs []string, lifetimeLimits ClientLifetimeLimits) OAuth2ClientService {
+	if len(signingAlgs) == 0 {
+		signingAlgs = []string{domain.DefaultIDTokenSigningAlg}
+	}
 	return &oauth2ClientServiceImpl{
-		db:         db,
-		clientRepo: clientRepo,
-		auditor:    auditor,
-		logger:     utility.EnsureLogger(logger),
+		db:             db,
+		clientRepo:     clientRepo,
+		auditor:        auditor,
+		logger:         utility.EnsureLogger(logger),
+		secretCipher:   secretCipher,
+		subjects:       subjects,
+		signingAlgs:    signingAlgs,
+		lifetimeLimits: lifetimeLimits,
 	}
 }
 
@@ -87,6 +177,30 @@ func (s *oauth2ClientServiceImpl) RegisterClient(ctx context.Context, req *Regis
 			return nil, "", validationErr
 		}
 	}
+	if validationErr := validatePushedAuthorizationRequirement(req.RequirePushedAuthorizationRequests, req.IsConfidential); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := validateEncryptedResponse("id_token", req.IDTokenEncryptedResponseAlg, req.IDTokenEncryptedResponseEnc); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := validateEncryptedResponse("userinfo", req.UserinfoEncryptedResponseAlg, req.UserinfoEncryptedResponseEnc); validationErr != nil {
+		return nil, "", validationErr
+	}
+	encryptsResponses := req.IDTokenEncryptedResponseAlg != "" || req.UserinfoEncryptedResponseAlg != ""
+	if validationErr := validateTokenEndpointAuthMethod(req.TokenEndpointAuthMethod, req.IsConfidential, req.JWKS, req.JWKSURI, encryptsResponses); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if req.TokenEndpointAuthMethod == domain.AuthMethodClientSecretJWT && s.secretCipher == nil {
+		return nil, "", &ValidationError{Message: "client_secret_jwt is not enabled on this server"}
+	}
+	if len(req.TokenExchangeAudiences) > 0 {
+		if !req.AllowTokenExchangePolicy {
+			return nil, "", &ValidationError{Message: "token_exchange_audiences can only be set by an administrator"}
+		}
+		if validationErr := validateTokenExchangeAudiences(req.TokenExchangeAudiences, req.IsConfidential); validationErr != nil {
+			return nil, "", validationErr
+		}
+	}
 
 	var secretPlaintext string
 	var secretHash string
@@ -135,6 +249,78 @@ func (s *oauth2ClientServiceImpl) RegisterClient(ctx context.Context, req *Regis
 	client.Scopes = scopes
 	client.IsConfidential = req.IsConfidential
 	client.Metadata = metadata
+	client.RequirePushedAuthorizationRequests = req.RequirePushedAuthorizationRequests
+	client.TokenEndpointAuthMethod = req.TokenEndpointAuthMethod
+	client.JWKS = req.JWKS
+	client.JWKSURI = req.JWKSURI
+	client.TokenExchangeAudiences = req.TokenExchangeAudiences
+	client.DPoPBoundAccessTokens = req.DPoPBoundAccessTokens
+	client.TLSClientAuthSubjectDN = req.TLSClientAuthSubjectDN
+	client.TLSClientAuthSANDNS = req.TLSClientAuthSANDNS
+	client.TLSClientAuthSANURI = req.TLSClientAuthSANURI
+	client.TLSClientAuthSANIP = req.TLSClientAuthSANIP
+	client.TLSClientAuthSANEmail = req.TLSClientAuthSANEmail
+	client.TLSClientCertificateBoundAccessTokens = req.TLSClientCertificateBoundAccessTokens
+	client.RegistrationAccessTokenHash = req.RegistrationAccessTokenHash
+	client.RequireSignedRequestObject = req.RequireSignedRequestObject
+	client.RequestURIs = req.RequestURIs
+	client.AuthorizationDetailsTypes = req.AuthorizationDetailsTypes
+	client.BackchannelTokenDeliveryMode = req.BackchannelTokenDeliveryMode
+	client.BackchannelClientNotificationEndpoint = req.BackchannelClientNotificationEndpoint
+	client.SubjectType = req.SubjectType
+	client.SectorIdentifierURI = req.SectorIdentifierURI
+	client.IDTokenSignedResponseAlg = req.IDTokenSignedResponseAlg
+	client.AccessTokenProfile = req.AccessTokenProfile
+	client.IDTokenEncryptedResponseAlg = req.IDTokenEncryptedResponseAlg
+	client.IDTokenEncryptedResponseEnc = req.IDTokenEncryptedResponseEnc
+	client.UserinfoEncryptedResponseAlg = req.UserinfoEncryptedResponseAlg
+	client.UserinfoEncryptedResponseEnc = req.UserinfoEncryptedResponseEnc
+	client.UserinfoSignedResponseAlg = req.UserinfoSignedResponseAlg
+	client.RefreshTokenIdleLifetime = req.RefreshTokenIdleLifetime
+	client.RefreshTokenAbsoluteLifetime = req.RefreshTokenAbsoluteLifetime
+	client.RefreshTokenExpiration = req.RefreshTokenExpiration
+	client.AccessTokenLifetime = req.AccessTokenLifetime
+	client.IDTokenLifetime = req.IDTokenLifetime
+	client.AuthorizationCodeLifetime = req.AuthorizationCodeLifetime
+	client.DeviceCodeLifetime = req.DeviceCodeLifetime
+	defaultEncryptedResponseEncs(client)
+	if validationErr := validateTLSClientAuthSubject(client); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := validateSignedRequestObjectRequirement(client); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := validateRequestURIs(client.RequestURIs); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := validateAuthorizationDetailsTypes(client.AuthorizationDetailsTypes); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := validateBackchannelAuthentication(client); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := s.validateSigningAlg("id_token_signed_response_alg", client.IDTokenSignedResponseAlg); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := s.validateSigningAlg("userinfo_signed_response_alg", client.UserinfoSignedResponseAlg); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := validateAccessTokenProfile(client.AccessTokenProfile); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := s.validateLifetimes(client); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if validationErr := s.subjects.ValidateClient(ctx, client); validationErr != nil {
+		return nil, "", validationErr
+	}
+	if client.TokenEndpointAuthMethod == domain.AuthMethodClientSecretJWT {
+		// HMAC assertions are keyed with the secret itself, so keep a recoverable copy.
+		client.ClientSecretEncrypted, err = s.secretCipher.Encrypt(client.ClientID, secretPlaintext)
+		if err != nil {
+			return nil, "", fmt.Errorf("encrypt client secret: %w", err)
+		}
+	}
 
 	err = dbutil.RunInTransaction(ctx, s.db, func(tx *sql.Tx) error {
 		return s.clientRepo.Create(ctx, tx, client)
@@ -143,11 +329,15 @@ func (s *oauth2ClientServiceImpl) RegisterClient(ctx context.Context, req *Regis
 		return nil, "", fmt.Errorf("register client: %w", err)
 	}
 
+	auditMetadata := map[string]any{"client_id": client.ClientID, "name": client.Name}
+	if client.RegistrationAccessTokenHash != "" {
+		auditMetadata["dynamic_registration"] = true
+	}
 	auditService.AuditLog(ctx, s.auditor, s.logger, auditDomain.NewRecord(
 		auditDomain.ActionOAuth2ClientRegister,
 		audit.IPFromContext(ctx),
 		&req.AccountID,
-		utility.MarshalJSONOrEmpty(map[string]any{"client_id": client.ClientID, "name": client.Name}),
+		utility.MarshalJSONOrEmpty(auditMetadata),
 		nil,
 	))
 
@@ -176,13 +366,47 @@ func (s *oauth2ClientServiceImpl) UpdateClient(ctx context.Context, client *doma
 
 // UpdateClientRequest contains the fields that can be updated on an OAuth2 client.
 type UpdateClientRequest struct {
-	Name                   *string  `json:"name"`
-	Description            *string  `json:"description"`
-	RedirectURIs           []string `json:"redirect_uris"`
-	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris"`
-	GrantTypes             []string `json:"grant_types"`
-	Scopes                 []string `json:"scopes"`
-	AllowReservedScopes    bool     `json:"-"`
+	Name                                  *string         `json:"name"`
+	Description                           *string         `json:"description"`
+	RedirectURIs                          []string        `json:"redirect_uris"`
+	PostLogoutRedirectURIs                []string        `json:"post_logout_redirect_uris"`
+	GrantTypes                            []string        `json:"grant_types"`
+	Scopes                                []string        `json:"scopes"`
+	RequirePushedAuthorizationRequests    *bool           `json:"require_pushed_authorization_requests"`
+	TokenEndpointAuthMethod               *string         `json:"token_endpoint_auth_method"`
+	JWKS                                  json.RawMessage `json:"jwks"`
+	JWKSURI                               *string         `json:"jwks_uri"`
+	TokenExchangeAudiences                []string        `json:"token_exchange_audiences"`
+	DPoPBoundAccessTokens                 *bool           `json:"dpop_bound_access_tokens"`
+	TLSClientAuthSubjectDN                *string         `json:"tls_client_auth_subject_dn"`
+	TLSClientAuthSANDNS                   *string         `json:"tls_client_auth_san_dns"`
+	TLSClientAuthSANURI                   *string         `json:"tls_client_auth_san_uri"`
+	TLSClientAuthSANIP                    *string         `json:"tls_client_auth_san_ip"`
+	TLSClientAuthSANEmail                 *string         `json:"tls_client_auth_san_email"`
+	TLSClientCertificateBoundAccessTokens *bool           `json:"tls_client_certificate_bound_access_tokens"`
+	RequireSignedRequestObject            *bool           `json:"require_signed_request_object"`
+	RequestURIs                           []string        `json:"request_uris"`
+	AuthorizationDetailsTypes             []string        `json:"authorization_details_types"`
+	BackchannelTokenDeliveryMode          *string         `json:"backchannel_token_delivery_mode"`
+	BackchannelClientNotificationEndpoint *string         `json:"backchannel_client_notification_endpoint"`
+	SubjectType                           *string         `json:"subject_type"`
+	SectorIdentifierURI                   *string         `json:"sector_identifier_uri"`
+	IDTokenSignedResponseAlg              *string         `json:"id_token_signed_response_alg"`
+	AccessTokenProfile                    *string         `json:"access_token_profile"`
+	IDTokenEncryptedResponseAlg           *string         `json:"id_token_encrypted_response_alg"`
+	IDTokenEncryptedResponseEnc           *string         `json:"id_token_encrypted_response_enc"`
+	UserinfoEncryptedResponseAlg          *string         `json:"userinfo_encrypted_response_alg"`
+	UserinfoEncryptedResponseEnc          *string         `json:"userinfo_encrypted_response_enc"`
+	UserinfoSignedResponseAlg             *string         `json:"userinfo_signed_response_alg"`
+	RefreshTokenIdleLifetime              *int            `json:"refresh_token_idle_lifetime"`
+	RefreshTokenAbsoluteLifetime          *int            `json:"refresh_token_absolute_lifetime"`
+	RefreshTokenExpiration                *string         `json:"refresh_token_expiration"`
+	AccessTokenLifetime                   *int            `json:"access_token_lifetime"`
+	IDTokenLifetime                       *int            `json:"id_token_lifetime"`
+	AuthorizationCodeLifetime             *int            `json:"authorization_code_lifetime"`
+	DeviceCodeLifetime                    *int            `json:"device_code_lifetime"`
+	AllowReservedScopes                   bool            `json:"-"`
+	AllowTokenExchangePolicy              bool            `json:"-"`
 }
 
 // UpdateClientByAccountID loads a client by ID, verifies ownership, applies partial updates with
@@ -217,6 +441,24 @@ func (s *oauth2ClientServiceImpl) UpdateClientByAccountID(ctx context.Context, a
 			return nil, err
 		}
 	}
+	if req.TokenExchangeAudiences != nil && !req.AllowTokenExchangePolicy {
+		return nil, &ValidationError{Message: "token_exchange_audiences can only be set by an administrator"}
+	}
+	if req.IDTokenSignedResponseAlg != nil {
+		if err := s.validateSigningAlg("id_token_signed_response_alg", *req.IDTokenSignedResponseAlg); err != nil {
+			return nil, err
+		}
+	}
+	if req.UserinfoSignedResponseAlg != nil {
+		if err := s.validateSigningAlg("userinfo_signed_response_alg", *req.UserinfoSignedResponseAlg); err != nil {
+			return nil, err
+		}
+	}
+	if req.AccessTokenProfile != nil {
+		if err := validateAccessTokenProfile(*req.AccessTokenProfile); err != nil {
+			return nil, err
+		}
+	}
 
 	var client *domain.OAuth2Client
 	err := dbutil.RunInTransaction(ctx, s.db, func(tx *sql.Tx) error {
@@ -257,6 +499,134 @@ func (s *oauth2ClientServiceImpl) UpdateClientByAccountID(ctx context.Context, a
 			c.Scopes = req.Scopes
 			c.Metadata = syncAdminCapability(c.Metadata, c.Scopes)
 		}
+		if req.RequirePushedAuthorizationRequests != nil {
+			c.RequirePushedAuthorizationRequests = *req.RequirePushedAuthorizationRequests
+		}
+		if err := validatePushedAuthorizationRequirement(c.RequirePushedAuthorizationRequests, c.IsConfidential); err != nil {
+			return err
+		}
+		if req.TokenEndpointAuthMethod != nil {
+			c.TokenEndpointAuthMethod = *req.TokenEndpointAuthMethod
+		}
+		if err := applyEncryptedResponses(c, req); err != nil {
+			return err
+		}
+		// jwks and jwks_uri are mutually exclusive, so setting one replaces the other.
+		if req.JWKS != nil {
+			c.JWKS, c.JWKSURI = req.JWKS, ""
+		}
+		if req.JWKSURI != nil {
+			c.JWKSURI = *req.JWKSURI
+			if req.JWKS == nil {
+				c.JWKS = nil
+			}
+		}
+		keyUseChanged := req.TokenEndpointAuthMethod != nil || req.IDTokenEncryptedResponseAlg != nil || req.UserinfoEncryptedResponseAlg != nil
+		if keyUseChanged && !usesClientKeys(c.TokenEndpointAuthMethod) && !c.EncryptsResponses() && req.JWKS == nil && req.JWKSURI == nil {
+			// Keys are only meaningful for private_key_jwt, self-signed certificates and
+			// encrypted responses; drop them when switching away.
+			c.JWKS, c.JWKSURI = nil, ""
+		}
+		if c.TokenEndpointAuthMethod != domain.AuthMethodClientSecretJWT {
+			// Don't keep a recoverable secret around for clients that no longer need it.
+			c.ClientSecretEncrypted = ""
+		}
+		if err := validateTokenEndpointAuthMethod(c.TokenEndpointAuthMethod, c.IsConfidential, c.JWKS, c.JWKSURI, c.EncryptsResponses()); err != nil {
+			return err
+		}
+		// The plaintext secret is only available at registration, so a client can only
+		// switch to client_secret_jwt if it was registered with it.
+		if c.TokenEndpointAuthMethod == domain.AuthMethodClientSecretJWT && c.ClientSecretEncrypted == "" {
+			return &ValidationError{Message: "token_endpoint_auth_method client_secret_jwt must be chosen at registration"}
+		}
+		if req.TokenExchangeAudiences != nil {
+			if err := validateTokenExchangeAudiences(req.TokenExchangeAudiences, c.IsConfidential); err != nil {
+				return err
+			}
+			c.TokenExchangeAudiences = req.TokenExchangeAudiences
+		}
+		if req.DPoPBoundAccessTokens != nil {
+			c.DPoPBoundAccessTokens = *req.DPoPBoundAccessTokens
+		}
+		applyTLSClientAuthSubject(c, req)
+		if err := validateTLSClientAuthSubject(c); err != nil {
+			return err
+		}
+		if req.TLSClientCertificateBoundAccessTokens != nil {
+			c.TLSClientCertificateBoundAccessTokens = *req.TLSClientCertificateBoundAccessTokens
+		}
+		if req.RequireSignedRequestObject != nil {
+			c.RequireSignedRequestObject = *req.RequireSignedRequestObject
+		}
+		if err := validateSignedRequestObjectRequirement(c); err != nil {
+			return err
+		}
+		if req.RequestURIs != nil {
+			if err := validateRequestURIs(req.RequestURIs); err != nil {
+				return err
+			}
+			c.RequestURIs = req.RequestURIs
+		}
+		if req.AuthorizationDetailsTypes != nil {
+			if err := validateAuthorizationDetailsTypes(req.AuthorizationDetailsTypes); err != nil {
+				return err
+			}
+			c.AuthorizationDetailsTypes = req.AuthorizationDetailsTypes
+		}
+		if req.BackchannelTokenDeliveryMode != nil {
+			c.BackchannelTokenDeliveryMode = *req.BackchannelTokenDeliveryMode
+		}
+		if req.BackchannelClientNotificationEndpoint != nil {
+			c.BackchannelClientNotificationEndpoint = *req.BackchannelClientNotificationEndpoint
+		}
+		if err := validateBackchannelAuthentication(c); err != nil {
+			return err
+		}
+		if req.SubjectType != nil {
+			c.SubjectType = *req.SubjectType
+		}
+		if req.SectorIdentifierURI != nil {
+			c.SectorIdentifierURI = *req.SectorIdentifierURI
+		}
+		if req.IDTokenSignedResponseAlg != nil {
+			c.IDTokenSignedResponseAlg = *req.IDTokenSignedResponseAlg
+		}
+		if req.UserinfoSignedResponseAlg != nil {
+			c.UserinfoSignedResponseAlg = *req.UserinfoSignedResponseAlg
+		}
+		if req.AccessTokenProfile != nil {
+			c.AccessTokenProfile = *req.AccessTokenProfile
+		}
+		if req.RefreshTokenIdleLifetime != nil {
+			c.RefreshTokenIdleLifetime = *req.RefreshTokenIdleLifetime
+		}
+		if req.RefreshTokenAbsoluteLifetime != nil {
+			c.RefreshTokenAbsoluteLifetime = *req.RefreshTokenAbsoluteLifetime
+		}
+		if req.RefreshTokenExpiration != nil {
+			c.RefreshTokenExpiration = *req.RefreshTokenExpiration
+		}
+		if req.AccessTokenLifetime != nil {
+			c.AccessTokenLifetime = *req.AccessTokenLifetime
+		}
+		if req.IDTokenLifetime != nil {
+			c.IDTokenLifetime = *req.IDTokenLifetime
+		}
+		if req.AuthorizationCodeLifetime != nil {
+			c.AuthorizationCodeLifetime = *req.AuthorizationCodeLifetime
+		}
+		if req.DeviceCodeLifetime != nil {
+			c.DeviceCodeLifetime = *req.DeviceCodeLifetime
+		}
+		if err := s.validateLifetimes(c); err != nil {
+			return err
+		}
+		// The sector_identifier_uri document is only refetched when something it vouches for changes.
+		if req.SubjectType != nil || req.SectorIdentifierURI != nil || req.RedirectURIs != nil {
+			if err := s.subjects.ValidateClient(ctx, c); err != nil {
+				return err
+			}
+		}
 
 		expectedUpdatedAt := c.UpdatedAt
 		c.UpdatedAt = time.Now()
@@ -281,11 +651,45 @@ func (s *oauth2ClientServiceImpl) UpdateClientByAccountID(ctx context.Context, a
 	return client, nil
 }
 
+// applyTLSClientAuthSubject applies the certificate subject fields of req to c. The
+// subject values are alternatives, so setting one clears the others, and switching to
+// another token_endpoint_auth_method clears them all.
+func applyTLSClientAuthSubject(c *domain.OAuth2Client, req *UpdateClientRequest) {
+	updates := []struct {
+		value *string
+		field *string
+	}{
+		{req.TLSClientAuthSubjectDN, &c.TLSClientAuthSubjectDN},
+		{req.TLSClientAuthSANDNS, &c.TLSClientAuthSANDNS},
+		{req.TLSClientAuthSANURI, &c.TLSClientAuthSANURI},
+		{req.TLSClientAuthSANIP, &c.TLSClientAuthSANIP},
+		{req.TLSClientAuthSANEmail, &c.TLSClientAuthSANEmail},
+	}
+	replace := req.TokenEndpointAuthMethod != nil && c.TokenEndpointAuthMethod != domain.AuthMethodTLSClientAuth
+	for _, u := range updates {
+		if u.value != nil {
+			replace = true
+		}
+	}
+	if !replace {
+		return
+	}
+	for _, u := range updates {
+		*u.field = ""
+		if u.value != nil {
+			*u.field = *u.value
+		}
+	}
+}
+
 var validGrantTypes = []string{
 	domain.GrantTypeAuthorizationCode,
 	domain.GrantTypeRefreshToken,
 	domain.GrantTypeClientCredentials,
 	domain.GrantTypeDeviceCode,
+	domain.GrantTypeTokenExchange,
+	domain.GrantTypeJWTBearer,
+	domain.GrantTypeCIBA,
 }
 
 // validateClientName validates a client name. If required is true, empty names are rejected.
@@ -315,6 +719,335 @@ func validateClientDescription(desc string) error {
 	return nil
 }
 
+// validatePushedAuthorizationRequirement rejects require_pushed_authorization_requests on
+// public clients: the PAR endpoint only accepts authenticated confidential clients, so the
+// flag would make the client unable to start any authorization request.
+func validatePushedAuthorizationRequirement(required, isConfidential bool) error {
+	if required && !isConfidential {
+		return &ValidationError{Message: "require_pushed_authorization_requests is only supported for confidential clients"}
+	}
+	return nil
+}
+
+// validateSignedRequestObjectRequirement rejects require_signed_request_object on clients
+// without a key to sign request objects with: registered jwks / jwks_uri, or the
+// recoverable secret of a client_secret_jwt client.
+func validateSignedRequestObjectRequirement(c *domain.OAuth2Client) error {
+	if c.RequireSignedRequestObject && len(c.JWKS) == 0 && c.JWKSURI == "" && c.TokenEndpointAuthMethod != domain.AuthMethodClientSecretJWT {
+		return &ValidationError{Message: "require_signed_request_object requires jwks, jwks_uri or token_endpoint_auth_method client_secret_jwt"}
+	}
+	return nil
+}
+
+const (
+	maxRequestURIs      = 10
+	maxRequestURILength = 2048
+)
+
+// validateRequestURIs checks the URLs a client serves request objects from (RFC 9101 §5.2).
+// They must be https, since the request object is fetched from them by the server.
+func validateRequestURIs(uris []string) error {
+	if len(uris) > maxRequestURIs {
+		return &ValidationError{Message: fmt.Sprintf("too many request_uris (max %d)", maxRequestURIs)}
+	}
+	for _, raw := range uris {
+		if len(raw) > maxRequestURILength {
+			return &ValidationError{Message: fmt.Sprintf("request_uri exceeds maximum length of %d characters", maxRequestURILength)}
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil || u.Fragment != "" {
+			return &ValidationError{Message: fmt.Sprintf("invalid request_uri: %q (must be an https URL without fragment)", raw)}
+		}
+	}
+	return nil
+}
+
+const (
+	maxAuthorizationDetailsTypes      = 20
+	maxAuthorizationDetailsTypeLength = 128
+)
+
+// validateAuthorizationDetailsTypes checks the authorization details types a client may
+// request (RFC 9396 §10.1).
+func validateAuthorizationDetailsTypes(types []string) error {
+	if len(types) > maxAuthorizationDetailsTypes {
+		return &ValidationError{Message: fmt.Sprintf("too many authorization_details_types (max %d)", maxAuthorizationDetailsTypes)}
+	}
+	for _, t := range types {
+		if t == "" || len(t) > maxAuthorizationDetailsTypeLength || strings.TrimSpace(t) != t {
+			return &ValidationError{Message: fmt.Sprintf("invalid authorization_details_type: %q", t)}
+		}
+	}
+	return nil
+}
+
+// validateBackchannelAuthentication checks the CIBA settings of a client. The
+// backchannel authentication endpoint only serves confidential clients, and ping and push
+// clients need an https endpoint to be notified at (CIBA Core §4).
+func validateBackchannelAuthentication(c *domain.OAuth2Client) error {
+	if c.HasGrantType(domain.GrantTypeCIBA) && !c.IsConfidential {
+		return &ValidationError{Message: "grant type " + domain.GrantTypeCIBA + " is only supported for confidential clients"}
+	}
+	mode := c.BackchannelTokenDeliveryMode
+	if mode != "" && !domain.IsValidBackchannelTokenDeliveryMode(mode) {
+		return &ValidationError{Message: fmt.Sprintf("invalid backchannel_token_delivery_mode: %q", mode)}
+	}
+	endpoint := c.BackchannelClientNotificationEndpoint
+	if endpoint == "" {
+		if mode == domain.BackchannelTokenDeliveryPing || mode == domain.BackchannelTokenDeliveryPush {
+			return &ValidationError{Message: fmt.Sprintf("backchannel_token_delivery_mode %s requires backchannel_client_notification_endpoint", mode)}
+		}
+		return nil
+	}
+	if len(endpoint) > maxRequestURILength {
+		return &ValidationError{Message: fmt.Sprintf("backchannel_client_notification_endpoint exceeds maximum length of %d characters", maxRequestURILength)}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil || u.Fragment != "" {
+		return &ValidationError{Message: "invalid backchannel_client_notification_endpoint (must be an https URL without fragment)"}
+	}
+	return nil
+}
+
+// validateSigningAlg checks the signing algorithm metadata field name against the
+// algorithms the server signs with. Unsigned responses ("none") are never issued.
+func (s *oauth2ClientServiceImpl) validateSigningAlg(name, alg string) error {
+	if alg != "" && !slices.Contains(s.signingAlgs, alg) {
+		return &ValidationError{Message: fmt.Sprintf("unsupported %s: %q (supported: %s)", name, alg, strings.Join(s.signingAlgs, ", "))}
+	}
+	return nil
+}
+
+// validateAccessTokenProfile checks access_token_profile.
+func validateAccessTokenProfile(profile string) error {
+	if !domain.IsValidAccessTokenProfile(profile) {
+		return &ValidationError{Message: fmt.Sprintf("invalid access_token_profile: %q (supported: %s, %s)", profile, domain.AccessTokenProfileRFC9068, domain.AccessTokenProfileReference)}
+	}
+	return nil
+}
+
+// validateLifetimes checks the token and code lifetimes of a client against the server's
+// ceilings, and its refresh token expiration mode.
+func (s *oauth2ClientServiceImpl) validateLifetimes(c *domain.OAuth2Client) error {
+	lifetimes := []struct {
+		name    string
+		seconds int
+		limit   time.Duration
+	}{
+		{"access_token_lifetime", c.AccessTokenLifetime, s.lifetimeLimits.AccessToken},
+		{"id_token_lifetime", c.IDTokenLifetime, s.lifetimeLimits.IDToken},
+		{"refresh_token_idle_lifetime", c.RefreshTokenIdleLifetime, s.lifetimeLimits.RefreshToken},
+		{"refresh_token_absolute_lifetime", c.RefreshTokenAbsoluteLifetime, 0},
+		{"authorization_code_lifetime", c.AuthorizationCodeLifetime, s.lifetimeLimits.AuthorizationCode},
+		{"device_code_lifetime", c.DeviceCodeLifetime, s.lifetimeLimits.DeviceCode},
+	}
+	for _, lifetime := range lifetimes {
+		if lifetime.seconds < 0 {
+			return &ValidationError{Message: fmt.Sprintf("%s must not be negative", lifetime.name)}
+		}
+		if lifetime.limit > 0 && time.Duration(lifetime.seconds)*time.Second > lifetime.limit {
+			return &ValidationError{Message: fmt.Sprintf("%s must not exceed %d seconds", lifetime.name, int(lifetime.limit.Seconds()))}
+		}
+	}
+	if !domain.IsValidRefreshTokenExpiration(c.RefreshTokenExpiration) {
+		return &ValidationError{Message: fmt.Sprintf("invalid refresh_token_expiration: %q (supported: %s, %s)", c.RefreshTokenExpiration, domain.RefreshTokenExpirationSliding, domain.RefreshTokenExpirationFixed)}
+	}
+	return nil
+}
+
+// validateEncryptedResponse checks the <name>_encrypted_response_alg and _enc pair of a
+// client (OpenID Connect Registration §2): enc requires alg.
+func validateEncryptedResponse(name, alg, enc string) error {
+	if alg == "" {
+		if enc != "" {
+			return &ValidationError{Message: fmt.Sprintf("%s_encrypted_response_enc requires %s_encrypted_response_alg", name, name)}
+		}
+		return nil
+	}
+	if !slices.Contains(jose.KeyEncryptionAlgs, alg) {
+		return &ValidationError{Message: fmt.Sprintf("unsupported %s_encrypted_response_alg: %q (supported: %s)", name, alg, strings.Join(jose.KeyEncryptionAlgs, ", "))}
+	}
+	if enc != "" && !slices.Contains(jose.ContentEncryptionAlgs, enc) {
+		return &ValidationError{Message: fmt.Sprintf("unsupported %s_encrypted_response_enc: %q (supported: %s)", name, enc, strings.Join(jose.ContentEncryptionAlgs, ", "))}
+	}
+	return nil
+}
+
+// applyEncryptedResponses applies and validates the encryption settings of req to c.
+func applyEncryptedResponses(c *domain.OAuth2Client, req *UpdateClientRequest) error {
+	if req.IDTokenEncryptedResponseAlg != nil {
+		c.IDTokenEncryptedResponseAlg = *req.IDTokenEncryptedResponseAlg
+		c.IDTokenEncryptedResponseEnc = ""
+	}
+	if req.IDTokenEncryptedResponseEnc != nil {
+		c.IDTokenEncryptedResponseEnc = *req.IDTokenEncryptedResponseEnc
+	}
+	if req.UserinfoEncryptedResponseAlg != nil {
+		c.UserinfoEncryptedResponseAlg = *req.UserinfoEncryptedResponseAlg
+		c.UserinfoEncryptedResponseEnc = ""
+	}
+	if req.UserinfoEncryptedResponseEnc != nil {
+		c.UserinfoEncryptedResponseEnc = *req.UserinfoEncryptedResponseEnc
+	}
+	if err := validateEncryptedResponse("id_token", c.IDTokenEncryptedResponseAlg, c.IDTokenEncryptedResponseEnc); err != nil {
+		return err
+	}
+	if err := validateEncryptedResponse("userinfo", c.UserinfoEncryptedResponseAlg, c.UserinfoEncryptedResponseEnc); err != nil {
+		return err
+	}
+	defaultEncryptedResponseEncs(c)
+	return nil
+}
+
+// defaultEncryptedResponseEncs fills in A128CBC-HS256 for encrypted responses registered
+// without a content encryption algorithm.
+func defaultEncryptedResponseEncs(c *domain.OAuth2Client) {
+	if c.IDTokenEncryptedResponseAlg != "" && c.IDTokenEncryptedResponseEnc == "" {
+		c.IDTokenEncryptedResponseEnc = domain.DefaultEncryptedResponseEnc
+	}
+	if c.UserinfoEncryptedResponseAlg != "" && c.UserinfoEncryptedResponseEnc == "" {
+		c.UserinfoEncryptedResponseEnc = domain.DefaultEncryptedResponseEnc
+	}
+}
+
+// maxClientJWKSSize bounds the inline jwks document stored for a client.
+const maxClientJWKSSize = 16 * 1024
+
+// validateTokenEndpointAuthMethod checks token_endpoint_auth_method against the client type
+// and, for private_key_jwt and clients with encrypted responses, requires exactly one of
+// jwks or jwks_uri.
+func validateTokenEndpointAuthMethod(method string, isConfidential bool, jwks json.RawMessage, jwksURI string, encryptsResponses bool) error {
+	switch method {
+	case "":
+	case domain.AuthMethodNone:
+		if isConfidential {
+			return &ValidationError{Message: "token_endpoint_auth_method none is only supported for public clients"}
+		}
+	case domain.AuthMethodClientSecretBasic, domain.AuthMethodClientSecretPost,
+		domain.AuthMethodClientSecretJWT, domain.AuthMethodPrivateKeyJWT,
+		domain.AuthMethodTLSClientAuth, domain.AuthMethodSelfSignedTLSClientAuth:
+		if !isConfidential {
+			return &ValidationError{Message: fmt.Sprintf("token_endpoint_auth_method %s is only supported for confidential clients", method)}
+		}
+	default:
+		return &ValidationError{Message: fmt.Sprintf("invalid token_endpoint_auth_method: %q", method)}
+	}
+
+	usesKeys := usesClientKeys(method)
+	if !usesKeys && !encryptsResponses {
+		if len(jwks) > 0 || jwksURI != "" {
+			return &ValidationError{Message: "jwks and jwks_uri are only supported with token_endpoint_auth_method private_key_jwt or self_signed_tls_client_auth, or with encrypted responses"}
+		}
+		return nil
+	}
+	if (len(jwks) == 0) == (jwksURI == "") {
+		if !usesKeys {
+			return &ValidationError{Message: "encrypted responses require exactly one of jwks or jwks_uri"}
+		}
+		return &ValidationError{Message: fmt.Sprintf("%s requires exactly one of jwks or jwks_uri", method)}
+	}
+	if len(jwks) > 0 {
+		if len(jwks) > maxClientJWKSSize {
+			return &ValidationError{Message: fmt.Sprintf("jwks must not exceed %d bytes", maxClientJWKSSize)}
+		}
+		if usesKeys {
+			if _, err := parseClientJWKS(jwks); err != nil {
+				return &ValidationError{Message: fmt.Sprintf("invalid jwks: %v", err)}
+			}
+		}
+		if encryptsResponses {
+			if _, err := clientEncryptionKey(jwks); err != nil {
+				return &ValidationError{Message: fmt.Sprintf("invalid jwks: %v", err)}
+			}
+		}
+		return nil
+	}
+	u, err := url.Parse(jwksURI)
+	if err != nil || u.Host == "" || u.Fragment != "" || u.Scheme != "https" {
+		return &ValidationError{Message: fmt.Sprintf("jwks_uri must be an https URL without fragment: %s", jwksURI)}
+	}
+	return nil
+}
+
+// usesClientKeys reports whether clients using method register public keys through jwks
+// or jwks_uri: private_key_jwt assertion keys or self-signed TLS certificates (RFC 8705 §2.2).
+func usesClientKeys(method string) bool {
+	return method == domain.AuthMethodPrivateKeyJWT || method == domain.AuthMethodSelfSignedTLSClientAuth
+}
+
+// maxTLSClientAuthSubjectLength bounds the registered certificate subject value.
+const maxTLSClientAuthSubjectLength = 512
+
+// validateTLSClientAuthSubject checks the expected certificate subject of a client. A
+// tls_client_auth client registers exactly one of tls_client_auth_subject_dn or a
+// tls_client_auth_san_* value (RFC 8705 §2.1.2); other clients register none.
+func validateTLSClientAuthSubject(c *domain.OAuth2Client) error {
+	fields := []struct{ name, value string }{
+		{"tls_client_auth_subject_dn", c.TLSClientAuthSubjectDN},
+		{"tls_client_auth_san_dns", c.TLSClientAuthSANDNS},
+		{"tls_client_auth_san_uri", c.TLSClientAuthSANURI},
+		{"tls_client_auth_san_ip", c.TLSClientAuthSANIP},
+		{"tls_client_auth_san_email", c.TLSClientAuthSANEmail},
+	}
+	set := 0
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		set++
+		if len(f.value) > maxTLSClientAuthSubjectLength {
+			return &ValidationError{Message: fmt.Sprintf("%s must not exceed %d characters", f.name, maxTLSClientAuthSubjectLength)}
+		}
+	}
+	if c.TokenEndpointAuthMethod != domain.AuthMethodTLSClientAuth {
+		if set > 0 {
+			return &ValidationError{Message: "tls_client_auth_* subject values are only supported with token_endpoint_auth_method tls_client_auth"}
+		}
+		return nil
+	}
+	if set != 1 {
+		return &ValidationError{Message: "tls_client_auth requires exactly one of tls_client_auth_subject_dn or tls_client_auth_san_*"}
+	}
+	if c.TLSClientAuthSANURI != "" {
+		if u, err := url.Parse(c.TLSClientAuthSANURI); err != nil || !u.IsAbs() {
+			return &ValidationError{Message: "tls_client_auth_san_uri must be an absolute URI"}
+		}
+	}
+	if c.TLSClientAuthSANIP != "" && net.ParseIP(c.TLSClientAuthSANIP) == nil {
+		return &ValidationError{Message: "tls_client_auth_san_ip must be an IP address"}
+	}
+	return nil
+}
+
+const (
+	maxTokenExchangeAudiences      = 20
+	maxTokenExchangeAudienceLength = 512
+)
+
+// validateTokenExchangeAudiences checks the audience / resource values a client may request
+// through token exchange. Only confidential clients can exchange tokens, since the token
+// endpoint must authenticate the party acting on the subject's behalf (RFC 8693 §2.1).
+func validateTokenExchangeAudiences(audiences []string, isConfidential bool) error {
+	if len(audiences) == 0 {
+		return nil
+	}
+	if !isConfidential {
+		return &ValidationError{Message: "token_exchange_audiences is only supported for confidential clients"}
+	}
+	if len(audiences) > maxTokenExchangeAudiences {
+		return &ValidationError{Message: fmt.Sprintf("too many token_exchange_audiences (max %d)", maxTokenExchangeAudiences)}
+	}
+	for _, aud := range audiences {
+		if aud == "" || strings.ContainsAny(aud, " \t\r\n") {
+			return &ValidationError{Message: fmt.Sprintf("invalid token exchange audience: %q", aud)}
+		}
+		if len(aud) > maxTokenExchangeAudienceLength {
+			return &ValidationError{Message: fmt.Sprintf("token exchange audience exceeds maximum length of %d characters", maxTokenExchangeAudienceLength)}
+		}
+	}
+	return nil
+}
+
 func validateGrantTypes(types []string) error {
 	for _, gt := range types {
 		found := false
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +27,18 @@ func clientTestColumns() []string {
 		"is_confidential", "metadata",
 		"frontchannel_logout_uri", "frontchannel_logout_session_required",
 		"backchannel_logout_uri", "backchannel_logout_session_required",
+		"require_pushed_authorization_requests",
+		"token_endpoint_auth_method", "jwks", "jwks_uri", "client_secret_encrypted",
+		"token_exchange_audiences", "dpop_bound_access_tokens",
+		"tls_client_auth_subject_dn", "tls_client_auth_san_dns", "tls_client_auth_san_uri",
+		"tls_client_auth_san_ip", "tls_client_auth_san_email",
+		"tls_client_certificate_bound_access_tokens", "registration_access_token_hash",
+		"require_signed_request_object", "request_uris", "authorization_details_types",
+		"backchannel_token_delivery_mode", "backchannel_client_notification_endpoint",
+		"subject_type", "sector_identifier_uri", "id_token_signed_response_alg", "access_token_profile", "id_token_encrypted_response_alg", "id_token_encrypted_response_enc",
+		"userinfo_encrypted_response_alg", "userinfo_encrypted_response_enc", "userinfo_signed_response_alg",
+		"refresh_token_idle_lifetime", "refresh_token_absolute_lifetime", "refresh_token_expiration",
+		"access_token_lifetime", "id_token_lifetime", "authorization_code_lifetime", "device_code_lifetime",
 		"created_at", "updated_at", "deleted_at",
 	}
 }
@@ -33,7 +48,7 @@ func setupTestClientService(t *testing.T) (*sql.DB, sqlmock.Sqlmock, OAuth2Clien
 	require.NoError(t, err)
 
 	clientRepo := repository.NewOAuth2ClientRepository(db)
-	svc := NewOAuth2ClientService(db, clientRepo, nil, nil)
+	svc := NewOAuth2ClientService(db, clientRepo, nil, nil, nil, nil, nil, ClientLifetimeLimits{})
 
 	return db, mock, svc
 }
@@ -54,7 +69,7 @@ func TestFindByAccountID(t *testing.T) {
 	rows := sqlmock.NewRows(clientTestColumns()).AddRow(
 		"uuid-001", "account-001", "abc123", "$2a$10$hash",
 		"Test App", "desc", redirectURIs, postLogoutURIs, grantTypes, scopes,
-		true, nil, "", false, "", false, now, now, nil,
+		true, nil, "", false, "", false, false, "", "", "", "", []byte("[]"), false, "", "", "", "", "", false, "", false, []byte("[]"), []byte("[]"), "", "", "", "", "", "", "", "", "", "", "", 0, 0, "", 0, 0, 0, 0, now, now, nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM oauth2_clients").
@@ -104,7 +119,7 @@ func TestUpdateClient(t *testing.T) {
 	clientRows := sqlmock.NewRows(clientTestColumns()).AddRow(
 		"uuid-001", "account-001", "cid-abc", "$2a$10$hash", "Old Name", "",
 		[]byte(`["http://localhost/callback"]`), []byte("null"), []byte(`["authorization_code"]`), []byte(`["openid"]`),
-		true, []byte("{}"), "", false, "", false, now, updatedAt, nil,
+		true, []byte("{}"), "", false, "", false, false, "", "", "", "", []byte("[]"), false, "", "", "", "", "", false, "", false, []byte("[]"), []byte("[]"), "", "", "", "", "", "", "", "", "", "", "", 0, 0, "", 0, 0, 0, 0, now, updatedAt, nil,
 	)
 	mock.ExpectQuery("SELECT (.+) FROM oauth2_clients").
 		WithArgs("cid-abc").
@@ -112,7 +127,7 @@ func TestUpdateClient(t *testing.T) {
 
 	// Update with optimistic locking
 	mock.ExpectQuery("UPDATE oauth2_clients").
-		WithArgs("Updated App", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "uuid-001", updatedAt).
+		WithArgs("Updated App", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "uuid-001", updatedAt).
 		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
 	mock.ExpectCommit()
 
@@ -177,7 +192,7 @@ func TestDeleteClient(t *testing.T) {
 	rows := sqlmock.NewRows(clientTestColumns()).AddRow(
 		"uuid-001", "account-001", "abc123", "$2a$10$hash",
 		"Test App", "desc", redirectURIs, postLogoutURIs, grantTypes, scopes,
-		true, nil, "", false, "", false, now, now, nil,
+		true, nil, "", false, "", false, false, "", "", "", "", []byte("[]"), false, "", "", "", "", "", false, "", false, []byte("[]"), []byte("[]"), "", "", "", "", "", "", "", "", "", "", "", 0, 0, "", 0, 0, 0, 0, now, now, nil,
 	)
 
 	// FindByClientID and SoftDelete now both run inside the same transaction
@@ -232,7 +247,7 @@ func TestDeleteClient_AccessDenied(t *testing.T) {
 	rows := sqlmock.NewRows(clientTestColumns()).AddRow(
 		"uuid-001", "account-001", "abc123", "$2a$10$hash",
 		"Test App", "desc", redirectURIs, postLogoutURIs, grantTypes, scopes,
-		true, nil, "", false, "", false, now, now, nil,
+		true, nil, "", false, "", false, false, "", "", "", "", []byte("[]"), false, "", "", "", "", "", false, "", false, []byte("[]"), []byte("[]"), "", "", "", "", "", "", "", "", "", "", "", 0, 0, "", 0, 0, 0, 0, now, now, nil,
 	)
 
 	// FindByClientID now runs inside the transaction; access denied triggers rollback
@@ -336,6 +351,512 @@ func TestRegisterClient_RejectsReservedAdminScopes(t *testing.T) {
 	assert.Contains(t, err.Error(), "reserved for administrator clients")
 }
 
+func TestRegisterClient_RejectsPushedAuthorizationRequirementForPublicClient(t *testing.T) {
+	db, _, svc := setupTestClientService(t)
+	defer db.Close()
+
+	req := &RegisterClientRequest{
+		AccountID:                          "account-001",
+		Name:                               "SPA",
+		RedirectURIs:                       []string{"http://localhost/callback"},
+		RequirePushedAuthorizationRequests: true,
+	}
+
+	client, _, err := svc.RegisterClient(context.Background(), req)
+	require.Error(t, err)
+	assert.Nil(t, client)
+	assert.True(t, IsValidationError(err))
+}
+
+func TestRegisterClient_RejectsSignedRequestObjectRequirementWithoutKeys(t *testing.T) {
+	db, _, svc := setupTestClientService(t)
+	defer db.Close()
+
+	req := &RegisterClientRequest{
+		AccountID:                  "account-001",
+		Name:                       "Web App",
+		RedirectURIs:               []string{"https://app.example.com/callback"},
+		IsConfidential:             true,
+		RequireSignedRequestObject: true,
+	}
+
+	client, _, err := svc.RegisterClient(context.Background(), req)
+	require.Error(t, err)
+	assert.Nil(t, client)
+	assert.True(t, IsValidationError(err))
+	assert.Contains(t, err.Error(), "require_signed_request_object")
+}
+
+func TestRegisterClient_RequestURIsValidation(t *testing.T) {
+	db, _, svc := setupTestClientService(t)
+	defer db.Close()
+
+	for _, uri := range []string{"http://app.example.com/request.jwt", "https://app.example.com/request.jwt#v1", "/request.jwt"} {
+		client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+			AccountID:    "account-001",
+			Name:         "Web App",
+			RedirectURIs: []string{"https://app.example.com/callback"},
+			RequestURIs:  []string{uri},
+		})
+		require.Error(t, err, uri)
+		assert.Nil(t, client)
+		assert.True(t, IsValidationError(err))
+		assert.Contains(t, err.Error(), "request_uri")
+	}
+}
+
+func TestRegisterClient_AuthorizationDetailsTypesValidation(t *testing.T) {
+	db, _, svc := setupTestClientService(t)
+	defer db.Close()
+
+	for _, types := range [][]string{{""}, {" payment_initiation"}, {strings.Repeat("x", 129)}} {
+		client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+			AccountID:                 "account-001",
+			Name:                      "Web App",
+			RedirectURIs:              []string{"https://app.example.com/callback"},
+			AuthorizationDetailsTypes: types,
+		})
+		require.Error(t, err, types)
+		assert.Nil(t, client)
+		assert.True(t, IsValidationError(err))
+		assert.Contains(t, err.Error(), "authorization_details_type")
+	}
+}
+
+func TestRegisterClient_BackchannelAuthenticationValidation(t *testing.T) {
+	db, _, svc := setupTestClientService(t)
+	defer db.Close()
+
+	tests := map[string]struct {
+		confidential bool
+		mode         string
+		endpoint     string
+		want         string
+	}{
+		"public client":         {false, "", "", "only supported for confidential clients"},
+		"unknown mode":          {true, "callback", "", "invalid backchannel_token_delivery_mode"},
+		"ping without endpoint": {true, "ping", "", "requires backchannel_client_notification_endpoint"},
+		"http endpoint":         {true, "push", "http://app.example.com/ciba", "invalid backchannel_client_notification_endpoint"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+				AccountID:                             "account-001",
+				Name:                                  "Call Center",
+				GrantTypes:                            []string{domain.GrantTypeCIBA},
+				IsConfidential:                        tt.confidential,
+				BackchannelTokenDeliveryMode:          tt.mode,
+				BackchannelClientNotificationEndpoint: tt.endpoint,
+			})
+			require.Error(t, err)
+			assert.Nil(t, client)
+			assert.True(t, IsValidationError(err))
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestRegisterClient_PairwiseSubjectsNotEnabled(t *testing.T) {
+	db, _, svc := setupTestClientService(t)
+	defer db.Close()
+
+	client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:    "account-001",
+		Name:         "Pairwise App",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		SubjectType:  domain.SubjectTypePairwise,
+	})
+	require.Error(t, err)
+	assert.Nil(t, client)
+	assert.True(t, IsValidationError(err))
+	assert.Contains(t, err.Error(), "pairwise subject identifiers are not enabled")
+}
+
+func TestRegisterClient_IDTokenSigningAlg(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	svc := NewOAuth2ClientService(db, repository.NewOAuth2ClientRepository(db), nil, nil, nil, nil, []string{"RS256", "ES256"}, ClientLifetimeLimits{})
+
+	for _, alg := range []string{"none", "HS256", "EdDSA"} {
+		client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+			AccountID:                "account-001",
+			Name:                     "Mobile App",
+			RedirectURIs:             []string{"https://app.example.com/callback"},
+			IDTokenSignedResponseAlg: alg,
+		})
+		require.Error(t, err, alg)
+		assert.Nil(t, client)
+		assert.True(t, IsValidationError(err))
+		assert.Contains(t, err.Error(), "unsupported id_token_signed_response_alg")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO oauth2_clients").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("client-uuid-001", time.Now(), time.Now()))
+	mock.ExpectCommit()
+	client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:                "account-001",
+		Name:                     "Mobile App",
+		RedirectURIs:             []string{"https://app.example.com/callback"},
+		IDTokenSignedResponseAlg: "ES256",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ES256", client.IDTokenSigningAlg())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterClient_UserinfoSigningAlg(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	svc := NewOAuth2ClientService(db, repository.NewOAuth2ClientRepository(db), nil, nil, nil, nil, []string{"RS256", "ES256"}, ClientLifetimeLimits{})
+
+	for _, alg := range []string{"none", "HS256", "EdDSA"} {
+		client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+			AccountID:                 "account-001",
+			Name:                      "Mobile App",
+			RedirectURIs:              []string{"https://app.example.com/callback"},
+			UserinfoSignedResponseAlg: alg,
+		})
+		require.Error(t, err, alg)
+		assert.Nil(t, client)
+		assert.True(t, IsValidationError(err))
+		assert.Contains(t, err.Error(), "unsupported userinfo_signed_response_alg")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO oauth2_clients").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("client-uuid-001", time.Now(), time.Now()))
+	mock.ExpectCommit()
+	client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:                 "account-001",
+		Name:                      "Mobile App",
+		RedirectURIs:              []string{"https://app.example.com/callback"},
+		UserinfoSignedResponseAlg: "ES256",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ES256", client.UserinfoSignedResponseAlg)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterClient_Lifetimes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	svc := NewOAuth2ClientService(db, repository.NewOAuth2ClientRepository(db), nil, nil, nil, nil, nil, ClientLifetimeLimits{
+		AccessToken:  time.Hour,
+		RefreshToken: 24 * time.Hour,
+	})
+
+	tests := map[string]struct {
+		req     RegisterClientRequest
+		wantErr string
+	}{
+		"negative":          {RegisterClientRequest{IDTokenLifetime: -1}, "id_token_lifetime must not be negative"},
+		"above ceiling":     {RegisterClientRequest{AccessTokenLifetime: 7200}, "access_token_lifetime must not exceed 3600 seconds"},
+		"refresh idle":      {RegisterClientRequest{RefreshTokenIdleLifetime: 90000}, "refresh_token_idle_lifetime must not exceed 86400 seconds"},
+		"expiration mode":   {RegisterClientRequest{RefreshTokenExpiration: "forever"}, "invalid refresh_token_expiration"},
+		"negative absolute": {RegisterClientRequest{RefreshTokenAbsoluteLifetime: -5}, "refresh_token_absolute_lifetime must not be negative"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := tt.req
+			req.AccountID, req.Name, req.RedirectURIs = "account-001", "Kiosk", []string{"https://kiosk.example.com/callback"}
+			client, _, err := svc.RegisterClient(context.Background(), &req)
+			require.Error(t, err)
+			assert.Nil(t, client)
+			assert.True(t, IsValidationError(err))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO oauth2_clients").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("client-uuid-001", time.Now(), time.Now()))
+	mock.ExpectCommit()
+	client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:                    "account-001",
+		Name:                         "Kiosk",
+		RedirectURIs:                 []string{"https://kiosk.example.com/callback"},
+		AccessTokenLifetime:          120,
+		AuthorizationCodeLifetime:    30,
+		RefreshTokenAbsoluteLifetime: 30 * 24 * 3600,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, client.AccessTokenExpiry())
+	assert.Equal(t, 30*time.Second, client.AuthorizationCodeExpiry())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterClient_TokenEndpointAuthMethodValidation(t *testing.T) {
+	db, _, svc := setupTestClientService(t)
+	defer db.Close()
+
+	tests := []struct {
+		name string
+		req  RegisterClientRequest
+	}{
+		{"unknown method", RegisterClientRequest{TokenEndpointAuthMethod: "client_secret_magic", IsConfidential: true}},
+		{"none for confidential client", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodNone, IsConfidential: true}},
+		{"private_key_jwt for public client", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT, JWKSURI: "https://app.example.com/jwks.json"}},
+		{"private_key_jwt without keys", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT, IsConfidential: true}},
+		{"private_key_jwt with jwks and jwks_uri", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT, IsConfidential: true, JWKS: json.RawMessage(`{"keys":[]}`), JWKSURI: "https://app.example.com/jwks.json"}},
+		{"private_key_jwt with http jwks_uri", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT, IsConfidential: true, JWKSURI: "http://app.example.com/jwks.json"}},
+		{"private_key_jwt with invalid jwks", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT, IsConfidential: true, JWKS: json.RawMessage(`{"keys":[]}`)}},
+		{"jwks_uri without private_key_jwt", RegisterClientRequest{IsConfidential: true, JWKSURI: "https://app.example.com/jwks.json"}},
+		{"client_secret_jwt without encryption key", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodClientSecretJWT, IsConfidential: true}},
+		{"tls_client_auth for public client", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth, TLSClientAuthSubjectDN: "CN=client"}},
+		{"tls_client_auth without subject", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth, IsConfidential: true}},
+		{"tls_client_auth with two subjects", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth, IsConfidential: true, TLSClientAuthSubjectDN: "CN=client", TLSClientAuthSANDNS: "client.example.com"}},
+		{"tls_client_auth with invalid san ip", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth, IsConfidential: true, TLSClientAuthSANIP: "not-an-ip"}},
+		{"tls_client_auth with relative san uri", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth, IsConfidential: true, TLSClientAuthSANURI: "client"}},
+		{"subject without tls_client_auth", RegisterClientRequest{IsConfidential: true, TLSClientAuthSubjectDN: "CN=client"}},
+		{"self_signed_tls_client_auth without keys", RegisterClientRequest{TokenEndpointAuthMethod: domain.AuthMethodSelfSignedTLSClientAuth, IsConfidential: true}},
+		{"encrypted responses without keys", RegisterClientRequest{IsConfidential: true, IDTokenEncryptedResponseAlg: "RSA-OAEP"}},
+		{"unsupported encryption alg", RegisterClientRequest{IsConfidential: true, JWKSURI: "https://app.example.com/jwks.json", IDTokenEncryptedResponseAlg: "ECDH-ES"}},
+		{"unsupported encryption enc", RegisterClientRequest{IsConfidential: true, JWKSURI: "https://app.example.com/jwks.json", UserinfoEncryptedResponseAlg: "RSA-OAEP", UserinfoEncryptedResponseEnc: "A512GCM"}},
+		{"encryption enc without alg", RegisterClientRequest{IsConfidential: true, JWKSURI: "https://app.example.com/jwks.json", UserinfoEncryptedResponseEnc: "A256GCM"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.AccountID = "account-001"
+			req.Name = "App"
+			req.RedirectURIs = []string{"https://app.example.com/callback"}
+			client, _, err := svc.RegisterClient(context.Background(), &req)
+			require.Error(t, err)
+			assert.Nil(t, client)
+			assert.True(t, IsValidationError(err))
+		})
+	}
+}
+
+func TestRegisterClient_PrivateKeyJWT(t *testing.T) {
+	db, mock, svc := setupTestClientService(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO oauth2_clients").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+			AddRow("client-uuid-pkjwt", now, now))
+	mock.ExpectCommit()
+
+	client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:               "account-001",
+		Name:                    "Backend",
+		RedirectURIs:            []string{"https://app.example.com/callback"},
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodPrivateKeyJWT,
+		JWKSURI:                 "https://app.example.com/jwks.json",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuthMethodPrivateKeyJWT, client.TokenEndpointAuthMethod)
+	assert.Equal(t, "https://app.example.com/jwks.json", client.JWKSURI)
+	assert.Empty(t, client.ClientSecretEncrypted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterClient_EncryptedResponses(t *testing.T) {
+	db, mock, svc := setupTestClientService(t)
+	defer db.Close()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// A jwks holding only signature keys cannot receive encrypted responses.
+	_, _, err = svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:                   "account-001",
+		Name:                        "Backend",
+		RedirectURIs:                []string{"https://app.example.com/callback"},
+		IsConfidential:              true,
+		JWKS:                        rsaJWKS(t, "sig1", &key.PublicKey),
+		IDTokenEncryptedResponseAlg: "RSA-OAEP",
+	})
+	require.Error(t, err)
+	assert.True(t, IsValidationError(err))
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO oauth2_clients").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+			AddRow("client-uuid-jwe", now, now))
+	mock.ExpectCommit()
+	client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:                    "account-001",
+		Name:                         "Backend",
+		RedirectURIs:                 []string{"https://app.example.com/callback"},
+		IsConfidential:               true,
+		JWKS:                         rsaEncryptionJWKS(t, "enc1", &key.PublicKey),
+		IDTokenEncryptedResponseAlg:  "RSA-OAEP-256",
+		UserinfoEncryptedResponseAlg: "RSA-OAEP",
+		UserinfoEncryptedResponseEnc: "A256GCM",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultEncryptedResponseEnc, client.IDTokenEncryptedResponseEnc)
+	assert.Equal(t, "A256GCM", client.UserinfoEncryptedResponseEnc)
+	assert.NotEmpty(t, client.JWKS)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyEncryptedResponses(t *testing.T) {
+	alg, enc, none := "RSA-OAEP", "A256GCM", ""
+	c := &domain.OAuth2Client{}
+	require.NoError(t, applyEncryptedResponses(c, &UpdateClientRequest{IDTokenEncryptedResponseAlg: &alg}))
+	assert.Equal(t, domain.DefaultEncryptedResponseEnc, c.IDTokenEncryptedResponseEnc)
+	require.NoError(t, applyEncryptedResponses(c, &UpdateClientRequest{IDTokenEncryptedResponseEnc: &enc}))
+	assert.Equal(t, "A256GCM", c.IDTokenEncryptedResponseEnc)
+	require.NoError(t, applyEncryptedResponses(c, &UpdateClientRequest{IDTokenEncryptedResponseAlg: &none}))
+	assert.False(t, c.EncryptsResponses())
+	assert.Empty(t, c.IDTokenEncryptedResponseEnc)
+	assert.Error(t, applyEncryptedResponses(c, &UpdateClientRequest{UserinfoEncryptedResponseEnc: &enc}))
+}
+
+func TestRegisterClient_TLSClientAuth(t *testing.T) {
+	db, mock, svc := setupTestClientService(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO oauth2_clients").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+			AddRow("client-uuid-mtls", now, now))
+	mock.ExpectCommit()
+
+	client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:                             "account-001",
+		Name:                                  "Backend",
+		RedirectURIs:                          []string{"https://app.example.com/callback"},
+		IsConfidential:                        true,
+		TokenEndpointAuthMethod:               domain.AuthMethodTLSClientAuth,
+		TLSClientAuthSANDNS:                   "client.example.com",
+		TLSClientCertificateBoundAccessTokens: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuthMethodTLSClientAuth, client.TokenEndpointAuthMethod)
+	assert.Equal(t, "client.example.com", client.TLSClientAuthSANDNS)
+	assert.True(t, client.TLSClientCertificateBoundAccessTokens)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyTLSClientAuthSubject(t *testing.T) {
+	dn, dns := "CN=client", "client.example.com"
+	c := &domain.OAuth2Client{TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth, TLSClientAuthSubjectDN: dn}
+
+	// Setting one subject value replaces the others.
+	applyTLSClientAuthSubject(c, &UpdateClientRequest{TLSClientAuthSANDNS: &dns})
+	assert.Empty(t, c.TLSClientAuthSubjectDN)
+	assert.Equal(t, dns, c.TLSClientAuthSANDNS)
+
+	// Unrelated updates keep the subject.
+	applyTLSClientAuthSubject(c, &UpdateClientRequest{})
+	assert.Equal(t, dns, c.TLSClientAuthSANDNS)
+
+	// Switching away from tls_client_auth clears it.
+	c.TokenEndpointAuthMethod = domain.AuthMethodPrivateKeyJWT
+	method := domain.AuthMethodPrivateKeyJWT
+	applyTLSClientAuthSubject(c, &UpdateClientRequest{TokenEndpointAuthMethod: &method})
+	assert.Empty(t, c.TLSClientAuthSANDNS)
+	assert.NoError(t, validateTLSClientAuthSubject(c))
+}
+
+func TestRegisterClient_ClientSecretJWT(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	cipher, err := NewClientSecretCipher(testClientSecretKey)
+	require.NoError(t, err)
+	svc := NewOAuth2ClientService(db, repository.NewOAuth2ClientRepository(db), nil, nil, cipher, nil, nil, ClientLifetimeLimits{})
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO oauth2_clients").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+			AddRow("client-uuid-csjwt", now, now))
+	mock.ExpectCommit()
+
+	client, secret, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:               "account-001",
+		Name:                    "Backend",
+		RedirectURIs:            []string{"https://app.example.com/callback"},
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodClientSecretJWT,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, client.ClientSecretEncrypted)
+	decrypted, err := cipher.Decrypt(client.ClientID, client.ClientSecretEncrypted)
+	require.NoError(t, err)
+	assert.Equal(t, secret, decrypted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterClient_TokenExchangeAudiencesValidation(t *testing.T) {
+	db, _, svc := setupTestClientService(t)
+	defer db.Close()
+
+	tests := []struct {
+		name string
+		req  RegisterClientRequest
+	}{
+		{"set without administrator", RegisterClientRequest{IsConfidential: true, TokenExchangeAudiences: []string{"orders-api"}}},
+		{"public client", RegisterClientRequest{TokenExchangeAudiences: []string{"orders-api"}, AllowTokenExchangePolicy: true}},
+		{"empty audience", RegisterClientRequest{IsConfidential: true, TokenExchangeAudiences: []string{""}, AllowTokenExchangePolicy: true}},
+		{"audience with whitespace", RegisterClientRequest{IsConfidential: true, TokenExchangeAudiences: []string{"orders api"}, AllowTokenExchangePolicy: true}},
+		{"audience too long", RegisterClientRequest{IsConfidential: true, TokenExchangeAudiences: []string{strings.Repeat("a", maxTokenExchangeAudienceLength+1)}, AllowTokenExchangePolicy: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.AccountID = "account-001"
+			req.Name = "App"
+			req.RedirectURIs = []string{"https://app.example.com/callback"}
+			req.GrantTypes = []string{domain.GrantTypeTokenExchange}
+			client, _, err := svc.RegisterClient(context.Background(), &req)
+			require.Error(t, err)
+			assert.Nil(t, client)
+			assert.True(t, IsValidationError(err))
+		})
+	}
+}
+
+func TestRegisterClient_TokenExchange(t *testing.T) {
+	db, mock, svc := setupTestClientService(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO oauth2_clients").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+			AddRow("client-uuid-exchange", now, now))
+	mock.ExpectCommit()
+
+	client, _, err := svc.RegisterClient(context.Background(), &RegisterClientRequest{
+		AccountID:                "account-001",
+		Name:                     "Orders Gateway",
+		RedirectURIs:             []string{"https://app.example.com/callback"},
+		GrantTypes:               []string{domain.GrantTypeTokenExchange},
+		IsConfidential:           true,
+		TokenExchangeAudiences:   []string{"orders-api", "https://billing.example.com/api"},
+		AllowTokenExchangePolicy: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.GrantTypeTokenExchange}, client.GrantTypes)
+	assert.True(t, client.CanExchangeForAudience("orders-api"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateClientByAccountID_TokenExchangeAudiencesRequireAdministrator(t *testing.T) {
+	db, mock, svc := setupTestClientService(t)
+	defer db.Close()
+
+	_, err := svc.UpdateClientByAccountID(context.Background(), "account-001", "abc123", &UpdateClientRequest{
+		TokenExchangeAudiences: []string{"orders-api"},
+	})
+	require.Error(t, err)
+	assert.True(t, IsValidationError(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRegisterClient_AllowsReservedAdminScopesForPrivilegedCaller(t *testing.T) {
 	db, mock, svc := setupTestClientService(t)
 	defer db.Close()
@@ -381,7 +902,7 @@ func TestFindByClientID(t *testing.T) {
 	rows := sqlmock.NewRows(clientTestColumns()).AddRow(
 		"uuid-001", "account-001", "abc123", "$2a$10$hash",
 		"Test App", "desc", redirectURIs, postLogoutURIs, grantTypes, scopes,
-		true, nil, "", false, "", false, now, now, nil,
+		true, nil, "", false, "", false, false, "", "", "", "", []byte("[]"), false, "", "", "", "", "", false, "", false, []byte("[]"), []byte("[]"), "", "", "", "", "", "", "", "", "", "", "", 0, 0, "", 0, 0, 0, 0, now, now, nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM oauth2_clients").
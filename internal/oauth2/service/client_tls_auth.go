@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// AuthenticateClientCertificate verifies mutual-TLS client authentication (RFC 8705 §2).
+// For tls_client_auth the certificate must chain to one of the trust anchors set with
+// SetTLSClientAuthRoots, for client authentication, and carry the subject DN or subject
+// alternative name the client registered (§2.1); without trust anchors it is refused. For
+// self_signed_tls_client_auth the certificate's public key must be one of the keys in
+// the client's jwks or jwks_uri (§2.2).
+func (a *ClientAuthenticator) AuthenticateClientCertificate(ctx context.Context, client *domain.OAuth2Client, cert *x509.Certificate) error {
+	if !client.UsesTLSClientAuth() {
+		return ErrClientAuthMethodNotAllowed
+	}
+	if cert == nil {
+		return ErrClientCertificateRequired
+	}
+	if client.TokenEndpointAuthMethod == domain.AuthMethodTLSClientAuth {
+		if a.tlsClientAuthRoots == nil {
+			return fmt.Errorf("%w: no trust anchors configured for tls_client_auth", ErrInvalidClientCertificate)
+		}
+		if _, err := cert.Verify(x509.VerifyOptions{
+			Roots:     a.tlsClientAuthRoots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidClientCertificate, err)
+		}
+		if !certificateMatchesSubject(client, cert) {
+			return fmt.Errorf("%w: subject mismatch", ErrInvalidClientCertificate)
+		}
+		return nil
+	}
+
+	matches, err := a.certificateKeyRegistered(ctx, client, cert, false)
+	if err == nil && !matches && client.JWKSURI != "" {
+		// The client may have rotated certificates since the set was cached.
+		matches, err = a.certificateKeyRegistered(ctx, client, cert, true)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidClientCertificate, err)
+	}
+	if !matches {
+		return fmt.Errorf("%w: certificate is not registered", ErrInvalidClientCertificate)
+	}
+	return nil
+}
+
+// certificateKeyRegistered reports whether the public key of cert is one of the client's
+// registered keys.
+func (a *ClientAuthenticator) certificateKeyRegistered(ctx context.Context, client *domain.OAuth2Client, cert *x509.Certificate, forceRefresh bool) (bool, error) {
+	keys, err := a.clientVerificationKeys(ctx, client, forceRefresh)
+	if err != nil {
+		return false, err
+	}
+	pub, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok {
+		return false, nil
+	}
+	return slices.ContainsFunc(keys, func(k clientJWK) bool { return pub.Equal(k.Key) }), nil
+}
+
+// certificateMatchesSubject reports whether cert carries the subject the tls_client_auth
+// client registered (RFC 8705 §2.1.2).
+func certificateMatchesSubject(client *domain.OAuth2Client, cert *x509.Certificate) bool {
+	switch {
+	case client.TLSClientAuthSubjectDN != "":
+		return strings.EqualFold(normalizeDN(client.TLSClientAuthSubjectDN), normalizeDN(cert.Subject.String()))
+	case client.TLSClientAuthSANDNS != "":
+		return slices.ContainsFunc(cert.DNSNames, func(name string) bool {
+			return strings.EqualFold(name, client.TLSClientAuthSANDNS)
+		})
+	case client.TLSClientAuthSANURI != "":
+		return slices.ContainsFunc(cert.URIs, func(u *url.URL) bool { return u.String() == client.TLSClientAuthSANURI })
+	case client.TLSClientAuthSANIP != "":
+		want := net.ParseIP(client.TLSClientAuthSANIP)
+		return want != nil && slices.ContainsFunc(cert.IPAddresses, want.Equal)
+	case client.TLSClientAuthSANEmail != "":
+		return slices.Contains(cert.EmailAddresses, client.TLSClientAuthSANEmail)
+	}
+	return false
+}
+
+// normalizeDN removes optional whitespace around the RDN separators of an RFC 4514
+// distinguished name, so "CN=a, O=b" matches "CN=a,O=b".
+func normalizeDN(dn string) string {
+	rdns := strings.Split(dn, ",")
+	for i, rdn := range rdns {
+		rdns[i] = strings.TrimSpace(rdn)
+	}
+	return strings.Join(rdns, ",")
+}
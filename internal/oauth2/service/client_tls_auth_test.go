@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+	"github.com/rushairer/gosso/internal/testutil"
+)
+
+func TestClientAuthenticator_TLSClientRejectsSecret(t *testing.T) {
+	a := &ClientAuthenticator{}
+	client := &domain.OAuth2Client{IsConfidential: true, TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth}
+	assert.ErrorIs(t, a.AuthenticateClient(client, "secret"), ErrClientCertificateRequired)
+}
+
+func TestClientAuthenticator_TLSClientAuth_SubjectDN(t *testing.T) {
+	ca := testutil.NewCertificateAuthority(t)
+	a := &ClientAuthenticator{}
+	a.SetTLSClientAuthRoots(ca.Pool())
+	cert, _ := ca.IssueCertificate(t, pkix.Name{CommonName: "client.example.com", Organization: []string{"Example"}})
+	client := &domain.OAuth2Client{
+		ClientID:                "cid-mtls",
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth,
+		TLSClientAuthSubjectDN:  "cn=client.example.com, o=Example",
+	}
+	assert.NoError(t, a.AuthenticateClientCertificate(context.Background(), client, cert))
+
+	client.TLSClientAuthSubjectDN = "CN=other.example.com,O=Example"
+	assert.ErrorIs(t, a.AuthenticateClientCertificate(context.Background(), client, cert), ErrInvalidClientCertificate)
+	assert.ErrorIs(t, a.AuthenticateClientCertificate(context.Background(), client, nil), ErrClientCertificateRequired)
+}
+
+func TestClientAuthenticator_TLSClientAuth_UntrustedCertificate(t *testing.T) {
+	ca := testutil.NewCertificateAuthority(t)
+	subject := pkix.Name{CommonName: "client.example.com", Organization: []string{"Example"}}
+	client := &domain.OAuth2Client{
+		ClientID:                "cid-mtls",
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodTLSClientAuth,
+		TLSClientAuthSubjectDN:  "CN=client.example.com,O=Example",
+	}
+
+	// A self-signed certificate carrying the registered DN is not issued by a trusted CA.
+	a := &ClientAuthenticator{}
+	a.SetTLSClientAuthRoots(ca.Pool())
+	selfSigned, _ := testutil.SelfSignedCertificate(t, subject)
+	assert.ErrorIs(t, a.AuthenticateClientCertificate(context.Background(), client, selfSigned), ErrInvalidClientCertificate)
+
+	// Neither is one issued by another CA.
+	otherCA := testutil.NewCertificateAuthority(t)
+	foreign, _ := otherCA.IssueCertificate(t, subject)
+	assert.ErrorIs(t, a.AuthenticateClientCertificate(context.Background(), client, foreign), ErrInvalidClientCertificate)
+
+	// Without trust anchors tls_client_auth is refused outright.
+	issued, _ := ca.IssueCertificate(t, subject)
+	assert.ErrorIs(t, (&ClientAuthenticator{}).AuthenticateClientCertificate(context.Background(), client, issued), ErrInvalidClientCertificate)
+}
+
+func TestCertificateMatchesSubject_SAN(t *testing.T) {
+	uri, err := url.Parse("spiffe://example.com/client")
+	require.NoError(t, err)
+	cert := &x509.Certificate{
+		DNSNames:       []string{"client.example.com"},
+		URIs:           []*url.URL{uri},
+		IPAddresses:    []net.IP{net.ParseIP("192.0.2.10")},
+		EmailAddresses: []string{"client@example.com"},
+	}
+
+	tests := []struct {
+		name   string
+		client domain.OAuth2Client
+		want   bool
+	}{
+		{"dns", domain.OAuth2Client{TLSClientAuthSANDNS: "CLIENT.example.com"}, true},
+		{"dns mismatch", domain.OAuth2Client{TLSClientAuthSANDNS: "other.example.com"}, false},
+		{"uri", domain.OAuth2Client{TLSClientAuthSANURI: "spiffe://example.com/client"}, true},
+		{"ip", domain.OAuth2Client{TLSClientAuthSANIP: "192.0.2.10"}, true},
+		{"ip mismatch", domain.OAuth2Client{TLSClientAuthSANIP: "192.0.2.11"}, false},
+		{"email", domain.OAuth2Client{TLSClientAuthSANEmail: "client@example.com"}, true},
+		{"nothing registered", domain.OAuth2Client{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := tt.client
+			assert.Equal(t, tt.want, certificateMatchesSubject(&client, cert))
+		})
+	}
+}
+
+func TestClientAuthenticator_SelfSignedTLSClientAuth(t *testing.T) {
+	a := &ClientAuthenticator{}
+	cert, key := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client"})
+	other, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client"})
+	client := &domain.OAuth2Client{
+		ClientID:                "cid-mtls",
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodSelfSignedTLSClientAuth,
+		JWKS:                    ecJWKS(t, "cert-1", &key.PublicKey),
+	}
+	assert.NoError(t, a.AuthenticateClientCertificate(context.Background(), client, cert))
+	assert.ErrorIs(t, a.AuthenticateClientCertificate(context.Background(), client, other), ErrInvalidClientCertificate)
+}
+
+func TestClientAuthenticator_CertificateNotAllowedForSecretClient(t *testing.T) {
+	a := &ClientAuthenticator{}
+	cert, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client"})
+	client := &domain.OAuth2Client{IsConfidential: true, TokenEndpointAuthMethod: domain.AuthMethodClientSecretBasic}
+	assert.ErrorIs(t, a.AuthenticateClientCertificate(context.Background(), client, cert), ErrClientAuthMethodNotAllowed)
+}
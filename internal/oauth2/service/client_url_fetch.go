@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+const (
+	clientURLTimeout      = 5 * time.Second
+	maxClientURLRedirects = 3
+	// maxClientURLResponseSize bounds every response; fetchers may enforce lower limits.
+	maxClientURLResponseSize = 64 * 1024
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which some clouds use for
+// their metadata services.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewClientURLHTTPClient returns an HTTP client for fetching the URLs that clients register,
+// such as jwks_uri, request_uris and sector_identifier_uri. Since anyone can register a
+// client through dynamic registration, it only fetches https URLs on public addresses, so
+// the server cannot be used to reach internal services (SSRF). Addresses are checked after
+// DNS resolution, on every connection. It follows at most 3 redirects, also to https URLs
+// only, and fails responses larger than 64KB.
+func NewClientURLHTTPClient() *http.Client {
+	dialer := &net.Dialer{Timeout: clientURLTimeout, Control: dialPublicAddressOnly}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   clientURLTimeout,
+		ResponseHeaderTimeout: clientURLTimeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+	return &http.Client{
+		Timeout:   clientURLTimeout,
+		Transport: clientURLTransport{base: transport},
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if len(via) > maxClientURLRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxClientURLRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// clientURLTransport refuses requests that are not https, including redirects, and caps
+// response bodies at maxClientURLResponseSize.
+type clientURLTransport struct {
+	base http.RoundTripper
+}
+
+func (t clientURLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return nil, errors.New("only https URLs can be fetched")
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = http.MaxBytesReader(nil, resp.Body, maxClientURLResponseSize)
+	return resp, nil
+}
+
+// dialPublicAddressOnly is a net.Dialer Control function that refuses connections to
+// addresses that are not public.
+func dialPublicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddress(addr) {
+		return fmt.Errorf("refusing to connect to non-public address %s", addr)
+	}
+	return nil
+}
+
+// isPublicAddress reports whether addr is a global unicast address outside the private
+// (RFC 1918, RFC 4193) and shared (RFC 6598) ranges. Loopback, link-local, multicast and
+// unspecified addresses are not global unicast.
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
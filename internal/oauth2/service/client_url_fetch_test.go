@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.215.14":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"fd00::1":         false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"100.100.100.200": false,
+		"0.0.0.0":         false,
+		"::":              false,
+		"224.0.0.1":       false,
+		"::ffff:10.0.0.1": false,
+		"::ffff:8.8.8.8":  true,
+	}
+	for addr, want := range tests {
+		assert.Equal(t, want, isPublicAddress(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestClientURLHTTPClient_RefusesNonPublicAndNonHTTPS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	client := NewClientURLHTTPClient()
+
+	_, err := client.Get(srv.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "non-public address")
+
+	_, err = client.Get("http://example.com/sector.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only https URLs")
+}
+
+func TestClientURLHTTPClient_Redirects(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.com/jwks.json", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	// Redirects must stay on https.
+	client := &http.Client{Transport: clientURLTransport{base: srv.Client().Transport}}
+	_, err := client.Get(srv.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only https URLs")
+
+	checkRedirect := NewClientURLHTTPClient().CheckRedirect
+	assert.NoError(t, checkRedirect(nil, make([]*http.Request, maxClientURLRedirects)))
+	assert.Error(t, checkRedirect(nil, make([]*http.Request, maxClientURLRedirects+1)))
+}
+
+func TestClientURLHTTPClient_ResponseSizeLimit(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := maxClientURLResponseSize
+		if r.URL.Path == "/large" {
+			size++
+		}
+		_, _ = w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: clientURLTransport{base: srv.Client().Transport}}
+
+	resp, err := client.Get(srv.URL + "/limit")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+	assert.Len(t, body, maxClientURLResponseSize)
+
+	resp, err = client.Get(srv.URL + "/large")
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	var tooLarge *http.MaxBytesError
+	assert.True(t, errors.As(err, &tooLarge), "got %v", err)
+}
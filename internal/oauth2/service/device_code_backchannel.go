@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/cache"
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
+	"github.com/rushairer/gosso/internal/utility"
+)
+
+// Backchannel authentication requests (CIBA) share the pending-grant lifecycle of device
+// codes: they are stored in Redis under the hash of the auth_req_id and move through the
+// same pending → authorized/denied → used states, so the device code scripts apply to them.
+const (
+	backchannelAuthKeyPrefix = "ciba_request:"
+	authReqIDLength          = 32 // bytes → 43 base64url chars
+)
+
+// CreateBackchannelAuthRequest stores a new pending backchannel authentication request and
+// returns it with its raw auth_req_id set. expiry is capped at the device code expiry; zero
+// selects it. The raw auth_req_id is only kept for ping and push requests, which are
+// notified with it once the user has responded.
+func (s *DeviceCodeService) CreateBackchannelAuthRequest(ctx context.Context, req *domain.BackchannelAuthRequest, expiry time.Duration) (*domain.BackchannelAuthRequest, error) {
+	if req.ClientID == "" {
+		return nil, domain.ErrDeviceClientRequired
+	}
+	if req.AccountID == "" {
+		return nil, domain.ErrDeviceAccountIDRequired
+	}
+	if expiry <= 0 || expiry > s.expiry {
+		expiry = s.expiry
+	}
+
+	idBytes := make([]byte, authReqIDLength)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, fmt.Errorf("generate auth_req_id: %w", err)
+	}
+	authReqID := base64.RawURLEncoding.EncodeToString(idBytes)
+
+	now := time.Now()
+	created := *req
+	created.AuthReqID = authReqID
+	created.Status = domain.DeviceCodeStatusPending
+	created.ExpiresAt = now.Add(expiry)
+	created.Interval = int(s.interval.Seconds())
+	created.CreatedAt = now
+	created.ID = tokenDomain.HashToken(authReqID)
+	if created.DeliveryMode == "" {
+		created.DeliveryMode = domain.BackchannelTokenDeliveryPoll
+	}
+
+	stored := created
+	if stored.DeliveryMode == domain.BackchannelTokenDeliveryPoll {
+		stored.AuthReqID = ""
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("marshal backchannel authentication request: %w", err)
+	}
+	if err := s.redis.Set(ctx, backchannelAuthKeyPrefix+created.ID, string(data), expiry); err != nil {
+		return nil, fmt.Errorf("store backchannel authentication request: %w", err)
+	}
+
+	s.logger.Info("Backchannel authentication request created",
+		zap.String("client_id", created.ClientID),
+		zap.String("account_id", utility.MaskOpaqueID(created.AccountID)),
+		zap.String("delivery_mode", created.DeliveryMode))
+
+	return &created, nil
+}
+
+// GetBackchannelAuthRequest retrieves a backchannel authentication request by its auth_req_id.
+func (s *DeviceCodeService) GetBackchannelAuthRequest(ctx context.Context, authReqID string) (*domain.BackchannelAuthRequest, error) {
+	return s.GetBackchannelAuthRequestByID(ctx, tokenDomain.HashToken(authReqID))
+}
+
+// GetBackchannelAuthRequestByID retrieves a backchannel authentication request by its ID
+// (the hash of its auth_req_id), as used by the approval API.
+func (s *DeviceCodeService) GetBackchannelAuthRequestByID(ctx context.Context, id string) (*domain.BackchannelAuthRequest, error) {
+	data, err := s.redis.Get(ctx, backchannelAuthKeyPrefix+id)
+	if errors.Is(err, cache.ErrKeyNotFound) {
+		return nil, domain.ErrBackchannelAuthRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get backchannel authentication request: %w", err)
+	}
+
+	var req domain.BackchannelAuthRequest
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		return nil, fmt.Errorf("unmarshal backchannel authentication request: %w", err)
+	}
+	req.ID = id
+	return &req, nil
+}
+
+// AuthorizeBackchannelAuthRequest atomically marks a pending request as approved by accountID,
+// which must be the user the request was addressed to. It returns the updated request.
+func (s *DeviceCodeService) AuthorizeBackchannelAuthRequest(ctx context.Context, id, accountID string) (*domain.BackchannelAuthRequest, error) {
+	req, err := s.GetBackchannelAuthRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// The addressed account never changes, so checking it outside the script is race-free.
+	if req.AccountID != accountID {
+		return nil, domain.ErrBackchannelAuthRequestNotFound
+	}
+
+	result, err := s.redis.RunScript(ctx, authorizeDeviceCodeScript, []string{backchannelAuthKeyPrefix + id},
+		accountID, time.Now().Format(time.RFC3339),
+	).Result()
+	if errors.Is(err, redis.Nil) || result == nil {
+		return nil, domain.ErrBackchannelAuthRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("authorize backchannel authentication request: %w", err)
+	}
+
+	s.logger.Info("Backchannel authentication request authorized",
+		zap.String("client_id", req.ClientID),
+		zap.String("account_id", utility.MaskOpaqueID(accountID)))
+	return decodeBackchannelAuthRequest(result, id)
+}
+
+// DenyBackchannelAuthRequest atomically marks a pending request addressed to accountID as
+// denied. It returns the updated request.
+func (s *DeviceCodeService) DenyBackchannelAuthRequest(ctx context.Context, id, accountID string) (*domain.BackchannelAuthRequest, error) {
+	req, err := s.GetBackchannelAuthRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.AccountID != accountID {
+		return nil, domain.ErrBackchannelAuthRequestNotFound
+	}
+
+	result, err := s.redis.RunScript(ctx, denyDeviceCodeScript, []string{backchannelAuthKeyPrefix + id}).Result()
+	if errors.Is(err, redis.Nil) || result == nil {
+		return nil, domain.ErrBackchannelAuthRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("deny backchannel authentication request: %w", err)
+	}
+
+	s.logger.Info("Backchannel authentication request denied",
+		zap.String("client_id", req.ClientID),
+		zap.String("account_id", utility.MaskOpaqueID(accountID)))
+	return decodeBackchannelAuthRequest(result, id)
+}
+
+// CheckBackchannelPollRate enforces the minimum polling interval for a backchannel
+// authentication request. Returns ErrSlowDown if the client polls too fast.
+func (s *DeviceCodeService) CheckBackchannelPollRate(ctx context.Context, authReqID string) error {
+	id := tokenDomain.HashToken(authReqID)
+	key := backchannelAuthKeyPrefix + id
+	pollKey := backchannelAuthKeyPrefix + "poll:" + id
+
+	result, err := s.redis.RunScript(ctx, checkAndUpdatePollRateScript, []string{key, pollKey},
+		fmt.Sprintf("%d", time.Now().Unix()),
+		fmt.Sprintf("%d", int(s.interval.Seconds())),
+	).Result()
+	if errors.Is(err, redis.Nil) || result == nil {
+		return domain.ErrBackchannelAuthRequestNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("check poll rate: %w", err)
+	}
+
+	if code, ok := result.(int64); ok && code == 0 {
+		return domain.ErrSlowDown
+	}
+	return nil
+}
+
+// ClaimAuthorizedBackchannelAuthRequest atomically validates that a request issued to clientID
+// has been approved and marks it as used, so tokens are only issued once for it.
+func (s *DeviceCodeService) ClaimAuthorizedBackchannelAuthRequest(ctx context.Context, authReqID, clientID string) (*domain.BackchannelAuthRequest, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("client_id is required for claiming backchannel authentication request")
+	}
+
+	id := tokenDomain.HashToken(authReqID)
+	result, err := s.redis.RunScript(ctx, claimAuthorizedScript, []string{backchannelAuthKeyPrefix + id}, clientID).Result()
+	if errors.Is(err, redis.Nil) || result == nil {
+		return nil, domain.ErrBackchannelAuthRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim backchannel authentication request: %w", err)
+	}
+	return decodeBackchannelAuthRequest(result, id)
+}
+
+// decodeBackchannelAuthRequest decodes the JSON returned by a pending-grant script.
+func decodeBackchannelAuthRequest(result any, id string) (*domain.BackchannelAuthRequest, error) {
+	data, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected backchannel authentication request data type")
+	}
+	var req domain.BackchannelAuthRequest
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		return nil, fmt.Errorf("unmarshal backchannel authentication request: %w", err)
+	}
+	req.ID = id
+	return &req, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+func newTestBackchannelAuthRequest(mode string) *domain.BackchannelAuthRequest {
+	return &domain.BackchannelAuthRequest{
+		ClientID:       "ciba-client",
+		AccountID:      "account-001",
+		Scopes:         []string{"openid", "profile"},
+		BindingMessage: "W4SCT",
+		DeliveryMode:   mode,
+	}
+}
+
+func TestBackchannelAuthRequest_CreateAndGet(t *testing.T) {
+	svc := setupDeviceCodeServiceBase(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateBackchannelAuthRequest(ctx, newTestBackchannelAuthRequest(domain.BackchannelTokenDeliveryPoll), 0)
+	require.NoError(t, err)
+	assert.Len(t, created.AuthReqID, 43)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, domain.DeviceCodeStatusPending, created.Status)
+	assert.Equal(t, 5, created.Interval)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), created.ExpiresAt, 5*time.Second)
+
+	fetched, err := svc.GetBackchannelAuthRequest(ctx, created.AuthReqID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, fetched.ID)
+	assert.Equal(t, "W4SCT", fetched.BindingMessage)
+	assert.Equal(t, []string{"openid", "profile"}, fetched.Scopes)
+	// Poll clients never need the raw auth_req_id back, so it is not stored.
+	assert.Empty(t, fetched.AuthReqID)
+
+	_, err = svc.GetBackchannelAuthRequest(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrBackchannelAuthRequestNotFound)
+}
+
+func TestBackchannelAuthRequest_PingKeepsAuthReqID(t *testing.T) {
+	svc := setupDeviceCodeServiceBase(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateBackchannelAuthRequest(ctx, newTestBackchannelAuthRequest(domain.BackchannelTokenDeliveryPing), time.Minute)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), created.ExpiresAt, 5*time.Second)
+
+	fetched, err := svc.GetBackchannelAuthRequestByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.AuthReqID, fetched.AuthReqID)
+}
+
+func TestBackchannelAuthRequest_CreateRequiresAccount(t *testing.T) {
+	svc := setupDeviceCodeServiceBase(t)
+
+	req := newTestBackchannelAuthRequest("")
+	req.AccountID = ""
+	_, err := svc.CreateBackchannelAuthRequest(context.Background(), req, 0)
+	assert.ErrorIs(t, err, domain.ErrDeviceAccountIDRequired)
+}
+
+func TestBackchannelAuthRequest_AuthorizeAndClaim(t *testing.T) {
+	svc := setupDeviceCodeServiceCJSON(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateBackchannelAuthRequest(ctx, newTestBackchannelAuthRequest(""), 0)
+	require.NoError(t, err)
+
+	_, err = svc.AuthorizeBackchannelAuthRequest(ctx, created.ID, "other-account")
+	assert.ErrorIs(t, err, domain.ErrBackchannelAuthRequestNotFound)
+
+	authorized, err := svc.AuthorizeBackchannelAuthRequest(ctx, created.ID, "account-001")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeviceCodeStatusAuthorized, authorized.Status)
+	assert.False(t, authorized.AuthorizedAt.IsZero())
+
+	_, err = svc.ClaimAuthorizedBackchannelAuthRequest(ctx, created.AuthReqID, "other-client")
+	assert.ErrorIs(t, err, domain.ErrBackchannelAuthRequestNotFound)
+
+	claimed, err := svc.ClaimAuthorizedBackchannelAuthRequest(ctx, created.AuthReqID, "ciba-client")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeviceCodeStatusUsed, claimed.Status)
+	assert.Equal(t, "account-001", claimed.AccountID)
+
+	_, err = svc.ClaimAuthorizedBackchannelAuthRequest(ctx, created.AuthReqID, "ciba-client")
+	assert.ErrorIs(t, err, domain.ErrBackchannelAuthRequestNotFound)
+}
+
+func TestBackchannelAuthRequest_Deny(t *testing.T) {
+	svc := setupDeviceCodeServiceCJSON(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateBackchannelAuthRequest(ctx, newTestBackchannelAuthRequest(""), 0)
+	require.NoError(t, err)
+
+	denied, err := svc.DenyBackchannelAuthRequest(ctx, created.ID, "account-001")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeviceCodeStatusDenied, denied.Status)
+
+	// A decided request cannot be approved afterwards.
+	_, err = svc.AuthorizeBackchannelAuthRequest(ctx, created.ID, "account-001")
+	assert.ErrorIs(t, err, domain.ErrBackchannelAuthRequestNotFound)
+}
+
+func TestBackchannelAuthRequest_PollRate(t *testing.T) {
+	svc := setupDeviceCodeServiceCJSON(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateBackchannelAuthRequest(ctx, newTestBackchannelAuthRequest(""), 0)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.CheckBackchannelPollRate(ctx, created.AuthReqID))
+	assert.ErrorIs(t, svc.CheckBackchannelPollRate(ctx, created.AuthReqID), domain.ErrSlowDown)
+	assert.ErrorIs(t, svc.CheckBackchannelPollRate(ctx, "unknown"), domain.ErrBackchannelAuthRequestNotFound)
+}
@@ -58,7 +58,8 @@ func NewDeviceCodeService(redis *cache.RedisClient, logger *zap.Logger, expiry,
 }
 
 // CreateDeviceCode generates a device code and user code, stores them in Redis.
-func (s *DeviceCodeService) CreateDeviceCode(ctx context.Context, clientID string, scopes []string) (*domain.DeviceCode, error) {
+// A positive lifetime, registered by the client, replaces the configured expiry.
+func (s *DeviceCodeService) CreateDeviceCode(ctx context.Context, clientID string, scopes []string, lifetime time.Duration) (*domain.DeviceCode, error) {
 	// Generate device code (32 random bytes → 64 hex chars)
 	dcBytes := make([]byte, deviceCodeLength)
 	if _, err := rand.Read(dcBytes); err != nil {
@@ -76,8 +77,11 @@ func (s *DeviceCodeService) CreateDeviceCode(ctx context.Context, clientID strin
 	}
 	formattedUserCode := userCode[:4] + "-" + userCode[4:]
 
+	if lifetime <= 0 {
+		lifetime = s.expiry
+	}
 	now := time.Now()
-	dc, err := domain.NewDeviceCode(deviceCodeStr, formattedUserCode, clientID, scopes, now.Add(s.expiry), int(s.interval.Seconds()))
+	dc, err := domain.NewDeviceCode(deviceCodeStr, formattedUserCode, clientID, scopes, now.Add(lifetime), int(s.interval.Seconds()))
 	if err != nil {
 		return nil, fmt.Errorf("create device code: %w", err)
 	}
@@ -95,7 +99,7 @@ func (s *DeviceCodeService) CreateDeviceCode(ctx context.Context, clientID strin
 	dcHash := tokenDomain.HashToken(deviceCodeStr)
 	dcKey := deviceCodeKeyPrefix + dcHash
 	ucKey := userCodeKeyPrefix + strings.ToUpper(formattedUserCode)
-	ttlSeconds := int(s.expiry.Seconds())
+	ttlSeconds := int(lifetime.Seconds())
 	if err := s.redis.RunScript(ctx, createDeviceCodeScript,
 		[]string{dcKey, ucKey},
 		string(data), ttlSeconds, dcHash,
@@ -43,7 +43,7 @@ func TestDeviceCodeService_CreateAndGet(t *testing.T) {
 	svc := setupDeviceCodeServiceBase(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid", "profile"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid", "profile"}, 0)
 	require.NoError(t, err)
 	require.NotNil(t, dc)
 
@@ -62,11 +62,19 @@ func TestDeviceCodeService_CreateAndGet(t *testing.T) {
 	assert.Equal(t, dc.UserCode, fetched.UserCode)
 }
 
+func TestDeviceCodeService_ClientLifetime(t *testing.T) {
+	svc := setupDeviceCodeServiceBase(t)
+
+	dc, err := svc.CreateDeviceCode(context.Background(), "test-client", []string{"openid"}, 2*time.Minute)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), dc.ExpiresAt, time.Second)
+}
+
 func TestDeviceCodeService_GetByUserCode(t *testing.T) {
 	svc := setupDeviceCodeServiceBase(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	fetched, err := svc.GetDeviceCodeByUserCode(ctx, dc.UserCode)
@@ -92,7 +100,7 @@ func TestDeviceCodeService_Authorize(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.AuthorizeDeviceCode(ctx, dc.DeviceCode, "account-123")
@@ -108,7 +116,7 @@ func TestDeviceCodeService_Deny(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.DenyDeviceCode(ctx, dc.DeviceCode)
@@ -123,7 +131,7 @@ func TestDeviceCodeService_PollRate(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	// First poll should succeed
@@ -193,7 +201,7 @@ func TestGetDeviceCode_CorruptData(t *testing.T) {
 	require.NoError(t, err)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	// Corrupt the stored device code data
@@ -231,7 +239,7 @@ func TestGetDeviceCodeByUserCode_CorruptDeviceCode(t *testing.T) {
 	require.NoError(t, err)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	// Corrupt the device code data (user code mapping still points to it)
@@ -280,7 +288,7 @@ func TestClaimAuthorizedDeviceCode_Success(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.AuthorizeDeviceCode(ctx, dc.DeviceCode, "account-123")
@@ -296,7 +304,7 @@ func TestClaimAuthorizedDeviceCode_AlreadyUsed(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.AuthorizeDeviceCode(ctx, dc.DeviceCode, "account-123")
@@ -314,7 +322,7 @@ func TestClaimAuthorizedDeviceCode_PendingNotClaimable(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	// Code is still pending — not yet authorized
@@ -326,7 +334,7 @@ func TestClaimAuthorizedDeviceCode_DeniedNotClaimable(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.DenyDeviceCode(ctx, dc.DeviceCode)
@@ -345,7 +353,7 @@ func TestAuthorizeDeviceCode_AlreadyDenied(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.DenyDeviceCode(ctx, dc.DeviceCode)
@@ -360,7 +368,7 @@ func TestDenyDeviceCode_AlreadyAuthorized(t *testing.T) {
 	svc := setupDeviceCodeServiceCJSON(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.AuthorizeDeviceCode(ctx, dc.DeviceCode, "account-123")
@@ -379,7 +387,7 @@ func TestClaimAuthorizedDeviceCode_ScriptError(t *testing.T) {
 	svc := setupDeviceCodeServiceBase(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	// claimAuthorizedScript uses cjson which fails on miniredis
@@ -392,7 +400,7 @@ func TestAuthorizeDeviceCode_ScriptError(t *testing.T) {
 	svc := setupDeviceCodeServiceBase(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.AuthorizeDeviceCode(ctx, dc.DeviceCode, "account-123")
@@ -403,7 +411,7 @@ func TestDenyDeviceCode_ScriptError(t *testing.T) {
 	svc := setupDeviceCodeServiceBase(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	err = svc.DenyDeviceCode(ctx, dc.DeviceCode)
@@ -414,7 +422,7 @@ func TestCheckAndUpdatePollRate_ScriptError(t *testing.T) {
 	svc := setupDeviceCodeServiceBase(t)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	// checkAndUpdatePollRateScript uses cjson which fails on miniredis
@@ -434,7 +442,7 @@ func TestCreateDeviceCode_RedisError(t *testing.T) {
 
 	mr.Close()
 
-	_, err = svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	_, err = svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "store device code")
 }
@@ -445,7 +453,7 @@ func TestGetDeviceCode_RedisError(t *testing.T) {
 	require.NoError(t, err)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	mr.Close()
@@ -461,7 +469,7 @@ func TestGetDeviceCodeByUserCode_ResolveError(t *testing.T) {
 	require.NoError(t, err)
 	ctx := context.Background()
 
-	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	dc, err := svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	mr.Close()
@@ -477,7 +485,7 @@ func TestAuthorizeDeviceCode_RedisDown(t *testing.T) {
 	require.NoError(t, err)
 	ctx := context.Background()
 
-	_, err = svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	_, err = svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	mr.Close()
@@ -492,7 +500,7 @@ func TestDenyDeviceCode_RedisDown(t *testing.T) {
 	require.NoError(t, err)
 	ctx := context.Background()
 
-	_, err = svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	_, err = svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	mr.Close()
@@ -507,7 +515,7 @@ func TestCheckAndUpdatePollRate_RedisDown(t *testing.T) {
 	require.NoError(t, err)
 	ctx := context.Background()
 
-	_, err = svc.CreateDeviceCode(ctx, "test-client", []string{"openid"})
+	_, err = svc.CreateDeviceCode(ctx, "test-client", []string{"openid"}, 0)
 	require.NoError(t, err)
 
 	mr.Close()
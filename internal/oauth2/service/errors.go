@@ -9,6 +9,64 @@ var (
 	// ErrInvalidClientSecret is returned when the client_secret does not match the stored hash.
 	ErrInvalidClientSecret = errors.New("invalid client_secret")
 
+	// ErrClientAssertionRequired is returned when a client registered for private_key_jwt or
+	// client_secret_jwt presents a client_secret instead of a client assertion.
+	ErrClientAssertionRequired = errors.New("client assertion required")
+
+	// ErrClientCertificateRequired is returned when a client registered for tls_client_auth or
+	// self_signed_tls_client_auth does not present a TLS client certificate (RFC 8705 §2).
+	ErrClientCertificateRequired = errors.New("client certificate required")
+
+	// ErrInvalidClientCertificate is returned when the TLS client certificate does not match
+	// the subject or keys the client registered (RFC 8705 §2.1, §2.2).
+	ErrInvalidClientCertificate = errors.New("invalid client certificate")
+
+	// ErrClientAuthMethodNotAllowed is returned when a client authenticates with a client
+	// assertion but is not registered for a JWT-based token_endpoint_auth_method.
+	ErrClientAuthMethodNotAllowed = errors.New("client authentication method not allowed")
+
+	// ErrInvalidClientAssertion is returned when a client assertion fails validation (RFC 7523 §3).
+	ErrInvalidClientAssertion = errors.New("invalid client assertion")
+
+	// ErrClientAssertionReplayed is returned when a client assertion's jti has already been used.
+	ErrClientAssertionReplayed = errors.New("client assertion replayed")
+
+	// ErrJWTBearerGrantUnavailable is returned when the jwt-bearer grant is used without
+	// auth.jwt_bearer_issuers configured.
+	ErrJWTBearerGrantUnavailable = errors.New("jwt bearer grant is not configured")
+
+	// ErrInvalidJWTBearerAssertion is returned when a JWT authorization grant fails
+	// validation (RFC 7523 §3) or its issuer or subject is not trusted.
+	ErrInvalidJWTBearerAssertion = errors.New("invalid jwt bearer assertion")
+
+	// ErrJWTBearerAssertionReplayed is returned when a JWT authorization grant's jti has already been used.
+	ErrJWTBearerAssertionReplayed = errors.New("jwt bearer assertion replayed")
+
+	// ErrJWTBearerReplayCheckUnavailable is returned when the jti of a JWT authorization grant
+	// cannot be recorded, so the assertion can be neither accepted nor rejected as a replay.
+	ErrJWTBearerReplayCheckUnavailable = errors.New("jwt bearer replay check unavailable")
+
+	// ErrInvalidInitialAccessToken is returned when a dynamic client registration request
+	// carries no initial access token, or one that is unknown, expired or used up (RFC 7591 §3).
+	ErrInvalidInitialAccessToken = errors.New("invalid initial access token")
+
+	// ErrInvalidRegistrationAccessToken is returned when a client configuration request does
+	// not carry the registration access token of the client it names (RFC 7592 §2). Unknown
+	// clients return the same error so the endpoint does not reveal which client IDs exist.
+	ErrInvalidRegistrationAccessToken = errors.New("invalid registration access token")
+
+	// ErrInvalidRequestObject is returned when a request object (RFC 9101) cannot be
+	// decrypted, is not signed with a key of the client, or carries invalid claims.
+	ErrInvalidRequestObject = errors.New("invalid request object")
+
+	// ErrInvalidRequestURI is returned when a request_uri is not registered for the client
+	// or the request object cannot be fetched from it (RFC 9101 §5.2.3).
+	ErrInvalidRequestURI = errors.New("invalid request_uri")
+
+	// ErrPairwiseSubjectsUnavailable is returned when a client registered for pairwise subject
+	// identifiers is used while auth.pairwise_subject_salt is not configured.
+	ErrPairwiseSubjectsUnavailable = errors.New("pairwise subject identifiers are not configured")
+
 	// ErrClientAccessDenied is returned when an account attempts to operate on a client they do not own.
 	ErrClientAccessDenied = errors.New("access denied: client does not belong to this account")
 
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+const (
+	// clientJWKSCacheTTL is how long a jwks_uri response is reused before refetching.
+	clientJWKSCacheTTL = 5 * time.Minute
+	// clientJWKSRefreshInterval rate-limits refetching a jwks_uri on an unknown kid,
+	// so a key holder rotating keys is picked up quickly without allowing fetch amplification.
+	clientJWKSRefreshInterval = 30 * time.Second
+	maxClientJWKSResponseSize = 64 * 1024
+)
+
+// jwksURICache fetches and caches remote JWK Sets by URI.
+// The zero value is ready to use and fetches with a 5 second timeout.
+type jwksURICache struct {
+	httpClient *http.Client
+	// parse selects the keys of a fetched set; nil selects signature keys.
+	parse func([]byte) ([]clientJWK, error)
+
+	mu      sync.Mutex
+	entries map[string]*cachedClientJWKS
+}
+
+type cachedClientJWKS struct {
+	keys      []clientJWK
+	fetchedAt time.Time
+}
+
+func newJWKSURICache(httpClient *http.Client) jwksURICache {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	return jwksURICache{httpClient: httpClient, entries: make(map[string]*cachedClientJWKS)}
+}
+
+// keys returns the keys published at jwksURI. forceRefresh refetches the set
+// unless it was fetched within clientJWKSRefreshInterval.
+func (c *jwksURICache) keys(ctx context.Context, jwksURI string, forceRefresh bool) ([]clientJWK, error) {
+	c.mu.Lock()
+	cached := c.entries[jwksURI]
+	c.mu.Unlock()
+	if cached != nil {
+		age := time.Since(cached.fetchedAt)
+		if age < clientJWKSCacheTTL && (!forceRefresh || age < clientJWKSRefreshInterval) {
+			return cached.keys, nil
+		}
+	}
+
+	keys, err := c.fetch(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]*cachedClientJWKS)
+	}
+	c.entries[jwksURI] = &cachedClientJWKS{keys: keys, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return keys, nil
+}
+
+func (c *jwksURICache) fetch(ctx context.Context, jwksURI string) ([]clientJWK, error) {
+	u, err := url.Parse(jwksURI)
+	if err != nil || (u.Scheme != "https" && !(u.Scheme == "http" && domain.IsLoopback(u.Hostname()))) {
+		return nil, errors.New("jwks_uri must use https")
+	}
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build jwks request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks_uri: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks_uri: unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxClientJWKSResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read jwks_uri response: %w", err)
+	}
+	if len(body) > maxClientJWKSResponseSize {
+		return nil, errors.New("jwks_uri response too large")
+	}
+	if c.parse != nil {
+		return c.parse(body)
+	}
+	return parseClientJWKS(body)
+}
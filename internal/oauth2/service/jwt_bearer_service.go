@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/rushairer/gosso/internal/cache"
+)
+
+// jwtBearerJTIKeyPrefix namespaces the replay-protection keys of JWT authorization grants.
+const jwtBearerJTIKeyPrefix = "jwt_bearer_jti:"
+
+// TrustedJWTIssuer is an external issuer whose JWTs are accepted as authorization grants
+// (RFC 7523 §2.1). Exactly one of JWKSURI and JWKS provides its verification keys.
+type TrustedJWTIssuer struct {
+	Issuer   string
+	JWKSURI  string
+	JWKS     []byte
+	Subjects []JWTBearerSubject
+}
+
+// JWTBearerSubject maps an assertion sub claim to the gosso account or client it acts as.
+// Exactly one of AccountID and ClientID is set. Scopes, when non-empty, caps the scopes
+// that may be granted for the subject.
+type JWTBearerSubject struct {
+	Subject   string
+	AccountID string
+	ClientID  string
+	Scopes    []string
+}
+
+// JWTBearerGrant is the result of a verified JWT authorization grant.
+type JWTBearerGrant struct {
+	Issuer    string
+	Subject   string
+	AccountID string
+	ClientID  string
+	Scopes    []string
+}
+
+type trustedJWTIssuer struct {
+	jwksURI  string
+	keys     []clientJWK
+	subjects map[string]JWTBearerSubject
+}
+
+// JWTBearerVerifier verifies JWT authorization grants from trusted issuers.
+type JWTBearerVerifier struct {
+	redis   *cache.RedisClient
+	issuers map[string]*trustedJWTIssuer
+	jwks    jwksURICache
+}
+
+// NewJWTBearerVerifier creates a verifier for the given trusted issuers. Redis is required
+// for jti replay protection. No issuers returns (nil, nil): the jwt-bearer grant is then
+// unavailable. A nil httpClient uses a 5 second timeout.
+func NewJWTBearerVerifier(redis *cache.RedisClient, issuers []TrustedJWTIssuer, httpClient *http.Client) (*JWTBearerVerifier, error) {
+	if len(issuers) == 0 {
+		return nil, nil
+	}
+	if redis == nil {
+		return nil, errors.New("jwt bearer verifier: redis client is required")
+	}
+	v := &JWTBearerVerifier{
+		redis:   redis,
+		issuers: make(map[string]*trustedJWTIssuer, len(issuers)),
+		jwks:    newJWKSURICache(httpClient),
+	}
+	for _, iss := range issuers {
+		if iss.Issuer == "" {
+			return nil, errors.New("trusted issuer must not be empty")
+		}
+		if _, dup := v.issuers[iss.Issuer]; dup {
+			return nil, fmt.Errorf("trusted issuer %q is configured more than once", iss.Issuer)
+		}
+		trusted, err := newTrustedJWTIssuer(iss.Issuer, iss.JWKSURI, iss.JWKS)
+		if err != nil {
+			return nil, err
+		}
+		trusted.subjects = make(map[string]JWTBearerSubject, len(iss.Subjects))
+		for _, sub := range iss.Subjects {
+			if sub.Subject == "" {
+				return nil, fmt.Errorf("trusted issuer %q: subject must not be empty", iss.Issuer)
+			}
+			if (sub.AccountID == "") == (sub.ClientID == "") {
+				return nil, fmt.Errorf("trusted issuer %q: subject %q must map to exactly one of account_id or client_id", iss.Issuer, sub.Subject)
+			}
+			trusted.subjects[sub.Subject] = sub
+		}
+		if len(trusted.subjects) == 0 {
+			return nil, fmt.Errorf("trusted issuer %q: at least one subject is required", iss.Issuer)
+		}
+		v.issuers[iss.Issuer] = trusted
+	}
+	return v, nil
+}
+
+// VerifyJWTBearer verifies a JWT authorization grant (RFC 7523 §3): iss must be a trusted
+// issuer, sub one of its allowed subjects, aud must contain one of audiences, and exp and
+// jti are required. Each jti is accepted only once per issuer while the assertion is valid.
+// A nil verifier returns ErrJWTBearerGrantUnavailable.
+func (v *JWTBearerVerifier) VerifyJWTBearer(ctx context.Context, assertion string, audiences []string) (*JWTBearerGrant, error) {
+	if v == nil {
+		return nil, ErrJWTBearerGrantUnavailable
+	}
+	unverified := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(assertion, unverified); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJWTBearerAssertion, err)
+	}
+	issuer, ok := v.issuers[unverified.Issuer]
+	if !ok {
+		return nil, fmt.Errorf("%w: untrusted issuer", ErrInvalidJWTBearerAssertion)
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(PrivateKeyJWTSigningAlgs),
+		jwt.WithIssuer(unverified.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clientAssertionLeeway),
+	)
+	claims := &jwt.RegisteredClaims{}
+	if _, err := parser.ParseWithClaims(assertion, claims, func(token *jwt.Token) (any, error) {
+		return issuer.verificationKey(ctx, &v.jwks, token)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJWTBearerAssertion, err)
+	}
+
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool { return slices.Contains(audiences, aud) }) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrInvalidJWTBearerAssertion)
+	}
+	subject, ok := issuer.subjects[claims.Subject]
+	if !ok {
+		return nil, fmt.Errorf("%w: subject not allowed", ErrInvalidJWTBearerAssertion)
+	}
+	if claims.ID == "" {
+		return nil, fmt.Errorf("%w: jti is required", ErrInvalidJWTBearerAssertion)
+	}
+	ttl := time.Until(claims.ExpiresAt.Time) + clientAssertionLeeway
+	if ttl > maxClientAssertionLifetime+clientAssertionLeeway {
+		return nil, fmt.Errorf("%w: exp is too far in the future", ErrInvalidJWTBearerAssertion)
+	}
+
+	// Hash the jti so attacker-controlled values never become raw Redis keys.
+	sum := sha256.Sum256([]byte(claims.Issuer + "\x00" + claims.ID))
+	fresh, err := v.redis.SetNX(ctx, jwtBearerJTIKeyPrefix+hex.EncodeToString(sum[:]), "1", ttl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWTBearerReplayCheckUnavailable, err)
+	}
+	if !fresh {
+		return nil, ErrJWTBearerAssertionReplayed
+	}
+
+	return &JWTBearerGrant{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		AccountID: subject.AccountID,
+		ClientID:  subject.ClientID,
+		Scopes:    subject.Scopes,
+	}, nil
+}
+
+// newTrustedJWTIssuer loads the verification keys of an external JWT issuer from exactly one
+// of an inline JWK Set or a jwks_uri, which is fetched on first use.
+func newTrustedJWTIssuer(issuer, jwksURI string, jwks []byte) (*trustedJWTIssuer, error) {
+	trusted := &trustedJWTIssuer{}
+	switch {
+	case len(jwks) > 0 && jwksURI != "":
+		return nil, fmt.Errorf("trusted issuer %q: jwks and jwks_uri are mutually exclusive", issuer)
+	case len(jwks) > 0:
+		keys, err := parseClientJWKS(jwks)
+		if err != nil {
+			return nil, fmt.Errorf("trusted issuer %q: %w", issuer, err)
+		}
+		trusted.keys = keys
+	case jwksURI != "":
+		if u, err := url.Parse(jwksURI); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("trusted issuer %q: invalid jwks_uri", issuer)
+		}
+		trusted.jwksURI = jwksURI
+	default:
+		return nil, fmt.Errorf("trusted issuer %q: jwks or jwks_uri is required", issuer)
+	}
+	return trusted, nil
+}
+
+// verificationKey returns the issuer key(s) that may have signed token, fetching
+// jwks_uri keys through jwks.
+func (issuer *trustedJWTIssuer) verificationKey(ctx context.Context, jwks *jwksURICache, token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if issuer.jwksURI == "" {
+		if matched := matchClientJWKs(issuer.keys, kid, token.Method); len(matched) > 0 {
+			return jwt.VerificationKeySet{Keys: matched}, nil
+		}
+		return nil, errors.New("no matching verification key")
+	}
+
+	keys, err := jwks.keys(ctx, issuer.jwksURI, false)
+	if err != nil {
+		return nil, err
+	}
+	matched := matchClientJWKs(keys, kid, token.Method)
+	if len(matched) == 0 {
+		// The issuer may have rotated keys since the set was cached.
+		if keys, err = jwks.keys(ctx, issuer.jwksURI, true); err != nil {
+			return nil, err
+		}
+		matched = matchClientJWKs(keys, kid, token.Method)
+	}
+	if len(matched) == 0 {
+		return nil, errors.New("no matching verification key")
+	}
+	return jwt.VerificationKeySet{Keys: matched}, nil
+}
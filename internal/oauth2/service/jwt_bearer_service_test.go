@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/testutil"
+)
+
+const testWorkloadIssuer = "https://workload.example.com"
+
+func jwtBearerClaims(subject string) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Issuer:    testWorkloadIssuer,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{testTokenEndpoint},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		ID:        uuid.NewString(),
+	}
+}
+
+func setupJWTBearerVerifier(t *testing.T) (*JWTBearerVerifier, *rsa.PrivateKey) {
+	t.Helper()
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	v, err := NewJWTBearerVerifier(redisClient, []TrustedJWTIssuer{{
+		Issuer: testWorkloadIssuer,
+		JWKS:   rsaJWKS(t, "w1", &key.PublicKey),
+		Subjects: []JWTBearerSubject{
+			{Subject: "batch-job", ClientID: "batch-client", Scopes: []string{"reports:read"}},
+			{Subject: "sync-job", AccountID: "acc-001"},
+		},
+	}}, nil)
+	require.NoError(t, err)
+	return v, key
+}
+
+func TestNewJWTBearerVerifier_NoIssuers(t *testing.T) {
+	v, err := NewJWTBearerVerifier(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	_, err = v.VerifyJWTBearer(context.Background(), "x.y.z", []string{testTokenEndpoint})
+	assert.ErrorIs(t, err, ErrJWTBearerGrantUnavailable)
+}
+
+func TestNewJWTBearerVerifier_InvalidConfig(t *testing.T) {
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	subjects := []JWTBearerSubject{{Subject: "job", ClientID: "cid"}}
+
+	tests := []struct {
+		name   string
+		issuer TrustedJWTIssuer
+	}{
+		{"no keys", TrustedJWTIssuer{Issuer: testWorkloadIssuer, Subjects: subjects}},
+		{"both jwks and jwks_uri", TrustedJWTIssuer{Issuer: testWorkloadIssuer, JWKS: []byte(`{"keys":[]}`), JWKSURI: "https://workload.example.com/jwks", Subjects: subjects}},
+		{"invalid jwks", TrustedJWTIssuer{Issuer: testWorkloadIssuer, JWKS: []byte(`{"keys":[]}`), Subjects: subjects}},
+		{"no subjects", TrustedJWTIssuer{Issuer: testWorkloadIssuer, JWKSURI: "https://workload.example.com/jwks"}},
+		{"subject mapped twice", TrustedJWTIssuer{Issuer: testWorkloadIssuer, JWKSURI: "https://workload.example.com/jwks", Subjects: []JWTBearerSubject{{Subject: "job", ClientID: "cid", AccountID: "acc"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJWTBearerVerifier(redisClient, []TrustedJWTIssuer{tt.issuer}, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJWTBearerVerifier_Verify(t *testing.T) {
+	v, key := setupJWTBearerVerifier(t)
+
+	assertion := signAssertion(t, jwt.SigningMethodRS256, key, "w1", jwtBearerClaims("batch-job"))
+	grant, err := v.VerifyJWTBearer(context.Background(), assertion, []string{testTokenEndpoint})
+	require.NoError(t, err)
+	assert.Equal(t, testWorkloadIssuer, grant.Issuer)
+	assert.Equal(t, "batch-job", grant.Subject)
+	assert.Equal(t, "batch-client", grant.ClientID)
+	assert.Empty(t, grant.AccountID)
+	assert.Equal(t, []string{"reports:read"}, grant.Scopes)
+
+	// RFC 7523 §3: the same jti must not be accepted twice.
+	_, err = v.VerifyJWTBearer(context.Background(), assertion, []string{testTokenEndpoint})
+	assert.ErrorIs(t, err, ErrJWTBearerAssertionReplayed)
+
+	grant, err = v.VerifyJWTBearer(context.Background(), signAssertion(t, jwt.SigningMethodRS256, key, "w1", jwtBearerClaims("sync-job")), []string{testTokenEndpoint})
+	require.NoError(t, err)
+	assert.Equal(t, "acc-001", grant.AccountID)
+	assert.Empty(t, grant.ClientID)
+}
+
+func TestJWTBearerVerifier_Invalid(t *testing.T) {
+	v, key := setupJWTBearerVerifier(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		key    *rsa.PrivateKey
+		mutate func(*jwt.RegisteredClaims)
+	}{
+		{"untrusted issuer", key, func(c *jwt.RegisteredClaims) { c.Issuer = "https://evil.example.com" }},
+		{"unknown subject", key, func(c *jwt.RegisteredClaims) { c.Subject = "someone-else" }},
+		{"wrong audience", key, func(c *jwt.RegisteredClaims) { c.Audience = jwt.ClaimStrings{"https://other.example.com"} }},
+		{"missing exp", key, func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil }},
+		{"expired", key, func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }},
+		{"exp too far in the future", key, func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour)) }},
+		{"not yet valid", key, func(c *jwt.RegisteredClaims) { c.NotBefore = jwt.NewNumericDate(time.Now().Add(5 * time.Minute)) }},
+		{"missing jti", key, func(c *jwt.RegisteredClaims) { c.ID = "" }},
+		{"untrusted key", otherKey, func(*jwt.RegisteredClaims) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwtBearerClaims("batch-job")
+			tt.mutate(&claims)
+			assertion := signAssertion(t, jwt.SigningMethodRS256, tt.key, "w1", claims)
+			_, err := v.VerifyJWTBearer(context.Background(), assertion, []string{testTokenEndpoint})
+			assert.ErrorIs(t, err, ErrInvalidJWTBearerAssertion)
+		})
+	}
+}
+
+func TestJWTBearerVerifier_ReplayCheckUnavailable(t *testing.T) {
+	redisClient, mr := testutil.SetupTestRedis(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	v, err := NewJWTBearerVerifier(redisClient, []TrustedJWTIssuer{{
+		Issuer:   testWorkloadIssuer,
+		JWKS:     rsaJWKS(t, "w1", &key.PublicKey),
+		Subjects: []JWTBearerSubject{{Subject: "batch-job", ClientID: "batch-client"}},
+	}}, nil)
+	require.NoError(t, err)
+	mr.Close()
+
+	assertion := signAssertion(t, jwt.SigningMethodRS256, key, "w1", jwtBearerClaims("batch-job"))
+	_, err = v.VerifyJWTBearer(context.Background(), assertion, []string{testTokenEndpoint})
+	assert.ErrorIs(t, err, ErrJWTBearerReplayCheckUnavailable)
+	assert.NotErrorIs(t, err, ErrInvalidJWTBearerAssertion)
+}
+
+func TestJWTBearerVerifier_RejectsHMAC(t *testing.T) {
+	v, _ := setupJWTBearerVerifier(t)
+	assertion := signAssertion(t, jwt.SigningMethodHS256, []byte("public-key-bytes"), "w1", jwtBearerClaims("batch-job"))
+	_, err := v.VerifyJWTBearer(context.Background(), assertion, []string{testTokenEndpoint})
+	assert.ErrorIs(t, err, ErrInvalidJWTBearerAssertion)
+}
+
+func TestJWTBearerVerifier_JWKSURI(t *testing.T) {
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var fetches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(ecJWKS(t, "ec1", &key.PublicKey))
+	}))
+	t.Cleanup(srv.Close)
+
+	v, err := NewJWTBearerVerifier(redisClient, []TrustedJWTIssuer{{
+		Issuer:   testWorkloadIssuer,
+		JWKSURI:  srv.URL + "/jwks.json",
+		Subjects: []JWTBearerSubject{{Subject: "batch-job", ClientID: "batch-client"}},
+	}}, srv.Client())
+	require.NoError(t, err)
+
+	for range 2 {
+		assertion := signAssertion(t, jwt.SigningMethodES256, key, "ec1", jwtBearerClaims("batch-job"))
+		_, err := v.VerifyJWTBearer(context.Background(), assertion, []string{testTokenEndpoint})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, fetches, "jwks_uri response should be cached")
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/rushairer/gosso/internal/jose"
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// maxRequestObjectSize bounds the request parameter before any parsing or decryption.
+const maxRequestObjectSize = 32 * 1024
+
+// RequestObjectSigningAlgs are the JWS algorithms accepted for request objects (RFC 9101 §4).
+// Like client assertions, HS* request objects are keyed with the client secret and are
+// therefore only accepted from client_secret_jwt clients. "none" is never accepted.
+var RequestObjectSigningAlgs = slices.Concat(ClientSecretJWTSigningAlgs, PrivateKeyJWTSigningAlgs)
+
+// RequestObjectVerifier verifies JWT-secured authorization requests (RFC 9101) against
+// the keys a client registered for client authentication.
+type RequestObjectVerifier struct {
+	clientAuth    *ClientAuthenticator
+	issuer        string
+	decryptionKey *rsa.PrivateKey
+	httpClient    *http.Client
+}
+
+// NewRequestObjectVerifier creates a verifier that requires request objects to be addressed
+// to issuer. decryptionKey decrypts request objects encrypted to this server (RFC 9101 §6.1);
+// nil rejects encrypted request objects. httpClient fetches request objects passed by
+// reference; nil uses NewClientURLHTTPClient.
+func NewRequestObjectVerifier(clientAuth *ClientAuthenticator, issuer string, decryptionKey *rsa.PrivateKey, httpClient *http.Client) *RequestObjectVerifier {
+	if httpClient == nil {
+		httpClient = NewClientURLHTTPClient()
+	}
+	return &RequestObjectVerifier{clientAuth: clientAuth, issuer: issuer, decryptionKey: decryptionKey, httpClient: httpClient}
+}
+
+// FetchRequestObject retrieves the request object that client passed by reference
+// (RFC 9101 §5.2). requestURI must match one of the client's registered request_uris,
+// ignoring the fragment, so the server only ever fetches URLs the client registered.
+// The returned object still has to be verified with VerifyRequestObject.
+func (v *RequestObjectVerifier) FetchRequestObject(ctx context.Context, client *domain.OAuth2Client, requestURI string) (string, error) {
+	registered, _, _ := strings.Cut(requestURI, "#")
+	if !slices.Contains(client.RequestURIs, registered) {
+		return "", fmt.Errorf("%w: not registered for the client", ErrInvalidRequestURI)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registered, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidRequestURI, err)
+	}
+	req.Header.Set("Accept", "application/oauth-authz-req+jwt")
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidRequestURI, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: unexpected status %d", ErrInvalidRequestURI, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRequestObjectSize+1))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidRequestURI, err)
+	}
+	if len(body) > maxRequestObjectSize {
+		return "", fmt.Errorf("%w: request object too large", ErrInvalidRequestURI)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// VerifyRequestObject decrypts requestObject if needed, verifies its signature with the
+// keys of client and returns the authorization request parameters it carries. iss must be
+// the client_id and aud must contain the issuer (RFC 9101 §4); exp and nbf are enforced
+// when present. Non-string claim values are returned in their JSON encoding.
+func (v *RequestObjectVerifier) VerifyRequestObject(ctx context.Context, client *domain.OAuth2Client, requestObject string) (url.Values, error) {
+	if len(requestObject) > maxRequestObjectSize {
+		return nil, fmt.Errorf("%w: request object too large", ErrInvalidRequestObject)
+	}
+	if jose.IsJWE(requestObject) {
+		if v.decryptionKey == nil {
+			return nil, fmt.Errorf("%w: encrypted request objects are not supported", ErrInvalidRequestObject)
+		}
+		plaintext, _, err := jose.Decrypt(requestObject, v.decryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRequestObject, err)
+		}
+		// The payload of an encrypted request object must itself be a signed JWT.
+		requestObject = string(plaintext)
+		if jose.IsJWE(requestObject) {
+			return nil, fmt.Errorf("%w: nested encryption is not supported", ErrInvalidRequestObject)
+		}
+	}
+
+	algs := PrivateKeyJWTSigningAlgs
+	if client.TokenEndpointAuthMethod == domain.AuthMethodClientSecretJWT {
+		algs = ClientSecretJWTSigningAlgs
+	}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(algs),
+		jwt.WithIssuer(client.ClientID),
+		jwt.WithAudience(v.issuer),
+		jwt.WithLeeway(clientAssertionLeeway),
+		jwt.WithJSONNumber(),
+	)
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(requestObject, claims, func(token *jwt.Token) (any, error) {
+		return v.clientAuth.assertionVerificationKey(ctx, client, token)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequestObject, err)
+	}
+
+	// RFC 9101 §4: request objects must not nest request or request_uri.
+	if _, ok := claims["request"]; ok {
+		return nil, fmt.Errorf("%w: request object must not contain request", ErrInvalidRequestObject)
+	}
+	if _, ok := claims["request_uri"]; ok {
+		return nil, fmt.Errorf("%w: request object must not contain request_uri", ErrInvalidRequestObject)
+	}
+	if clientID, ok := claims["client_id"]; ok && clientID != client.ClientID {
+		return nil, fmt.Errorf("%w: client_id does not match the client", ErrInvalidRequestObject)
+	}
+
+	params := url.Values{}
+	for name, value := range claims {
+		// The resource parameter may be repeated (RFC 8707 §2), so it may be an array here.
+		if resources, ok := value.([]any); ok && name == "resource" {
+			for _, resource := range resources {
+				s, ok := resource.(string)
+				if !ok {
+					return nil, fmt.Errorf("%w: invalid resource: not a string", ErrInvalidRequestObject)
+				}
+				params.Add(name, s)
+			}
+			continue
+		}
+		s, err := requestObjectParamValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s: %v", ErrInvalidRequestObject, name, err)
+		}
+		params.Set(name, s)
+	}
+	return params, nil
+}
+
+// requestObjectParamValue converts a claim value to its authorization request parameter
+// form: strings as is, numbers and booleans in decimal, objects and arrays as JSON.
+func requestObjectParamValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "", errors.New("null value")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
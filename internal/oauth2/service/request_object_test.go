@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/jose"
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+const testRequestObjectIssuer = "https://sso.example.com"
+
+func requestObjectClaims(clientID string, extra jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":           clientID,
+		"aud":           testRequestObjectIssuer,
+		"exp":           time.Now().Add(time.Minute).Unix(),
+		"client_id":     clientID,
+		"response_type": "code",
+		"redirect_uri":  "https://app.example.com/callback",
+		"scope":         "openid profile",
+		"state":         "signed-state",
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+func signRequestObject(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestRequestObjectVerifier_PrivateKeyJWT(t *testing.T) {
+	auth, client, key := setupPrivateKeyJWTClient(t)
+	v := NewRequestObjectVerifier(auth, testRequestObjectIssuer, nil, nil)
+
+	request := signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{
+		"max_age":  300,
+		"claims":   map[string]any{"id_token": map[string]any{"acr": nil}},
+		"resource": []string{"https://api.example.com/orders", "https://api.example.com/billing"},
+	}))
+	params, err := v.VerifyRequestObject(context.Background(), client, request)
+	require.NoError(t, err)
+	assert.Equal(t, "code", params.Get("response_type"))
+	assert.Equal(t, "openid profile", params.Get("scope"))
+	assert.Equal(t, "signed-state", params.Get("state"))
+	assert.Equal(t, "300", params.Get("max_age"))
+	assert.JSONEq(t, `{"id_token":{"acr":null}}`, params.Get("claims"))
+	assert.Equal(t, []string{"https://api.example.com/orders", "https://api.example.com/billing"}, params["resource"])
+}
+
+func TestRequestObjectVerifier_Rejects(t *testing.T) {
+	auth, client, key := setupPrivateKeyJWTClient(t)
+	v := NewRequestObjectVerifier(auth, testRequestObjectIssuer, nil, nil)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		request string
+	}{
+		{"unknown key", signRequestObject(t, jwt.SigningMethodRS256, other, "k1", requestObjectClaims(client.ClientID, nil))},
+		{"wrong issuer", signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{"iss": "other"}))},
+		{"wrong audience", signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{"aud": "https://other.example.com"}))},
+		{"missing audience", signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{"aud": nil}))},
+		{"expired", signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}))},
+		{"client_id mismatch", signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{"client_id": "other"}))},
+		{"nested request", signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{"request": "x"}))},
+		{"nested request_uri", signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{"request_uri": "x"}))},
+		{"HMAC for key client", signRequestObject(t, jwt.SigningMethodHS256, []byte("secret"), "", requestObjectClaims(client.ClientID, nil))},
+		{"unsigned", signRequestObject(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", requestObjectClaims(client.ClientID, nil))},
+		{"resource not a string", signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, jwt.MapClaims{"resource": []any{"https://api.example.com/orders", 1}}))},
+		{"too large", strings.Repeat("a", maxRequestObjectSize+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.VerifyRequestObject(context.Background(), client, tt.request)
+			assert.ErrorIs(t, err, ErrInvalidRequestObject)
+		})
+	}
+}
+
+func TestRequestObjectVerifier_ClientSecretJWT(t *testing.T) {
+	cipher, err := NewClientSecretCipher("a9fd7106e9647479494fddf8a850b6d9a09114c0afa81e77385686f5455e7270")
+	require.NoError(t, err)
+	encrypted, err := cipher.Encrypt("csjwt-client", "shared-secret-value-with-enough-entropy")
+	require.NoError(t, err)
+	client := &domain.OAuth2Client{
+		ClientID:                "csjwt-client",
+		IsConfidential:          true,
+		TokenEndpointAuthMethod: domain.AuthMethodClientSecretJWT,
+		ClientSecretEncrypted:   encrypted,
+	}
+	v := NewRequestObjectVerifier(NewClientAuthenticator(nil, cipher, nil), testRequestObjectIssuer, nil, nil)
+
+	request := signRequestObject(t, jwt.SigningMethodHS256, []byte("shared-secret-value-with-enough-entropy"), "", requestObjectClaims(client.ClientID, nil))
+	params, err := v.VerifyRequestObject(context.Background(), client, request)
+	require.NoError(t, err)
+	assert.Equal(t, "signed-state", params.Get("state"))
+
+	wrong := signRequestObject(t, jwt.SigningMethodHS256, []byte("wrong-secret"), "", requestObjectClaims(client.ClientID, nil))
+	_, err = v.VerifyRequestObject(context.Background(), client, wrong)
+	assert.ErrorIs(t, err, ErrInvalidRequestObject)
+}
+
+func TestRequestObjectVerifier_Encrypted(t *testing.T) {
+	auth, client, key := setupPrivateKeyJWTClient(t)
+	serverKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signed := signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, nil))
+	encrypted, err := jose.Encrypt([]byte(signed), &serverKey.PublicKey, jose.JWEHeader{Alg: jose.KeyAlgRSAOAEP256, Enc: "A256GCM", Cty: "JWT"})
+	require.NoError(t, err)
+
+	params, err := NewRequestObjectVerifier(auth, testRequestObjectIssuer, serverKey, nil).VerifyRequestObject(context.Background(), client, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "signed-state", params.Get("state"))
+
+	// Without a decryption key encrypted request objects are rejected.
+	_, err = NewRequestObjectVerifier(auth, testRequestObjectIssuer, nil, nil).VerifyRequestObject(context.Background(), client, encrypted)
+	assert.ErrorIs(t, err, ErrInvalidRequestObject)
+}
+
+func TestRequestObjectVerifier_FetchRequestObject(t *testing.T) {
+	auth, client, key := setupPrivateKeyJWTClient(t)
+	signed := signRequestObject(t, jwt.SigningMethodRS256, key, "k1", requestObjectClaims(client.ClientID, nil))
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/request.jwt":
+			assert.Equal(t, "application/oauth-authz-req+jwt", r.Header.Get("Accept"))
+			_, _ = w.Write([]byte(signed + "\n"))
+		case "/large.jwt":
+			_, _ = w.Write([]byte(strings.Repeat("a", maxRequestObjectSize+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client.RequestURIs = []string{server.URL + "/request.jwt", server.URL + "/large.jwt", server.URL + "/missing.jwt"}
+	v := NewRequestObjectVerifier(auth, testRequestObjectIssuer, nil, server.Client())
+
+	// The fragment is ignored when matching registered URIs (RFC 9101 §5.2).
+	requestObject, err := v.FetchRequestObject(context.Background(), client, server.URL+"/request.jwt#v2")
+	require.NoError(t, err)
+	assert.Equal(t, signed, requestObject)
+
+	for _, uri := range []string{server.URL + "/other.jwt", server.URL + "/large.jwt", server.URL + "/missing.jwt"} {
+		_, err = v.FetchRequestObject(context.Background(), client, uri)
+		assert.ErrorIs(t, err, ErrInvalidRequestURI, uri)
+	}
+
+	// By default request objects are never fetched from non-public addresses.
+	_, err = NewRequestObjectVerifier(auth, testRequestObjectIssuer, nil, nil).FetchRequestObject(context.Background(), client, server.URL+"/request.jwt")
+	assert.ErrorIs(t, err, ErrInvalidRequestURI)
+	assert.Contains(t, err.Error(), "non-public address")
+}
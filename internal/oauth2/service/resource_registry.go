@@ -0,0 +1,122 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// ResourceRegistry holds the protected resources that access tokens can be restricted to
+// with resource indicators (RFC 8707). A nil *ResourceRegistry has no resources registered.
+type ResourceRegistry struct {
+	resources map[string]domain.ProtectedResource
+}
+
+// NewResourceRegistry creates a registry of resources. Later entries replace earlier ones
+// with the same identifier.
+func NewResourceRegistry(resources []domain.ProtectedResource) *ResourceRegistry {
+	r := &ResourceRegistry{resources: make(map[string]domain.ProtectedResource, len(resources))}
+	for _, res := range resources {
+		r.resources[res.Identifier] = res
+	}
+	return r
+}
+
+// Identifiers returns the identifiers of the registered resources in sorted order.
+func (r *ResourceRegistry) Identifiers() []string {
+	if r == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(r.resources))
+	for id := range r.resources {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Lookup returns the registered resource with identifier.
+func (r *ResourceRegistry) Lookup(identifier string) (domain.ProtectedResource, bool) {
+	if r == nil {
+		return domain.ProtectedResource{}, false
+	}
+	res, ok := r.resources[identifier]
+	return res, ok
+}
+
+// IsResourceServer reports whether clientID is registered for one of the resources in
+// audience, and so may introspect tokens issued for it.
+func (r *ResourceRegistry) IsResourceServer(clientID string, audience []string) bool {
+	return slices.ContainsFunc(audience, func(identifier string) bool {
+		res, ok := r.Lookup(identifier)
+		return ok && slices.Contains(res.ClientIDs, clientID)
+	})
+}
+
+// Validate checks that every resource indicator is well formed and registered. It returns
+// domain.ErrInvalidTarget otherwise.
+func (r *ResourceRegistry) Validate(resources []string) error {
+	for _, id := range resources {
+		if !domain.IsValidResourceIndicator(id) {
+			return domain.ErrInvalidTarget
+		}
+		if _, ok := r.Lookup(id); !ok {
+			return domain.ErrInvalidTarget
+		}
+	}
+	return nil
+}
+
+// Target returns the audience, lifetime and profile of an access token for the registered
+// resources among identifiers, skipping the others, or nil when none is registered. Unlike
+// Resolve it leaves scopes alone; token exchange uses it to find the registered resources
+// among its audiences, which need not all be registered.
+func (r *ResourceRegistry) Target(identifiers []string) *domain.ResourceTarget {
+	var target *domain.ResourceTarget
+	for _, id := range identifiers {
+		res, ok := r.Lookup(id)
+		if !ok || (target != nil && slices.Contains(target.Audience, id)) {
+			continue
+		}
+		if target == nil {
+			target = &domain.ResourceTarget{}
+		}
+		target.Audience = append(target.Audience, id)
+		if res.AccessTokenExpiry > 0 && (target.Lifetime == 0 || res.AccessTokenExpiry < target.Lifetime) {
+			target.Lifetime = res.AccessTokenExpiry
+		}
+		if res.AccessTokenProfile == domain.AccessTokenProfileRFC9068 {
+			target.AccessTokenProfile = domain.AccessTokenProfileRFC9068
+		}
+	}
+	return target
+}
+
+// Resolve returns the target of an access token for resources carrying scopes. The token
+// keeps only the scopes allowed for at least one of the resources and lives as long as the
+// shortest-lived resource allows, in the RFC 9068 profile if any resource requires it. It
+// returns domain.ErrInvalidTarget for an unknown resource and
+// domain.ErrResourceScopeNotAllowed when no scope remains.
+func (r *ResourceRegistry) Resolve(resources, scopes []string) (*domain.ResourceTarget, error) {
+	if err := r.Validate(resources); err != nil {
+		return nil, err
+	}
+	target := r.Target(resources)
+	if target == nil {
+		return nil, domain.ErrResourceScopeNotAllowed
+	}
+	var allowed []string
+	for _, id := range target.Audience {
+		res, _ := r.Lookup(id)
+		allowed = append(allowed, res.Scopes...)
+	}
+	for _, scope := range scopes {
+		if slices.Contains(allowed, scope) && !slices.Contains(target.Scopes, scope) {
+			target.Scopes = append(target.Scopes, scope)
+		}
+	}
+	if len(target.Scopes) == 0 {
+		return nil, domain.ErrResourceScopeNotAllowed
+	}
+	return target, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+func newTestResourceRegistry() *ResourceRegistry {
+	return NewResourceRegistry([]domain.ProtectedResource{
+		{Identifier: "https://api.example.com/orders", Scopes: []string{"orders:read", "orders:write"}, AccessTokenExpiry: 10 * time.Minute, ClientIDs: []string{"orders-api"}},
+		{Identifier: "https://api.example.com/billing", Scopes: []string{"billing:read"}, AccessTokenExpiry: 5 * time.Minute},
+		{Identifier: "urn:example:reports", Scopes: []string{"reports:read"}, AccessTokenProfile: domain.AccessTokenProfileRFC9068},
+	})
+}
+
+func TestResourceRegistry_Resolve(t *testing.T) {
+	registry := newTestResourceRegistry()
+
+	target, err := registry.Resolve([]string{"https://api.example.com/orders"}, []string{"openid", "orders:read", "billing:read"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://api.example.com/orders"}, target.Audience)
+	assert.Equal(t, []string{"orders:read"}, target.Scopes)
+	assert.Equal(t, 10*time.Minute, target.Lifetime)
+
+	target, err = registry.Resolve([]string{"https://api.example.com/orders", "https://api.example.com/billing", "https://api.example.com/orders"}, []string{"orders:read", "billing:read"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://api.example.com/orders", "https://api.example.com/billing"}, target.Audience)
+	assert.Equal(t, []string{"orders:read", "billing:read"}, target.Scopes)
+	assert.Equal(t, 5*time.Minute, target.Lifetime, "the shortest resource lifetime wins")
+	assert.Empty(t, target.AccessTokenProfile)
+
+	target, err = registry.Resolve([]string{"urn:example:reports"}, []string{"reports:read"})
+	require.NoError(t, err)
+	assert.Zero(t, target.Lifetime, "no configured lifetime uses the default")
+	assert.Equal(t, domain.AccessTokenProfileRFC9068, target.AccessTokenProfile)
+}
+
+func TestResourceRegistry_ResolveErrors(t *testing.T) {
+	registry := newTestResourceRegistry()
+
+	_, err := registry.Resolve([]string{"https://api.example.com/unknown"}, []string{"orders:read"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTarget)
+	_, err = registry.Resolve([]string{"/orders"}, []string{"orders:read"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTarget)
+	_, err = registry.Resolve([]string{"https://api.example.com/orders#frag"}, []string{"orders:read"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTarget)
+	_, err = registry.Resolve([]string{"https://api.example.com/orders"}, []string{"billing:read"})
+	assert.ErrorIs(t, err, domain.ErrResourceScopeNotAllowed)
+
+	var empty *ResourceRegistry
+	assert.Empty(t, empty.Identifiers())
+	_, err = empty.Resolve([]string{"https://api.example.com/orders"}, []string{"orders:read"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTarget)
+}
+
+func TestResourceRegistry_Target(t *testing.T) {
+	registry := newTestResourceRegistry()
+
+	target := registry.Target([]string{"orders-api", "https://api.example.com/orders", "https://api.example.com/billing", "urn:example:reports"})
+	require.NotNil(t, target)
+	assert.Equal(t, []string{"https://api.example.com/orders", "https://api.example.com/billing", "urn:example:reports"}, target.Audience)
+	assert.Equal(t, 5*time.Minute, target.Lifetime)
+	assert.Equal(t, domain.AccessTokenProfileRFC9068, target.AccessTokenProfile)
+	assert.Empty(t, target.Scopes)
+
+	assert.Nil(t, registry.Target([]string{"orders-api"}))
+	var empty *ResourceRegistry
+	assert.Nil(t, empty.Target([]string{"https://api.example.com/orders"}))
+}
+
+func TestResourceRegistry_IsResourceServer(t *testing.T) {
+	registry := newTestResourceRegistry()
+
+	assert.True(t, registry.IsResourceServer("orders-api", []string{"https://api.example.com/billing", "https://api.example.com/orders"}))
+	assert.False(t, registry.IsResourceServer("orders-api", []string{"https://api.example.com/billing"}))
+	assert.False(t, registry.IsResourceServer("billing-api", []string{"https://api.example.com/orders"}))
+	assert.False(t, registry.IsResourceServer("orders-api", nil))
+
+	var empty *ResourceRegistry
+	assert.False(t, empty.IsResourceServer("orders-api", []string{"https://api.example.com/orders"}))
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/rushairer/gosso/internal/jose"
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// ResponseEncrypter encrypts ID tokens and UserInfo responses to the encryption key a
+// client registered in its jwks or jwks_uri (OpenID Connect Core §10.2). Only RSA keys are
+// used, with the key management algorithms in jose.KeyEncryptionAlgs.
+type ResponseEncrypter struct {
+	jwks jwksURICache
+}
+
+// NewResponseEncrypter creates a response encrypter. httpClient fetches client jwks_uri
+// documents; nil uses NewClientURLHTTPClient.
+func NewResponseEncrypter(httpClient *http.Client) *ResponseEncrypter {
+	if httpClient == nil {
+		httpClient = NewClientURLHTTPClient()
+	}
+	e := &ResponseEncrypter{jwks: newJWKSURICache(httpClient)}
+	e.jwks.parse = parseClientEncryptionJWKS
+	return e
+}
+
+// EncryptIDToken returns idToken encrypted as a nested JWT for clients that registered
+// id_token_encrypted_response_alg, and idToken unchanged for other clients.
+func (e *ResponseEncrypter) EncryptIDToken(ctx context.Context, client *domain.OAuth2Client, idToken string) (string, error) {
+	if client.IDTokenEncryptedResponseAlg == "" {
+		return idToken, nil
+	}
+	return e.encrypt(ctx, client, []byte(idToken), client.IDTokenEncryptedResponseAlg, client.IDTokenEncryptedResponseEnc, "JWT")
+}
+
+// EncryptUserInfo encrypts a UserInfo response for a client that registered
+// userinfo_encrypted_response_alg. payload is the JSON claims, or a signed JWT with cty "JWT".
+func (e *ResponseEncrypter) EncryptUserInfo(ctx context.Context, client *domain.OAuth2Client, payload []byte, cty string) (string, error) {
+	if client.UserinfoEncryptedResponseAlg == "" {
+		return "", errors.New("client did not register userinfo_encrypted_response_alg")
+	}
+	return e.encrypt(ctx, client, payload, client.UserinfoEncryptedResponseAlg, client.UserinfoEncryptedResponseEnc, cty)
+}
+
+func (e *ResponseEncrypter) encrypt(ctx context.Context, client *domain.OAuth2Client, plaintext []byte, alg, enc, cty string) (string, error) {
+	key, err := e.encryptionKey(ctx, client, alg)
+	if err != nil {
+		return "", fmt.Errorf("client encryption key: %w", err)
+	}
+	if enc == "" {
+		enc = domain.DefaultEncryptedResponseEnc
+	}
+	return jose.Encrypt(plaintext, key.Key.(*rsa.PublicKey), jose.JWEHeader{Alg: alg, Enc: enc, Kid: key.KeyID, Cty: cty})
+}
+
+// encryptionKey returns the client's encryption key for alg from its inline jwks or, failing
+// that, from jwks_uri, which is refetched once when it holds no usable key.
+func (e *ResponseEncrypter) encryptionKey(ctx context.Context, client *domain.OAuth2Client, alg string) (*clientJWK, error) {
+	if len(client.JWKS) > 0 {
+		keys, err := parseClientEncryptionJWKS(client.JWKS)
+		if err != nil {
+			return nil, err
+		}
+		return matchEncryptionKey(keys, alg)
+	}
+	if client.JWKSURI == "" {
+		return nil, errors.New("client has no registered jwks or jwks_uri")
+	}
+	keys, err := e.jwks.keys(ctx, client.JWKSURI, false)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := matchEncryptionKey(keys, alg); err == nil {
+		return key, nil
+	}
+	// The client may have rotated keys since the set was cached.
+	if keys, err = e.jwks.keys(ctx, client.JWKSURI, true); err != nil {
+		return nil, err
+	}
+	return matchEncryptionKey(keys, alg)
+}
+
+// matchEncryptionKey returns the first RSA key usable with alg; an empty alg matches any.
+func matchEncryptionKey(keys []clientJWK, alg string) (*clientJWK, error) {
+	for i := range keys {
+		if _, ok := keys[i].Key.(*rsa.PublicKey); !ok {
+			continue
+		}
+		if alg == "" || keys[i].Algorithm == "" || keys[i].Algorithm == alg {
+			return &keys[i], nil
+		}
+	}
+	return nil, errors.New("jwks contains no RSA encryption key")
+}
+
+// clientEncryptionKey returns the first RSA encryption key of an inline jwks.
+func clientEncryptionKey(jwks []byte) (*clientJWK, error) {
+	keys, err := parseClientEncryptionJWKS(jwks)
+	if err != nil {
+		return nil, err
+	}
+	return matchEncryptionKey(keys, "")
+}
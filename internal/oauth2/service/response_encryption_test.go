@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/jose"
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// rsaEncryptionJWKS returns a JWK Set holding a signature key and an encryption key for pub.
+func rsaEncryptionJWKS(t *testing.T, kid string, pub *rsa.PublicKey) json.RawMessage {
+	t.Helper()
+	n := base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	raw, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "sig-key", "use": "sig", "alg": "RS256", "n": n, "e": e},
+		{"kty": "RSA", "kid": kid, "use": "enc", "alg": "RSA-OAEP-256", "n": n, "e": e},
+	}})
+	require.NoError(t, err)
+	return raw
+}
+
+func TestParseClientEncryptionJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys, err := parseClientEncryptionJWKS(rsaEncryptionJWKS(t, "enc1", &key.PublicKey))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "enc1", keys[0].KeyID)
+
+	_, err = parseClientEncryptionJWKS(rsaJWKS(t, "sig1", &key.PublicKey))
+	assert.ErrorContains(t, err, "no encryption keys")
+}
+
+func TestResponseEncrypter_EncryptIDToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	enc := NewResponseEncrypter(nil)
+	ctx := context.Background()
+
+	// Clients without id_token_encrypted_response_alg get the signed token as is.
+	token, err := enc.EncryptIDToken(ctx, &domain.OAuth2Client{}, "header.payload.signature")
+	require.NoError(t, err)
+	assert.Equal(t, "header.payload.signature", token)
+
+	client := &domain.OAuth2Client{
+		JWKS:                        rsaEncryptionJWKS(t, "enc1", &key.PublicKey),
+		IDTokenEncryptedResponseAlg: jose.KeyAlgRSAOAEP256,
+	}
+	token, err = enc.EncryptIDToken(ctx, client, "header.payload.signature")
+	require.NoError(t, err)
+	require.True(t, jose.IsJWE(token))
+	plaintext, header, err := jose.Decrypt(token, key)
+	require.NoError(t, err)
+	assert.Equal(t, "header.payload.signature", string(plaintext))
+	assert.Equal(t, jose.KeyAlgRSAOAEP256, header.Alg)
+	assert.Equal(t, domain.DefaultEncryptedResponseEnc, header.Enc)
+	assert.Equal(t, "enc1", header.Kid)
+	assert.Equal(t, "JWT", header.Cty)
+
+	// The key is registered for RSA-OAEP-256 only.
+	client.IDTokenEncryptedResponseAlg = jose.KeyAlgRSAOAEP
+	_, err = enc.EncryptIDToken(ctx, client, "header.payload.signature")
+	assert.ErrorContains(t, err, "no RSA encryption key")
+}
+
+func TestResponseEncrypter_EncryptUserInfo_JWKSURI(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks := rsaEncryptionJWKS(t, "enc1", &key.PublicKey)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(jwks)
+	}))
+	defer server.Close()
+
+	client := &domain.OAuth2Client{
+		JWKSURI:                      server.URL,
+		UserinfoEncryptedResponseAlg: jose.KeyAlgRSAOAEP256,
+		UserinfoEncryptedResponseEnc: "A256GCM",
+	}
+	token, err := NewResponseEncrypter(server.Client()).EncryptUserInfo(context.Background(), client, []byte(`{"sub":"account-001"}`), "")
+	require.NoError(t, err)
+	plaintext, header, err := jose.Decrypt(token, key)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"account-001"}`, string(plaintext))
+	assert.Equal(t, "A256GCM", header.Enc)
+	assert.Empty(t, header.Cty)
+
+	_, err = NewResponseEncrypter(nil).EncryptUserInfo(context.Background(), &domain.OAuth2Client{}, []byte(`{}`), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+	"github.com/rushairer/gosso/internal/oauth2/repository"
+)
+
+// maxSectorIdentifierDocumentSize bounds the redirect URI list fetched from a sector_identifier_uri.
+const maxSectorIdentifierDocumentSize = 64 * 1024
+
+// SubjectIdentifierService derives the subject identifier each client sees for an account
+// (OpenID Connect Core §8). Public clients receive the account ID; pairwise clients receive
+// a salted hash of the account ID and the client's sector, so clients in different sectors
+// cannot correlate their users.
+//
+// A nil *SubjectIdentifierService has pairwise identifiers disabled and skips client
+// lookups, so clients named only by client ID receive public identifiers.
+type SubjectIdentifierService struct {
+	salt        []byte
+	clientRepo  repository.OAuth2ClientRepository
+	subjectRepo repository.PairwiseSubjectRepository
+	httpClient  *http.Client
+}
+
+// NewSubjectIdentifierService creates a subject identifier service. An empty salt disables
+// pairwise identifiers: clients cannot register for them, and existing pairwise clients are
+// refused rather than given public identifiers. httpClient fetches sector_identifier_uri
+// documents; nil uses NewClientURLHTTPClient.
+func NewSubjectIdentifierService(salt string, clientRepo repository.OAuth2ClientRepository, subjectRepo repository.PairwiseSubjectRepository, httpClient *http.Client) *SubjectIdentifierService {
+	if httpClient == nil {
+		httpClient = NewClientURLHTTPClient()
+	}
+	return &SubjectIdentifierService{
+		salt:        []byte(salt),
+		clientRepo:  clientRepo,
+		subjectRepo: subjectRepo,
+		httpClient:  httpClient,
+	}
+}
+
+// PairwiseEnabled reports whether clients may use pairwise subject identifiers.
+func (s *SubjectIdentifierService) PairwiseEnabled() bool {
+	return s != nil && len(s.salt) > 0
+}
+
+// Subject returns the subject identifier client sees for accountID.
+func (s *SubjectIdentifierService) Subject(client *domain.OAuth2Client, accountID string) (string, error) {
+	if !client.UsesPairwiseSubject() {
+		return accountID, nil
+	}
+	if !s.PairwiseEnabled() {
+		return "", ErrPairwiseSubjectsUnavailable
+	}
+	sector := client.SectorIdentifier()
+	if sector == "" {
+		return "", fmt.Errorf("client %s has no sector identifier", client.ClientID)
+	}
+	mac := hmac.New(sha256.New, s.salt)
+	mac.Write([]byte(sector))
+	mac.Write([]byte{0})
+	mac.Write([]byte(accountID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// SubjectForClientID returns the subject identifier the client with clientID sees for accountID.
+func (s *SubjectIdentifierService) SubjectForClientID(ctx context.Context, clientID, accountID string) (string, error) {
+	if s == nil {
+		return accountID, nil
+	}
+	client, err := s.clientRepo.FindByClientID(ctx, clientID)
+	if err != nil {
+		return "", fmt.Errorf("find client: %w", err)
+	}
+	return s.Subject(client, accountID)
+}
+
+// IssueSubject is SubjectForClientID for subjects placed in ID tokens. It records each
+// pairwise subject so an id_token_hint carrying it can be resolved back to the account.
+func (s *SubjectIdentifierService) IssueSubject(ctx context.Context, clientID, accountID string) (string, error) {
+	if s == nil {
+		return accountID, nil
+	}
+	client, err := s.clientRepo.FindByClientID(ctx, clientID)
+	if err != nil {
+		return "", fmt.Errorf("find client: %w", err)
+	}
+	subject, err := s.Subject(client, accountID)
+	if err != nil || !client.UsesPairwiseSubject() {
+		return subject, err
+	}
+	if err := s.subjectRepo.Save(ctx, client.SectorIdentifier(), subject, accountID); err != nil {
+		return "", err
+	}
+	return subject, nil
+}
+
+// ResolveAccountID maps a subject identifier issued to the client with clientID back to
+// the account ID. Pairwise subjects must have been issued through IssueSubject.
+func (s *SubjectIdentifierService) ResolveAccountID(ctx context.Context, clientID, subject string) (string, error) {
+	if s == nil {
+		return subject, nil
+	}
+	client, err := s.clientRepo.FindByClientID(ctx, clientID)
+	if err != nil {
+		return "", fmt.Errorf("find client: %w", err)
+	}
+	return s.resolveAccountID(ctx, client, subject)
+}
+
+// ResolveAccessTokenSubject maps the sub claim of an RFC 9068 access token issued to the
+// client with clientID back to the account ID. A subject equal to clientID names the client
+// itself, as in client credentials tokens, and resolves to the account owning the client.
+func (s *SubjectIdentifierService) ResolveAccessTokenSubject(ctx context.Context, clientID, subject string) (string, error) {
+	if s == nil {
+		if subject == clientID {
+			return "", nil
+		}
+		return subject, nil
+	}
+	client, err := s.clientRepo.FindByClientID(ctx, clientID)
+	if err != nil {
+		return "", fmt.Errorf("find client: %w", err)
+	}
+	if subject == clientID {
+		return client.AccountID, nil
+	}
+	return s.resolveAccountID(ctx, client, subject)
+}
+
+func (s *SubjectIdentifierService) resolveAccountID(ctx context.Context, client *domain.OAuth2Client, subject string) (string, error) {
+	if !client.UsesPairwiseSubject() {
+		return subject, nil
+	}
+	if !s.PairwiseEnabled() {
+		return "", ErrPairwiseSubjectsUnavailable
+	}
+	return s.subjectRepo.FindAccountID(ctx, client.SectorIdentifier(), subject)
+}
+
+// ValidateClient checks the subject_type and sector_identifier_uri of a client (OpenID
+// Connect Dynamic Client Registration §2, §5). A pairwise client's redirect URIs must either
+// share a single host or all be listed in the JSON array published at its
+// sector_identifier_uri, which is fetched here.
+func (s *SubjectIdentifierService) ValidateClient(ctx context.Context, c *domain.OAuth2Client) error {
+	if c.SubjectType != "" && !domain.IsValidSubjectType(c.SubjectType) {
+		return &ValidationError{Message: fmt.Sprintf("invalid subject_type: %q", c.SubjectType)}
+	}
+	if c.SectorIdentifierURI != "" {
+		if c.SubjectType != domain.SubjectTypePairwise {
+			return &ValidationError{Message: "sector_identifier_uri requires subject_type pairwise"}
+		}
+		if len(c.SectorIdentifierURI) > maxRequestURILength {
+			return &ValidationError{Message: fmt.Sprintf("sector_identifier_uri exceeds maximum length of %d characters", maxRequestURILength)}
+		}
+		u, err := url.Parse(c.SectorIdentifierURI)
+		if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil || u.Fragment != "" {
+			return &ValidationError{Message: "invalid sector_identifier_uri (must be an https URL without fragment)"}
+		}
+	}
+	if !c.UsesPairwiseSubject() {
+		return nil
+	}
+	if !s.PairwiseEnabled() {
+		return &ValidationError{Message: "pairwise subject identifiers are not enabled on this server"}
+	}
+	if c.SectorIdentifierURI == "" {
+		if len(c.RedirectURIs) == 0 {
+			return &ValidationError{Message: "subject_type pairwise requires redirect_uris or sector_identifier_uri"}
+		}
+		sector := c.SectorIdentifier()
+		for _, uri := range c.RedirectURIs[1:] {
+			if u, err := url.Parse(uri); err != nil || u.Hostname() != sector {
+				return &ValidationError{Message: "redirect_uris with more than one host require a sector_identifier_uri"}
+			}
+		}
+		return nil
+	}
+	listed, err := s.fetchSectorRedirectURIs(ctx, c.SectorIdentifierURI)
+	if err != nil {
+		return &ValidationError{Message: "invalid sector_identifier_uri: " + err.Error()}
+	}
+	for _, uri := range c.RedirectURIs {
+		if !slices.Contains(listed, uri) {
+			return &ValidationError{Message: fmt.Sprintf("redirect_uri %q is not listed at sector_identifier_uri", uri)}
+		}
+	}
+	return nil
+}
+
+// fetchSectorRedirectURIs fetches the JSON array of redirect URIs published at sectorURI.
+func (s *SubjectIdentifierService) fetchSectorRedirectURIs(ctx context.Context, sectorURI string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sectorURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSectorIdentifierDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if len(body) > maxSectorIdentifierDocumentSize {
+		return nil, errors.New("response too large")
+	}
+	var uris []string
+	if err := json.Unmarshal(body, &uris); err != nil {
+		return nil, errors.New("response is not a JSON array of redirect URIs")
+	}
+	return uris, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/oauth2/domain"
+	"github.com/rushairer/gosso/internal/oauth2/repository"
+)
+
+const testPairwiseSalt = "0123456789abcdef0123456789abcdef"
+
+// fakeSubjectClientRepo serves FindByClientID from a map.
+type fakeSubjectClientRepo struct {
+	repository.OAuth2ClientRepository
+	clients map[string]*domain.OAuth2Client
+}
+
+func (r *fakeSubjectClientRepo) FindByClientID(_ context.Context, clientID string) (*domain.OAuth2Client, error) {
+	if c, ok := r.clients[clientID]; ok {
+		return c, nil
+	}
+	return nil, domain.ErrClientNotFound
+}
+
+// fakePairwiseSubjectRepo keeps pairwise subject mappings in memory.
+type fakePairwiseSubjectRepo struct {
+	accounts map[string]string
+}
+
+func (r *fakePairwiseSubjectRepo) Save(_ context.Context, sector, subject, accountID string) error {
+	r.accounts[sector+"|"+subject] = accountID
+	return nil
+}
+
+func (r *fakePairwiseSubjectRepo) FindAccountID(_ context.Context, sector, subject string) (string, error) {
+	if accountID, ok := r.accounts[sector+"|"+subject]; ok {
+		return accountID, nil
+	}
+	return "", domain.ErrPairwiseSubjectNotFound
+}
+
+func newTestSubjectIdentifierService(salt string, clients ...*domain.OAuth2Client) (*SubjectIdentifierService, *fakePairwiseSubjectRepo) {
+	repo := &fakeSubjectClientRepo{clients: make(map[string]*domain.OAuth2Client)}
+	for _, c := range clients {
+		repo.clients[c.ClientID] = c
+	}
+	subjects := &fakePairwiseSubjectRepo{accounts: make(map[string]string)}
+	return NewSubjectIdentifierService(salt, repo, subjects, nil), subjects
+}
+
+func TestSubjectIdentifier_Subject(t *testing.T) {
+	public := &domain.OAuth2Client{ClientID: "public", RedirectURIs: []string{"https://a.example.com/cb"}}
+	pairwiseA := &domain.OAuth2Client{ClientID: "a", SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb"}}
+	pairwiseA2 := &domain.OAuth2Client{ClientID: "a2", SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com:8443/other"}}
+	pairwiseB := &domain.OAuth2Client{ClientID: "b", SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://b.example.com/cb"}}
+	svc, _ := newTestSubjectIdentifierService(testPairwiseSalt)
+
+	sub, err := svc.Subject(public, "account-001")
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", sub)
+
+	subA, err := svc.Subject(pairwiseA, "account-001")
+	require.NoError(t, err)
+	assert.NotEqual(t, "account-001", subA)
+	subA2, err := svc.Subject(pairwiseA2, "account-001")
+	require.NoError(t, err)
+	assert.Equal(t, subA, subA2, "clients in one sector share subjects")
+	subB, err := svc.Subject(pairwiseB, "account-001")
+	require.NoError(t, err)
+	assert.NotEqual(t, subA, subB, "sectors must not be correlatable")
+	other, err := svc.Subject(pairwiseA, "account-002")
+	require.NoError(t, err)
+	assert.NotEqual(t, subA, other)
+
+	resalted, _ := newTestSubjectIdentifierService("fedcba9876543210fedcba9876543210")
+	subResalted, err := resalted.Subject(pairwiseA, "account-001")
+	require.NoError(t, err)
+	assert.NotEqual(t, subA, subResalted)
+}
+
+func TestSubjectIdentifier_PairwiseDisabled(t *testing.T) {
+	client := &domain.OAuth2Client{ClientID: "a", SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb"}}
+	svc, _ := newTestSubjectIdentifierService("", client)
+
+	assert.False(t, svc.PairwiseEnabled())
+	_, err := svc.Subject(client, "account-001")
+	assert.ErrorIs(t, err, ErrPairwiseSubjectsUnavailable)
+	_, err = svc.IssueSubject(context.Background(), "a", "account-001")
+	assert.ErrorIs(t, err, ErrPairwiseSubjectsUnavailable)
+
+	var nilSvc *SubjectIdentifierService
+	sub, err := nilSvc.SubjectForClientID(context.Background(), "a", "account-001")
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", sub)
+}
+
+func TestSubjectIdentifier_IssueAndResolve(t *testing.T) {
+	pairwise := &domain.OAuth2Client{ClientID: "a", SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb"}}
+	sameSector := &domain.OAuth2Client{ClientID: "a2", SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/other"}}
+	public := &domain.OAuth2Client{ClientID: "public"}
+	svc, _ := newTestSubjectIdentifierService(testPairwiseSalt, pairwise, sameSector, public)
+	ctx := context.Background()
+
+	sub, err := svc.IssueSubject(ctx, "a", "account-001")
+	require.NoError(t, err)
+	assert.NotEqual(t, "account-001", sub)
+
+	accountID, err := svc.ResolveAccountID(ctx, "a", sub)
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", accountID)
+	accountID, err = svc.ResolveAccountID(ctx, "a2", sub)
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", accountID)
+
+	_, err = svc.ResolveAccountID(ctx, "a", "never-issued")
+	assert.ErrorIs(t, err, domain.ErrPairwiseSubjectNotFound)
+
+	accountID, err = svc.ResolveAccountID(ctx, "public", "account-002")
+	require.NoError(t, err)
+	assert.Equal(t, "account-002", accountID)
+}
+
+func TestSubjectIdentifier_ResolveAccessTokenSubject(t *testing.T) {
+	pairwise := &domain.OAuth2Client{ClientID: "a", AccountID: "owner-001", SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb"}}
+	svc, _ := newTestSubjectIdentifierService(testPairwiseSalt, pairwise)
+	ctx := context.Background()
+
+	sub, err := svc.IssueSubject(ctx, "a", "account-001")
+	require.NoError(t, err)
+	accountID, err := svc.ResolveAccessTokenSubject(ctx, "a", sub)
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", accountID)
+
+	accountID, err = svc.ResolveAccessTokenSubject(ctx, "a", "a")
+	require.NoError(t, err)
+	assert.Equal(t, "owner-001", accountID, "a client_id subject resolves to the client owner")
+
+	var nilSvc *SubjectIdentifierService
+	accountID, err = nilSvc.ResolveAccessTokenSubject(ctx, "a", "account-002")
+	require.NoError(t, err)
+	assert.Equal(t, "account-002", accountID)
+}
+
+func TestSubjectIdentifier_ValidateClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sector.json":
+			_, _ = w.Write([]byte(`["https://a.example.com/cb","https://b.example.com/cb"]`))
+		case "/object.json":
+			_, _ = w.Write([]byte(`{"redirect_uris":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc := NewSubjectIdentifierService(testPairwiseSalt, nil, nil, srv.Client())
+	disabled := NewSubjectIdentifierService("", nil, nil, srv.Client())
+	guarded := NewSubjectIdentifierService(testPairwiseSalt, nil, nil, nil)
+
+	tests := []struct {
+		name    string
+		svc     *SubjectIdentifierService
+		client  domain.OAuth2Client
+		wantErr string
+	}{
+		{name: "public", svc: disabled, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePublic}},
+		{name: "unknown type", svc: svc, client: domain.OAuth2Client{SubjectType: "opaque"}, wantErr: "invalid subject_type"},
+		{name: "pairwise disabled", svc: disabled, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb"}}, wantErr: "not enabled"},
+		{name: "single host", svc: svc, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb", "https://a.example.com/cb2"}}},
+		{name: "multiple hosts", svc: svc, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb", "https://b.example.com/cb"}}, wantErr: "require a sector_identifier_uri"},
+		{name: "no redirect uris", svc: svc, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise}, wantErr: "requires redirect_uris or sector_identifier_uri"},
+		{name: "sector lists redirect uris", svc: svc, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, SectorIdentifierURI: srv.URL + "/sector.json", RedirectURIs: []string{"https://a.example.com/cb", "https://b.example.com/cb"}}},
+		{name: "redirect uri not listed", svc: svc, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, SectorIdentifierURI: srv.URL + "/sector.json", RedirectURIs: []string{"https://c.example.com/cb"}}, wantErr: "is not listed"},
+		{name: "sector not an array", svc: svc, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, SectorIdentifierURI: srv.URL + "/object.json", RedirectURIs: []string{"https://a.example.com/cb"}}, wantErr: "not a JSON array"},
+		{name: "sector not found", svc: svc, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, SectorIdentifierURI: srv.URL + "/missing.json", RedirectURIs: []string{"https://a.example.com/cb"}}, wantErr: "unexpected status 404"},
+		{name: "sector over http", svc: svc, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, SectorIdentifierURI: "http://a.example.com/sector.json"}, wantErr: "must be an https URL"},
+		{name: "sector on loopback", svc: guarded, client: domain.OAuth2Client{SubjectType: domain.SubjectTypePairwise, SectorIdentifierURI: srv.URL + "/sector.json", RedirectURIs: []string{"https://a.example.com/cb"}}, wantErr: "non-public address"},
+		{name: "sector without pairwise", svc: svc, client: domain.OAuth2Client{SectorIdentifierURI: srv.URL + "/sector.json"}, wantErr: "requires subject_type pairwise"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.svc.ValidateClient(context.Background(), &tt.client)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, IsValidationError(err))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rushairer/gouno"
+	"go.uber.org/zap"
+
+	sessionService "github.com/rushairer/gosso/internal/session/service"
+	"github.com/rushairer/gosso/middleware"
+)
+
+//go:embed template/check_session.html
+var checkSessionTemplateFS embed.FS
+
+// checkSessionTmpl is html/template, which escapes the cookie name as a JS string.
+var checkSessionTmpl = template.Must(template.ParseFS(checkSessionTemplateFS, "template/check_session.html"))
+
+// CheckSessionIframe handles GET /oidc/check_session, the check_session_iframe of OpenID
+// Connect Session Management 1.0. Relying parties embed it and post "client_id session_state"
+// messages to it; the page answers changed or unchanged by recomputing session_state from
+// the browser state cookie, so detecting a logout needs no request to the server.
+func (c *OIDCController) CheckSessionIframe(ctx *gin.Context) {
+	nonce := middleware.GetCSPNonce(ctx)
+	var buf bytes.Buffer
+	if err := checkSessionTmpl.Execute(&buf, gin.H{
+		"CookieName": sessionService.BrowserStateCookieName,
+		"CSPNonce":   nonce,
+	}); err != nil {
+		c.logger.Error("Failed to render check_session_iframe", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gouno.NewInternalServerErrorResponse())
+		return
+	}
+	// The page is embedded by relying parties on any origin.
+	ctx.Writer.Header().Del("X-Frame-Options")
+	if nonce != "" {
+		ctx.Header("Content-Security-Policy", "default-src 'none'; script-src 'nonce-"+nonce+"'; base-uri 'none'; form-action 'none'; frame-ancestors *")
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
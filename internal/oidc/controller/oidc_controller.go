@@ -65,7 +65,8 @@ func NewOIDCController(
 	}
 }
 
-// RegisterRoutes registers OIDC routes (UserInfo + Logout + Front-Channel Logout).
+// RegisterRoutes registers OIDC routes (UserInfo + Logout + Front-Channel Logout +
+// check_session_iframe).
 // .well-known routes are registered at the router layer for independent rate limiting.
 // GET /logout is intentionally omitted to prevent CSRF via image tags or link prefetching.
 // Clients must use POST or redirect with id_token_hint (handled in Logout).
@@ -74,6 +75,7 @@ func (c *OIDCController) RegisterRoutes(server *gin.RouterGroup, authMiddleware
 	server.POST("/userinfo", authMiddleware, c.UserInfo)
 	server.POST("/logout", c.Logout)
 	server.GET("/frontchannel_logout", c.FrontChannelLogout)
+	server.GET("/check_session", c.CheckSessionIframe)
 }
 
 // Discovery GET /.well-known/openid-configuration
@@ -105,7 +107,7 @@ func (c *OIDCController) UserInfo(ctx *gin.Context) {
 	// Parse scope
 	scopes := strings.Split(claims.Scope, " ")
 
-	info, err := c.userInfoSvc.GetUserInfo(ctx, claims.AccountID, scopes)
+	info, err := c.userInfoSvc.GetUserInfo(ctx, claims.AccountID, claims.ClientID, scopes, claims.UserInfoClaims)
 	if err != nil {
 		controllerutil.AbortWithServiceError(ctx, c.logger, err, userInfoErrorMap,
 			http.StatusInternalServerError, "Failed to get user info")
@@ -119,6 +121,16 @@ func (c *OIDCController) UserInfo(ctx *gin.Context) {
 		info["scope"] = claims.Scope
 	}
 
+	response, encoded, err := c.userInfoSvc.EncodeResponse(ctx, claims.ClientID, info)
+	if err != nil {
+		controllerutil.AbortWithServiceError(ctx, c.logger, err, nil,
+			http.StatusInternalServerError, "Failed to encode user info")
+		return
+	}
+	if encoded {
+		ctx.Data(http.StatusOK, "application/jwt", []byte(response))
+		return
+	}
 	ctx.JSON(http.StatusOK, info)
 }
 
@@ -189,6 +201,10 @@ func (c *OIDCController) Logout(ctx *gin.Context) {
 		}
 	}
 
+	if logoutPerformed {
+		controllerutil.ClearBrowserStateCookie(ctx, controllerutil.IsSecureRequest(ctx))
+	}
+
 	// Post-logout redirect
 	if req.PostLogoutRedirectURI != "" && clientID != "" {
 		c.handlePostLogoutRedirect(ctx, req, clientID)
@@ -224,7 +240,7 @@ func (c *OIDCController) validateBearerToken(ctx *gin.Context) *tokenDomain.Acce
 // tryLogoutByIDTokenHint attempts logout using the id_token_hint parameter.
 // Returns the resolved clientID and true on success, or ("", false) to fall through.
 func (c *OIDCController) tryLogoutByIDTokenHint(ctx *gin.Context, req logoutRequest, bearerClaims *tokenDomain.AccessTokenClaims) (string, bool) {
-	claims, err := c.logoutSvc.ValidateIDTokenHint(req.IDTokenHint, req.ClientID)
+	claims, err := c.logoutSvc.ValidateIDTokenHint(ctx, req.IDTokenHint, req.ClientID)
 	if err != nil {
 		// Audience mismatch is a client error — return 400 instead of falling through.
 		if errors.Is(err, oidcService.ErrAudienceMismatch) {
@@ -351,7 +367,7 @@ func (c *OIDCController) FrontChannelLogout(ctx *gin.Context) {
 	var accountID, sessionID string
 
 	if req.IDTokenHint != "" {
-		claims, err := c.logoutSvc.ValidateIDTokenHint(req.IDTokenHint, req.ClientID)
+		claims, err := c.logoutSvc.ValidateIDTokenHint(ctx, req.IDTokenHint, req.ClientID)
 		if err != nil {
 			c.logger.Debug("id_token_hint validation failed for front-channel logout", zap.Error(err))
 			ctx.JSON(http.StatusBadRequest, gouno.NewErrorResponse(http.StatusBadRequest, "invalid id_token_hint"))
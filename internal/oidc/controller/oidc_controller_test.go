@@ -2,6 +2,8 @@ package controller
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -22,8 +24,10 @@ import (
 	accountDomain "github.com/rushairer/gosso/internal/account/domain"
 	accountService "github.com/rushairer/gosso/internal/account/service"
 	"github.com/rushairer/gosso/internal/cache"
+	"github.com/rushairer/gosso/internal/jose"
 	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
 	oauth2Repo "github.com/rushairer/gosso/internal/oauth2/repository"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
 	oidcService "github.com/rushairer/gosso/internal/oidc/service"
 	sessionDomain "github.com/rushairer/gosso/internal/session/domain"
 	sessionService "github.com/rushairer/gosso/internal/session/service"
@@ -204,8 +208,8 @@ func setupUserInfoEngine(accountSvc *mockAccountService, credRepo *mockCredentia
 		ctx.Next()
 	})
 
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
-	userInfoSvc := oidcService.NewUserInfoService(accountSvc, credRepo, nil)
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
+	userInfoSvc := oidcService.NewUserInfoService(accountSvc, credRepo, nil, nil, nil, nil, "", nil)
 
 	ctrl := NewOIDCController(discoverySvc, nil, userInfoSvc, nil, nil, nil, nil, "https://sso.example.com", zap.NewNop())
 	engine.GET("/.well-known/openid-configuration", ctrl.Discovery)
@@ -316,12 +320,147 @@ func TestUserInfo_EmailScope(t *testing.T) {
 	assert.Equal(t, true, resp["email_verified"])
 }
 
+func TestUserInfo_EncryptedResponse(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&key.PublicKey)
+	require.NoError(t, err)
+	jwk.Kid, jwk.Use = "enc-001", "enc"
+	jwks, err := json.Marshal(map[string]any{"keys": []*jose.JWK{jwk}})
+	require.NoError(t, err)
+	client := &oauth2Domain.OAuth2Client{
+		ClientID:                     "client-001",
+		JWKS:                         jwks,
+		UserinfoEncryptedResponseAlg: jose.KeyAlgRSAOAEP,
+	}
+
+	account := newTestAccount()
+	accountSvc := &mockAccountService{
+		findByIDFn: func() (*accountDomain.Account, error) {
+			return account, nil
+		},
+	}
+	clientRepo := &mockClientRepo{findByClientIDFn: func() (*oauth2Domain.OAuth2Client, error) {
+		return client, nil
+	}}
+	userInfoSvc := oidcService.NewUserInfoService(accountSvc, &mockCredentialRepo{}, clientRepo, nil, oauth2Service.NewResponseEncrypter(nil), nil, "", nil)
+	ctrl := NewOIDCController(oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{}), nil, userInfoSvc, nil, nil, nil, nil, "https://sso.example.com", zap.NewNop())
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		ctx.Set(middleware.ContextKeyClaims, &tokenDomain.AccessTokenClaims{
+			AccountID: "account-001",
+			ClientID:  "client-001",
+			Scope:     "openid profile",
+		})
+		ctx.Next()
+	})
+	ctrl.RegisterRoutes(engine.Group("/oidc"), func(ctx *gin.Context) { ctx.Next() })
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oidc/userinfo", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/jwt", w.Header().Get("Content-Type"))
+	plaintext, header, err := jose.Decrypt(w.Body.String(), key)
+	require.NoError(t, err)
+	assert.Equal(t, "enc-001", header.Kid)
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(plaintext, &resp))
+	assert.Equal(t, "account-001", resp["sub"])
+	assert.Equal(t, "Test User", resp["name"])
+}
+
+// serveSignedUserInfo serves a UserInfo request for client with responses signed by a
+// fresh key service, which it returns for verification.
+func serveSignedUserInfo(t *testing.T, client *oauth2Domain.OAuth2Client) (*httptest.ResponseRecorder, *tokenService.KeyService) {
+	t.Helper()
+	keySvc := setupTestKeyService(t)
+	redisClient, _ := testutil.SetupTestRedis(t)
+	tokenSvc := setupTestTokenService(t, keySvc, "https://sso.example.com", redisClient, nil)
+
+	account := newTestAccount()
+	accountSvc := &mockAccountService{
+		findByIDFn: func() (*accountDomain.Account, error) {
+			return account, nil
+		},
+	}
+	clientRepo := &mockClientRepo{findByClientIDFn: func() (*oauth2Domain.OAuth2Client, error) {
+		return client, nil
+	}}
+	userInfoSvc := oidcService.NewUserInfoService(accountSvc, &mockCredentialRepo{}, clientRepo, nil, oauth2Service.NewResponseEncrypter(nil), tokenSvc, "https://sso.example.com", nil)
+	ctrl := NewOIDCController(oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{}), nil, userInfoSvc, nil, nil, nil, nil, "https://sso.example.com", zap.NewNop())
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		ctx.Set(middleware.ContextKeyClaims, &tokenDomain.AccessTokenClaims{
+			AccountID: "account-001",
+			ClientID:  client.ClientID,
+			Scope:     "openid profile",
+		})
+		ctx.Next()
+	})
+	ctrl.RegisterRoutes(engine.Group("/oidc"), func(ctx *gin.Context) { ctx.Next() })
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oidc/userinfo", nil))
+	return w, keySvc
+}
+
+func TestUserInfo_SignedResponse(t *testing.T) {
+	w, keySvc := serveSignedUserInfo(t, &oauth2Domain.OAuth2Client{
+		ClientID:                  "client-001",
+		UserinfoSignedResponseAlg: "RS256",
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/jwt", w.Header().Get("Content-Type"))
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(w.Body.String(), claims, keySvc.Keyfunc, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	assert.Equal(t, keySvc.KeyID(), token.Header["kid"])
+	assert.Equal(t, "https://sso.example.com", claims["iss"])
+	assert.Equal(t, "client-001", claims["aud"])
+	assert.Equal(t, "account-001", claims["sub"])
+	assert.Equal(t, "Test User", claims["name"])
+}
+
+func TestUserInfo_SignedAndEncryptedResponse(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&key.PublicKey)
+	require.NoError(t, err)
+	jwk.Kid, jwk.Use = "enc-001", "enc"
+	jwks, err := json.Marshal(map[string]any{"keys": []*jose.JWK{jwk}})
+	require.NoError(t, err)
+
+	w, keySvc := serveSignedUserInfo(t, &oauth2Domain.OAuth2Client{
+		ClientID:                     "client-001",
+		JWKS:                         jwks,
+		UserinfoSignedResponseAlg:    "RS256",
+		UserinfoEncryptedResponseAlg: jose.KeyAlgRSAOAEP,
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/jwt", w.Header().Get("Content-Type"))
+	plaintext, header, err := jose.Decrypt(w.Body.String(), key)
+	require.NoError(t, err)
+	assert.Equal(t, "JWT", header.Cty)
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(string(plaintext), claims, keySvc.Keyfunc, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	assert.Equal(t, "client-001", claims["aud"])
+	assert.Equal(t, "account-001", claims["sub"])
+}
+
 func TestUserInfo_NoClaims(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	engine := gin.New()
 
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
-	userInfoSvc := oidcService.NewUserInfoService(&mockAccountService{}, &mockCredentialRepo{}, nil)
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
+	userInfoSvc := oidcService.NewUserInfoService(&mockAccountService{}, &mockCredentialRepo{}, nil, nil, nil, nil, "", nil)
 	ctrl := NewOIDCController(discoverySvc, nil, userInfoSvc, nil, nil, nil, nil, "https://sso.example.com", zap.NewNop())
 	engine.GET("/.well-known/openid-configuration", ctrl.Discovery)
 	oidcGroup := engine.Group("/oidc")
@@ -478,8 +617,8 @@ func setupLogoutEngine(t *testing.T, clientRepo *mockClientRepo) (*gin.Engine, *
 	blacklistSvc, err := tokenService.NewBlacklistService(redisClient, zap.NewNop())
 	require.NoError(t, err)
 	tokenSvc := setupTestTokenService(t, keySvc, "https://sso.example.com", redisClient, blacklistSvc)
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, nil, nil, "https://sso.example.com", nil, nil, zap.NewNop())
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, nil, "https://sso.example.com", nil, nil, nil, zap.NewNop())
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
 
 	ctrl := NewOIDCController(discoverySvc, nil, nil, logoutSvc, clientRepo, tokenSvc, nil, "https://sso.example.com", zap.NewNop())
 
@@ -522,7 +661,7 @@ func TestLogout_ExpiredIDTokenHint(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
 
 	ctrl := NewOIDCController(nil, nil, nil, logoutSvc, nil, tokenSvc, nil, "https://sso.example.com", zap.NewNop())
 
@@ -561,8 +700,8 @@ func TestLogout_IDTokenHint_WrongIssuer(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
 
 	ctrl := NewOIDCController(discoverySvc, nil, nil, logoutSvc, nil, tokenSvc, nil, "https://sso.example.com", zap.NewNop())
 
@@ -596,8 +735,8 @@ func TestLogout_IDTokenHint_NoAudience(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
 
 	ctrl := NewOIDCController(discoverySvc, nil, nil, logoutSvc, nil, tokenSvc, nil, "https://sso.example.com", zap.NewNop())
 
@@ -630,7 +769,7 @@ func TestLogout_IDTokenHint_WrongSignature(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
 
 	// Sign with a DIFFERENT key service
 	otherKeySvc, err := tokenService.NewKeyService("", "", false, 0, zap.NewNop())
@@ -671,7 +810,7 @@ func TestLogout_BearerToken_InvalidSignature(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
 
 	otherKeySvc, err := tokenService.NewKeyService("", "", false, 0, zap.NewNop())
 	require.NoError(t, err)
@@ -714,8 +853,8 @@ func TestLogout_PostLogoutRedirect_InvalidURI(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
 
 	clientRepo := &mockClientRepo{
 		findByClientIDFn: func() (*oauth2Domain.OAuth2Client, error) {
@@ -761,8 +900,8 @@ func TestLogout_PostLogoutRedirect_ValidURI(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
 
 	clientRepo := &mockClientRepo{
 		findByClientIDFn: func() (*oauth2Domain.OAuth2Client, error) {
@@ -811,7 +950,7 @@ func TestLogout_PostLogoutRedirect_ClientNotFound(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
 
 	clientRepo := &mockClientRepo{
 		findByClientIDFn: func() (*oauth2Domain.OAuth2Client, error) {
@@ -858,7 +997,7 @@ func TestLogout_ClientIDMismatch(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
 
 	ctrl := NewOIDCController(nil, nil, nil, logoutSvc, nil, tokenSvc, nil, "https://sso.example.com", zap.NewNop())
 
@@ -888,6 +1027,32 @@ func TestLogout_ClientIDMismatch(t *testing.T) {
 // Logout Tests — no session
 // ──────────────────────────────────────────────
 
+func TestCheckSessionIframe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.SecurityHeadersMiddleware(false), func(ctx *gin.Context) {
+		ctx.Header("X-Frame-Options", "DENY")
+		ctx.Next()
+	})
+	ctrl := NewOIDCController(nil, nil, nil, nil, nil, nil, nil, "https://sso.example.com", zap.NewNop())
+	ctrl.RegisterRoutes(engine.Group("/oidc"), func(ctx *gin.Context) { ctx.Next() })
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oidc/check_session", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("X-Frame-Options"))
+	csp := w.Header().Get("Content-Security-Policy")
+	assert.Contains(t, csp, "frame-ancestors *")
+	_, rest, ok := strings.Cut(csp, "'nonce-")
+	require.True(t, ok, csp)
+	nonce, _, _ := strings.Cut(rest, "'")
+	assert.Contains(t, w.Body.String(), `<script nonce="`+nonce+`">`)
+	assert.Contains(t, w.Body.String(), `"`+sessionService.BrowserStateCookieName+`"`)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
+
 func TestLogout_NoSession(t *testing.T) {
 	engine, _ := setupLogoutEngine(t, &mockClientRepo{})
 
@@ -927,8 +1092,8 @@ func TestJWKS_Success(t *testing.T) {
 	engine := gin.New()
 
 	keySvc := setupTestKeyService(t)
-	jwksSvc := oidcService.NewJWKSService(keySvc)
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
+	jwksSvc := oidcService.NewJWKSService(keySvc, nil)
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
 
 	ctrl := NewOIDCController(discoverySvc, jwksSvc, nil, nil, nil, nil, nil, "https://sso.example.com", zap.NewNop())
 	engine.GET("/.well-known/jwks.json", ctrl.JWKS)
@@ -1012,8 +1177,8 @@ func setupLogoutEngineWithBlacklist(t *testing.T, clientRepo *mockClientRepo) (*
 	if err != nil {
 		t.Fatalf("NewSessionService: %v", err)
 	}
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
-	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com")
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
+	discoverySvc := oidcService.NewDiscoveryService("https://sso.example.com", oidcService.DiscoveryOptions{})
 
 	ctrl := NewOIDCController(discoverySvc, nil, nil, logoutSvc, clientRepo, tokenSvc, sessionSvc, "https://sso.example.com", zap.NewNop())
 
@@ -1050,6 +1215,10 @@ func TestLogout_BearerToken_Success(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	data := resp["data"].(map[string]any)
 	assert.Equal(t, "logged_out", data["status"])
+	// The check_session_iframe reports the session as changed once the browser state is gone.
+	require.Len(t, w.Result().Cookies(), 1)
+	assert.Equal(t, sessionService.BrowserStateCookieName, w.Result().Cookies()[0].Name)
+	assert.Less(t, w.Result().Cookies()[0].MaxAge, 0)
 }
 
 func TestLogout_BearerToken_WithClientID_NoRedirect(t *testing.T) {
@@ -1142,7 +1311,7 @@ func TestLogout_BearerToken_WithIDTokenHint_SameAccount(t *testing.T) {
 	require.NoError(t, sessionSvc.CreateSession(context.Background(), &sessionDomain.Session{
 		ID: "session-001", AccountID: "account-001", IP: "127.0.0.1", UserAgent: "test",
 	}))
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
 
 	ctrl := NewOIDCController(nil, nil, nil, logoutSvc, nil, tokenSvc, sessionSvc, "https://sso.example.com", zap.NewNop())
 
@@ -1186,7 +1355,7 @@ func TestLogout_BearerToken_WithIDTokenHint_AccountMismatch(t *testing.T) {
 	require.NoError(t, sessionSvc.CreateSession(context.Background(), &sessionDomain.Session{
 		ID: "session-002", AccountID: "account-002", IP: "127.0.0.1", UserAgent: "test",
 	}))
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, zap.NewNop())
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, zap.NewNop())
 
 	ctrl := NewOIDCController(nil, nil, nil, logoutSvc, nil, tokenSvc, sessionSvc, "https://sso.example.com", zap.NewNop())
 
@@ -1235,7 +1404,7 @@ func TestFrontChannelLogout_Unauthorized(t *testing.T) {
 }
 
 func TestFrontChannelLogout_InvalidIDTokenHint(t *testing.T) {
-	logoutSvc := oidcService.NewLogoutService(nil, nil, nil, "https://sso.example.com", nil, nil, zap.NewNop())
+	logoutSvc := oidcService.NewLogoutService(nil, nil, "https://sso.example.com", nil, nil, nil, zap.NewNop())
 	ctrl := NewOIDCController(nil, nil, nil, logoutSvc, nil, nil, nil, "https://sso.example.com", zap.NewNop())
 
 	gin.SetMode(gin.TestMode)
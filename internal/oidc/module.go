@@ -9,6 +9,7 @@ import (
 	accountRepo "github.com/rushairer/gosso/internal/account/repository"
 	accountService "github.com/rushairer/gosso/internal/account/service"
 	oauth2Repo "github.com/rushairer/gosso/internal/oauth2/repository"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
 	oidcService "github.com/rushairer/gosso/internal/oidc/service"
 	sessionService "github.com/rushairer/gosso/internal/session/service"
 	tokenService "github.com/rushairer/gosso/internal/token/service"
@@ -23,22 +24,32 @@ type OIDCModule struct {
 	LogoutService    *oidcService.LogoutService
 }
 
-// InitializeOIDCModule initializes the OIDC module
+// InitializeOIDCModule initializes the OIDC module. requestObjectKeySvc holds the request
+// object encryption key and is nil when auth.request_object_encryption_key_path is empty.
 func InitializeOIDCModule(
 	tokenSvc *tokenService.TokenService,
+	requestObjectKeySvc *tokenService.KeyService,
 	accountSvc accountService.AccountService,
 	authConfig config.AuthConfig,
 	sessionSvc *sessionService.SessionService,
 	credentialRepo accountRepo.CredentialRepository,
 	clientRepo oauth2Repo.OAuth2ClientRepository,
+	subjects *oauth2Service.SubjectIdentifierService,
 	httpClient *http.Client,
 	logger *zap.Logger,
 ) *OIDCModule {
-	idTokenSvc := oidcService.NewIDTokenService(tokenSvc, authConfig.Issuer, accountSvc, credentialRepo, authConfig.IDTokenExpiry, logger)
-	discoverySvc := oidcService.NewDiscoveryService(authConfig.Issuer)
-	jwksSvc := oidcService.NewJWKSService(tokenSvc.KeyService())
-	userInfoSvc := oidcService.NewUserInfoService(accountSvc, credentialRepo, logger)
-	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, jwksSvc, authConfig.Issuer, clientRepo, httpClient, logger)
+	encrypter := oauth2Service.NewResponseEncrypter(httpClient)
+	idTokenSvc := oidcService.NewIDTokenService(tokenSvc, authConfig.Issuer, accountSvc, credentialRepo, clientRepo, subjects, encrypter, authConfig.IDTokenExpiry, logger)
+	discoverySvc := oidcService.NewDiscoveryService(authConfig.Issuer, oidcService.DiscoveryOptions{
+		MTLSBaseURL:               authConfig.MTLSEndpointAliasBaseURL,
+		RequestObjectEncryption:   requestObjectKeySvc != nil,
+		BackchannelAuthentication: authConfig.CIBANotificationURL != "",
+		PairwiseSubjects:          subjects.PairwiseEnabled(),
+		SigningAlgs:               tokenSvc.KeyService().SigningAlgs(),
+	})
+	jwksSvc := oidcService.NewJWKSService(tokenSvc.KeyService(), requestObjectKeySvc)
+	userInfoSvc := oidcService.NewUserInfoService(accountSvc, credentialRepo, clientRepo, subjects, encrypter, tokenSvc, authConfig.Issuer, logger)
+	logoutSvc := oidcService.NewLogoutService(tokenSvc, sessionSvc, authConfig.Issuer, clientRepo, subjects, httpClient, logger)
 
 	return &OIDCModule{
 		IDTokenService:   idTokenSvc,
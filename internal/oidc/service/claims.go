@@ -0,0 +1,20 @@
+package service
+
+import (
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+)
+
+// releasedClaims returns the end-user claims to release for scopes (OIDC Core §5.4) and
+// the claims requested individually with the claims parameter (OIDC Core §5.5).
+func releasedClaims(scopes, requested []string) map[string]bool {
+	released := make(map[string]bool)
+	for _, name := range oauth2Domain.ScopeClaims(scopes) {
+		released[name] = true
+	}
+	for _, name := range requested {
+		if oauth2Domain.IsUserClaim(name) {
+			released[name] = true
+		}
+	}
+	return released
+}
@@ -1,17 +1,57 @@
 package service
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/rushairer/gosso/internal/jose"
+)
+
+// asymmetricSigningAlgs are the JWS algorithms accepted for private_key_jwt client
+// assertions and DPoP proofs.
+var asymmetricSigningAlgs = []string{
+	"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA",
+}
+
+// clientAssertionSigningAlgs are the JWS algorithms accepted for client_secret_jwt (HS*)
+// and private_key_jwt client assertions.
+var clientAssertionSigningAlgs = append([]string{"HS256", "HS384", "HS512"}, asymmetricSigningAlgs...)
 
 // DiscoveryService OIDC Discovery service
 type DiscoveryService struct {
 	jsonBytes []byte
 }
 
+// DiscoveryOptions selects the optional features advertised in the discovery document.
+type DiscoveryOptions struct {
+	// MTLSBaseURL is the base URL of the endpoints that accept TLS client certificates
+	// (RFC 8705 §5); empty uses the issuer.
+	MTLSBaseURL string
+	// RequestObjectEncryption advertises encrypted request objects (RFC 9101 §6.1) and is
+	// set when an encryption key is configured.
+	RequestObjectEncryption bool
+	// BackchannelAuthentication advertises the CIBA endpoint and grant and is set when a
+	// user notification webhook is configured.
+	BackchannelAuthentication bool
+	// PairwiseSubjects advertises pairwise subject identifiers and is set when a pairwise
+	// subject salt is configured.
+	PairwiseSubjects bool
+	// SigningAlgs lists the algorithms ID tokens can be signed with; empty means RS256.
+	SigningAlgs []string
+}
+
 // NewDiscoveryService creates a new instance of DiscoveryService.
 // The discovery document is pre-marshaled to JSON once since it is static
 // for the lifetime of the service. This avoids per-request map copying and
 // JSON marshaling.
-func NewDiscoveryService(issuer string) *DiscoveryService {
+func NewDiscoveryService(issuer string, opts DiscoveryOptions) *DiscoveryService {
+	mtlsBaseURL := opts.MTLSBaseURL
+	if mtlsBaseURL == "" {
+		mtlsBaseURL = issuer
+	}
+	signingAlgs := opts.SigningAlgs
+	if len(signingAlgs) == 0 {
+		signingAlgs = []string{"RS256"}
+	}
 	doc := map[string]any{
 		"issuer":                        issuer,
 		"authorization_endpoint":        issuer + "/oauth2/authorize",
@@ -19,28 +59,43 @@ func NewDiscoveryService(issuer string) *DiscoveryService {
 		"jwks_uri":                      issuer + "/.well-known/jwks.json",
 		"userinfo_endpoint":             issuer + "/oidc/userinfo",
 		"end_session_endpoint":          issuer + "/oidc/logout",
+		"check_session_iframe":          issuer + "/oidc/check_session",
 		"device_authorization_endpoint": issuer + "/oauth2/device/code",
+		"registration_endpoint":         issuer + "/oauth2/register",
 		"scopes_supported": []string{
-			"openid", "profile", "email", "phone",
+			"openid", "profile", "email", "phone", "offline_access",
 		},
 		"response_modes_supported": []string{
-			"query",
+			"query", "form_post", "jwt", "query.jwt", "form_post.jwt",
+		},
+		"authorization_signing_alg_values_supported": []string{
+			"RS256",
 		},
 		"response_types_supported": []string{
 			"code",
 		},
 		"grant_types_supported": []string{
 			"authorization_code", "refresh_token", "client_credentials", "urn:ietf:params:oauth:grant-type:device_code",
+			"urn:ietf:params:oauth:grant-type:token-exchange", "urn:ietf:params:oauth:grant-type:jwt-bearer",
 		},
 		"subject_types_supported": []string{
 			"public",
 		},
-		"id_token_signing_alg_values_supported": []string{
-			"RS256",
-		},
+		"id_token_signing_alg_values_supported": signingAlgs,
+		"userinfo_signing_alg_values_supported": signingAlgs,
+		// ID tokens and UserInfo responses are encrypted to a key from the client's jwks
+		// or jwks_uri, so no server key is needed (OIDC Core §10.2).
+		"id_token_encryption_alg_values_supported": jose.KeyEncryptionAlgs,
+		"id_token_encryption_enc_values_supported": jose.ContentEncryptionAlgs,
+		"userinfo_encryption_alg_values_supported": jose.KeyEncryptionAlgs,
+		"userinfo_encryption_enc_values_supported": jose.ContentEncryptionAlgs,
+		// client_secret_jwt additionally requires auth.client_secret_encryption_key;
+		// clients cannot register for it when the key is not configured.
 		"token_endpoint_auth_methods_supported": []string{
-			"client_secret_post", "client_secret_basic", "none",
+			"client_secret_post", "client_secret_basic", "client_secret_jwt", "private_key_jwt", "none",
+			"tls_client_auth", "self_signed_tls_client_auth",
 		},
+		"token_endpoint_auth_signing_alg_values_supported": clientAssertionSigningAlgs,
 		"claims_supported": []string{
 			"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce",
 			"name", "preferred_username", "picture",
@@ -48,21 +103,71 @@ func NewDiscoveryService(issuer string) *DiscoveryService {
 			"phone_number", "phone_number_verified",
 			"locale",
 		},
+		"claims_parameter_supported": true,
 		"code_challenge_methods_supported": []string{
 			"S256",
 		},
+		"prompt_values_supported": []string{
+			"none", "login", "consent", "select_account",
+		},
 		"revocation_endpoint": issuer + "/oauth2/revoke",
 		"revocation_endpoint_auth_methods_supported": []string{
-			"client_secret_post", "client_secret_basic",
+			"client_secret_post", "client_secret_basic", "client_secret_jwt", "private_key_jwt",
+			"tls_client_auth", "self_signed_tls_client_auth",
 		},
-		"introspection_endpoint": issuer + "/oauth2/introspect",
+		"revocation_endpoint_auth_signing_alg_values_supported": clientAssertionSigningAlgs,
+		"introspection_endpoint":                                issuer + "/oauth2/introspect",
 		"introspection_endpoint_auth_methods_supported": []string{
-			"client_secret_post", "client_secret_basic",
+			"client_secret_post", "client_secret_basic", "client_secret_jwt", "private_key_jwt",
+			"tls_client_auth", "self_signed_tls_client_auth",
+		},
+		"introspection_endpoint_auth_signing_alg_values_supported": clientAssertionSigningAlgs,
+		// Signed introspection responses (RFC 9701), for resource servers that send
+		// Accept: application/token-introspection+jwt.
+		"introspection_signing_alg_values_supported": []string{
+			"RS256",
 		},
+		"pushed_authorization_request_endpoint": issuer + "/oauth2/par",
+		// PAR is enforced per client (oauth2_clients.require_pushed_authorization_requests),
+		// so the server-wide requirement stays false (RFC 9126 §5).
+		"require_pushed_authorization_requests":          false,
 		"authorization_response_iss_parameter_supported": true,
-		"frontchannel_logout_supported":                  true,
-		"frontchannel_logout_session_supported":          true,
-		"backchannel_logout_supported":                   true,
+		// Request objects are signed with the keys registered for client authentication,
+		// so the accepted algorithms match the client assertion algorithms (RFC 9101 §4).
+		"request_parameter_supported":                 true,
+		"request_object_signing_alg_values_supported": clientAssertionSigningAlgs,
+		// Request objects are only fetched from request_uris the client registered.
+		"request_uri_parameter_supported":  true,
+		"require_request_uri_registration": true,
+		// DPoP proofs are verified with the public key they carry, so only
+		// asymmetric algorithms are accepted (RFC 9449 §5.1).
+		"dpop_signing_alg_values_supported":          asymmetricSigningAlgs,
+		"tls_client_certificate_bound_access_tokens": true,
+		"mtls_endpoint_aliases": map[string]string{
+			"token_endpoint":                        mtlsBaseURL + "/oauth2/token",
+			"revocation_endpoint":                   mtlsBaseURL + "/oauth2/revoke",
+			"introspection_endpoint":                mtlsBaseURL + "/oauth2/introspect",
+			"pushed_authorization_request_endpoint": mtlsBaseURL + "/oauth2/par",
+			"device_authorization_endpoint":         mtlsBaseURL + "/oauth2/device/code",
+			"userinfo_endpoint":                     mtlsBaseURL + "/oidc/userinfo",
+		},
+		"frontchannel_logout_supported":         true,
+		"frontchannel_logout_session_supported": true,
+		"backchannel_logout_supported":          true,
+	}
+	if opts.RequestObjectEncryption {
+		doc["request_object_encryption_alg_values_supported"] = jose.KeyEncryptionAlgs
+		doc["request_object_encryption_enc_values_supported"] = jose.ContentEncryptionAlgs
+	}
+	if opts.BackchannelAuthentication {
+		doc["backchannel_authentication_endpoint"] = issuer + "/oauth2/bc-authorize"
+		doc["backchannel_token_delivery_modes_supported"] = []string{"poll", "ping", "push"}
+		doc["backchannel_user_code_parameter_supported"] = false
+		doc["grant_types_supported"] = append(doc["grant_types_supported"].([]string), "urn:openid:params:grant-type:ciba")
+		doc["mtls_endpoint_aliases"].(map[string]string)["backchannel_authentication_endpoint"] = mtlsBaseURL + "/oauth2/bc-authorize"
+	}
+	if opts.PairwiseSubjects {
+		doc["subject_types_supported"] = []string{"public", "pairwise"}
 	}
 
 	jsonBytes, err := json.Marshal(doc)
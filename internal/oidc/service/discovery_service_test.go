@@ -17,19 +17,19 @@ func unmarshalDiscovery(t *testing.T, raw []byte) map[string]any {
 }
 
 func TestNewDiscoveryService(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	require.NotNil(t, svc)
 }
 
 func TestGetDiscoveryDocument_ContainsIssuer(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	assert.Equal(t, "https://sso.example.com", doc["issuer"])
 }
 
 func TestGetDiscoveryDocument_Endpoints(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	assert.Equal(t, "https://sso.example.com/oauth2/authorize", doc["authorization_endpoint"])
@@ -39,13 +39,14 @@ func TestGetDiscoveryDocument_Endpoints(t *testing.T) {
 }
 
 func TestGetDiscoveryDocument_SupportedValues(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	assert.Contains(t, doc["scopes_supported"], "openid")
 	assert.Contains(t, doc["scopes_supported"], "profile")
 	assert.Contains(t, doc["scopes_supported"], "email")
 	assert.Contains(t, doc["scopes_supported"], "phone")
+	assert.Contains(t, doc["scopes_supported"], "offline_access")
 
 	assert.Contains(t, doc["response_types_supported"], "code")
 
@@ -53,14 +54,17 @@ func TestGetDiscoveryDocument_SupportedValues(t *testing.T) {
 	assert.Contains(t, doc["grant_types_supported"], "refresh_token")
 	assert.Contains(t, doc["grant_types_supported"], "client_credentials")
 	assert.Contains(t, doc["grant_types_supported"], "urn:ietf:params:oauth:grant-type:device_code")
+	assert.Contains(t, doc["grant_types_supported"], "urn:ietf:params:oauth:grant-type:token-exchange")
+	assert.Contains(t, doc["grant_types_supported"], "urn:ietf:params:oauth:grant-type:jwt-bearer")
 
 	assert.Contains(t, doc["subject_types_supported"], "public")
 	assert.Contains(t, doc["id_token_signing_alg_values_supported"], "RS256")
+	assert.Contains(t, doc["userinfo_signing_alg_values_supported"], "RS256")
 	assert.Contains(t, doc["code_challenge_methods_supported"], "S256")
 }
 
 func TestGetDiscoveryDocument_TokenEndpointAuthMethods(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	// JSON unmarshal produces []interface{}, not []string
@@ -68,10 +72,114 @@ func TestGetDiscoveryDocument_TokenEndpointAuthMethods(t *testing.T) {
 	require.True(t, ok)
 	assert.Contains(t, methods, "client_secret_post")
 	assert.Contains(t, methods, "client_secret_basic")
+	assert.Contains(t, methods, "client_secret_jwt")
+	assert.Contains(t, methods, "private_key_jwt")
+}
+
+func TestGetDiscoveryDocument_ClientAssertionAuth(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	for _, endpoint := range []string{"revocation_endpoint", "introspection_endpoint"} {
+		methods, ok := doc[endpoint+"_auth_methods_supported"].([]interface{})
+		require.True(t, ok, endpoint)
+		assert.Contains(t, methods, "private_key_jwt", endpoint)
+		assert.Contains(t, methods, "client_secret_jwt", endpoint)
+	}
+	for _, key := range []string{
+		"token_endpoint_auth_signing_alg_values_supported",
+		"revocation_endpoint_auth_signing_alg_values_supported",
+		"introspection_endpoint_auth_signing_alg_values_supported",
+	} {
+		algs, ok := doc[key].([]interface{})
+		require.True(t, ok, key)
+		assert.Contains(t, algs, "RS256", key)
+		assert.Contains(t, algs, "HS256", key)
+		assert.NotContains(t, algs, "none", key)
+	}
+}
+
+func TestGetDiscoveryDocument_IntrospectionSigning(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	assert.Equal(t, []interface{}{"RS256"}, doc["introspection_signing_alg_values_supported"])
+}
+
+func TestGetDiscoveryDocument_DPoP(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	algs, ok := doc["dpop_signing_alg_values_supported"].([]interface{})
+	require.True(t, ok)
+	assert.Contains(t, algs, "ES256")
+	assert.Contains(t, algs, "RS256")
+	assert.NotContains(t, algs, "HS256")
+}
+
+func TestGetDiscoveryDocument_MutualTLS(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	for _, endpoint := range []string{"token_endpoint", "revocation_endpoint", "introspection_endpoint"} {
+		methods, ok := doc[endpoint+"_auth_methods_supported"].([]interface{})
+		require.True(t, ok, endpoint)
+		assert.Contains(t, methods, "tls_client_auth", endpoint)
+		assert.Contains(t, methods, "self_signed_tls_client_auth", endpoint)
+	}
+	assert.Equal(t, true, doc["tls_client_certificate_bound_access_tokens"])
+	aliases, ok := doc["mtls_endpoint_aliases"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "https://sso.example.com/oauth2/token", aliases["token_endpoint"])
+
+	svc = NewDiscoveryService("https://sso.example.com", DiscoveryOptions{MTLSBaseURL: "https://mtls.sso.example.com"})
+	doc = unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+	aliases, ok = doc["mtls_endpoint_aliases"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "https://mtls.sso.example.com/oauth2/token", aliases["token_endpoint"])
+	assert.Equal(t, "https://mtls.sso.example.com/oidc/userinfo", aliases["userinfo_endpoint"])
+	assert.Equal(t, "https://sso.example.com/oauth2/token", doc["token_endpoint"])
+}
+
+func TestGetDiscoveryDocument_RequestObjects(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	assert.Equal(t, true, doc["request_parameter_supported"])
+	assert.Equal(t, true, doc["request_uri_parameter_supported"])
+	assert.Equal(t, true, doc["require_request_uri_registration"])
+	algs, ok := doc["request_object_signing_alg_values_supported"].([]interface{})
+	require.True(t, ok)
+	assert.Contains(t, algs, "RS256")
+	assert.NotContains(t, algs, "none")
+	assert.NotContains(t, doc, "request_object_encryption_alg_values_supported")
+
+	svc = NewDiscoveryService("https://sso.example.com", DiscoveryOptions{RequestObjectEncryption: true})
+	doc = unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+	assert.Equal(t, []interface{}{"RSA-OAEP", "RSA-OAEP-256"}, doc["request_object_encryption_alg_values_supported"])
+	assert.Contains(t, doc["request_object_encryption_enc_values_supported"], "A256GCM")
+}
+
+func TestGetDiscoveryDocument_ResponseEncryption(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	for _, member := range []string{"id_token", "userinfo"} {
+		assert.Equal(t, []interface{}{"RSA-OAEP", "RSA-OAEP-256"}, doc[member+"_encryption_alg_values_supported"])
+		assert.Contains(t, doc[member+"_encryption_enc_values_supported"], "A128CBC-HS256")
+	}
+}
+
+func TestGetDiscoveryDocument_ResponseModes(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	assert.Equal(t, []interface{}{"query", "form_post", "jwt", "query.jwt", "form_post.jwt"}, doc["response_modes_supported"])
+	assert.Equal(t, []interface{}{"RS256"}, doc["authorization_signing_alg_values_supported"])
 }
 
 func TestGetDiscoveryDocument_ClaimsSupported(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	claims, ok := doc["claims_supported"].([]interface{})
@@ -82,7 +190,7 @@ func TestGetDiscoveryDocument_ClaimsSupported(t *testing.T) {
 }
 
 func TestGetDiscoveryDocument_DifferentIssuer(t *testing.T) {
-	svc := NewDiscoveryService("http://localhost:8080")
+	svc := NewDiscoveryService("http://localhost:8080", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	assert.Equal(t, "http://localhost:8080", doc["issuer"])
@@ -90,36 +198,96 @@ func TestGetDiscoveryDocument_DifferentIssuer(t *testing.T) {
 }
 
 func TestGetDiscoveryDocument_EndSessionEndpoint(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	assert.Equal(t, "https://sso.example.com/oidc/logout", doc["end_session_endpoint"])
 }
 
+func TestGetDiscoveryDocument_CheckSessionIframe(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	assert.Equal(t, "https://sso.example.com/oidc/check_session", doc["check_session_iframe"])
+}
+
 func TestGetDiscoveryDocument_EndSessionEndpoint_Localhost(t *testing.T) {
-	svc := NewDiscoveryService("http://localhost:8080")
+	svc := NewDiscoveryService("http://localhost:8080", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	assert.Equal(t, "http://localhost:8080/oidc/logout", doc["end_session_endpoint"])
 }
 
 func TestGetDiscoveryDocument_DeviceAuthorizationEndpoint(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	assert.Equal(t, "https://sso.example.com/oauth2/device/code", doc["device_authorization_endpoint"])
 }
 
 func TestGetDiscoveryDocument_DeviceAuthorizationEndpoint_Localhost(t *testing.T) {
-	svc := NewDiscoveryService("http://localhost:8080")
+	svc := NewDiscoveryService("http://localhost:8080", DiscoveryOptions{})
 	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
 
 	assert.Equal(t, "http://localhost:8080/oauth2/device/code", doc["device_authorization_endpoint"])
 }
 
 func TestGetDiscoveryDocument_ValidJSON(t *testing.T) {
-	svc := NewDiscoveryService("https://sso.example.com")
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
 	raw := svc.GetDiscoveryDocument()
 	require.NotEmpty(t, raw)
 	assert.Contains(t, string(raw), `"issuer":"https://sso.example.com"`)
 }
+
+func TestGetDiscoveryDocument_PushedAuthorizationRequests(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	assert.Equal(t, "https://sso.example.com/oauth2/par", doc["pushed_authorization_request_endpoint"])
+	assert.Equal(t, false, doc["require_pushed_authorization_requests"])
+}
+
+func TestGetDiscoveryDocument_RegistrationEndpoint(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	assert.Equal(t, "https://sso.example.com/oauth2/register", doc["registration_endpoint"])
+}
+
+func TestGetDiscoveryDocument_PromptValues(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	assert.ElementsMatch(t, []any{"none", "login", "consent", "select_account"}, doc["prompt_values_supported"])
+}
+
+func TestGetDiscoveryDocument_ClaimsParameter(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+
+	assert.Equal(t, true, doc["claims_parameter_supported"])
+}
+
+func TestGetDiscoveryDocument_BackchannelAuthentication(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+	assert.NotContains(t, doc, "backchannel_authentication_endpoint")
+	assert.NotContains(t, doc["grant_types_supported"], "urn:openid:params:grant-type:ciba")
+
+	svc = NewDiscoveryService("https://sso.example.com", DiscoveryOptions{BackchannelAuthentication: true})
+	doc = unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+	assert.Equal(t, "https://sso.example.com/oauth2/bc-authorize", doc["backchannel_authentication_endpoint"])
+	assert.Equal(t, []interface{}{"poll", "ping", "push"}, doc["backchannel_token_delivery_modes_supported"])
+	assert.Equal(t, false, doc["backchannel_user_code_parameter_supported"])
+	assert.Contains(t, doc["grant_types_supported"], "urn:openid:params:grant-type:ciba")
+}
+
+func TestGetDiscoveryDocument_PairwiseSubjects(t *testing.T) {
+	svc := NewDiscoveryService("https://sso.example.com", DiscoveryOptions{})
+	doc := unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+	assert.Equal(t, []interface{}{"public"}, doc["subject_types_supported"])
+
+	svc = NewDiscoveryService("https://sso.example.com", DiscoveryOptions{PairwiseSubjects: true})
+	doc = unmarshalDiscovery(t, svc.GetDiscoveryDocument())
+	assert.Equal(t, []interface{}{"public", "pairwise"}, doc["subject_types_supported"])
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,9 @@ import (
 	accountDomain "github.com/rushairer/gosso/internal/account/domain"
 	accountRepo "github.com/rushairer/gosso/internal/account/repository"
 	accountService "github.com/rushairer/gosso/internal/account/service"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	oauth2Repo "github.com/rushairer/gosso/internal/oauth2/repository"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
 	tokenService "github.com/rushairer/gosso/internal/token/service"
 	"github.com/rushairer/gosso/internal/utility"
 )
@@ -34,6 +38,10 @@ type IDTokenClaims struct {
 	AuthTime          *int64   `json:"auth_time,omitempty"`
 	AMR               []string `json:"amr,omitempty"` // Authentication Methods References (e.g. ["pwd"], ["pwd","otp"], ["swk"])
 	ATHash            string   `json:"at_hash,omitempty"`
+	// CIBA push mode claims (CIBA Core §10.3.1): the refresh token hash and the
+	// auth_req_id the tokens were issued for.
+	RTHash    string `json:"urn:openid:params:jwt:claim:rt_hash,omitempty"`
+	AuthReqID string `json:"urn:openid:params:jwt:claim:auth_req_id,omitempty"`
 }
 
 // IDTokenService OIDC ID Token service
@@ -42,18 +50,28 @@ type IDTokenService struct {
 	issuer         string
 	accountSvc     accountService.AccountService
 	credentialRepo accountRepo.CredentialRepository
+	clientRepo     oauth2Repo.OAuth2ClientRepository
+	subjects       *oauth2Service.SubjectIdentifierService
+	encrypter      *oauth2Service.ResponseEncrypter
 	expiry         time.Duration
 	logger         *zap.Logger
 }
 
 const defaultIDTokenExpiry = 10 * time.Minute
 
-// NewIDTokenService creates a new instance of IDTokenService
+// NewIDTokenService creates a new instance of IDTokenService. subjects derives the sub claim
+// each client receives; when nil, every client receives the account ID. clientRepo supplies
+// each client's id_token_signed_response_alg; when nil, every ID token is signed with RS256.
+// encrypter encrypts the ID tokens of clients that registered id_token_encrypted_response_alg;
+// when nil, issuing ID tokens to such clients fails.
 func NewIDTokenService(
 	tokenSvc *tokenService.TokenService,
 	issuer string,
 	accountSvc accountService.AccountService,
 	credentialRepo accountRepo.CredentialRepository,
+	clientRepo oauth2Repo.OAuth2ClientRepository,
+	subjects *oauth2Service.SubjectIdentifierService,
+	encrypter *oauth2Service.ResponseEncrypter,
 	expiry time.Duration,
 	logger *zap.Logger,
 ) *IDTokenService {
@@ -66,25 +84,56 @@ func NewIDTokenService(
 		issuer:         issuer,
 		accountSvc:     accountSvc,
 		credentialRepo: credentialRepo,
+		clientRepo:     clientRepo,
+		subjects:       subjects,
+		encrypter:      encrypter,
 		expiry:         expiry,
 		logger:         logger,
 	}
 }
 
 // GenerateIDToken generates an OIDC ID Token.
+// requestedClaims are the claims the client requested for the ID token with the claims
+// parameter (OIDC Core §5.5); they are released in addition to those of scopes.
 // authMethods contains AMR values per RFC 8176 (e.g. "pwd", "otp", "swk").
 // Pass nil to omit the amr claim.
-func (s *IDTokenService) GenerateIDToken(ctx context.Context, accountID, clientID string, scopes []string, nonce string, authTime time.Time, accessToken string, authMethods []string) (string, error) {
+func (s *IDTokenService) GenerateIDToken(ctx context.Context, accountID, clientID string, scopes, requestedClaims []string, nonce string, authTime time.Time, accessToken string, authMethods []string) (string, error) {
+	claims, err := s.buildClaims(ctx, accountID, clientID, scopes, requestedClaims, nonce, authTime, accessToken, authMethods)
+	if err != nil {
+		return "", err
+	}
+	return s.sign(ctx, clientID, claims)
+}
+
+// GenerateBackchannelIDToken generates the ID token delivered to a CIBA push mode client.
+// Besides at_hash it carries the rt_hash and auth_req_id claims the client uses to match
+// the pushed tokens to its request (CIBA Core §10.3.1).
+func (s *IDTokenService) GenerateBackchannelIDToken(ctx context.Context, accountID, clientID string, scopes []string, authTime time.Time, accessToken, refreshToken, authReqID string) (string, error) {
+	claims, err := s.buildClaims(ctx, accountID, clientID, scopes, nil, "", authTime, accessToken, nil)
+	if err != nil {
+		return "", err
+	}
+	claims.RTHash = halfHash(refreshToken)
+	claims.AuthReqID = authReqID
+	return s.sign(ctx, clientID, claims)
+}
+
+// buildClaims assembles the claims of an ID token for accountID.
+func (s *IDTokenService) buildClaims(ctx context.Context, accountID, clientID string, scopes, requestedClaims []string, nonce string, authTime time.Time, accessToken string, authMethods []string) (*IDTokenClaims, error) {
 	account, err := s.accountSvc.FindAccountByID(ctx, accountID)
 	if err != nil {
-		return "", fmt.Errorf("find account: %w", err)
+		return nil, fmt.Errorf("find account: %w", err)
+	}
+	subject, err := s.subjects.IssueSubject(ctx, clientID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("subject identifier: %w", err)
 	}
 
 	now := time.Now()
 	claims := &IDTokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
-			Subject:   accountID,
+			Subject:   subject,
 			Audience:  jwt.ClaimStrings{clientID},
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.expiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -96,48 +145,81 @@ func (s *IDTokenService) GenerateIDToken(ctx context.Context, accountID, clientI
 		AMR:      authMethods,
 	}
 
-	// Add claims based on scope
-	// Batch email+phone credential queries into a single DB round-trip when both are needed.
-	needEmail, needPhone := false, false
-	for _, scope := range scopes {
-		switch scope {
-		case "profile":
-			claims.Name = account.DisplayName
-			claims.PreferredUsername = utility.DerefString(account.Username)
-			claims.Picture = utility.DerefString(account.AvatarURL)
-			claims.Locale = account.Locale
-		case "email":
-			needEmail = true
-		case "phone":
-			needPhone = true
-		}
+	// Add claims based on scope and the claims parameter.
+	released := releasedClaims(scopes, requestedClaims)
+	if released["name"] {
+		claims.Name = account.DisplayName
+	}
+	if released["preferred_username"] {
+		claims.PreferredUsername = utility.DerefString(account.Username)
+	}
+	if released["picture"] {
+		claims.Picture = utility.DerefString(account.AvatarURL)
 	}
+	if released["locale"] {
+		claims.Locale = account.Locale
+	}
+	// Batch email+phone credential queries into a single DB round-trip when both are needed.
+	needEmail := released["email"] || released["email_verified"]
+	needPhone := released["phone_number"] || released["phone_number_verified"]
 	if needEmail || needPhone {
-		if contactErr := s.addContactClaims(ctx, accountID, claims, needEmail, needPhone); contactErr != nil {
-			return "", contactErr
+		if contactErr := s.addContactClaims(ctx, accountID, claims, released, needEmail, needPhone); contactErr != nil {
+			return nil, contactErr
 		}
 	}
 
 	// Compute at_hash: SHA-256 half-hash of access token per OIDC Core §2.3.1
-	if accessToken != "" {
-		hash := sha256.Sum256([]byte(accessToken))
-		claims.ATHash = base64.RawURLEncoding.EncodeToString(hash[:len(hash)/2])
+	claims.ATHash = halfHash(accessToken)
+	return claims, nil
+}
+
+// halfHash returns the base64url-encoded left half of the SHA-256 hash of token, as used
+// by at_hash (OIDC Core §3.1.3.6) and rt_hash, or "" for an empty token.
+func halfHash(token string) string {
+	if token == "" {
+		return ""
 	}
+	hash := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(hash[:len(hash)/2])
+}
 
-	// Sign the ID Token using TokenService's RSA private key
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header["kid"] = s.tokenSvc.KeyService().KeyID()
-	tokenString, err := token.SignedString(s.tokenSvc.KeyService().PrivateKey())
+// sign signs claims with the active key of the algorithm clientID registered as its
+// id_token_signed_response_alg (OIDC Registration §2), then encrypts the token when the
+// client registered id_token_encrypted_response_alg (OIDC Core §10.2). A lifetime the client
+// registered for its ID tokens replaces the configured expiry.
+func (s *IDTokenService) sign(ctx context.Context, clientID string, claims *IDTokenClaims) (string, error) {
+	var client *oauth2Domain.OAuth2Client
+	if s.clientRepo != nil {
+		var err error
+		if client, err = s.clientRepo.FindByClientID(ctx, clientID); err != nil {
+			return "", fmt.Errorf("find client: %w", err)
+		}
+	}
+
+	if lifetime := client.IDTokenExpiry(); lifetime > 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(claims.IssuedAt.Add(lifetime))
+	}
+
+	tokenString, err := s.tokenSvc.KeyService().Sign(jwt.NewWithClaims(jwt.GetSigningMethod(client.IDTokenSigningAlg()), claims))
 	if err != nil {
 		return "", fmt.Errorf("sign id token: %w", err)
 	}
+	if client == nil || client.IDTokenEncryptedResponseAlg == "" {
+		return tokenString, nil
+	}
+	if s.encrypter == nil {
+		return "", errors.New("encrypt id token: response encryption is not configured")
+	}
+	if tokenString, err = s.encrypter.EncryptIDToken(ctx, client, tokenString); err != nil {
+		return "", fmt.Errorf("encrypt id token: %w", err)
+	}
 
 	return tokenString, nil
 }
 
 // addContactClaims fetches email and/or phone credentials in a single DB query
-// and populates the corresponding claims on the ID token.
-func (s *IDTokenService) addContactClaims(ctx context.Context, accountID string, claims *IDTokenClaims, needEmail, needPhone bool) error {
+// and populates the released claims on the ID token.
+func (s *IDTokenService) addContactClaims(ctx context.Context, accountID string, claims *IDTokenClaims, released map[string]bool, needEmail, needPhone bool) error {
 	var credTypes []accountDomain.CredentialType
 	if needEmail {
 		credTypes = append(credTypes, accountDomain.CredentialTypeEmail)
@@ -157,13 +239,19 @@ func (s *IDTokenService) addContactClaims(ctx context.Context, accountID string,
 		}
 		switch c.Type {
 		case accountDomain.CredentialTypeEmail:
-			claims.Email = *c.Identifier
-			verified := c.IsVerified()
-			claims.EmailVerified = &verified
+			if released["email"] {
+				claims.Email = *c.Identifier
+			}
+			if released["email_verified"] {
+				claims.EmailVerified = utility.Ptr(c.IsVerified())
+			}
 		case accountDomain.CredentialTypePhone:
-			claims.PhoneNumber = *c.Identifier
-			verified := c.IsVerified()
-			claims.PhoneVerified = &verified
+			if released["phone_number"] {
+				claims.PhoneNumber = *c.Identifier
+			}
+			if released["phone_number_verified"] {
+				claims.PhoneVerified = utility.Ptr(c.IsVerified())
+			}
 		case accountDomain.CredentialTypePassword,
 			accountDomain.CredentialTypeTOTP,
 			accountDomain.CredentialTypeWebAuthn,
@@ -15,6 +15,7 @@ import (
 	accountDomain "github.com/rushairer/gosso/internal/account/domain"
 	accountRepo "github.com/rushairer/gosso/internal/account/repository"
 	accountService "github.com/rushairer/gosso/internal/account/service"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
 	"github.com/rushairer/gosso/internal/testutil"
 	tokenService "github.com/rushairer/gosso/internal/token/service"
 )
@@ -215,7 +216,7 @@ func setupTestIDTokenService(t *testing.T) (*IDTokenService, func()) {
 		},
 	}
 
-	svc := NewIDTokenService(tokenSvc, "http://localhost:8080", accountSvc, credRepo, 0, logger)
+	svc := NewIDTokenService(tokenSvc, "http://localhost:8080", accountSvc, credRepo, nil, nil, nil, 0, logger)
 	return svc, cleanup
 }
 
@@ -227,7 +228,7 @@ func TestGenerateIDToken_OpenID(t *testing.T) {
 
 	ctx := context.Background()
 
-	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid"}, "test-nonce", time.Now(), "", nil)
+	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid"}, nil, "test-nonce", time.Now(), "", nil)
 	require.NoError(t, err)
 	assert.NotEmpty(t, tokenString)
 
@@ -256,7 +257,7 @@ func TestGenerateIDToken_ProfileScope(t *testing.T) {
 
 	ctx := context.Background()
 
-	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid", "profile"}, "", time.Now(), "", nil)
+	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid", "profile"}, nil, "", time.Now(), "", nil)
 	require.NoError(t, err)
 
 	parser := jwt.NewParser()
@@ -278,7 +279,7 @@ func TestGenerateIDToken_EmailScope(t *testing.T) {
 
 	ctx := context.Background()
 
-	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid", "email"}, "", time.Now(), "", nil)
+	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid", "email"}, nil, "", time.Now(), "", nil)
 	require.NoError(t, err)
 
 	parser := jwt.NewParser()
@@ -292,13 +293,36 @@ func TestGenerateIDToken_EmailScope(t *testing.T) {
 	assert.Equal(t, true, claims["email_verified"])
 }
 
+func TestGenerateIDToken_RequestedClaims(t *testing.T) {
+	svc, cleanup := setupTestIDTokenService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// email is requested through the claims parameter without the email scope.
+	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid"}, []string{"email", "name"}, "", time.Now(), "", nil)
+	require.NoError(t, err)
+
+	parser := jwt.NewParser()
+	token, _, err := parser.ParseUnverified(tokenString, jwt.MapClaims{})
+	require.NoError(t, err)
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+
+	assert.Equal(t, "test@example.com", claims["email"])
+	assert.Nil(t, claims["email_verified"])
+	assert.Equal(t, "Test User", claims["name"])
+	assert.Nil(t, claims["preferred_username"])
+}
+
 func TestGenerateIDToken_PhoneScope(t *testing.T) {
 	svc, cleanup := setupTestIDTokenService(t)
 	defer cleanup()
 
 	ctx := context.Background()
 
-	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid", "phone"}, "", time.Now(), "", nil)
+	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid", "phone"}, nil, "", time.Now(), "", nil)
 	require.NoError(t, err)
 
 	parser := jwt.NewParser()
@@ -312,13 +336,32 @@ func TestGenerateIDToken_PhoneScope(t *testing.T) {
 	assert.Equal(t, true, claims["phone_number_verified"])
 }
 
+func TestGenerateBackchannelIDToken(t *testing.T) {
+	svc, cleanup := setupTestIDTokenService(t)
+	defer cleanup()
+
+	tokenString, err := svc.GenerateBackchannelIDToken(context.Background(), "account-001", "client-001", []string{"openid"}, time.Now(), "access-token", "refresh-token", "auth-req-001")
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	require.NoError(t, err)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+
+	assert.Equal(t, "account-001", claims["sub"])
+	assert.Equal(t, "auth-req-001", claims["urn:openid:params:jwt:claim:auth_req_id"])
+	assert.Equal(t, halfHash("refresh-token"), claims["urn:openid:params:jwt:claim:rt_hash"])
+	assert.Equal(t, halfHash("access-token"), claims["at_hash"])
+	assert.Nil(t, claims["nonce"])
+}
+
 func TestGenerateIDToken_RS256(t *testing.T) {
 	svc, cleanup := setupTestIDTokenService(t)
 	defer cleanup()
 
 	ctx := context.Background()
 
-	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid"}, "", time.Now(), "", nil)
+	tokenString, err := svc.GenerateIDToken(ctx, "account-001", "client-001", []string{"openid"}, nil, "", time.Now(), "", nil)
 	require.NoError(t, err)
 
 	parser := jwt.NewParser()
@@ -328,3 +371,17 @@ func TestGenerateIDToken_RS256(t *testing.T) {
 	assert.Equal(t, "RS256", token.Header["alg"])
 	assert.NotEmpty(t, token.Header["kid"])
 }
+
+func TestGenerateIDToken_ClientLifetime(t *testing.T) {
+	svc, cleanup := setupTestIDTokenService(t)
+	defer cleanup()
+	svc.clientRepo = &stubClientRepo{client: &oauth2Domain.OAuth2Client{ClientID: "client-001", IDTokenLifetime: 120}}
+
+	tokenString, err := svc.GenerateIDToken(context.Background(), "account-001", "client-001", []string{"openid"}, nil, "", time.Now(), "", nil)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(tokenString, claims)
+	require.NoError(t, err)
+	assert.Equal(t, 120.0, claims["exp"].(float64)-claims["iat"].(float64))
+}
@@ -1,161 +1,81 @@
 package service
 
 import (
-	"crypto/rsa"
-	"encoding/base64"
+	"crypto"
 	"encoding/json"
-	"fmt"
-	"math/big"
 	"sync"
 
+	"github.com/rushairer/gosso/internal/jose"
 	tokenService "github.com/rushairer/gosso/internal/token/service"
-	"github.com/rushairer/gosso/internal/utility"
 )
 
 // JWKSService OIDC JWKS service
 type JWKSService struct {
-	keySvc      *tokenService.KeyService
-	mu          sync.RWMutex
-	jwksJSON    []byte             // pre-marshaled JWKS bytes, rebuilt on Reload/ClearPreviousKey
-	previousKey *map[string]string // previous key for rotation overlap, nil if none
+	keySvc    *tokenService.KeyService
+	encKeySvc *tokenService.KeyService // request object encryption key, nil if none
+	mu        sync.RWMutex
+	jwksJSON  []byte // pre-marshaled JWKS bytes, rebuilt when the published keys change
+	version   uint64 // key service version jwksJSON was built from
 }
 
 // NewJWKSService creates a new instance of JWKSService.
-// The JWKS document is pre-marshaled once since the key is stable for the service lifetime.
-func NewJWKSService(keySvc *tokenService.KeyService) *JWKSService {
+// The JWKS document publishes every key of keySvc that is pending, active or retiring,
+// and is rebuilt when the keyring changes. encKeySvc, if not nil, is published with use
+// "enc" so clients can encrypt request objects to it (RFC 9101 §6.1); it is never used to
+// verify signatures.
+func NewJWKSService(keySvc, encKeySvc *tokenService.KeyService) *JWKSService {
 	s := &JWKSService{
-		keySvc: keySvc,
+		keySvc:    keySvc,
+		encKeySvc: encKeySvc,
 	}
+	s.version = keySvc.Version()
 	s.jwksJSON = s.marshalJWKS()
 	return s
 }
 
 // GetJWKS returns the pre-marshaled JWKS JSON bytes.
 // The returned slice is safe to send directly to a response writer; no copy is needed
-// because the bytes are only rebuilt on Reload/ClearPreviousKey, never mutated in place.
+// because the bytes are only ever replaced, never mutated in place.
 func (s *JWKSService) GetJWKS() []byte {
+	version := s.keySvc.Version()
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.jwksJSON
-}
-
-// GetPublicKeyByKID returns the RSA public key matching the given key ID.
-// Checks the current key first, then the previous key (for rotation overlap).
-// Returns nil and an error if no matching key is found.
-func (s *JWKSService) GetPublicKeyByKID(kid string) (*rsa.PublicKey, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	// Check current key
-	if s.keySvc.KeyID() == kid {
-		return s.keySvc.PublicKey(), nil
-	}
-
-	// Check previous key (rotation overlap)
-	if s.previousKey != nil && (*s.previousKey)["kid"] == kid {
-		return s.reconstructPublicKey(*s.previousKey)
-	}
-
-	return nil, fmt.Errorf("no key found for kid %q", kid)
-}
-
-// GetAllPublicKeys returns all RSA public keys currently available for signature
-// verification (current key + previous key during rotation overlap).
-// Used as a fallback when a token has no "kid" header and cannot be matched by ID.
-func (s *JWKSService) GetAllPublicKeys() []*rsa.PublicKey {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	keys := []*rsa.PublicKey{s.keySvc.PublicKey()}
-	if s.previousKey != nil {
-		if pubKey, err := s.reconstructPublicKey(*s.previousKey); err == nil {
-			keys = append(keys, pubKey)
-		}
-	}
-	return keys
-}
-
-// reconstructPublicKey rebuilds an RSA public key from JWK parameters (n, e).
-func (s *JWKSService) reconstructPublicKey(jwk map[string]string) (*rsa.PublicKey, error) {
-	nStr, ok := jwk["n"]
-	if !ok {
-		return nil, fmt.Errorf("missing 'n' in JWK")
-	}
-	eStr, ok := jwk["e"]
-	if !ok {
-		return nil, fmt.Errorf("missing 'e' in JWK")
-	}
-
-	nBytes, err := base64.RawURLEncoding.DecodeString(nStr)
-	if err != nil {
-		return nil, fmt.Errorf("decode 'n': %w", err)
-	}
-	eBytes, err := base64.RawURLEncoding.DecodeString(eStr)
-	if err != nil {
-		return nil, fmt.Errorf("decode 'e': %w", err)
-	}
-
-	n := new(big.Int).SetBytes(nBytes)
-	e := 0
-	for _, b := range eBytes {
-		e = e<<8 + int(b)
+	if s.version == version {
+		defer s.mu.RUnlock()
+		return s.jwksJSON
 	}
+	s.mu.RUnlock()
 
-	return &rsa.PublicKey{N: n, E: e}, nil
-}
-
-// Reload re-computes the JWKS document from the current RSA key.
-// Call this after a key rotation (e.g., via SIGHUP handler) to update the
-// published key set without restarting the service.
-//
-// The previous key is retained and published alongside the new key during
-// the rotation window. Call ClearPreviousKey() after the old key's
-// longest-lived token has expired to remove it from the JWKS.
-func (s *JWKSService) Reload() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	// Save current key as previous before replacing
-	currentKey := s.buildCurrentKeyEntry()
-	s.previousKey = &currentKey
-	s.jwksJSON = s.marshalJWKS()
-}
-
-// ClearPreviousKey removes the previous key from the JWKS document.
-// Call this after the old key's longest-lived token has expired.
-func (s *JWKSService) ClearPreviousKey() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.previousKey = nil
-	s.jwksJSON = s.marshalJWKS()
+	if s.version != version {
+		s.jwksJSON = s.marshalJWKS()
+		s.version = version
+	}
+	return s.jwksJSON
 }
 
-// buildCurrentKeyEntry builds a single key entry from the current key service.
-func (s *JWKSService) buildCurrentKeyEntry() map[string]string {
-	pubKey := s.keySvc.PublicKey()
-	n := base64.RawURLEncoding.EncodeToString(pubKey.N.Bytes())
-	eBytes, err := utility.BigEndianBytes(pubKey.E)
+// keyEntry builds the JWK of pub.
+func keyEntry(kid string, pub crypto.PublicKey, use, alg string) *jose.JWK {
+	jwk, err := jose.NewJWK(pub)
 	if err != nil {
-		// RSA exponents are always positive; this should never happen.
-		panic("jwks: unexpected BigEndianBytes error: " + err.Error())
-	}
-	e := base64.RawURLEncoding.EncodeToString(eBytes)
-	return map[string]string{
-		"kty": "RSA",
-		"kid": s.keySvc.KeyID(),
-		"alg": "RS256",
-		"use": "sig",
-		"n":   n,
-		"e":   e,
+		// The key service only holds RSA, EC and Ed25519 keys; this should never happen.
+		panic("jwks: " + err.Error())
 	}
+	jwk.Kid, jwk.Use, jwk.Alg = kid, use, alg
+	return jwk
 }
 
-// marshalJWKS constructs the JWKS document from the current key material,
-// including the previous key if one exists (for rotation overlap),
-// and returns the pre-marshaled JSON bytes.
+// marshalJWKS constructs the JWKS document from the published signing keys and the
+// encryption key, and returns the pre-marshaled JSON bytes.
 func (s *JWKSService) marshalJWKS() []byte {
-	keys := []map[string]string{s.buildCurrentKeyEntry()}
-	if s.previousKey != nil {
-		keys = append(keys, *s.previousKey)
+	keys := []*jose.JWK{}
+	for _, key := range s.keySvc.Keys() {
+		if key.Published() {
+			keys = append(keys, keyEntry(key.KeyID, key.PublicKey, "sig", key.Algorithm))
+		}
+	}
+	if s.encKeySvc != nil {
+		keys = append(keys, keyEntry(s.encKeySvc.KeyID(), s.encKeySvc.PublicKey(), "enc", jose.KeyAlgRSAOAEP256))
 	}
 	jwks := map[string]any{
 		"keys": keys,
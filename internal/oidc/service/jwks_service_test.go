@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,7 +29,7 @@ func newTestJWKSService(t *testing.T) *JWKSService {
 	t.Helper()
 	keySvc, err := tokenService.NewKeyService("", "test-kid", false, 0, zap.NewNop())
 	require.NoError(t, err)
-	return NewJWKSService(keySvc)
+	return NewJWKSService(keySvc, nil)
 }
 
 func TestGetJWKS_ContainsKeys(t *testing.T) {
@@ -38,6 +39,24 @@ func TestGetJWKS_ContainsKeys(t *testing.T) {
 	require.Len(t, result.Keys, 1)
 }
 
+func TestGetJWKS_EncryptionKey(t *testing.T) {
+	keySvc, err := tokenService.NewKeyService("", "test-kid", false, 0, zap.NewNop())
+	require.NoError(t, err)
+	encKeySvc, err := tokenService.NewKeyService("", "enc-kid", false, 0, zap.NewNop())
+	require.NoError(t, err)
+	svc := NewJWKSService(keySvc, encKeySvc)
+
+	result := unmarshalJWKS(t, svc.GetJWKS())
+	require.Len(t, result.Keys, 2)
+	assert.Equal(t, "enc-kid", result.Keys[1]["kid"])
+	assert.Equal(t, "enc", result.Keys[1]["use"])
+	assert.Equal(t, "RSA-OAEP-256", result.Keys[1]["alg"])
+
+	// The encryption key is never used to verify signatures.
+	_, ok := keySvc.VerificationKey("enc-kid")
+	assert.False(t, ok)
+}
+
 func TestGetJWKS_KeyFields(t *testing.T) {
 	svc := newTestJWKSService(t)
 	result := unmarshalJWKS(t, svc.GetJWKS())
@@ -77,19 +96,45 @@ func TestGetJWKS_ExponentIsStandard(t *testing.T) {
 	assert.Equal(t, []byte{1, 0, 1}, eBytes)
 }
 
-func TestReload_RebuildsJWKS(t *testing.T) {
-	svc := newTestJWKSService(t)
+func TestGetJWKS_FollowsKeyring(t *testing.T) {
+	keySvc, err := tokenService.NewKeyringKeyService(tokenService.KeyringOptions{Dir: t.TempDir(), KeyBits: 2048}, zap.NewNop())
+	require.NoError(t, err)
+	svc := NewJWKSService(keySvc, nil)
+	first := keySvc.KeyID()
+
 	before := unmarshalJWKS(t, svc.GetJWKS())
+	require.Len(t, before.Keys, 1)
+	assert.Equal(t, first, before.Keys[0]["kid"])
 
-	// Capture the "n" value before reload
-	nBefore := before.Keys[0]["n"]
+	rotated, err := keySvc.Rotate("")
+	require.NoError(t, err)
+	require.Len(t, rotated, 1)
+	after := unmarshalJWKS(t, svc.GetJWKS())
+	require.Len(t, after.Keys, 2, "the retiring key stays published")
+	assert.ElementsMatch(t, []string{first, rotated[0].KeyID}, []string{after.Keys[0]["kid"], after.Keys[1]["kid"]})
 
-	svc.Reload()
+	require.NoError(t, keySvc.Revoke(first))
+	revoked := unmarshalJWKS(t, svc.GetJWKS())
+	require.Len(t, revoked.Keys, 1)
+	assert.Equal(t, rotated[0].KeyID, revoked.Keys[0]["kid"])
+}
 
-	after := unmarshalJWKS(t, svc.GetJWKS())
+func TestGetJWKS_SigningAlgorithms(t *testing.T) {
+	keySvc, err := tokenService.NewKeyringKeyService(tokenService.KeyringOptions{
+		Dir: t.TempDir(), Overlap: time.Hour, KeyBits: 2048, Algorithms: []string{"RS256", "ES256", "EdDSA"},
+	}, zap.NewNop())
+	require.NoError(t, err)
 
-	// Key material is unchanged (same RSA key), but the map is rebuilt
-	assert.Equal(t, nBefore, after.Keys[0]["n"])
-	assert.Equal(t, "test-kid", after.Keys[0]["kid"])
-	assert.Equal(t, "RS256", after.Keys[0]["alg"])
+	byAlg := map[string]map[string]string{}
+	for _, key := range unmarshalJWKS(t, NewJWKSService(keySvc, nil).GetJWKS()).Keys {
+		byAlg[key["alg"]] = key
+	}
+	require.Len(t, byAlg, 3)
+	assert.Equal(t, "RSA", byAlg["RS256"]["kty"])
+	assert.Equal(t, "EC", byAlg["ES256"]["kty"])
+	assert.Equal(t, "P-256", byAlg["ES256"]["crv"])
+	assert.NotEmpty(t, byAlg["ES256"]["y"])
+	assert.Equal(t, "OKP", byAlg["EdDSA"]["kty"])
+	assert.Equal(t, "Ed25519", byAlg["EdDSA"]["crv"])
+	assert.Equal(t, "sig", byAlg["EdDSA"]["use"])
 }
@@ -13,8 +13,11 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
 	oauth2Repo "github.com/rushairer/gosso/internal/oauth2/repository"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
 	sessionService "github.com/rushairer/gosso/internal/session/service"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
 	tokenService "github.com/rushairer/gosso/internal/token/service"
 	"github.com/rushairer/gosso/internal/utility"
 )
@@ -24,21 +27,22 @@ import (
 type LogoutService struct {
 	tokenSvc   *tokenService.TokenService
 	sessionSvc *sessionService.SessionService
-	jwksSvc    *JWKSService // for key rotation fallback in id_token_hint validation
 	clientRepo oauth2Repo.OAuth2ClientRepository
+	subjects   *oauth2Service.SubjectIdentifierService
 	httpClient *http.Client
 	issuer     string
 	logger     *zap.Logger
 	parser     *jwt.Parser
 }
 
-// NewLogoutService creates a new LogoutService.
+// NewLogoutService creates a new LogoutService. subjects maps between account IDs and the
+// subject identifiers clients receive; when nil, subjects are account IDs.
 func NewLogoutService(
 	tokenSvc *tokenService.TokenService,
 	sessionSvc *sessionService.SessionService,
-	jwksSvc *JWKSService,
 	issuer string,
 	clientRepo oauth2Repo.OAuth2ClientRepository,
+	subjects *oauth2Service.SubjectIdentifierService,
 	httpClient *http.Client,
 	logger *zap.Logger,
 ) *LogoutService {
@@ -48,12 +52,12 @@ func NewLogoutService(
 	return &LogoutService{
 		tokenSvc:   tokenSvc,
 		sessionSvc: sessionSvc,
-		jwksSvc:    jwksSvc,
 		clientRepo: clientRepo,
+		subjects:   subjects,
 		httpClient: httpClient,
 		issuer:     issuer,
 		logger:     logger,
-		parser:     jwt.NewParser(jwt.WithoutClaimsValidation()),
+		parser:     jwt.NewParser(jwt.WithoutClaimsValidation(), jwt.WithValidMethods(tokenDomain.SigningAlgs)),
 	}
 }
 
@@ -61,7 +65,8 @@ func NewLogoutService(
 // The OP SHOULD accept expired ID tokens, so expiry is not checked.
 // Only signature, issuer, and audience are validated.
 // If clientID is non-empty, the token's audience must contain it (OIDC RP-Initiated Logout §2).
-func (s *LogoutService) ValidateIDTokenHint(tokenString string, clientID string) (*IDTokenClaims, error) {
+// The subject of the returned claims is the account ID, also for pairwise subject identifiers.
+func (s *LogoutService) ValidateIDTokenHint(ctx context.Context, tokenString string, clientID string) (*IDTokenClaims, error) {
 	if tokenString == "" {
 		return nil, fmt.Errorf("empty id_token_hint")
 	}
@@ -69,51 +74,13 @@ func (s *LogoutService) ValidateIDTokenHint(tokenString string, clientID string)
 	parser := s.parser
 	claims := &IDTokenClaims{}
 
-	// keyFunc resolves the RSA public key for signature verification.
-	// Tries the current key first; on failure, falls back to JWKS-published keys
-	// (including the previous key during rotation overlap).
+	// Keyfunc resolves the published key named by the kid header, so hints signed by a
+	// key that has since been rotated out still verify until it is removed from the JWKS.
 	keyFunc := func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		// Try current key first
-		currentKey := s.tokenSvc.KeyService().PublicKey()
-		return currentKey, nil
+		return s.tokenSvc.KeyService().Keyfunc(token)
 	}
 
 	token, err := parser.ParseWithClaims(tokenString, claims, keyFunc)
-	if err != nil && s.jwksSvc != nil {
-		// Current key failed — try JWKS fallback (handles key rotation overlap).
-		// Extract kid from token header and look up in JWKS.
-		var kid string
-		if hdr, _, parseErr := parser.ParseUnverified(tokenString, jwt.MapClaims{}); parseErr == nil {
-			if k, ok := hdr.Header["kid"].(string); ok {
-				kid = k
-			}
-		}
-		if kid != "" && kid != s.tokenSvc.KeyService().KeyID() {
-			// kid present and differs from current key — look up by ID.
-			if pubKey, jwksErr := s.jwksSvc.GetPublicKeyByKID(kid); jwksErr == nil {
-				claims = &IDTokenClaims{}
-				token, err = parser.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (any, error) {
-					return pubKey, nil
-				})
-			}
-		} else {
-			// kid is absent or matches current key — try all available keys.
-			// This handles the case where a token was signed with the previous key
-			// but has no "kid" header (valid per JWT spec), e.g. during key rotation.
-			for _, pubKey := range s.jwksSvc.GetAllPublicKeys() {
-				claims = &IDTokenClaims{}
-				token, err = parser.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (any, error) {
-					return pubKey, nil
-				})
-				if err == nil && token.Valid {
-					break
-				}
-			}
-		}
-	}
 	if err != nil {
 		return nil, fmt.Errorf("parse id_token_hint: %w", err)
 	}
@@ -143,6 +110,20 @@ func (s *LogoutService) ValidateIDTokenHint(tokenString string, clientID string)
 		}
 	}
 
+	// Map a pairwise subject back to the account it was issued for (OIDC Core §8.1).
+	issuedTo := clientID
+	if issuedTo == "" {
+		issuedTo = claims.AZP
+	}
+	if issuedTo == "" {
+		issuedTo = claims.Audience[0]
+	}
+	accountID, err := s.subjects.ResolveAccountID(ctx, issuedTo, claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("resolve id_token_hint subject: %w", err)
+	}
+	claims.Subject = accountID
+
 	return claims, nil
 }
 
@@ -256,16 +237,17 @@ type LogoutTokenClaims struct {
 	SID    string                    `json:"sid,omitempty"`
 }
 
-// sendBackChannelLogoutAsync asynchronously sends a logout_token to the given
-// backchannel_logout_uri for a single client. Fire-and-forget: errors are logged
-// but do not block the caller.
-func (s *LogoutService) sendBackChannelLogoutAsync(clientID, accountID, sessionID, backchannelURI string, sessionRequired bool) {
+// sendBackChannelLogoutAsync asynchronously sends a logout_token to the
+// backchannel_logout_uri of client. subject is the subject identifier the client knows
+// the account by. Fire-and-forget: errors are logged but do not block the caller.
+func (s *LogoutService) sendBackChannelLogoutAsync(client *oauth2Domain.OAuth2Client, subject, sessionID string) {
+	clientID, backchannelURI := client.ClientID, client.BackchannelLogoutURI
 	go func() {
 		// 5-second timeout for the HTTP POST
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		logoutToken, err := s.generateLogoutToken(clientID, accountID, sessionID, sessionRequired)
+		logoutToken, err := s.generateLogoutToken(client, subject, sessionID)
 		if err != nil {
 			s.logger.Error("Failed to generate back-channel logout token",
 				zap.String("client_id", clientID), zap.Error(err))
@@ -303,13 +285,14 @@ func (s *LogoutService) sendBackChannelLogoutAsync(clientID, accountID, sessionI
 }
 
 // generateLogoutToken creates a signed JWT logout token per OIDC Back-Channel Logout §2.4.
-func (s *LogoutService) generateLogoutToken(clientID, accountID, sessionID string, sessionRequired bool) (string, error) {
+// It is signed like the client's ID tokens, with its id_token_signed_response_alg.
+func (s *LogoutService) generateLogoutToken(client *oauth2Domain.OAuth2Client, subject, sessionID string) (string, error) {
 	now := time.Now()
 	claims := &LogoutTokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
-			Subject:   accountID,
-			Audience:  jwt.ClaimStrings{clientID},
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{client.ClientID},
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
 			ID:        uuid.New().String(),
@@ -318,19 +301,12 @@ func (s *LogoutService) generateLogoutToken(clientID, accountID, sessionID strin
 			"http://schemas.openid.net/event/backchannel-logout": {},
 		},
 	}
-	if sessionRequired && sessionID != "" {
+	if client.BackchannelLogoutSessionRequired && sessionID != "" {
 		claims.SID = sessionID
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header["kid"] = s.tokenSvc.KeyService().KeyID()
-
-	privateKey := s.tokenSvc.KeyService().PrivateKey()
-	if privateKey == nil {
-		return "", fmt.Errorf("no private key available for signing logout token")
-	}
-
-	return token.SignedString(privateKey)
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(client.IDTokenSigningAlg()), claims)
+	return s.tokenSvc.KeyService().Sign(token)
 }
 
 // triggerBackChannelLogout sends back-channel logout notifications to all clients
@@ -348,6 +324,12 @@ func (s *LogoutService) triggerBackChannelLogout(ctx context.Context, accountID,
 	}
 
 	for _, c := range clients {
-		s.sendBackChannelLogoutAsync(c.ClientID, accountID, sessionID, c.BackchannelLogoutURI, c.BackchannelLogoutSessionRequired)
+		subject, err := s.subjects.Subject(c, accountID)
+		if err != nil {
+			s.logger.Warn("Failed to derive back-channel logout subject",
+				zap.String("client_id", c.ClientID), zap.Error(err))
+			continue
+		}
+		s.sendBackChannelLogoutAsync(c, subject, sessionID)
 	}
 }
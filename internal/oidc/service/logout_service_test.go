@@ -10,9 +10,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
 	sessionDomain "github.com/rushairer/gosso/internal/session/domain"
 	sessionService "github.com/rushairer/gosso/internal/session/service"
 	"github.com/rushairer/gosso/internal/testutil"
+	tokenDomain "github.com/rushairer/gosso/internal/token/domain"
 	tokenService "github.com/rushairer/gosso/internal/token/service"
 )
 
@@ -27,7 +29,7 @@ func setupTestLogoutService(t *testing.T) (*LogoutService, *tokenService.KeyServ
 	require.NoError(t, err)
 	tokenSvc, err := tokenService.NewTokenService(keySvc, "https://sso.example.com", 15*time.Minute, 720*time.Hour, redisClient, blacklistSvc, nil, false, logger)
 	require.NoError(t, err)
-	logoutSvc := NewLogoutService(tokenSvc, nil, nil, "https://sso.example.com", nil, nil, logger)
+	logoutSvc := NewLogoutService(tokenSvc, nil, "https://sso.example.com", nil, nil, nil, logger)
 
 	return logoutSvc, keySvc
 }
@@ -58,7 +60,7 @@ func TestValidateIDTokenHint_Valid(t *testing.T) {
 
 	tokenString := signTestIDToken(t, keySvc, "https://sso.example.com", "account-001", []string{"client-001"}, false)
 
-	claims, err := svc.ValidateIDTokenHint(tokenString, "")
+	claims, err := svc.ValidateIDTokenHint(context.Background(), tokenString, "")
 	require.NoError(t, err)
 	assert.Equal(t, "account-001", claims.Subject)
 	assert.Equal(t, "https://sso.example.com", claims.Issuer)
@@ -70,7 +72,7 @@ func TestValidateIDTokenHint_ExpiredTokenAccepted(t *testing.T) {
 
 	tokenString := signTestIDToken(t, keySvc, "https://sso.example.com", "account-001", []string{"client-001"}, true)
 
-	claims, err := svc.ValidateIDTokenHint(tokenString, "")
+	claims, err := svc.ValidateIDTokenHint(context.Background(), tokenString, "")
 	require.NoError(t, err)
 	assert.Equal(t, "account-001", claims.Subject)
 }
@@ -78,7 +80,7 @@ func TestValidateIDTokenHint_ExpiredTokenAccepted(t *testing.T) {
 func TestValidateIDTokenHint_EmptyString(t *testing.T) {
 	svc, _ := setupTestLogoutService(t)
 
-	_, err := svc.ValidateIDTokenHint("", "")
+	_, err := svc.ValidateIDTokenHint(context.Background(), "", "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "empty")
 }
@@ -86,7 +88,7 @@ func TestValidateIDTokenHint_EmptyString(t *testing.T) {
 func TestValidateIDTokenHint_InvalidJWT(t *testing.T) {
 	svc, _ := setupTestLogoutService(t)
 
-	_, err := svc.ValidateIDTokenHint("not-a-jwt", "")
+	_, err := svc.ValidateIDTokenHint(context.Background(), "not-a-jwt", "")
 	assert.Error(t, err)
 }
 
@@ -95,7 +97,7 @@ func TestValidateIDTokenHint_WrongIssuer(t *testing.T) {
 
 	tokenString := signTestIDToken(t, keySvc, "https://other-issuer.com", "account-001", []string{"client-001"}, false)
 
-	_, err := svc.ValidateIDTokenHint(tokenString, "")
+	_, err := svc.ValidateIDTokenHint(context.Background(), tokenString, "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "issuer mismatch")
 }
@@ -105,7 +107,7 @@ func TestValidateIDTokenHint_NoAudience(t *testing.T) {
 
 	tokenString := signTestIDToken(t, keySvc, "https://sso.example.com", "account-001", nil, false)
 
-	_, err := svc.ValidateIDTokenHint(tokenString, "")
+	_, err := svc.ValidateIDTokenHint(context.Background(), tokenString, "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "no audience")
 }
@@ -119,7 +121,7 @@ func TestValidateIDTokenHint_BadSignature(t *testing.T) {
 
 	tokenString := signTestIDToken(t, otherKeySvc, "https://sso.example.com", "account-001", []string{"client-001"}, false)
 
-	_, err = svc.ValidateIDTokenHint(tokenString, "")
+	_, err = svc.ValidateIDTokenHint(context.Background(), tokenString, "")
 	assert.Error(t, err)
 }
 
@@ -138,7 +140,7 @@ func TestValidateIDTokenHint_WrongAlgorithm(t *testing.T) {
 	tokenString, err := token.SignedString([]byte("hmac-secret"))
 	require.NoError(t, err)
 
-	_, err = svc.ValidateIDTokenHint(tokenString, "")
+	_, err = svc.ValidateIDTokenHint(context.Background(), tokenString, "")
 	assert.Error(t, err)
 }
 
@@ -163,7 +165,7 @@ func setupTestLogoutServiceWithSession(t *testing.T) (*LogoutService, *tokenServ
 	})
 	require.NoError(t, err)
 
-	logoutSvc := NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, logger)
+	logoutSvc := NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, logger)
 	return logoutSvc, keySvc, sessionSvc
 }
 
@@ -225,7 +227,7 @@ func TestLogoutByAccountID_SessionServiceError(t *testing.T) {
 	// RevokeAllForAccount now gracefully skips token revocation and logs a warning.
 	sessionSvc, err := sessionService.NewSessionServiceWithConfig(redisClient, logger, sessionService.SessionConfig{})
 	require.NoError(t, err)
-	logoutSvc := NewLogoutService(tokenSvc, sessionSvc, nil, "https://sso.example.com", nil, nil, logger)
+	logoutSvc := NewLogoutService(tokenSvc, sessionSvc, "https://sso.example.com", nil, nil, nil, logger)
 
 	err = logoutSvc.LogoutByAccountID(context.Background(), "account-001")
 	assert.NoError(t, err)
@@ -297,7 +299,7 @@ func TestGetFrontChannelLogoutURIs_NilClientRepo(t *testing.T) {
 func TestGenerateLogoutToken_Success(t *testing.T) {
 	svc, keySvc := setupTestLogoutService(t)
 
-	tokenString, err := svc.generateLogoutToken("client-001", "account-001", "session-001", true)
+	tokenString, err := svc.generateLogoutToken(&oauth2Domain.OAuth2Client{ClientID: "client-001", BackchannelLogoutSessionRequired: true}, "account-001", "session-001")
 	require.NoError(t, err)
 	assert.NotEmpty(t, tokenString)
 
@@ -331,10 +333,18 @@ func TestGenerateLogoutToken_Success(t *testing.T) {
 	assert.Equal(t, keySvc.KeyID(), token.Header["kid"])
 }
 
+func TestGenerateLogoutToken_ClientSigningAlg(t *testing.T) {
+	svc, _ := setupTestLogoutService(t)
+
+	// Logout tokens use the client's ID token algorithm, which needs an active key.
+	_, err := svc.generateLogoutToken(&oauth2Domain.OAuth2Client{ClientID: "client-001", IDTokenSignedResponseAlg: "ES256"}, "account-001", "")
+	assert.ErrorIs(t, err, tokenDomain.ErrSigningAlgUnavailable)
+}
+
 func TestGenerateLogoutToken_WithoutSessionRequired(t *testing.T) {
 	svc, _ := setupTestLogoutService(t)
 
-	tokenString, err := svc.generateLogoutToken("client-001", "account-001", "session-001", false)
+	tokenString, err := svc.generateLogoutToken(&oauth2Domain.OAuth2Client{ClientID: "client-001"}, "account-001", "session-001")
 	require.NoError(t, err)
 
 	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
@@ -349,7 +359,7 @@ func TestGenerateLogoutToken_WithoutSessionRequired(t *testing.T) {
 func TestGenerateLogoutToken_WithSessionRequired(t *testing.T) {
 	svc, _ := setupTestLogoutService(t)
 
-	tokenString, err := svc.generateLogoutToken("client-001", "account-001", "session-001", true)
+	tokenString, err := svc.generateLogoutToken(&oauth2Domain.OAuth2Client{ClientID: "client-001", BackchannelLogoutSessionRequired: true}, "account-001", "session-001")
 	require.NoError(t, err)
 
 	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
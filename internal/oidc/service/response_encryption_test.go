@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/jose"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	oauth2Repo "github.com/rushairer/gosso/internal/oauth2/repository"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
+)
+
+// stubClientRepo returns client from FindByClientID; other methods are not implemented.
+type stubClientRepo struct {
+	oauth2Repo.OAuth2ClientRepository
+	client *oauth2Domain.OAuth2Client
+}
+
+func (r *stubClientRepo) FindByClientID(context.Context, string) (*oauth2Domain.OAuth2Client, error) {
+	return r.client, nil
+}
+
+// newEncryptingClient returns a client with an RSA encryption key in its jwks.
+func newEncryptingClient(t *testing.T) (*oauth2Domain.OAuth2Client, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&key.PublicKey)
+	require.NoError(t, err)
+	jwk.Kid, jwk.Use = "enc-001", "enc"
+	jwks, err := json.Marshal(map[string]any{"keys": []*jose.JWK{jwk}})
+	require.NoError(t, err)
+	return &oauth2Domain.OAuth2Client{ClientID: "client-001", JWKS: jwks}, key
+}
+
+func TestGenerateIDToken_Encrypted(t *testing.T) {
+	svc, cleanup := setupTestIDTokenService(t)
+	defer cleanup()
+	client, key := newEncryptingClient(t)
+	client.IDTokenEncryptedResponseAlg = jose.KeyAlgRSAOAEP
+	client.IDTokenEncryptedResponseEnc = "A256GCM"
+	svc.clientRepo = &stubClientRepo{client: client}
+
+	// Without an encrypter the token is refused rather than sent unencrypted.
+	_, err := svc.GenerateIDToken(context.Background(), "account-001", "client-001", []string{"openid", "email"}, nil, "", time.Now(), "", nil)
+	require.Error(t, err)
+
+	svc.encrypter = oauth2Service.NewResponseEncrypter(nil)
+	tokenString, err := svc.GenerateIDToken(context.Background(), "account-001", "client-001", []string{"openid", "email"}, nil, "", time.Now(), "", nil)
+	require.NoError(t, err)
+	require.True(t, jose.IsJWE(tokenString))
+
+	plaintext, header, err := jose.Decrypt(tokenString, key)
+	require.NoError(t, err)
+	assert.Equal(t, "JWT", header.Cty)
+	assert.Equal(t, "enc-001", header.Kid)
+	token, err := jwt.Parse(string(plaintext), svc.tokenSvc.KeyService().Keyfunc)
+	require.NoError(t, err)
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, "account-001", claims["sub"])
+	assert.Equal(t, "test@example.com", claims["email"])
+}
+
+func TestUserInfo_EncodeResponse(t *testing.T) {
+	svc := newTestUserInfoService(t)
+	info := map[string]any{"sub": "account-001", "name": "Test User"}
+
+	// Without a client repository, and for first-party tokens, responses stay JSON.
+	_, ok, err := svc.EncodeResponse(context.Background(), "client-001", info)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	client, key := newEncryptingClient(t)
+	svc.clientRepo = &stubClientRepo{client: client}
+	svc.encrypter = oauth2Service.NewResponseEncrypter(nil)
+	_, ok, err = svc.EncodeResponse(context.Background(), "client-001", info)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = svc.EncodeResponse(context.Background(), "", info)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	client.UserinfoEncryptedResponseAlg = jose.KeyAlgRSAOAEP256
+	response, ok, err := svc.EncodeResponse(context.Background(), "client-001", info)
+	require.NoError(t, err)
+	require.True(t, ok)
+	plaintext, header, err := jose.Decrypt(response, key)
+	require.NoError(t, err)
+	assert.Equal(t, oauth2Domain.DefaultEncryptedResponseEnc, header.Enc)
+	assert.JSONEq(t, `{"sub":"account-001","name":"Test User"}`, string(plaintext))
+}
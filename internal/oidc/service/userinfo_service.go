@@ -2,13 +2,21 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 
 	accountDomain "github.com/rushairer/gosso/internal/account/domain"
 	accountRepo "github.com/rushairer/gosso/internal/account/repository"
 	accountService "github.com/rushairer/gosso/internal/account/service"
+	oauth2Domain "github.com/rushairer/gosso/internal/oauth2/domain"
+	oauth2Repo "github.com/rushairer/gosso/internal/oauth2/repository"
+	oauth2Service "github.com/rushairer/gosso/internal/oauth2/service"
+	tokenService "github.com/rushairer/gosso/internal/token/service"
 	"github.com/rushairer/gosso/internal/utility"
 )
 
@@ -16,25 +24,48 @@ import (
 type UserInfoService struct {
 	accountSvc     accountService.AccountService
 	credentialRepo accountRepo.CredentialRepository
+	clientRepo     oauth2Repo.OAuth2ClientRepository
+	subjects       *oauth2Service.SubjectIdentifierService
+	encrypter      *oauth2Service.ResponseEncrypter
+	tokenSvc       *tokenService.TokenService
+	issuer         string
 	logger         *zap.Logger
 }
 
-// NewUserInfoService creates a new instance of UserInfoService
+// NewUserInfoService creates a new instance of UserInfoService. subjects derives the sub
+// claim each client receives; when nil, every client receives the account ID. clientRepo
+// and encrypter encrypt the responses of clients that registered
+// userinfo_encrypted_response_alg, and tokenSvc signs those of clients that registered
+// userinfo_signed_response_alg with issuer as iss; when clientRepo is nil, every response
+// is plain JSON.
 func NewUserInfoService(
 	accountSvc accountService.AccountService,
 	credentialRepo accountRepo.CredentialRepository,
+	clientRepo oauth2Repo.OAuth2ClientRepository,
+	subjects *oauth2Service.SubjectIdentifierService,
+	encrypter *oauth2Service.ResponseEncrypter,
+	tokenSvc *tokenService.TokenService,
+	issuer string,
 	logger *zap.Logger,
 ) *UserInfoService {
 	logger = utility.EnsureLogger(logger)
 	return &UserInfoService{
 		accountSvc:     accountSvc,
 		credentialRepo: credentialRepo,
+		clientRepo:     clientRepo,
+		subjects:       subjects,
+		encrypter:      encrypter,
+		tokenSvc:       tokenSvc,
+		issuer:         issuer,
 		logger:         logger,
 	}
 }
 
-// GetUserInfo returns user information based on scope
-func (s *UserInfoService) GetUserInfo(ctx context.Context, accountID string, scopes []string) (map[string]any, error) {
+// GetUserInfo returns user information based on scope and on requestedClaims, the claims
+// the client requested for the UserInfo response with the claims parameter (OIDC Core §5.5).
+// clientID is the client the access token was issued to; it selects the subject identifier
+// and is empty for first-party tokens.
+func (s *UserInfoService) GetUserInfo(ctx context.Context, accountID, clientID string, scopes, requestedClaims []string) (map[string]any, error) {
 	account, err := s.accountSvc.FindAccountByID(ctx, accountID)
 	if err != nil {
 		return nil, fmt.Errorf("find account: %w", err)
@@ -44,21 +75,23 @@ func (s *UserInfoService) GetUserInfo(ctx context.Context, accountID string, sco
 		return nil, accountService.ErrAccountNotActive
 	}
 
+	subject := accountID
+	if clientID != "" {
+		if subject, err = s.subjects.SubjectForClientID(ctx, clientID, accountID); err != nil {
+			return nil, fmt.Errorf("subject identifier: %w", err)
+		}
+	}
+
 	info := map[string]any{
-		"sub": accountID,
+		"sub": subject,
 	}
 
+	released := releasedClaims(scopes, requestedClaims)
+
 	// Pre-fetch email and phone credentials in a single DB round-trip
-	// if both scopes are requested.
-	needEmail, needPhone := false, false
-	for _, scope := range scopes {
-		switch scope {
-		case "email":
-			needEmail = true
-		case "phone":
-			needPhone = true
-		}
-	}
+	// if both are requested.
+	needEmail := released["email"] || released["email_verified"]
+	needPhone := released["phone_number"] || released["phone_number_verified"]
 
 	var credsByType map[accountDomain.CredentialType][]*accountDomain.Credential
 	if needEmail || needPhone {
@@ -79,37 +112,94 @@ func (s *UserInfoService) GetUserInfo(ctx context.Context, accountID string, sco
 		}
 	}
 
-	for _, scope := range scopes {
-		switch scope {
-		case "profile":
-			info["name"] = account.DisplayName
-			if account.Username != nil {
-				info["preferred_username"] = *account.Username
-			}
-			if account.AvatarURL != nil && *account.AvatarURL != "" {
-				info["picture"] = *account.AvatarURL
-			}
-			info["locale"] = account.Locale
-		case "email":
-			s.addEmailInfo(credsByType[accountDomain.CredentialTypeEmail], info)
-		case "phone":
-			s.addPhoneInfo(credsByType[accountDomain.CredentialTypePhone], info)
-		}
+	if released["name"] {
+		info["name"] = account.DisplayName
+	}
+	if released["preferred_username"] && account.Username != nil {
+		info["preferred_username"] = *account.Username
+	}
+	if released["picture"] && account.AvatarURL != nil && *account.AvatarURL != "" {
+		info["picture"] = *account.AvatarURL
+	}
+	if released["locale"] {
+		info["locale"] = account.Locale
+	}
+	if needEmail {
+		addContactInfo(credsByType[accountDomain.CredentialTypeEmail], info, released, "email", "email_verified")
+	}
+	if needPhone {
+		addContactInfo(credsByType[accountDomain.CredentialTypePhone], info, released, "phone_number", "phone_number_verified")
 	}
 
 	return info, nil
 }
 
-func (s *UserInfoService) addEmailInfo(creds []*accountDomain.Credential, info map[string]any) {
-	if len(creds) > 0 && creds[0].Identifier != nil {
-		info["email"] = *creds[0].Identifier
-		info["email_verified"] = creds[0].IsVerified()
+// EncodeResponse returns info as a JWT for clients that registered
+// userinfo_signed_response_alg or userinfo_encrypted_response_alg, to be served as
+// application/jwt (OIDC Core §5.3.2). A response that is both signed and encrypted is a
+// nested JWT. ok is false when the response stays plain JSON, including for first-party
+// tokens.
+func (s *UserInfoService) EncodeResponse(ctx context.Context, clientID string, info map[string]any) (response string, ok bool, err error) {
+	if clientID == "" || s.clientRepo == nil {
+		return "", false, nil
+	}
+	client, err := s.clientRepo.FindByClientID(ctx, clientID)
+	if err != nil {
+		return "", false, fmt.Errorf("find client: %w", err)
+	}
+	if client.UserinfoSignedResponseAlg == "" && client.UserinfoEncryptedResponseAlg == "" {
+		return "", false, nil
 	}
+
+	var payload []byte
+	var cty string
+	if client.UserinfoSignedResponseAlg != "" {
+		signed, err := s.signResponse(client, info)
+		if err != nil {
+			return "", false, fmt.Errorf("sign userinfo: %w", err)
+		}
+		if client.UserinfoEncryptedResponseAlg == "" {
+			return signed, true, nil
+		}
+		payload, cty = []byte(signed), "JWT"
+	} else if payload, err = json.Marshal(info); err != nil {
+		return "", false, fmt.Errorf("marshal userinfo: %w", err)
+	}
+
+	if s.encrypter == nil {
+		return "", false, errors.New("encrypt userinfo: response encryption is not configured")
+	}
+	if response, err = s.encrypter.EncryptUserInfo(ctx, client, payload, cty); err != nil {
+		return "", false, fmt.Errorf("encrypt userinfo: %w", err)
+	}
+	return response, true, nil
 }
 
-func (s *UserInfoService) addPhoneInfo(creds []*accountDomain.Credential, info map[string]any) {
-	if len(creds) > 0 && creds[0].Identifier != nil {
-		info["phone_number"] = *creds[0].Identifier
-		info["phone_number_verified"] = creds[0].IsVerified()
+// signResponse signs info with the client's userinfo_signed_response_alg. The response
+// carries iss and aud so that relying parties can verify it offline (OIDC Core §5.3.2).
+func (s *UserInfoService) signResponse(client *oauth2Domain.OAuth2Client, info map[string]any) (string, error) {
+	if s.tokenSvc == nil {
+		return "", errors.New("response signing is not configured")
+	}
+	claims := make(jwt.MapClaims, len(info)+3)
+	for name, value := range info {
+		claims[name] = value
+	}
+	claims["iss"] = s.issuer
+	claims["aud"] = client.ClientID
+	claims["iat"] = time.Now().Unix()
+	return s.tokenSvc.KeyService().Sign(jwt.NewWithClaims(jwt.GetSigningMethod(client.UserinfoSignedResponseAlg), claims))
+}
+
+// addContactInfo adds the released value and verified claims of the first contact credential.
+func addContactInfo(creds []*accountDomain.Credential, info map[string]any, released map[string]bool, valueClaim, verifiedClaim string) {
+	if len(creds) == 0 || creds[0].Identifier == nil {
+		return
+	}
+	if released[valueClaim] {
+		info[valueClaim] = *creds[0].Identifier
+	}
+	if released[verifiedClaim] {
+		info[verifiedClaim] = creds[0].IsVerified()
 	}
 }
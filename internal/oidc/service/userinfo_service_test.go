@@ -63,12 +63,12 @@ func newTestUserInfoService(t *testing.T) *UserInfoService {
 		},
 	}
 
-	return NewUserInfoService(accountSvc, credRepo, zap.NewNop())
+	return NewUserInfoService(accountSvc, credRepo, nil, nil, nil, nil, "", zap.NewNop())
 }
 
 func TestUserInfo_GetUserInfo_SubOnly(t *testing.T) {
 	svc := newTestUserInfoService(t)
-	info, err := svc.GetUserInfo(context.Background(), "account-001", []string{"openid"})
+	info, err := svc.GetUserInfo(context.Background(), "account-001", "", []string{"openid"}, nil)
 	require.NoError(t, err)
 	assert.Equal(t, "account-001", info["sub"])
 	assert.Nil(t, info["name"])
@@ -77,7 +77,7 @@ func TestUserInfo_GetUserInfo_SubOnly(t *testing.T) {
 
 func TestUserInfo_GetUserInfo_ProfileScope(t *testing.T) {
 	svc := newTestUserInfoService(t)
-	info, err := svc.GetUserInfo(context.Background(), "account-001", []string{"openid", "profile"})
+	info, err := svc.GetUserInfo(context.Background(), "account-001", "", []string{"openid", "profile"}, nil)
 	require.NoError(t, err)
 
 	assert.Equal(t, "Test User", info["name"])
@@ -88,16 +88,28 @@ func TestUserInfo_GetUserInfo_ProfileScope(t *testing.T) {
 
 func TestUserInfo_GetUserInfo_EmailScope(t *testing.T) {
 	svc := newTestUserInfoService(t)
-	info, err := svc.GetUserInfo(context.Background(), "account-001", []string{"openid", "email"})
+	info, err := svc.GetUserInfo(context.Background(), "account-001", "", []string{"openid", "email"}, nil)
 	require.NoError(t, err)
 
 	assert.Equal(t, "test@example.com", info["email"])
 	assert.Equal(t, true, info["email_verified"])
 }
 
+func TestUserInfo_GetUserInfo_RequestedClaims(t *testing.T) {
+	svc := newTestUserInfoService(t)
+	info, err := svc.GetUserInfo(context.Background(), "account-001", "", []string{"openid"}, []string{"email_verified", "picture", "acr"})
+	require.NoError(t, err)
+
+	assert.Equal(t, true, info["email_verified"])
+	assert.Equal(t, "https://example.com/avatar.png", info["picture"])
+	assert.NotContains(t, info, "email")
+	assert.NotContains(t, info, "name")
+	assert.NotContains(t, info, "acr")
+}
+
 func TestUserInfo_GetUserInfo_PhoneScope(t *testing.T) {
 	svc := newTestUserInfoService(t)
-	info, err := svc.GetUserInfo(context.Background(), "account-001", []string{"openid", "phone"})
+	info, err := svc.GetUserInfo(context.Background(), "account-001", "", []string{"openid", "phone"}, nil)
 	require.NoError(t, err)
 
 	assert.Equal(t, "+8613800138000", info["phone_number"])
@@ -106,7 +118,7 @@ func TestUserInfo_GetUserInfo_PhoneScope(t *testing.T) {
 
 func TestUserInfo_GetUserInfo_AllScopes(t *testing.T) {
 	svc := newTestUserInfoService(t)
-	info, err := svc.GetUserInfo(context.Background(), "account-001", []string{"openid", "profile", "email", "phone"})
+	info, err := svc.GetUserInfo(context.Background(), "account-001", "", []string{"openid", "profile", "email", "phone"}, nil)
 	require.NoError(t, err)
 
 	assert.Equal(t, "account-001", info["sub"])
@@ -117,14 +129,14 @@ func TestUserInfo_GetUserInfo_AllScopes(t *testing.T) {
 
 func TestUserInfo_GetUserInfo_AccountNotFound(t *testing.T) {
 	svc := newTestUserInfoService(t)
-	_, err := svc.GetUserInfo(context.Background(), "nonexistent", []string{"openid"})
+	_, err := svc.GetUserInfo(context.Background(), "nonexistent", "", []string{"openid"}, nil)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "find account")
 }
 
 func TestUserInfo_GetUserInfo_AccountNotActive(t *testing.T) {
 	svc := newTestUserInfoService(t)
-	_, err := svc.GetUserInfo(context.Background(), "suspended-001", []string{"openid"})
+	_, err := svc.GetUserInfo(context.Background(), "suspended-001", "", []string{"openid"}, nil)
 	assert.ErrorIs(t, err, accountService.ErrAccountNotActive)
 }
 
@@ -142,9 +154,9 @@ func TestUserInfo_GetUserInfo_NoAvatar(t *testing.T) {
 		},
 	}
 	credRepo := &mockCredentialRepo{credentials: map[string][]*accountDomain.Credential{}}
-	svc := NewUserInfoService(accountSvc, credRepo, zap.NewNop())
+	svc := NewUserInfoService(accountSvc, credRepo, nil, nil, nil, nil, "", zap.NewNop())
 
-	info, err := svc.GetUserInfo(context.Background(), "account-no-avatar", []string{"openid", "profile"})
+	info, err := svc.GetUserInfo(context.Background(), "account-no-avatar", "", []string{"openid", "profile"}, nil)
 	require.NoError(t, err)
 	assert.Nil(t, info["picture"])
 }
@@ -162,9 +174,9 @@ func TestUserInfo_GetUserInfo_NoEmailCredential(t *testing.T) {
 		},
 	}
 	credRepo := &mockCredentialRepo{credentials: map[string][]*accountDomain.Credential{}}
-	svc := NewUserInfoService(accountSvc, credRepo, zap.NewNop())
+	svc := NewUserInfoService(accountSvc, credRepo, nil, nil, nil, nil, "", zap.NewNop())
 
-	info, err := svc.GetUserInfo(context.Background(), "account-no-email", []string{"openid", "email"})
+	info, err := svc.GetUserInfo(context.Background(), "account-no-email", "", []string{"openid", "email"}, nil)
 	require.NoError(t, err)
 	assert.Nil(t, info["email"])
 	assert.Nil(t, info["email_verified"])
@@ -173,9 +185,9 @@ func TestUserInfo_GetUserInfo_NoEmailCredential(t *testing.T) {
 func TestUserInfo_GetUserInfo_NilLogger(t *testing.T) {
 	accountSvc := &mockAccountService{accounts: map[string]*accountDomain.Account{}}
 	credRepo := &mockCredentialRepo{credentials: map[string][]*accountDomain.Credential{}}
-	svc := NewUserInfoService(accountSvc, credRepo, nil)
+	svc := NewUserInfoService(accountSvc, credRepo, nil, nil, nil, nil, "", nil)
 
-	_, err := svc.GetUserInfo(context.Background(), "any", []string{"openid"})
+	_, err := svc.GetUserInfo(context.Background(), "any", "", []string{"openid"}, nil)
 	assert.Error(t, err) // account not found, but no panic from nil logger
 }
 
@@ -197,9 +209,9 @@ func TestUserInfo_GetUserInfo_CredentialRepoError(t *testing.T) {
 		},
 		findByAccountAndTypeErr: fmt.Errorf("db error"),
 	}
-	svc := NewUserInfoService(accountSvc, credRepo, zap.NewNop())
+	svc := NewUserInfoService(accountSvc, credRepo, nil, nil, nil, nil, "", zap.NewNop())
 
-	info, err := svc.GetUserInfo(context.Background(), "account-001", []string{"openid", "email"})
+	info, err := svc.GetUserInfo(context.Background(), "account-001", "", []string{"openid", "email"}, nil)
 	assert.Error(t, err)
 	assert.Nil(t, info)
 	assert.Contains(t, err.Error(), "find credentials")
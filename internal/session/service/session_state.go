@@ -0,0 +1,64 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BrowserStateCookieName is the cookie holding the OP browser state of OpenID Connect
+// Session Management 1.0. Unlike the session cookies it is readable by script, because the
+// check_session_iframe compares it against the session_state of each relying party.
+const BrowserStateCookieName = "__Host-browser_state"
+
+// BrowserState returns the OP browser state of the session with sessionID. It is a hash of
+// the session ID, so the cookie reveals nothing that could be used to act on the session.
+// An empty sessionID has no browser state.
+func BrowserState(sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte("gosso browser state\x00" + sessionID))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SessionState returns the session_state of an authentication response to clientID at
+// redirectURI for the session with sessionID (OpenID Connect Session Management 1.0 §3):
+// hex(SHA-256(client_id " " origin " " browser_state " " salt)) "." salt, with a fresh salt
+// per response. The check_session_iframe computes the same value from the browser state
+// cookie to detect that the session changed.
+func SessionState(clientID, redirectURI, sessionID string) (string, error) {
+	origin, err := redirectOrigin(redirectURI)
+	if err != nil {
+		return "", err
+	}
+	saltBytes := make([]byte, 16)
+	if _, err := rand.Read(saltBytes); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	salt := hex.EncodeToString(saltBytes)
+	sum := sha256.Sum256([]byte(clientID + " " + origin + " " + BrowserState(sessionID) + " " + salt))
+	return hex.EncodeToString(sum[:]) + "." + salt, nil
+}
+
+// redirectOrigin returns the origin of redirectURI as a browser reports it in
+// MessageEvent.origin: scheme and host, without a default port.
+func redirectOrigin(redirectURI string) (string, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", errors.New("redirect_uri has no origin")
+	}
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); port != "" && !(scheme == "https" && port == "443") && !(scheme == "http" && port == "80") {
+		host += ":" + port
+	}
+	return scheme + "://" + host, nil
+}
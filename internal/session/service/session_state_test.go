@@ -0,0 +1,60 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserState(t *testing.T) {
+	state := BrowserState("session-001")
+	assert.NotEmpty(t, state)
+	assert.NotContains(t, state, "session-001")
+	assert.Equal(t, state, BrowserState("session-001"))
+	assert.NotEqual(t, state, BrowserState("session-002"))
+	assert.Empty(t, BrowserState(""))
+}
+
+func TestSessionState(t *testing.T) {
+	state, err := SessionState("client-001", "https://app.example.com:443/callback?x=1", "session-001")
+	require.NoError(t, err)
+
+	hash, salt, ok := strings.Cut(state, ".")
+	require.True(t, ok)
+	require.NotEmpty(t, salt)
+	sum := sha256.Sum256([]byte("client-001 https://app.example.com " + BrowserState("session-001") + " " + salt))
+	assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+
+	again, err := SessionState("client-001", "https://app.example.com/callback", "session-001")
+	require.NoError(t, err)
+	assert.NotEqual(t, state, again, "every response gets a fresh salt")
+}
+
+func TestSessionState_InvalidRedirectURI(t *testing.T) {
+	_, err := SessionState("client-001", "/callback", "session-001")
+	assert.Error(t, err)
+}
+
+func TestRedirectOrigin(t *testing.T) {
+	tests := map[string]string{
+		"https://App.Example.com/cb":      "https://app.example.com",
+		"https://app.example.com:8443/cb": "https://app.example.com:8443",
+		"http://localhost:80/cb":          "http://localhost",
+		"http://localhost:3000/cb":        "http://localhost:3000",
+		"http://[::1]:3000/cb":            "http://[::1]:3000",
+		"com.example.app:/oauth2redirect": "",
+	}
+	for uri, want := range tests {
+		origin, err := redirectOrigin(uri)
+		if want == "" {
+			assert.Error(t, err, uri)
+			continue
+		}
+		require.NoError(t, err, uri)
+		assert.Equal(t, want, origin, uri)
+	}
+}
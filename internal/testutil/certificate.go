@@ -0,0 +1,103 @@
+package testutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// SelfSignedCertificate creates a short-lived self-signed ECDSA P-256 client certificate
+// for mutual-TLS tests, with the given subject and DNS SANs, and returns it with its key.
+func SelfSignedCertificate(t *testing.T, subject pkix.Name, dnsNames ...string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key := generateCertificateKey(t)
+	tmpl := clientCertificateTemplate(t, subject, dnsNames)
+	return createCertificate(t, tmpl, tmpl, &key.PublicKey, key), key
+}
+
+// CertificateAuthority issues client certificates for PKI mutual-TLS tests.
+type CertificateAuthority struct {
+	Certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+}
+
+// NewCertificateAuthority creates a short-lived self-signed root CA.
+func NewCertificateAuthority(t *testing.T) *CertificateAuthority {
+	t.Helper()
+	key := generateCertificateKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          certificateSerial(t),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return &CertificateAuthority{Certificate: createCertificate(t, tmpl, tmpl, &key.PublicKey, key), key: key}
+}
+
+// Pool returns a certificate pool holding the CA as its only trust anchor.
+func (ca *CertificateAuthority) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Certificate)
+	return pool
+}
+
+// IssueCertificate creates a short-lived ECDSA P-256 client certificate signed by the CA,
+// with the given subject and DNS SANs, and returns it with its key.
+func (ca *CertificateAuthority) IssueCertificate(t *testing.T, subject pkix.Name, dnsNames ...string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key := generateCertificateKey(t)
+	tmpl := clientCertificateTemplate(t, subject, dnsNames)
+	return createCertificate(t, tmpl, ca.Certificate, &key.PublicKey, ca.key), key
+}
+
+func generateCertificateKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate certificate key: %v", err)
+	}
+	return key
+}
+
+func certificateSerial(t *testing.T) *big.Int {
+	t.Helper()
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("generate certificate serial: %v", err)
+	}
+	return serial
+}
+
+func clientCertificateTemplate(t *testing.T, subject pkix.Name, dnsNames []string) *x509.Certificate {
+	t.Helper()
+	return &x509.Certificate{
+		SerialNumber: certificateSerial(t),
+		Subject:      subject,
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+}
+
+func createCertificate(t *testing.T, tmpl, parent *x509.Certificate, pub *ecdsa.PublicKey, signer *ecdsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, signer)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return cert
+}
@@ -3,12 +3,34 @@ package domain
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Access token profiles select the format access tokens are issued in.
+const (
+	// AccessTokenProfileDefault is gosso's own format, with account_id, username, email
+	// and permissions claims.
+	AccessTokenProfileDefault = ""
+	// AccessTokenProfileRFC9068 is the JWT profile for OAuth 2.0 access tokens (RFC 9068):
+	// typ at+jwt, the subject identifier the client knows as sub, and permissions as
+	// entitlements.
+	AccessTokenProfileRFC9068 = "rfc9068"
+)
+
+// JWTAccessTokenType is the typ header of RFC 9068 access tokens (RFC 9068 §2.1).
+const JWTAccessTokenType = "at+jwt"
+
+// IsAccessTokenProfile reports whether profile is a supported access token profile.
+func IsAccessTokenProfile(profile string) bool {
+	return profile == AccessTokenProfileDefault || profile == AccessTokenProfileRFC9068
+}
+
 // AccessTokenClaims JWT access token claims
 type AccessTokenClaims struct {
 	jwt.RegisteredClaims
@@ -20,21 +42,175 @@ type AccessTokenClaims struct {
 	Scope       string   `json:"scope,omitempty"`
 	ClientID    string   `json:"client_id,omitempty"`
 	SessionID   string   `json:"sid,omitempty"`
+	// Act identifies the party acting on behalf of the subject when the token
+	// was obtained through token exchange (RFC 8693 §4.1).
+	Act *ActorClaim `json:"act,omitempty"`
+	// Cnf binds the token to the key of a DPoP proof (RFC 9449 §6.1) or to a TLS
+	// client certificate (RFC 8705 §3.1).
+	Cnf *ConfirmationClaim `json:"cnf,omitempty"`
+	// AuthorizationDetails are the authorization details (RFC 9396 §9.1) the token grants.
+	AuthorizationDetails json.RawMessage `json:"authorization_details,omitempty"`
+	// UserInfoClaims are the claims requested individually for the UserInfo response
+	// (OIDC Core §5.5) when the token was authorized.
+	UserInfoClaims []string `json:"userinfo_claims,omitempty"`
+	// AuthTime and AMR describe when and how the resource owner authenticated
+	// (RFC 9068 §2.2.1).
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	AMR      []string         `json:"amr,omitempty"`
+	// Entitlements carries Permissions in RFC 9068 access tokens (RFC 9068 §2.2.3.1).
+	// It is only set while parsing such a token.
+	Entitlements []string `json:"entitlements,omitempty"`
+	// Profile is the format the token is issued in, or was parsed from; it is not a claim.
+	// In the RFC 9068 profile a non-empty Subject is kept as the sub claim.
+	Profile string `json:"-"`
+}
+
+// JWTAccessTokenClaims are the claims of an access token in the JWT profile for OAuth 2.0
+// access tokens (RFC 9068 §2.2). Unlike AccessTokenClaims they never carry the account ID:
+// sub is the subject identifier the client knows, as in its ID tokens.
+type JWTAccessTokenClaims struct {
+	jwt.RegisteredClaims
+	ClientID             string             `json:"client_id"`
+	Scope                string             `json:"scope,omitempty"`
+	AuthTime             *jwt.NumericDate   `json:"auth_time,omitempty"`
+	AMR                  []string           `json:"amr,omitempty"`
+	SessionID            string             `json:"sid,omitempty"`
+	Roles                []string           `json:"roles,omitempty"`
+	Entitlements         []string           `json:"entitlements,omitempty"`
+	Act                  *ActorClaim        `json:"act,omitempty"`
+	Cnf                  *ConfirmationClaim `json:"cnf,omitempty"`
+	AuthorizationDetails json.RawMessage    `json:"authorization_details,omitempty"`
+	UserInfoClaims       []string           `json:"userinfo_claims,omitempty"`
+}
+
+// JWTProfile returns c in the RFC 9068 profile.
+func (c *AccessTokenClaims) JWTProfile() *JWTAccessTokenClaims {
+	return &JWTAccessTokenClaims{
+		RegisteredClaims:     c.RegisteredClaims,
+		ClientID:             c.ClientID,
+		Scope:                c.Scope,
+		AuthTime:             c.AuthTime,
+		AMR:                  c.AMR,
+		SessionID:            c.SessionID,
+		Roles:                c.Roles,
+		Entitlements:         c.Permissions,
+		Act:                  c.Act,
+		Cnf:                  c.Cnf,
+		AuthorizationDetails: c.AuthorizationDetails,
+		UserInfoClaims:       c.UserInfoClaims,
+	}
+}
+
+// ActorClaim is the value of the act (actor) claim. A nested Act records the
+// prior actors in a delegation chain, most recent first (RFC 8693 §4.1).
+type ActorClaim struct {
+	Subject  string      `json:"sub"`
+	ClientID string      `json:"client_id,omitempty"`
+	Act      *ActorClaim `json:"act,omitempty"`
+}
+
+// ConfirmationClaim is the value of the cnf (confirmation) claim (RFC 7800).
+type ConfirmationClaim struct {
+	// JKT is the JWK SHA-256 thumbprint of the DPoP proof key (RFC 9449 §6.1).
+	JKT string `json:"jkt,omitempty"`
+	// X5TS256 is the SHA-256 thumbprint of the TLS client certificate (RFC 8705 §3.1).
+	X5TS256 string `json:"x5t#S256,omitempty"`
+}
+
+// DPoPThumbprint returns the DPoP key thumbprint the token is bound to, or "" for a bearer token.
+func (c *AccessTokenClaims) DPoPThumbprint() string {
+	if c == nil || c.Cnf == nil {
+		return ""
+	}
+	return c.Cnf.JKT
+}
+
+// CertificateThumbprint returns the client certificate thumbprint the token is bound to,
+// or "" if it is not certificate-bound.
+func (c *AccessTokenClaims) CertificateThumbprint() string {
+	if c == nil || c.Cnf == nil {
+		return ""
+	}
+	return c.Cnf.X5TS256
 }
 
 // RefreshToken refresh token
 type RefreshToken struct {
-	Token     string    `json:"-"`
-	AccountID string    `json:"account_id"`
-	ClientID  string    `json:"client_id,omitempty"`
-	SessionID string    `json:"session_id,omitempty"`
-	Scope     string    `json:"scope,omitempty"`
-	IP        string    `json:"ip,omitempty"`
-	UserAgent string    `json:"user_agent,omitempty"`
+	Token     string `json:"-"`
+	AccountID string `json:"account_id"`
+	ClientID  string `json:"client_id,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	// Cnf binds the token to a DPoP key (RFC 9449 §5) or a TLS client certificate
+	// (RFC 8705 §4); nil for an unbound token.
+	Cnf *ConfirmationClaim `json:"cnf,omitempty"`
+	// AuthorizationDetails are the authorization details (RFC 9396) granted with the token.
+	AuthorizationDetails json.RawMessage `json:"authorization_details,omitempty"`
+	// Resources are the protected resources (RFC 8707) granted with the token; access
+	// tokens obtained with it are restricted to them.
+	Resources []string `json:"resources,omitempty"`
+	// UserInfoClaims are the claims requested individually for the UserInfo response
+	// (OIDC Core §5.5); access tokens obtained with it carry them.
+	UserInfoClaims []string `json:"userinfo_claims,omitempty"`
+	// Policy is the lifetime policy of the client the token was issued to; nil applies
+	// the server default. It is carried over on rotation.
+	Policy *RefreshTokenPolicy `json:"policy,omitempty"`
+	// GrantedAt is when the first token of the rotation chain was issued; the absolute
+	// lifetime of Policy runs from it.
+	GrantedAt time.Time `json:"granted_at,omitempty"`
 	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ScopeOfflineAccess requests a refresh token that outlives the browser session
+// (OIDC Core §11).
+const ScopeOfflineAccess = "offline_access"
+
+// HasOfflineAccess reports whether the space-delimited scope grants offline access.
+func HasOfflineAccess(scope string) bool {
+	return slices.Contains(strings.Fields(scope), ScopeOfflineAccess)
+}
+
+// IsOffline reports whether rt is an offline token: one granted offline_access, which is
+// not tied to a browser session and survives its logout.
+func (rt *RefreshToken) IsOffline() bool {
+	return HasOfflineAccess(rt.Scope)
+}
+
+// RefreshTokenPolicy is a per-client refresh token lifetime policy.
+type RefreshTokenPolicy struct {
+	// IdleLifetime is how long a token stays valid after it is issued. Zero applies the
+	// server default (auth.refresh_token_expiry).
+	IdleLifetime time.Duration `json:"idle_lifetime,omitempty"`
+	// AbsoluteLifetime, if positive, caps the whole rotation chain: no token outlives the
+	// grant by more than this.
+	AbsoluteLifetime time.Duration `json:"absolute_lifetime,omitempty"`
+	// Sliding starts a new idle window on each rotation. Otherwise rotated tokens keep
+	// the expiry of the first token of the grant.
+	Sliding bool `json:"sliding,omitempty"`
+}
+
+// ExpiresAt returns the expiry of a token issued at now for a grant made at grantedAt,
+// with defaultIdle standing in for an unset IdleLifetime.
+func (p RefreshTokenPolicy) ExpiresAt(grantedAt, now time.Time, defaultIdle time.Duration) time.Time {
+	idle := p.IdleLifetime
+	if idle <= 0 {
+		idle = defaultIdle
+	}
+	expiresAt := grantedAt.Add(idle)
+	if p.Sliding {
+		expiresAt = now.Add(idle)
+	}
+	if p.AbsoluteLifetime > 0 {
+		if limit := grantedAt.Add(p.AbsoluteLifetime); limit.Before(expiresAt) {
+			expiresAt = limit
+		}
+	}
+	return expiresAt
+}
+
 // Sentinel errors for RefreshToken.
 var (
 	ErrRefreshTokenRequired        = errors.New("refresh token: token is required")
@@ -50,3 +50,30 @@ func TestHashToken_Empty(t *testing.T) {
 	assert.NotEmpty(t, h)
 	assert.Len(t, h, 64)
 }
+
+func TestHasOfflineAccess(t *testing.T) {
+	assert.True(t, HasOfflineAccess("openid offline_access"))
+	assert.False(t, HasOfflineAccess("openid profile"))
+	assert.False(t, HasOfflineAccess(""))
+}
+
+func TestRefreshTokenPolicy_ExpiresAt(t *testing.T) {
+	granted := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := granted.Add(2 * time.Hour)
+
+	tests := map[string]struct {
+		policy RefreshTokenPolicy
+		want   time.Time
+	}{
+		"sliding default idle": {RefreshTokenPolicy{Sliding: true}, now.Add(24 * time.Hour)},
+		"sliding custom idle":  {RefreshTokenPolicy{IdleLifetime: time.Hour, Sliding: true}, now.Add(time.Hour)},
+		"fixed":                {RefreshTokenPolicy{IdleLifetime: 3 * time.Hour}, granted.Add(3 * time.Hour)},
+		"absolute cap":         {RefreshTokenPolicy{AbsoluteLifetime: 10 * time.Hour, Sliding: true}, granted.Add(10 * time.Hour)},
+		"absolute above idle":  {RefreshTokenPolicy{IdleLifetime: time.Hour, AbsoluteLifetime: 10 * time.Hour, Sliding: true}, now.Add(time.Hour)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.ExpiresAt(granted, now, 24*time.Hour))
+		})
+	}
+}
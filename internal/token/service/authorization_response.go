@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// authorizationResponseExpiry is the lifetime of a signed authorization response. JARM §2.1
+// recommends a short expiration since the response is consumed by the client right away.
+const authorizationResponseExpiry = 10 * time.Minute
+
+// AuthorizationResponseSigner signs authorization responses as JWTs for the JARM response
+// modes (jwt, query.jwt, form_post.jwt).
+type AuthorizationResponseSigner struct {
+	keySvc *KeyService
+	issuer string
+}
+
+// NewAuthorizationResponseSigner creates a signer that issues responses as issuer with the
+// server signing key.
+func NewAuthorizationResponseSigner(keySvc *KeyService, issuer string) *AuthorizationResponseSigner {
+	return &AuthorizationResponseSigner{keySvc: keySvc, issuer: issuer}
+}
+
+// SignAuthorizationResponse returns the authorization response params (code, state, error,
+// ...) as a JWT addressed to clientID (JARM §2.1). iss, aud and exp are set by the signer
+// and take precedence over params of the same name.
+func (s *AuthorizationResponseSigner) SignAuthorizationResponse(clientID string, params url.Values) (string, error) {
+	claims := jwt.MapClaims{}
+	for name := range params {
+		claims[name] = params.Get(name)
+	}
+	claims["iss"] = s.issuer
+	claims["aud"] = clientID
+	claims["exp"] = time.Now().Add(authorizationResponseExpiry).Unix()
+
+	signed, err := s.keySvc.Sign(jwt.NewWithClaims(jwt.SigningMethodRS256, claims))
+	if err != nil {
+		return "", fmt.Errorf("sign authorization response: %w", err)
+	}
+	return signed, nil
+}
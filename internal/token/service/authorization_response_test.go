@@ -0,0 +1,38 @@
+package service
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuthorizationResponseSigner(t *testing.T) {
+	keySvc, err := NewKeyService("", "", false, 0, zap.NewNop())
+	require.NoError(t, err)
+	signer := NewAuthorizationResponseSigner(keySvc, "https://sso.example.com")
+
+	response, err := signer.SignAuthorizationResponse("client-1", url.Values{
+		"code":  {"abc"},
+		"state": {"xyz"},
+		"iss":   {"https://attacker.example.com"},
+	})
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(response, claims, func(*jwt.Token) (any, error) {
+		return keySvc.PublicKey(), nil
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer("https://sso.example.com"), jwt.WithAudience("client-1"))
+	require.NoError(t, err)
+	assert.Equal(t, keySvc.KeyID(), token.Header["kid"])
+	assert.Equal(t, "abc", claims["code"])
+	assert.Equal(t, "xyz", claims["state"])
+
+	exp, err := claims.GetExpirationTime()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(authorizationResponseExpiry), exp.Time, 5*time.Second)
+}
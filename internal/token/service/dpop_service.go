@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/rushairer/gosso/internal/cache"
+	"github.com/rushairer/gosso/internal/jose"
+)
+
+const (
+	// dpopProofType is the required typ header of a DPoP proof (RFC 9449 §4.2).
+	dpopProofType = "dpop+jwt"
+	// dpopProofLifetime is how long after iat a proof is accepted; proofs are meant to be
+	// created just before the request they accompany.
+	dpopProofLifetime = time.Minute
+	// dpopProofLeeway tolerates clock skew between the client and this server.
+	dpopProofLeeway = 30 * time.Second
+
+	dpopJTIKeyPrefix     = "dpop_jti:"
+	dpopNonceKeyPrefix   = "dpop_nonce:"
+	dpopCurrentNonceKey  = "dpop_nonce_current"
+	dpopNonceRotation    = 5 * time.Minute
+	dpopNonceValidity    = 2 * dpopNonceRotation
+	dpopNonceRandomBytes = 16
+)
+
+// DPoPSigningAlgs lists the JWS algorithms accepted for DPoP proofs. Only asymmetric
+// algorithms are allowed, since the proof is verified with the public key it carries.
+var DPoPSigningAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// DPoP proof errors.
+var (
+	ErrInvalidDPoPProof  = errors.New("invalid DPoP proof")
+	ErrDPoPNonceRequired = errors.New("DPoP proof must contain a valid server nonce")
+	ErrDPoPProofReplayed = errors.New("DPoP proof has already been used")
+)
+
+// dpopProofClaims are the claims of a DPoP proof JWT (RFC 9449 §4.2).
+type dpopProofClaims struct {
+	jwt.RegisteredClaims
+	HTM   string `json:"htm"`
+	HTU   string `json:"htu"`
+	ATH   string `json:"ath,omitempty"`
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// DPoPVerifier validates DPoP proofs (RFC 9449) and issues server nonces.
+type DPoPVerifier struct {
+	redis         *cache.RedisClient
+	issuer        *url.URL
+	nonceRequired bool
+	parser        *jwt.Parser
+}
+
+// NewDPoPVerifier creates a DPoP proof verifier. The htu of each proof is compared with
+// issuer joined with the request path. When nonceRequired is set, every proof must carry
+// a nonce previously returned by Nonce (RFC 9449 §8).
+func NewDPoPVerifier(redis *cache.RedisClient, issuer string, nonceRequired bool) (*DPoPVerifier, error) {
+	if redis == nil {
+		return nil, errors.New("dpop verifier: redis client is required")
+	}
+	issuerURL, err := url.Parse(strings.TrimSuffix(issuer, "/"))
+	if err != nil || issuerURL.Host == "" {
+		return nil, fmt.Errorf("dpop verifier: issuer must be an absolute URL, got %q", issuer)
+	}
+	return &DPoPVerifier{
+		redis:         redis,
+		issuer:        issuerURL,
+		nonceRequired: nonceRequired,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(DPoPSigningAlgs),
+			jwt.WithIssuedAt(),
+			jwt.WithLeeway(dpopProofLeeway),
+		),
+	}, nil
+}
+
+// NonceRequired reports whether proofs must carry a server-provided nonce.
+func (v *DPoPVerifier) NonceRequired() bool {
+	return v.nonceRequired
+}
+
+// VerifyProof validates a DPoP proof presented with an HTTP request to path and returns
+// the JWK SHA-256 thumbprint of its key. When accessToken is non-empty the proof must
+// carry its hash in ath (RFC 9449 §4.3). Each proof is accepted only once.
+// Returns ErrInvalidDPoPProof, ErrDPoPNonceRequired or ErrDPoPProofReplayed on failure.
+func (v *DPoPVerifier) VerifyProof(ctx context.Context, proof, method, path, accessToken string) (string, error) {
+	var jkt string
+	claims := &dpopProofClaims{}
+	if _, err := v.parser.ParseWithClaims(proof, claims, func(token *jwt.Token) (any, error) {
+		if typ, _ := token.Header["typ"].(string); !strings.EqualFold(typ, dpopProofType) {
+			return nil, errors.New("typ must be dpop+jwt")
+		}
+		jwk, err := proofJWK(token.Header["jwk"])
+		if err != nil {
+			return nil, err
+		}
+		if jkt, err = jwk.Thumbprint(); err != nil {
+			return nil, err
+		}
+		return jwk.PublicKey()
+	}); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDPoPProof, err)
+	}
+
+	if claims.ID == "" || claims.IssuedAt == nil {
+		return "", fmt.Errorf("%w: jti and iat are required", ErrInvalidDPoPProof)
+	}
+	if time.Since(claims.IssuedAt.Time) > dpopProofLifetime+dpopProofLeeway {
+		return "", fmt.Errorf("%w: proof is too old", ErrInvalidDPoPProof)
+	}
+	if claims.HTM != method {
+		return "", fmt.Errorf("%w: htm mismatch", ErrInvalidDPoPProof)
+	}
+	if !v.matchesHTU(claims.HTU, path) {
+		return "", fmt.Errorf("%w: htu mismatch", ErrInvalidDPoPProof)
+	}
+	if accessToken != "" && claims.ATH != AccessTokenHash(accessToken) {
+		return "", fmt.Errorf("%w: ath mismatch", ErrInvalidDPoPProof)
+	}
+	if v.nonceRequired {
+		if claims.Nonce == "" {
+			return "", ErrDPoPNonceRequired
+		}
+		valid, err := v.redis.Exists(ctx, dpopNonceKeyPrefix+claims.Nonce)
+		if err != nil {
+			return "", fmt.Errorf("%w: nonce check failed: %v", ErrInvalidDPoPProof, err)
+		}
+		if !valid {
+			return "", ErrDPoPNonceRequired
+		}
+	}
+
+	// Hash the jti so attacker-controlled values never become raw Redis keys.
+	sum := sha256.Sum256([]byte(jkt + "\x00" + claims.ID))
+	ttl := time.Until(claims.IssuedAt.Time) + dpopProofLifetime + dpopProofLeeway
+	fresh, err := v.redis.SetNX(ctx, dpopJTIKeyPrefix+hex.EncodeToString(sum[:]), "1", ttl)
+	if err != nil {
+		return "", fmt.Errorf("%w: replay check failed: %v", ErrInvalidDPoPProof, err)
+	}
+	if !fresh {
+		return "", ErrDPoPProofReplayed
+	}
+	return jkt, nil
+}
+
+// Nonce returns the current server nonce for the DPoP-Nonce header (RFC 9449 §8.2).
+// Nonces rotate every few minutes and remain valid for one rotation after that, so
+// a client that just received a nonce is not rejected at the rotation boundary.
+func (v *DPoPVerifier) Nonce(ctx context.Context) (string, error) {
+	nonce, err := v.redis.Get(ctx, dpopCurrentNonceKey)
+	if err == nil {
+		return nonce, nil
+	}
+	if !errors.Is(err, cache.ErrKeyNotFound) {
+		return "", fmt.Errorf("get dpop nonce: %w", err)
+	}
+
+	b := make([]byte, dpopNonceRandomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate dpop nonce: %w", err)
+	}
+	nonce = base64.RawURLEncoding.EncodeToString(b)
+	if err := v.redis.Set(ctx, dpopNonceKeyPrefix+nonce, "1", dpopNonceValidity); err != nil {
+		return "", fmt.Errorf("store dpop nonce: %w", err)
+	}
+	set, err := v.redis.SetNX(ctx, dpopCurrentNonceKey, nonce, dpopNonceRotation)
+	if err != nil {
+		return "", fmt.Errorf("store dpop nonce: %w", err)
+	}
+	if !set {
+		// Another instance rotated the nonce concurrently; use theirs.
+		return v.redis.Get(ctx, dpopCurrentNonceKey)
+	}
+	return nonce, nil
+}
+
+// matchesHTU compares the htu claim with the URI of the request, ignoring any query
+// and fragment (RFC 9449 §4.3).
+func (v *DPoPVerifier) matchesHTU(htu, path string) bool {
+	u, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, v.issuer.Scheme) &&
+		strings.EqualFold(u.Host, v.issuer.Host) &&
+		u.Path == v.issuer.Path+path
+}
+
+// proofJWK decodes the jwk header of a DPoP proof, which must be a public key.
+func proofJWK(header any) (*jose.JWK, error) {
+	data, err := json.Marshal(header)
+	if err != nil || header == nil {
+		return nil, errors.New("jwk header is required")
+	}
+	var jwk jose.JWK
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, fmt.Errorf("invalid jwk header: %w", err)
+	}
+	if jwk.IsPrivate() {
+		return nil, errors.New("jwk header must not contain a private key")
+	}
+	return &jwk, nil
+}
+
+// AccessTokenHash returns the ath value for accessToken: the base64url-encoded SHA-256
+// hash of its ASCII encoding (RFC 9449 §4.2).
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/jose"
+	"github.com/rushairer/gosso/internal/testutil"
+)
+
+const testDPoPIssuer = "https://sso.example.com"
+
+func setupDPoPVerifier(t *testing.T, nonceRequired bool) *DPoPVerifier {
+	t.Helper()
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	v, err := NewDPoPVerifier(redisClient, testDPoPIssuer, nonceRequired)
+	require.NoError(t, err)
+	return v
+}
+
+func ecJWK(key *ecdsa.PrivateKey, private bool) jose.JWK {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	jwk := jose.JWK{
+		Kty: "EC",
+		Crv: key.Curve.Params().Name,
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+	}
+	if private {
+		jwk.D = base64.RawURLEncoding.EncodeToString(key.D.FillBytes(make([]byte, size)))
+	}
+	return jwk
+}
+
+func dpopClaims(htm, htu string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"jti": uuid.NewString(),
+		"iat": time.Now().Unix(),
+		"htm": htm,
+		"htu": htu,
+	}
+}
+
+func signDPoPProof(t *testing.T, key *ecdsa.PrivateKey, claims jwt.MapClaims, mutateHeader func(map[string]any)) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = "dpop+jwt"
+	token.Header["jwk"] = ecJWK(key, false)
+	if mutateHeader != nil {
+		mutateHeader(token.Header)
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestNewDPoPVerifier_InvalidConfig(t *testing.T) {
+	_, err := NewDPoPVerifier(nil, testDPoPIssuer, false)
+	assert.Error(t, err)
+
+	redisClient, mr := testutil.SetupTestRedis(t)
+	t.Cleanup(mr.Close)
+	_, err = NewDPoPVerifier(redisClient, "/relative", false)
+	assert.Error(t, err)
+}
+
+func TestDPoPVerifier_VerifyProof(t *testing.T) {
+	v := setupDPoPVerifier(t, false)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwk := ecJWK(key, false)
+	wantJKT, err := jwk.Thumbprint()
+	require.NoError(t, err)
+
+	proof := signDPoPProof(t, key, dpopClaims("POST", testDPoPIssuer+"/oauth2/token?ignored=1"), nil)
+	jkt, err := v.VerifyProof(context.Background(), proof, "POST", "/oauth2/token", "")
+	require.NoError(t, err)
+	assert.Equal(t, wantJKT, jkt)
+
+	// RFC 9449 §11.1: a proof must not be accepted twice.
+	_, err = v.VerifyProof(context.Background(), proof, "POST", "/oauth2/token", "")
+	assert.ErrorIs(t, err, ErrDPoPProofReplayed)
+}
+
+func TestDPoPVerifier_AccessTokenHash(t *testing.T) {
+	v := setupDPoPVerifier(t, false)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	claims := dpopClaims("GET", testDPoPIssuer+"/oidc/userinfo")
+	claims["ath"] = AccessTokenHash("access-token")
+	_, err = v.VerifyProof(context.Background(), signDPoPProof(t, key, claims, nil), "GET", "/oidc/userinfo", "access-token")
+	require.NoError(t, err)
+
+	claims = dpopClaims("GET", testDPoPIssuer+"/oidc/userinfo")
+	claims["ath"] = AccessTokenHash("other-token")
+	_, err = v.VerifyProof(context.Background(), signDPoPProof(t, key, claims, nil), "GET", "/oidc/userinfo", "access-token")
+	assert.ErrorIs(t, err, ErrInvalidDPoPProof)
+}
+
+func TestDPoPVerifier_Invalid(t *testing.T) {
+	v := setupDPoPVerifier(t, false)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		mutateClaims func(jwt.MapClaims)
+		mutateHeader func(map[string]any)
+	}{
+		{"wrong htm", func(c jwt.MapClaims) { c["htm"] = "GET" }, nil},
+		{"wrong htu host", func(c jwt.MapClaims) { c["htu"] = "https://evil.example.com/oauth2/token" }, nil},
+		{"wrong htu path", func(c jwt.MapClaims) { c["htu"] = testDPoPIssuer + "/oauth2/revoke" }, nil},
+		{"missing jti", func(c jwt.MapClaims) { delete(c, "jti") }, nil},
+		{"missing iat", func(c jwt.MapClaims) { delete(c, "iat") }, nil},
+		{"too old", func(c jwt.MapClaims) { c["iat"] = time.Now().Add(-5 * time.Minute).Unix() }, nil},
+		{"issued in the future", func(c jwt.MapClaims) { c["iat"] = time.Now().Add(5 * time.Minute).Unix() }, nil},
+		{"wrong typ", nil, func(h map[string]any) { h["typ"] = "JWT" }},
+		{"missing jwk", nil, func(h map[string]any) { delete(h, "jwk") }},
+		{"private jwk", nil, func(h map[string]any) { h["jwk"] = ecJWK(key, true) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := dpopClaims("POST", testDPoPIssuer+"/oauth2/token")
+			if tt.mutateClaims != nil {
+				tt.mutateClaims(claims)
+			}
+			_, err := v.VerifyProof(context.Background(), signDPoPProof(t, key, claims, tt.mutateHeader), "POST", "/oauth2/token", "")
+			assert.ErrorIs(t, err, ErrInvalidDPoPProof)
+		})
+	}
+}
+
+func TestDPoPVerifier_RejectsHMAC(t *testing.T) {
+	v := setupDPoPVerifier(t, false)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, dpopClaims("POST", testDPoPIssuer+"/oauth2/token"))
+	token.Header["typ"] = "dpop+jwt"
+	token.Header["jwk"] = ecJWK(key, false)
+	proof, err := token.SignedString([]byte("shared-secret"))
+	require.NoError(t, err)
+
+	_, err = v.VerifyProof(context.Background(), proof, "POST", "/oauth2/token", "")
+	assert.ErrorIs(t, err, ErrInvalidDPoPProof)
+}
+
+func TestDPoPVerifier_Nonce(t *testing.T) {
+	v := setupDPoPVerifier(t, true)
+	assert.True(t, v.NonceRequired())
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = v.VerifyProof(context.Background(), signDPoPProof(t, key, dpopClaims("POST", testDPoPIssuer+"/oauth2/token"), nil), "POST", "/oauth2/token", "")
+	assert.ErrorIs(t, err, ErrDPoPNonceRequired)
+
+	claims := dpopClaims("POST", testDPoPIssuer+"/oauth2/token")
+	claims["nonce"] = "made-up"
+	_, err = v.VerifyProof(context.Background(), signDPoPProof(t, key, claims, nil), "POST", "/oauth2/token", "")
+	assert.ErrorIs(t, err, ErrDPoPNonceRequired)
+
+	nonce, err := v.Nonce(context.Background())
+	require.NoError(t, err)
+	again, err := v.Nonce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, nonce, again, "nonce should be stable until it rotates")
+
+	claims = dpopClaims("POST", testDPoPIssuer+"/oauth2/token")
+	claims["nonce"] = nonce
+	_, err = v.VerifyProof(context.Background(), signDPoPProof(t, key, claims, nil), "POST", "/oauth2/token", "")
+	assert.NoError(t, err)
+}
@@ -7,4 +7,16 @@ var (
 	ErrInvalidToken         = errors.New("invalid token claims")
 	ErrBlacklistUnavailable = errors.New("token blacklist unavailable")
 	ErrRefreshTokenExpired  = errors.New("refresh token expired")
+
+	// ErrTokenStoreUnavailable is returned when a refresh or reference token cannot be read
+	// from Redis, as opposed to not being found there.
+	ErrTokenStoreUnavailable = errors.New("token store unavailable")
+
+	// ErrCertificateBindingMismatch is returned when a certificate-bound access token is
+	// presented without the client certificate it is bound to (RFC 8705 §3).
+	ErrCertificateBindingMismatch = errors.New("access token is bound to a different client certificate")
+
+	// ErrAudienceMismatch is returned when an access token restricted to other protected
+	// resources (RFC 8707) is presented to this server's own APIs.
+	ErrAudienceMismatch = errors.New("access token is issued for a different resource")
 )
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IntrospectionResponseSigner signs token introspection responses as JWTs (RFC 9701).
+type IntrospectionResponseSigner struct {
+	keySvc *KeyService
+	issuer string
+}
+
+// NewIntrospectionResponseSigner creates a signer that issues responses as issuer with the
+// server signing key.
+func NewIntrospectionResponseSigner(keySvc *KeyService, issuer string) *IntrospectionResponseSigner {
+	return &IntrospectionResponseSigner{keySvc: keySvc, issuer: issuer}
+}
+
+// SignIntrospectionResponse returns the introspection result as a JWT addressed to
+// clientID, the resource server that requested it. The result is nested in the
+// token_introspection claim so that its sub, exp, ... are not mistaken for those of the
+// response itself (RFC 9701 §5).
+func (s *IntrospectionResponseSigner) SignIntrospectionResponse(clientID string, result map[string]any) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                 s.issuer,
+		"aud":                 clientID,
+		"iat":                 time.Now().Unix(),
+		"token_introspection": result,
+	})
+	token.Header["typ"] = "token-introspection+jwt"
+
+	signed, err := s.keySvc.Sign(token)
+	if err != nil {
+		return "", fmt.Errorf("sign introspection response: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestIntrospectionResponseSigner(t *testing.T) {
+	keySvc, err := NewKeyService("", "", false, 0, zap.NewNop())
+	require.NoError(t, err)
+	signer := NewIntrospectionResponseSigner(keySvc, "https://sso.example.com")
+
+	response, err := signer.SignIntrospectionResponse("rs-1", map[string]any{
+		"active": true,
+		"sub":    "account-1",
+		"exp":    int64(1700000000),
+	})
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(response, claims, func(*jwt.Token) (any, error) {
+		return keySvc.PublicKey(), nil
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer("https://sso.example.com"), jwt.WithAudience("rs-1"), jwt.WithIssuedAt())
+	require.NoError(t, err)
+	assert.Equal(t, "token-introspection+jwt", token.Header["typ"])
+	assert.Equal(t, keySvc.KeyID(), token.Header["kid"])
+	assert.NotContains(t, claims, "sub")
+	assert.NotContains(t, claims, "exp")
+
+	introspection, ok := claims["token_introspection"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, true, introspection["active"])
+	assert.Equal(t, "account-1", introspection["sub"])
+}
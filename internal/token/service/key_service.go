@@ -1,6 +1,10 @@
 package service
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -10,19 +14,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 
+	"github.com/rushairer/gosso/internal/token/domain"
 	"github.com/rushairer/gosso/internal/utility"
 )
 
 const defaultRSAKeyBits = 3072
 
-// KeyService manages RSA key pairs for RS256 JWT signing.
+// KeyService manages the keys that sign JWTs. It either holds a single static RS256 key
+// or, when created with NewKeyringKeyService, the keys of a signing keyring, which has an
+// active key for each of its signing algorithms.
 type KeyService struct {
-	privateKey *rsa.PrivateKey
-	keyID      string
-	logger     *zap.Logger
+	mu     sync.RWMutex
+	keys   []*keyringKey
+	active map[string]*keyringKey // active key of each signing algorithm
+	logger *zap.Logger
+
+	// Keyring state; keyring is nil for a single static key.
+	keyring  *KeyringOptions
+	manifest []byte // keyring manifest the installed keys were loaded from
+	version  uint64 // incremented whenever the published keys change
+	now      func() time.Time
 }
 
 // NewKeyService creates a KeyService. Loading/generation strategy:
@@ -93,23 +110,143 @@ func NewKeyService(privateKeyPath string, keyID string, isProduction bool, keyBi
 		kid = computedKid
 	}
 
+	key := &keyringKey{
+		SigningKey: domain.SigningKey{
+			KeyID:     kid,
+			Algorithm: domain.SigningAlgRS256,
+			State:     domain.SigningKeyActive,
+			PublicKey: &privateKey.PublicKey,
+		},
+		private: privateKey,
+	}
 	return &KeyService{
-		privateKey: privateKey,
-		keyID:      kid,
-		logger:     logger,
+		keys:   []*keyringKey{key},
+		active: map[string]*keyringKey{domain.SigningAlgRS256: key},
+		logger: logger,
+		now:    time.Now,
 	}, nil
 }
 
+// PrivateKey returns the RS256 key that signs new tokens.
 func (s *KeyService) PrivateKey() *rsa.PrivateKey {
-	return s.privateKey
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active[domain.SigningAlgRS256].private.(*rsa.PrivateKey)
 }
 
+// PublicKey returns the public half of the RS256 key that signs new tokens.
 func (s *KeyService) PublicKey() *rsa.PublicKey {
-	return &s.privateKey.PublicKey
+	return &s.PrivateKey().PublicKey
 }
 
+// KeyID returns the kid of the RS256 key that signs new tokens.
 func (s *KeyService) KeyID() string {
-	return s.keyID
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active[domain.SigningAlgRS256].KeyID
+}
+
+// SigningAlgs returns the algorithms the service signs tokens with, in the order of
+// domain.SigningAlgs. RS256 is always included.
+func (s *KeyService) SigningAlgs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var algs []string
+	for _, alg := range domain.SigningAlgs {
+		if s.active[alg] != nil {
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// Sign signs token with the active key of its signing method and sets its kid header.
+// It returns domain.ErrSigningAlgUnavailable when the service has no key for the method.
+func (s *KeyService) Sign(token *jwt.Token) (string, error) {
+	alg := token.Method.Alg()
+	s.mu.RLock()
+	key := s.active[alg]
+	s.mu.RUnlock()
+	if key == nil {
+		return "", fmt.Errorf("%w: %s", domain.ErrSigningAlgUnavailable, alg)
+	}
+	token.Header["kid"] = key.KeyID
+	return token.SignedString(key.private)
+}
+
+// VerificationKey returns the published key with kid.
+func (s *KeyService) VerificationKey(kid string) (domain.SigningKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, k := range s.keys {
+		if k.KeyID == kid && k.Published() {
+			return k.SigningKey, true
+		}
+	}
+	return domain.SigningKey{}, false
+}
+
+// VerificationKeys returns the public keys of every published key for alg, the signing
+// key first.
+func (s *KeyService) VerificationKeys(alg string) []crypto.PublicKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var keys []crypto.PublicKey
+	if active := s.active[alg]; active != nil {
+		keys = append(keys, active.PublicKey)
+	}
+	for _, k := range s.keys {
+		if k.Published() && k.Algorithm == alg && k != s.active[alg] {
+			keys = append(keys, k.PublicKey)
+		}
+	}
+	return keys
+}
+
+// Keyfunc is a jwt.Keyfunc resolving the published key named by the kid header of token,
+// or every published key of its algorithm when it has none. A key only verifies tokens
+// of its own algorithm, so an RS256 key is never used to check a PS256 signature.
+func (s *KeyService) Keyfunc(token *jwt.Token) (any, error) {
+	alg := token.Method.Alg()
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		set := jwt.VerificationKeySet{}
+		for _, key := range s.VerificationKeys(alg) {
+			set.Keys = append(set.Keys, key)
+		}
+		if len(set.Keys) == 0 {
+			return nil, fmt.Errorf("no signing key for %s", alg)
+		}
+		return set, nil
+	}
+	key, ok := s.VerificationKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if key.Algorithm != alg {
+		return nil, fmt.Errorf("signing key %q is not for %s", kid, alg)
+	}
+	return key.PublicKey, nil
+}
+
+// Keys returns the keys of the keyring, including revoked ones, in the order they were
+// created. A single static key is returned as the active RS256 key.
+func (s *KeyService) Keys() []domain.SigningKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]domain.SigningKey, len(s.keys))
+	for i, k := range s.keys {
+		keys[i] = k.SigningKey
+	}
+	return keys
+}
+
+// Version changes whenever the published keys change, so caches derived from them can
+// tell when to rebuild.
+func (s *KeyService) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
 }
 
 func generateKey(bits int) (*rsa.PrivateKey, error) {
@@ -134,7 +271,7 @@ func generateAndSaveKey(path string, bits int) (*rsa.PrivateKey, error) {
 	return key, nil
 }
 
-func savePrivateKeyToPEM(path string, key *rsa.PrivateKey) error {
+func savePrivateKeyToPEM(path string, key crypto.Signer) error {
 	der, err := x509.MarshalPKCS8PrivateKey(key)
 	if err != nil {
 		return fmt.Errorf("marshal PKCS8: %w", err)
@@ -175,6 +312,16 @@ func savePrivateKeyToPEM(path string, key *rsa.PrivateKey) error {
 }
 
 func loadPrivateKeyFromPEM(path string) (*rsa.PrivateKey, error) {
+	key, err := loadSigningKeyFromPEM(path, domain.SigningAlgRS256)
+	if err != nil {
+		return nil, err
+	}
+	return key.(*rsa.PrivateKey), nil
+}
+
+// loadSigningKeyFromPEM loads a PKCS#8 private key for alg: an RSA key of at least 2048
+// bits for RS256 and PS256, an EC P-256 key for ES256 or an Ed25519 key for EdDSA.
+func loadSigningKeyFromPEM(path, alg string) (crypto.Signer, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
@@ -190,19 +337,34 @@ func loadPrivateKeyFromPEM(path string) (*rsa.PrivateKey, error) {
 		return nil, fmt.Errorf("parse PKCS8: %w", err)
 	}
 
-	rsaKey, ok := key.(*rsa.PrivateKey)
-	if !ok {
-		return nil, fmt.Errorf("key is not RSA (got %T)", key)
-	}
-
-	if rsaKey.N.BitLen() < 2048 {
-		return nil, fmt.Errorf("RSA key too small: %d bits (minimum 2048)", rsaKey.N.BitLen())
+	switch alg {
+	case domain.SigningAlgRS256, domain.SigningAlgPS256:
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("key is not RSA (got %T)", key)
+		}
+		if rsaKey.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA key too small: %d bits (minimum 2048)", rsaKey.N.BitLen())
+		}
+		return rsaKey, nil
+	case domain.SigningAlgES256:
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("key is not EC P-256 (got %T)", key)
+		}
+		return ecKey, nil
+	case domain.SigningAlgEdDSA:
+		edKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("key is not Ed25519 (got %T)", key)
+		}
+		return edKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
 	}
-
-	return rsaKey, nil
 }
 
-func computeKeyID(pubKey *rsa.PublicKey) (string, error) {
+func computeKeyID(pubKey crypto.PublicKey) (string, error) {
 	DER, err := x509.MarshalPKIXPublicKey(pubKey)
 	if err != nil {
 		return "", fmt.Errorf("marshal public key: %w", err)
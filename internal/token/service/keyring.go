@@ -0,0 +1,552 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/token/domain"
+	"github.com/rushairer/gosso/internal/utility"
+)
+
+const (
+	keyringManifestFile = "keyring.json"
+	keyringLockFile     = ".keyring.lock"
+	// keyringLockTimeout bounds how long a keyring update waits for another instance.
+	keyringLockTimeout = 30 * time.Second
+	// keyringLockStale is the age after which a lock is assumed to be left by a crashed
+	// instance. Updates hold the lock for at most a few key generations.
+	keyringLockStale = 2 * time.Minute
+	// KeyringRefreshInterval is how often Run reloads the keyring, which bounds how long
+	// other instances take to pick up rotations and revocations.
+	KeyringRefreshInterval = time.Minute
+)
+
+// KeyringOptions configures a signing keyring stored in a directory.
+type KeyringOptions struct {
+	// Dir holds the keyring manifest and one PEM file per published key. It may be
+	// shared by several instances.
+	Dir string
+	// RotationPeriod is how long each key signs before the next one takes over.
+	// 0 disables automatic rotation.
+	RotationPeriod time.Duration
+	// Overlap is how long a new key is published before it starts signing, and a retired
+	// or revoked key is kept after it stops. Retired keys stay trusted for all of it, so it
+	// must outlive the tokens they signed.
+	Overlap time.Duration
+	// ImportKeyPath is the PEM file of a key that becomes the first active key of an
+	// empty keyring, with ImportKeyID as its kid (the RFC 7638 thumbprint when empty).
+	// A new key is generated when it is empty or the file does not exist.
+	ImportKeyPath string
+	ImportKeyID   string
+	// KeyBits is the size of generated RSA keys. 0 uses 3072.
+	KeyBits int
+	// Algorithms are the signing algorithms the keyring keeps an active key for, each
+	// rotated on its own schedule. Empty means RS256 only; RS256 is required. Keys of
+	// other algorithms, added by instances configured differently, are published but
+	// neither used to sign nor rotated.
+	Algorithms []string
+}
+
+// keyringManifest is the keyring.json document. Private keys are stored next to it.
+type keyringManifest struct {
+	Keys []domain.SigningKey `json:"keys"`
+}
+
+// keyringKey is a key of the keyring together with its private half, which is nil once
+// the key is no longer published.
+type keyringKey struct {
+	domain.SigningKey
+	private crypto.Signer
+	unsaved bool
+}
+
+// NewKeyringKeyService creates a KeyService whose keys are managed in the keyring at
+// opts.Dir. The keyring is brought up to date immediately: an empty keyring receives its
+// first active key, and due rotations are carried out. Call Run to keep it up to date.
+func NewKeyringKeyService(opts KeyringOptions, logger *zap.Logger) (*KeyService, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("keyring directory is empty")
+	}
+	if opts.KeyBits == 0 {
+		opts.KeyBits = defaultRSAKeyBits
+	}
+	if opts.KeyBits < 2048 {
+		return nil, fmt.Errorf("rsa_key_bits must be at least 2048 (got %d)", opts.KeyBits)
+	}
+	if opts.RotationPeriod < 0 || opts.Overlap < 0 {
+		return nil, errors.New("keyring rotation period and overlap must not be negative")
+	}
+	if opts.RotationPeriod > 0 && opts.RotationPeriod <= opts.Overlap {
+		return nil, fmt.Errorf("keyring rotation period (%s) must be longer than the overlap (%s)", opts.RotationPeriod, opts.Overlap)
+	}
+	if len(opts.Algorithms) == 0 {
+		opts.Algorithms = []string{domain.SigningAlgRS256}
+	}
+	for i, alg := range opts.Algorithms {
+		if !domain.IsSigningAlg(alg) {
+			return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+		}
+		if slices.Contains(opts.Algorithms[:i], alg) {
+			return nil, fmt.Errorf("duplicate signing algorithm %q", alg)
+		}
+	}
+	if !slices.Contains(opts.Algorithms, domain.SigningAlgRS256) {
+		return nil, errors.New("keyring signing algorithms must include RS256")
+	}
+	if err := os.MkdirAll(opts.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("create keyring directory: %w", err)
+	}
+	s := &KeyService{
+		keyring: &opts,
+		logger:  utility.EnsureLogger(logger),
+		now:     time.Now,
+	}
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run refreshes the keyring every KeyringRefreshInterval until ctx is done. It returns
+// immediately for a single static key.
+func (s *KeyService) Run(ctx context.Context) {
+	if s.keyring == nil {
+		return
+	}
+	ticker := time.NewTicker(KeyringRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Refresh(); err != nil {
+				s.logger.Error("signing keyring refresh failed", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Refresh reloads the keyring and carries out due transitions: pending keys whose time
+// has come activate, the key they replace starts retiring, expired keys are removed and,
+// with automatic rotation, the next key is published Overlap before it is due.
+func (s *KeyService) Refresh() error {
+	return s.updateKeyring(func([]*keyringKey, time.Time) ([]*keyringKey, error) { return nil, nil })
+}
+
+// Rotate makes a new key of alg sign immediately, or of every signing algorithm when alg
+// is empty: the pending key if there is one, otherwise a freshly generated key. It returns
+// the new active keys. Relying parties that cached the JWKS only learn the new keys when
+// they refetch it, so prefer scheduled rotation outside of emergencies.
+func (s *KeyService) Rotate(alg string) ([]domain.SigningKey, error) {
+	if s.keyring == nil {
+		return nil, domain.ErrKeyringDisabled
+	}
+	algs := s.keyring.Algorithms
+	if alg != "" {
+		if !slices.Contains(algs, alg) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrSigningAlgUnavailable, alg)
+		}
+		algs = []string{alg}
+	}
+	err := s.updateKeyring(func(keys []*keyringKey, now time.Time) ([]*keyringKey, error) {
+		var added []*keyringKey
+		for _, alg := range algs {
+			if pending := firstKeyInState(keys, alg, domain.SigningKeyPending); pending != nil {
+				pending.ActivatesAt = now
+				continue
+			}
+			key, err := s.newKeyringKey(now, alg, domain.SigningKeyPending)
+			if err != nil {
+				return nil, err
+			}
+			added = append(added, key)
+		}
+		return added, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rotated := make([]domain.SigningKey, 0, len(algs))
+	for _, alg := range algs {
+		rotated = append(rotated, s.active[alg].SigningKey)
+	}
+	return rotated, nil
+}
+
+// Revoke removes the key with kid from the JWKS and stops trusting it at once; tokens it
+// signed are rejected. When it is the active key, the pending key of its algorithm or a
+// freshly generated key takes over. Revoking a revoked key is a no-op.
+func (s *KeyService) Revoke(kid string) error {
+	if s.keyring == nil {
+		return domain.ErrKeyringDisabled
+	}
+	return s.updateKeyring(func(keys []*keyringKey, now time.Time) ([]*keyringKey, error) {
+		i := slices.IndexFunc(keys, func(k *keyringKey) bool { return k.KeyID == kid })
+		if i < 0 {
+			return nil, domain.ErrSigningKeyNotFound
+		}
+		key := keys[i]
+		if key.State == domain.SigningKeyRevoked {
+			return nil, nil
+		}
+		if key.State == domain.SigningKeyActive {
+			if pending := firstKeyInState(keys, key.Algorithm, domain.SigningKeyPending); pending != nil {
+				pending.ActivatesAt = now
+			}
+		}
+		key.State = domain.SigningKeyRevoked
+		key.RevokedAt = now
+		key.ExpiresAt = now.Add(s.keyring.Overlap)
+		key.private = nil
+		s.logger.Warn("signing key revoked", zap.String("kid", kid))
+		return nil, nil
+	})
+}
+
+// updateKeyring loads the keyring under the directory lock, applies change, advances the
+// schedule, saves the result when it differs and installs it. change may modify the keys
+// in place and returns keys to add.
+func (s *KeyService) updateKeyring(change func(keys []*keyringKey, now time.Time) ([]*keyringKey, error)) error {
+	unlock, err := lockKeyring(s.keyring.Dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	keys, manifest, err := loadKeyring(s.keyring.Dir)
+	if err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	added, err := change(keys, now)
+	if err != nil {
+		return err
+	}
+	keys = append(keys, added...)
+	if keys, err = s.advanceKeyring(keys, now); err != nil {
+		return err
+	}
+	updated, err := marshalKeyringManifest(keys)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(updated, manifest) {
+		if err := saveKeyring(s.keyring.Dir, keys, updated); err != nil {
+			return err
+		}
+	}
+	s.installKeyring(keys, updated)
+	return nil
+}
+
+// advanceKeyring carries out the transitions that are due at now and returns the keys
+// that remain in the keyring.
+func (s *KeyService) advanceKeyring(keys []*keyringKey, now time.Time) ([]*keyringKey, error) {
+	keys = slices.DeleteFunc(keys, func(k *keyringKey) bool {
+		return (k.State == domain.SigningKeyRetiring || k.State == domain.SigningKeyRevoked) && !now.Before(k.ExpiresAt)
+	})
+	for _, alg := range s.keyring.Algorithms {
+		var err error
+		if keys, err = s.advanceAlgorithm(keys, alg, now); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// advanceAlgorithm carries out the transitions of the keys of alg that are due at now.
+func (s *KeyService) advanceAlgorithm(keys []*keyringKey, alg string, now time.Time) ([]*keyringKey, error) {
+	active := firstKeyInState(keys, alg, domain.SigningKeyActive)
+	pending := firstKeyInState(keys, alg, domain.SigningKeyPending)
+	switch {
+	case active == nil && pending != nil:
+		s.activate(pending, now)
+		active = pending
+	case active == nil:
+		empty := !slices.ContainsFunc(keys, func(k *keyringKey) bool { return k.Algorithm == alg })
+		key, err := s.initialKeyringKey(now, alg, empty)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		active = key
+	case pending != nil && !now.Before(pending.ActivatesAt):
+		active.State = domain.SigningKeyRetiring
+		active.RetiredAt = now
+		active.ExpiresAt = now.Add(s.keyring.Overlap)
+		s.activate(pending, now)
+		active = pending
+	}
+
+	// Publish the next key Overlap before the active one is due to be replaced.
+	if period := s.keyring.RotationPeriod; period > 0 && firstKeyInState(keys, alg, domain.SigningKeyPending) == nil {
+		due := active.ActivatesAt.Add(period)
+		if !now.Before(due.Add(-s.keyring.Overlap)) {
+			key, err := s.newKeyringKey(now, alg, domain.SigningKeyPending)
+			if err != nil {
+				return nil, err
+			}
+			key.ActivatesAt = due
+			if earliest := now.Add(s.keyring.Overlap); due.Before(earliest) {
+				key.ActivatesAt = earliest
+			}
+			keys = append(keys, key)
+			s.logger.Info("signing key published", zap.String("kid", key.KeyID), zap.String("alg", alg), zap.Time("activates_at", key.ActivatesAt))
+		}
+	}
+	return keys, nil
+}
+
+func (s *KeyService) activate(key *keyringKey, now time.Time) {
+	key.State = domain.SigningKeyActive
+	key.ActivatesAt = now
+	s.logger.Info("signing key activated", zap.String("kid", key.KeyID), zap.String("alg", key.Algorithm))
+}
+
+// initialKeyringKey returns a new active key of alg for a keyring without one: the
+// imported key for RS256 when the keyring has no RS256 keys and one is configured,
+// otherwise a new one. A keyring whose active key was revoked never re-imports it.
+func (s *KeyService) initialKeyringKey(now time.Time, alg string, empty bool) (*keyringKey, error) {
+	path := s.keyring.ImportKeyPath
+	if path == "" || !empty || alg != domain.SigningAlgRS256 {
+		return s.newKeyringKey(now, alg, domain.SigningKeyActive)
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		s.logger.Warn("signing key to import not found, generating a new one", zap.String("path", path))
+		return s.newKeyringKey(now, alg, domain.SigningKeyActive)
+	}
+	private, err := loadPrivateKeyFromPEM(path)
+	if err != nil {
+		return nil, fmt.Errorf("import signing key: %w", err)
+	}
+	kid := s.keyring.ImportKeyID
+	if kid == "" {
+		if kid, err = computeKeyID(&private.PublicKey); err != nil {
+			return nil, fmt.Errorf("compute key ID: %w", err)
+		}
+	}
+	s.logger.Info("signing key imported into keyring", zap.String("path", path), zap.String("kid", kid))
+	return &keyringKey{
+		SigningKey: domain.SigningKey{
+			KeyID:       kid,
+			Algorithm:   domain.SigningAlgRS256,
+			State:       domain.SigningKeyActive,
+			CreatedAt:   now,
+			ActivatesAt: now,
+			PublicKey:   &private.PublicKey,
+		},
+		private: private,
+		unsaved: true,
+	}, nil
+}
+
+// newKeyringKey generates a key of alg in state, activating at now.
+func (s *KeyService) newKeyringKey(now time.Time, alg string, state domain.SigningKeyState) (*keyringKey, error) {
+	private, err := generateSigningKey(alg, s.keyring.KeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	kid, err := computeKeyID(private.Public())
+	if err != nil {
+		return nil, fmt.Errorf("compute key ID: %w", err)
+	}
+	return &keyringKey{
+		SigningKey: domain.SigningKey{
+			KeyID:       kid,
+			Algorithm:   alg,
+			State:       state,
+			CreatedAt:   now,
+			ActivatesAt: now,
+			PublicKey:   private.Public(),
+		},
+		private: private,
+		unsaved: true,
+	}, nil
+}
+
+// generateSigningKey generates a key for alg; RSA keys have rsaBits bits.
+func generateSigningKey(alg string, rsaBits int) (crypto.Signer, error) {
+	switch alg {
+	case domain.SigningAlgRS256, domain.SigningAlgPS256:
+		return generateKey(rsaBits)
+	case domain.SigningAlgES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case domain.SigningAlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+}
+
+// installKeyring makes keys the keys of the service.
+func (s *KeyService) installKeyring(keys []*keyringKey, manifest []byte) {
+	active := make(map[string]*keyringKey, len(s.keyring.Algorithms))
+	for _, alg := range s.keyring.Algorithms {
+		active[alg] = firstKeyInState(keys, alg, domain.SigningKeyActive)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !bytes.Equal(manifest, s.manifest) {
+		s.version++
+	}
+	s.keys = keys
+	s.manifest = manifest
+	s.active = active
+}
+
+func firstKeyInState(keys []*keyringKey, alg string, state domain.SigningKeyState) *keyringKey {
+	var first *keyringKey
+	for _, k := range keys {
+		if k.Algorithm == alg && k.State == state && (first == nil || k.ActivatesAt.Before(first.ActivatesAt)) {
+			first = k
+		}
+	}
+	return first
+}
+
+// keyringKeyFile returns the PEM file of the key with kid. The kid is encoded because a
+// configured key_id may contain characters that are unsafe in file names.
+func keyringKeyFile(dir, kid string) string {
+	return filepath.Join(dir, base64.RawURLEncoding.EncodeToString([]byte(kid))+".pem")
+}
+
+// loadKeyring reads the keyring in dir and the private keys of its published keys. It
+// also returns the manifest as read, which is nil for a new keyring.
+func loadKeyring(dir string) ([]*keyringKey, []byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, keyringManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("read keyring: %w", err)
+	}
+	var manifest keyringManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("parse keyring: %w", err)
+	}
+	keys := make([]*keyringKey, 0, len(manifest.Keys))
+	for _, meta := range manifest.Keys {
+		key := &keyringKey{SigningKey: meta}
+		if key.Algorithm == "" {
+			key.Algorithm = domain.SigningAlgRS256
+		}
+		if key.Published() {
+			if key.private, err = loadSigningKeyFromPEM(keyringKeyFile(dir, meta.KeyID), key.Algorithm); err != nil {
+				return nil, nil, fmt.Errorf("load signing key %q: %w", meta.KeyID, err)
+			}
+			key.PublicKey = key.private.Public()
+		}
+		keys = append(keys, key)
+	}
+	return keys, data, nil
+}
+
+func marshalKeyringManifest(keys []*keyringKey) ([]byte, error) {
+	manifest := keyringManifest{Keys: make([]domain.SigningKey, len(keys))}
+	for i, k := range keys {
+		manifest.Keys[i] = k.SigningKey
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal keyring: %w", err)
+	}
+	return data, nil
+}
+
+// saveKeyring writes the private keys of new keys, then the manifest, and finally removes
+// the private keys of keys that are no longer published. Every write is atomic, so a
+// crash leaves either the old or the new keyring.
+func saveKeyring(dir string, keys []*keyringKey, manifest []byte) error {
+	keep := map[string]bool{}
+	for _, k := range keys {
+		if !k.Published() {
+			continue
+		}
+		path := keyringKeyFile(dir, k.KeyID)
+		keep[filepath.Base(path)] = true
+		if k.unsaved {
+			if err := savePrivateKeyToPEM(path, k.private); err != nil {
+				return fmt.Errorf("save signing key %q: %w", k.KeyID, err)
+			}
+			k.unsaved = false
+		}
+	}
+	if err := writeFileAtomic(filepath.Join(dir, keyringManifestFile), manifest); err != nil {
+		return fmt.Errorf("save keyring: %w", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return fmt.Errorf("list keyring: %w", err)
+	}
+	for _, file := range files {
+		if !keep[filepath.Base(file)] {
+			if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("remove signing key: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		_ = os.Remove(tmpPath) // cleanup on failure
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// lockKeyring takes the lock file of the keyring in dir, which serialises updates across
+// instances sharing the directory, and returns the function releasing it.
+func lockKeyring(dir string) (func(), error) {
+	path := filepath.Join(dir, keyringLockFile)
+	deadline := time.Now().Add(keyringLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock keyring: %w", err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > keyringLockStale {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("lock keyring: %s is held by another instance", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
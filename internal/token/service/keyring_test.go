@@ -0,0 +1,218 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/token/domain"
+)
+
+// newTestKeyring creates a keyring in dir whose clock is controlled by the returned function.
+func newTestKeyring(t *testing.T, opts KeyringOptions) (*KeyService, func(time.Duration)) {
+	t.Helper()
+	opts.KeyBits = 2048
+	svc, err := NewKeyringKeyService(opts, zap.NewNop())
+	require.NoError(t, err)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	return svc, func(d time.Duration) {
+		now = now.Add(d)
+		require.NoError(t, svc.Refresh())
+	}
+}
+
+func keyStates(svc *KeyService) map[string]domain.SigningKeyState {
+	states := map[string]domain.SigningKeyState{}
+	for _, k := range svc.Keys() {
+		states[k.KeyID] = k.State
+	}
+	return states
+}
+
+func TestKeyring_Bootstrap(t *testing.T) {
+	dir := t.TempDir()
+	svc, _ := newTestKeyring(t, KeyringOptions{Dir: dir})
+
+	kid, key := svc.KeyID(), svc.PrivateKey()
+	require.NotNil(t, key)
+	assert.Equal(t, map[string]domain.SigningKeyState{kid: domain.SigningKeyActive}, keyStates(svc))
+
+	info, err := os.Stat(keyringKeyFile(dir, kid))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A second instance sharing the directory uses the same key.
+	other, _ := newTestKeyring(t, KeyringOptions{Dir: dir})
+	assert.Equal(t, kid, other.KeyID())
+	assert.True(t, key.Equal(other.PrivateKey()))
+}
+
+func TestKeyring_ImportsPrivateKeyPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "private.pem")
+	static, err := NewKeyService(path, "legacy-kid", false, 2048, zap.NewNop())
+	require.NoError(t, err)
+
+	svc, _ := newTestKeyring(t, KeyringOptions{Dir: filepath.Join(dir, "keyring"), ImportKeyPath: path, ImportKeyID: "legacy-kid"})
+	assert.Equal(t, "legacy-kid", svc.KeyID())
+	assert.True(t, static.PrivateKey().Equal(svc.PrivateKey()))
+}
+
+func TestKeyring_ScheduledRotation(t *testing.T) {
+	const day = 24 * time.Hour
+	svc, advance := newTestKeyring(t, KeyringOptions{Dir: t.TempDir(), RotationPeriod: 10 * day, Overlap: day})
+	first := svc.KeyID()
+
+	advance(8 * day)
+	assert.Len(t, svc.Keys(), 1, "the next key is not published before the overlap window")
+
+	advance(day)
+	require.Len(t, svc.Keys(), 2)
+	var next string
+	for kid, state := range keyStates(svc) {
+		if state == domain.SigningKeyPending {
+			next = kid
+		}
+	}
+	require.NotEmpty(t, next, "the next key is published ahead of its activation")
+	assert.Equal(t, first, svc.KeyID(), "a pending key does not sign")
+	_, ok := svc.VerificationKey(next)
+	assert.True(t, ok)
+
+	advance(day)
+	assert.Equal(t, next, svc.KeyID())
+	assert.Equal(t, map[string]domain.SigningKeyState{first: domain.SigningKeyRetiring, next: domain.SigningKeyActive}, keyStates(svc))
+	_, ok = svc.VerificationKey(first)
+	assert.True(t, ok, "the retiring key still verifies the tokens it signed")
+
+	advance(day)
+	assert.Equal(t, map[string]domain.SigningKeyState{next: domain.SigningKeyActive}, keyStates(svc))
+	_, ok = svc.VerificationKey(first)
+	assert.False(t, ok)
+	_, err := os.Stat(keyringKeyFile(svc.keyring.Dir, first))
+	assert.ErrorIs(t, err, os.ErrNotExist, "the private key of a removed key is deleted")
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	svc, _ := newTestKeyring(t, KeyringOptions{Dir: t.TempDir(), Overlap: time.Hour})
+	first := svc.KeyID()
+
+	rotated, err := svc.Rotate("")
+	require.NoError(t, err)
+	require.Len(t, rotated, 1)
+	assert.NotEqual(t, first, rotated[0].KeyID)
+	assert.Equal(t, rotated[0].KeyID, svc.KeyID())
+	assert.Equal(t, domain.SigningKeyRetiring, keyStates(svc)[first])
+}
+
+func TestKeyring_RevokeActive(t *testing.T) {
+	dir := t.TempDir()
+	svc, _ := newTestKeyring(t, KeyringOptions{Dir: dir, Overlap: time.Hour})
+	other, _ := newTestKeyring(t, KeyringOptions{Dir: dir, Overlap: time.Hour})
+	first := svc.KeyID()
+
+	signed := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "account-001"})
+	signed.Header["kid"] = first
+	tokenString, err := signed.SignedString(svc.PrivateKey())
+	require.NoError(t, err)
+	_, err = jwt.Parse(tokenString, other.Keyfunc)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Revoke(first))
+	assert.NotEqual(t, first, svc.KeyID(), "a new key takes over from a revoked active key")
+	assert.Equal(t, domain.SigningKeyRevoked, keyStates(svc)[first])
+	_, err = os.Stat(keyringKeyFile(dir, first))
+	assert.ErrorIs(t, err, os.ErrNotExist, "the private key of a revoked key is deleted")
+
+	// Other instances stop trusting the key on their next refresh.
+	require.NoError(t, other.Refresh())
+	assert.Equal(t, svc.KeyID(), other.KeyID())
+	_, err = jwt.Parse(tokenString, other.Keyfunc)
+	assert.Error(t, err)
+
+	require.NoError(t, svc.Revoke(first), "revoking twice is a no-op")
+	assert.ErrorIs(t, svc.Revoke("unknown"), domain.ErrSigningKeyNotFound)
+}
+
+func TestKeyring_RevokeActivatesPendingKey(t *testing.T) {
+	const day = 24 * time.Hour
+	svc, advance := newTestKeyring(t, KeyringOptions{Dir: t.TempDir(), RotationPeriod: 10 * day, Overlap: day})
+	first := svc.KeyID()
+	advance(9 * day)
+	require.Len(t, svc.Keys(), 2)
+
+	require.NoError(t, svc.Revoke(first))
+	assert.Len(t, svc.Keys(), 2, "the published pending key takes over instead of a new one")
+	assert.Equal(t, domain.SigningKeyActive, keyStates(svc)[svc.KeyID()])
+}
+
+func TestKeyring_Algorithms(t *testing.T) {
+	dir := t.TempDir()
+	svc, _ := newTestKeyring(t, KeyringOptions{Dir: dir, Overlap: time.Hour, Algorithms: []string{"RS256", "ES256", "EdDSA"}})
+	assert.Equal(t, []string{"RS256", "ES256", "EdDSA"}, svc.SigningAlgs())
+	require.Len(t, svc.Keys(), 3)
+
+	for _, alg := range []string{"RS256", "ES256", "EdDSA"} {
+		tokenString, err := svc.Sign(jwt.NewWithClaims(jwt.GetSigningMethod(alg), jwt.MapClaims{"sub": "account-001"}))
+		require.NoError(t, err, alg)
+		token, err := jwt.Parse(tokenString, svc.Keyfunc)
+		require.NoError(t, err, alg)
+		assert.Equal(t, alg, token.Method.Alg())
+	}
+	_, err := svc.Sign(jwt.NewWithClaims(jwt.SigningMethodPS256, jwt.MapClaims{}))
+	assert.ErrorIs(t, err, domain.ErrSigningAlgUnavailable)
+
+	// A kid only verifies tokens of its own algorithm.
+	es256, err := svc.Sign(jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{}))
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(es256, jwt.MapClaims{})
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{})
+	forged.Header["kid"] = parsed.Header["kid"]
+	forgedString, err := forged.SignedString(svc.PrivateKey())
+	require.NoError(t, err)
+	_, err = jwt.Parse(forgedString, svc.Keyfunc)
+	assert.Error(t, err)
+
+	// Rotating one algorithm leaves the others alone.
+	rsKID := svc.KeyID()
+	rotated, err := svc.Rotate("ES256")
+	require.NoError(t, err)
+	require.Len(t, rotated, 1)
+	assert.Equal(t, "ES256", rotated[0].Algorithm)
+	assert.Equal(t, rsKID, svc.KeyID())
+	_, err = svc.Rotate("PS256")
+	assert.ErrorIs(t, err, domain.ErrSigningAlgUnavailable)
+
+	// An instance configured for RS256 only keeps the other keys for verification.
+	other, _ := newTestKeyring(t, KeyringOptions{Dir: dir, Overlap: time.Hour})
+	assert.Equal(t, []string{"RS256"}, other.SigningAlgs())
+	_, err = jwt.Parse(es256, other.Keyfunc)
+	assert.NoError(t, err)
+}
+
+func TestKeyring_StaticKey(t *testing.T) {
+	svc, err := NewKeyService("", "static-kid", false, 2048, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = svc.Rotate("")
+	assert.ErrorIs(t, err, domain.ErrKeyringDisabled)
+	assert.ErrorIs(t, svc.Revoke("static-kid"), domain.ErrKeyringDisabled)
+	keys := svc.Keys()
+	require.Len(t, keys, 1)
+	assert.Equal(t, domain.SigningKeyActive, keys[0].State)
+}
+
+func TestNewKeyringKeyService_InvalidOptions(t *testing.T) {
+	_, err := NewKeyringKeyService(KeyringOptions{}, zap.NewNop())
+	assert.Error(t, err)
+	_, err = NewKeyringKeyService(KeyringOptions{Dir: t.TempDir(), RotationPeriod: time.Hour, Overlap: time.Hour}, zap.NewNop())
+	assert.ErrorContains(t, err, "must be longer than the overlap")
+}
@@ -56,6 +56,20 @@ type TokenService struct {
 	enforceIPBinding bool
 	logger           *zap.Logger
 	parser           *jwt.Parser
+	// accessTokenMethod signs access tokens; see SetAccessTokenSigningAlg.
+	accessTokenMethod jwt.SigningMethod
+	// resources holds the identifiers of the protected resources (RFC 8707) tokens can be
+	// issued for in place of the client audience.
+	resources map[string]bool
+	// subjects maps the sub claim of RFC 9068 access tokens back to account IDs.
+	subjects SubjectResolver
+}
+
+// SubjectResolver maps the subject identifier in an RFC 9068 access token issued to the
+// client with clientID back to the account ID. A subject equal to clientID marks a token
+// without a resource owner, such as one from the client credentials grant.
+type SubjectResolver interface {
+	ResolveAccessTokenSubject(ctx context.Context, clientID, subject string) (string, error)
 }
 
 // NewTokenService creates a new token service instance.
@@ -89,44 +103,106 @@ func NewTokenService(
 		return nil, errors.New("token service: refreshExpiry must be positive")
 	}
 	return &TokenService{
-		keySvc:           keySvc,
-		issuer:           issuer,
-		accessExpiry:     accessExpiry,
-		refreshExpiry:    refreshExpiry,
-		redis:            redis,
-		blacklist:        blacklist,
-		auditor:          auditor,
-		enforceIPBinding: enforceIPBinding,
-		logger:           logger,
-		parser:           jwt.NewParser(jwt.WithIssuer(issuer), jwt.WithLeeway(accessTokenClockSkew)),
+		keySvc:            keySvc,
+		issuer:            issuer,
+		accessExpiry:      accessExpiry,
+		refreshExpiry:     refreshExpiry,
+		redis:             redis,
+		blacklist:         blacklist,
+		auditor:           auditor,
+		enforceIPBinding:  enforceIPBinding,
+		logger:            logger,
+		parser:            jwt.NewParser(jwt.WithIssuer(issuer), jwt.WithLeeway(accessTokenClockSkew), jwt.WithValidMethods(domain.SigningAlgs)),
+		accessTokenMethod: jwt.SigningMethodRS256,
 	}, nil
 }
 
-// RefreshExpiry returns the configured lifetime for refresh tokens.
+// SetAccessTokenSigningAlg selects the algorithm access tokens are signed with
+// (auth.access_token_signing_alg); the default is RS256. The key service must sign with
+// it. It must be called before the service is used.
+func (s *TokenService) SetAccessTokenSigningAlg(alg string) error {
+	if !slices.Contains(s.keySvc.SigningAlgs(), alg) {
+		return fmt.Errorf("%w: %s", domain.ErrSigningAlgUnavailable, alg)
+	}
+	s.accessTokenMethod = jwt.GetSigningMethod(alg)
+	return nil
+}
+
+// SetProtectedResources registers the protected resource identifiers (RFC 8707) that access
+// tokens may carry as their audience instead of their client_id. It must be called before
+// the service is used.
+func (s *TokenService) SetProtectedResources(identifiers []string) {
+	s.resources = make(map[string]bool, len(identifiers))
+	for _, id := range identifiers {
+		s.resources[id] = true
+	}
+}
+
+// SetSubjectResolver sets the resolver for the sub claim of RFC 9068 access tokens. Without
+// one, sub is taken as the account ID. It must be called before the service is used.
+func (s *TokenService) SetSubjectResolver(subjects SubjectResolver) {
+	s.subjects = subjects
+}
+
+// RefreshExpiry returns the configured lifetime for refresh tokens; clients may override
+// it with a RefreshTokenPolicy.
 func (s *TokenService) RefreshExpiry() time.Duration {
 	return s.refreshExpiry
 }
 
-// GenerateAccessToken generates a JWT access token (RS256).
+// GenerateAccessToken generates a JWT access token.
 // Note: this always overrides ExpiresAt with the configured accessExpiry,
 // regardless of any value the caller may have set on claims.
 // Use GenerateShortLivedToken if a custom expiry is needed.
 func (s *TokenService) GenerateAccessToken(claims *domain.AccessTokenClaims) (string, error) {
+	return s.GenerateAccessTokenWithLifetime(claims, 0)
+}
+
+// GenerateAccessTokenWithLifetime generates a JWT access token like GenerateAccessToken.
+// A positive lifetime, e.g. one registered by the client, replaces the configured accessExpiry.
+func (s *TokenService) GenerateAccessTokenWithLifetime(claims *domain.AccessTokenClaims, lifetime time.Duration) (string, error) {
+	if lifetime <= 0 {
+		lifetime = s.accessExpiry
+	}
 	now := time.Now()
 	clonedClaims := *claims
 	if clonedClaims.ID == "" {
 		clonedClaims.ID = uuid.New().String()
 	}
 	clonedClaims.Issuer = s.issuer
-	clonedClaims.Subject = clonedClaims.AccountID
+	clonedClaims.Subject = accessTokenSubject(&clonedClaims)
 	clonedClaims.IssuedAt = jwt.NewNumericDate(now)
-	clonedClaims.ExpiresAt = jwt.NewNumericDate(now.Add(s.accessExpiry))
+	clonedClaims.ExpiresAt = jwt.NewNumericDate(now.Add(lifetime))
 	ensureClientAudience(&clonedClaims)
 
 	return s.signToken(&clonedClaims, "access token")
 }
 
-// GenerateShortLivedToken generates a JWT access token (RS256) that respects
+// GenerateResourceAccessToken generates a JWT access token for the protected
+// resources in claims.Audience (RFC 8707 §2). Unlike GenerateAccessToken, client_id is not
+// added to aud, so the token is only good at those resources. A positive lifetime replaces
+// the configured accessExpiry.
+func (s *TokenService) GenerateResourceAccessToken(claims *domain.AccessTokenClaims, lifetime time.Duration) (string, error) {
+	if len(claims.Audience) == 0 {
+		return "", errors.New("generate resource access token: audience is required")
+	}
+	if lifetime <= 0 {
+		lifetime = s.accessExpiry
+	}
+	now := time.Now()
+	clonedClaims := *claims
+	if clonedClaims.ID == "" {
+		clonedClaims.ID = uuid.New().String()
+	}
+	clonedClaims.Issuer = s.issuer
+	clonedClaims.Subject = accessTokenSubject(&clonedClaims)
+	clonedClaims.IssuedAt = jwt.NewNumericDate(now)
+	clonedClaims.ExpiresAt = jwt.NewNumericDate(now.Add(lifetime))
+
+	return s.signToken(&clonedClaims, "resource access token")
+}
+
+// GenerateShortLivedToken generates a JWT access token that respects
 // the caller-provided ExpiresAt. Unlike GenerateAccessToken which always uses
 // the configured accessExpiry, this method preserves short TTLs for special
 // purposes like MFA verification tokens.
@@ -137,7 +213,7 @@ func (s *TokenService) GenerateShortLivedToken(claims *domain.AccessTokenClaims)
 		clonedClaims.ID = uuid.New().String()
 	}
 	clonedClaims.Issuer = s.issuer
-	clonedClaims.Subject = clonedClaims.AccountID
+	clonedClaims.Subject = accessTokenSubject(&clonedClaims)
 	clonedClaims.IssuedAt = jwt.NewNumericDate(now)
 	if clonedClaims.ExpiresAt == nil || clonedClaims.ExpiresAt.IsZero() {
 		clonedClaims.ExpiresAt = jwt.NewNumericDate(now.Add(s.accessExpiry))
@@ -167,11 +243,24 @@ func ensureClientAudience(claims *domain.AccessTokenClaims) {
 	claims.Audience = append(claims.Audience, claims.ClientID)
 }
 
-// signToken creates and signs a JWT with RS256, setting the kid header.
+// accessTokenSubject returns the sub claim for claims: the account ID, or in the RFC 9068
+// profile the subject identifier set by the caller.
+func accessTokenSubject(claims *domain.AccessTokenClaims) string {
+	if claims.Profile == domain.AccessTokenProfileRFC9068 && claims.Subject != "" {
+		return claims.Subject
+	}
+	return claims.AccountID
+}
+
+// signToken creates and signs a JWT with the access token signing algorithm, setting the
+// kid header. Tokens in the RFC 9068 profile carry its claims and typ header.
 func (s *TokenService) signToken(claims *domain.AccessTokenClaims, label string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header["kid"] = s.keySvc.KeyID()
-	tokenString, err := token.SignedString(s.keySvc.PrivateKey())
+	token := jwt.NewWithClaims(s.accessTokenMethod, claims)
+	if claims.Profile == domain.AccessTokenProfileRFC9068 {
+		token = jwt.NewWithClaims(s.accessTokenMethod, claims.JWTProfile())
+		token.Header["typ"] = domain.JWTAccessTokenType
+	}
+	tokenString, err := s.keySvc.Sign(token)
 	if err != nil {
 		s.logger.Error("Failed to sign "+label, zap.Error(err))
 		return "", fmt.Errorf("sign %s: %w", label, err)
@@ -184,8 +273,14 @@ func (s *TokenService) AccessExpiry() time.Duration {
 	return s.accessExpiry
 }
 
-// GenerateRefreshToken generates a random refresh token and stores it in Redis
-func (s *TokenService) GenerateRefreshToken(ctx context.Context, accountID, clientID, sessionID, scope string) (*domain.RefreshToken, error) {
+// GenerateRefreshToken generates a random refresh token and stores it in Redis.
+// authorizationDetails (RFC 9396), resources (RFC 8707), userInfoClaims (OIDC Core §5.5),
+// a non-nil cnf, which binds the token to a DPoP key or client certificate, and policy are
+// carried over on rotation. A nil policy applies the server default: a sliding
+// refreshExpiry window with no absolute limit.
+// A token whose scope grants offline_access (OIDC Core §11) is not tied to sessionID, so
+// RevokeAllForSession leaves it alone.
+func (s *TokenService) GenerateRefreshToken(ctx context.Context, accountID, clientID, sessionID, scope string, authorizationDetails json.RawMessage, resources, userInfoClaims []string, cnf *domain.ConfirmationClaim, policy *domain.RefreshTokenPolicy) (*domain.RefreshToken, error) {
 	randomBytes := make([]byte, refreshTokenLength)
 	if _, err := rand.Read(randomBytes); err != nil {
 		s.logger.Error("Failed to generate random bytes", zap.Error(err))
@@ -193,6 +288,9 @@ func (s *TokenService) GenerateRefreshToken(ctx context.Context, accountID, clie
 	}
 	tokenString := hex.EncodeToString(randomBytes)
 
+	if domain.HasOfflineAccess(scope) {
+		sessionID = ""
+	}
 	rt := &domain.RefreshToken{
 		Token:     tokenString,
 		AccountID: accountID,
@@ -201,9 +299,15 @@ func (s *TokenService) GenerateRefreshToken(ctx context.Context, accountID, clie
 		Scope:     scope,
 		IP:        audit.IPFromContext(ctx),
 		UserAgent: audit.UserAgentFromContext(ctx),
+		Cnf:       cnf,
 	}
+	rt.AuthorizationDetails = authorizationDetails
+	rt.Resources = resources
+	rt.UserInfoClaims = userInfoClaims
+	rt.Policy = policy
 	now := time.Now()
-	rt.ExpiresAt = now.Add(s.refreshExpiry)
+	rt.GrantedAt = now
+	rt.ExpiresAt = s.refreshTokenPolicy(rt).ExpiresAt(now, now, s.refreshExpiry)
 	rt.CreatedAt = now
 
 	// When IP binding enforcement is enabled, reject token creation when IP is empty.
@@ -222,10 +326,7 @@ func (s *TokenService) GenerateRefreshToken(ctx context.Context, accountID, clie
 	}
 
 	key := s.buildRefreshTokenKey(tokenString)
-	ttlSecs := int(math.Ceil(s.refreshExpiry.Seconds()))
-	if ttlSecs < 1 {
-		ttlSecs = 1
-	}
+	ttlSecs := refreshTokenTTLSeconds(rt.ExpiresAt, now)
 
 	// Atomically store the refresh token and update the session -> tokens index
 	// in a single Lua script to prevent partial state on crash.
@@ -244,6 +345,24 @@ func (s *TokenService) GenerateRefreshToken(ctx context.Context, accountID, clie
 	return rt, nil
 }
 
+// refreshTokenPolicy returns the lifetime policy of rt, defaulting to a sliding window.
+func (s *TokenService) refreshTokenPolicy(rt *domain.RefreshToken) domain.RefreshTokenPolicy {
+	if rt.Policy == nil {
+		return domain.RefreshTokenPolicy{Sliding: true}
+	}
+	return *rt.Policy
+}
+
+// refreshTokenTTLSeconds returns the Redis TTL of a refresh token expiring at expiresAt,
+// rounded up and at least one second.
+func refreshTokenTTLSeconds(expiresAt, now time.Time) int {
+	ttlSecs := int(math.Ceil(expiresAt.Sub(now).Seconds()))
+	if ttlSecs < 1 {
+		ttlSecs = 1
+	}
+	return ttlSecs
+}
+
 func (s *TokenService) buildRefreshTokenKey(token string) string {
 	return refreshTokenKeyPrefix + domain.HashToken(token)
 }
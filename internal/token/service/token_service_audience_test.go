@@ -88,3 +88,79 @@ func TestValidateAccessTokenWithContext_RejectsClientAudienceMismatch(t *testing
 	_, err = svc.ValidateAccessTokenWithContext(context.Background(), tokenString)
 	assert.Error(t, err)
 }
+
+func TestGenerateResourceAccessToken_RestrictsAudience(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	svc.SetProtectedResources([]string{"https://api.example.com/orders"})
+	ctx := context.Background()
+
+	tokenString, err := svc.GenerateResourceAccessToken(&domain.AccessTokenClaims{
+		AccountID: "account-resource",
+		ClientID:  "client-resource",
+		Scope:     "orders:read",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{"https://api.example.com/orders"},
+		},
+	}, 5*time.Minute)
+	require.NoError(t, err)
+
+	claims, err := svc.ParseAccessToken(ctx, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, jwt.ClaimStrings{"https://api.example.com/orders"}, claims.Audience)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), claims.ExpiresAt.Time, 5*time.Second)
+
+	result, err := svc.IntrospectToken(ctx, tokenString, "")
+	require.NoError(t, err)
+	assert.Equal(t, true, result["active"])
+	assert.Equal(t, jwt.ClaimStrings{"https://api.example.com/orders"}, result["aud"])
+
+	// The token is for another resource server, not for this server's own APIs.
+	_, err = svc.ValidateAccessTokenWithContext(ctx, tokenString)
+	assert.ErrorIs(t, err, ErrAudienceMismatch)
+}
+
+func TestGenerateResourceAccessToken_IssuerResource(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	svc.SetProtectedResources([]string{"http://localhost:8080"})
+
+	tokenString, err := svc.GenerateResourceAccessToken(&domain.AccessTokenClaims{
+		AccountID: "account-resource",
+		ClientID:  "client-resource",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{"http://localhost:8080"},
+		},
+	}, 0)
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateAccessTokenWithContext(context.Background(), tokenString)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(svc.AccessExpiry()), claims.ExpiresAt.Time, 5*time.Second)
+}
+
+func TestGenerateResourceAccessToken_RequiresAudience(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	_, err := svc.GenerateResourceAccessToken(&domain.AccessTokenClaims{AccountID: "account-resource", ClientID: "client-resource"}, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestParseAccessToken_RejectsUnregisteredResourceAudience(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	svc.SetProtectedResources([]string{"https://api.example.com/orders"})
+
+	tokenString, err := svc.GenerateResourceAccessToken(&domain.AccessTokenClaims{
+		AccountID: "account-resource",
+		ClientID:  "client-resource",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{"https://api.example.com/orders", "https://api.example.com/unknown"},
+		},
+	}, time.Minute)
+	require.NoError(t, err)
+
+	_, err = svc.ParseAccessToken(context.Background(), tokenString)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
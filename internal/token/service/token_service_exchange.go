@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/rushairer/gosso/internal/audit"
+	auditDomain "github.com/rushairer/gosso/internal/audit/domain"
+	auditService "github.com/rushairer/gosso/internal/audit/service"
+	"github.com/rushairer/gosso/internal/token/domain"
+	"github.com/rushairer/gosso/internal/utility"
+)
+
+// maxActorChainDepth bounds the nested act claims carried by exchanged tokens so a
+// token cannot grow without limit through repeated exchanges.
+const maxActorChainDepth = 5
+
+// ErrActorChainTooDeep is returned when an exchange would exceed maxActorChainDepth.
+var ErrActorChainTooDeep = errors.New("token exchange: delegation chain too deep")
+
+// TokenExchangeRequest describes a token exchange (RFC 8693) that the caller has already
+// authorized: the subject and actor tokens are validated, the client is authenticated
+// and the requested audiences are allowed by the client's policy.
+type TokenExchangeRequest struct {
+	// ClientID is the authenticated client performing the exchange.
+	ClientID string
+	// Subject holds the claims of the validated subject_token.
+	Subject *domain.AccessTokenClaims
+	// Actor holds the claims of the validated actor_token, if one was presented.
+	// Without an actor token the requesting client is recorded as the actor.
+	Actor *domain.AccessTokenClaims
+	// Audiences are the requested audience and resource values.
+	Audiences []string
+	// Scope is the scope of the issued token, already narrowed to the subject's scope.
+	Scope string
+	// Cnf binds the issued token to the requesting client's DPoP key or certificate, if any.
+	Cnf *domain.ConfirmationClaim
+	// Lifetime, when positive, replaces the configured access token lifetime. The token
+	// still never outlives the subject token.
+	Lifetime time.Duration
+	// Profile is domain.AccessTokenProfileRFC9068 to issue the token in the JWT profile for
+	// OAuth 2.0 access tokens, and ProfileSubject its sub claim; empty uses the account ID.
+	Profile        string
+	ProfileSubject string
+	// Reference issues an opaque reference token, as GenerateReferenceAccessToken does,
+	// instead of a JWT.
+	Reference bool
+}
+
+// ExchangeToken issues an access token for req.Subject that is targeted at req.Audiences
+// and carries an act claim naming the acting party. Like GenerateResourceAccessToken it
+// does not add client_id to aud, so the token is only good at those audiences. The new
+// token never outlives the subject token. Every exchange is recorded in the audit log.
+func (s *TokenService) ExchangeToken(ctx context.Context, req *TokenExchangeRequest) (string, time.Time, error) {
+	if req == nil || req.Subject == nil || req.ClientID == "" {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	subject := req.Subject
+
+	act := &domain.ActorClaim{Subject: req.ClientID, ClientID: req.ClientID, Act: subject.Act}
+	if req.Actor != nil {
+		act = &domain.ActorClaim{Subject: req.Actor.AccountID, ClientID: req.Actor.ClientID, Act: subject.Act}
+	}
+	if actorChainDepth(act) > maxActorChainDepth {
+		return "", time.Time{}, ErrActorChainTooDeep
+	}
+
+	lifetime := req.Lifetime
+	if lifetime <= 0 {
+		lifetime = s.accessExpiry
+	}
+	now := time.Now()
+	expiresAt := now.Add(lifetime)
+	if subject.ExpiresAt != nil && subject.ExpiresAt.Before(expiresAt) {
+		expiresAt = subject.ExpiresAt.Time
+	}
+
+	claims := &domain.AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    s.issuer,
+			Audience:  jwt.ClaimStrings(append([]string(nil), req.Audiences...)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		AccountID:   subject.AccountID,
+		Username:    subject.Username,
+		Email:       subject.Email,
+		Roles:       subject.Roles,
+		Permissions: subject.Permissions,
+		Scope:       req.Scope,
+		ClientID:    req.ClientID,
+		SessionID:   subject.SessionID,
+		Act:         act,
+		Cnf:         req.Cnf,
+		Profile:     req.Profile,
+	}
+	claims.Subject = req.ProfileSubject
+	claims.Subject = accessTokenSubject(claims)
+
+	var tokenString string
+	var err error
+	if req.Reference {
+		tokenString, err = s.storeReferenceAccessToken(ctx, claims)
+	} else {
+		tokenString, err = s.signToken(claims, "exchanged access token")
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	auditService.AuditLog(ctx, s.auditor, s.logger, auditDomain.NewRecord(
+		auditDomain.ActionOAuth2TokenExchange,
+		audit.IPFromContext(ctx),
+		utility.Ptr[string](subject.AccountID),
+		utility.MarshalJSONOrEmpty(map[string]any{
+			"client_id":         req.ClientID,
+			"subject_client_id": subject.ClientID,
+			"subject_jti":       subject.ID,
+			"actor_sub":         act.Subject,
+			"actor_client_id":   act.ClientID,
+			"audience":          req.Audiences,
+			"scope":             req.Scope,
+			"jti":               claims.ID,
+		}),
+		utility.MarshalJSONOrEmpty(map[string]any{
+			"ip":         audit.IPFromContext(ctx),
+			"user_agent": audit.UserAgentFromContext(ctx),
+		}),
+	))
+
+	return tokenString, expiresAt, nil
+}
+
+func actorChainDepth(act *domain.ActorClaim) int {
+	depth := 0
+	for ; act != nil; act = act.Act {
+		depth++
+	}
+	return depth
+}
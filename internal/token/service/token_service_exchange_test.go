@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/token/domain"
+)
+
+func TestExchangeToken_IssuesDelegatedToken(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	subjectExpiry := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	subject := &domain.AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ID: "subject-jti", ExpiresAt: jwt.NewNumericDate(subjectExpiry)},
+		AccountID:        "account-001",
+		Username:         "alice",
+		Roles:            []string{"user"},
+		Scope:            "openid orders:read",
+		ClientID:         "frontend-client",
+		SessionID:        "session-001",
+	}
+	actor := &domain.AccessTokenClaims{AccountID: "service-account", ClientID: "orders-gateway"}
+
+	tokenString, expiresAt, err := svc.ExchangeToken(ctx, &TokenExchangeRequest{
+		ClientID:  "orders-gateway",
+		Subject:   subject,
+		Actor:     actor,
+		Audiences: []string{"orders-api"},
+		Scope:     "orders:read",
+	})
+	require.NoError(t, err)
+	// The exchanged token must not outlive the subject token.
+	assert.Equal(t, subjectExpiry, expiresAt)
+
+	claims, err := svc.ParseAccessToken(ctx, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", claims.Subject)
+	assert.Equal(t, "alice", claims.Username)
+	assert.Equal(t, []string{"user"}, claims.Roles)
+	assert.Equal(t, "orders:read", claims.Scope)
+	assert.Equal(t, "orders-gateway", claims.ClientID)
+	assert.Equal(t, "session-001", claims.SessionID)
+	assert.Equal(t, jwt.ClaimStrings{"orders-api"}, claims.Audience)
+	assert.NotEqual(t, "subject-jti", claims.ID)
+	assert.Equal(t, subjectExpiry.Unix(), claims.ExpiresAt.Unix())
+	require.NotNil(t, claims.Act)
+	assert.Equal(t, &domain.ActorClaim{Subject: "service-account", ClientID: "orders-gateway"}, claims.Act)
+
+	result, err := svc.IntrospectToken(ctx, tokenString, "")
+	require.NoError(t, err)
+	assert.Equal(t, claims.Act, result["act"])
+
+	// The token is narrowed to its audience, so this server's own APIs refuse it.
+	_, err = svc.ValidateAccessTokenWithContext(ctx, tokenString)
+	assert.ErrorIs(t, err, ErrAudienceMismatch)
+}
+
+func TestExchangeToken_RequestingClientIsActorByDefault(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tokenString, expiresAt, err := svc.ExchangeToken(ctx, &TokenExchangeRequest{
+		ClientID:  "orders-gateway",
+		Subject:   &domain.AccessTokenClaims{AccountID: "account-001", Scope: "openid"},
+		Audiences: []string{"orders-api"},
+		Scope:     "openid",
+	})
+	require.NoError(t, err)
+	// Without a subject expiry the configured access token lifetime applies.
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, 5*time.Second)
+
+	claims, err := svc.ParseAccessToken(ctx, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.ActorClaim{Subject: "orders-gateway", ClientID: "orders-gateway"}, claims.Act)
+}
+
+func TestExchangeToken_Lifetime(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, expiresAt, err := svc.ExchangeToken(ctx, &TokenExchangeRequest{
+		ClientID:  "orders-gateway",
+		Subject:   &domain.AccessTokenClaims{AccountID: "account-001"},
+		Audiences: []string{"orders-api"},
+		Lifetime:  2 * time.Minute,
+	})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), expiresAt, 5*time.Second)
+
+	// The subject token still bounds a longer lifetime.
+	subjectExpiry := time.Now().Add(time.Minute).Truncate(time.Second)
+	_, expiresAt, err = svc.ExchangeToken(ctx, &TokenExchangeRequest{
+		ClientID: "orders-gateway",
+		Subject: &domain.AccessTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(subjectExpiry)},
+			AccountID:        "account-001",
+		},
+		Audiences: []string{"orders-api"},
+		Lifetime:  time.Hour,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, subjectExpiry, expiresAt)
+}
+
+func TestExchangeToken_RFC9068Profile(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	svc.SetSubjectResolver(stubSubjectResolver{"orders-gateway|pairwise-sub": "account-001"})
+	ctx := context.Background()
+
+	tokenString, _, err := svc.ExchangeToken(ctx, &TokenExchangeRequest{
+		ClientID:       "orders-gateway",
+		Subject:        &domain.AccessTokenClaims{AccountID: "account-001", Scope: "orders:read"},
+		Audiences:      []string{"orders-api"},
+		Scope:          "orders:read",
+		Profile:        domain.AccessTokenProfileRFC9068,
+		ProfileSubject: "pairwise-sub",
+	})
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "at+jwt", token.Header["typ"])
+	raw := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, "pairwise-sub", raw["sub"])
+	assert.NotContains(t, raw, "account_id")
+	assert.Equal(t, map[string]any{"sub": "orders-gateway", "client_id": "orders-gateway"}, raw["act"])
+
+	claims, err := svc.ParseAccessToken(ctx, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", claims.AccountID)
+}
+
+func TestExchangeToken_ReferenceToken(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	subjectExpiry := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	tokenString, expiresAt, err := svc.ExchangeToken(ctx, &TokenExchangeRequest{
+		ClientID: "orders-gateway",
+		Subject: &domain.AccessTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(subjectExpiry)},
+			AccountID:        "account-001",
+			Scope:            "orders:read",
+		},
+		Audiences: []string{"orders-api"},
+		Scope:     "orders:read",
+		Reference: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, subjectExpiry, expiresAt)
+	assert.Len(t, tokenString, 2*referenceTokenLength)
+
+	claims, err := svc.ParseAccessToken(ctx, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", claims.Subject)
+	assert.Equal(t, jwt.ClaimStrings{"orders-api"}, claims.Audience)
+	assert.Equal(t, subjectExpiry.Unix(), claims.ExpiresAt.Unix())
+	assert.Equal(t, &domain.ActorClaim{Subject: "orders-gateway", ClientID: "orders-gateway"}, claims.Act)
+}
+
+func TestExchangeToken_NestsPriorActors(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	prior := &domain.ActorClaim{Subject: "orders-gateway", ClientID: "orders-gateway"}
+	tokenString, _, err := svc.ExchangeToken(ctx, &TokenExchangeRequest{
+		ClientID:  "billing-service",
+		Subject:   &domain.AccessTokenClaims{AccountID: "account-001", Act: prior},
+		Audiences: []string{"billing-api"},
+	})
+	require.NoError(t, err)
+
+	claims, err := svc.ParseAccessToken(ctx, tokenString)
+	require.NoError(t, err)
+	require.NotNil(t, claims.Act)
+	assert.Equal(t, "billing-service", claims.Act.Subject)
+	assert.Equal(t, prior, claims.Act.Act)
+}
+
+func TestExchangeToken_RejectsDeepActorChain(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	var chain *domain.ActorClaim
+	for range maxActorChainDepth {
+		chain = &domain.ActorClaim{Subject: "svc", Act: chain}
+	}
+	_, _, err := svc.ExchangeToken(context.Background(), &TokenExchangeRequest{
+		ClientID:  "billing-service",
+		Subject:   &domain.AccessTokenClaims{AccountID: "account-001", Act: chain},
+		Audiences: []string{"billing-api"},
+	})
+	assert.ErrorIs(t, err, ErrActorChainTooDeep)
+}
+
+func TestExchangeToken_RequiresSubjectAndClient(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _, err := svc.ExchangeToken(ctx, nil)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = svc.ExchangeToken(ctx, &TokenExchangeRequest{ClientID: "orders-gateway"})
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = svc.ExchangeToken(ctx, &TokenExchangeRequest{Subject: &domain.AccessTokenClaims{AccountID: "account-001"}})
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
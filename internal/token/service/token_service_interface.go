@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/rushairer/gosso/internal/token/domain"
@@ -10,24 +11,48 @@ import (
 // TokenServiceInterface defines the contract for JWT and refresh token operations.
 // Concrete implementation is provided by TokenService.
 type TokenServiceInterface interface {
-	// GenerateAccessToken generates a JWT access token (RS256).
+	// GenerateAccessToken generates a JWT access token.
 	// Note: this always overrides ExpiresAt with the configured accessExpiry.
 	GenerateAccessToken(claims *domain.AccessTokenClaims) (string, error)
 
-	// GenerateShortLivedToken generates a JWT access token (RS256) that respects
+	// GenerateAccessTokenWithLifetime generates a JWT access token like GenerateAccessToken.
+	// A positive lifetime replaces the configured accessExpiry.
+	GenerateAccessTokenWithLifetime(claims *domain.AccessTokenClaims, lifetime time.Duration) (string, error)
+
+	// GenerateShortLivedToken generates a JWT access token that respects
 	// the caller-provided ExpiresAt. Useful for special purposes like MFA
 	// verification tokens.
 	GenerateShortLivedToken(claims *domain.AccessTokenClaims) (string, error)
 
+	// GenerateResourceAccessToken generates a JWT access token whose aud is restricted to
+	// the protected resources in claims.Audience (RFC 8707). A positive lifetime replaces
+	// the configured accessExpiry.
+	GenerateResourceAccessToken(claims *domain.AccessTokenClaims, lifetime time.Duration) (string, error)
+
+	// GenerateReferenceAccessToken generates an opaque access token whose claims are kept
+	// in Redis, for clients whose resource servers introspect their tokens.
+	GenerateReferenceAccessToken(ctx context.Context, claims *domain.AccessTokenClaims, lifetime time.Duration) (string, error)
+
+	// ExchangeToken issues a delegated access token for a validated subject token (RFC 8693).
+	ExchangeToken(ctx context.Context, req *TokenExchangeRequest) (string, time.Time, error)
+
 	// AccessExpiry returns the access token expiration duration.
 	AccessExpiry() time.Duration
 
 	// GenerateRefreshToken generates a random refresh token and stores it in Redis.
-	GenerateRefreshToken(ctx context.Context, accountID, clientID, sessionID, scope string) (*domain.RefreshToken, error)
+	// A non-nil cnf binds the token to a DPoP key (RFC 9449 §5) or client certificate (RFC 8705 §4).
+	// Access tokens obtained with it are restricted to resources (RFC 8707). A nil policy
+	// applies the server default lifetime; offline_access tokens are not tied to sessionID.
+	GenerateRefreshToken(ctx context.Context, accountID, clientID, sessionID, scope string, authorizationDetails json.RawMessage, resources, userInfoClaims []string, cnf *domain.ConfirmationClaim, policy *domain.RefreshTokenPolicy) (*domain.RefreshToken, error)
 
 	// ValidateAccessTokenWithContext validates a JWT access token using the request context.
+	// Certificate-bound tokens require the bound client certificate in ctx (RFC 8705 §3).
 	ValidateAccessTokenWithContext(ctx context.Context, tokenString string) (*domain.AccessTokenClaims, error)
 
+	// ParseAccessToken validates a JWT or reference access token without checking its
+	// certificate binding, for introspection, revocation and token exchange.
+	ParseAccessToken(ctx context.Context, tokenString string) (*domain.AccessTokenClaims, error)
+
 	// ValidateRefreshToken validates a refresh token.
 	ValidateRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error)
 
@@ -46,8 +71,9 @@ type TokenServiceInterface interface {
 	// RevokeAccountTokens marks all access tokens for the given account as revoked.
 	RevokeAccountTokens(ctx context.Context, accountID string) error
 
-	// IntrospectToken validates a token and returns its active status (RFC 7662).
-	IntrospectToken(ctx context.Context, tokenString string) (map[string]any, error)
+	// IntrospectToken validates an access or refresh token and returns its active status
+	// (RFC 7662). tokenTypeHint selects the type looked up first.
+	IntrospectToken(ctx context.Context, tokenString, tokenTypeHint string) (map[string]any, error)
 
 	// KeyService returns the underlying key service.
 	// Exposed for OIDC JWKS endpoint (public key distribution) and ID token signing.
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/token/domain"
+)
+
+type stubSubjectResolver map[string]string
+
+func (r stubSubjectResolver) ResolveAccessTokenSubject(_ context.Context, clientID, subject string) (string, error) {
+	return r[clientID+"|"+subject], nil
+}
+
+func TestGenerateAccessToken_RFC9068Profile(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	svc.SetSubjectResolver(stubSubjectResolver{"client-9068|pairwise-sub": "account-9068"})
+	ctx := context.Background()
+
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{
+		AccountID:   "account-9068",
+		ClientID:    "client-9068",
+		Scope:       "openid profile",
+		SessionID:   "sid-9068",
+		Permissions: []string{"orders:read"},
+		AuthTime:    jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		AMR:         []string{"pwd"},
+		Profile:     domain.AccessTokenProfileRFC9068,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "pairwise-sub",
+		},
+	})
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "at+jwt", token.Header["typ"])
+	raw := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, "pairwise-sub", raw["sub"])
+	assert.Equal(t, "client-9068", raw["client_id"])
+	assert.NotContains(t, raw, "account_id")
+	assert.NotContains(t, raw, "permissions")
+	assert.Equal(t, []any{"orders:read"}, raw["entitlements"])
+	assert.Equal(t, []any{"pwd"}, raw["amr"])
+	assert.Contains(t, raw, "auth_time")
+
+	claims, err := svc.ParseAccessToken(ctx, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccessTokenProfileRFC9068, claims.Profile)
+	assert.Equal(t, "account-9068", claims.AccountID)
+	assert.Equal(t, []string{"orders:read"}, claims.Permissions)
+
+	result, err := svc.IntrospectToken(ctx, tokenString, "")
+	require.NoError(t, err)
+	assert.Equal(t, true, result["active"])
+	assert.Equal(t, "account-9068", result["sub"])
+}
+
+func TestGenerateAccessToken_RFC9068Profile_ClientSubject(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{
+		AccountID: "owner-account",
+		ClientID:  "client-cc",
+		Scope:     "api",
+		Profile:   domain.AccessTokenProfileRFC9068,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "client-cc",
+		},
+	})
+	require.NoError(t, err)
+
+	// Without a resolver a subject naming the client carries no account.
+	claims, err := svc.ParseAccessToken(context.Background(), tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "client-cc", claims.Subject)
+	assert.Empty(t, claims.AccountID)
+}
+
+func TestGenerateAccessToken_DefaultProfileIgnoresSubject(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{
+		AccountID: "account-default",
+		ClientID:  "client-default",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "client-default",
+		},
+	})
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.NotEqual(t, "at+jwt", token.Header["typ"])
+	assert.Equal(t, "account-default", token.Claims.(jwt.MapClaims)["sub"])
+	assert.Equal(t, "account-default", token.Claims.(jwt.MapClaims)["account_id"])
+}
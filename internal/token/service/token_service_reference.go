@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/cache"
+	"github.com/rushairer/gosso/internal/token/domain"
+)
+
+const (
+	referenceTokenKeyPrefix = "reference_token:"
+	referenceTokenLength    = 32 // 32 bytes = 64 hex chars
+)
+
+// GenerateReferenceAccessToken generates an opaque reference access token and stores its
+// claims in Redis until it expires. Resource servers cannot read it and must introspect
+// it; everything else accepts it like a JWT access token. aud defaults to the client_id,
+// so claims restricted to protected resources (RFC 8707) stay restricted. A positive
+// lifetime replaces the configured accessExpiry.
+func (s *TokenService) GenerateReferenceAccessToken(ctx context.Context, claims *domain.AccessTokenClaims, lifetime time.Duration) (string, error) {
+	if lifetime <= 0 {
+		lifetime = s.accessExpiry
+	}
+	now := time.Now()
+	clonedClaims := *claims
+	if clonedClaims.ID == "" {
+		clonedClaims.ID = uuid.New().String()
+	}
+	clonedClaims.Issuer = s.issuer
+	clonedClaims.Subject = accessTokenSubject(&clonedClaims)
+	clonedClaims.IssuedAt = jwt.NewNumericDate(now)
+	clonedClaims.ExpiresAt = jwt.NewNumericDate(now.Add(lifetime))
+	if len(clonedClaims.Audience) == 0 {
+		ensureClientAudience(&clonedClaims)
+	}
+	return s.storeReferenceAccessToken(ctx, &clonedClaims)
+}
+
+// storeReferenceAccessToken stores complete claims under a new reference token until
+// claims.ExpiresAt and returns the token.
+func (s *TokenService) storeReferenceAccessToken(ctx context.Context, claims *domain.AccessTokenClaims) (string, error) {
+	randomBytes := make([]byte, referenceTokenLength)
+	if _, err := rand.Read(randomBytes); err != nil {
+		s.logger.Error("Failed to generate random bytes", zap.Error(err))
+		return "", fmt.Errorf("generate reference token: %w", err)
+	}
+	tokenString := hex.EncodeToString(randomBytes)
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("marshal reference token: %w", err)
+	}
+	if err := s.redis.Set(ctx, s.buildReferenceTokenKey(tokenString), data, time.Until(claims.ExpiresAt.Time)); err != nil {
+		s.logger.Error("Failed to store reference token", zap.Error(err))
+		return "", fmt.Errorf("store reference token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// isReferenceToken reports whether tokenString is an opaque token rather than a JWT, which
+// always has three dot-separated parts.
+func isReferenceToken(tokenString string) bool {
+	return !strings.Contains(tokenString, ".")
+}
+
+// loadReferenceAccessToken returns the claims stored for a reference access token. Unknown
+// and expired tokens are ErrInvalidToken.
+func (s *TokenService) loadReferenceAccessToken(ctx context.Context, tokenString string) (*domain.AccessTokenClaims, error) {
+	data, err := s.redis.Get(ctx, s.buildReferenceTokenKey(tokenString))
+	if errors.Is(err, cache.ErrKeyNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get reference token: %w: %w", ErrTokenStoreUnavailable, err)
+	}
+
+	var claims domain.AccessTokenClaims
+	if err := json.Unmarshal([]byte(data), &claims); err != nil {
+		return nil, fmt.Errorf("unmarshal reference token: %w", err)
+	}
+	// Defense-in-depth: explicit expiry check in addition to Redis TTL.
+	if claims.ExpiresAt == nil || time.Now().After(claims.ExpiresAt.Time) {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+func (s *TokenService) buildReferenceTokenKey(token string) string {
+	return referenceTokenKeyPrefix + domain.HashToken(token)
+}
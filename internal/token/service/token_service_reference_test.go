@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/token/domain"
+)
+
+func TestGenerateReferenceAccessToken(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tokenString, err := svc.GenerateReferenceAccessToken(ctx, &domain.AccessTokenClaims{
+		AccountID: "account-ref",
+		ClientID:  "client-ref",
+		Scope:     "openid orders:read",
+		SessionID: "sid-ref",
+		Cnf:       &domain.ConfirmationClaim{JKT: "thumbprint"},
+	}, 2*time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, tokenString, 2*referenceTokenLength)
+	assert.NotContains(t, tokenString, ".")
+
+	claims, err := svc.ValidateAccessTokenWithContext(ctx, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "account-ref", claims.AccountID)
+	assert.Equal(t, "account-ref", claims.Subject)
+	assert.Equal(t, "http://localhost:8080", claims.Issuer)
+	assert.Equal(t, []string{"client-ref"}, []string(claims.Audience))
+	assert.Equal(t, "thumbprint", claims.DPoPThumbprint())
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), claims.ExpiresAt.Time, 5*time.Second)
+
+	result, err := svc.IntrospectToken(ctx, tokenString, TokenTypeHintAccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, true, result["active"])
+	assert.Equal(t, "client-ref", result["client_id"])
+	assert.Equal(t, "sid-ref", result["sid"])
+	assert.Equal(t, "DPoP", result["token_type"])
+
+	require.NoError(t, svc.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time))
+	_, err = svc.ParseAccessToken(ctx, tokenString)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestGenerateReferenceAccessToken_ResourceAudience(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+	svc.SetProtectedResources([]string{"https://api.example.com"})
+	ctx := context.Background()
+
+	tokenString, err := svc.GenerateReferenceAccessToken(ctx, &domain.AccessTokenClaims{
+		AccountID: "account-ref",
+		ClientID:  "client-ref",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{"https://api.example.com"},
+		},
+	}, 0)
+	require.NoError(t, err)
+
+	claims, err := svc.ParseAccessToken(ctx, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://api.example.com"}, []string(claims.Audience))
+	assert.WithinDuration(t, time.Now().Add(svc.AccessExpiry()), claims.ExpiresAt.Time, 5*time.Second)
+
+	_, err = svc.ValidateAccessTokenWithContext(ctx, tokenString)
+	assert.ErrorIs(t, err, ErrAudienceMismatch)
+}
+
+func TestParseAccessToken_UnknownReferenceToken(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	_, err := svc.ParseAccessToken(context.Background(), "0123456789abcdef")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -27,15 +27,21 @@ const (
 )
 
 type refreshTokenReplay struct {
-	Token     string    `json:"token"`
-	AccountID string    `json:"account_id"`
-	ClientID  string    `json:"client_id,omitempty"`
-	SessionID string    `json:"session_id,omitempty"`
-	Scope     string    `json:"scope,omitempty"`
-	IP        string    `json:"ip,omitempty"`
-	UserAgent string    `json:"user_agent,omitempty"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
+	Token     string                     `json:"token"`
+	AccountID string                     `json:"account_id"`
+	ClientID  string                     `json:"client_id,omitempty"`
+	SessionID string                     `json:"session_id,omitempty"`
+	Scope     string                     `json:"scope,omitempty"`
+	IP        string                     `json:"ip,omitempty"`
+	UserAgent string                     `json:"user_agent,omitempty"`
+	Cnf       *domain.ConfirmationClaim  `json:"cnf,omitempty"`
+	Details   json.RawMessage            `json:"authorization_details,omitempty"`
+	Resources []string                   `json:"resources,omitempty"`
+	Claims    []string                   `json:"userinfo_claims,omitempty"`
+	Policy    *domain.RefreshTokenPolicy `json:"policy,omitempty"`
+	GrantedAt time.Time                  `json:"granted_at,omitempty"`
+	ExpiresAt time.Time                  `json:"expires_at"`
+	CreatedAt time.Time                  `json:"created_at"`
 }
 
 // rotateAndDeleteAndCleanSessionScript atomically retrieves and deletes a refresh token,
@@ -143,7 +149,18 @@ func (s *TokenService) RotateRefreshToken(ctx context.Context, oldToken string)
 		return nil, fmt.Errorf("unmarshal old refresh token: %w", unmarshalErr)
 	}
 
-	newRT, err := domain.NewRefreshToken(newTokenString, oldRT.AccountID, time.Now().Add(s.refreshExpiry))
+	// Tokens stored before lifetime policies were introduced have no grant time; their
+	// chain starts now.
+	now := time.Now()
+	grantedAt := oldRT.GrantedAt
+	if grantedAt.IsZero() {
+		grantedAt = now
+	}
+	expiresAt := s.refreshTokenPolicy(&oldRT).ExpiresAt(grantedAt, now, s.refreshExpiry)
+	if !expiresAt.After(now) {
+		return nil, fmt.Errorf("refresh token grant expired: %w", cache.ErrKeyNotFound)
+	}
+	newRT, err := domain.NewRefreshToken(newTokenString, oldRT.AccountID, expiresAt)
 	if err != nil {
 		return nil, fmt.Errorf("create new refresh token: %w", err)
 	}
@@ -152,12 +169,18 @@ func (s *TokenService) RotateRefreshToken(ctx context.Context, oldToken string)
 	newRT.Scope = oldRT.Scope
 	newRT.IP = oldRT.IP
 	newRT.UserAgent = oldRT.UserAgent
+	newRT.Cnf = oldRT.Cnf
+	newRT.AuthorizationDetails = oldRT.AuthorizationDetails
+	newRT.Resources = oldRT.Resources
+	newRT.UserInfoClaims = oldRT.UserInfoClaims
+	newRT.Policy = oldRT.Policy
+	newRT.GrantedAt = grantedAt
 
 	// 3. Atomically consume old token, store new token, update indexes, and
 	// publish a short replay result for concurrent retries.
 	newHash := domain.HashToken(newTokenString)
 	newKey := s.buildRefreshTokenKey(newTokenString)
-	expirySeconds := int(math.Ceil(s.refreshExpiry.Seconds()))
+	expirySeconds := refreshTokenTTLSeconds(newRT.ExpiresAt, now)
 	replaySeconds := int(math.Ceil(refreshTokenReplayTTL.Seconds()))
 
 	newData, err := json.Marshal(newRT)
@@ -172,6 +195,12 @@ func (s *TokenService) RotateRefreshToken(ctx context.Context, oldToken string)
 		Scope:     newRT.Scope,
 		IP:        newRT.IP,
 		UserAgent: newRT.UserAgent,
+		Cnf:       newRT.Cnf,
+		Details:   newRT.AuthorizationDetails,
+		Resources: newRT.Resources,
+		Claims:    newRT.UserInfoClaims,
+		Policy:    newRT.Policy,
+		GrantedAt: newRT.GrantedAt,
 		ExpiresAt: newRT.ExpiresAt,
 		CreatedAt: newRT.CreatedAt,
 	})
@@ -248,7 +277,7 @@ func parseRefreshTokenReplay(data string) (*domain.RefreshToken, error) {
 	if replay.Token == "" || replay.AccountID == "" || replay.ExpiresAt.IsZero() {
 		return nil, fmt.Errorf("invalid refresh token replay")
 	}
-	return &domain.RefreshToken{
+	rt := &domain.RefreshToken{
 		Token:     replay.Token,
 		AccountID: replay.AccountID,
 		ClientID:  replay.ClientID,
@@ -256,9 +285,16 @@ func parseRefreshTokenReplay(data string) (*domain.RefreshToken, error) {
 		Scope:     replay.Scope,
 		IP:        replay.IP,
 		UserAgent: replay.UserAgent,
+		Cnf:       replay.Cnf,
+		Policy:    replay.Policy,
+		GrantedAt: replay.GrantedAt,
 		ExpiresAt: replay.ExpiresAt,
 		CreatedAt: replay.CreatedAt,
-	}, nil
+	}
+	rt.AuthorizationDetails = replay.Details
+	rt.Resources = replay.Resources
+	rt.UserInfoClaims = replay.Claims
+	return rt, nil
 }
 
 // RevokeRefreshToken revokes a refresh token and removes it from the session index.
@@ -296,6 +332,7 @@ func (s *TokenService) RevokeRefreshToken(ctx context.Context, token string) err
 }
 
 // RevokeAllForSession atomically revokes all refresh tokens under a given session.
+// Offline tokens are not indexed under a session and survive.
 // Uses a Lua script to read the session set, delete each refresh token key,
 // and delete the session set in a single atomic operation — preventing TOCTOU
 // race conditions with concurrent RotateRefreshToken calls.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/rushairer/gosso/internal/audit"
+	"github.com/rushairer/gosso/internal/mtls"
 	"github.com/rushairer/gosso/internal/testutil"
 	"github.com/rushairer/gosso/internal/token/domain"
 )
@@ -85,6 +87,29 @@ func TestGenerateAccessToken_RS256(t *testing.T) {
 	assert.NotNil(t, parsed.IssuedAt)
 }
 
+func TestGenerateAccessToken_ES256(t *testing.T) {
+	logger := zap.NewNop()
+	redisClient, _ := testutil.SetupTestRedis(t)
+	keySvc, err := NewKeyringKeyService(KeyringOptions{Dir: t.TempDir(), Overlap: time.Hour, KeyBits: 2048, Algorithms: []string{"RS256", "ES256"}}, logger)
+	require.NoError(t, err)
+	blacklist, err := NewBlacklistService(redisClient, logger)
+	require.NoError(t, err)
+	svc, err := NewTokenService(keySvc, "http://localhost:8080", 15*time.Minute, 7*24*time.Hour, redisClient, blacklist, nil, false, logger)
+	require.NoError(t, err)
+	assert.ErrorIs(t, svc.SetAccessTokenSigningAlg("EdDSA"), domain.ErrSigningAlgUnavailable)
+	require.NoError(t, svc.SetAccessTokenSigningAlg("ES256"))
+
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{AccountID: "account-001"})
+	require.NoError(t, err)
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &domain.AccessTokenClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "ES256", token.Header["alg"])
+
+	parsed, err := svc.ValidateAccessTokenWithContext(context.Background(), tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "account-001", parsed.AccountID)
+}
+
 func TestValidateAccessTokenWithContext_HS256Rejected(t *testing.T) {
 	svc, cleanup := setupTestTokenService(t)
 	defer cleanup()
@@ -194,7 +219,7 @@ func TestGenerateAndValidateRefreshToken(t *testing.T) {
 
 	ctx := context.Background()
 
-	rt, err := svc.GenerateRefreshToken(ctx, "account-001", "client-001", "session-001", "openid profile")
+	rt, err := svc.GenerateRefreshToken(ctx, "account-001", "client-001", "session-001", "openid profile", nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	assert.NotEmpty(t, rt.Token)
 	assert.Equal(t, "account-001", rt.AccountID)
@@ -220,7 +245,7 @@ func TestRotateRefreshToken(t *testing.T) {
 	ctx := audit.SetMetadata(context.Background(), "192.0.2.10", "test-agent", "req-001")
 
 	// Generate initial token
-	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "session-003", "openid")
+	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "session-003", "openid", nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	oldToken := rt.Token
 
@@ -246,13 +271,102 @@ func TestRotateRefreshToken(t *testing.T) {
 	_ = svc.RevokeRefreshToken(ctx, newRT.Token)
 }
 
+func TestRotateRefreshToken_PreservesDPoPBinding(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "", "openid", nil, nil, nil, &domain.ConfirmationClaim{JKT: "jkt-001"}, nil)
+	require.NoError(t, err)
+
+	newRT, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.ConfirmationClaim{JKT: "jkt-001"}, newRT.Cnf)
+
+	// A replayed rotation within the grace window returns the same binding.
+	replayed, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.ConfirmationClaim{JKT: "jkt-001"}, replayed.Cnf)
+}
+
+func TestRotateRefreshToken_FixedPolicyKeepsExpiry(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	policy := &domain.RefreshTokenPolicy{IdleLifetime: time.Hour, AbsoluteLifetime: 30 * time.Minute}
+	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "", "openid", nil, nil, nil, nil, policy)
+	require.NoError(t, err)
+	assert.WithinDuration(t, rt.GrantedAt.Add(30*time.Minute), rt.ExpiresAt, time.Second)
+
+	newRT, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.Equal(t, policy, newRT.Policy)
+	assert.True(t, rt.GrantedAt.Equal(newRT.GrantedAt))
+	assert.True(t, rt.ExpiresAt.Equal(newRT.ExpiresAt))
+}
+
+func TestRotateRefreshToken_PreservesAuthorizationDetails(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	details := json.RawMessage(`[{"type":"payment_initiation"}]`)
+	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "", "openid", details, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	newRT, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(details), string(newRT.AuthorizationDetails))
+
+	replayed, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(details), string(replayed.AuthorizationDetails))
+}
+
+func TestRotateRefreshToken_PreservesResources(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	resources := []string{"https://api.example.com/orders"}
+	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "", "openid orders:read", nil, resources, nil, nil, nil)
+	require.NoError(t, err)
+
+	newRT, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.Equal(t, resources, newRT.Resources)
+
+	replayed, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.Equal(t, resources, replayed.Resources)
+}
+
+func TestRotateRefreshToken_PreservesUserInfoClaims(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	claims := []string{"email", "name"}
+	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "", "openid", nil, nil, claims, nil, nil)
+	require.NoError(t, err)
+
+	newRT, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.Equal(t, claims, newRT.UserInfoClaims)
+
+	replayed, err := svc.RotateRefreshToken(ctx, rt.Token)
+	require.NoError(t, err)
+	assert.Equal(t, claims, replayed.UserInfoClaims)
+}
+
 func TestRotateRefreshToken_ReplaysRecentRotation(t *testing.T) {
 	svc, cleanup := setupTestTokenService(t)
 	defer cleanup()
 
 	ctx := audit.SetMetadata(context.Background(), "192.0.2.10", "test-agent", "req-001")
 
-	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "session-003", "openid")
+	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "session-003", "openid", nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	firstRotation, err := svc.RotateRefreshToken(ctx, rt.Token)
@@ -277,7 +391,7 @@ func TestRotateRefreshToken_ReplaysWhenOldTokenDisappearsDuringRotation(t *testi
 
 	ctx := audit.SetMetadata(context.Background(), "192.0.2.10", "test-agent", "req-001")
 
-	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "session-003", "openid")
+	rt, err := svc.GenerateRefreshToken(ctx, "account-003", "client-003", "session-003", "openid", nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	firstRotation, err := svc.RotateRefreshToken(ctx, rt.Token)
@@ -334,7 +448,7 @@ func TestRevokeRefreshToken(t *testing.T) {
 
 	ctx := context.Background()
 
-	rt, err := svc.GenerateRefreshToken(ctx, "account-004", "", "session-004", "")
+	rt, err := svc.GenerateRefreshToken(ctx, "account-004", "", "session-004", "", nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	// Revoke
@@ -428,6 +542,31 @@ func TestAccessExpiry(t *testing.T) {
 	assert.Equal(t, 15*time.Minute, svc.AccessExpiry())
 }
 
+func TestValidateAccessToken_CertificateBinding(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	cert, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client"})
+	other, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "other"})
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{
+		AccountID: "account-001",
+		Cnf:       &domain.ConfirmationClaim{X5TS256: mtls.Thumbprint(cert)},
+	})
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateAccessTokenWithContext(mtls.WithCertificate(context.Background(), cert), tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, mtls.Thumbprint(cert), claims.CertificateThumbprint())
+
+	_, err = svc.ValidateAccessTokenWithContext(context.Background(), tokenString)
+	assert.ErrorIs(t, err, ErrCertificateBindingMismatch)
+	_, err = svc.ValidateAccessTokenWithContext(mtls.WithCertificate(context.Background(), other), tokenString)
+	assert.ErrorIs(t, err, ErrCertificateBindingMismatch)
+
+	_, err = svc.ParseAccessToken(context.Background(), tokenString)
+	assert.NoError(t, err)
+}
+
 // ──────────────────────────────────────────────
 // IntrospectToken
 // ──────────────────────────────────────────────
@@ -447,7 +586,7 @@ func TestIntrospectToken_Active(t *testing.T) {
 	tokenString, err := svc.GenerateAccessToken(claims)
 	require.NoError(t, err)
 
-	result, err := svc.IntrospectToken(ctx, tokenString)
+	result, err := svc.IntrospectToken(ctx, tokenString, "")
 	require.NoError(t, err)
 	assert.Equal(t, true, result["active"])
 	assert.Equal(t, "account-introspect", result["sub"])
@@ -460,11 +599,62 @@ func TestIntrospectToken_Active(t *testing.T) {
 	assert.NotNil(t, result["jti"])
 }
 
+func TestIntrospectToken_DPoPBound(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{
+		AccountID: "account-introspect",
+		Cnf:       &domain.ConfirmationClaim{JKT: "jkt-001"},
+	})
+	require.NoError(t, err)
+
+	result, err := svc.IntrospectToken(context.Background(), tokenString, "")
+	require.NoError(t, err)
+	assert.Equal(t, "DPoP", result["token_type"])
+	assert.Equal(t, &domain.ConfirmationClaim{JKT: "jkt-001"}, result["cnf"])
+}
+
+func TestIntrospectToken_CertificateBound(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{
+		AccountID: "account-introspect",
+		Cnf:       &domain.ConfirmationClaim{X5TS256: "x5t-001"},
+	})
+	require.NoError(t, err)
+
+	// The introspecting resource server is not the token holder, so no certificate is needed.
+	result, err := svc.IntrospectToken(context.Background(), tokenString, "")
+	require.NoError(t, err)
+	assert.Equal(t, true, result["active"])
+	assert.Equal(t, "Bearer", result["token_type"])
+	assert.Equal(t, &domain.ConfirmationClaim{X5TS256: "x5t-001"}, result["cnf"])
+}
+
+func TestIntrospectToken_AuthorizationDetails(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{
+		AccountID:            "account-introspect",
+		AuthorizationDetails: json.RawMessage(`[{"type":"payment_initiation","creditorName":"Merchant A"}]`),
+	})
+	require.NoError(t, err)
+
+	result, err := svc.IntrospectToken(context.Background(), tokenString, "")
+	require.NoError(t, err)
+	details, ok := result["authorization_details"].(json.RawMessage)
+	require.True(t, ok)
+	assert.JSONEq(t, `[{"type":"payment_initiation","creditorName":"Merchant A"}]`, string(details))
+}
+
 func TestIntrospectToken_Inactive(t *testing.T) {
 	svc, cleanup := setupTestTokenService(t)
 	defer cleanup()
 
-	result, err := svc.IntrospectToken(context.Background(), "garbage-token")
+	result, err := svc.IntrospectToken(context.Background(), "garbage-token", "")
 	require.NoError(t, err)
 	assert.Equal(t, false, result["active"])
 }
@@ -490,11 +680,50 @@ func TestIntrospectToken_Revoked(t *testing.T) {
 	err = svc.blacklist.RevokeToken(ctx, parsed.ID, "test", parsed.ExpiresAt.Time)
 	require.NoError(t, err)
 
-	result, err := svc.IntrospectToken(ctx, tokenString)
+	result, err := svc.IntrospectToken(ctx, tokenString, "")
+	require.NoError(t, err)
+	assert.Equal(t, false, result["active"])
+}
+
+func TestIntrospectToken_RefreshToken(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rt, err := svc.GenerateRefreshToken(ctx, "account-001", "client-001", "session-001", "openid profile", nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	for _, hint := range []string{"", TokenTypeHintAccessToken, TokenTypeHintRefreshToken, "unknown"} {
+		result, err := svc.IntrospectToken(ctx, rt.Token, hint)
+		require.NoError(t, err, hint)
+		assert.Equal(t, true, result["active"], hint)
+		assert.Equal(t, "account-001", result["sub"], hint)
+		assert.Equal(t, "client-001", result["client_id"], hint)
+		assert.Equal(t, "session-001", result["sid"], hint)
+		assert.Equal(t, "openid profile", result["scope"], hint)
+		assert.Equal(t, rt.ExpiresAt.Unix(), result["exp"], hint)
+		assert.NotContains(t, result, "token_type", hint)
+	}
+
+	require.NoError(t, svc.RevokeRefreshToken(ctx, rt.Token))
+	result, err := svc.IntrospectToken(ctx, rt.Token, TokenTypeHintRefreshToken)
 	require.NoError(t, err)
 	assert.Equal(t, false, result["active"])
 }
 
+func TestIntrospectToken_AccessTokenWithRefreshHint(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	tokenString, err := svc.GenerateAccessToken(&domain.AccessTokenClaims{AccountID: "account-001", ClientID: "client-001"})
+	require.NoError(t, err)
+
+	result, err := svc.IntrospectToken(context.Background(), tokenString, TokenTypeHintRefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, true, result["active"])
+	assert.Equal(t, "Bearer", result["token_type"])
+}
+
 // ──────────────────────────────────────────────
 // RevokeAllForSession
 // ──────────────────────────────────────────────
@@ -505,7 +734,7 @@ func TestRevokeAllForSession(t *testing.T) {
 
 	ctx := context.Background()
 
-	rt, err := svc.GenerateRefreshToken(ctx, "acct-revoke-all", "client-1", "session-revoke-all", "openid")
+	rt, err := svc.GenerateRefreshToken(ctx, "acct-revoke-all", "client-1", "session-revoke-all", "openid", nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	err = svc.RevokeAllForSession(ctx, "session-revoke-all")
@@ -516,6 +745,23 @@ func TestRevokeAllForSession(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestRevokeAllForSession_KeepsOfflineTokens(t *testing.T) {
+	svc, cleanup := setupTestTokenService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	rt, err := svc.GenerateRefreshToken(ctx, "acct-offline", "client-1", "session-offline", "openid offline_access", nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, rt.IsOffline())
+	assert.Empty(t, rt.SessionID)
+
+	require.NoError(t, svc.RevokeAllForSession(ctx, "session-offline"))
+
+	_, err = svc.ValidateRefreshToken(ctx, rt.Token)
+	assert.NoError(t, err)
+}
+
 func TestRevokeAllForSession_Empty(t *testing.T) {
 	svc, cleanup := setupTestTokenService(t)
 	defer cleanup()
@@ -534,7 +780,7 @@ func TestRevokeAllForSession_MultipleTokens(t *testing.T) {
 	// Generate multiple tokens under the same session
 	tokens := make([]*domain.RefreshToken, 3)
 	for i := range tokens {
-		rt, err := svc.GenerateRefreshToken(ctx, "acct-revoke-multi", "client-1", sessionID, "openid")
+		rt, err := svc.GenerateRefreshToken(ctx, "acct-revoke-multi", "client-1", sessionID, "openid", nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		tokens[i] = rt
 	}
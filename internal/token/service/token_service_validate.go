@@ -2,47 +2,67 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 
 	"github.com/rushairer/gosso/internal/cache"
+	"github.com/rushairer/gosso/internal/mtls"
 	"github.com/rushairer/gosso/internal/token/domain"
 	"github.com/rushairer/gosso/internal/utility"
 )
 
 const accessTokenClockSkew = 30 * time.Second
 
-// ValidateAccessTokenWithContext validates a JWT access token using the request context
+// ValidateAccessTokenWithContext validates a JWT access token using the request context.
+// A token bound to a client certificate (cnf.x5t#S256) is only accepted when the request
+// presented that certificate (RFC 8705 §3); see mtls.CertificateFromContext. A token
+// restricted to protected resources (RFC 8707) is only accepted when the issuer is one of
+// them, since this server's own APIs are the resource being accessed.
 func (s *TokenService) ValidateAccessTokenWithContext(ctx context.Context, tokenString string) (*domain.AccessTokenClaims, error) {
-	token, err := s.parser.ParseWithClaims(tokenString, &domain.AccessTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		// Enforce RS256 specifically to prevent algorithm downgrade attacks (e.g., RS384/RS512).
-		if token.Method.Alg() != "RS256" {
-			return nil, fmt.Errorf("unexpected signing algorithm: %v", token.Method.Alg())
-		}
-		return s.keySvc.PublicKey(), nil
-	})
+	claims, err := s.ParseAccessToken(ctx, tokenString)
 	if err != nil {
-		return nil, fmt.Errorf("parse access token: %w", err)
+		return nil, err
+	}
+	if claims.ClientID != "" && !slices.Contains(claims.Audience, claims.ClientID) && !slices.Contains(claims.Audience, s.issuer) {
+		return nil, ErrAudienceMismatch
 	}
+	if x5t := claims.CertificateThumbprint(); x5t != "" {
+		presented := mtls.Thumbprint(mtls.CertificateFromContext(ctx))
+		if subtle.ConstantTimeCompare([]byte(x5t), []byte(presented)) != 1 {
+			return nil, ErrCertificateBindingMismatch
+		}
+	}
+	return claims, nil
+}
 
-	claims, ok := token.Claims.(*domain.AccessTokenClaims)
-	if !ok || !token.Valid {
-		return nil, ErrInvalidToken
+// ParseAccessToken validates a JWT or reference access token like
+// ValidateAccessTokenWithContext but does not check its certificate binding. It is for
+// endpoints where the token is presented by a party other than its holder: introspection,
+// revocation and token exchange.
+func (s *TokenService) ParseAccessToken(ctx context.Context, tokenString string) (*domain.AccessTokenClaims, error) {
+	var claims *domain.AccessTokenClaims
+	var err error
+	if isReferenceToken(tokenString) {
+		claims, err = s.loadReferenceAccessToken(ctx, tokenString)
+	} else {
+		claims, err = s.parseJWTAccessToken(ctx, tokenString)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// OAuth client-bound tokens must carry an audience matching their client_id.
-	// This keeps newly issued tokens standards-compliant and rejects malformed
-	// tokens that include client_id but omit or mismatch aud.
-	if claims.ClientID != "" && !slices.Contains(claims.Audience, claims.ClientID) {
+	// OAuth client-bound tokens must carry an audience matching their client_id, only
+	// the protected resources they were issued for (RFC 8707), or, for exchanged tokens,
+	// the audiences they were exchanged for (RFC 8693). This keeps newly issued tokens
+	// standards-compliant and rejects malformed tokens that include client_id but omit or
+	// mismatch aud.
+	if claims.ClientID != "" && !slices.Contains(claims.Audience, claims.ClientID) && !s.isResourceAudience(claims.Audience) && (claims.Act == nil || len(claims.Audience) == 0) {
 		return nil, ErrInvalidToken
 	}
 
@@ -80,6 +100,60 @@ func (s *TokenService) ValidateAccessTokenWithContext(ctx context.Context, token
 	return claims, nil
 }
 
+// parseJWTAccessToken verifies the signature and registered claims of a JWT access token.
+func (s *TokenService) parseJWTAccessToken(ctx context.Context, tokenString string) (*domain.AccessTokenClaims, error) {
+	// The parser only accepts domain.SigningAlgs, and Keyfunc only returns keys of the
+	// token's algorithm, which rules out algorithm confusion and downgrades.
+	token, err := s.parser.ParseWithClaims(tokenString, &domain.AccessTokenClaims{}, s.keySvc.Keyfunc)
+	if err != nil {
+		return nil, fmt.Errorf("parse access token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*domain.AccessTokenClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	if typ, _ := token.Header["typ"].(string); typ == domain.JWTAccessTokenType {
+		if err := s.resolveJWTProfile(ctx, claims); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+// resolveJWTProfile maps the claims of an RFC 9068 access token onto AccessTokenClaims:
+// the account ID is resolved from sub, and entitlements become permissions.
+func (s *TokenService) resolveJWTProfile(ctx context.Context, claims *domain.AccessTokenClaims) error {
+	claims.Profile = domain.AccessTokenProfileRFC9068
+	claims.Permissions, claims.Entitlements = claims.Entitlements, nil
+	switch {
+	case s.subjects != nil:
+		accountID, err := s.subjects.ResolveAccessTokenSubject(ctx, claims.ClientID, claims.Subject)
+		if err != nil {
+			return fmt.Errorf("resolve access token subject: %w", err)
+		}
+		claims.AccountID = accountID
+	case claims.Subject == claims.ClientID:
+		claims.AccountID = ""
+	default:
+		claims.AccountID = claims.Subject
+	}
+	return nil
+}
+
+// isResourceAudience reports whether aud lists only registered protected resources.
+func (s *TokenService) isResourceAudience(aud []string) bool {
+	if len(aud) == 0 {
+		return false
+	}
+	for _, a := range aud {
+		if !s.resources[a] {
+			return false
+		}
+	}
+	return true
+}
+
 // ValidateRefreshToken validates a refresh token
 func (s *TokenService) ValidateRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
 	key := s.buildRefreshTokenKey(token)
@@ -88,7 +162,7 @@ func (s *TokenService) ValidateRefreshToken(ctx context.Context, token string) (
 		return nil, fmt.Errorf("refresh token not found or expired: %w", cache.ErrKeyNotFound)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("get refresh token: %w", err)
+		return nil, fmt.Errorf("get refresh token: %w: %w", ErrTokenStoreUnavailable, err)
 	}
 
 	var rt domain.RefreshToken
@@ -120,16 +194,70 @@ func (s *TokenService) ValidateRefreshToken(ctx context.Context, token string) (
 	return &rt, nil
 }
 
-// IntrospectToken validates a token and returns its active status (RFC 7662).
+// Token type hints of introspection and revocation requests (RFC 7009 §2.1).
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+)
+
+// IntrospectToken validates an access or refresh token and returns its active status
+// (RFC 7662). tokenTypeHint selects the type looked up first; the other type is still
+// tried when the token is not found, and unknown hints are ignored (RFC 7662 §2.1).
 // Returns (result, nil) for both active and inactive tokens.
 // Returns (nil, error) only for infrastructure failures (e.g., blacklist unavailable).
-func (s *TokenService) IntrospectToken(ctx context.Context, tokenString string) (map[string]any, error) {
-	claims, err := s.ValidateAccessTokenWithContext(ctx, tokenString)
+func (s *TokenService) IntrospectToken(ctx context.Context, tokenString, tokenTypeHint string) (map[string]any, error) {
+	lookups := []func(context.Context, string) (map[string]any, error){s.introspectAccessToken, s.introspectRefreshToken}
+	if tokenTypeHint == TokenTypeHintRefreshToken {
+		slices.Reverse(lookups)
+	}
+	for _, lookup := range lookups {
+		result, err := lookup(ctx, tokenString)
+		if err != nil || result != nil {
+			return result, err
+		}
+	}
+	return map[string]any{"active": false}, nil
+}
+
+// introspectRefreshToken returns the introspection result of an active refresh token, or
+// nil when tokenString is not one.
+func (s *TokenService) introspectRefreshToken(ctx context.Context, tokenString string) (map[string]any, error) {
+	rt, err := s.ValidateRefreshToken(ctx, tokenString)
 	if err != nil {
-		if errors.Is(err, ErrBlacklistUnavailable) {
+		if errors.Is(err, ErrBlacklistUnavailable) || errors.Is(err, ErrTokenStoreUnavailable) {
 			return nil, err
 		}
-		return map[string]any{"active": false}, nil
+		return nil, nil
+	}
+
+	result := map[string]any{
+		"active":    true,
+		"client_id": rt.ClientID,
+		"scope":     rt.Scope,
+		"exp":       rt.ExpiresAt.Unix(),
+		"iat":       rt.CreatedAt.Unix(),
+	}
+	if rt.AccountID != "" {
+		result["sub"] = rt.AccountID
+	}
+	if rt.SessionID != "" {
+		result["sid"] = rt.SessionID
+	}
+	if s.issuer != "" {
+		result["iss"] = s.issuer
+	}
+	return result, nil
+}
+
+// introspectAccessToken returns the introspection result of an active JWT or reference
+// access token, or nil when tokenString is not one.
+func (s *TokenService) introspectAccessToken(ctx context.Context, tokenString string) (map[string]any, error) {
+	claims, err := s.ParseAccessToken(ctx, tokenString)
+	if err != nil {
+		if errors.Is(err, ErrBlacklistUnavailable) || errors.Is(err, ErrTokenStoreUnavailable) {
+			return nil, err
+		}
+		return nil, nil
 	}
 
 	result := map[string]any{
@@ -157,6 +285,18 @@ func (s *TokenService) IntrospectToken(ctx context.Context, tokenString string)
 	if len(claims.Roles) > 0 {
 		result["roles"] = claims.Roles
 	}
+	if claims.Act != nil {
+		result["act"] = claims.Act
+	}
+	if claims.Cnf != nil {
+		result["cnf"] = claims.Cnf
+	}
+	if len(claims.AuthorizationDetails) > 0 {
+		result["authorization_details"] = claims.AuthorizationDetails
+	}
+	if claims.DPoPThumbprint() != "" {
+		result["token_type"] = "DPoP"
+	}
 	if claims.ID != "" {
 		result["jti"] = claims.ID
 	}
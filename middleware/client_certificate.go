@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/rushairer/gosso/internal/mtls"
+	"github.com/rushairer/gosso/internal/utility"
+)
+
+// ClientCertificateMiddleware stores the TLS client certificate of each request in the
+// request context for mutual-TLS client authentication and certificate-bound tokens
+// (RFC 8705); see mtls.CertificateFromContext. A certificate from a direct TLS connection
+// is always used. Behind a TLS-terminating proxy, header names the request header the
+// proxy forwards the verified certificate in. The header is only trusted on connections
+// from trustedProxies (IP addresses or CIDRs), so clients cannot inject a certificate.
+func ClientCertificateMiddleware(header string, trustedProxies []string, logger *zap.Logger) (gin.HandlerFunc, error) {
+	logger = utility.EnsureLogger(logger)
+	prefixes := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		prefix, err := parseProxyPrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate middleware: %w", err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	return func(ctx *gin.Context) {
+		if tls := ctx.Request.TLS; tls != nil && len(tls.PeerCertificates) > 0 {
+			ctx.Request = ctx.Request.WithContext(mtls.WithCertificate(ctx.Request.Context(), tls.PeerCertificates[0]))
+			ctx.Next()
+			return
+		}
+		if header == "" {
+			ctx.Next()
+			return
+		}
+		value := ctx.GetHeader(header)
+		if value == "" || !fromTrustedProxy(ctx.Request.RemoteAddr, prefixes) {
+			ctx.Next()
+			return
+		}
+		cert, err := mtls.ParseCertificateHeader(value)
+		if err != nil {
+			logger.Warn("Ignoring malformed client certificate header", zap.String("header", header), zap.Error(err))
+			ctx.Next()
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(mtls.WithCertificate(ctx.Request.Context(), cert))
+		ctx.Next()
+	}, nil
+}
+
+// parseProxyPrefix parses a trusted proxy entry given as an IP address or CIDR.
+func parseProxyPrefix(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// fromTrustedProxy reports whether remoteAddr (host:port) belongs to one of prefixes.
+func fromTrustedProxy(remoteAddr string, prefixes []netip.Prefix) bool {
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rushairer/gosso/internal/mtls"
+	"github.com/rushairer/gosso/internal/testutil"
+)
+
+const testClientCertHeader = "X-Client-Cert"
+
+func serveClientCertificate(t *testing.T, header string, trustedProxies []string, req *http.Request) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	handler, err := ClientCertificateMiddleware(header, trustedProxies, nil)
+	require.NoError(t, err)
+	r := gin.New()
+	r.Use(handler)
+	var thumbprint string
+	r.GET("/test", func(c *gin.Context) {
+		thumbprint = mtls.Thumbprint(mtls.CertificateFromContext(c.Request.Context()))
+		c.Status(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	return thumbprint
+}
+
+func TestClientCertificateMiddleware_TrustedProxyHeader(t *testing.T) {
+	cert, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client"})
+	header := base64.StdEncoding.EncodeToString(cert.Raw)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.RemoteAddr = "10.0.0.5:443"
+	req.Header.Set(testClientCertHeader, header)
+	assert.Equal(t, mtls.Thumbprint(cert), serveClientCertificate(t, testClientCertHeader, []string{"10.0.0.0/8"}, req))
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.RemoteAddr = "10.0.0.5:443"
+	req.Header.Set(testClientCertHeader, header)
+	assert.Equal(t, mtls.Thumbprint(cert), serveClientCertificate(t, testClientCertHeader, []string{"10.0.0.5"}, req))
+}
+
+func TestClientCertificateMiddleware_UntrustedSource(t *testing.T) {
+	cert, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client"})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.RemoteAddr = "203.0.113.7:5000"
+	req.Header.Set(testClientCertHeader, base64.StdEncoding.EncodeToString(cert.Raw))
+	assert.Empty(t, serveClientCertificate(t, testClientCertHeader, []string{"10.0.0.0/8"}, req))
+
+	// Without a configured header nothing is read from the request headers.
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.RemoteAddr = "10.0.0.5:443"
+	req.Header.Set(testClientCertHeader, base64.StdEncoding.EncodeToString(cert.Raw))
+	assert.Empty(t, serveClientCertificate(t, "", []string{"10.0.0.0/8"}, req))
+}
+
+func TestClientCertificateMiddleware_MalformedHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.RemoteAddr = "10.0.0.5:443"
+	req.Header.Set(testClientCertHeader, "not-a-certificate")
+	assert.Empty(t, serveClientCertificate(t, testClientCertHeader, []string{"10.0.0.0/8"}, req))
+}
+
+func TestClientCertificateMiddleware_DirectTLS(t *testing.T) {
+	cert, _ := testutil.SelfSignedCertificate(t, pkix.Name{CommonName: "client"})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	assert.Equal(t, mtls.Thumbprint(cert), serveClientCertificate(t, "", nil, req))
+}
+
+func TestClientCertificateMiddleware_InvalidProxy(t *testing.T) {
+	_, err := ClientCertificateMiddleware(testClientCertHeader, []string{"not-an-ip"}, nil)
+	assert.Error(t, err)
+}
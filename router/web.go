@@ -35,6 +35,7 @@ type RouterDeps struct {
 	OIDCCtrl         *oidcController.OIDCController
 	AdminCtrl        *adminController.AdminController
 	TokenSvc         *tokenService.TokenService
+	DPoPVerifier     *tokenService.DPoPVerifier
 	PasskeyCtrl      *authController.PasskeyController
 	Redis            *cache.RedisClient
 	RateLimits       config.RateLimitsConfig
@@ -67,11 +68,15 @@ func RegisterWebRouter(deps RouterDeps) error {
 	}
 
 	// JWT auth middleware
-	jwtAuth, err := authMiddleware.JWTAuthMiddlewareWithConfig(deps.TokenSvc, deps.SessionValidator, authMiddleware.AuthConfigOptions{
+	jwtAuthOpts := authMiddleware.AuthConfigOptions{
 		LoginURL:         deps.AuthConfig.LoginURL,
 		EnableCookieAuth: deps.AuthConfig.EnableCookieAuth,
 		AuthCookieName:   deps.AuthConfig.AuthCookieName,
-	})
+	}
+	if deps.DPoPVerifier != nil {
+		jwtAuthOpts.DPoP = deps.DPoPVerifier
+	}
+	jwtAuth, err := authMiddleware.JWTAuthMiddlewareWithConfig(deps.TokenSvc, deps.SessionValidator, jwtAuthOpts)
 	if err != nil {
 		return err
 	}
@@ -64,18 +64,29 @@ type HTTPTestEnv struct {
 
 // SeedClientOptions configures the OAuth2 client to seed.
 type SeedClientOptions struct {
-	Name                              string
-	Confidential                      bool
-	RedirectURIs                      []string
-	PostLogoutRedirectURIs            []string
-	GrantTypes                        []string
-	Scopes                            []string
-	FrontchannelLogoutURI             string
-	FrontchannelLogoutSessionRequired bool
-	BackchannelLogoutURI              string
-	BackchannelLogoutSessionRequired  bool
+	Name                                  string
+	Confidential                          bool
+	RedirectURIs                          []string
+	PostLogoutRedirectURIs                []string
+	GrantTypes                            []string
+	Scopes                                []string
+	FrontchannelLogoutURI                 string
+	FrontchannelLogoutSessionRequired     bool
+	BackchannelLogoutURI                  string
+	BackchannelLogoutSessionRequired      bool
+	RequirePushedAuthorizationRequests    bool
+	TokenEndpointAuthMethod               string
+	JWKS                                  string
+	TokenExchangeAudiences                []string
+	DPoPBoundAccessTokens                 bool
+	TLSClientAuthSubjectDN                string
+	TLSClientCertificateBoundAccessTokens bool
 }
 
+// ClientCertHeader is the header the test server reads a forwarded TLS client
+// certificate from, as a TLS-terminating proxy would send it.
+const ClientCertHeader = "X-Client-Cert"
+
 // SetupHTTPTestEnv creates a full Gin HTTP test server with real DB + Redis.
 func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 	t.Helper()
@@ -109,6 +120,9 @@ func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 	)
 	require.NoError(t, err)
 
+	dpopVerifier, err := tokenServicePkg.NewDPoPVerifier(env.Redis, "http://localhost", false)
+	require.NoError(t, err)
+
 	authMod, err := auth.InitializeAuthModule(auth.AuthModuleConfig{
 		DB:                    env.DB,
 		Redis:                 env.Redis,
@@ -128,16 +142,16 @@ func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 	})
 	require.NoError(t, err)
 
-	oauth2Mod, err := oauth2.InitializeOAuth2Module(env.DB, env.Redis, logger, env.Config.AuthConfig, auditor)
+	oauth2Mod, err := oauth2.InitializeOAuth2Module(env.DB, env.Redis, logger, env.Config.AuthConfig, auditor, nil)
 	require.NoError(t, err)
 
 	jar, err := cookiejar.New(nil)
 	require.NoError(t, err)
 
 	oidcMod := oidc.InitializeOIDCModule(
-		tokenSvc, accountMod.Service, env.Config.AuthConfig,
+		tokenSvc, nil, accountMod.Service, env.Config.AuthConfig,
 		authMod.SessionService, accountMod.CredentialRepo,
-		oauth2Mod.ClientRepo, &http.Client{Timeout: 5 * time.Second}, logger,
+		oauth2Mod.ClientRepo, oauth2Mod.SubjectIdentifiers, &http.Client{Timeout: 5 * time.Second}, logger,
 	)
 
 	// Wire cross-module dependencies
@@ -159,7 +173,7 @@ func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 		TokenSvc:                   tokenSvc,
 		IDTokenSvc:                 oidcMod.IDTokenService,
 		DeviceCodeSvc:              oauth2Mod.DeviceCodeService,
-		ClientAuth:                 &oauth2Service.ClientAuthenticator{},
+		ClientAuth:                 oauth2Mod.ClientAuthenticator,
 		AccountValidator:           &alwaysActiveValidator{},
 		SessionValidator:           authMod.SessionService,
 		Redis:                      env.Redis,
@@ -167,6 +181,12 @@ func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 		EnforcePKCEForConfidential: false,
 		Logger:                     logger,
 		RoleFetcher:                &accountRoleFetcherAdapter{accountSvc: accountMod.Service},
+		JWTBearerVerifier:          oauth2Mod.JWTBearerVerifier,
+		ClientRegistrar:            oauth2Mod.ClientRegistration,
+		DPoPVerifier:               dpopVerifier,
+		RequestObjectVerifier:      oauth2Mod.RequestObjectVerifier,
+		JARMSigner:                 tokenServicePkg.NewAuthorizationResponseSigner(keySvc, "http://localhost"),
+		IntrospectionSigner:        tokenServicePkg.NewIntrospectionResponseSigner(keySvc, "http://localhost"),
 	})
 	require.NoError(t, err)
 
@@ -181,11 +201,13 @@ func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 
 	auditQueryRepo := auditRepository.NewAuditQueryRepository(env.DB)
 	authSvcConcrete, _ := authMod.AuthService.(*authServicePkg.AuthService)
-	adminCtrl := adminController.NewAdminController(accountMod.Service, oauth2Mod.ConsentService, auditQueryRepo, authSvcConcrete, logger)
+	adminCtrl := adminController.NewAdminController(accountMod.Service, oauth2Mod.ConsentService, auditQueryRepo, authSvcConcrete, oauth2Mod.ClientRegistration, tokenSvc.KeyService(), logger)
 
 	// Build Gin engine with middleware
 	engine := gin.New()
 	_ = engine.SetTrustedProxies([]string{"127.0.0.1"})
+	clientCertMiddleware, err := middleware.ClientCertificateMiddleware(ClientCertHeader, []string{"127.0.0.1"}, logger)
+	require.NoError(t, err)
 
 	corsConfig := cors.Config{
 		AllowAllOrigins:  true,
@@ -199,6 +221,7 @@ func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 		middleware.RecoveryMiddleware(logger),
 		cors.New(corsConfig),
 		middleware.RequestIDMiddleware(),
+		clientCertMiddleware,
 		middleware.ZapLoggerMiddleware(logger),
 		middleware.SecurityHeadersMiddleware(false),
 		middleware.MaxBodySizeMiddleware(10<<20),
@@ -206,6 +229,8 @@ func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 		middleware.CSRFMiddleware(false, logger, 3600,
 			"/oauth2/token",
 			"/oauth2/introspect",
+			"/oauth2/par",
+			"/oauth2/register",
 			"/oauth2/device/code",
 			"/.well-known",
 			"/swagger",
@@ -221,6 +246,7 @@ func SetupHTTPTestEnv(t *testing.T) *HTTPTestEnv {
 		OIDCCtrl:         oidcCtrl,
 		AdminCtrl:        adminCtrl,
 		TokenSvc:         tokenSvc,
+		DPoPVerifier:     dpopVerifier,
 		PasskeyCtrl:      nil,
 		Redis:            env.Redis,
 		RateLimits:       env.Config.WebServerConfig.RateLimits,
@@ -287,6 +313,16 @@ func (e *HTTPTestEnv) SeedOAuth2Client(t *testing.T, ctx context.Context, accoun
 	client.FrontchannelLogoutSessionRequired = opts.FrontchannelLogoutSessionRequired
 	client.BackchannelLogoutURI = opts.BackchannelLogoutURI
 	client.BackchannelLogoutSessionRequired = opts.BackchannelLogoutSessionRequired
+	client.RequirePushedAuthorizationRequests = opts.RequirePushedAuthorizationRequests
+	client.TokenEndpointAuthMethod = opts.TokenEndpointAuthMethod
+	client.JWKS = json.RawMessage(opts.JWKS)
+	client.TokenExchangeAudiences = opts.TokenExchangeAudiences
+	if client.TokenExchangeAudiences == nil {
+		client.TokenExchangeAudiences = []string{}
+	}
+	client.DPoPBoundAccessTokens = opts.DPoPBoundAccessTokens
+	client.TLSClientAuthSubjectDN = opts.TLSClientAuthSubjectDN
+	client.TLSClientCertificateBoundAccessTokens = opts.TLSClientCertificateBoundAccessTokens
 
 	secret := ""
 	if opts.Confidential {
@@ -297,8 +333,8 @@ func (e *HTTPTestEnv) SeedOAuth2Client(t *testing.T, ctx context.Context, accoun
 	}
 
 	_, err = e.DB.ExecContext(ctx,
-		`INSERT INTO oauth2_clients (id, account_id, client_id, client_secret_hash, name, description, redirect_uris, post_logout_redirect_uris, grant_types, scopes, is_confidential, metadata, frontchannel_logout_uri, frontchannel_logout_session_required, backchannel_logout_uri, backchannel_logout_session_required)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+		`INSERT INTO oauth2_clients (id, account_id, client_id, client_secret_hash, name, description, redirect_uris, post_logout_redirect_uris, grant_types, scopes, is_confidential, metadata, frontchannel_logout_uri, frontchannel_logout_session_required, backchannel_logout_uri, backchannel_logout_session_required, require_pushed_authorization_requests, token_endpoint_auth_method, jwks, token_exchange_audiences, dpop_bound_access_tokens, tls_client_auth_subject_dn, tls_client_certificate_bound_access_tokens)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)`,
 		client.ID, client.AccountID, client.ClientID, client.ClientSecretHash,
 		client.Name, client.Description,
 		marshalJSON(client.RedirectURIs), marshalJSON(client.PostLogoutRedirectURIs),
@@ -306,6 +342,11 @@ func (e *HTTPTestEnv) SeedOAuth2Client(t *testing.T, ctx context.Context, accoun
 		client.IsConfidential, marshalJSON(client.Metadata),
 		client.FrontchannelLogoutURI, client.FrontchannelLogoutSessionRequired,
 		client.BackchannelLogoutURI, client.BackchannelLogoutSessionRequired,
+		client.RequirePushedAuthorizationRequests,
+		client.TokenEndpointAuthMethod, string(client.JWKS),
+		marshalJSON(client.TokenExchangeAudiences),
+		client.DPoPBoundAccessTokens,
+		client.TLSClientAuthSubjectDN, client.TLSClientCertificateBoundAccessTokens,
 	)
 	require.NoError(t, err)
 